@@ -1,9 +1,7 @@
 package main
 
 import (
-	"fmt"
 	"log"
-	"net/http"
 	"order_service/composer"
 	"order_service/config"
 	"os"
@@ -13,7 +11,6 @@ import (
 	_ "order_service/docs"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/filesystem"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/swagger"
@@ -44,24 +41,12 @@ func main() {
 	defer pg.Close()
 	defer rd.Close()
 
-	pwd, err := os.Getwd()
-	if err != nil {
-		log.Fatalln(err)
-	}
-
 	app := fiber.New(fiber.Config{
 		BodyLimit: 2 * 1024 * 1024,
 	})
 
 	app.Use(recover.New())
 	app.Use(logger.New())
-	app.Use("/static", filesystem.New(
-		filesystem.Config{
-			Root:   http.Dir(fmt.Sprintf("%s/storage", pwd)),
-			Browse: true,
-			MaxAge: 3600,
-		}))
-
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
 	composer.SetUpRoutes(app.Group("/v1"), cfg, pg, rd, s3Client)
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	analyticsRepo "order_service/services/analytics/repository/postgres"
+	analyticsUsecase "order_service/services/analytics/usecase"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Rebuilds the daily sales rollups from the orders, days are UTC and both ends are included.
+//
+//	go run ./cmd/backfill -from 2024-01-01 -to 2024-12-31
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	today := time.Now().UTC().Format(time.DateOnly)
+	from := flag.String("from", "1970-01-01", "first day to rebuild")
+	to := flag.String("to", today, "last day to rebuild")
+	flag.Parse()
+
+	startDate, err := time.Parse(time.DateOnly, *from)
+	if err != nil {
+		log.Fatalf("[Error]: Parse from date error: %v", err)
+	}
+
+	endDate, err := time.Parse(time.DateOnly, *to)
+	if err != nil {
+		log.Fatalf("[Error]: Parse to date error: %v", err)
+	}
+
+	// Connect to postgres
+	pool, err := pgxpool.New(ctx, os.Getenv("PG_URL"))
+	if err != nil {
+		log.Fatalf("[Error]: Connect to pg error: %v", err)
+	}
+	defer pool.Close()
+
+	uc := analyticsUsecase.NewUsecase(analyticsRepo.NewAnalyticsRepo(pool), nil, time.UTC, 0)
+
+	fmt.Printf("[backfill]: Rebuilding daily sales from %s to %s...\n", *from, *to)
+	rebuild, err := uc.RebuildDailySales(ctx, startDate, endDate)
+	if err != nil {
+		log.Fatalf("[Error]: Rebuild daily sales error: %v", err)
+	}
+
+	fmt.Printf("[backfill]: Done, %d user rows, %d product rows and %d tax rows\n", rebuild.UserRows, rebuild.ProductRows, rebuild.TaxRows)
+}
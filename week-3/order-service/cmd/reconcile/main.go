@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	userEntity "order_service/services/user/entity"
+	userRepo "order_service/services/user/repository/postgres"
+	userUsecase "order_service/services/user/usecase"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Checks the cached balance of every user against the sum of its wallet ledger entries, and that every
+// ledger transaction sums up to zero. It exits with 1 when anything does not add up.
+//
+//	go run ./cmd/reconcile
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Connect to postgres
+	pool, err := pgxpool.New(ctx, os.Getenv("PG_URL"))
+	if err != nil {
+		log.Fatalf("[Error]: Connect to pg error: %v", err)
+	}
+	defer pool.Close()
+
+	uc := userUsecase.NewUsecase(userRepo.NewUserRepo(pool), userEntity.TopUpLimits{})
+
+	fmt.Println("[reconcile]: Checking balances against the ledger...")
+	reconciliation, err := uc.Reconcile(ctx)
+	if err != nil {
+		log.Fatalf("[Error]: Reconcile balances error: %v", err)
+	}
+
+	for _, mismatch := range reconciliation.Mismatches {
+		fmt.Printf("[reconcile]: User %d has a balance of %s but a ledger of %s\n", mismatch.UserId, mismatch.Balance, mismatch.Ledger)
+	}
+
+	for _, transactionId := range reconciliation.UnbalancedTransactions {
+		fmt.Printf("[reconcile]: Transaction %s does not balance\n", transactionId)
+	}
+
+	fmt.Printf("[reconcile]: Done, %d users checked, %d mismatches and %d unbalanced transactions\n", reconciliation.Users, len(reconciliation.Mismatches), len(reconciliation.UnbalancedTransactions))
+
+	if !reconciliation.IsBalanced() {
+		os.Exit(1)
+	}
+}
@@ -1,19 +1,56 @@
 package composer
 
 import (
+	"log"
 	"order_service/config"
 	"order_service/pkg"
+	addressPGRepo "order_service/services/address/repository/postgres"
+	addressUsecase "order_service/services/address/usecase"
+	analyticsPGRepo "order_service/services/analytics/repository/postgres"
+	analyticsRDRepo "order_service/services/analytics/repository/redis"
+	analyticsUsecase "order_service/services/analytics/usecase"
 	authPGRepo "order_service/services/auth/repository/postgres"
 	authRDRepo "order_service/services/auth/repository/redis"
 	authUsecase "order_service/services/auth/usecase"
+	cartRDRepo "order_service/services/cart/repository/redis"
+	cartUsecase "order_service/services/cart/usecase"
+	couponPGRepo "order_service/services/coupon/repository/postgres"
+	couponUsecase "order_service/services/coupon/usecase"
+	currencyFileRepo "order_service/services/currency/repository/file"
+	currencyPGRepo "order_service/services/currency/repository/postgres"
+	currencyUsecase "order_service/services/currency/usecase"
+	idempotencyRDRepo "order_service/services/idempotency/repository/redis"
+	idempotencyUsecase "order_service/services/idempotency/usecase"
 	orderPGRepo "order_service/services/order/repository/postgres"
 	orderUsecase "order_service/services/order/usecase"
+	outboxChannel "order_service/services/outbox/repository/channel"
+	outboxFile "order_service/services/outbox/repository/file"
+	outboxPGRepo "order_service/services/outbox/repository/postgres"
+	outboxWebhook "order_service/services/outbox/repository/webhook"
+	outboxUsecase "order_service/services/outbox/usecase"
+	paymentCard "order_service/services/payment/repository/card"
+	paymentPGRepo "order_service/services/payment/repository/postgres"
+	paymentWallet "order_service/services/payment/repository/wallet"
+	paymentUsecase "order_service/services/payment/usecase"
 	productS3Client "order_service/services/product/repository/aws"
 	productPGRepo "order_service/services/product/repository/postgres"
 	productUsecase "order_service/services/product/usecase"
+	reportFile "order_service/services/report/repository/file"
+	reportPGRepo "order_service/services/report/repository/postgres"
+	reportRDRepo "order_service/services/report/repository/redis"
+	reportSMTP "order_service/services/report/repository/smtp"
+	reportUsecase "order_service/services/report/usecase"
+	shipmentPGRepo "order_service/services/shipment/repository/postgres"
+	shipmentUsecase "order_service/services/shipment/usecase"
+	subscriptionPGRepo "order_service/services/subscription/repository/postgres"
+	subscriptionUsecase "order_service/services/subscription/usecase"
+	taxPGRepo "order_service/services/tax/repository/postgres"
+	taxUsecase "order_service/services/tax/usecase"
+	userEntity "order_service/services/user/entity"
 	userPGRepo "order_service/services/user/repository/postgres"
 	userUsecase "order_service/services/user/usecase"
 	"runtime"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -29,10 +66,15 @@ func ComposeAuthUsecase(cfg *config.Config, pg *pgxpool.Pool, rd *redis.Client)
 	return authUsecase.NewUsecase(repo, tokenRepo, hasher, jwt)
 }
 
-func ComposeUserUsecase(db *pgxpool.Pool) userUsecase.UserUsecase {
+func ComposeUserUsecase(cfg *config.Config, db *pgxpool.Pool) userUsecase.UserUsecase {
 	repo := userPGRepo.NewUserRepo(db)
+	limits := userEntity.TopUpLimits{
+		PerTransaction:    cfg.TopUpMaxPerTransaction,
+		PerDay:            cfg.TopUpMaxPerDay,
+		ApprovalThreshold: cfg.TopUpApprovalThreshold,
+	}
 
-	return userUsecase.NewUsecase(repo)
+	return userUsecase.NewUsecase(repo, limits)
 }
 
 func ComposeProductUsecase(db *pgxpool.Pool, s3Client *s3.Client) productUsecase.ProductUsecase {
@@ -42,8 +84,132 @@ func ComposeProductUsecase(db *pgxpool.Pool, s3Client *s3.Client) productUsecase
 	return productUsecase.NewUsecase(repo, client)
 }
 
-func ComposeOrderUsecase(db *pgxpool.Pool) orderUsecase.OrderUsecase {
+func ComposeOrderUsecase(cfg *config.Config, db *pgxpool.Pool, currencyUc currencyUsecase.CurrencyUsecase, taxUc taxUsecase.TaxUsecase, addressUc addressUsecase.AddressUsecase, shipmentUc shipmentUsecase.ShipmentUsecase, paymentUc paymentUsecase.PaymentUsecase) orderUsecase.OrderUsecase {
 	repo := orderPGRepo.NewOrderRepo(db)
+	cancelWindow := time.Second * time.Duration(cfg.CancelWindowInSec)
+	reservationTTL := time.Second * time.Duration(cfg.ReservationTTLInSec)
 
-	return orderUsecase.NewUsecase(repo)
+	return orderUsecase.NewUsecase(repo, currencyUc, taxUc, addressUc, shipmentUc, paymentUc, cancelWindow, reservationTTL)
+}
+
+func ComposeCurrencyUsecase(cfg *config.Config, db *pgxpool.Pool) currencyUsecase.CurrencyUsecase {
+	repo := currencyPGRepo.NewCurrencyRepo(db)
+
+	var ratesFile currencyFileRepo.RatesFile
+	if cfg.CurrencyCfg.RatesFile != "" {
+		ratesFile = currencyFileRepo.NewRatesFile(cfg.CurrencyCfg.RatesFile)
+	}
+
+	return currencyUsecase.NewUsecase(repo, ratesFile, cfg.CurrencyCfg.BaseCurrency)
+}
+
+func ComposeTaxUsecase(cfg *config.Config, db *pgxpool.Pool) taxUsecase.TaxUsecase {
+	repo := taxPGRepo.NewTaxRepo(db)
+
+	return taxUsecase.NewUsecase(repo, cfg.TaxCfg.DefaultRegion)
+}
+
+func ComposeAddressUsecase(db *pgxpool.Pool) addressUsecase.AddressUsecase {
+	repo := addressPGRepo.NewAddressRepo(db)
+
+	return addressUsecase.NewUsecase(repo)
+}
+
+func ComposeShipmentUsecase(cfg *config.Config, db *pgxpool.Pool) shipmentUsecase.ShipmentUsecase {
+	repo := shipmentPGRepo.NewShipmentRepo(db)
+
+	return shipmentUsecase.NewUsecase(repo, cfg.ShipmentCfg.WebhookSecret)
+}
+
+// ComposePaymentUsecase pays orders with the wallet balance or with the mock card gateway, which confirms
+// its charges through the payment webhook.
+func ComposePaymentUsecase(cfg *config.Config, db *pgxpool.Pool, userUc userUsecase.UserUsecase) paymentUsecase.PaymentUsecase {
+	repo := paymentPGRepo.NewPaymentRepo(db)
+	wallet := paymentWallet.NewWalletProvider(userUc)
+	card := paymentCard.NewMockGateway(cfg.PaymentCfg.CardWebhookURL, cfg.PaymentCfg.WebhookSecret, time.Second*time.Duration(cfg.PaymentCfg.CardConfirmDelayInSec), time.Second*time.Duration(cfg.PaymentCfg.CardTimeoutInSec))
+
+	return paymentUsecase.NewUsecase(repo, cfg.PaymentCfg.WebhookSecret, wallet, card)
+}
+
+func ComposeCouponUsecase(db *pgxpool.Pool) couponUsecase.CouponUsecase {
+	repo := couponPGRepo.NewCouponRepo(db)
+
+	return couponUsecase.NewUsecase(repo)
+}
+
+func ComposeIdempotencyUsecase(cfg *config.Config, rd *redis.Client) idempotencyUsecase.IdempotencyUsecase {
+	repo := idempotencyRDRepo.NewIdempotencyRepo(rd)
+
+	return idempotencyUsecase.NewUsecase(repo, cfg.IdempotencyCfg.LockExpireInSec, cfg.IdempotencyCfg.RecordExpireInSec)
+}
+
+func ComposeCartUsecase(cfg *config.Config, rd *redis.Client, productUc productUsecase.ProductUsecase, orderUc orderUsecase.OrderUsecase) cartUsecase.CartUsecase {
+	repo := cartRDRepo.NewCartRepo(rd)
+
+	return cartUsecase.NewUsecase(repo, productUc, orderUc, cfg.CartCfg.ExpireInSec)
+}
+
+func ComposeReportUsecase(cfg *config.Config, db *pgxpool.Pool, rd *redis.Client, orderUc orderUsecase.OrderUsecase) reportUsecase.ReportUsecase {
+	repo := reportPGRepo.NewReportRepo(db)
+	documents := reportFile.NewDocumentGenerator()
+	mailer := reportSMTP.NewSMTPMailer(cfg.SMTPCfg.Host, cfg.SMTPCfg.Port, cfg.SMTPCfg.Username, cfg.SMTPCfg.Password, cfg.SMTPCfg.From, time.Second*time.Duration(cfg.SMTPCfg.TimeoutInSec))
+	lock := reportRDRepo.NewSchedulerLock(rd)
+	retryDelay := time.Second * time.Duration(cfg.ReportCfg.RetryDelayInSec)
+	jobTimeout := time.Second * time.Duration(cfg.ReportCfg.JobTimeoutInSec)
+
+	return reportUsecase.NewUsecase(repo, documents, orderUc, mailer, lock, cfg.ReportCfg.MaxAttempts, retryDelay, jobTimeout, cfg.ReportCfg.SchedulerLockExpireInSec)
+}
+
+func ComposeSubscriptionUsecase(cfg *config.Config, db *pgxpool.Pool, orderUc orderUsecase.OrderUsecase) subscriptionUsecase.SubscriptionUsecase {
+	repo := subscriptionPGRepo.NewSubscriptionRepo(db)
+	retryDelay := time.Second * time.Duration(cfg.SubscriptionCfg.RetryDelayInSec)
+	claimTTL := time.Second * time.Duration(cfg.SubscriptionCfg.ClaimTTLInSec)
+
+	return subscriptionUsecase.NewUsecase(repo, orderUc, cfg.SubscriptionCfg.MaxAttempts, retryDelay, claimTTL)
+}
+
+func ComposeAnalyticsUsecase(cfg *config.Config, db *pgxpool.Pool, rd *redis.Client) analyticsUsecase.AnalyticsUsecase {
+	repo := analyticsPGRepo.NewAnalyticsRepo(db)
+	cache := analyticsRDRepo.NewLeaderboardCache(rd)
+
+	location, err := time.LoadLocation(cfg.AnalyticsCfg.Timezone)
+	if err != nil {
+		log.Fatalf("load analytics timezone %s error: %v", cfg.AnalyticsCfg.Timezone, err)
+	}
+
+	return analyticsUsecase.NewUsecase(repo, cache, location, cfg.AnalyticsCfg.LeaderboardCacheExpireInSec)
+}
+
+func ComposeOutboxUsecase(cfg *config.Config, db *pgxpool.Pool) outboxUsecase.OutboxUsecase {
+	repo := outboxPGRepo.NewOutboxRepo(db)
+	retryDelay := time.Second * time.Duration(cfg.OutboxCfg.RetryDelayInSec)
+	maxRetryDelay := time.Second * time.Duration(cfg.OutboxCfg.MaxRetryDelayInSec)
+
+	return outboxUsecase.NewUsecase(repo, composeEventPublisher(cfg), cfg.OutboxCfg.BatchSize, retryDelay, maxRetryDelay)
+}
+
+func composeEventPublisher(cfg *config.Config) outboxUsecase.EventPublisher {
+	switch cfg.OutboxCfg.Publisher {
+	case "webhook":
+		timeout := time.Second * time.Duration(cfg.OutboxCfg.WebhookTimeoutInSec)
+
+		return outboxWebhook.NewWebhookPublisher(cfg.OutboxCfg.WebhookURL, cfg.OutboxCfg.WebhookSecret, timeout)
+	case "channel":
+		publisher := outboxChannel.NewChannelPublisher(cfg.OutboxCfg.BatchSize)
+
+		// nothing in the service subscribes yet, keep the channel drained
+		go func() {
+			for event := range publisher.Events() {
+				log.Printf("received %s event %d", event.GetType(), event.GetId())
+			}
+		}()
+
+		return publisher
+	case "file":
+		return outboxFile.NewFilePublisher(cfg.OutboxCfg.LogFile)
+	}
+
+	log.Fatalf("unknown outbox publisher %q", cfg.OutboxCfg.Publisher)
+
+	return nil
 }
@@ -1,8 +1,11 @@
 package composer
 
 import (
+	"context"
+	"log"
 	"order_service/config"
 	"order_service/middleware"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gofiber/fiber/v2"
@@ -13,18 +16,65 @@ import (
 func SetUpRoutes(router fiber.Router, cfg *config.Config, pg *pgxpool.Pool, rd *redis.Client, s3Client *s3.Client) {
 	// create businesses
 	authUc := ComposeAuthUsecase(cfg, pg, rd)
-	userUc := ComposeUserUsecase(pg)
+	userUc := ComposeUserUsecase(cfg, pg)
 	productUc := ComposeProductUsecase(pg, s3Client)
-	orderUc := ComposeOrderUsecase(pg)
+	currencyUc := ComposeCurrencyUsecase(cfg, pg)
+	taxUc := ComposeTaxUsecase(cfg, pg)
+	addressUc := ComposeAddressUsecase(pg)
+	shipmentUc := ComposeShipmentUsecase(cfg, pg)
+	paymentUc := ComposePaymentUsecase(cfg, pg, userUc)
+	orderUc := ComposeOrderUsecase(cfg, pg, currencyUc, taxUc, addressUc, shipmentUc, paymentUc)
+	couponUc := ComposeCouponUsecase(pg)
+	idempotencyUc := ComposeIdempotencyUsecase(cfg, rd)
+	cartUc := ComposeCartUsecase(cfg, rd, productUc, orderUc)
+	outboxUc := ComposeOutboxUsecase(cfg, pg)
+	reportUc := ComposeReportUsecase(cfg, pg, rd, orderUc)
+	analyticsUc := ComposeAnalyticsUsecase(cfg, pg, rd)
+	subscriptionUc := ComposeSubscriptionUsecase(cfg, pg, orderUc)
 
 	// create services
 	authAPIService := ComposeAuthAPIService(authUc)
 	userAPIService := ComposeUserAPIService(userUc)
 	productAPIService := ComposeProductAPIService(productUc)
 	orderAPIService := ComposeOrderAPIService(orderUc)
+	currencyAPIService := ComposeCurrencyAPIService(currencyUc)
+	couponAPIService := ComposeCouponAPIService(couponUc)
+	taxAPIService := ComposeTaxAPIService(taxUc)
+	addressAPIService := ComposeAddressAPIService(addressUc)
+	shipmentAPIService := ComposeShipmentAPIService(shipmentUc)
+	cartAPIService := ComposeCartAPIService(cartUc)
+	reportAPIService := ComposeReportAPIService(reportUc)
+	analyticsAPIService := ComposeAnalyticsAPIService(analyticsUc)
+	subscriptionAPIService := ComposeSubscriptionAPIService(subscriptionUc)
+
+	// seed exchange rates, a broken rates file should not keep the service down
+	if cfg.CurrencyCfg.RatesFile != "" {
+		loaded, err := currencyUc.LoadRates(context.Background())
+		if err != nil {
+			log.Println("load exchange rates error:", err)
+		} else {
+			log.Printf("loaded %d exchange rates", loaded)
+		}
+	}
+
+	// give the stock of unpaid orders back once their reservation runs out
+	go orderUc.RunReservationSweeper(context.Background(), time.Second*time.Duration(cfg.ReservationSweepInSec))
+
+	// publish the order events committed to the outbox
+	go outboxUc.RunRelay(context.Background(), time.Second*time.Duration(cfg.OutboxCfg.PollInSec))
+
+	// generate the queued reports away from the request handlers
+	go reportUc.RunWorkers(context.Background(), cfg.ReportCfg.Workers, time.Second*time.Duration(cfg.ReportCfg.PollInSec))
+
+	// queue the scheduled reports, every instance runs it but only the lock holder does the work
+	go reportUc.RunScheduler(context.Background(), time.Second*time.Duration(cfg.ReportCfg.SchedulerPollInSec))
+
+	// place the orders of the due subscriptions, every instance runs it and claims one subscription at a time
+	go subscriptionUc.RunScheduler(context.Background(), time.Second*time.Duration(cfg.SubscriptionCfg.PollInSec))
 
 	// create middlewares
 	authMiddleware := middleware.RequireAuth(authUc)
+	idempotencyMiddleware := middleware.RequireIdempotency(idempotencyUc)
 
 	// prepare routes
 	// /auth
@@ -44,7 +94,48 @@ func SetUpRoutes(router fiber.Router, cfg *config.Config, pg *pgxpool.Pool, rd *
 		userRouter.Get("/", userAPIService.GetUsers)
 		userRouter.Get("/profile", userAPIService.GetUserProfile)
 		userRouter.Get("/:userID", userAPIService.GetUser)
-		userRouter.Post("/balance", userAPIService.AddUserBalance)
+		userRouter.Post("/:userID/balance/adjustments", idempotencyMiddleware, userAPIService.AdjustBalance)
+		userRouter.Post("/balance", idempotencyMiddleware, userAPIService.AddUserBalance)
+		userRouter.Get("/balance/history", userAPIService.GetBalanceHistory)
+		userRouter.Get("/balance/top-ups", userAPIService.GetTopUps)
+		userRouter.Post("/balance/top-ups/:topUpID/approve", userAPIService.ApproveTopUp)
+		userRouter.Post("/balance/top-ups/:topUpID/reject", userAPIService.RejectTopUp)
+		userRouter.Patch("/currency", currencyAPIService.SetUserCurrency)
+	}
+
+	// /currencies
+	currencyRouter := router.Group("/currencies", authMiddleware)
+	{
+		currencyRouter.Get("/", currencyAPIService.GetRates)
+		currencyRouter.Post("/reload", currencyAPIService.ReloadRates)
+		currencyRouter.Put("/:currency", currencyAPIService.SetRate)
+		currencyRouter.Delete("/:currency", currencyAPIService.DeleteRate)
+	}
+
+	// /coupons
+	couponRouter := router.Group("/coupons", authMiddleware)
+	{
+		couponRouter.Get("/", couponAPIService.GetCoupons)
+		couponRouter.Post("/", couponAPIService.CreateCoupon)
+		couponRouter.Delete("/:code", couponAPIService.DeactivateCoupon)
+	}
+
+	// /taxes
+	taxRouter := router.Group("/taxes", authMiddleware)
+	{
+		taxRouter.Get("/", taxAPIService.GetRules)
+		taxRouter.Put("/", taxAPIService.SetRule)
+		taxRouter.Delete("/:ruleID", taxAPIService.DeleteRule)
+	}
+
+	// /addresses
+	addressRouter := router.Group("/addresses", authMiddleware)
+	{
+		addressRouter.Get("/", addressAPIService.GetAddresses)
+		addressRouter.Post("/", addressAPIService.CreateAddress)
+		addressRouter.Put("/:addressID", addressAPIService.UpdateAddress)
+		addressRouter.Put("/:addressID/default", addressAPIService.SetDefaultAddress)
+		addressRouter.Delete("/:addressID", addressAPIService.DeleteAddress)
 	}
 
 	// /products
@@ -65,7 +156,74 @@ func SetUpRoutes(router fiber.Router, cfg *config.Config, pg *pgxpool.Pool, rd *
 		orderRouter.Get("/top-by-price", orderAPIService.GetTopFiveOrdersByPrice)
 		orderRouter.Get("/orders-by-month", orderAPIService.GetNumOfOrdersByMonth)
 		orderRouter.Get("/:orderID/invoice", orderAPIService.GetOrder)
-		orderRouter.Post("/", orderAPIService.CreateOrder)
+		orderRouter.Get("/:orderID/status", orderAPIService.GetOrderStatusHistories)
+		orderRouter.Get("/:orderID/shipments", shipmentAPIService.GetShipments)
+		orderRouter.Post("/:orderID/shipments", shipmentAPIService.CreateShipment)
+		orderRouter.Get("/:orderID/payments", orderAPIService.GetOrderPayments)
+		orderRouter.Post("/:orderID/payments", idempotencyMiddleware, orderAPIService.PayOrder)
+		orderRouter.Post("/", idempotencyMiddleware, orderAPIService.CreateOrder)
+		orderRouter.Post("/:orderID/cancel", idempotencyMiddleware, orderAPIService.CancelOrder)
+		orderRouter.Patch("/:orderID/status", orderAPIService.UpdateOrderStatus)
 		orderRouter.Post("/summarize", orderAPIService.GetOrdersSummarize)
 	}
+
+	// /shipments
+	shipmentRouter := router.Group("/shipments")
+	{
+		// carriers sign their callbacks instead of logging in
+		shipmentRouter.Post("/webhook", shipmentAPIService.ReceiveCarrierEvent)
+	}
+
+	// /payments
+	paymentRouter := router.Group("/payments")
+	{
+		// payment gateways sign their callbacks instead of logging in
+		paymentRouter.Post("/webhook", orderAPIService.ReceivePaymentEvent)
+	}
+
+	// /cart
+	cartRouter := router.Group("/cart", authMiddleware)
+	{
+		cartRouter.Get("/", cartAPIService.GetCart)
+		cartRouter.Delete("/", cartAPIService.ClearCart)
+		cartRouter.Post("/items", cartAPIService.AddItem)
+		cartRouter.Patch("/items/:productID", cartAPIService.UpdateItem)
+		cartRouter.Delete("/items/:productID", cartAPIService.RemoveItem)
+		cartRouter.Post("/checkout", idempotencyMiddleware, cartAPIService.Checkout)
+	}
+
+	// /reports
+	reportRouter := router.Group("/reports", authMiddleware)
+	{
+		reportRouter.Post("/", idempotencyMiddleware, reportAPIService.CreateJob)
+		// before /:reportID so schedules is not read as a report id
+		reportRouter.Post("/schedules", reportAPIService.CreateSchedule)
+		reportRouter.Get("/schedules", reportAPIService.GetSchedules)
+		reportRouter.Put("/schedules/:scheduleID", reportAPIService.UpdateSchedule)
+		reportRouter.Delete("/schedules/:scheduleID", reportAPIService.DeleteSchedule)
+		reportRouter.Get("/:reportID", reportAPIService.GetJob)
+		reportRouter.Get("/:reportID/download", reportAPIService.DownloadJob)
+		reportRouter.Post("/:reportID/cancel", reportAPIService.CancelJob)
+		reportRouter.Post("/:reportID/retry", reportAPIService.RetryJob)
+	}
+
+	// /analytics
+	analyticsRouter := router.Group("/analytics", authMiddleware)
+	{
+		analyticsRouter.Get("/orders", analyticsAPIService.GetOrderTimeSeries)
+		analyticsRouter.Get("/leaderboards/:kind", analyticsAPIService.GetLeaderboard)
+	}
+
+	// /subscriptions
+	subscriptionRouter := router.Group("/subscriptions", authMiddleware)
+	{
+		subscriptionRouter.Post("/", idempotencyMiddleware, subscriptionAPIService.CreateSubscription)
+		subscriptionRouter.Get("/", subscriptionAPIService.GetSubscriptions)
+		subscriptionRouter.Get("/:subscriptionID", subscriptionAPIService.GetSubscription)
+		subscriptionRouter.Put("/:subscriptionID", subscriptionAPIService.UpdateSubscription)
+		subscriptionRouter.Delete("/:subscriptionID", subscriptionAPIService.DeleteSubscription)
+		subscriptionRouter.Post("/:subscriptionID/pause", subscriptionAPIService.PauseSubscription)
+		subscriptionRouter.Post("/:subscriptionID/resume", subscriptionAPIService.ResumeSubscription)
+		subscriptionRouter.Post("/:subscriptionID/skip", subscriptionAPIService.SkipNextRun)
+	}
 }
@@ -1,12 +1,30 @@
 package composer
 
 import (
+	addressSrv "order_service/services/address/controller/api"
+	addressUc "order_service/services/address/usecase"
+	analyticsSrv "order_service/services/analytics/controller/api"
+	analyticsUc "order_service/services/analytics/usecase"
 	authSrv "order_service/services/auth/controller/api"
 	authUc "order_service/services/auth/usecase"
+	cartSrv "order_service/services/cart/controller/api"
+	cartUc "order_service/services/cart/usecase"
+	couponSrv "order_service/services/coupon/controller/api"
+	couponUc "order_service/services/coupon/usecase"
+	currencySrv "order_service/services/currency/controller/api"
+	currencyUc "order_service/services/currency/usecase"
 	orderSrv "order_service/services/order/controller/api"
 	orderUc "order_service/services/order/usecase"
 	productSrv "order_service/services/product/controller/api"
 	productUc "order_service/services/product/usecase"
+	reportSrv "order_service/services/report/controller/api"
+	reportUc "order_service/services/report/usecase"
+	shipmentSrv "order_service/services/shipment/controller/api"
+	shipmentUc "order_service/services/shipment/usecase"
+	subscriptionSrv "order_service/services/subscription/controller/api"
+	subscriptionUc "order_service/services/subscription/usecase"
+	taxSrv "order_service/services/tax/controller/api"
+	taxUc "order_service/services/tax/usecase"
 	userSrv "order_service/services/user/controller/api"
 	userUc "order_service/services/user/usecase"
 )
@@ -34,3 +52,57 @@ func ComposeOrderAPIService(biz orderUc.OrderUsecase) orderSrv.OrderService {
 
 	return serviceAPI
 }
+
+func ComposeCurrencyAPIService(biz currencyUc.CurrencyUsecase) currencySrv.CurrencyService {
+	serviceAPI := currencySrv.NewService(biz)
+
+	return serviceAPI
+}
+
+func ComposeCouponAPIService(biz couponUc.CouponUsecase) couponSrv.CouponService {
+	serviceAPI := couponSrv.NewService(biz)
+
+	return serviceAPI
+}
+
+func ComposeTaxAPIService(biz taxUc.TaxUsecase) taxSrv.TaxService {
+	serviceAPI := taxSrv.NewService(biz)
+
+	return serviceAPI
+}
+
+func ComposeAddressAPIService(biz addressUc.AddressUsecase) addressSrv.AddressService {
+	serviceAPI := addressSrv.NewService(biz)
+
+	return serviceAPI
+}
+
+func ComposeShipmentAPIService(biz shipmentUc.ShipmentUsecase) shipmentSrv.ShipmentService {
+	serviceAPI := shipmentSrv.NewService(biz)
+
+	return serviceAPI
+}
+
+func ComposeCartAPIService(biz cartUc.CartUsecase) cartSrv.CartService {
+	serviceAPI := cartSrv.NewService(biz)
+
+	return serviceAPI
+}
+
+func ComposeReportAPIService(biz reportUc.ReportUsecase) reportSrv.ReportService {
+	serviceAPI := reportSrv.NewService(biz)
+
+	return serviceAPI
+}
+
+func ComposeAnalyticsAPIService(biz analyticsUc.AnalyticsUsecase) analyticsSrv.AnalyticsService {
+	serviceAPI := analyticsSrv.NewService(biz)
+
+	return serviceAPI
+}
+
+func ComposeSubscriptionAPIService(biz subscriptionUc.SubscriptionUsecase) subscriptionSrv.SubscriptionService {
+	serviceAPI := subscriptionSrv.NewService(biz)
+
+	return serviceAPI
+}
@@ -6,6 +6,8 @@ import (
 	"log"
 	"time"
 
+	"order_service/internal/core"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsCfg "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
@@ -36,11 +38,108 @@ type AWSCfg struct {
 	SecretKey string `env-required:"true" env:"AWS_S3_SECRET_KEY"`
 }
 
+type OrderCfg struct {
+	CancelWindowInSec     int `env:"ORDER_CANCEL_WINDOW_IN_SEC" env-default:"3600"`  // 60 * 60
+	ReservationTTLInSec   int `env:"ORDER_RESERVATION_TTL_IN_SEC" env-default:"900"` // 60 * 15
+	ReservationSweepInSec int `env:"ORDER_RESERVATION_SWEEP_IN_SEC" env-default:"60"`
+}
+
+type IdempotencyCfg struct {
+	LockExpireInSec   int `env:"IDEMPOTENCY_LOCK_EXPIRE_IN_SEC" env-default:"60"`
+	RecordExpireInSec int `env:"IDEMPOTENCY_RECORD_EXPIRE_IN_SEC" env-default:"86400"` // 60 * 60 * 24
+}
+
+type CurrencyCfg struct {
+	BaseCurrency string `env:"CURRENCY_BASE" env-default:"USD"`
+	RatesFile    string `env:"CURRENCY_RATES_FILE" env-default:""`
+}
+
+type CartCfg struct {
+	ExpireInSec int `env:"CART_EXPIRE_IN_SEC" env-default:"2592000"` // 60 * 60 * 24 * 30
+}
+
+type TaxCfg struct {
+	DefaultRegion string `env:"TAX_DEFAULT_REGION" env-default:""`
+}
+
+type OutboxCfg struct {
+	Publisher           string `env:"OUTBOX_PUBLISHER" env-default:"file"` // file, webhook or channel
+	LogFile             string `env:"OUTBOX_LOG_FILE" env-default:"./logs/outbox.log"`
+	WebhookURL          string `env:"OUTBOX_WEBHOOK_URL" env-default:""`
+	WebhookSecret       string `env:"OUTBOX_WEBHOOK_SECRET" env-default:""`
+	WebhookTimeoutInSec int    `env:"OUTBOX_WEBHOOK_TIMEOUT_IN_SEC" env-default:"10"`
+	PollInSec           int    `env:"OUTBOX_POLL_IN_SEC" env-default:"5"`
+	BatchSize           int    `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	RetryDelayInSec     int    `env:"OUTBOX_RETRY_DELAY_IN_SEC" env-default:"5"`
+	MaxRetryDelayInSec  int    `env:"OUTBOX_MAX_RETRY_DELAY_IN_SEC" env-default:"3600"` // 60 * 60
+}
+
+type ReportCfg struct {
+	Workers                  int `env:"REPORT_WORKERS" env-default:"2"`
+	PollInSec                int `env:"REPORT_POLL_IN_SEC" env-default:"2"`
+	MaxAttempts              int `env:"REPORT_MAX_ATTEMPTS" env-default:"3"`
+	RetryDelayInSec          int `env:"REPORT_RETRY_DELAY_IN_SEC" env-default:"30"`
+	JobTimeoutInSec          int `env:"REPORT_JOB_TIMEOUT_IN_SEC" env-default:"600"` // 60 * 10
+	SchedulerPollInSec       int `env:"REPORT_SCHEDULER_POLL_IN_SEC" env-default:"30"`
+	SchedulerLockExpireInSec int `env:"REPORT_SCHEDULER_LOCK_EXPIRE_IN_SEC" env-default:"60"`
+}
+
+type SMTPCfg struct {
+	Host         string `env:"SMTP_HOST" env-default:"localhost"`
+	Port         int    `env:"SMTP_PORT" env-default:"1025"`
+	Username     string `env:"SMTP_USERNAME" env-default:""`
+	Password     string `env:"SMTP_PASSWORD" env-default:""`
+	From         string `env:"SMTP_FROM" env-default:"reports@order-service.local"`
+	TimeoutInSec int    `env:"SMTP_TIMEOUT_IN_SEC" env-default:"30"`
+}
+
+type AnalyticsCfg struct {
+	Timezone                    string `env:"ANALYTICS_TIMEZONE" env-default:"UTC"`
+	LeaderboardCacheExpireInSec int    `env:"ANALYTICS_LEADERBOARD_CACHE_EXPIRE_IN_SEC" env-default:"300"` // 60 * 5
+}
+
+type ShipmentCfg struct {
+	WebhookSecret string `env:"SHIPMENT_WEBHOOK_SECRET" env-default:""`
+}
+
+type PaymentCfg struct {
+	WebhookSecret         string `env:"PAYMENT_WEBHOOK_SECRET" env-default:""`
+	CardWebhookURL        string `env:"PAYMENT_CARD_WEBHOOK_URL" env-default:"http://localhost:8080/v1/payments/webhook"`
+	CardConfirmDelayInSec int    `env:"PAYMENT_CARD_CONFIRM_DELAY_IN_SEC" env-default:"2"`
+	CardTimeoutInSec      int    `env:"PAYMENT_CARD_TIMEOUT_IN_SEC" env-default:"10"`
+}
+
+type WalletCfg struct {
+	TopUpMaxPerTransaction core.Money `env:"WALLET_TOP_UP_MAX_PER_TRANSACTION" env-default:"1000"`
+	TopUpMaxPerDay         core.Money `env:"WALLET_TOP_UP_MAX_PER_DAY" env-default:"2000"`
+	TopUpApprovalThreshold core.Money `env:"WALLET_TOP_UP_APPROVAL_THRESHOLD" env-default:"500"` // above it a top-up waits for an admin
+}
+
+type SubscriptionCfg struct {
+	PollInSec       int `env:"SUBSCRIPTION_POLL_IN_SEC" env-default:"30"`
+	MaxAttempts     int `env:"SUBSCRIPTION_MAX_ATTEMPTS" env-default:"3"`
+	RetryDelayInSec int `env:"SUBSCRIPTION_RETRY_DELAY_IN_SEC" env-default:"3600"` // 60 * 60
+	ClaimTTLInSec   int `env:"SUBSCRIPTION_CLAIM_TTL_IN_SEC" env-default:"300"`    // 60 * 5
+}
+
 type Config struct {
 	PGCfg
 	RDCfg
 	AWSCfg
 	JWTCfg
+	OrderCfg
+	IdempotencyCfg
+	CurrencyCfg
+	TaxCfg
+	CartCfg
+	OutboxCfg
+	ReportCfg
+	SMTPCfg
+	AnalyticsCfg
+	ShipmentCfg
+	PaymentCfg
+	WalletCfg
+	SubscriptionCfg
 }
 
 func NewConfig() *Config {
@@ -21,36 +21,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Login a specific user with the input payload",
-                "consumes": [
+        "/addresses/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the address book of the current user, the default address comes first",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
-                ],
-                "summary": "Login",
-                "parameters": [
-                    {
-                        "description": "Auth login request body",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.AuthLogin"
-                        }
-                    }
+                    "addresses"
                 ],
+                "summary": "Get Addresses",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.TokenResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Address"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/core.DefaultError"
                         }
@@ -62,34 +59,40 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/refresh": {
+            },
             "post": {
-                "description": "Rotate current user's session token",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add an address to the address book of the current user, the first address becomes the default one",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "auth"
+                    "addresses"
                 ],
-                "summary": "Refresh token",
+                "summary": "Create Address",
                 "parameters": [
                     {
-                        "description": "Auth refresh request body",
+                        "description": "Address request body",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.RefreshTokenRequest"
+                            "$ref": "#/definitions/entity.AddressRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/entity.TokenResponse"
+                            "$ref": "#/definitions/entity.Address"
                         }
                     },
                     "400": {
@@ -104,42 +107,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/core.DefaultError"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/core.DefaultError"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Create a new user with the input payload",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Create a new user",
-                "parameters": [
-                    {
-                        "description": "Auth register request body",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.AuthUsernamePassword"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/core.DefaultError"
                         }
@@ -153,35 +122,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/sign-out": {
-            "post": {
+        "/addresses/:addressID": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Sign out current user's session with specific device",
+                "description": "Replace an address of the current user, orders already shipped to it keep the address they were placed with",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "auth"
+                    "addresses"
                 ],
-                "summary": "Sign out",
+                "summary": "Update Address",
                 "parameters": [
                     {
-                        "description": "Auth sign out request body",
+                        "type": "integer",
+                        "description": "Address's ID",
+                        "name": "addressID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Address request body",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.AuthSignOut"
+                            "$ref": "#/definitions/entity.AddressRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Address"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -202,26 +190,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/sign-out-all": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Sign out current user's session from all devices",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Remove an address of the current user, the most recently added of the others becomes the default one",
                 "tags": [
-                    "auth"
+                    "addresses"
+                ],
+                "summary": "Delete Address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Address's ID",
+                        "name": "addressID",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Sign out all",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -244,26 +242,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/": {
-            "get": {
+        "/addresses/:addressID/default": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all of orders of the current user or entire users based on user's role",
+                "description": "Make an address of the current user the default one, orders that do not name an address are shipped to it",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "orders"
+                    "addresses"
+                ],
+                "summary": "Set Default Address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Address's ID",
+                        "name": "addressID",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Orders",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.Order"
-                            }
+                            "$ref": "#/definitions/entity.Address"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
                         }
                     },
                     "401": {
@@ -285,35 +298,97 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/analytics/leaderboards/{kind}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new order with the input payload",
-                "consumes": [
+                "description": "Rank products by revenue or units, customers by spend or number of orders, or orders by value. Days are UTC, cancelled orders are left out and amounts are in the base currency. Leaderboards of a window are cached for a few minutes, only admin can do this action",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "analytics"
                 ],
-                "summary": "Create a new order",
+                "summary": "Get Leaderboard",
                 "parameters": [
                     {
-                        "description": "Create order request body",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.OrderRequest"
-                        }
+                        "enum": [
+                            "products",
+                            "customers",
+                            "orders"
+                        ],
+                        "type": "string",
+                        "description": "What is ranked",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "revenue",
+                            "units",
+                            "spend",
+                            "orders",
+                            "value"
+                        ],
+                        "type": "string",
+                        "description": "What entries are ranked by, revenue for products, spend for customers and value for orders by default",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "secondary",
+                            "recent",
+                            "id"
+                        ],
+                        "type": "string",
+                        "description": "How entries with the same value are ordered, the lowest id settles anything left. secondary by default",
+                        "name": "tie_break",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries, 10 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "7d",
+                            "30d",
+                            "90d",
+                            "365d"
+                        ],
+                        "type": "string",
+                        "description": "Days ending today, cannot be combined with dates. 30d when no dates are given",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day ranked (2006-01-02), 30 days before the end date by default",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day ranked (2006-01-02), today by default",
+                        "name": "end_date",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Leaderboard"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -327,12 +402,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/core.DefaultError"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/core.DefaultError"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -342,39 +411,80 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/:orderID/invoice": {
+        "/analytics/orders": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get specific order of the current user and export to pdf",
+                "description": "Get the order count, revenue, average order value and units sold of every day, week, month or quarter, buckets without orders are zero. Cancelled orders are left out and amounts are in the base currency. Customers only get their own orders",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "orders"
+                    "analytics"
                 ],
-                "summary": "Get Order",
+                "summary": "Get Order Time Series",
                 "parameters": [
+                    {
+                        "enum": [
+                            "day",
+                            "week",
+                            "month",
+                            "quarter"
+                        ],
+                        "type": "string",
+                        "description": "Bucket size, day by default",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day of the series (2006-01-02), 30 buckets before the end date by default",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the series (2006-01-02), today by default",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone the days are read in, like Asia/Jakarta",
+                        "name": "timezone",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Order's ID",
-                        "name": "orderID",
-                        "in": "path",
-                        "required": true
+                        "description": "Only orders of this user, admin only",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only orders having this product, counting its lines only",
+                        "name": "product_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "301": {
-                        "description": "Moved Permanently"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TimeSeries"
+                        }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/core.DefaultError"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/core.DefaultError"
                         }
@@ -388,30 +498,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/orders-by-month": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/login": {
+            "post": {
+                "description": "Login a specific user with the input payload",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get aggregated orders group by month of the current user",
                 "tags": [
-                    "orders"
+                    "auth"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Auth login request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuthLogin"
+                        }
+                    }
                 ],
-                "summary": "Get Aggregated Orders By Month",
                 "responses": {
                     "200": {
-                        "description": "OK"
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/core.DefaultError"
+                            "$ref": "#/definitions/entity.TokenResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/core.DefaultError"
                         }
@@ -425,32 +541,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/summarize": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/refresh": {
+            "post": {
+                "description": "Rotate current user's session token",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get summarized orders of the current user and export to excel",
                 "tags": [
-                    "orders"
+                    "auth"
                 ],
-                "summary": "Get Orders Summarize",
+                "summary": "Refresh token",
                 "parameters": [
                     {
-                        "description": "Order summary request body",
+                        "description": "Auth refresh request body",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.OrdersSummarizeReq"
+                            "$ref": "#/definitions/entity.RefreshTokenRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "301": {
-                        "description": "Moved Permanently"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -461,30 +590,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/top-by-price": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/register": {
+            "post": {
+                "description": "Create a new user with the input payload",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get top five orders order by price",
                 "tags": [
-                    "orders"
+                    "auth"
                 ],
-                "summary": "Get Top Five Orders",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "Auth register request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.Order"
-                            }
+                            "$ref": "#/definitions/entity.AuthUsernamePassword"
                         }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/core.DefaultError"
                         }
@@ -498,21 +630,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/": {
-            "get": {
-                "description": "Get entire products",
+        "/auth/sign-out": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sign out current user's session with specific device",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "products"
+                    "auth"
+                ],
+                "summary": "Sign out",
+                "parameters": [
+                    {
+                        "description": "Auth sign out request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AuthSignOut"
+                        }
+                    }
                 ],
-                "summary": "Get Products",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.Product"
-                            }
+                            "$ref": "#/definitions/core.DefaultError"
                         }
                     },
                     "404": {
@@ -528,30 +679,26 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/sign-out-all": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new product with input payload",
+                "description": "Sign out current user's session from all devices",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "auth"
                 ],
-                "summary": "Create Product",
+                "summary": "Sign out all",
                 "responses": {
-                    "201": {
-                        "description": "Created"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/core.DefaultError"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -574,31 +721,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/:productID": {
+        "/cart/": {
             "get": {
-                "description": "Get specific product",
-                "tags": [
-                    "products"
-                ],
-                "summary": "Get Product",
-                "parameters": [
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Product's ID",
-                        "name": "productID",
-                        "in": "path",
-                        "required": true
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Get the cart of the current user, priced with the live price and stock of every product",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Get Cart",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Product"
+                            "$ref": "#/definitions/entity.Cart"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/core.DefaultError"
                         }
@@ -611,48 +757,27 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the specific product",
+                "description": "Remove every product from the cart of the current user",
                 "tags": [
-                    "products"
-                ],
-                "summary": "Update Product",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Product's ID",
-                        "name": "productID",
-                        "in": "path",
-                        "required": true
-                    }
+                    "cart"
                 ],
+                "summary": "Clear Cart",
                 "responses": {
                     "200": {
                         "description": "OK"
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/core.DefaultError"
-                        }
-                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/core.DefaultError"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/core.DefaultError"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -660,30 +785,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/cart/checkout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete the specific product",
+                "description": "Place an order with the products of the cart, they leave the cart once the order is placed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "products"
+                    "cart"
                 ],
-                "summary": "Delete Product",
+                "summary": "Checkout Cart",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product's ID",
-                        "name": "productID",
-                        "in": "path",
-                        "required": true
+                        "description": "Key to safely retry the request without applying it twice",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Checkout request body",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CheckoutRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Order"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -697,39 +840,67 @@ const docTemplate = `{
                             "$ref": "#/definitions/core.DefaultError"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/core.DefaultError"
                         }
-                    }
-                }
-            }
-        },
-        "/products/search": {
-            "get": {
-                "description": "Search products with input query string",
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/items": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a quantity of a product to the cart of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "products"
+                    "cart"
                 ],
-                "summary": "Search Products",
+                "summary": "Add Cart Item",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Query string",
-                        "name": "name",
-                        "in": "query",
-                        "required": true
+                        "description": "Add cart item request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CartItemRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.Product"
-                            }
+                            "$ref": "#/definitions/entity.Cart"
                         }
                     },
                     "400": {
@@ -738,12 +909,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/core.DefaultError"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/core.DefaultError"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -753,26 +936,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/": {
-            "get": {
+        "/cart/items/:productID": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get entire users",
+                "description": "Remove a product from the cart of the current user",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "users"
+                    "cart"
+                ],
+                "summary": "Remove Cart Item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product's ID",
+                        "name": "productID",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.User"
-                            }
+                            "$ref": "#/definitions/entity.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
                         }
                     },
                     "404": {
@@ -788,34 +992,59 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/:userID": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get specific user",
+                "description": "Replace the quantity of a product that is already in the cart of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "users"
+                    "cart"
                 ],
-                "summary": "Get User",
+                "summary": "Update Cart Item",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User's ID",
-                        "name": "userID",
+                        "description": "Product's ID",
+                        "name": "productID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update cart item request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CartQuantityRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/entity.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
                         }
                     },
                     "404": {
@@ -833,33 +1062,150 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/balance": {
+        "/coupons/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every coupon with its redemption count, only admin can do this action",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "coupons"
+                ],
+                "summary": "Get Coupons",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Coupon"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add user balance",
+                "description": "Create a percentage, fixed amount or buy X get Y coupon, only admin can do this action",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "users"
+                    "coupons"
                 ],
-                "summary": "Add User Balance",
+                "summary": "Create Coupon",
                 "parameters": [
                     {
-                        "description": "User request body",
+                        "description": "Create coupon request body",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UserRequest"
+                            "$ref": "#/definitions/entity.CouponRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Coupon"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
                         }
                     }
+                }
+            }
+        },
+        "/coupons/:code": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop a coupon from being redeemed, only admin can do this action",
+                "tags": [
+                    "coupons"
+                ],
+                "summary": "Deactivate Coupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -875,113 +1221,4625 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/profile": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get the current user profile",
-                "tags": [
-                    "users"
-                ],
-                "summary": "Get User Profile",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/entity.User"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/core.DefaultError"
+        "/currencies/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every currency orders can be paid in, with its rate from the base currency listed first",
+                "tags": [
+                    "currencies"
+                ],
+                "summary": "Get Exchange Rates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ExchangeRate"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/currencies/:currency": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create or update the rate of a currency, only admin can do this action",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currencies"
+                ],
+                "summary": "Set Exchange Rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Exchange rate request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ExchangeRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop selling in a currency, only admin can do this action",
+                "tags": [
+                    "currencies"
+                ],
+                "summary": "Delete Exchange Rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/currencies/reload": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Load the rates from the configured rates file, only admin can do this action",
+                "tags": [
+                    "currencies"
+                ],
+                "summary": "Reload Exchange Rates",
+                "responses": {
+                    "200": {
+                        "description": "Number of rates loaded",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a page of orders of the current user or entire users based on user's role",
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get Orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, 20 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Orders created from this date (2006-01-02 or RFC 3339)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Orders created before this date (2006-01-02 or RFC 3339)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total price in the base currency",
+                        "name": "min_total_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum total price in the base currency",
+                        "name": "max_total_price",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "total_price"
+                        ],
+                        "type": "string",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only orders of this user, admin only",
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Order"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new order with the input payload, its stock is reserved until the order is paid or the reservation expires. It is shipped to the given address, the named saved address or else the default address of the user. The order is charged to the wallet balance or to a card, it stays pending until its payment is captured or is cancelled once its reservation expires, and a payment that cannot be made comes back failed with its reason",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create a new order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request without applying it twice",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Create order request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/:orderID/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel an order, give back its stock and refund or void its payments. Customers can only cancel their own orders within the cancellation window, admin can cancel any order",
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cancel Order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request without applying it twice",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order's ID",
+                        "name": "orderID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/:orderID/invoice": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get specific order of the current user and download it as a pdf invoice, prefer queueing an invoice report with POST /reports",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get Order",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order's ID",
+                        "name": "orderID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/:orderID/payments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every attempt at paying an order with the references of its provider",
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get Order Payments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order's ID",
+                        "name": "orderID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Payment"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Make another attempt at paying a pending order of the current user, e.g. after a declined card. A wallet payment is captured right away, a card payment is confirmed by the gateway later",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Pay Order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request without applying it twice",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order's ID",
+                        "name": "orderID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/:orderID/shipments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the shipments of an order with their tracking history, oldest event first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipments"
+                ],
+                "summary": "Get Shipments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order's ID",
+                        "name": "orderID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Shipment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record the carrier and tracking number of a parcel of a fulfilling or shipped order, the order is moved to shipped with its first shipment, only for admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipments"
+                ],
+                "summary": "Create Shipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order's ID",
+                        "name": "orderID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shipment request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ShipmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Shipment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/:orderID/status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every status transition of an order with the actor who made it",
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get Order Status Histories",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order's ID",
+                        "name": "orderID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.OrderStatusHistory"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move an order to the next status of its lifecycle, only admin can do this action. An order is only paid once its payment is captured",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Update Order Status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order's ID",
+                        "name": "orderID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order status request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/orders-by-month": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get aggregated orders group by month of the current user, prefer GET /analytics/orders?granularity=month",
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get Aggregated Orders By Month",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/summarize": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get summarized orders of every user and download them as xlsx, csv, ndjson or ods, picked with the format query or the Accept header. Only admin can export the summary. Prefer queueing a summarize report with POST /reports",
+                "produces": [
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.oasis.opendocument.spreadsheet"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get Orders Summarize",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "enum": [
+                            "xlsx",
+                            "csv",
+                            "ndjson",
+                            "ods"
+                        ],
+                        "type": "string",
+                        "description": "Report format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Order summary request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrdersSummarizeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/top-by-price": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the five most expensive orders by their price in the base currency, prefer GET /analytics/leaderboards/orders which takes a date range and a limit",
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get Top Five Orders",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Order"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook": {
+            "post": {
+                "description": "Payment gateway callback, the body has to be signed with the webhook secret in the X-Signature header as hex encoded HMAC-SHA256. The order is paid once its payment is captured",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Receive Payment Event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 of the body",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment gateway event",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.GatewayEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/": {
+            "get": {
+                "description": "Get entire products",
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get Products",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Product"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new product with input payload",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Create Product",
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/:productID": {
+            "get": {
+                "description": "Get specific product",
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get Product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product's ID",
+                        "name": "productID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Product"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the specific product",
+                "tags": [
+                    "products"
+                ],
+                "summary": "Update Product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product's ID",
+                        "name": "productID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the specific product",
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete Product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product's ID",
+                        "name": "productID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/search": {
+            "get": {
+                "description": "Search products with input query string",
+                "tags": [
+                    "products"
+                ],
+                "summary": "Search Products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Query string",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Product"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue the generation of an orders summary (xlsx by default, or csv, ndjson or ods) or an order invoice pdf, poll the job for its file. Only admin can export the orders summary",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Create Report Job",
+                "parameters": [
+                    {
+                        "description": "Report job request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.JobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/:reportID": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the status of a report job of the current user, the file of a succeeded job is downloaded from /reports/:reportID/download",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get Report Job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report job's ID",
+                        "name": "reportID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/:reportID/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a queued or running report job of the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Cancel Report Job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report job's ID",
+                        "name": "reportID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/:reportID/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the file of a succeeded report job of the current user",
+                "produces": [
+                    "application/pdf",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.oasis.opendocument.spreadsheet"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Download Report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report job's ID",
+                        "name": "reportID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/:reportID/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a dead or cancelled report job of the current user again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Retry Report Job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report job's ID",
+                        "name": "reportID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/schedules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the report schedules of the current admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get Report Schedules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Schedule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mail the orders summary of the days before every run to the recipients, runs follow a 5 field cron read in the timezone of the schedule (UTC by default). The summary covers 7 days and is an xlsx file by default, only admin can do this action",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Create Report Schedule",
+                "parameters": [
+                    {
+                        "description": "Report schedule request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/schedules/:scheduleID": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a report schedule of the current admin, its next run is worked out again. Leaving active out keeps it as it is",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Update Report Schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report schedule's ID",
+                        "name": "scheduleID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Report schedule request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a report schedule of the current admin, the jobs it already queued keep going",
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Delete Report Schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report schedule's ID",
+                        "name": "scheduleID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/shipments/webhook": {
+            "post": {
+                "description": "Carrier status callback, the body has to be signed with the webhook secret in the X-Signature header as hex encoded HMAC-SHA256. A callback sent again with the same event id is accepted and ignored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipments"
+                ],
+                "summary": "Receive Carrier Event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 of the body",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Carrier event",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CarrierEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the subscriptions of the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get Subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Subscription"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Re-order the same products daily, weekly or monthly at the time of the first run, or following a 5 field cron, read in the timezone of the subscription (UTC by default). Every run places an order paid from the wallet, a run failing on the balance or the stock is retried and the user is notified through the subscription events",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Create Subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request without applying it twice",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Subscription request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.SubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/:subscriptionID": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a subscription of the current user with its next run and how its last run went, admin can see every subscription",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get Subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription's ID",
+                        "name": "subscriptionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the products and the schedule of a subscription of the current user, its next run is worked out again and a run being retried is given up. A paused subscription stays paused",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Update Subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription's ID",
+                        "name": "subscriptionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.SubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a subscription of the current user, the orders it already placed are kept",
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Delete Subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription's ID",
+                        "name": "subscriptionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/:subscriptionID/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop placing the orders of an active subscription of the current user until it is resumed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Pause Subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription's ID",
+                        "name": "subscriptionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/:subscriptionID/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Place the orders of a paused subscription of the current user again from its next run, the runs missed while it was paused are not made up for",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Resume Subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription's ID",
+                        "name": "subscriptionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/:subscriptionID/skip": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Skip the next order of an active subscription of the current user, a run being retried is given up",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Skip Next Subscription Run",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription's ID",
+                        "name": "subscriptionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/taxes/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the tax rules of every region and tax class",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "Get Tax Rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TaxRule"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create or replace the tax rule of a region and tax class, only admin can do this action",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "Set Tax Rule",
+                "parameters": [
+                    {
+                        "description": "Tax rule request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/taxes/:ruleID": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop taxing a tax class in a region, only admin can do this action",
+                "tags": [
+                    "taxes"
+                ],
+                "summary": "Delete Tax Rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tax rule's ID",
+                        "name": "ruleID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get entire users",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get Users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.User"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/:userID": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get specific user",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User's ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/:userID/balance/adjustments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Credit or debit the wallet of a user for a reason code, the adjustment is recorded in the balance history along with the admin who made it. A debit never takes the balance below zero. Only admin can adjust balances",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Adjust Balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request without applying it twice",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User's ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Adjustment request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AdjustmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.LedgerEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/balance": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Top the wallet balance of the current user up within the limits per top-up and per day. A top-up below the approval threshold is credited right away, a larger one is accepted as pending until an admin reviews it",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Add User Balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key to safely retry the request without applying it twice",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "User request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TopUp"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TopUp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/balance/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Page through the wallet ledger entries of the current user newest first: top-ups, order charges, refunds and adjustments, with the balance each one left behind",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get Balance History",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, 20 by default and 100 at most",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.LedgerEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/balance/top-ups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the top-ups newest first, admin see the top-ups of every user and customers only their own",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get Top-Ups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only the top-ups in this status: pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TopUp"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/balance/top-ups/:topUpID/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approve a pending top-up and credit the wallet of its user, only admin can review top-ups and never their own",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Approve Top-Up",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Top-up's ID",
+                        "name": "topUpID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review request body",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TopUp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/balance/top-ups/:topUpID/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reject a pending top-up, the wallet is left untouched. Only admin can review top-ups and never their own",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Reject Top-Up",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Top-up's ID",
+                        "name": "topUpID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review request body",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TopUp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/currency": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Choose the currency the current user is charged in when an order does not ask for one",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Set User Currency",
+                "parameters": [
+                    {
+                        "description": "User currency request body",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserCurrencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current user profile",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get User Profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/core.DefaultError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "core.DefaultError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "status code\nex: 404",
+                    "type": "integer"
+                },
+                "debug": {
+                    "description": "debug information",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "error message\nex: the resource could be not found",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "status description\nex: not found",
+                    "type": "string"
+                }
+            }
+        },
+        "core.RoundingMode": {
+            "type": "string",
+            "enum": [
+                "half_up",
+                "half_even",
+                "up",
+                "down"
+            ],
+            "x-enum-comments": {
+                "RoundDown": "toward zero",
+                "RoundHalfEven": "bankers' rounding, 0.125 -\u003e 0.12",
+                "RoundHalfUp": "half away from zero, 0.125 -\u003e 0.13",
+                "RoundUp": "away from zero"
+            },
+            "x-enum-varnames": [
+                "RoundHalfUp",
+                "RoundHalfEven",
+                "RoundUp",
+                "RoundDown"
+            ]
+        },
+        "entity.Address": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "line1": {
+                    "type": "string"
+                },
+                "line2": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "recipient": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.AddressRequest": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "San Francisco"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "US"
+                },
+                "is_default": {
+                    "description": "IsDefault makes the address the default one, the first address of a user is always the default one",
+                    "type": "boolean"
+                },
+                "label": {
+                    "description": "Label is optional, e.g. Home or Office",
+                    "type": "string",
+                    "example": "Home"
+                },
+                "line1": {
+                    "type": "string",
+                    "example": "1 Market St"
+                },
+                "line2": {
+                    "type": "string",
+                    "example": "Suite 200"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+1 415 555 0100"
+                },
+                "postal_code": {
+                    "type": "string",
+                    "example": "94105"
+                },
+                "recipient": {
+                    "type": "string",
+                    "example": "Jane Doe"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "CA"
+                }
+            }
+        },
+        "entity.AdjustmentRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 10.5
+                },
+                "direction": {
+                    "type": "string",
+                    "example": "credit"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Late delivery of order 42"
+                },
+                "reason_code": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ReasonCode"
+                        }
+                    ],
+                    "example": "goodwill"
+                }
+            }
+        },
+        "entity.AuthLogin": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AuthSignOut": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AuthUsernamePassword": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CarrierEvent": {
+            "type": "object",
+            "properties": {
+                "carrier": {
+                    "type": "string",
+                    "example": "ups"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Departed from facility"
+                },
+                "event_id": {
+                    "type": "string",
+                    "example": "evt_0001"
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Oakland, CA"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ShipmentStatus"
+                        }
+                    ],
+                    "example": "in_transit"
+                },
+                "tracking_number": {
+                    "type": "string",
+                    "example": "1Z999AA10123456784"
+                }
+            }
+        },
+        "entity.Cart": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CartLine"
+                    }
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.CartItemRequest": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.CartLine": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "line_total": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.CartQuantityRequest": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.CheckoutRequest": {
+            "type": "object",
+            "properties": {
+                "address_id": {
+                    "description": "AddressId is a saved address to ship to, the default address is used when neither it nor ShippingAddress is given",
+                    "type": "integer"
+                },
+                "card_token": {
+                    "description": "CardToken is the token of the card to charge, only for card payments",
+                    "type": "string"
+                },
+                "coupons": {
+                    "description": "Coupons are optional coupon codes, applied in the given order",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "currency": {
+                    "description": "Currency is optional, the user's preferred currency or the base currency is used when empty",
+                    "type": "string"
+                },
+                "payment_method": {
+                    "description": "PaymentMethod is wallet or card, the wallet balance is charged when empty",
+                    "type": "string",
+                    "example": "wallet"
+                },
+                "region": {
+                    "description": "Region is where the order is taxed, e.g. US-CA, the configured default region is used when empty",
+                    "type": "string"
+                },
+                "shipping_address": {
+                    "description": "ShippingAddress is an address to ship to that is not saved in the address book",
+                    "type": "object"
+                }
+            }
+        },
+        "entity.Coupon": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amount_off": {
+                    "type": "number"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "get_quantity": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "min_spend": {
+                    "type": "number"
+                },
+                "per_user_limit": {
+                    "type": "integer"
+                },
+                "percent_off": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "redeemed_count": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.CouponType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "usage_limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.CouponRequest": {
+            "type": "object",
+            "properties": {
+                "amount_off": {
+                    "type": "number"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "get_quantity": {
+                    "type": "integer"
+                },
+                "min_spend": {
+                    "type": "number"
+                },
+                "per_user_limit": {
+                    "type": "integer"
+                },
+                "percent_off": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.CouponType"
+                },
+                "usage_limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.CouponType": {
+            "type": "string",
+            "enum": [
+                "percentage",
+                "fixed",
+                "buy_x_get_y"
+            ],
+            "x-enum-varnames": [
+                "CouponPercentage",
+                "CouponFixed",
+                "CouponBuyXGetY"
+            ]
+        },
+        "entity.ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ExchangeRateRequest": {
+            "type": "object",
+            "properties": {
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.Frequency": {
+            "type": "string",
+            "enum": [
+                "daily",
+                "weekly",
+                "monthly",
+                "cron"
+            ],
+            "x-enum-varnames": [
+                "FrequencyDaily",
+                "FrequencyWeekly",
+                "FrequencyMonthly",
+                "FrequencyCron"
+            ]
+        },
+        "entity.GatewayEvent": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "string",
+                    "example": "evt_0001"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "card"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "ch_0001"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.PaymentStatus"
+                        }
+                    ],
+                    "example": "captured"
+                }
+            }
+        },
+        "entity.Granularity": {
+            "type": "string",
+            "enum": [
+                "day",
+                "week",
+                "month",
+                "quarter"
+            ],
+            "x-enum-varnames": [
+                "GranularityDay",
+                "GranularityWeek",
+                "GranularityMonth",
+                "GranularityQuarter"
+            ]
+        },
+        "entity.Job": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "available_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "params": {
+                    "$ref": "#/definitions/entity.JobParams"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.JobStatus"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.JobType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.JobParams": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "format": {
+                    "$ref": "#/definitions/pkg.ReportFormat"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schedule_id": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.JobRequest": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "format": {
+                    "enum": [
+                        "xlsx",
+                        "csv",
+                        "ndjson",
+                        "ods"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pkg.ReportFormat"
+                        }
+                    ]
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.JobType"
+                }
+            }
+        },
+        "entity.JobStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "succeeded",
+                "cancelled",
+                "dead"
+            ],
+            "x-enum-varnames": [
+                "JobStatusQueued",
+                "JobStatusRunning",
+                "JobStatusSucceeded",
+                "JobStatusCancelled",
+                "JobStatusDead"
+            ]
+        },
+        "entity.JobType": {
+            "type": "string",
+            "enum": [
+                "summarize",
+                "invoice"
+            ],
+            "x-enum-varnames": [
+                "JobTypeSummarize",
+                "JobTypeInvoice"
+            ]
+        },
+        "entity.Leaderboard": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LeaderboardEntry"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/entity.LeaderboardKind"
+                },
+                "metric": {
+                    "$ref": "#/definitions/entity.LeaderboardMetric"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "tie_break": {
+                    "$ref": "#/definitions/entity.TieBreak"
+                },
+                "window": {
+                    "$ref": "#/definitions/entity.LeaderboardWindow"
+                }
+            }
+        },
+        "entity.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "num_of_orders": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "units_sold": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.LeaderboardKind": {
+            "type": "string",
+            "enum": [
+                "products",
+                "customers",
+                "orders"
+            ],
+            "x-enum-varnames": [
+                "LeaderboardProducts",
+                "LeaderboardCustomers",
+                "LeaderboardOrders"
+            ]
+        },
+        "entity.LeaderboardMetric": {
+            "type": "string",
+            "enum": [
+                "revenue",
+                "units",
+                "spend",
+                "orders",
+                "value"
+            ],
+            "x-enum-varnames": [
+                "MetricRevenue",
+                "MetricUnits",
+                "MetricSpend",
+                "MetricOrders",
+                "MetricValue"
+            ]
+        },
+        "entity.LeaderboardWindow": {
+            "type": "string",
+            "enum": [
+                "7d",
+                "30d",
+                "90d",
+                "365d"
+            ],
+            "x-enum-varnames": [
+                "Window7Days",
+                "Window30Days",
+                "Window90Days",
+                "Window365Days"
+            ]
+        },
+        "entity.LedgerAccount": {
+            "type": "string",
+            "enum": [
+                "wallet",
+                "external",
+                "orders"
+            ],
+            "x-enum-varnames": [
+                "LedgerAccountWallet",
+                "LedgerAccountExternal",
+                "LedgerAccountOrders"
+            ]
+        },
+        "entity.LedgerEntry": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/entity.LedgerAccount"
+                },
+                "actor_id": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "balance_after": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/entity.LedgerKind"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/entity.ReasonCode"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "transaction_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.LedgerKind": {
+            "type": "string",
+            "enum": [
+                "top_up",
+                "order_charge",
+                "refund",
+                "adjustment"
+            ],
+            "x-enum-varnames": [
+                "LedgerKindTopUp",
+                "LedgerKindOrderCharge",
+                "LedgerKindRefund",
+                "LedgerKindAdjustment"
+            ]
+        },
+        "entity.Order": {
+            "type": "object",
+            "properties": {
+                "base_total_price": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderDiscount"
+                    }
+                },
+                "exchange_rate": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderItem"
+                    }
+                },
+                "payment": {
+                    "type": "object"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "reserved_until": {
+                    "type": "string"
+                },
+                "shipments": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "shipping_address": {
+                    "$ref": "#/definitions/entity.OrderAddress"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderTax"
+                    }
+                },
+                "total_price": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.OrderAddress": {
+            "type": "object",
+            "properties": {
+                "address_id": {
+                    "type": "integer"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "line1": {
+                    "type": "string"
+                },
+                "line2": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "recipient": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OrderDiscount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "base_amount": {
+                    "type": "number"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "coupon_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.OrderItem": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "exchange_rate": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "product_price": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "tax": {
+                    "type": "number"
+                },
+                "tax_class": {
+                    "type": "string"
+                },
+                "tax_inclusive": {
+                    "type": "boolean"
+                },
+                "tax_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.OrderRequest": {
+            "type": "object",
+            "properties": {
+                "address_id": {
+                    "description": "AddressId is a saved address to ship to, the default address is used when neither it nor ShippingAddress is given",
+                    "type": "integer"
+                },
+                "card_token": {
+                    "description": "CardToken is the token of the card to charge, only for card payments",
+                    "type": "string"
+                },
+                "coupons": {
+                    "description": "Coupons are optional coupon codes, applied in the given order",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "currency": {
+                    "description": "Currency is optional, the user's preferred currency or the base currency is used when empty",
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ProductItem"
+                    }
+                },
+                "payment_method": {
+                    "description": "PaymentMethod is wallet or card, the wallet balance is charged when empty",
+                    "type": "string",
+                    "example": "wallet"
+                },
+                "region": {
+                    "description": "Region is where the order is taxed, e.g. US-CA, the configured default region is used when empty",
+                    "type": "string"
+                },
+                "shipping_address": {
+                    "description": "ShippingAddress is an address to ship to that is not saved in the address book",
+                    "type": "object"
+                }
+            }
+        },
+        "entity.OrderStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "paid",
+                "fulfilling",
+                "shipped",
+                "delivered",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "OrderStatusPending",
+                "OrderStatusPaid",
+                "OrderStatusFulfilling",
+                "OrderStatusShipped",
+                "OrderStatusDelivered",
+                "OrderStatusCancelled"
+            ]
+        },
+        "entity.OrderStatusHistory": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "to_status": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                }
+            }
+        },
+        "entity.OrderStatusRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                }
+            }
+        },
+        "entity.OrderTax": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "base_amount": {
+                    "type": "number"
+                },
+                "base_taxable_amount": {
+                    "type": "number"
+                },
+                "inclusive": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "taxable_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.OrdersSummarizeReq": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.PaymentStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PaymentRequest": {
+            "type": "object",
+            "properties": {
+                "card_token": {
+                    "type": "string",
+                    "example": "tok_visa"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "example": "wallet"
+                }
+            }
+        },
+        "entity.PaymentStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "authorized",
+                "captured",
+                "failed",
+                "voided",
+                "refunded"
+            ],
+            "x-enum-varnames": [
+                "PaymentStatusPending",
+                "PaymentStatusAuthorized",
+                "PaymentStatusCaptured",
+                "PaymentStatusFailed",
+                "PaymentStatusVoided",
+                "PaymentStatusRefunded"
+            ]
+        },
+        "entity.Product": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reserved_quantity": {
+                    "type": "integer"
+                },
+                "tax_class": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ProductItem": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ReasonCode": {
+            "type": "string",
+            "enum": [
+                "correction",
+                "goodwill",
+                "promotion",
+                "chargeback",
+                "fraud"
+            ],
+            "x-enum-varnames": [
+                "ReasonCodeCorrection",
+                "ReasonCodeGoodwill",
+                "ReasonCodePromotion",
+                "ReasonCodeChargeback",
+                "ReasonCodeFraud"
+            ]
+        },
+        "entity.RefreshTokenRequest": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ReviewRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Confirmed with the bank transfer"
+                }
+            }
+        },
+        "entity.Schedule": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cron": {
+                    "type": "string"
+                },
+                "format": {
+                    "$ref": "#/definitions/pkg.ReportFormat"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_job_id": {
+                    "type": "integer"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "range_days": {
+                    "type": "integer"
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "cron": {
+                    "type": "string",
+                    "example": "0 8 * * MON"
+                },
+                "format": {
+                    "enum": [
+                        "xlsx",
+                        "csv",
+                        "ndjson",
+                        "ods"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pkg.ReportFormat"
                         }
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Weekly orders summary"
+                },
+                "range_days": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/core.DefaultError"
-                        }
-                    }
+                    "example": [
+                        "finance@example.com"
+                    ]
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Jakarta"
                 }
             }
-        }
-    },
-    "definitions": {
-        "core.DefaultError": {
+        },
+        "entity.Shipment": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "status code\nex: 404",
-                    "type": "integer"
+                "carrier": {
+                    "type": "string"
                 },
-                "debug": {
-                    "description": "debug information",
+                "created_at": {
                     "type": "string"
                 },
-                "message": {
-                    "description": "error message\nex: the resource could be not found",
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ShipmentEvent"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_event_at": {
                     "type": "string"
                 },
+                "order_id": {
+                    "type": "integer"
+                },
                 "status": {
-                    "description": "status description\nex: not found",
+                    "$ref": "#/definitions/entity.ShipmentStatus"
+                },
+                "tracking_number": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "entity.AuthLogin": {
+        "entity.ShipmentEvent": {
             "type": "object",
             "properties": {
-                "device_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "password": {
+                "description": {
                     "type": "string"
                 },
-                "username": {
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
                     "type": "string"
-                }
-            }
-        },
-        "entity.AuthSignOut": {
-            "type": "object",
-            "properties": {
-                "device_id": {
+                },
+                "occurred_at": {
                     "type": "string"
+                },
+                "shipment_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.ShipmentStatus"
                 }
             }
         },
-        "entity.AuthUsernamePassword": {
+        "entity.ShipmentRequest": {
             "type": "object",
             "properties": {
-                "password": {
-                    "type": "string"
+                "carrier": {
+                    "type": "string",
+                    "example": "ups"
                 },
-                "username": {
-                    "type": "string"
+                "tracking_number": {
+                    "type": "string",
+                    "example": "1Z999AA10123456784"
                 }
             }
         },
-        "entity.Order": {
+        "entity.ShipmentStatus": {
+            "type": "string",
+            "enum": [
+                "label_created",
+                "in_transit",
+                "out_for_delivery",
+                "delivered",
+                "exception",
+                "returned"
+            ],
+            "x-enum-varnames": [
+                "ShipmentStatusLabelCreated",
+                "ShipmentStatusInTransit",
+                "ShipmentStatusOutForDelivery",
+                "ShipmentStatusDelivered",
+                "ShipmentStatusException",
+                "ShipmentStatusReturned"
+            ]
+        },
+        "entity.Subscription": {
             "type": "object",
             "properties": {
+                "address_id": {
+                    "type": "integer"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "cron": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "$ref": "#/definitions/entity.Frequency"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.OrderItem"
+                        "$ref": "#/definitions/entity.SubscriptionItem"
                     }
                 },
-                "total_price": {
-                    "type": "number"
+                "last_error": {
+                    "type": "string"
+                },
+                "last_order_id": {
+                    "type": "integer"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.SubscriptionStatus"
+                },
+                "timezone": {
+                    "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
@@ -991,49 +5849,73 @@ const docTemplate = `{
                 }
             }
         },
-        "entity.OrderItem": {
+        "entity.SubscriptionItem": {
             "type": "object",
             "properties": {
-                "order_id": {
-                    "type": "integer"
-                },
                 "product_id": {
                     "type": "integer"
                 },
-                "product_name": {
-                    "type": "string"
-                },
-                "product_price": {
-                    "type": "number"
-                },
                 "quantity": {
                     "type": "integer"
                 }
             }
         },
-        "entity.OrderRequest": {
+        "entity.SubscriptionRequest": {
             "type": "object",
             "properties": {
+                "address_id": {
+                    "type": "integer"
+                },
+                "cron": {
+                    "description": "Cron is only read for the cron frequency",
+                    "type": "string",
+                    "example": "0 8 * * MON"
+                },
+                "frequency": {
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "cron"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Frequency"
+                        }
+                    ]
+                },
                 "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.ProductItem"
+                        "$ref": "#/definitions/entity.SubscriptionItem"
                     }
-                }
-            }
-        },
-        "entity.OrdersSummarizeReq": {
-            "type": "object",
-            "properties": {
-                "end_date": {
-                    "type": "string"
                 },
-                "start_date": {
+                "name": {
+                    "type": "string",
+                    "example": "Weekly groceries"
+                },
+                "start_at": {
+                    "description": "StartAt is the first run of a daily, weekly or monthly subscription, when empty it is timed from now\nand the first order is placed one period later",
                     "type": "string"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Jakarta"
                 }
             }
         },
-        "entity.Product": {
+        "entity.SubscriptionStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "paused"
+            ],
+            "x-enum-varnames": [
+                "SubscriptionStatusActive",
+                "SubscriptionStatusPaused"
+            ]
+        },
+        "entity.TaxRule": {
             "type": "object",
             "properties": {
                 "created_at": {
@@ -1042,42 +5924,115 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
-                "image_url": {
-                    "type": "string"
+                "inclusive": {
+                    "type": "boolean"
                 },
                 "name": {
                     "type": "string"
                 },
-                "price": {
+                "rate": {
                     "type": "number"
                 },
-                "quantity": {
-                    "type": "integer"
+                "region": {
+                    "type": "string"
+                },
+                "rounding": {
+                    "$ref": "#/definitions/core.RoundingMode"
+                },
+                "tax_class": {
+                    "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "entity.ProductItem": {
+        "entity.TaxRuleRequest": {
+            "type": "object",
+            "properties": {
+                "inclusive": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "rounding": {
+                    "$ref": "#/definitions/core.RoundingMode"
+                },
+                "tax_class": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TieBreak": {
+            "type": "string",
+            "enum": [
+                "secondary",
+                "recent",
+                "id"
+            ],
+            "x-enum-comments": {
+                "TieBreakRecent": "the latest order first",
+                "TieBreakSecondary": "the other metric, units for order values"
+            },
+            "x-enum-varnames": [
+                "TieBreakSecondary",
+                "TieBreakRecent",
+                "TieBreakId"
+            ]
+        },
+        "entity.TimeSeries": {
             "type": "object",
             "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "$ref": "#/definitions/entity.Granularity"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TimeSeriesPoint"
+                    }
+                },
                 "product_id": {
                     "type": "integer"
                 },
-                "quantity": {
+                "start_date": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "entity.RefreshTokenRequest": {
+        "entity.TimeSeriesPoint": {
             "type": "object",
             "properties": {
-                "device_id": {
-                    "type": "string"
+                "average_order_value": {
+                    "type": "number"
                 },
-                "refresh_token": {
+                "bucket": {
                     "type": "string"
+                },
+                "num_of_orders": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "units_sold": {
+                    "type": "integer"
                 }
             }
         },
@@ -1103,6 +6058,48 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.TopUp": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "review_note": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.TopUpStatus"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.TopUpStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "TopUpStatusPending",
+                "TopUpStatusApproved",
+                "TopUpStatusRejected"
+            ]
+        },
         "entity.User": {
             "type": "object",
             "properties": {
@@ -1112,6 +6109,10 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "currency": {
+                    "description": "preferred currency, empty for the base currency",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -1126,6 +6127,14 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.UserCurrencyRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.UserRequest": {
             "type": "object",
             "properties": {
@@ -1133,6 +6142,21 @@ const docTemplate = `{
                     "type": "number"
                 }
             }
+        },
+        "pkg.ReportFormat": {
+            "type": "string",
+            "enum": [
+                "xlsx",
+                "csv",
+                "ndjson",
+                "ods"
+            ],
+            "x-enum-varnames": [
+                "ReportFormatXLSX",
+                "ReportFormatCSV",
+                "ReportFormatNDJSON",
+                "ReportFormatODS"
+            ]
         }
     },
     "securityDefinitions": {
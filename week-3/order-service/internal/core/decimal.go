@@ -0,0 +1,144 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The helpers below back the fixed point types of this package (Money, Rate).
+// Values are kept as int64 scaled by 10^scale so nothing ever goes through floats.
+
+var (
+	errInvalidDecimal  = errors.New("invalid decimal")
+	errDecimalOverflow = errors.New("decimal overflow")
+)
+
+func pow10(scale int) int64 {
+	value := int64(1)
+	for i := 0; i < scale; i++ {
+		value *= 10
+	}
+
+	return value
+}
+
+// parseDecimal parses strings such as "12", "-3.5" or "0.25".
+// Values with more than scale decimal places are rejected rather than rounded.
+func parseDecimal(s string, scale int) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errInvalidDecimal
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return 0, errInvalidDecimal
+	}
+	if len(fraction) > scale {
+		// trailing zeros are harmless, anything else would lose precision
+		if strings.TrimRight(fraction[scale:], "0") != "" {
+			return 0, errInvalidDecimal
+		}
+		fraction = fraction[:scale]
+	}
+	fraction += strings.Repeat("0", scale-len(fraction))
+
+	if whole == "" {
+		whole = "0"
+	}
+
+	for _, digits := range []string{whole, fraction} {
+		for _, r := range digits {
+			if r < '0' || r > '9' {
+				return 0, errInvalidDecimal
+			}
+		}
+	}
+
+	value, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, errDecimalOverflow
+	}
+
+	if negative {
+		value = -value
+	}
+
+	return value, nil
+}
+
+func formatDecimal(value int64, scale int) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+	}
+
+	unit := pow10(scale)
+	whole := value / unit
+	fraction := value % unit
+	if whole < 0 {
+		whole = -whole
+	}
+	if fraction < 0 {
+		fraction = -fraction
+	}
+
+	if scale == 0 {
+		return fmt.Sprintf("%s%d", sign, whole)
+	}
+
+	return fmt.Sprintf("%s%d.%0*d", sign, whole, scale, fraction)
+}
+
+// roundDiv divides value by divisor, rounding half away from zero.
+func roundDiv(value, divisor *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(value, divisor, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+
+	return quotient
+}
+
+func scanDecimal(v pgtype.Numeric, scale int) (int64, error) {
+	if !v.Valid {
+		return 0, nil
+	}
+	if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return 0, errInvalidDecimal
+	}
+
+	value := new(big.Int).Set(v.Int)
+	exp := int64(v.Exp) + int64(scale)
+
+	if exp >= 0 {
+		value.Mul(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+	} else {
+		// aggregates like AVG carry more decimals, round them
+		value = roundDiv(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(-exp), nil))
+	}
+
+	if !value.IsInt64() {
+		return 0, errDecimalOverflow
+	}
+
+	return value.Int64(), nil
+}
+
+func decimalNumeric(value int64, scale int) pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(value), Exp: int32(-scale), Valid: true}
+}
@@ -71,4 +71,10 @@ var ErrConfict = DefaultError{
 	CodeField:   http.StatusConflict,
 }
 
+var ErrUnprocessableEntity = DefaultError{
+	StatusField: http.StatusText(http.StatusUnprocessableEntity),
+	ErrorField:  "The request could not be processed with the given content",
+	CodeField:   http.StatusUnprocessableEntity,
+}
+
 var ErrRecordNotFound = errors.New("record not found")
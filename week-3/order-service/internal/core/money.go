@@ -0,0 +1,163 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// MoneyScale is the number of decimal places kept by Money, matching the numeric(14, 2) columns.
+const MoneyScale = 2
+
+var (
+	ErrInvalidMoney  = errors.New("money amount is not valid")
+	ErrMoneyOverflow = errors.New("money amount is out of range")
+)
+
+// Money is an exact amount held in minor units (cents), so sums and products never drift like floats do.
+// It is read from and written to Postgres numeric columns, and marshalled to JSON as a plain decimal number.
+type Money int64
+
+func NewMoney(minorUnits int64) Money {
+	return Money(minorUnits)
+}
+
+// ParseMoney parses a decimal string such as "12", "-3.5" or "0.25" without going through floats.
+// Amounts with more than MoneyScale decimal places are rejected rather than rounded.
+func ParseMoney(s string) (Money, error) {
+	value, err := parseDecimal(s, MoneyScale)
+	if err != nil {
+		if err == errDecimalOverflow {
+			return 0, ErrMoneyOverflow
+		}
+		return 0, ErrInvalidMoney
+	}
+
+	return Money(value), nil
+}
+
+func (m Money) Add(other Money) Money {
+	return m + other
+}
+
+func (m Money) Sub(other Money) Money {
+	return m - other
+}
+
+func (m Money) Mul(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// Percent returns percent % of the amount, rounded half away from zero to the cent.
+func (m Money) Percent(percent int) Money {
+	value := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(percent)))
+
+	return Money(roundDiv(value, big.NewInt(100)).Int64())
+}
+
+// Allocate splits the amount in proportion to the weights, rounding every share down and handing the
+// cents left over to the first weights that still have room. Shares always add up to the amount, and no
+// share exceeds its weight as long as the amount does not exceed the sum of the weights.
+func (m Money) Allocate(weights []Money) []Money {
+	shares := make([]Money, len(weights))
+
+	total := big.NewInt(0)
+	for _, weight := range weights {
+		total.Add(total, big.NewInt(int64(weight)))
+	}
+	if total.Sign() <= 0 || m <= 0 {
+		return shares
+	}
+
+	remaining := m
+	for idx, weight := range weights {
+		value := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(weight)))
+		shares[idx] = Money(value.Quo(value, total).Int64())
+		remaining -= shares[idx]
+	}
+
+	for idx, weight := range weights {
+		if remaining <= 0 {
+			break
+		}
+
+		room := min(weight-shares[idx], remaining)
+		if room > 0 {
+			shares[idx] += room
+			remaining -= room
+		}
+	}
+
+	return shares
+}
+
+func (m Money) IsNegative() bool {
+	return m < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m > 0
+}
+
+func (m Money) MinorUnits() int64 {
+	return int64(m)
+}
+
+// Float64 is only meant for presentation, e.g. spreadsheet cells. Never compute with it.
+func (m Money) Float64() float64 {
+	return float64(m) / float64(pow10(MoneyScale))
+}
+
+func (m Money) String() string {
+	return formatDecimal(int64(m), MoneyScale)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts both a JSON number and a quoted decimal string.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	return m.UnmarshalText(bytes.Trim(data, `"`))
+}
+
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalText(data []byte) error {
+	money, err := ParseMoney(string(data))
+	if err != nil {
+		return err
+	}
+
+	*m = money
+
+	return nil
+}
+
+// ScanNumeric lets pgx scan numeric columns straight into Money.
+func (m *Money) ScanNumeric(v pgtype.Numeric) error {
+	value, err := scanDecimal(v, MoneyScale)
+	if err != nil {
+		if err == errDecimalOverflow {
+			return ErrMoneyOverflow
+		}
+		return ErrInvalidMoney
+	}
+
+	*m = Money(value)
+
+	return nil
+}
+
+// NumericValue lets pgx write Money into numeric columns and parameters.
+func (m Money) NumericValue() (pgtype.Numeric, error) {
+	return decimalNumeric(int64(m), MoneyScale), nil
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RateScale is the number of decimal places kept by Rate, matching the numeric(18, 8) columns.
+const RateScale = 8
+
+var ErrInvalidRate = errors.New("exchange rate is not valid")
+
+// Rate is an exact exchange rate, the amount of a currency one unit of the base currency buys.
+type Rate int64
+
+// RateOne converts the base currency to itself.
+const RateOne = Rate(100000000)
+
+func ParseRate(s string) (Rate, error) {
+	value, err := parseDecimal(s, RateScale)
+	if err != nil {
+		return 0, ErrInvalidRate
+	}
+
+	return Rate(value), nil
+}
+
+func (r Rate) IsPositive() bool {
+	return r > 0
+}
+
+func (r Rate) IsNegative() bool {
+	return r < 0
+}
+
+func (r Rate) String() string {
+	return formatDecimal(int64(r), RateScale)
+}
+
+// Percent formats the rate as a percentage without trailing zeros, e.g. 0.075 becomes "7.5".
+// It is only meant for presentation.
+func (r Rate) Percent() string {
+	value := formatDecimal(int64(r), RateScale-2)
+	value = strings.TrimRight(value, "0")
+
+	return strings.TrimSuffix(value, ".")
+}
+
+// Convert applies the rate to an amount of the base currency, rounding half away from zero to the cent.
+func (m Money) Convert(rate Rate) Money {
+	value := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(rate)))
+
+	return Money(roundDiv(value, big.NewInt(pow10(RateScale))).Int64())
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accepts both a JSON number and a quoted decimal string.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	return r.UnmarshalText(bytes.Trim(data, `"`))
+}
+
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalText(data []byte) error {
+	rate, err := ParseRate(string(data))
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+
+	return nil
+}
+
+// ScanNumeric lets pgx scan numeric columns straight into Rate.
+func (r *Rate) ScanNumeric(v pgtype.Numeric) error {
+	value, err := scanDecimal(v, RateScale)
+	if err != nil {
+		return ErrInvalidRate
+	}
+
+	*r = Rate(value)
+
+	return nil
+}
+
+// NumericValue lets pgx write Rate into numeric columns and parameters.
+func (r Rate) NumericValue() (pgtype.Numeric, error) {
+	return decimalNumeric(int64(r), RateScale), nil
+}
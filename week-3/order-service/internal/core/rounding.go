@@ -0,0 +1,66 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+)
+
+var ErrInvalidRoundingMode = errors.New("rounding mode is not valid")
+
+// RoundingMode tells how an amount that falls between two cents is rounded.
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half_up"   // half away from zero, 0.125 -> 0.13
+	RoundHalfEven RoundingMode = "half_even" // bankers' rounding, 0.125 -> 0.12
+	RoundUp       RoundingMode = "up"        // away from zero
+	RoundDown     RoundingMode = "down"      // toward zero
+)
+
+func (mode RoundingMode) IsValid() bool {
+	switch mode {
+	case RoundHalfUp, RoundHalfEven, RoundUp, RoundDown:
+		return true
+	}
+
+	return false
+}
+
+// Tax returns the tax due on the amount at the given rate, e.g. 0.1 for 10%. An inclusive amount already
+// contains the tax, so the tax is taken out of it instead of being added on top.
+func (m Money) Tax(rate Rate, inclusive bool, mode RoundingMode) Money {
+	value := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(rate)))
+	divisor := big.NewInt(pow10(RateScale))
+	if inclusive {
+		divisor.Add(divisor, big.NewInt(int64(rate)))
+	}
+
+	return Money(roundDivMode(value, divisor, mode).Int64())
+}
+
+// roundDivMode divides value by a positive divisor with the given rounding mode, half up being the default.
+func roundDivMode(value, divisor *big.Int, mode RoundingMode) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(value, divisor, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	away := false
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundHalfEven:
+		cmp := new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(divisor)
+		away = cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1)
+	default:
+		return roundDiv(value, divisor)
+	}
+
+	if away {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+
+	return quotient
+}
@@ -1,9 +1,19 @@
 package core
 
+type Paging struct {
+	NextCursor string `json:"next_cursor"`
+	Limit      int    `json:"limit"`
+}
+
 type success struct {
-	Data interface{} `json:"data"`
+	Data   interface{} `json:"data"`
+	Paging *Paging     `json:"paging,omitempty"`
 }
 
 func ResponseData(data interface{}) *success {
 	return &success{Data: data}
 }
+
+func ResponseDataWithPaging(data interface{}, paging Paging) *success {
+	return &success{Data: data, Paging: &paging}
+}
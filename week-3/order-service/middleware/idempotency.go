@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"order_service/internal/core"
+	"order_service/pkg"
+	idempotencyEntity "order_service/services/idempotency/entity"
+	idempotencyUc "order_service/services/idempotency/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// RequireIdempotency replays the stored response of a request sent again with the same Idempotency-Key.
+// It must be registered after RequireAuth since keys are scoped per user. Requests without the header pass through.
+func RequireIdempotency(biz idempotencyUc.IdempotencyUsecase) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return c.Next()
+		}
+
+		requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+		if !ok {
+			return pkg.WriteResponse(c, core.ErrUnauthorized)
+		}
+
+		uid, err := core.DecomposeUID(requester.GetSubject())
+		if err != nil {
+			return pkg.WriteResponse(c, core.ErrInternalServerError.WithDebug(err.Error()))
+		}
+		userId := int(uid.GetLocalID())
+
+		// the request context is reused by fiber once the handler returns, so we do not bind to it
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		fingerprint := requestFingerprint(c)
+
+		record, err := biz.Begin(ctx, userId, key, fingerprint)
+		if err != nil {
+			return pkg.WriteResponse(c, err)
+		}
+
+		if record != nil {
+			c.Set("Idempotent-Replayed", "true")
+			c.Set(fiber.HeaderContentType, record.ContentType)
+
+			return c.Status(record.StatusCode).Send(record.Body)
+		}
+
+		err = c.Next()
+		statusCode := c.Response().StatusCode()
+
+		// let the client retry when no final response has been produced
+		if err != nil || statusCode >= fiber.StatusInternalServerError {
+			if abortErr := biz.Abort(ctx, userId, key); abortErr != nil {
+				return pkg.WriteResponse(c, abortErr)
+			}
+
+			return err
+		}
+
+		newRecord := idempotencyEntity.NewRecord(fingerprint)
+		newRecord.Complete(statusCode, string(c.Response().Header.ContentType()), append([]byte(nil), c.Response().Body()...))
+
+		// the handler has already succeeded, failing to store its response must not turn it into an error
+		if err := biz.Complete(ctx, userId, key, newRecord); err != nil {
+			log.Println("idempotency complete", err)
+		}
+
+		return nil
+	}
+}
+
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{'\n'})
+	hash.Write([]byte(c.OriginalURL()))
+	hash.Write([]byte{'\n'})
+	hash.Write(c.Body())
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
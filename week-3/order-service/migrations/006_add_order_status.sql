@@ -0,0 +1,16 @@
+DO $$ BEGIN CREATE TYPE order_status AS ENUM ('pending', 'paid', 'fulfilling', 'shipped', 'delivered', 'cancelled'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+
+ALTER TABLE IF EXISTS orders ADD COLUMN IF NOT EXISTS status order_status NOT NULL DEFAULT 'pending';
+
+CREATE TABLE IF NOT EXISTS order_status_histories (
+  id            serial,
+  order_id      int           NOT NULL,
+  from_status   order_status,
+  to_status     order_status  NOT NULL,
+  actor_id      int           NOT NULL,
+  created_at    timestamp     DEFAULT NOW(),
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS order_status_histories_order_id_idx ON order_status_histories(order_id);
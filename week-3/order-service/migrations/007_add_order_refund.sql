@@ -0,0 +1 @@
+ALTER TABLE IF EXISTS orders ADD COLUMN IF NOT EXISTS refunded_at timestamp;
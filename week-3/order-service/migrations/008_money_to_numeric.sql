@@ -0,0 +1,7 @@
+ALTER TABLE IF EXISTS users ALTER COLUMN balance TYPE numeric(14, 2) USING ROUND(balance::numeric, 2);
+ALTER TABLE IF EXISTS users ALTER COLUMN balance SET DEFAULT 0;
+ALTER TABLE IF EXISTS products ALTER COLUMN price TYPE numeric(14, 2) USING ROUND(price::numeric, 2);
+ALTER TABLE IF EXISTS orders ALTER COLUMN total_price TYPE numeric(14, 2) USING ROUND(total_price::numeric, 2);
+ALTER TABLE IF EXISTS orders ALTER COLUMN total_price SET DEFAULT 0;
+ALTER TABLE IF EXISTS order_items ALTER COLUMN product_price TYPE numeric(14, 2) USING ROUND(product_price::numeric, 2);
+ALTER TABLE IF EXISTS order_items ALTER COLUMN quantity TYPE int USING ROUND(quantity)::int;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS exchange_rates (
+  currency    text            NOT NULL,
+  rate        numeric(18, 8)  NOT NULL CHECK (rate > 0),
+  updated_at  timestamp       DEFAULT NOW(),
+
+  PRIMARY KEY (currency)
+);
+
+ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS currency text;
+
+-- orders placed before multi-currency were charged in the base currency (CURRENCY_BASE, USD by default)
+ALTER TABLE IF EXISTS orders ADD COLUMN IF NOT EXISTS currency text NOT NULL DEFAULT 'USD';
+ALTER TABLE IF EXISTS orders ADD COLUMN IF NOT EXISTS exchange_rate numeric(18, 8) NOT NULL DEFAULT 1;
+ALTER TABLE IF EXISTS orders ADD COLUMN IF NOT EXISTS base_total_price numeric(14, 2);
+UPDATE orders SET base_total_price = total_price WHERE base_total_price IS NULL;
+ALTER TABLE IF EXISTS orders ALTER COLUMN base_total_price SET DEFAULT 0;
+
+ALTER TABLE IF EXISTS order_items ADD COLUMN IF NOT EXISTS currency text NOT NULL DEFAULT 'USD';
+ALTER TABLE IF EXISTS order_items ADD COLUMN IF NOT EXISTS exchange_rate numeric(18, 8) NOT NULL DEFAULT 1;
@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS coupons (
+  id              serial,
+  code            text            NOT NULL UNIQUE,
+  type            text            NOT NULL,
+  percent_off     int             NOT NULL DEFAULT 0,
+  amount_off      numeric(14, 2)  NOT NULL DEFAULT 0,
+  product_id      int,
+  buy_quantity    int             NOT NULL DEFAULT 0,
+  get_quantity    int             NOT NULL DEFAULT 0,
+  min_spend       numeric(14, 2)  NOT NULL DEFAULT 0,
+  usage_limit     int             NOT NULL DEFAULT 0,
+  per_user_limit  int             NOT NULL DEFAULT 0,
+  redeemed_count  int             NOT NULL DEFAULT 0,
+  active          boolean         NOT NULL DEFAULT true,
+  starts_at       timestamp,
+  expires_at      timestamp,
+  created_at      timestamp       DEFAULT NOW(),
+  updated_at      timestamp,
+
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+  id          serial,
+  coupon_id   int        NOT NULL,
+  user_id     int        NOT NULL,
+  order_id    int        NOT NULL,
+  created_at  timestamp  DEFAULT NOW(),
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS coupon_redemptions_user_id_coupon_id_idx ON coupon_redemptions(user_id, coupon_id);
+CREATE INDEX IF NOT EXISTS coupon_redemptions_order_id_idx ON coupon_redemptions(order_id);
+
+CREATE TABLE IF NOT EXISTS order_discounts (
+  order_id     int             NOT NULL,
+  coupon_id    int             NOT NULL,
+  code         text            NOT NULL,
+  description  text            NOT NULL,
+  amount       numeric(14, 2)  NOT NULL,
+  base_amount  numeric(14, 2)  NOT NULL,
+
+  PRIMARY KEY (order_id, coupon_id)
+);
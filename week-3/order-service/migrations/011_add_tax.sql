@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS tax_rules (
+  id          serial,
+  region      text            NOT NULL,
+  tax_class   text            NOT NULL DEFAULT 'standard',
+  name        text            NOT NULL,
+  rate        numeric(18, 8)  NOT NULL CHECK (rate >= 0),
+  inclusive   boolean         NOT NULL DEFAULT false,
+  rounding    text            NOT NULL DEFAULT 'half_up',
+  created_at  timestamp       DEFAULT NOW(),
+  updated_at  timestamp,
+
+  PRIMARY KEY (id),
+  UNIQUE (region, tax_class)
+);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_class text NOT NULL DEFAULT 'standard';
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS region text NOT NULL DEFAULT '';
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_class text NOT NULL DEFAULT 'standard';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax numeric(14, 2) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_rate numeric(18, 8) NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_inclusive boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS order_taxes (
+  id                   serial,
+  order_id             int             NOT NULL,
+  name                 text            NOT NULL,
+  rate                 numeric(18, 8)  NOT NULL,
+  inclusive            boolean         NOT NULL,
+  taxable_amount       numeric(14, 2)  NOT NULL,
+  amount               numeric(14, 2)  NOT NULL,
+  base_taxable_amount  numeric(14, 2)  NOT NULL,
+  base_amount          numeric(14, 2)  NOT NULL,
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS order_taxes_order_id_idx ON order_taxes(order_id);
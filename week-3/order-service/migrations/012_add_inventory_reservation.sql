@@ -0,0 +1,19 @@
+ALTER TABLE IF EXISTS products ADD COLUMN IF NOT EXISTS reserved_quantity int NOT NULL DEFAULT 0;
+
+DO $$ BEGIN ALTER TABLE products ADD CONSTRAINT products_reserved_quantity_check CHECK (reserved_quantity >= 0); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+
+CREATE TABLE IF NOT EXISTS inventory_reservations (
+  id          serial,
+  order_id    int        NOT NULL,
+  product_id  int        NOT NULL,
+  quantity    int        NOT NULL,
+  status      text       NOT NULL DEFAULT 'active',
+  expires_at  timestamp  NOT NULL,
+  created_at  timestamp  DEFAULT NOW(),
+  updated_at  timestamp,
+
+  PRIMARY KEY (id),
+  UNIQUE (order_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS inventory_reservations_status_expires_at_idx ON inventory_reservations(status, expires_at);
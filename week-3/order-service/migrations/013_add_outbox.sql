@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS outbox (
+  id              bigserial,
+  aggregate_type  text       NOT NULL,
+  aggregate_id    int        NOT NULL,
+  event_type      text       NOT NULL,
+  payload         jsonb      NOT NULL,
+  attempts        int        NOT NULL DEFAULT 0,
+  last_error      text,
+  available_at    timestamp  NOT NULL DEFAULT NOW(),
+  published_at    timestamp,
+  created_at      timestamp  DEFAULT NOW(),
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS outbox_available_at_idx ON outbox(available_at) WHERE published_at IS NULL;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS report_jobs (
+  id            serial,
+  user_id       int        NOT NULL,
+  type          text       NOT NULL,
+  status        text       NOT NULL DEFAULT 'queued',
+  params        jsonb      NOT NULL DEFAULT '{}',
+  attempts      int        NOT NULL DEFAULT 0,
+  max_attempts  int        NOT NULL DEFAULT 1,
+  last_error    text,
+  file_name     text,
+  available_at  timestamp  NOT NULL DEFAULT NOW(),
+  started_at    timestamp,
+  finished_at   timestamp,
+  created_at    timestamp  DEFAULT NOW(),
+  updated_at    timestamp,
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS report_jobs_status_available_at_idx ON report_jobs(status, available_at);
+CREATE INDEX IF NOT EXISTS report_jobs_user_id_idx ON report_jobs(user_id);
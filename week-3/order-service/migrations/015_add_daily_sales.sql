@@ -0,0 +1,27 @@
+-- rollups of the orders placed every day (UTC), kept up to date by the order flows and rebuilt with cmd/backfill
+CREATE TABLE IF NOT EXISTS daily_user_sales (
+  day            date            NOT NULL,
+  user_id        int             NOT NULL,
+  num_of_orders  int             NOT NULL DEFAULT 0,
+  revenue        numeric(14, 2)  NOT NULL DEFAULT 0,
+  item_price     numeric(14, 2)  NOT NULL DEFAULT 0,
+  num_of_items   int             NOT NULL DEFAULT 0,
+  units_sold     int             NOT NULL DEFAULT 0,
+  tax            numeric(14, 2)  NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (day, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS daily_user_sales_user_id_day_idx ON daily_user_sales(user_id, day);
+
+CREATE TABLE IF NOT EXISTS daily_product_sales (
+  day            date            NOT NULL,
+  product_id     int             NOT NULL,
+  num_of_orders  int             NOT NULL DEFAULT 0,
+  units_sold     int             NOT NULL DEFAULT 0,
+  revenue        numeric(14, 2)  NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (day, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS daily_product_sales_product_id_day_idx ON daily_product_sales(product_id, day);
@@ -0,0 +1,22 @@
+-- schedules queue an orders summary job when next_run_at is due, next_run_at is UTC
+CREATE TABLE IF NOT EXISTS report_schedules (
+  id            serial,
+  user_id       int           NOT NULL,
+  name          varchar(100)  NOT NULL DEFAULT '',
+  cron          varchar(100)  NOT NULL,
+  timezone      varchar(64)   NOT NULL DEFAULT 'UTC',
+  format        varchar(10)   NOT NULL DEFAULT 'xlsx',
+  range_days    int           NOT NULL DEFAULT 7,
+  recipients    text[]        NOT NULL DEFAULT '{}',
+  active        boolean       NOT NULL DEFAULT TRUE,
+  next_run_at   timestamp     NOT NULL,
+  last_run_at   timestamp,
+  last_job_id   int,
+  created_at    timestamp     DEFAULT NOW(),
+  updated_at    timestamp,
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS report_schedules_next_run_at_idx ON report_schedules(next_run_at) WHERE active;
+CREATE INDEX IF NOT EXISTS report_schedules_user_id_idx ON report_schedules(user_id);
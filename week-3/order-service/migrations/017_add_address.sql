@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS user_addresses (
+  id           serial,
+  user_id      int           NOT NULL,
+  label        varchar(50)   NOT NULL DEFAULT '',
+  recipient    varchar(100)  NOT NULL,
+  phone        varchar(30)   NOT NULL DEFAULT '',
+  line1        varchar(200)  NOT NULL,
+  line2        varchar(200)  NOT NULL DEFAULT '',
+  city         varchar(100)  NOT NULL,
+  state        varchar(100)  NOT NULL DEFAULT '',
+  postal_code  varchar(20)   NOT NULL DEFAULT '',
+  country      char(2)       NOT NULL,
+  is_default   boolean       NOT NULL DEFAULT false,
+  created_at   timestamp     DEFAULT NOW(),
+  updated_at   timestamp,
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS user_addresses_user_id_idx ON user_addresses(user_id);
+-- a user has at most one default address
+CREATE UNIQUE INDEX IF NOT EXISTS user_addresses_default_idx ON user_addresses(user_id) WHERE is_default;
+
+-- the address an order is shipped to is copied, editing or removing the saved one leaves the order as it was
+CREATE TABLE IF NOT EXISTS order_shipping_addresses (
+  order_id     int           NOT NULL,
+  address_id   int,
+  recipient    varchar(100)  NOT NULL,
+  phone        varchar(30)   NOT NULL DEFAULT '',
+  line1        varchar(200)  NOT NULL,
+  line2        varchar(200)  NOT NULL DEFAULT '',
+  city         varchar(100)  NOT NULL,
+  state        varchar(100)  NOT NULL DEFAULT '',
+  postal_code  varchar(20)   NOT NULL DEFAULT '',
+  country      char(2)       NOT NULL,
+
+  PRIMARY KEY (order_id)
+);
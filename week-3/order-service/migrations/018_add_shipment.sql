@@ -0,0 +1,36 @@
+DO $$ BEGIN CREATE TYPE shipment_status AS ENUM ('label_created', 'in_transit', 'out_for_delivery', 'delivered', 'exception', 'returned'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+
+CREATE TABLE IF NOT EXISTS shipments (
+  id               serial,
+  order_id         int              NOT NULL,
+  user_id          int              NOT NULL,
+  carrier          varchar(30)      NOT NULL,
+  tracking_number  varchar(40)      NOT NULL,
+  status           shipment_status  NOT NULL DEFAULT 'label_created',
+  last_event_at    timestamp,
+  created_at       timestamp        DEFAULT NOW(),
+  updated_at       timestamp,
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS shipments_order_id_idx ON shipments(order_id);
+-- carrier callbacks find their shipment by tracking number
+CREATE UNIQUE INDEX IF NOT EXISTS shipments_tracking_number_idx ON shipments(carrier, tracking_number);
+
+CREATE TABLE IF NOT EXISTS shipment_events (
+  id           serial,
+  shipment_id  int              NOT NULL,
+  event_id     varchar(100),
+  status       shipment_status  NOT NULL,
+  location     varchar(200)     NOT NULL DEFAULT '',
+  description  varchar(500)     NOT NULL DEFAULT '',
+  occurred_at  timestamp        NOT NULL,
+  created_at   timestamp        DEFAULT NOW(),
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS shipment_events_shipment_id_idx ON shipment_events(shipment_id, occurred_at);
+-- a carrier retrying a callback it already sent does not add it to the timeline twice
+CREATE UNIQUE INDEX IF NOT EXISTS shipment_events_event_id_idx ON shipment_events(shipment_id, event_id);
@@ -0,0 +1,20 @@
+DO $$ BEGIN CREATE TYPE payment_status AS ENUM ('pending', 'authorized', 'captured', 'failed', 'voided', 'refunded'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+
+CREATE TABLE IF NOT EXISTS payments (
+  id              serial,
+  order_id        int             NOT NULL,
+  user_id         int             NOT NULL,
+  provider        varchar(30)     NOT NULL,
+  reference       varchar(100)    NOT NULL DEFAULT '',
+  status          payment_status  NOT NULL DEFAULT 'pending',
+  amount          numeric(14, 2)  NOT NULL,
+  failure_reason  varchar(200)    NOT NULL DEFAULT '',
+  created_at      timestamp       DEFAULT NOW(),
+  updated_at      timestamp,
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS payments_order_id_idx ON payments(order_id, created_at);
+-- provider callbacks find their payment by the provider's reference, attempts without one yet are left out
+CREATE UNIQUE INDEX IF NOT EXISTS payments_reference_idx ON payments(provider, reference) WHERE reference <> '';
@@ -0,0 +1,35 @@
+DO $$ BEGIN CREATE TYPE ledger_kind AS ENUM ('top_up', 'order_charge', 'refund', 'adjustment'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+DO $$ BEGIN CREATE TYPE ledger_account AS ENUM ('wallet', 'external', 'orders'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+
+-- append-only double-entry ledger, the entries of a transaction sum up to zero and users.balance caches
+-- the sum of the wallet entries of the user
+CREATE TABLE IF NOT EXISTS ledger_entries (
+  id              bigserial,
+  transaction_id  uuid            NOT NULL,
+  account         ledger_account  NOT NULL,
+  user_id         int             NOT NULL,
+  kind            ledger_kind     NOT NULL,
+  amount          numeric(14, 2)  NOT NULL,
+  balance_after   numeric(14, 2),
+  reference       varchar(100)    NOT NULL DEFAULT '',
+  created_at      timestamp       DEFAULT NOW(),
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS ledger_entries_wallet_idx ON ledger_entries(user_id, id) WHERE account = 'wallet';
+CREATE INDEX IF NOT EXISTS ledger_entries_transaction_id_idx ON ledger_entries(transaction_id);
+-- a payment is charged and refunded once, entries without a reference (top-ups) are left out
+CREATE UNIQUE INDEX IF NOT EXISTS ledger_entries_reference_idx ON ledger_entries(kind, reference, account) WHERE reference <> '';
+
+UPDATE users SET balance = 0 WHERE balance IS NULL;
+
+-- balances kept before the ledger are opened with an adjustment, once per user
+WITH opening AS (
+  SELECT id, balance, gen_random_uuid() AS transaction_id FROM users
+  WHERE balance <> 0 AND NOT EXISTS (SELECT 1 FROM ledger_entries WHERE ledger_entries.user_id = users.id)
+)
+INSERT INTO ledger_entries (transaction_id, account, user_id, kind, amount, balance_after, reference)
+SELECT transaction_id, 'wallet', id, 'adjustment', balance, balance, 'opening_' || id FROM opening
+UNION ALL
+SELECT transaction_id, 'external', id, 'adjustment', -balance, NULL, 'opening_' || id FROM opening;
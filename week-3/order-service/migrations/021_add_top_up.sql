@@ -0,0 +1,23 @@
+-- every ledger transaction is attributed to whoever made it, admin adjustments always carry a reason code
+ALTER TABLE IF EXISTS ledger_entries ADD COLUMN IF NOT EXISTS actor_id int;
+ALTER TABLE IF EXISTS ledger_entries ADD COLUMN IF NOT EXISTS reason_code varchar(30) NOT NULL DEFAULT '';
+ALTER TABLE IF EXISTS ledger_entries ADD COLUMN IF NOT EXISTS note varchar(200) NOT NULL DEFAULT '';
+
+DO $$ BEGIN CREATE TYPE top_up_status AS ENUM ('pending', 'approved', 'rejected'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+
+CREATE TABLE IF NOT EXISTS top_ups (
+  id           serial,
+  user_id      int             NOT NULL,
+  amount       numeric(14, 2)  NOT NULL,
+  status       top_up_status   NOT NULL DEFAULT 'pending',
+  reviewer_id  int,
+  review_note  varchar(200)    NOT NULL DEFAULT '',
+  created_at   timestamp       DEFAULT NOW(),
+  reviewed_at  timestamp,
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS top_ups_user_id_created_at_idx ON top_ups(user_id, created_at);
+-- admin go through the top-ups waiting for approval
+CREATE INDEX IF NOT EXISTS top_ups_pending_idx ON top_ups(id) WHERE status = 'pending';
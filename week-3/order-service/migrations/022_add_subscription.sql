@@ -0,0 +1,26 @@
+-- subscriptions place an order of their items when next_run_at is due, next_run_at is UTC
+DO $$ BEGIN CREATE TYPE subscription_status AS ENUM ('active', 'paused'); EXCEPTION WHEN duplicate_object THEN NULL; END $$;
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+  id             serial,
+  user_id        int                  NOT NULL,
+  name           varchar(100)         NOT NULL DEFAULT '',
+  frequency      varchar(10)          NOT NULL,
+  cron           varchar(100)         NOT NULL,
+  timezone       varchar(64)          NOT NULL DEFAULT 'UTC',
+  items          jsonb                NOT NULL,
+  address_id     int                  NOT NULL DEFAULT 0,
+  status         subscription_status  NOT NULL DEFAULT 'active',
+  next_run_at    timestamp            NOT NULL,
+  attempts       int                  NOT NULL DEFAULT 0,
+  last_error     text                 NOT NULL DEFAULT '',
+  last_run_at    timestamp,
+  last_order_id  int,
+  created_at     timestamp            DEFAULT NOW(),
+  updated_at     timestamp,
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS subscriptions_next_run_at_idx ON subscriptions(next_run_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS subscriptions_user_id_idx ON subscriptions(user_id);
@@ -0,0 +1,2 @@
+-- refunded_at is set once the payments of a cancelled order are given back, until then they are retried
+CREATE INDEX IF NOT EXISTS orders_refund_pending_idx ON orders(id) WHERE status = 'cancelled' AND refunded_at IS NULL;
@@ -0,0 +1,12 @@
+-- rollup of the taxes collected every day (UTC) at every rate, in the base currency, kept like the daily sales;
+-- created_at holds the server's local time, so run cmd/backfill once to move orders to their UTC day
+CREATE TABLE IF NOT EXISTS daily_tax_sales (
+  day             date            NOT NULL,
+  name            text            NOT NULL,
+  rate            numeric(18, 8)  NOT NULL,
+  inclusive       boolean         NOT NULL,
+  taxable_amount  numeric(14, 2)  NOT NULL DEFAULT 0,
+  amount          numeric(14, 2)  NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (day, name, rate, inclusive)
+);
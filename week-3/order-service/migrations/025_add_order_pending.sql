@@ -0,0 +1,2 @@
+-- pending orders whose reservation expired are looked up and cancelled by the reservation sweeper
+CREATE INDEX IF NOT EXISTS orders_pending_idx ON orders(id) WHERE status = 'pending';
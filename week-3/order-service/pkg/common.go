@@ -3,6 +3,7 @@ package pkg
 import (
 	"bytes"
 	"context"
+	"encoding"
 	"errors"
 	"fmt"
 	"io"
@@ -82,6 +83,15 @@ func MultipartParser(m *multipart.Form, result interface{}) error {
 			continue
 		}
 
+		// types such as core.Money know how to parse themselves
+		if unmarshaler, ok := r.Field(i).Addr().Interface().(encoding.TextUnmarshaler); ok {
+			err := unmarshaler.UnmarshalText([]byte(formValue[0]))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		switch field.Type.Kind() {
 		case reflect.String:
 			r.Field(i).SetString(formValue[0])
@@ -0,0 +1,207 @@
+package pkg
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCron = errors.New("cron must have 5 fields: minute hour day-of-month month day-of-week, or be @hourly, @daily, @weekly, @monthly or @yearly")
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	cronMonthNames = []string{"", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	cronDayNames   = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+// cronField is the range a field can take, names are matched to their index.
+type cronField struct {
+	min, max int
+	names    []string
+}
+
+var (
+	cronMinute     = cronField{0, 59, nil}
+	cronHour       = cronField{0, 23, nil}
+	cronDayOfMonth = cronField{1, 31, nil}
+	cronMonth      = cronField{1, 12, cronMonthNames}
+	cronDayOfWeek  = cronField{0, 7, cronDayNames} // 7 is Sunday as well
+)
+
+// CronSchedule is a parsed cron expression, every field is a bit set of the values it matches.
+type CronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// a day matches if either day field does, unless one of them is * and then only the other counts
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+// ParseCron reads a standard 5 field cron expression. Fields take *, values, ranges, lists and steps like
+// "*/15 9-17 * * MON-FRI", months and days of week can be named.
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, ErrInvalidCron
+	}
+
+	var schedule CronSchedule
+	var err error
+
+	for i, parse := range []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{cronMinute, &schedule.minute},
+		{cronHour, &schedule.hour},
+		{cronDayOfMonth, &schedule.dayOfMonth},
+		{cronMonth, &schedule.month},
+		{cronDayOfWeek, &schedule.dayOfWeek},
+	} {
+		*parse.bits, err = parseCronField(fields[i], parse.field)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Sunday can be written 0 or 7, it is matched as 0
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+	schedule.anyDayOfMonth = strings.HasPrefix(fields[2], "*") || fields[2] == "?"
+	schedule.anyDayOfWeek = strings.HasPrefix(fields[4], "*") || fields[4] == "?"
+
+	return &schedule, nil
+}
+
+func parseCronField(expr string, field cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(expr, ",") {
+		start, end, step := field.min, field.max, 1
+
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			value, err := strconv.Atoi(stepExpr)
+			if err != nil || value <= 0 {
+				return 0, ErrInvalidCron
+			}
+			step = value
+		}
+
+		if rangeExpr != "*" && rangeExpr != "?" {
+			first, last, isRange := strings.Cut(rangeExpr, "-")
+
+			var err error
+			start, err = parseCronValue(first, field)
+			if err != nil {
+				return 0, err
+			}
+
+			end = start
+			if isRange {
+				end, err = parseCronValue(last, field)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// 5/15 means from 5 to the end of the field every 15
+				end = field.max
+			}
+
+			if end < start {
+				return 0, ErrInvalidCron
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << value
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(expr string, field cronField) (int, error) {
+	for i, name := range field.names {
+		if name != "" && strings.EqualFold(expr, name) {
+			return i, nil
+		}
+	}
+
+	value, err := strconv.Atoi(expr)
+	if err != nil || value < field.min || value > field.max {
+		return 0, ErrInvalidCron
+	}
+
+	return value, nil
+}
+
+// Next is the first minute strictly after t the schedule matches, in the location of t. It is the zero time
+// when nothing matches within five years, like the 30th of February.
+func (schedule *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+	// move the biggest field that does not match to its next value, a field wrapping around starts over
+	// from the month as the fields above it changed
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for schedule.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !schedule.matchesDay(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for schedule.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for schedule.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
+
+func (schedule *CronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := schedule.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := schedule.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if schedule.anyDayOfMonth || schedule.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
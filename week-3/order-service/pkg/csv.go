@@ -0,0 +1,47 @@
+package pkg
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type csvExporter struct{}
+
+func (exporter *csvExporter) Format() ReportFormat {
+	return ReportFormatCSV
+}
+
+// Export writes every table with its header row, tables are separated by an empty line.
+func (exporter *csvExporter) Export(w io.Writer, report *SummarizeReport) error {
+	writer := csv.NewWriter(w)
+
+	for i, table := range report.Tables() {
+		if i > 0 {
+			err := writer.Write(nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := writer.Write(table.Headers)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range table.Rows {
+			record := make([]string, 0, len(row))
+			for _, value := range row {
+				record = append(record, formatCell(value))
+			}
+
+			err = writer.Write(record)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
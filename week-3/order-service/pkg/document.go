@@ -0,0 +1,70 @@
+package pkg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DocumentDir is where generated invoices and reports are written, relative to the working directory.
+// It is not served as is, documents only go out through SendDocument after the caller checked access.
+const DocumentDir = "storage"
+
+var (
+	ErrInvalidDocumentName = errors.New("document name is not valid")
+	ErrDocumentNotFound    = errors.New("document cannot be found")
+)
+
+// DocumentPath is the path of the generated document name, a name reaching outside DocumentDir is refused.
+func DocumentPath(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", ErrInvalidDocumentName
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(pwd, DocumentDir, name), nil
+}
+
+// SendDocument streams the generated document name as an attachment called downloadName. With remove the
+// file is deleted as soon as it is open, it is gone from disk once the response is sent.
+func SendDocument(c *fiber.Ctx, name, downloadName string, remove bool) error {
+	path, err := DocumentPath(name)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrDocumentNotFound
+		}
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	if remove {
+		err = os.Remove(path)
+		if err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	c.Attachment(downloadName)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	// the response closes the file once it is sent
+	return c.Status(fiber.StatusOK).SendStream(file, int(info.Size()))
+}
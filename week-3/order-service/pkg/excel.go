@@ -2,68 +2,111 @@ package pkg
 
 import (
 	"fmt"
-	"order_service/services/order/entity"
-	"os"
-	"time"
+	"io"
+	"order_service/internal/core"
+	"strconv"
 	"unicode/utf8"
 
-	"github.com/google/uuid"
 	"github.com/xuri/excelize/v2"
 )
 
-func GenerateExcel(datas *[]entity.OrdersSummarize, startDate, endDate time.Time) (string, error) {
-	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
-	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, endDate.Location())
+type xlsxExporter struct{}
 
+func (exporter *xlsxExporter) Format() ReportFormat {
+	return ReportFormatXLSX
+}
+
+// Export writes one sheet per table, the summarize sheet starts with the date range of the report.
+func (exporter *xlsxExporter) Export(w io.Writer, report *SummarizeReport) error {
 	f := excelize.NewFile()
 	defer f.Close()
 
-	sheetName := "summarize"
-	index, err := f.NewSheet(sheetName)
-	if err != nil {
-		return "", err
-	}
-	f.DeleteSheet("Sheet1")
+	tables := report.Tables()
+	activeIndex := 0
 
-	err = f.MergeCell(sheetName, "A1", "E1")
-	if err != nil {
-		return "", err
-	}
+	for i, table := range tables {
+		index, err := f.NewSheet(table.Name)
+		if err != nil {
+			return err
+		}
 
-	f.SetRowHeight(sheetName, 1, 25)
-	f.SetCellValue(sheetName, "A1", fmt.Sprintf("Start date: %s", startDate))
+		headerRow := 1
+		if i == 0 {
+			activeIndex = index
+			lastCol, err := excelize.ColumnNumberToName(max(len(table.Headers), 1))
+			if err != nil {
+				return err
+			}
 
-	err = f.MergeCell(sheetName, "A2", "E2")
-	if err != nil {
-		return "", err
-	}
+			err = f.MergeCell(table.Name, "A1", fmt.Sprintf("%s1", lastCol))
+			if err != nil {
+				return err
+			}
+
+			f.SetRowHeight(table.Name, 1, 25)
+			f.SetCellValue(table.Name, "A1", fmt.Sprintf("Start date: %s", report.StartDate))
+
+			err = f.MergeCell(table.Name, "A2", fmt.Sprintf("%s2", lastCol))
+			if err != nil {
+				return err
+			}
+
+			f.SetRowHeight(table.Name, 2, 20)
+			f.SetCellValue(table.Name, "A2", fmt.Sprintf("End date: %s", report.EndDate))
+
+			headerRow = 4
+		}
 
-	f.SetRowHeight(sheetName, 2, 20)
-	f.SetCellValue(sheetName, "A2", fmt.Sprintf("End date: %s", endDate))
+		err = f.SetSheetRow(table.Name, fmt.Sprintf("A%d", headerRow), &table.Headers)
+		if err != nil {
+			return err
+		}
 
-	cells := []string{"A", "B", "C", "D", "E"}
-	cellsValue := []string{"User ID", "Username", "Num of Orders", "Average Price", "Average Order Quantity"}
+		for rowIdx, row := range table.Rows {
+			for colIdx, value := range row {
+				cell, err := excelize.CoordinatesToCellName(colIdx+1, headerRow+rowIdx+1)
+				if err != nil {
+					return err
+				}
+
+				err = setCell(f, table.Name, cell, value)
+				if err != nil {
+					return err
+				}
+			}
+		}
 
-	cellHeaderIdx := 4
-	for i := 1; i <= len(cells); i++ {
-		f.SetCellValue(sheetName, fmt.Sprintf("%s%d", cells[i-1], cellHeaderIdx), cellsValue[i-1])
+		err = fitColumns(f, table.Name)
+		if err != nil {
+			return err
+		}
 	}
 
-	startRow := 5
+	f.DeleteSheet("Sheet1")
+	f.SetActiveSheet(activeIndex)
 
-	for _, data := range *datas {
-		f.SetCellValue(sheetName, fmt.Sprintf("A%d", startRow), data.UserId)
-		f.SetCellValue(sheetName, fmt.Sprintf("B%d", startRow), data.Username)
-		f.SetCellValue(sheetName, fmt.Sprintf("C%d", startRow), data.NumOfOrders)
-		f.SetCellValue(sheetName, fmt.Sprintf("D%d", startRow), data.SumOrderPrice)
-		f.SetCellValue(sheetName, fmt.Sprintf("E%d", startRow), data.AverageOrderItemQuantity)
+	return f.Write(w)
+}
 
-		startRow++
+func setCell(f *excelize.File, sheetName, cell string, value any) error {
+	switch v := value.(type) {
+	case core.Money:
+		return f.SetCellFloat(sheetName, cell, v.Float64(), core.MoneyScale, 64)
+	case core.Rate:
+		rate, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return err
+		}
+		return f.SetCellFloat(sheetName, cell, rate, -1, 64)
 	}
 
+	return f.SetCellValue(sheetName, cell, value)
+}
+
+func fitColumns(f *excelize.File, sheetName string) error {
 	cols, err := f.GetCols(sheetName)
 	if err != nil {
-		return "", err
+		return err
 	}
 	for idx, col := range cols {
 		largestWidth := 0
@@ -75,23 +118,10 @@ func GenerateExcel(datas *[]entity.OrdersSummarize, startDate, endDate time.Time
 		}
 		name, err := excelize.ColumnNumberToName(idx + 1)
 		if err != nil {
-			return "", err
+			return err
 		}
 		f.SetColWidth(sheetName, name, name, float64(largestWidth))
 	}
 
-	f.SetActiveSheet(index)
-
-	pwd, err := os.Getwd()
-	if err != nil {
-		return "", err
-	}
-
-	excelName := fmt.Sprintf("summarize-%s.xlsx", uuid.New().String())
-	err = f.SaveAs(fmt.Sprintf("%s/storage/%s", pwd, excelName))
-	if err != nil {
-		return "", err
-	}
-
-	return excelName, nil
+	return nil
 }
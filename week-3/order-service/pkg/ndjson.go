@@ -0,0 +1,49 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+type ndjsonExporter struct{}
+
+func (exporter *ndjsonExporter) Format() ReportFormat {
+	return ReportFormatNDJSON
+}
+
+// Export writes one JSON object per row, keyed by column with the table it belongs to under "table".
+// Keys keep the column order so every line reads the same way.
+func (exporter *ndjsonExporter) Export(w io.Writer, report *SummarizeReport) error {
+	var line bytes.Buffer
+
+	for _, table := range report.Tables() {
+		for _, row := range table.Rows {
+			line.Reset()
+			line.WriteString(`{"table":`)
+			line.WriteString(strconv.Quote(table.Name))
+
+			for i, value := range row {
+				content, err := json.Marshal(value)
+				if err != nil {
+					return err
+				}
+
+				line.WriteString(",")
+				line.WriteString(strconv.Quote(table.Keys[i]))
+				line.WriteString(":")
+				line.Write(content)
+			}
+
+			line.WriteString("}\n")
+
+			_, err := w.Write(line.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+const (
+	odsManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.2">
+ <manifest:file-entry manifest:full-path="/" manifest:version="1.2" manifest:media-type="application/vnd.oasis.opendocument.spreadsheet"/>
+ <manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>
+</manifest:manifest>
+`
+	odsContentHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" office:version="1.2"><office:body><office:spreadsheet>`
+	odsContentFooter = `</office:spreadsheet></office:body></office:document-content>`
+)
+
+type odsExporter struct{}
+
+func (exporter *odsExporter) Format() ReportFormat {
+	return ReportFormatODS
+}
+
+// Export writes an OpenDocument spreadsheet laid out like the xlsx one, one table per sheet.
+func (exporter *odsExporter) Export(w io.Writer, report *SummarizeReport) error {
+	archive := zip.NewWriter(w)
+
+	// the mimetype has to be the first entry and cannot be compressed
+	mimetype, err := archive.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(mimetype, ReportFormatODS.ContentType())
+	if err != nil {
+		return err
+	}
+
+	manifest, err := archive.Create("META-INF/manifest.xml")
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(manifest, odsManifest)
+	if err != nil {
+		return err
+	}
+
+	content, err := archive.Create("content.xml")
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(odsContentHeader)
+
+	for i, table := range report.Tables() {
+		buf.WriteString(`<table:table table:name="`)
+		xml.EscapeText(&buf, []byte(table.Name))
+		buf.WriteString(`">`)
+
+		if i == 0 {
+			writeODSRow(&buf, []any{fmt.Sprintf("Start date: %s", report.StartDate)})
+			writeODSRow(&buf, []any{fmt.Sprintf("End date: %s", report.EndDate)})
+			writeODSRow(&buf, nil)
+		}
+
+		headers := make([]any, 0, len(table.Headers))
+		for _, header := range table.Headers {
+			headers = append(headers, header)
+		}
+		writeODSRow(&buf, headers)
+
+		for _, row := range table.Rows {
+			writeODSRow(&buf, row)
+		}
+
+		buf.WriteString(`</table:table>`)
+	}
+
+	buf.WriteString(odsContentFooter)
+
+	_, err = content.Write(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+func writeODSRow(buf *bytes.Buffer, row []any) {
+	buf.WriteString(`<table:table-row>`)
+
+	if len(row) == 0 {
+		buf.WriteString(`<table:table-cell/>`)
+	}
+
+	for _, value := range row {
+		text := formatCell(value)
+
+		switch v := value.(type) {
+		case bool:
+			fmt.Fprintf(buf, `<table:table-cell office:value-type="boolean" office:boolean-value="%t">`, v)
+		default:
+			if isNumericCell(value) {
+				fmt.Fprintf(buf, `<table:table-cell office:value-type="float" office:value="%s">`, text)
+			} else {
+				buf.WriteString(`<table:table-cell office:value-type="string">`)
+			}
+		}
+
+		buf.WriteString(`<text:p>`)
+		xml.EscapeText(buf, []byte(text))
+		buf.WriteString(`</text:p></table:table-cell>`)
+	}
+
+	buf.WriteString(`</table:table-row>`)
+}
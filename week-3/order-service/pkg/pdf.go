@@ -2,13 +2,14 @@ package pkg
 
 import (
 	"fmt"
+	"order_service/internal/core"
 	"order_service/services/order/entity"
-	"os"
 
 	"github.com/go-pdf/fpdf"
+	"github.com/google/uuid"
 )
 
-func GeneratePDF(order *entity.Order) error {
+func GeneratePDF(order *entity.Order) (string, error) {
 	headerText := "INVOICE"
 
 	marginX := 10.0
@@ -36,13 +37,37 @@ func GeneratePDF(order *entity.Order) error {
 	pdf.Ln(lineBreak)
 	pdf.Ln(lineBreak)
 
+	// the address the order was placed with, later edits of the address book do not show here
+	if address := order.GetShippingAddressSafe(); address != nil {
+		pdf.SetFont("Arial", "B", 12)
+		_, addressLineHeight := pdf.GetFontSize()
+		addressLineHeight += gapY
+
+		pdf.SetX(marginX)
+		pdf.Cell(0, addressLineHeight, "Ship to")
+		pdf.Ln(-1)
+
+		// the core fonts are cp1252, accented names and streets have to be translated to it
+		translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+		pdf.SetFontStyle("")
+		for _, line := range address.Lines() {
+			pdf.SetX(marginX)
+			pdf.Cell(0, addressLineHeight, translate(line))
+			pdf.Ln(-1)
+		}
+		pdf.SetFontStyle("B")
+	}
+
 	pdf.SetFontSize(14)
 
 	_, lineHeight = pdf.GetFontSize()
 	currentY = pdf.GetY() + lineHeight
 	pdf.SetXY(marginX, currentY)
 
-	headers := [5]string{"ID", "Name", "Quantity", "Unit Price", "Price"}
+	currency := order.GetCurrencySafe()
+
+	headers := [5]string{"ID", "Name", "Quantity", fmt.Sprintf("Unit Price (%s)", currency), fmt.Sprintf("Price (%s)", currency)}
 	colWidth := [5]float64{10.0, 75.0, 25.0, 40.0, 40.0}
 
 	pdf.SetFillColor(200, 200, 200)
@@ -56,13 +81,22 @@ func GeneratePDF(order *entity.Order) error {
 	lineHeight += gapY
 
 	for _, item := range order.GetItemsSafe() {
-		price := item.GetProductPrice() * float32(item.GetQuantity())
+		price := item.GetProductPrice().Mul(item.GetQuantity())
 
 		pdf.CellFormat(colWidth[0], lineHeight, fmt.Sprintf("%d", item.GetProductId()), "1", 0, "CM", false, 0, "")
 		pdf.CellFormat(colWidth[1], lineHeight, item.GetProductName(), "1", 0, "CM", false, 0, "")
 		pdf.CellFormat(colWidth[2], lineHeight, fmt.Sprintf("%d", item.GetQuantity()), "1", 0, "CM", false, 0, "")
-		pdf.CellFormat(colWidth[3], lineHeight, fmt.Sprintf("%.2f", item.GetProductPrice()), "1", 0, "CM", false, 0, "")
-		pdf.CellFormat(colWidth[4], lineHeight, fmt.Sprintf("%.2f", price), "1", 0, "CM", false, 0, "")
+		pdf.CellFormat(colWidth[3], lineHeight, item.GetProductPrice().String(), "1", 0, "CM", false, 0, "")
+		pdf.CellFormat(colWidth[4], lineHeight, price.String(), "1", 0, "CM", false, 0, "")
+
+		pdf.Ln(-1)
+	}
+
+	// discount lines sit under the items, with the coupon code in place of the product
+	for _, discount := range order.GetDiscountsSafe() {
+		pdf.CellFormat(colWidth[0]+colWidth[1], lineHeight, discount.GetCode(), "1", 0, "CM", false, 0, "")
+		pdf.CellFormat(colWidth[2]+colWidth[3], lineHeight, discount.GetDescription(), "1", 0, "CM", false, 0, "")
+		pdf.CellFormat(colWidth[4], lineHeight, fmt.Sprintf("-%s", discount.GetAmount()), "1", 0, "CM", false, 0, "")
 
 		pdf.Ln(-1)
 	}
@@ -77,14 +111,72 @@ func GeneratePDF(order *entity.Order) error {
 	pdf.SetX(marginX + leftIndent)
 
 	pdf.CellFormat(colWidth[3], lineHeight, "Total", "1", 0, "CM", false, 0, "")
-	pdf.CellFormat(colWidth[4], lineHeight, fmt.Sprintf("%.2f", order.GetTotalPriceSafe()), "1", 0, "CM", false, 0, "")
+	pdf.CellFormat(colWidth[4], lineHeight, fmt.Sprintf("%s %s", currency, order.GetTotalPriceSafe()), "1", 0, "CM", false, 0, "")
 
 	pdf.Ln(-1)
 
-	pwd, err := os.Getwd()
+	// taxes per rate, inclusive ones are already part of the prices above
+	pdf.SetFontStyle("")
+	for _, tax := range order.GetTaxesSafe() {
+		kind := "excl."
+		if tax.IsInclusive() {
+			kind = "incl."
+		}
+
+		pdf.SetX(marginX + leftIndent)
+		pdf.CellFormat(colWidth[3], lineHeight, fmt.Sprintf("%s %s%% %s", tax.GetName(), tax.GetRate().Percent(), kind), "1", 0, "CM", false, 0, "")
+		pdf.CellFormat(colWidth[4], lineHeight, tax.GetAmount().String(), "1", 0, "CM", false, 0, "")
+
+		pdf.Ln(-1)
+	}
+
+	// prices were converted from the base currency at checkout, show the rate that was used
+	if order.GetExchangeRateSafe() != core.RateOne {
+		pdf.SetFontStyle("")
+		pdf.Ln(lineBreak)
+		pdf.SetX(marginX)
+		pdf.Cell(0, lineHeight, fmt.Sprintf("Exchange rate: 1 base unit = %s %s", order.GetExchangeRateSafe(), currency))
+		pdf.Ln(-1)
+	}
+
+	// every parcel with its tracking history, oldest event first
+	if shipments := order.GetShipmentsSafe(); len(shipments) > 0 {
+		translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+		pdf.Ln(lineBreak)
+		pdf.SetFont("Arial", "B", 12)
+		pdf.SetX(marginX)
+		pdf.Cell(0, lineHeight, "Tracking")
+		pdf.Ln(-1)
+
+		for _, shipment := range shipments {
+			pdf.SetFont("Arial", "B", 10)
+			pdf.SetX(marginX)
+			pdf.Cell(0, lineHeight, fmt.Sprintf("%s %s - %s", shipment.Carrier, shipment.TrackingNumber, shipment.Status))
+			pdf.Ln(-1)
+
+			pdf.SetFontStyle("")
+			for _, event := range shipment.Events {
+				pdf.SetX(marginX)
+				pdf.CellFormat(40.0, lineHeight, event.OccurredAt.Format("2006-01-02 15:04"), "", 0, "LM", false, 0, "")
+				pdf.CellFormat(35.0, lineHeight, string(event.Status), "", 0, "LM", false, 0, "")
+				pdf.CellFormat(0, lineHeight, translate(fmt.Sprintf("%s %s", event.Location, event.Description)), "", 0, "LM", false, 0, "")
+				pdf.Ln(-1)
+			}
+		}
+	}
+
+	// the name cannot be guessed from the order and every invoice gets its own file
+	pdfName := fmt.Sprintf("invoice-%d-%s.pdf", order.GetIdSafe(), uuid.New().String())
+	path, err := DocumentPath(pdfName)
+	if err != nil {
+		return "", err
+	}
+
+	err = pdf.OutputFileAndClose(path)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return pdf.OutputFileAndClose(fmt.Sprintf("%s/storage/invoice-%d-%d.pdf", pwd, order.GetUserIdSafe(), order.GetIdSafe()))
+	return pdfName, nil
 }
@@ -0,0 +1,254 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"order_service/internal/core"
+	"order_service/services/order/entity"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ReportFormat string
+
+const (
+	ReportFormatXLSX   ReportFormat = "xlsx"
+	ReportFormatCSV    ReportFormat = "csv"
+	ReportFormatNDJSON ReportFormat = "ndjson"
+	ReportFormatODS    ReportFormat = "ods"
+)
+
+var ErrUnsupportedReportFormat = errors.New("report format must be xlsx, csv, ndjson or ods")
+
+// reportFormats are in order of preference, the first one is used when the client accepts anything.
+var reportFormats = []ReportFormat{ReportFormatXLSX, ReportFormatCSV, ReportFormatNDJSON, ReportFormatODS}
+
+var reportContentTypes = map[ReportFormat]string{
+	ReportFormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	ReportFormatCSV:    "text/csv",
+	ReportFormatNDJSON: "application/x-ndjson",
+	ReportFormatODS:    "application/vnd.oasis.opendocument.spreadsheet",
+}
+
+func (format ReportFormat) IsValid() bool {
+	_, ok := reportContentTypes[format]
+	return ok
+}
+
+func (format ReportFormat) ContentType() string {
+	return reportContentTypes[format]
+}
+
+// ReportContentTypes lists the content type of every format, in order of preference.
+func ReportContentTypes() []string {
+	contentTypes := make([]string, 0, len(reportFormats))
+	for _, format := range reportFormats {
+		contentTypes = append(contentTypes, format.ContentType())
+	}
+
+	return contentTypes
+}
+
+// ReportFormatOf is the format served as contentType.
+func ReportFormatOf(contentType string) (ReportFormat, error) {
+	for format, formatContentType := range reportContentTypes {
+		if formatContentType == contentType {
+			return format, nil
+		}
+	}
+
+	return "", ErrUnsupportedReportFormat
+}
+
+// NegotiateReportFormat picks the format asked for with the format query parameter, or else the Accept header.
+// A client accepting anything gets xlsx.
+func NegotiateReportFormat(c *fiber.Ctx) (ReportFormat, error) {
+	if format := ReportFormat(strings.ToLower(c.Query("format"))); format != "" {
+		if !format.IsValid() {
+			return "", ErrUnsupportedReportFormat
+		}
+
+		return format, nil
+	}
+
+	return ReportFormatOf(c.Accepts(ReportContentTypes()...))
+}
+
+// ReportColumn is one field of a report table, Value reads it from a row.
+type ReportColumn[T any] struct {
+	Value  func(row T) any
+	Key    string
+	Header string
+}
+
+// SummarizeColumns are the fields of the orders summary, every format writes them in this order.
+var SummarizeColumns = []ReportColumn[entity.OrdersSummarize]{
+	{Key: "user_id", Header: "User ID", Value: func(row entity.OrdersSummarize) any { return row.UserId }},
+	{Key: "username", Header: "Username", Value: func(row entity.OrdersSummarize) any { return row.Username }},
+	{Key: "num_of_orders", Header: "Num of Orders", Value: func(row entity.OrdersSummarize) any { return row.NumOfOrders }},
+	{Key: "sum_order_price", Header: "Sum Order Price", Value: func(row entity.OrdersSummarize) any { return row.SumOrderPrice }},
+	{Key: "sum_tax", Header: "Tax", Value: func(row entity.OrdersSummarize) any { return row.SumTax }},
+	{Key: "average_order_item_quantity", Header: "Average Order Quantity", Value: func(row entity.OrdersSummarize) any { return row.AverageOrderItemQuantity }},
+}
+
+// TaxColumns are the fields of the taxes collected at every rate, amounts are in the base currency.
+var TaxColumns = []ReportColumn[entity.OrderTax]{
+	{Key: "name", Header: "Tax", Value: func(row entity.OrderTax) any { return row.GetName() }},
+	{Key: "rate", Header: "Rate", Value: func(row entity.OrderTax) any { return row.GetRate() }},
+	{Key: "inclusive", Header: "Inclusive", Value: func(row entity.OrderTax) any { return row.IsInclusive() }},
+	{Key: "taxable_amount", Header: "Taxable Amount", Value: func(row entity.OrderTax) any { return row.GetBaseTaxableAmount() }},
+	{Key: "amount", Header: "Tax Amount", Value: func(row entity.OrderTax) any { return row.GetBaseAmount() }},
+}
+
+// ReportTable is a named table of cells, built from the shared column definitions.
+type ReportTable struct {
+	Name    string
+	Keys    []string
+	Headers []string
+	Rows    [][]any
+}
+
+func newReportTable[T any](name string, columns []ReportColumn[T], rows []T) ReportTable {
+	table := ReportTable{
+		Name:    name,
+		Keys:    make([]string, 0, len(columns)),
+		Headers: make([]string, 0, len(columns)),
+		Rows:    make([][]any, 0, len(rows)),
+	}
+
+	for _, column := range columns {
+		table.Keys = append(table.Keys, column.Key)
+		table.Headers = append(table.Headers, column.Header)
+	}
+
+	for _, row := range rows {
+		cells := make([]any, 0, len(columns))
+		for _, column := range columns {
+			cells = append(cells, column.Value(row))
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+
+	return table
+}
+
+// SummarizeReport is the orders summary between StartDate and EndDate with the taxes collected over it.
+type SummarizeReport struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Rows      []entity.OrdersSummarize
+	Taxes     []entity.OrderTax
+}
+
+func NewSummarizeReport(datas *[]entity.OrdersSummarize, taxes *[]entity.OrderTax, startDate, endDate time.Time) *SummarizeReport {
+	report := SummarizeReport{
+		StartDate: time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location()),
+		EndDate:   time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, endDate.Location()),
+	}
+	if datas != nil {
+		report.Rows = *datas
+	}
+	if taxes != nil {
+		report.Taxes = *taxes
+	}
+
+	return &report
+}
+
+func (report *SummarizeReport) Tables() []ReportTable {
+	return []ReportTable{
+		newReportTable("summarize", SummarizeColumns, report.Rows),
+		newReportTable("taxes", TaxColumns, report.Taxes),
+	}
+}
+
+// ReportExporter writes a report in one file format.
+type ReportExporter interface {
+	Format() ReportFormat
+	Export(w io.Writer, report *SummarizeReport) error
+}
+
+func NewReportExporter(format ReportFormat) (ReportExporter, error) {
+	switch format {
+	case ReportFormatXLSX:
+		return &xlsxExporter{}, nil
+	case ReportFormatCSV:
+		return &csvExporter{}, nil
+	case ReportFormatNDJSON:
+		return &ndjsonExporter{}, nil
+	case ReportFormatODS:
+		return &odsExporter{}, nil
+	}
+
+	return nil, ErrUnsupportedReportFormat
+}
+
+// ExportReport writes the report to a new document in the given format and returns the document's name.
+func ExportReport(report *SummarizeReport, format ReportFormat) (string, error) {
+	exporter, err := NewReportExporter(format)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("summarize-%s.%s", uuid.New().String(), exporter.Format())
+	path, err := DocumentPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+
+	err = exporter.Export(file, report)
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", err
+	}
+
+	err = file.Close()
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return name, nil
+}
+
+// formatCell is the text of a cell, decimals keep their exact digits.
+func formatCell(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case bool:
+		return strconv.FormatBool(v)
+	case core.Money:
+		return v.String()
+	case core.Rate:
+		return v.String()
+	}
+
+	return fmt.Sprint(value)
+}
+
+// isNumericCell tells whether the cell should be stored as a number by spreadsheets.
+func isNumericCell(value any) bool {
+	switch value.(type) {
+	case int, float32, core.Money, core.Rate:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,19 @@
+package pkg
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidTimezone = errors.New("timezone must be an IANA time zone like Asia/Jakarta")
+
+// LoadLocation loads the IANA time zone a client asked for. Local is whatever the server runs in, it is not a
+// zone the client can mean.
+func LoadLocation(name string) (*time.Location, error) {
+	location, err := time.LoadLocation(name)
+	if err != nil || location == time.Local {
+		return nil, ErrInvalidTimezone
+	}
+
+	return location, nil
+}
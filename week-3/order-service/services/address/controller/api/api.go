@@ -0,0 +1,202 @@
+package api
+
+import (
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/address/entity"
+	addressUc "order_service/services/address/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AddressService interface {
+	GetAddresses(*fiber.Ctx) error
+	CreateAddress(*fiber.Ctx) error
+	UpdateAddress(*fiber.Ctx) error
+	SetDefaultAddress(*fiber.Ctx) error
+	DeleteAddress(*fiber.Ctx) error
+}
+
+type service struct {
+	usecase addressUc.AddressUsecase
+}
+
+func NewService(uc addressUc.AddressUsecase) AddressService {
+	return &service{
+		usecase: uc,
+	}
+}
+
+// Get Addresses godoc
+// @summary Get Addresses
+// @description Get the address book of the current user, the default address comes first
+// @tags addresses
+// @produce json
+// @security BearerAuth
+// @success 200 {array} entity.Address
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /addresses/ [get]
+func (srv *service) GetAddresses(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	addresses, err := srv.usecase.GetAddresses(ctx)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(addresses))
+}
+
+// Create Address godoc
+// @summary Create Address
+// @description Add an address to the address book of the current user, the first address becomes the default one
+// @tags addresses
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param payload body entity.AddressRequest true "Address request body"
+// @success 201 {object} entity.Address
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /addresses/ [post]
+func (srv *service) CreateAddress(c *fiber.Ctx) error {
+	var data entity.AddressRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrMissingAddressField.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	address, err := srv.usecase.CreateAddress(ctx, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(core.ResponseData(address))
+}
+
+// Update Address godoc
+// @summary Update Address
+// @description Replace an address of the current user, orders already shipped to it keep the address they were placed with
+// @tags addresses
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param addressID path int true "Address's ID"
+// @param payload body entity.AddressRequest true "Address request body"
+// @success 200 {object} entity.Address
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /addresses/:addressID [put]
+func (srv *service) UpdateAddress(c *fiber.Ctx) error {
+	addressId, err := c.ParamsInt("addressID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	var data entity.AddressRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrMissingAddressField.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	address, err := srv.usecase.UpdateAddress(ctx, addressId, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(address))
+}
+
+// Set Default Address godoc
+// @summary Set Default Address
+// @description Make an address of the current user the default one, orders that do not name an address are shipped to it
+// @tags addresses
+// @produce json
+// @security BearerAuth
+// @param addressID path int true "Address's ID"
+// @success 200 {object} entity.Address
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /addresses/:addressID/default [put]
+func (srv *service) SetDefaultAddress(c *fiber.Ctx) error {
+	addressId, err := c.ParamsInt("addressID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	address, err := srv.usecase.SetDefaultAddress(ctx, addressId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(address))
+}
+
+// Delete Address godoc
+// @summary Delete Address
+// @description Remove an address of the current user, the most recently added of the others becomes the default one
+// @tags addresses
+// @security BearerAuth
+// @param addressID path int true "Address's ID"
+// @success 200
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /addresses/:addressID [delete]
+func (srv *service) DeleteAddress(c *fiber.Ctx) error {
+	addressId, err := c.ParamsInt("addressID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err = srv.usecase.DeleteAddress(ctx, addressId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
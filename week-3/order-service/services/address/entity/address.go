@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxAddressesPerUser is how many addresses fit in the address book of one user.
+const MaxAddressesPerUser = 20
+
+var (
+	countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	phonePattern   = regexp.MustCompile(`^\+?[0-9 ()-]{4,30}$`)
+)
+
+// Address is an entry of a user's address book, the one marked IsDefault ships the orders that do not name
+// an address. Country is an ISO 3166 country code, State is free text as its meaning depends on the country.
+type Address struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+	Label      string     `json:"label"`
+	Recipient  string     `json:"recipient"`
+	Phone      string     `json:"phone"`
+	Line1      string     `json:"line1"`
+	Line2      string     `json:"line2"`
+	City       string     `json:"city"`
+	State      string     `json:"state"`
+	PostalCode string     `json:"postal_code"`
+	Country    string     `json:"country"`
+	Id         int        `json:"id"`
+	UserId     int        `json:"user_id"`
+	IsDefault  bool       `json:"is_default"`
+}
+
+func NewAddress(userId int, now time.Time) Address {
+	return Address{
+		UserId:    userId,
+		CreatedAt: now,
+	}
+}
+
+// NormalizeCountry upper cases an ISO 3166 country code.
+func NormalizeCountry(country string) (string, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if !countryPattern.MatchString(country) {
+		return "", ErrInvalidCountry
+	}
+
+	return country, nil
+}
+
+func (address *Address) SetId(id int) {
+	if address != nil {
+		address.Id = id
+	}
+}
+
+func (address *Address) SetDefault(isDefault bool) {
+	if address != nil {
+		address.IsDefault = isDefault
+	}
+}
+
+func (address *Address) GetIdSafe() int {
+	if address != nil {
+		return address.Id
+	}
+
+	return 0
+}
+
+func (address *Address) GetUserIdSafe() int {
+	if address != nil {
+		return address.UserId
+	}
+
+	return 0
+}
+
+func (address *Address) IsDefaultSafe() bool {
+	if address != nil {
+		return address.IsDefault
+	}
+
+	return false
+}
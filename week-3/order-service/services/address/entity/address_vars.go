@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type AddressRequest struct {
+	// Label is optional, e.g. Home or Office
+	Label      string `json:"label" example:"Home"`
+	Recipient  string `json:"recipient" example:"Jane Doe"`
+	Phone      string `json:"phone" example:"+1 415 555 0100"`
+	Line1      string `json:"line1" example:"1 Market St"`
+	Line2      string `json:"line2" example:"Suite 200"`
+	City       string `json:"city" example:"San Francisco"`
+	State      string `json:"state" example:"CA"`
+	PostalCode string `json:"postal_code" example:"94105"`
+	Country    string `json:"country" example:"US"`
+	// IsDefault makes the address the default one, the first address of a user is always the default one
+	IsDefault bool `json:"is_default"`
+}
+
+func (data AddressRequest) Validate() error {
+	if strings.TrimSpace(data.Recipient) == "" || strings.TrimSpace(data.Line1) == "" || strings.TrimSpace(data.City) == "" || strings.TrimSpace(data.Country) == "" {
+		return ErrMissingAddressField
+	}
+
+	for _, field := range []struct {
+		value string
+		max   int
+	}{
+		{data.Label, 50},
+		{data.Recipient, 100},
+		{data.Line1, 200},
+		{data.Line2, 200},
+		{data.City, 100},
+		{data.State, 100},
+		{data.PostalCode, 20},
+	} {
+		if utf8.RuneCountInString(strings.TrimSpace(field.value)) > field.max {
+			return ErrAddressFieldTooLong
+		}
+	}
+
+	if _, err := NormalizeCountry(data.Country); err != nil {
+		return err
+	}
+
+	if phone := strings.TrimSpace(data.Phone); phone != "" && !phonePattern.MatchString(phone) {
+		return ErrInvalidPhone
+	}
+
+	return nil
+}
+
+// ApplyTo sets the fields of a validated request on the address, whether it is the default one is left to
+// the caller.
+func (data AddressRequest) ApplyTo(address *Address) {
+	country, _ := NormalizeCountry(data.Country)
+
+	address.Label = strings.TrimSpace(data.Label)
+	address.Recipient = strings.TrimSpace(data.Recipient)
+	address.Phone = strings.TrimSpace(data.Phone)
+	address.Line1 = strings.TrimSpace(data.Line1)
+	address.Line2 = strings.TrimSpace(data.Line2)
+	address.City = strings.TrimSpace(data.City)
+	address.State = strings.TrimSpace(data.State)
+	address.PostalCode = strings.TrimSpace(data.PostalCode)
+	address.Country = country
+}
+
+// ToAddress builds an address of a validated request that is not saved in any address book.
+func (data AddressRequest) ToAddress() Address {
+	address := NewAddress(0, time.Now())
+	data.ApplyTo(&address)
+
+	return address
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "errors"
+
+var (
+	ErrMissingAddressField  = errors.New("address must have a recipient, a first line, a city and a country")
+	ErrAddressFieldTooLong  = errors.New("one field of the address is too long")
+	ErrInvalidCountry       = errors.New("country must be a two letter country code like US")
+	ErrInvalidPhone         = errors.New("phone must only have digits, spaces, dashes, brackets and a leading plus")
+	ErrTooManyAddresses     = errors.New("address book is full")
+	ErrAddressNotFound      = errors.New("address cannot be found")
+	ErrCannotGetAddresses   = errors.New("addresses cannot be get")
+	ErrCannotCreateAddress  = errors.New("address cannot be create")
+	ErrCannotUpdateAddress  = errors.New("address cannot be update")
+	ErrCannotDeleteAddress  = errors.New("address cannot be delete")
+	ErrCannotResolveAddress = errors.New("shipping address cannot be resolved")
+)
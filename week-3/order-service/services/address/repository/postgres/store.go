@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/address/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AddressRepository interface {
+	GetAddresses(ctx context.Context, userId int) (*[]entity.Address, error)
+	GetAddress(ctx context.Context, userId, addressId int) (*entity.Address, error)
+	GetDefaultAddress(ctx context.Context, userId int) (*entity.Address, error)
+	CreateAddress(ctx context.Context, address *entity.Address, callbackFn func(address *entity.Address, count int) error) error
+	UpdateAddress(ctx context.Context, userId, addressId int, callbackFn func(address *entity.Address) error) error
+	DeleteAddress(ctx context.Context, userId, addressId int) error
+}
+
+const (
+	QUERY_GET_ADDRESSES                 = "SELECT id, user_id, label, recipient, phone, line1, line2, city, state, postal_code, country, is_default, created_at, updated_at FROM user_addresses WHERE user_id = $1 ORDER BY is_default DESC, id"
+	QUERY_GET_ADDRESS                   = "SELECT id, user_id, label, recipient, phone, line1, line2, city, state, postal_code, country, is_default, created_at, updated_at FROM user_addresses WHERE user_id = $1 AND id = $2"
+	QUERY_GET_DEFAULT_ADDRESS           = "SELECT id, user_id, label, recipient, phone, line1, line2, city, state, postal_code, country, is_default, created_at, updated_at FROM user_addresses WHERE user_id = $1 AND is_default"
+	QUERY_GET_ADDRESS_LOCK              = "SELECT id, user_id, label, recipient, phone, line1, line2, city, state, postal_code, country, is_default, created_at, updated_at FROM user_addresses WHERE user_id = $1 AND id = $2 FOR UPDATE"
+	QUERY_GET_ADDRESS_USER_LOCK         = "SELECT id FROM users WHERE id = $1 FOR UPDATE"
+	QUERY_COUNT_ADDRESSES               = "SELECT COUNT(*) FROM user_addresses WHERE user_id = $1"
+	QUERY_CREATE_ADDRESS_WITH_RETURN_ID = "INSERT INTO user_addresses (user_id, label, recipient, phone, line1, line2, city, state, postal_code, country, is_default, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id"
+	QUERY_UPDATE_ADDRESS                = "UPDATE user_addresses SET label = $2, recipient = $3, phone = $4, line1 = $5, line2 = $6, city = $7, state = $8, postal_code = $9, country = $10, is_default = $11, updated_at = $12 WHERE id = $1"
+	QUERY_UNSET_DEFAULT_ADDRESS         = "UPDATE user_addresses SET is_default = false, updated_at = $3 WHERE user_id = $1 AND id <> $2 AND is_default"
+	QUERY_DELETE_ADDRESS                = "DELETE FROM user_addresses WHERE user_id = $1 AND id = $2 RETURNING is_default"
+	QUERY_PROMOTE_DEFAULT_ADDRESS       = "UPDATE user_addresses SET is_default = true, updated_at = NOW() WHERE id = (SELECT id FROM user_addresses WHERE user_id = $1 ORDER BY id DESC LIMIT 1)"
+)
+
+type postgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAddressRepo(db *pgxpool.Pool) AddressRepository {
+	return &postgresRepo{
+		db,
+	}
+}
+
+func scanAddress(row pgx.Row) (*entity.Address, error) {
+	var address entity.Address
+
+	err := row.Scan(&address.Id, &address.UserId, &address.Label, &address.Recipient, &address.Phone, &address.Line1, &address.Line2, &address.City, &address.State, &address.PostalCode, &address.Country, &address.IsDefault, &address.CreatedAt, &address.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, core.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+func (repo *postgresRepo) GetAddresses(ctx context.Context, userId int) (*[]entity.Address, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_ADDRESSES, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Address, error) {
+		address, err := scanAddress(row)
+		if err != nil {
+			return entity.Address{}, err
+		}
+
+		return *address, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &addresses, nil
+}
+
+func (repo *postgresRepo) GetAddress(ctx context.Context, userId, addressId int) (*entity.Address, error) {
+	return scanAddress(repo.db.QueryRow(ctx, QUERY_GET_ADDRESS, userId, addressId))
+}
+
+func (repo *postgresRepo) GetDefaultAddress(ctx context.Context, userId int) (*entity.Address, error) {
+	return scanAddress(repo.db.QueryRow(ctx, QUERY_GET_DEFAULT_ADDRESS, userId))
+}
+
+// CreateAddress hands the new address and how many addresses the user already has to callbackFn, under the
+// user row so two addresses cannot be added past the limit at once. A default address takes over from the
+// previous one.
+func (repo *postgresRepo) CreateAddress(ctx context.Context, address *entity.Address, callbackFn func(address *entity.Address, count int) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		var userId, count int
+
+		err := tx.QueryRow(ctx, QUERY_GET_ADDRESS_USER_LOCK, address.GetUserIdSafe()).Scan(&userId)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return core.ErrRecordNotFound
+			}
+			return err
+		}
+
+		err = tx.QueryRow(ctx, QUERY_COUNT_ADDRESSES, userId).Scan(&count)
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		err = callbackFn(address, count)
+		if err != nil {
+			return err
+		}
+
+		// the previous default goes first, only one address of a user can be the default one
+		if address.IsDefault {
+			_, err = tx.Exec(ctx, QUERY_UNSET_DEFAULT_ADDRESS, address.UserId, 0, address.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		var newAddressId int
+
+		err = tx.QueryRow(ctx, QUERY_CREATE_ADDRESS_WITH_RETURN_ID, address.UserId, address.Label, address.Recipient, address.Phone, address.Line1, address.Line2, address.City, address.State, address.PostalCode, address.Country, address.IsDefault, address.CreatedAt).Scan(&newAddressId)
+		if err != nil {
+			return err
+		}
+		address.SetId(newAddressId)
+
+		return nil
+	})
+}
+
+func (repo *postgresRepo) UpdateAddress(ctx context.Context, userId, addressId int, callbackFn func(address *entity.Address) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		address, err := scanAddress(tx.QueryRow(ctx, QUERY_GET_ADDRESS_LOCK, userId, addressId))
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		err = callbackFn(address)
+		if err != nil {
+			return err
+		}
+
+		// the previous default goes first, only one address of a user can be the default one
+		if address.IsDefault {
+			_, err = tx.Exec(ctx, QUERY_UNSET_DEFAULT_ADDRESS, address.UserId, address.Id, address.UpdatedAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(ctx, QUERY_UPDATE_ADDRESS, address.Id, address.Label, address.Recipient, address.Phone, address.Line1, address.Line2, address.City, address.State, address.PostalCode, address.Country, address.IsDefault, address.UpdatedAt)
+
+		return err
+	})
+}
+
+// DeleteAddress removes an address of the user, the most recently added of the others becomes the default one
+// when the default one is removed. Orders keep their own copy of the address they were shipped to.
+func (repo *postgresRepo) DeleteAddress(ctx context.Context, userId, addressId int) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		var wasDefault bool
+
+		err := tx.QueryRow(ctx, QUERY_DELETE_ADDRESS, userId, addressId).Scan(&wasDefault)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return core.ErrRecordNotFound
+			}
+			return err
+		}
+
+		if !wasDefault {
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, QUERY_PROMOTE_DEFAULT_ADDRESS, userId)
+
+		return err
+	})
+}
@@ -0,0 +1,293 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	"order_service/services/address/entity"
+	"order_service/services/address/test/mock"
+	"order_service/services/address/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type AddressUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo *mock.MockAddressRepository
+	usecase  usecase.AddressUsecase
+	userCtx  context.Context
+	request  entity.AddressRequest
+}
+
+func (suite *AddressUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockAddressRepository(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo)
+	suite.userCtx = requesterContext(2, 0)
+	suite.request = entity.AddressRequest{Label: "Home", Recipient: "Jane Doe", Line1: "1 Market St", City: "San Francisco", State: "CA", PostalCode: "94105", Country: "us"}
+}
+
+func (suite *AddressUsecaseTestSuite) TestCreateAddress() {
+	repoErr := errors.New("connection refused")
+
+	tests := []struct {
+		name        string
+		isDefault   bool
+		count       int
+		repoErr     error
+		wantDefault bool
+		wantErr     error
+		assertion   assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "First address becomes the default one",
+			count:       0,
+			wantDefault: true,
+			assertion:   assert.NoError,
+		},
+		{
+			name:        "Another address is not the default one",
+			count:       2,
+			wantDefault: false,
+			assertion:   assert.NoError,
+		},
+		{
+			name:        "Another address asked to be the default one",
+			isDefault:   true,
+			count:       2,
+			wantDefault: true,
+			assertion:   assert.NoError,
+		},
+		{
+			name:      "Address book is full",
+			count:     entity.MaxAddressesPerUser,
+			wantErr:   core.ErrConfict.WithError(entity.ErrTooManyAddresses.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo error",
+			repoErr:   repoErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotCreateAddress.Error()).WithDebug(repoErr.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			data := suite.request
+			data.IsDefault = tt.isDefault
+
+			suite.mockRepo.EXPECT().CreateAddress(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, address *entity.Address, callbackFn func(address *entity.Address, count int) error) error {
+				if tt.repoErr != nil {
+					return tt.repoErr
+				}
+
+				err := callbackFn(address, tt.count)
+				if err != nil {
+					return err
+				}
+				address.SetId(5)
+
+				return nil
+			})
+
+			address, err := suite.usecase.CreateAddress(suite.userCtx, &data)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(5, address.Id, "address should be stored")
+				suite.Equal(2, address.UserId, "address should belong to the requester")
+				suite.Equal("US", address.Country, "country should be normalized")
+				suite.Equal(tt.wantDefault, address.IsDefault, "default address should be set correctly")
+			}
+		})
+	}
+}
+
+func (suite *AddressUsecaseTestSuite) TestUpdateAddress() {
+	tests := []struct {
+		name        string
+		address     entity.Address
+		isDefault   bool
+		repoErr     error
+		wantDefault bool
+		wantErr     error
+		assertion   assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "Default address stays the default one",
+			address:     entity.Address{Id: 5, UserId: 2, Country: "DE", IsDefault: true},
+			wantDefault: true,
+			assertion:   assert.NoError,
+		},
+		{
+			name:        "Address is made the default one",
+			address:     entity.Address{Id: 5, UserId: 2, Country: "DE"},
+			isDefault:   true,
+			wantDefault: true,
+			assertion:   assert.NoError,
+		},
+		{
+			name:      "Address of someone else is missing",
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrAddressNotFound.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			data := suite.request
+			data.IsDefault = tt.isDefault
+
+			suite.mockRepo.EXPECT().UpdateAddress(gomock.Any(), 2, 5, gomock.Any()).DoAndReturn(func(ctx context.Context, userId, addressId int, callbackFn func(address *entity.Address) error) error {
+				if tt.repoErr != nil {
+					return tt.repoErr
+				}
+
+				address := tt.address
+				return callbackFn(&address)
+			})
+
+			address, err := suite.usecase.UpdateAddress(suite.userCtx, 5, &data)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal("US", address.Country, "address should be replaced")
+				suite.Equal(tt.wantDefault, address.IsDefault, "default address should be set correctly")
+				suite.NotNil(address.UpdatedAt, "update time should be set")
+			}
+		})
+	}
+}
+
+func (suite *AddressUsecaseTestSuite) TestDeleteAddress() {
+	repoErr := errors.New("connection refused")
+
+	tests := []struct {
+		name      string
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Address is removed",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Unknown address",
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrAddressNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo error",
+			repoErr:   repoErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotDeleteAddress.Error()).WithDebug(repoErr.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().DeleteAddress(gomock.Any(), 2, 5).Return(tt.repoErr)
+
+			err := suite.usecase.DeleteAddress(suite.userCtx, 5)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+		})
+	}
+}
+
+func (suite *AddressUsecaseTestSuite) TestResolveAddress() {
+	saved := &entity.Address{Id: 5, UserId: 2, Recipient: "Jane Doe", IsDefault: true}
+	repoErr := errors.New("connection refused")
+
+	tests := []struct {
+		name        string
+		addressId   int
+		repoAddress *entity.Address
+		repoErr     error
+		want        *entity.Address
+		wantErr     error
+		assertion   assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "Saved address",
+			addressId:   5,
+			repoAddress: saved,
+			want:        saved,
+			assertion:   assert.NoError,
+		},
+		{
+			name:        "Default address when none is named",
+			repoAddress: saved,
+			want:        saved,
+			assertion:   assert.NoError,
+		},
+		{
+			name:      "No default address",
+			repoErr:   core.ErrRecordNotFound,
+			want:      nil,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Unknown saved address",
+			addressId: 9,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrAddressNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo error",
+			repoErr:   repoErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotResolveAddress.Error()).WithDebug(repoErr.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.addressId == 0 {
+				suite.mockRepo.EXPECT().GetDefaultAddress(gomock.Any(), 2).Return(tt.repoAddress, tt.repoErr)
+			} else {
+				suite.mockRepo.EXPECT().GetAddress(gomock.Any(), 2, tt.addressId).Return(tt.repoAddress, tt.repoErr)
+			}
+
+			address, err := suite.usecase.ResolveAddress(context.Background(), 2, tt.addressId)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			suite.Equal(tt.want, address, "address should be resolved correctly")
+		})
+	}
+}
+
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
+
+func TestAddressUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AddressUsecaseTestSuite))
+}
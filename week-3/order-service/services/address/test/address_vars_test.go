@@ -0,0 +1,39 @@
+package test
+
+import (
+	"order_service/services/address/entity"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressRequestValidate(t *testing.T) {
+	valid := entity.AddressRequest{Recipient: "Jane Doe", Line1: "1 Market St", City: "San Francisco", Country: "US"}
+
+	tests := []struct {
+		name    string
+		modify  func(data *entity.AddressRequest)
+		wantErr error
+	}{
+		{name: "Valid", modify: func(data *entity.AddressRequest) {}},
+		{name: "Lower case country", modify: func(data *entity.AddressRequest) { data.Country = "us" }},
+		{name: "Phone", modify: func(data *entity.AddressRequest) { data.Phone = "+1 (415) 555-0100" }},
+		{name: "No recipient", modify: func(data *entity.AddressRequest) { data.Recipient = " " }, wantErr: entity.ErrMissingAddressField},
+		{name: "No first line", modify: func(data *entity.AddressRequest) { data.Line1 = "" }, wantErr: entity.ErrMissingAddressField},
+		{name: "No city", modify: func(data *entity.AddressRequest) { data.City = "" }, wantErr: entity.ErrMissingAddressField},
+		{name: "No country", modify: func(data *entity.AddressRequest) { data.Country = "" }, wantErr: entity.ErrMissingAddressField},
+		{name: "Country name", modify: func(data *entity.AddressRequest) { data.Country = "United States" }, wantErr: entity.ErrInvalidCountry},
+		{name: "Postal code too long", modify: func(data *entity.AddressRequest) { data.PostalCode = strings.Repeat("9", 21) }, wantErr: entity.ErrAddressFieldTooLong},
+		{name: "Phone with letters", modify: func(data *entity.AddressRequest) { data.Phone = "call me" }, wantErr: entity.ErrInvalidPhone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := valid
+			tt.modify(&data)
+
+			assert.Equal(t, tt.wantErr, data.Validate())
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/postgres/store.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/postgres/store.go -destination test/mock/store.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/address/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAddressRepository is a mock of AddressRepository interface.
+type MockAddressRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressRepositoryMockRecorder
+}
+
+// MockAddressRepositoryMockRecorder is the mock recorder for MockAddressRepository.
+type MockAddressRepositoryMockRecorder struct {
+	mock *MockAddressRepository
+}
+
+// NewMockAddressRepository creates a new mock instance.
+func NewMockAddressRepository(ctrl *gomock.Controller) *MockAddressRepository {
+	mock := &MockAddressRepository{ctrl: ctrl}
+	mock.recorder = &MockAddressRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddressRepository) EXPECT() *MockAddressRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAddress mocks base method.
+func (m *MockAddressRepository) CreateAddress(ctx context.Context, address *entity.Address, callbackFn func(*entity.Address, int) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAddress", ctx, address, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAddress indicates an expected call of CreateAddress.
+func (mr *MockAddressRepositoryMockRecorder) CreateAddress(ctx, address, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAddress", reflect.TypeOf((*MockAddressRepository)(nil).CreateAddress), ctx, address, callbackFn)
+}
+
+// DeleteAddress mocks base method.
+func (m *MockAddressRepository) DeleteAddress(ctx context.Context, userId, addressId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddress", ctx, userId, addressId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAddress indicates an expected call of DeleteAddress.
+func (mr *MockAddressRepositoryMockRecorder) DeleteAddress(ctx, userId, addressId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddress", reflect.TypeOf((*MockAddressRepository)(nil).DeleteAddress), ctx, userId, addressId)
+}
+
+// GetAddress mocks base method.
+func (m *MockAddressRepository) GetAddress(ctx context.Context, userId, addressId int) (*entity.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddress", ctx, userId, addressId)
+	ret0, _ := ret[0].(*entity.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddress indicates an expected call of GetAddress.
+func (mr *MockAddressRepositoryMockRecorder) GetAddress(ctx, userId, addressId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddress", reflect.TypeOf((*MockAddressRepository)(nil).GetAddress), ctx, userId, addressId)
+}
+
+// GetAddresses mocks base method.
+func (m *MockAddressRepository) GetAddresses(ctx context.Context, userId int) (*[]entity.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddresses", ctx, userId)
+	ret0, _ := ret[0].(*[]entity.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddresses indicates an expected call of GetAddresses.
+func (mr *MockAddressRepositoryMockRecorder) GetAddresses(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddresses", reflect.TypeOf((*MockAddressRepository)(nil).GetAddresses), ctx, userId)
+}
+
+// GetDefaultAddress mocks base method.
+func (m *MockAddressRepository) GetDefaultAddress(ctx context.Context, userId int) (*entity.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultAddress", ctx, userId)
+	ret0, _ := ret[0].(*entity.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDefaultAddress indicates an expected call of GetDefaultAddress.
+func (mr *MockAddressRepositoryMockRecorder) GetDefaultAddress(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultAddress", reflect.TypeOf((*MockAddressRepository)(nil).GetDefaultAddress), ctx, userId)
+}
+
+// UpdateAddress mocks base method.
+func (m *MockAddressRepository) UpdateAddress(ctx context.Context, userId, addressId int, callbackFn func(*entity.Address) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAddress", ctx, userId, addressId, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAddress indicates an expected call of UpdateAddress.
+func (mr *MockAddressRepositoryMockRecorder) UpdateAddress(ctx, userId, addressId, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddress", reflect.TypeOf((*MockAddressRepository)(nil).UpdateAddress), ctx, userId, addressId, callbackFn)
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/services/address/entity"
+	addressRepo "order_service/services/address/repository/postgres"
+	"time"
+)
+
+type AddressUsecase interface {
+	GetAddresses(ctx context.Context) (*[]entity.Address, error)
+	CreateAddress(ctx context.Context, data *entity.AddressRequest) (*entity.Address, error)
+	UpdateAddress(ctx context.Context, addressId int, data *entity.AddressRequest) (*entity.Address, error)
+	SetDefaultAddress(ctx context.Context, addressId int) (*entity.Address, error)
+	DeleteAddress(ctx context.Context, addressId int) error
+	ResolveAddress(ctx context.Context, userId, addressId int) (*entity.Address, error)
+}
+
+type addressUsecase struct {
+	repo addressRepo.AddressRepository
+}
+
+func NewUsecase(repo addressRepo.AddressRepository) AddressUsecase {
+	return &addressUsecase{
+		repo,
+	}
+}
+
+func requesterId(ctx context.Context) (int, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return 0, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return int(uid.GetLocalID()), nil
+}
+
+// GetAddresses returns the address book of the requester, the default address comes first.
+func (uc *addressUsecase) GetAddresses(ctx context.Context) (*[]entity.Address, error) {
+	userId, err := requesterId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := uc.repo.GetAddresses(ctx, userId)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetAddresses.Error()).WithDebug(err.Error())
+	}
+
+	return addresses, nil
+}
+
+// CreateAddress adds an address to the address book of the requester, the first one becomes the default one.
+func (uc *addressUsecase) CreateAddress(ctx context.Context, data *entity.AddressRequest) (*entity.Address, error) {
+	userId, err := requesterId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	address := entity.NewAddress(userId, time.Now())
+	data.ApplyTo(&address)
+
+	err = uc.repo.CreateAddress(ctx, &address, func(address *entity.Address, count int) error {
+		if count >= entity.MaxAddressesPerUser {
+			return entity.ErrTooManyAddresses
+		}
+
+		address.SetDefault(data.IsDefault || count == 0)
+
+		return nil
+	})
+	if err != nil {
+		if err == entity.ErrTooManyAddresses {
+			return nil, core.ErrConfict.WithError(entity.ErrTooManyAddresses.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotCreateAddress.Error()).WithDebug(err.Error())
+	}
+
+	return &address, nil
+}
+
+// UpdateAddress replaces an address of the requester. The default address stays the default one until another
+// one is made the default, orders already shipped to it are not changed.
+func (uc *addressUsecase) UpdateAddress(ctx context.Context, addressId int, data *entity.AddressRequest) (*entity.Address, error) {
+	return uc.updateOwnAddress(ctx, addressId, func(address *entity.Address) {
+		data.ApplyTo(address)
+		address.SetDefault(address.IsDefaultSafe() || data.IsDefault)
+	})
+}
+
+func (uc *addressUsecase) SetDefaultAddress(ctx context.Context, addressId int) (*entity.Address, error) {
+	return uc.updateOwnAddress(ctx, addressId, func(address *entity.Address) {
+		address.SetDefault(true)
+	})
+}
+
+func (uc *addressUsecase) updateOwnAddress(ctx context.Context, addressId int, changeFn func(address *entity.Address)) (*entity.Address, error) {
+	userId, err := requesterId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated entity.Address
+
+	err = uc.repo.UpdateAddress(ctx, userId, addressId, func(address *entity.Address) error {
+		changeFn(address)
+
+		now := time.Now()
+		address.UpdatedAt = &now
+
+		updated = *address
+
+		return nil
+	})
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return nil, core.ErrNotFound.WithError(entity.ErrAddressNotFound.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotUpdateAddress.Error()).WithDebug(err.Error())
+	}
+
+	return &updated, nil
+}
+
+func (uc *addressUsecase) DeleteAddress(ctx context.Context, addressId int) error {
+	userId, err := requesterId(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.DeleteAddress(ctx, userId, addressId)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return core.ErrNotFound.WithError(entity.ErrAddressNotFound.Error())
+		}
+
+		return core.ErrInternalServerError.WithError(entity.ErrCannotDeleteAddress.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// ResolveAddress returns the address an order of the user is shipped to, the given one or else the default
+// one. It is nil when no address was given and the user has no default one.
+func (uc *addressUsecase) ResolveAddress(ctx context.Context, userId, addressId int) (*entity.Address, error) {
+	if addressId == 0 {
+		address, err := uc.repo.GetDefaultAddress(ctx, userId)
+		if err != nil {
+			if err == core.ErrRecordNotFound {
+				return nil, nil
+			}
+
+			return nil, core.ErrInternalServerError.WithError(entity.ErrCannotResolveAddress.Error()).WithDebug(err.Error())
+		}
+
+		return address, nil
+	}
+
+	address, err := uc.repo.GetAddress(ctx, userId, addressId)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return nil, core.ErrNotFound.WithError(entity.ErrAddressNotFound.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotResolveAddress.Error()).WithDebug(err.Error())
+	}
+
+	return address, nil
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/analytics/entity"
+	analyticsUc "order_service/services/analytics/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnalyticsService interface {
+	GetOrderTimeSeries(*fiber.Ctx) error
+	GetLeaderboard(*fiber.Ctx) error
+}
+
+type service struct {
+	usecase analyticsUc.AnalyticsUsecase
+}
+
+func NewService(uc analyticsUc.AnalyticsUsecase) AnalyticsService {
+	return &service{
+		usecase: uc,
+	}
+}
+
+// Get Order Time Series godoc
+// @summary Get Order Time Series
+// @description Get the order count, revenue, average order value and units sold of every day, week, month or quarter, buckets without orders are zero. Cancelled orders are left out and amounts are in the base currency. Customers only get their own orders
+// @tags analytics
+// @produce json
+// @security BearerAuth
+// @param granularity query string false "Bucket size, day by default" Enums(day, week, month, quarter)
+// @param start_date query string false "First day of the series (2006-01-02), 30 buckets before the end date by default"
+// @param end_date query string false "Last day of the series (2006-01-02), today by default"
+// @param timezone query string false "IANA time zone the days are read in, like Asia/Jakarta"
+// @param user_id query int false "Only orders of this user, admin only"
+// @param product_id query int false "Only orders having this product, counting its lines only"
+// @success 200 {object} entity.TimeSeries
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /analytics/orders [get]
+func (srv *service) GetOrderTimeSeries(c *fiber.Ctx) error {
+	var data entity.TimeSeriesRequest
+
+	if err := c.QueryParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidId.Error()).WithDebug(err.Error()))
+	}
+
+	filter, err := data.ToFilter()
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	series, err := srv.usecase.GetOrderTimeSeries(ctx, filter)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(series))
+}
+
+// Get Leaderboard godoc
+// @summary Get Leaderboard
+// @description Rank products by revenue or units, customers by spend or number of orders, or orders by value. Days are UTC, cancelled orders are left out and amounts are in the base currency. Leaderboards of a window are cached for a few minutes, only admin can do this action
+// @tags analytics
+// @produce json
+// @security BearerAuth
+// @param kind path string true "What is ranked" Enums(products, customers, orders)
+// @param metric query string false "What entries are ranked by, revenue for products, spend for customers and value for orders by default" Enums(revenue, units, spend, orders, value)
+// @param tie_break query string false "How entries with the same value are ordered, the lowest id settles anything left. secondary by default" Enums(secondary, recent, id)
+// @param limit query int false "Number of entries, 10 by default and 100 at most"
+// @param window query string false "Days ending today, cannot be combined with dates. 30d when no dates are given" Enums(7d, 30d, 90d, 365d)
+// @param start_date query string false "First day ranked (2006-01-02), 30 days before the end date by default"
+// @param end_date query string false "Last day ranked (2006-01-02), today by default"
+// @success 200 {object} entity.Leaderboard
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /analytics/leaderboards/{kind} [get]
+func (srv *service) GetLeaderboard(c *fiber.Ctx) error {
+	var data entity.LeaderboardRequest
+
+	if err := c.QueryParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidLimit.Error()).WithDebug(err.Error()))
+	}
+	data.Kind = c.Params("kind")
+
+	filter, err := data.ToFilter()
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	board, err := srv.usecase.GetLeaderboard(ctx, filter)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(board))
+}
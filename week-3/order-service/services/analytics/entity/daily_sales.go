@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// DailySalesRebuild is what a rebuild of the daily sales rollups from StartDate to EndDate (UTC days) wrote.
+type DailySalesRebuild struct {
+	StartDate   time.Time
+	EndDate     time.Time
+	UserRows    int
+	ProductRows int
+	TaxRows     int
+}
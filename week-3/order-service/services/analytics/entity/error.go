@@ -0,0 +1,18 @@
+package entity
+
+import "errors"
+
+var (
+	ErrInvalidGranularity   = errors.New("granularity must be day, week, month or quarter")
+	ErrInvalidDateRange     = errors.New("dates must be 2006-01-02 and start date cannot be after end date")
+	ErrInvalidId            = errors.New("user id and product id cannot be negative")
+	ErrTooManyBuckets       = errors.New("date range has too many buckets for the granularity")
+	ErrCannotGetAnalytics   = errors.New("analytics cannot be get")
+	ErrCannotRebuildSales   = errors.New("daily sales cannot be rebuilt")
+	ErrInvalidLeaderboard   = errors.New("leaderboard must be products, customers or orders")
+	ErrInvalidMetric        = errors.New("metric is not supported by the leaderboard")
+	ErrInvalidTieBreak      = errors.New("tie break must be secondary, recent or id")
+	ErrInvalidLimit         = errors.New("limit must be between 1 and 100")
+	ErrInvalidWindow        = errors.New("window must be 7d, 30d, 90d or 365d and cannot be combined with dates")
+	ErrCannotGetLeaderboard = errors.New("leaderboard cannot be get")
+)
@@ -0,0 +1,222 @@
+package entity
+
+import (
+	"fmt"
+	"order_service/internal/core"
+	"time"
+)
+
+const (
+	DefaultLeaderboardLimit = 10
+	MaxLeaderboardLimit     = 100
+)
+
+type LeaderboardKind string
+
+const (
+	LeaderboardProducts  LeaderboardKind = "products"
+	LeaderboardCustomers LeaderboardKind = "customers"
+	LeaderboardOrders    LeaderboardKind = "orders"
+)
+
+// Metrics lists what the entries of the leaderboard can be ranked by, the first one is the default.
+func (kind LeaderboardKind) Metrics() []LeaderboardMetric {
+	switch kind {
+	case LeaderboardProducts:
+		return []LeaderboardMetric{MetricRevenue, MetricUnits}
+	case LeaderboardCustomers:
+		return []LeaderboardMetric{MetricSpend, MetricOrders}
+	case LeaderboardOrders:
+		return []LeaderboardMetric{MetricValue}
+	}
+
+	return nil
+}
+
+func (kind LeaderboardKind) IsValid() bool {
+	return len(kind.Metrics()) > 0
+}
+
+func (kind LeaderboardKind) Supports(metric LeaderboardMetric) bool {
+	for _, m := range kind.Metrics() {
+		if m == metric {
+			return true
+		}
+	}
+
+	return false
+}
+
+type LeaderboardMetric string
+
+const (
+	MetricRevenue LeaderboardMetric = "revenue"
+	MetricUnits   LeaderboardMetric = "units"
+	MetricSpend   LeaderboardMetric = "spend"
+	MetricOrders  LeaderboardMetric = "orders"
+	MetricValue   LeaderboardMetric = "value"
+)
+
+// Secondary is the metric that settles a tie with the secondary tie break.
+func (metric LeaderboardMetric) Secondary() LeaderboardMetric {
+	switch metric {
+	case MetricRevenue:
+		return MetricUnits
+	case MetricUnits:
+		return MetricRevenue
+	case MetricSpend:
+		return MetricOrders
+	case MetricOrders:
+		return MetricSpend
+	}
+
+	return MetricUnits
+}
+
+// TieBreak settles entries with the same value, whatever is left is settled by the lowest id.
+type TieBreak string
+
+const (
+	TieBreakSecondary TieBreak = "secondary" // the other metric, units for order values
+	TieBreakRecent    TieBreak = "recent"    // the latest order first
+	TieBreakId        TieBreak = "id"
+)
+
+func (tieBreak TieBreak) IsValid() bool {
+	switch tieBreak {
+	case TieBreakSecondary, TieBreakRecent, TieBreakId:
+		return true
+	}
+
+	return false
+}
+
+// LeaderboardWindow is a preset range of days ending today, these are what dashboards ask for and
+// the only leaderboards that get cached.
+type LeaderboardWindow string
+
+const (
+	Window7Days   LeaderboardWindow = "7d"
+	Window30Days  LeaderboardWindow = "30d"
+	Window90Days  LeaderboardWindow = "90d"
+	Window365Days LeaderboardWindow = "365d"
+)
+
+func (window LeaderboardWindow) Days() int {
+	switch window {
+	case Window7Days:
+		return 7
+	case Window30Days:
+		return 30
+	case Window90Days:
+		return 90
+	case Window365Days:
+		return 365
+	}
+
+	return 0
+}
+
+// LeaderboardFilter is the validated form of LeaderboardRequest. StartDate and EndDate are UTC days,
+// both included, like the daily sales rollups the products and customers are ranked from.
+type LeaderboardFilter struct {
+	Kind      LeaderboardKind
+	Metric    LeaderboardMetric
+	TieBreak  TieBreak
+	Limit     int
+	Window    LeaderboardWindow
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// Range is the time the filter covers, from the start of StartDate up to but not including the day after EndDate.
+func (filter *LeaderboardFilter) Range() (time.Time, time.Time) {
+	start := time.Date(filter.StartDate.Year(), filter.StartDate.Month(), filter.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(filter.EndDate.Year(), filter.EndDate.Month(), filter.EndDate.Day()+1, 0, 0, 0, 0, time.UTC)
+
+	return start, end
+}
+
+// ApplyDefaults turns the window into dates ending today, without a window or dates the last 30 days are ranked.
+// A missing end date is today and a missing start date is 30 days before the end date.
+func (filter *LeaderboardFilter) ApplyDefaults(now time.Time) error {
+	today := now.UTC()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+
+	if filter.Window == "" && filter.StartDate == nil && filter.EndDate == nil {
+		filter.Window = Window30Days
+	}
+
+	if filter.Window != "" {
+		startDate := today.AddDate(0, 0, 1-filter.Window.Days())
+		filter.StartDate, filter.EndDate = &startDate, &today
+		return nil
+	}
+
+	if filter.EndDate == nil {
+		if filter.StartDate.After(today) {
+			return ErrInvalidDateRange
+		}
+		filter.EndDate = &today
+	}
+
+	if filter.StartDate == nil {
+		startDate := filter.EndDate.AddDate(0, 0, 1-Window30Days.Days())
+		filter.StartDate = &startDate
+	}
+
+	return nil
+}
+
+// CacheKey identifies the leaderboard of a window for the day, it is empty for any other range.
+func (filter *LeaderboardFilter) CacheKey() string {
+	if filter.Window == "" || filter.EndDate == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%s:%s:%d:%s:%s", filter.Kind, filter.Metric, filter.TieBreak, filter.Limit, filter.Window, filter.EndDate.Format(time.DateOnly))
+}
+
+// LeaderboardEntry is one product, customer or order of a leaderboard. Revenue is in the base currency,
+// it is what the product sold for, what the customer spent or what the order was charged.
+type LeaderboardEntry struct {
+	Rank        int        `json:"rank"`
+	Id          int        `json:"id"`
+	Name        string     `json:"name"`
+	UserId      int        `json:"user_id,omitempty"`
+	NumOfOrders int        `json:"num_of_orders"`
+	UnitsSold   int        `json:"units_sold"`
+	Revenue     core.Money `json:"revenue" swaggertype:"number"`
+}
+
+type Leaderboard struct {
+	Kind        LeaderboardKind    `json:"kind"`
+	Metric      LeaderboardMetric  `json:"metric"`
+	TieBreak    TieBreak           `json:"tie_break"`
+	Window      LeaderboardWindow  `json:"window,omitempty"`
+	StartDate   string             `json:"start_date"`
+	EndDate     string             `json:"end_date"`
+	Entries     []LeaderboardEntry `json:"entries"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
+
+// NewLeaderboard ranks entries in the order they are given, starting at 1.
+func NewLeaderboard(filter *LeaderboardFilter, entries []LeaderboardEntry, now time.Time) Leaderboard {
+	board := Leaderboard{
+		Kind:        filter.Kind,
+		Metric:      filter.Metric,
+		TieBreak:    filter.TieBreak,
+		Window:      filter.Window,
+		StartDate:   filter.StartDate.Format(time.DateOnly),
+		EndDate:     filter.EndDate.Format(time.DateOnly),
+		Entries:     make([]LeaderboardEntry, 0, len(entries)),
+		GeneratedAt: now,
+	}
+
+	for i, entry := range entries {
+		entry.Rank = i + 1
+		board.Entries = append(board.Entries, entry)
+	}
+
+	return board
+}
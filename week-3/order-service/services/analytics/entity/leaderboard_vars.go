@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// LeaderboardRequest holds the leaderboard of the path and the raw query parameters.
+type LeaderboardRequest struct {
+	Kind      string `query:"-"`
+	Metric    string `query:"metric"`
+	TieBreak  string `query:"tie_break"`
+	Limit     int    `query:"limit"`
+	Window    string `query:"window"`
+	StartDate string `query:"start_date"`
+	EndDate   string `query:"end_date"`
+}
+
+func (data LeaderboardRequest) ToFilter() (*LeaderboardFilter, error) {
+	filter := LeaderboardFilter{
+		Kind:     LeaderboardKind(strings.ToLower(data.Kind)),
+		Metric:   LeaderboardMetric(strings.ToLower(data.Metric)),
+		TieBreak: TieBreak(strings.ToLower(data.TieBreak)),
+		Limit:    data.Limit,
+		Window:   LeaderboardWindow(strings.ToLower(data.Window)),
+	}
+
+	if !filter.Kind.IsValid() {
+		return nil, ErrInvalidLeaderboard
+	}
+
+	if filter.Metric == "" {
+		filter.Metric = filter.Kind.Metrics()[0]
+	}
+	if !filter.Kind.Supports(filter.Metric) {
+		return nil, ErrInvalidMetric
+	}
+
+	if filter.TieBreak == "" {
+		filter.TieBreak = TieBreakSecondary
+	}
+	if !filter.TieBreak.IsValid() {
+		return nil, ErrInvalidTieBreak
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = DefaultLeaderboardLimit
+	}
+	if filter.Limit < 0 || filter.Limit > MaxLeaderboardLimit {
+		return nil, ErrInvalidLimit
+	}
+
+	if filter.Window != "" && (filter.Window.Days() == 0 || data.StartDate != "" || data.EndDate != "") {
+		return nil, ErrInvalidWindow
+	}
+
+	if data.StartDate != "" {
+		startDate, err := time.Parse(time.DateOnly, data.StartDate)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		filter.StartDate = &startDate
+	}
+
+	if data.EndDate != "" {
+		endDate, err := time.Parse(time.DateOnly, data.EndDate)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		filter.EndDate = &endDate
+	}
+
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, ErrInvalidDateRange
+	}
+
+	return &filter, nil
+}
@@ -0,0 +1,185 @@
+package entity
+
+import (
+	"order_service/internal/core"
+	"time"
+)
+
+const (
+	// DefaultTimeSeriesBuckets is how many buckets are returned when no start date is given.
+	DefaultTimeSeriesBuckets = 30
+	MaxTimeSeriesBuckets     = 1000
+)
+
+type Granularity string
+
+const (
+	GranularityDay     Granularity = "day"
+	GranularityWeek    Granularity = "week"
+	GranularityMonth   Granularity = "month"
+	GranularityQuarter Granularity = "quarter"
+)
+
+func (g Granularity) IsValid() bool {
+	switch g {
+	case GranularityDay, GranularityWeek, GranularityMonth, GranularityQuarter:
+		return true
+	}
+
+	return false
+}
+
+// Truncate is the start of the bucket t falls in, in the location of t. Weeks start on Monday like
+// they do in postgres.
+func (g Granularity) Truncate(t time.Time) time.Time {
+	year, month, day := t.Date()
+
+	switch g {
+	case GranularityWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	case GranularityMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case GranularityQuarter:
+		return time.Date(year, ((month-1)/3)*3+1, 1, 0, 0, 0, 0, t.Location())
+	}
+
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// Add moves a bucket start n buckets forward, or backward for a negative n.
+func (g Granularity) Add(bucket time.Time, n int) time.Time {
+	switch g {
+	case GranularityWeek:
+		return bucket.AddDate(0, 0, 7*n)
+	case GranularityMonth:
+		return bucket.AddDate(0, n, 0)
+	case GranularityQuarter:
+		return bucket.AddDate(0, 3*n, 0)
+	}
+
+	return bucket.AddDate(0, 0, n)
+}
+
+// TimeSeriesFilter is the validated form of TimeSeriesRequest. StartDate and EndDate are calendar days,
+// both included, read in Location. A zero UserId or ProductId means every user or product.
+type TimeSeriesFilter struct {
+	StartDate   *time.Time
+	EndDate     *time.Time
+	Location    *time.Location
+	Granularity Granularity
+	UserId      int
+	ProductId   int
+}
+
+// Range is the time the filter covers, from the start of StartDate up to but not including the day after EndDate.
+func (filter *TimeSeriesFilter) Range() (time.Time, time.Time) {
+	start := time.Date(filter.StartDate.Year(), filter.StartDate.Month(), filter.StartDate.Day(), 0, 0, 0, 0, filter.Location)
+	end := time.Date(filter.EndDate.Year(), filter.EndDate.Month(), filter.EndDate.Day()+1, 0, 0, 0, 0, filter.Location)
+
+	return start, end
+}
+
+// Buckets lists the start of every bucket the filter covers, the first and last bucket may be partly outside of it.
+func (filter *TimeSeriesFilter) Buckets() []time.Time {
+	start, end := filter.Range()
+
+	var buckets []time.Time
+	for bucket := filter.Granularity.Truncate(start); bucket.Before(end); bucket = filter.Granularity.Add(bucket, 1) {
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets
+}
+
+// AlignsWithUTCDays tells whether every bucket starts and ends on a UTC midnight, the series can then be
+// added up from the daily sales rollups which are kept by UTC day.
+func (filter *TimeSeriesFilter) AlignsWithUTCDays() bool {
+	start, end := filter.Range()
+	for _, t := range []time.Time{start, end} {
+		if _, offset := t.Zone(); offset != 0 {
+			return false
+		}
+	}
+
+	for _, bucket := range filter.Buckets() {
+		if _, offset := bucket.Zone(); offset != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TimeSeriesPoint is what the orders of one bucket add up to. Revenue is in the base currency, it is what
+// the orders were charged unless the series is narrowed to a product, then it is what that product sold for.
+type TimeSeriesPoint struct {
+	Bucket            time.Time  `json:"bucket"`
+	NumOfOrders       int        `json:"num_of_orders"`
+	Revenue           core.Money `json:"revenue" swaggertype:"number"`
+	AverageOrderValue core.Money `json:"average_order_value" swaggertype:"number"`
+	UnitsSold         int        `json:"units_sold"`
+}
+
+func NewTimeSeriesPoint(bucket time.Time, numOfOrders int, revenue core.Money, unitsSold int) TimeSeriesPoint {
+	point := TimeSeriesPoint{
+		Bucket:      bucket,
+		NumOfOrders: numOfOrders,
+		Revenue:     revenue,
+		UnitsSold:   unitsSold,
+	}
+
+	if numOfOrders > 0 {
+		// round half away from zero to the cent like every other amount
+		count := int64(numOfOrders)
+		units := revenue.MinorUnits()
+		if units < 0 {
+			point.AverageOrderValue = core.NewMoney((units - count/2) / count)
+		} else {
+			point.AverageOrderValue = core.NewMoney((units + count/2) / count)
+		}
+	}
+
+	return point
+}
+
+// TimeSeries holds one point for every bucket between StartDate and EndDate, buckets without orders are zero.
+type TimeSeries struct {
+	StartDate   string            `json:"start_date"`
+	EndDate     string            `json:"end_date"`
+	Timezone    string            `json:"timezone"`
+	Granularity Granularity       `json:"granularity"`
+	UserId      int               `json:"user_id,omitempty"`
+	ProductId   int               `json:"product_id,omitempty"`
+	Points      []TimeSeriesPoint `json:"points"`
+}
+
+// NewTimeSeries lays points over every bucket of the filter, points outside of it are dropped.
+func NewTimeSeries(filter *TimeSeriesFilter, points []TimeSeriesPoint) TimeSeries {
+	byBucket := make(map[int64]TimeSeriesPoint, len(points))
+	for _, point := range points {
+		byBucket[point.Bucket.Unix()] = point
+	}
+
+	buckets := filter.Buckets()
+	series := TimeSeries{
+		StartDate:   filter.StartDate.Format(time.DateOnly),
+		EndDate:     filter.EndDate.Format(time.DateOnly),
+		Timezone:    filter.Location.String(),
+		Granularity: filter.Granularity,
+		UserId:      filter.UserId,
+		ProductId:   filter.ProductId,
+		Points:      make([]TimeSeriesPoint, 0, len(buckets)),
+	}
+
+	for _, bucket := range buckets {
+		point, ok := byBucket[bucket.Unix()]
+		if !ok {
+			point = NewTimeSeriesPoint(bucket, 0, 0, 0)
+		}
+		point.Bucket = bucket
+		series.Points = append(series.Points, point)
+	}
+
+	return series
+}
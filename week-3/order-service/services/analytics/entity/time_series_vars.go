@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"order_service/pkg"
+	"strings"
+	"time"
+)
+
+// TimeSeriesRequest holds the raw query parameters of the order time series.
+type TimeSeriesRequest struct {
+	Granularity string `query:"granularity"`
+	StartDate   string `query:"start_date"`
+	EndDate     string `query:"end_date"`
+	Timezone    string `query:"timezone"`
+	UserId      int    `query:"user_id"`
+	ProductId   int    `query:"product_id"`
+}
+
+func (data TimeSeriesRequest) ToFilter() (*TimeSeriesFilter, error) {
+	filter := TimeSeriesFilter{
+		Granularity: Granularity(strings.ToLower(data.Granularity)),
+		UserId:      data.UserId,
+		ProductId:   data.ProductId,
+	}
+
+	if filter.Granularity == "" {
+		filter.Granularity = GranularityDay
+	}
+	if !filter.Granularity.IsValid() {
+		return nil, ErrInvalidGranularity
+	}
+
+	if data.Timezone != "" {
+		location, err := pkg.LoadLocation(data.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		filter.Location = location
+	}
+
+	if data.StartDate != "" {
+		startDate, err := time.Parse(time.DateOnly, data.StartDate)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		filter.StartDate = &startDate
+	}
+
+	if data.EndDate != "" {
+		endDate, err := time.Parse(time.DateOnly, data.EndDate)
+		if err != nil {
+			return nil, ErrInvalidDateRange
+		}
+		filter.EndDate = &endDate
+	}
+
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, ErrInvalidDateRange
+	}
+
+	if filter.UserId < 0 || filter.ProductId < 0 {
+		return nil, ErrInvalidId
+	}
+
+	return &filter, nil
+}
+
+// ApplyDefaults reads the dates in location unless the client picked a timezone, ends the series today
+// and starts it DefaultTimeSeriesBuckets buckets earlier when no dates are given.
+func (filter *TimeSeriesFilter) ApplyDefaults(location *time.Location, now time.Time) error {
+	if filter.Location == nil {
+		filter.Location = location
+	}
+
+	if filter.EndDate == nil {
+		today := now.In(filter.Location)
+		endDate := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+		if filter.StartDate != nil && endDate.Before(*filter.StartDate) {
+			return ErrInvalidDateRange
+		}
+		filter.EndDate = &endDate
+	}
+
+	if filter.StartDate == nil {
+		first := filter.Granularity.Add(filter.Granularity.Truncate(*filter.EndDate), 1-DefaultTimeSeriesBuckets)
+		filter.StartDate = &first
+	}
+
+	// count without listing them, a range of centuries by the day should not be built only to be refused
+	start, end := filter.Range()
+	bucket := filter.Granularity.Truncate(start)
+	for count := 0; bucket.Before(end); count++ {
+		if count == MaxTimeSeriesBuckets {
+			return ErrTooManyBuckets
+		}
+		bucket = filter.Granularity.Add(bucket, 1)
+	}
+
+	return nil
+}
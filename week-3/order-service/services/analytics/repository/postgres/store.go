@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/analytics/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AnalyticsRepository interface {
+	GetOrderTimeSeries(ctx context.Context, filter *entity.TimeSeriesFilter) (*[]entity.TimeSeriesPoint, error)
+	RebuildDailySales(ctx context.Context, startDate, endDate time.Time) (*entity.DailySalesRebuild, error)
+	GetLeaderboard(ctx context.Context, filter *entity.LeaderboardFilter) (*[]entity.LeaderboardEntry, error)
+}
+
+const (
+	// created_at holds UTC wall clock time, it is moved into the requested zone before being bucketed.
+	// A product filter keeps the orders having that product and only counts its lines, its base price is
+	// the unit price converted back at the order's rate.
+	QUERY_GET_ORDER_TIME_SERIES         = "SELECT DATE_TRUNC($1, o.created_at AT TIME ZONE 'UTC' AT TIME ZONE $2) AS bucket, COUNT(*) AS num_of_orders, COALESCE(SUM(CASE WHEN $6 = 0 THEN o.base_total_price ELSE oi.base_amount END), 0) AS revenue, COALESCE(SUM(oi.units), 0) AS units_sold FROM orders AS o JOIN LATERAL (SELECT SUM(quantity) AS units, SUM(ROUND(product_price / exchange_rate, 2) * quantity) AS base_amount FROM order_items WHERE order_id = o.id AND ($6 = 0 OR product_id = $6)) AS oi ON oi.units IS NOT NULL WHERE o.status <> 'cancelled' AND o.created_at >= $3 AND o.created_at < $4 AND ($5 = 0 OR o.user_id = $5) GROUP BY bucket ORDER BY bucket"
+	QUERY_GET_USER_SALES_TIME_SERIES    = "SELECT DATE_TRUNC($1, day::timestamp) AS bucket, SUM(num_of_orders) AS num_of_orders, SUM(revenue) AS revenue, SUM(units_sold) AS units_sold FROM daily_user_sales WHERE day >= $2 AND day < $3 AND ($4 = 0 OR user_id = $4) GROUP BY bucket ORDER BY bucket"
+	QUERY_GET_PRODUCT_SALES_TIME_SERIES = "SELECT DATE_TRUNC($1, day::timestamp) AS bucket, SUM(num_of_orders) AS num_of_orders, SUM(revenue) AS revenue, SUM(units_sold) AS units_sold FROM daily_product_sales WHERE day >= $2 AND day < $3 AND product_id = $4 GROUP BY bucket ORDER BY bucket"
+	QUERY_LOCK_DAILY_SALES              = "LOCK TABLE daily_user_sales, daily_product_sales, daily_tax_sales IN EXCLUSIVE MODE"
+	QUERY_DELETE_DAILY_USER_SALES       = "DELETE FROM daily_user_sales WHERE day >= $1 AND day < $2"
+	QUERY_DELETE_DAILY_PRODUCT_SALES    = "DELETE FROM daily_product_sales WHERE day >= $1 AND day < $2"
+	QUERY_DELETE_DAILY_TAX_SALES        = "DELETE FROM daily_tax_sales WHERE day >= $1 AND day < $2"
+	QUERY_REBUILD_DAILY_USER_SALES      = "INSERT INTO daily_user_sales (day, user_id, num_of_orders, revenue, item_price, num_of_items, units_sold, tax) SELECT (o.created_at::timestamptz AT TIME ZONE 'UTC')::date AS day, o.user_id, COUNT(*), SUM(o.base_total_price), SUM(i.item_price), SUM(i.num_of_items), SUM(i.units_sold), SUM(COALESCE(t.tax, 0)) FROM orders AS o CROSS JOIN LATERAL (SELECT COALESCE(SUM(ROUND(product_price / exchange_rate, 2)), 0) AS item_price, COUNT(*) AS num_of_items, COALESCE(SUM(quantity), 0) AS units_sold FROM order_items WHERE order_id = o.id) AS i CROSS JOIN LATERAL (SELECT SUM(base_amount) AS tax FROM order_taxes WHERE order_id = o.id) AS t WHERE o.status <> 'cancelled' AND o.created_at::timestamptz >= $1 AND o.created_at::timestamptz < $2 GROUP BY day, o.user_id"
+	QUERY_REBUILD_DAILY_PRODUCT_SALES   = "INSERT INTO daily_product_sales (day, product_id, num_of_orders, units_sold, revenue) SELECT (o.created_at::timestamptz AT TIME ZONE 'UTC')::date AS day, oi.product_id, COUNT(DISTINCT o.id), SUM(oi.quantity), SUM(ROUND(oi.product_price / oi.exchange_rate, 2) * oi.quantity) FROM orders AS o JOIN order_items AS oi ON oi.order_id = o.id WHERE o.status <> 'cancelled' AND o.created_at::timestamptz >= $1 AND o.created_at::timestamptz < $2 GROUP BY day, oi.product_id"
+	QUERY_REBUILD_DAILY_TAX_SALES       = "INSERT INTO daily_tax_sales (day, name, rate, inclusive, taxable_amount, amount) SELECT (o.created_at::timestamptz AT TIME ZONE 'UTC')::date AS day, ot.name, ot.rate, ot.inclusive, SUM(ot.base_taxable_amount), SUM(ot.base_amount) FROM orders AS o JOIN order_taxes AS ot ON ot.order_id = o.id WHERE o.status <> 'cancelled' AND o.created_at::timestamptz >= $1 AND o.created_at::timestamptz < $2 GROUP BY day, ot.name, ot.rate, ot.inclusive"
+	QUERY_PRODUCTS_LEADERBOARD          = "SELECT p.id, p.name, 0 AS user_id, SUM(s.num_of_orders) AS num_of_orders, SUM(s.units_sold) AS units_sold, SUM(s.revenue) AS revenue, MAX(s.day)::timestamp AS last_order_at FROM daily_product_sales AS s JOIN products AS p ON p.id = s.product_id WHERE s.day >= $1 AND s.day < $2 GROUP BY p.id, p.name"
+	QUERY_CUSTOMERS_LEADERBOARD         = "SELECT u.id, u.username AS name, u.id AS user_id, SUM(s.num_of_orders) AS num_of_orders, SUM(s.units_sold) AS units_sold, SUM(s.revenue) AS revenue, MAX(s.day)::timestamp AS last_order_at FROM daily_user_sales AS s JOIN users AS u ON u.id = s.user_id WHERE s.day >= $1 AND s.day < $2 GROUP BY u.id, u.username"
+	QUERY_ORDERS_LEADERBOARD            = "SELECT o.id, u.username AS name, o.user_id, 1 AS num_of_orders, (SELECT COALESCE(SUM(quantity), 0) FROM order_items WHERE order_id = o.id) AS units_sold, o.base_total_price AS revenue, o.created_at AS last_order_at FROM orders AS o JOIN users AS u ON u.id = o.user_id WHERE o.status <> 'cancelled' AND o.created_at::timestamptz >= $1 AND o.created_at::timestamptz < $2"
+)
+
+type postgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAnalyticsRepo(db *pgxpool.Pool) AnalyticsRepository {
+	return &postgresRepo{
+		db,
+	}
+}
+
+func (repo *postgresRepo) GetOrderTimeSeries(ctx context.Context, filter *entity.TimeSeriesFilter) (*[]entity.TimeSeriesPoint, error) {
+	start, end := filter.Range()
+
+	// the rollups are kept per user and per product by UTC day, anything else is added up from the orders
+	var rows pgx.Rows
+	var err error
+
+	switch {
+	case filter.AlignsWithUTCDays() && filter.ProductId == 0:
+		rows, err = repo.db.Query(ctx, QUERY_GET_USER_SALES_TIME_SERIES, string(filter.Granularity), start.UTC(), end.UTC(), filter.UserId)
+	case filter.AlignsWithUTCDays() && filter.UserId == 0:
+		rows, err = repo.db.Query(ctx, QUERY_GET_PRODUCT_SALES_TIME_SERIES, string(filter.Granularity), start.UTC(), end.UTC(), filter.ProductId)
+	default:
+		rows, err = repo.db.Query(ctx, QUERY_GET_ORDER_TIME_SERIES, string(filter.Granularity), filter.Location.String(), start.UTC(), end.UTC(), filter.UserId, filter.ProductId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.TimeSeriesPoint, error) {
+		var bucket time.Time
+		var numOfOrders, unitsSold int
+		var revenue core.Money
+
+		err := row.Scan(&bucket, &numOfOrders, &revenue, &unitsSold)
+		if err != nil {
+			return entity.TimeSeriesPoint{}, err
+		}
+
+		// the bucket comes back as wall clock time of the requested zone
+		bucket = time.Date(bucket.Year(), bucket.Month(), bucket.Day(), bucket.Hour(), bucket.Minute(), bucket.Second(), 0, filter.Location)
+
+		return entity.NewTimeSeriesPoint(bucket, numOfOrders, revenue, unitsSold), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &points, nil
+}
+
+func (repo *postgresRepo) GetLeaderboard(ctx context.Context, filter *entity.LeaderboardFilter) (*[]entity.LeaderboardEntry, error) {
+	start, end := filter.Range()
+
+	rows, err := repo.db.Query(ctx, buildLeaderboardQuery(filter), start, end, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.LeaderboardEntry, error) {
+		var entry entity.LeaderboardEntry
+
+		err := row.Scan(&entry.Id, &entry.Name, &entry.UserId, &entry.NumOfOrders, &entry.UnitsSold, &entry.Revenue)
+		if err != nil {
+			return entity.LeaderboardEntry{}, err
+		}
+
+		return entry, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entries, nil
+}
+
+// leaderboardColumns maps a metric to the column of the leaderboard queries it is read from.
+var leaderboardColumns = map[entity.LeaderboardMetric]string{
+	entity.MetricRevenue: "revenue",
+	entity.MetricUnits:   "units_sold",
+	entity.MetricSpend:   "revenue",
+	entity.MetricOrders:  "num_of_orders",
+	entity.MetricValue:   "revenue",
+}
+
+// buildLeaderboardQuery ranks the rows of the leaderboard's query by the metric then the tie break, the lowest
+// id settles anything left so a leaderboard never changes between two identical calls. Products and customers
+// are added up from the daily sales rollups, orders are read as they are.
+func buildLeaderboardQuery(filter *entity.LeaderboardFilter) string {
+	source := QUERY_PRODUCTS_LEADERBOARD
+	switch filter.Kind {
+	case entity.LeaderboardCustomers:
+		source = QUERY_CUSTOMERS_LEADERBOARD
+	case entity.LeaderboardOrders:
+		source = QUERY_ORDERS_LEADERBOARD
+	}
+
+	orderBy := fmt.Sprintf("%s DESC", leaderboardColumns[filter.Metric])
+	switch filter.TieBreak {
+	case entity.TieBreakSecondary:
+		orderBy += fmt.Sprintf(", %s DESC", leaderboardColumns[filter.Metric.Secondary()])
+	case entity.TieBreakRecent:
+		orderBy += ", last_order_at DESC"
+	}
+
+	return fmt.Sprintf("SELECT id, name, user_id, num_of_orders, units_sold, revenue FROM (%s) AS board ORDER BY %s, id ASC LIMIT $3", source, orderBy)
+}
+
+func (repo *postgresRepo) RebuildDailySales(ctx context.Context, startDate, endDate time.Time) (*entity.DailySalesRebuild, error) {
+	rebuild := entity.DailySalesRebuild{
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	start := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(endDate.Year(), endDate.Month(), endDate.Day()+1, 0, 0, 0, 0, time.UTC)
+
+	err := pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		// orders adding themselves to the rollups hold them until they commit, so once the lock is taken every
+		// order written so far is visible here and the ones still to come are added on top of the rebuild
+		_, err := tx.Exec(ctx, QUERY_LOCK_DAILY_SALES)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_DELETE_DAILY_USER_SALES, start, end)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_DELETE_DAILY_PRODUCT_SALES, start, end)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_DELETE_DAILY_TAX_SALES, start, end)
+		if err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, QUERY_REBUILD_DAILY_USER_SALES, start, end)
+		if err != nil {
+			return err
+		}
+		rebuild.UserRows = int(tag.RowsAffected())
+
+		tag, err = tx.Exec(ctx, QUERY_REBUILD_DAILY_PRODUCT_SALES, start, end)
+		if err != nil {
+			return err
+		}
+		rebuild.ProductRows = int(tag.RowsAffected())
+
+		tag, err = tx.Exec(ctx, QUERY_REBUILD_DAILY_TAX_SALES, start, end)
+		if err != nil {
+			return err
+		}
+		rebuild.TaxRows = int(tag.RowsAffected())
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rebuild, nil
+}
@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"order_service/internal/core"
+	"order_service/services/analytics/entity"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type LeaderboardCache interface {
+	GetLeaderboard(ctx context.Context, key string) (*entity.Leaderboard, error)
+	SetLeaderboard(ctx context.Context, key string, board entity.Leaderboard, expiration int) error
+}
+
+type redisRepo struct {
+	db *redis.Client
+}
+
+func NewLeaderboardCache(db *redis.Client) LeaderboardCache {
+	return &redisRepo{
+		db,
+	}
+}
+
+func (repo *redisRepo) GetLeaderboard(ctx context.Context, key string) (*entity.Leaderboard, error) {
+	redisKey := fmt.Sprintf("leaderboard:%s", key)
+
+	value, err := repo.db.Get(ctx, redisKey).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, core.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	var board entity.Leaderboard
+
+	err = json.Unmarshal(value, &board)
+	if err != nil {
+		return nil, err
+	}
+
+	return &board, nil
+}
+
+func (repo *redisRepo) SetLeaderboard(ctx context.Context, key string, board entity.Leaderboard, expiration int) error {
+	redisKey := fmt.Sprintf("leaderboard:%s", key)
+
+	value, err := json.Marshal(board)
+	if err != nil {
+		return err
+	}
+
+	return repo.db.Set(ctx, redisKey, value, time.Second*time.Duration(expiration)).Err()
+}
@@ -0,0 +1,318 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	"order_service/services/analytics/entity"
+	"order_service/services/analytics/test/mock"
+	"order_service/services/analytics/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type AnalyticsUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo  *mock.MockAnalyticsRepository
+	mockCache *mock.MockLeaderboardCache
+	usecase   usecase.AnalyticsUsecase
+	jakarta   *time.Location
+	adminCtx  context.Context
+	memberCtx context.Context
+}
+
+func (suite *AnalyticsUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.jakarta, _ = time.LoadLocation("Asia/Jakarta")
+	suite.mockRepo = mock.NewMockAnalyticsRepository(ctrl)
+	suite.mockCache = mock.NewMockLeaderboardCache(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, suite.mockCache, suite.jakarta, 300)
+	suite.adminCtx = requesterContext(1, 1)
+	suite.memberCtx = requesterContext(2, 0)
+}
+
+func (suite *AnalyticsUsecaseTestSuite) TestGetOrderTimeSeries() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	farEnd := start.AddDate(5, 0, 0)
+	repoErr := errors.New("connection reset")
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		filter     entity.TimeSeriesFilter
+		callRepo   bool
+		points     []entity.TimeSeriesPoint
+		repoErr    error
+		wantUser   int
+		wantPoints []entity.TimeSeriesPoint
+		wantErr    error
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "Admin gets every order with empty days zero filled",
+			ctx:      suite.adminCtx,
+			filter:   entity.TimeSeriesFilter{Granularity: entity.GranularityDay, StartDate: &start, EndDate: &end},
+			callRepo: true,
+			points: []entity.TimeSeriesPoint{
+				entity.NewTimeSeriesPoint(time.Date(2024, 1, 2, 0, 0, 0, 0, suite.jakarta), 2, core.NewMoney(3001), 5),
+			},
+			wantPoints: []entity.TimeSeriesPoint{
+				entity.NewTimeSeriesPoint(time.Date(2024, 1, 1, 0, 0, 0, 0, suite.jakarta), 0, 0, 0),
+				{Bucket: time.Date(2024, 1, 2, 0, 0, 0, 0, suite.jakarta), NumOfOrders: 2, Revenue: core.NewMoney(3001), AverageOrderValue: core.NewMoney(1501), UnitsSold: 5},
+				entity.NewTimeSeriesPoint(time.Date(2024, 1, 3, 0, 0, 0, 0, suite.jakarta), 0, 0, 0),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:     "Admin narrows the series down to a user",
+			ctx:      suite.adminCtx,
+			filter:   entity.TimeSeriesFilter{Granularity: entity.GranularityDay, StartDate: &start, EndDate: &start, UserId: 7},
+			callRepo: true,
+			wantUser: 7,
+			wantPoints: []entity.TimeSeriesPoint{
+				entity.NewTimeSeriesPoint(time.Date(2024, 1, 1, 0, 0, 0, 0, suite.jakarta), 0, 0, 0),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:     "Member only gets own orders",
+			ctx:      suite.memberCtx,
+			filter:   entity.TimeSeriesFilter{Granularity: entity.GranularityDay, StartDate: &start, EndDate: &start, UserId: 7},
+			callRepo: true,
+			wantUser: 2,
+			wantPoints: []entity.TimeSeriesPoint{
+				entity.NewTimeSeriesPoint(time.Date(2024, 1, 1, 0, 0, 0, 0, suite.jakarta), 0, 0, 0),
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Too many buckets",
+			ctx:       suite.adminCtx,
+			filter:    entity.TimeSeriesFilter{Granularity: entity.GranularityDay, StartDate: &start, EndDate: &farEnd},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrTooManyBuckets.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repository error",
+			ctx:       suite.adminCtx,
+			filter:    entity.TimeSeriesFilter{Granularity: entity.GranularityDay, StartDate: &start, EndDate: &end},
+			callRepo:  true,
+			repoErr:   repoErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotGetAnalytics.Error()).WithDebug(repoErr.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().GetOrderTimeSeries(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, filter *entity.TimeSeriesFilter) (*[]entity.TimeSeriesPoint, error) {
+					suite.Equal(tt.wantUser, filter.UserId, "user filter should be enforced")
+					suite.Equal(suite.jakarta, filter.Location, "dates should be read in the default timezone")
+
+					return &tt.points, tt.repoErr
+				})
+			}
+
+			series, err := suite.usecase.GetOrderTimeSeries(tt.ctx, &tt.filter)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal("Asia/Jakarta", series.Timezone, "timezone should be returned")
+				suite.Equal(tt.wantPoints, series.Points, "points should be zero filled")
+			}
+		})
+	}
+}
+
+func (suite *AnalyticsUsecaseTestSuite) TestRebuildDailySales() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	repoErr := errors.New("lock timeout")
+
+	tests := []struct {
+		name      string
+		start     time.Time
+		end       time.Time
+		callRepo  bool
+		repoErr   error
+		want      *entity.DailySalesRebuild
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Rebuild a month",
+			start:     start,
+			end:       end,
+			callRepo:  true,
+			want:      &entity.DailySalesRebuild{StartDate: start, EndDate: end, UserRows: 12, ProductRows: 30, TaxRows: 4},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Start after end",
+			start:     end,
+			end:       start,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrInvalidDateRange.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repository error",
+			start:     start,
+			end:       end,
+			callRepo:  true,
+			repoErr:   repoErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotRebuildSales.Error()).WithDebug(repoErr.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().RebuildDailySales(gomock.Any(), tt.start, tt.end).Return(tt.want, tt.repoErr)
+			}
+
+			rebuild, err := suite.usecase.RebuildDailySales(context.Background(), tt.start, tt.end)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(tt.want, rebuild, "rebuild should be returned")
+			}
+		})
+	}
+}
+
+func (suite *AnalyticsUsecaseTestSuite) TestGetLeaderboard() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	entries := []entity.LeaderboardEntry{
+		{Id: 3, Name: "Keyboard", NumOfOrders: 4, UnitsSold: 6, Revenue: core.NewMoney(60000)},
+		{Id: 1, Name: "Mouse", NumOfOrders: 9, UnitsSold: 12, Revenue: core.NewMoney(24000)},
+	}
+	cached := entity.Leaderboard{Kind: entity.LeaderboardProducts, Metric: entity.MetricRevenue, Window: entity.Window7Days}
+	repoErr := errors.New("connection reset")
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		filter    entity.LeaderboardFilter
+		cached    *entity.Leaderboard
+		cacheErr  error
+		callRepo  bool
+		repoErr   error
+		wantCache bool
+		wantRanks []int
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Window is served from cache",
+			ctx:       suite.adminCtx,
+			filter:    entity.LeaderboardFilter{Kind: entity.LeaderboardProducts, Metric: entity.MetricRevenue, TieBreak: entity.TieBreakSecondary, Limit: 10, Window: entity.Window7Days},
+			cached:    &cached,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Window missing from cache is ranked and cached",
+			ctx:       suite.adminCtx,
+			filter:    entity.LeaderboardFilter{Kind: entity.LeaderboardProducts, Metric: entity.MetricRevenue, TieBreak: entity.TieBreakSecondary, Limit: 10, Window: entity.Window7Days},
+			cacheErr:  core.ErrRecordNotFound,
+			callRepo:  true,
+			wantCache: true,
+			wantRanks: []int{1, 2},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Unreachable cache falls back to the database",
+			ctx:       suite.adminCtx,
+			filter:    entity.LeaderboardFilter{Kind: entity.LeaderboardProducts, Metric: entity.MetricRevenue, TieBreak: entity.TieBreakSecondary, Limit: 10, Window: entity.Window7Days},
+			cacheErr:  errors.New("connection refused"),
+			callRepo:  true,
+			wantCache: true,
+			wantRanks: []int{1, 2},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Date range is not cached",
+			ctx:       suite.adminCtx,
+			filter:    entity.LeaderboardFilter{Kind: entity.LeaderboardProducts, Metric: entity.MetricUnits, TieBreak: entity.TieBreakId, Limit: 2, StartDate: &start, EndDate: &end},
+			callRepo:  true,
+			wantRanks: []int{1, 2},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Member cannot get leaderboards",
+			ctx:       suite.memberCtx,
+			filter:    entity.LeaderboardFilter{Kind: entity.LeaderboardCustomers, Metric: entity.MetricSpend, TieBreak: entity.TieBreakSecondary, Limit: 10},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrCannotGetLeaderboard.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repository error",
+			ctx:       suite.adminCtx,
+			filter:    entity.LeaderboardFilter{Kind: entity.LeaderboardOrders, Metric: entity.MetricValue, TieBreak: entity.TieBreakRecent, Limit: 10, StartDate: &start, EndDate: &end},
+			callRepo:  true,
+			repoErr:   repoErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotGetLeaderboard.Error()).WithDebug(repoErr.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.cached != nil || tt.cacheErr != nil {
+				suite.mockCache.EXPECT().GetLeaderboard(gomock.Any(), gomock.Any()).Return(tt.cached, tt.cacheErr)
+			}
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().GetLeaderboard(gomock.Any(), gomock.Any()).Return(&entries, tt.repoErr)
+			}
+			if tt.wantCache {
+				suite.mockCache.EXPECT().SetLeaderboard(gomock.Any(), gomock.Any(), gomock.Any(), 300).Return(nil)
+			}
+
+			board, err := suite.usecase.GetLeaderboard(tt.ctx, &tt.filter)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				if tt.cached != nil {
+					suite.Equal(tt.cached, board, "cached leaderboard should be returned")
+					return
+				}
+
+				ranks := make([]int, 0, len(board.Entries))
+				for _, entry := range board.Entries {
+					ranks = append(ranks, entry.Rank)
+				}
+				suite.Equal(tt.wantRanks, ranks, "entries should be ranked in order")
+			}
+		})
+	}
+}
+
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
+
+func TestAnalyticsUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AnalyticsUsecaseTestSuite))
+}
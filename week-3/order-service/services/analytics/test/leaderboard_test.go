@@ -0,0 +1,93 @@
+package test
+
+import (
+	"order_service/services/analytics/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type LeaderboardTestSuite struct {
+	suite.Suite
+}
+
+func (suite *LeaderboardTestSuite) TestToFilter() {
+	tests := []struct {
+		name         string
+		data         entity.LeaderboardRequest
+		wantMetric   entity.LeaderboardMetric
+		wantTieBreak entity.TieBreak
+		wantLimit    int
+		wantErr      error
+		assertion    assert.ErrorAssertionFunc
+	}{
+		{name: "Products by revenue by default", data: entity.LeaderboardRequest{Kind: "products"}, wantMetric: entity.MetricRevenue, wantTieBreak: entity.TieBreakSecondary, wantLimit: entity.DefaultLeaderboardLimit, assertion: assert.NoError},
+		{name: "Customers by number of orders", data: entity.LeaderboardRequest{Kind: "Customers", Metric: "orders", TieBreak: "recent", Limit: 5}, wantMetric: entity.MetricOrders, wantTieBreak: entity.TieBreakRecent, wantLimit: 5, assertion: assert.NoError},
+		{name: "Orders by value by default", data: entity.LeaderboardRequest{Kind: "orders", Window: "90d"}, wantMetric: entity.MetricValue, wantTieBreak: entity.TieBreakSecondary, wantLimit: entity.DefaultLeaderboardLimit, assertion: assert.NoError},
+		{name: "Unknown leaderboard", data: entity.LeaderboardRequest{Kind: "coupons"}, wantErr: entity.ErrInvalidLeaderboard, assertion: assert.Error},
+		{name: "Metric of another leaderboard", data: entity.LeaderboardRequest{Kind: "products", Metric: "spend"}, wantErr: entity.ErrInvalidMetric, assertion: assert.Error},
+		{name: "Unknown tie break", data: entity.LeaderboardRequest{Kind: "products", TieBreak: "random"}, wantErr: entity.ErrInvalidTieBreak, assertion: assert.Error},
+		{name: "Limit too high", data: entity.LeaderboardRequest{Kind: "products", Limit: entity.MaxLeaderboardLimit + 1}, wantErr: entity.ErrInvalidLimit, assertion: assert.Error},
+		{name: "Unknown window", data: entity.LeaderboardRequest{Kind: "products", Window: "14d"}, wantErr: entity.ErrInvalidWindow, assertion: assert.Error},
+		{name: "Window with dates", data: entity.LeaderboardRequest{Kind: "products", Window: "7d", StartDate: "2024-01-01"}, wantErr: entity.ErrInvalidWindow, assertion: assert.Error},
+		{name: "Ending before it starts", data: entity.LeaderboardRequest{Kind: "products", StartDate: "2024-01-02", EndDate: "2024-01-01"}, wantErr: entity.ErrInvalidDateRange, assertion: assert.Error},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			filter, err := tt.data.ToFilter()
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(tt.wantMetric, filter.Metric, "metric should be set")
+				suite.Equal(tt.wantTieBreak, filter.TieBreak, "tie break should be set")
+				suite.Equal(tt.wantLimit, filter.Limit, "limit should be set")
+			}
+		})
+	}
+}
+
+func (suite *LeaderboardTestSuite) TestApplyDefaults() {
+	now := time.Date(2024, 8, 13, 23, 30, 0, 0, time.UTC)
+
+	filter := entity.LeaderboardFilter{Kind: entity.LeaderboardProducts, Metric: entity.MetricRevenue, TieBreak: entity.TieBreakSecondary, Limit: 10}
+	suite.Require().NoError(filter.ApplyDefaults(now))
+	suite.Equal(entity.Window30Days, filter.Window, "last 30 days should be ranked by default")
+	suite.Equal("2024-07-15", filter.StartDate.Format(time.DateOnly))
+	suite.Equal("2024-08-13", filter.EndDate.Format(time.DateOnly))
+	suite.Equal("products:revenue:secondary:10:30d:2024-08-13", filter.CacheKey(), "window should be cached for the day")
+
+	filter = entity.LeaderboardFilter{Kind: entity.LeaderboardOrders, Window: entity.Window7Days}
+	suite.Require().NoError(filter.ApplyDefaults(now))
+	suite.Equal("2024-08-07", filter.StartDate.Format(time.DateOnly), "window should include today")
+
+	startDate := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	filter = entity.LeaderboardFilter{Kind: entity.LeaderboardCustomers, StartDate: &startDate}
+	suite.Require().NoError(filter.ApplyDefaults(now))
+	suite.Equal("2024-08-13", filter.EndDate.Format(time.DateOnly), "range should end today")
+	suite.Empty(filter.CacheKey(), "date ranges should not be cached")
+
+	startDate = time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	filter = entity.LeaderboardFilter{Kind: entity.LeaderboardCustomers, StartDate: &startDate}
+	suite.ErrorIs(filter.ApplyDefaults(now), entity.ErrInvalidDateRange, "start date cannot be after today")
+}
+
+func (suite *LeaderboardTestSuite) TestNewLeaderboard() {
+	startDate := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC)
+	filter := entity.LeaderboardFilter{Kind: entity.LeaderboardCustomers, Metric: entity.MetricSpend, TieBreak: entity.TieBreakId, StartDate: &startDate, EndDate: &endDate}
+
+	board := entity.NewLeaderboard(&filter, []entity.LeaderboardEntry{{Id: 7, Name: "alice"}, {Id: 2, Name: "bob"}}, endDate)
+
+	suite.Equal("2024-08-01", board.StartDate)
+	suite.Equal("2024-08-31", board.EndDate)
+	suite.Equal([]entity.LeaderboardEntry{{Rank: 1, Id: 7, Name: "alice"}, {Rank: 2, Id: 2, Name: "bob"}}, board.Entries)
+}
+
+func TestLeaderboardTestSuite(t *testing.T) {
+	suite.Run(t, new(LeaderboardTestSuite))
+}
@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/redis/store.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/redis/store.go -destination test/mock/cache.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/analytics/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLeaderboardCache is a mock of LeaderboardCache interface.
+type MockLeaderboardCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaderboardCacheMockRecorder
+}
+
+// MockLeaderboardCacheMockRecorder is the mock recorder for MockLeaderboardCache.
+type MockLeaderboardCacheMockRecorder struct {
+	mock *MockLeaderboardCache
+}
+
+// NewMockLeaderboardCache creates a new mock instance.
+func NewMockLeaderboardCache(ctrl *gomock.Controller) *MockLeaderboardCache {
+	mock := &MockLeaderboardCache{ctrl: ctrl}
+	mock.recorder = &MockLeaderboardCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaderboardCache) EXPECT() *MockLeaderboardCacheMockRecorder {
+	return m.recorder
+}
+
+// GetLeaderboard mocks base method.
+func (m *MockLeaderboardCache) GetLeaderboard(ctx context.Context, key string) (*entity.Leaderboard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboard", ctx, key)
+	ret0, _ := ret[0].(*entity.Leaderboard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboard indicates an expected call of GetLeaderboard.
+func (mr *MockLeaderboardCacheMockRecorder) GetLeaderboard(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboard", reflect.TypeOf((*MockLeaderboardCache)(nil).GetLeaderboard), ctx, key)
+}
+
+// SetLeaderboard mocks base method.
+func (m *MockLeaderboardCache) SetLeaderboard(ctx context.Context, key string, board entity.Leaderboard, expiration int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLeaderboard", ctx, key, board, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLeaderboard indicates an expected call of SetLeaderboard.
+func (mr *MockLeaderboardCacheMockRecorder) SetLeaderboard(ctx, key, board, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLeaderboard", reflect.TypeOf((*MockLeaderboardCache)(nil).SetLeaderboard), ctx, key, board, expiration)
+}
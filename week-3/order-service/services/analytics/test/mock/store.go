@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/postgres/store.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/postgres/store.go -destination test/mock/store.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/analytics/entity"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAnalyticsRepository is a mock of AnalyticsRepository interface.
+type MockAnalyticsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalyticsRepositoryMockRecorder
+}
+
+// MockAnalyticsRepositoryMockRecorder is the mock recorder for MockAnalyticsRepository.
+type MockAnalyticsRepositoryMockRecorder struct {
+	mock *MockAnalyticsRepository
+}
+
+// NewMockAnalyticsRepository creates a new mock instance.
+func NewMockAnalyticsRepository(ctrl *gomock.Controller) *MockAnalyticsRepository {
+	mock := &MockAnalyticsRepository{ctrl: ctrl}
+	mock.recorder = &MockAnalyticsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalyticsRepository) EXPECT() *MockAnalyticsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetLeaderboard mocks base method.
+func (m *MockAnalyticsRepository) GetLeaderboard(ctx context.Context, filter *entity.LeaderboardFilter) (*[]entity.LeaderboardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderboard", ctx, filter)
+	ret0, _ := ret[0].(*[]entity.LeaderboardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderboard indicates an expected call of GetLeaderboard.
+func (mr *MockAnalyticsRepositoryMockRecorder) GetLeaderboard(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderboard", reflect.TypeOf((*MockAnalyticsRepository)(nil).GetLeaderboard), ctx, filter)
+}
+
+// GetOrderTimeSeries mocks base method.
+func (m *MockAnalyticsRepository) GetOrderTimeSeries(ctx context.Context, filter *entity.TimeSeriesFilter) (*[]entity.TimeSeriesPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderTimeSeries", ctx, filter)
+	ret0, _ := ret[0].(*[]entity.TimeSeriesPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderTimeSeries indicates an expected call of GetOrderTimeSeries.
+func (mr *MockAnalyticsRepositoryMockRecorder) GetOrderTimeSeries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderTimeSeries", reflect.TypeOf((*MockAnalyticsRepository)(nil).GetOrderTimeSeries), ctx, filter)
+}
+
+// RebuildDailySales mocks base method.
+func (m *MockAnalyticsRepository) RebuildDailySales(ctx context.Context, startDate, endDate time.Time) (*entity.DailySalesRebuild, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildDailySales", ctx, startDate, endDate)
+	ret0, _ := ret[0].(*entity.DailySalesRebuild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildDailySales indicates an expected call of RebuildDailySales.
+func (mr *MockAnalyticsRepositoryMockRecorder) RebuildDailySales(ctx, startDate, endDate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildDailySales", reflect.TypeOf((*MockAnalyticsRepository)(nil).RebuildDailySales), ctx, startDate, endDate)
+}
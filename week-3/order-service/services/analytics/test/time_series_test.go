@@ -0,0 +1,159 @@
+package test
+
+import (
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/analytics/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TimeSeriesTestSuite struct {
+	suite.Suite
+	jakarta  *time.Location
+	newYork  *time.Location
+	tuesday  time.Time
+	lastDays time.Time
+}
+
+func (suite *TimeSeriesTestSuite) SetupTest() {
+	suite.jakarta, _ = time.LoadLocation("Asia/Jakarta")
+	suite.newYork, _ = time.LoadLocation("America/New_York")
+	suite.tuesday = time.Date(2024, 8, 13, 15, 4, 5, 0, suite.jakarta)
+	suite.lastDays = time.Date(2024, 12, 31, 23, 0, 0, 0, suite.jakarta)
+}
+
+func (suite *TimeSeriesTestSuite) TestTruncate() {
+	tests := []struct {
+		name        string
+		granularity entity.Granularity
+		t           time.Time
+		want        time.Time
+	}{
+		{name: "Day", granularity: entity.GranularityDay, t: suite.tuesday, want: time.Date(2024, 8, 13, 0, 0, 0, 0, suite.jakarta)},
+		{name: "Week starts on Monday", granularity: entity.GranularityWeek, t: suite.tuesday, want: time.Date(2024, 8, 12, 0, 0, 0, 0, suite.jakarta)},
+		{name: "Sunday belongs to the week before", granularity: entity.GranularityWeek, t: time.Date(2024, 8, 18, 1, 0, 0, 0, suite.jakarta), want: time.Date(2024, 8, 12, 0, 0, 0, 0, suite.jakarta)},
+		{name: "Month", granularity: entity.GranularityMonth, t: suite.tuesday, want: time.Date(2024, 8, 1, 0, 0, 0, 0, suite.jakarta)},
+		{name: "Quarter", granularity: entity.GranularityQuarter, t: suite.tuesday, want: time.Date(2024, 7, 1, 0, 0, 0, 0, suite.jakarta)},
+		{name: "Last quarter", granularity: entity.GranularityQuarter, t: suite.lastDays, want: time.Date(2024, 10, 1, 0, 0, 0, 0, suite.jakarta)},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.Equal(tt.want, tt.granularity.Truncate(tt.t), "bucket should start correctly")
+		})
+	}
+}
+
+func (suite *TimeSeriesTestSuite) TestToFilter() {
+	tests := []struct {
+		name      string
+		data      entity.TimeSeriesRequest
+		want      entity.Granularity
+		wantZone  string
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "Day by default", data: entity.TimeSeriesRequest{}, want: entity.GranularityDay, assertion: assert.NoError},
+		{name: "Quarter in Jakarta", data: entity.TimeSeriesRequest{Granularity: "Quarter", Timezone: "Asia/Jakarta"}, want: entity.GranularityQuarter, wantZone: "Asia/Jakarta", assertion: assert.NoError},
+		{name: "Unknown granularity", data: entity.TimeSeriesRequest{Granularity: "year"}, wantErr: entity.ErrInvalidGranularity, assertion: assert.Error},
+		{name: "Unknown timezone", data: entity.TimeSeriesRequest{Timezone: "Mars/Olympus"}, wantErr: pkg.ErrInvalidTimezone, assertion: assert.Error},
+		{name: "Server timezone", data: entity.TimeSeriesRequest{Timezone: "Local"}, wantErr: pkg.ErrInvalidTimezone, assertion: assert.Error},
+		{name: "Not a date", data: entity.TimeSeriesRequest{StartDate: "13/08/2024"}, wantErr: entity.ErrInvalidDateRange, assertion: assert.Error},
+		{name: "Ending before it starts", data: entity.TimeSeriesRequest{StartDate: "2024-08-13", EndDate: "2024-08-12"}, wantErr: entity.ErrInvalidDateRange, assertion: assert.Error},
+		{name: "Negative product", data: entity.TimeSeriesRequest{ProductId: -1}, wantErr: entity.ErrInvalidId, assertion: assert.Error},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			filter, err := tt.data.ToFilter()
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(tt.want, filter.Granularity, "granularity should be set")
+				if tt.wantZone != "" {
+					suite.Equal(tt.wantZone, filter.Location.String(), "timezone should be loaded")
+				} else {
+					suite.Nil(filter.Location, "timezone should be left to the default")
+				}
+			}
+		})
+	}
+}
+
+func (suite *TimeSeriesTestSuite) TestApplyDefaults() {
+	// 2024-08-13 02:00 UTC is already the 13th in Jakarta and still the 12th in New York
+	now := time.Date(2024, 8, 13, 2, 0, 0, 0, time.UTC)
+
+	filter := entity.TimeSeriesFilter{Granularity: entity.GranularityMonth}
+	suite.Require().NoError(filter.ApplyDefaults(suite.jakarta, now))
+	suite.Equal("2024-08-13", filter.EndDate.Format(time.DateOnly), "series should end today")
+	suite.Equal("2022-03-01", filter.StartDate.Format(time.DateOnly), "series should start 30 buckets earlier")
+	suite.Len(filter.Buckets(), entity.DefaultTimeSeriesBuckets)
+
+	filter = entity.TimeSeriesFilter{Granularity: entity.GranularityDay, Location: suite.newYork}
+	suite.Require().NoError(filter.ApplyDefaults(suite.jakarta, now))
+	suite.Equal(suite.newYork, filter.Location, "requested timezone should win")
+	suite.Equal("2024-08-12", filter.EndDate.Format(time.DateOnly), "today should be read in the requested timezone")
+
+	startDate := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	filter = entity.TimeSeriesFilter{Granularity: entity.GranularityDay, StartDate: &startDate}
+	suite.ErrorIs(filter.ApplyDefaults(suite.jakarta, now), entity.ErrInvalidDateRange, "start date cannot be after today")
+}
+
+func (suite *TimeSeriesTestSuite) TestNewTimeSeries() {
+	startDate := time.Date(2024, 8, 10, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC)
+	filter := entity.TimeSeriesFilter{Granularity: entity.GranularityWeek, Location: suite.jakarta, StartDate: &startDate, EndDate: &endDate}
+
+	start, end := filter.Range()
+	suite.Equal(time.Date(2024, 8, 10, 0, 0, 0, 0, suite.jakarta), start, "range should start at midnight")
+	suite.Equal(time.Date(2024, 8, 21, 0, 0, 0, 0, suite.jakarta), end, "range should include the end date")
+
+	series := entity.NewTimeSeries(&filter, []entity.TimeSeriesPoint{
+		entity.NewTimeSeriesPoint(time.Date(2024, 8, 12, 0, 0, 0, 0, suite.jakarta), 3, core.NewMoney(1000), 4),
+	})
+
+	suite.Equal("2024-08-10", series.StartDate)
+	suite.Equal("2024-08-20", series.EndDate)
+	suite.Equal([]entity.TimeSeriesPoint{
+		entity.NewTimeSeriesPoint(time.Date(2024, 8, 5, 0, 0, 0, 0, suite.jakarta), 0, 0, 0),
+		{Bucket: time.Date(2024, 8, 12, 0, 0, 0, 0, suite.jakarta), NumOfOrders: 3, Revenue: core.NewMoney(1000), AverageOrderValue: core.NewMoney(333), UnitsSold: 4},
+		entity.NewTimeSeriesPoint(time.Date(2024, 8, 19, 0, 0, 0, 0, suite.jakarta), 0, 0, 0),
+	}, series.Points)
+}
+
+func (suite *TimeSeriesTestSuite) TestAlignsWithUTCDays() {
+	startDate := time.Date(2024, 8, 10, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		granularity entity.Granularity
+		location    *time.Location
+		want        bool
+	}{
+		{name: "UTC days", granularity: entity.GranularityDay, location: time.UTC, want: true},
+		{name: "UTC weeks", granularity: entity.GranularityWeek, location: time.UTC, want: true},
+		{name: "UTC quarters", granularity: entity.GranularityQuarter, location: time.UTC, want: true},
+		{name: "Days in Jakarta", granularity: entity.GranularityDay, location: suite.jakarta, want: false},
+		{name: "Months in New York", granularity: entity.GranularityMonth, location: suite.newYork, want: false},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			filter := entity.TimeSeriesFilter{Granularity: tt.granularity, Location: tt.location, StartDate: &startDate, EndDate: &endDate}
+
+			suite.Equal(tt.want, filter.AlignsWithUTCDays(), "only UTC buckets should be read from the rollups")
+		})
+	}
+}
+
+func TestTimeSeriesTestSuite(t *testing.T) {
+	suite.Run(t, new(TimeSeriesTestSuite))
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"order_service/internal/core"
+	"order_service/services/analytics/entity"
+	analyticsRepo "order_service/services/analytics/repository/postgres"
+	analyticsCache "order_service/services/analytics/repository/redis"
+	"time"
+)
+
+type AnalyticsUsecase interface {
+	GetOrderTimeSeries(ctx context.Context, filter *entity.TimeSeriesFilter) (*entity.TimeSeries, error)
+	RebuildDailySales(ctx context.Context, startDate, endDate time.Time) (*entity.DailySalesRebuild, error)
+	GetLeaderboard(ctx context.Context, filter *entity.LeaderboardFilter) (*entity.Leaderboard, error)
+}
+
+type analyticsUsecase struct {
+	repo             analyticsRepo.AnalyticsRepository
+	cache            analyticsCache.LeaderboardCache
+	location         *time.Location
+	cacheExpireInSec int
+}
+
+// NewUsecase builds the analytics usecase, dates are read in location unless the client asks for another timezone.
+// Leaderboards of a window are kept in cache for cacheExpireInSec, a nil cache always reads them from the database.
+func NewUsecase(repo analyticsRepo.AnalyticsRepository, cache analyticsCache.LeaderboardCache, location *time.Location, cacheExpireInSec int) AnalyticsUsecase {
+	return &analyticsUsecase{
+		repo,
+		cache,
+		location,
+		cacheExpireInSec,
+	}
+}
+
+func (uc *analyticsUsecase) GetOrderTimeSeries(ctx context.Context, filter *entity.TimeSeriesFilter) (*entity.TimeSeries, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	// customers only ever see their own orders, admin may narrow the series down to one user
+	if uid.GetRole() != 1 {
+		filter.UserId = int(uid.GetLocalID())
+	}
+
+	err = filter.ApplyDefaults(uc.location, time.Now())
+	if err != nil {
+		return nil, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	points, err := uc.repo.GetOrderTimeSeries(ctx, filter)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetAnalytics.Error()).WithDebug(err.Error())
+	}
+
+	series := entity.NewTimeSeries(filter, *points)
+
+	return &series, nil
+}
+
+// RebuildDailySales recomputes the daily sales rollups of the UTC days from startDate to endDate out of the orders.
+func (uc *analyticsUsecase) RebuildDailySales(ctx context.Context, startDate, endDate time.Time) (*entity.DailySalesRebuild, error) {
+	if startDate.After(endDate) {
+		return nil, core.ErrBadRequest.WithError(entity.ErrInvalidDateRange.Error())
+	}
+
+	rebuild, err := uc.repo.RebuildDailySales(ctx, startDate, endDate)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotRebuildSales.Error()).WithDebug(err.Error())
+	}
+
+	return rebuild, nil
+}
+
+func (uc *analyticsUsecase) GetLeaderboard(ctx context.Context, filter *entity.LeaderboardFilter) (*entity.Leaderboard, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+	if uid.GetRole() != 1 {
+		return nil, core.ErrBadRequest.WithError(entity.ErrCannotGetLeaderboard.Error())
+	}
+
+	now := time.Now()
+
+	err = filter.ApplyDefaults(now)
+	if err != nil {
+		return nil, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	// a cache failure only costs a trip to the database
+	key := filter.CacheKey()
+	if key != "" && uc.cache != nil {
+		board, err := uc.cache.GetLeaderboard(ctx, key)
+		if err == nil {
+			return board, nil
+		}
+		if err != core.ErrRecordNotFound {
+			log.Println("get cached leaderboard error:", err)
+		}
+	}
+
+	entries, err := uc.repo.GetLeaderboard(ctx, filter)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetLeaderboard.Error()).WithDebug(err.Error())
+	}
+
+	board := entity.NewLeaderboard(filter, *entries, now)
+
+	if key != "" && uc.cache != nil {
+		err = uc.cache.SetLeaderboard(ctx, key, board, uc.cacheExpireInSec)
+		if err != nil {
+			log.Println("cache leaderboard error:", err)
+		}
+	}
+
+	return &board, nil
+}
@@ -232,7 +232,7 @@ func (suite *AuthUsecaseTestSuite) TestLogin() {
 					Id:        1,
 					Username:  "partridge1307",
 					Password:  "hashed-password",
-					Balance:   10.0,
+					Balance:   core.NewMoney(1000),
 					CreatedAt: time.Now(),
 				},
 			},
@@ -271,7 +271,7 @@ func (suite *AuthUsecaseTestSuite) TestLogin() {
 					Id:        1,
 					Username:  "partridge1307",
 					Password:  "hashed-password",
-					Balance:   10.0,
+					Balance:   core.NewMoney(1000),
 					CreatedAt: time.Now(),
 				},
 			},
@@ -307,7 +307,7 @@ func (suite *AuthUsecaseTestSuite) TestLogin() {
 					Id:        1,
 					Username:  "partridge1307",
 					Password:  "hashed-password",
-					Balance:   10.0,
+					Balance:   core.NewMoney(1000),
 					CreatedAt: time.Now(),
 				},
 			},
@@ -343,7 +343,7 @@ func (suite *AuthUsecaseTestSuite) TestLogin() {
 					Id:        1,
 					Username:  "partridge1307",
 					Password:  "hashed-password",
-					Balance:   10.0,
+					Balance:   core.NewMoney(1000),
 					CreatedAt: time.Now(),
 				},
 			},
@@ -379,7 +379,7 @@ func (suite *AuthUsecaseTestSuite) TestLogin() {
 					Id:        1,
 					Username:  "partridge1307",
 					Password:  "hashed-password",
-					Balance:   10.0,
+					Balance:   core.NewMoney(1000),
 					CreatedAt: time.Now(),
 				},
 			},
@@ -415,7 +415,7 @@ func (suite *AuthUsecaseTestSuite) TestLogin() {
 					Id:        1,
 					Username:  "partridge1307",
 					Password:  "hashed-password",
-					Balance:   10.0,
+					Balance:   core.NewMoney(1000),
 					CreatedAt: time.Now(),
 				},
 			},
@@ -451,7 +451,7 @@ func (suite *AuthUsecaseTestSuite) TestLogin() {
 					Id:        1,
 					Username:  "partridge1307",
 					Password:  "hashed-password",
-					Balance:   10.0,
+					Balance:   core.NewMoney(1000),
 					CreatedAt: time.Now(),
 				},
 			},
@@ -487,7 +487,7 @@ func (suite *AuthUsecaseTestSuite) TestLogin() {
 					Id:        1,
 					Username:  "partridge1307",
 					Password:  "hashed-password",
-					Balance:   10.0,
+					Balance:   core.NewMoney(1000),
 					CreatedAt: time.Now(),
 				},
 			},
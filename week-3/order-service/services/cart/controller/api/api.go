@@ -0,0 +1,237 @@
+package api
+
+import (
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/cart/entity"
+	cartUc "order_service/services/cart/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CartService interface {
+	GetCart(*fiber.Ctx) error
+	AddItem(*fiber.Ctx) error
+	UpdateItem(*fiber.Ctx) error
+	RemoveItem(*fiber.Ctx) error
+	ClearCart(*fiber.Ctx) error
+	Checkout(*fiber.Ctx) error
+}
+
+type service struct {
+	usecase cartUc.CartUsecase
+}
+
+func NewService(uc cartUc.CartUsecase) CartService {
+	return &service{
+		usecase: uc,
+	}
+}
+
+// Get Cart godoc
+// @summary Get Cart
+// @description Get the cart of the current user, priced with the live price and stock of every product
+// @tags cart
+// @produce json
+// @security BearerAuth
+// @success 200 {object} entity.Cart
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /cart/ [get]
+func (srv *service) GetCart(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	cart, err := srv.usecase.GetCart(ctx)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(cart))
+}
+
+// Add Cart Item godoc
+// @summary Add Cart Item
+// @description Add a quantity of a product to the cart of the current user
+// @tags cart
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param payload body entity.CartItemRequest true "Add cart item request body"
+// @success 200 {object} entity.Cart
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 422 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /cart/items [post]
+func (srv *service) AddItem(c *fiber.Ctx) error {
+	var data entity.CartItemRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	cart, err := srv.usecase.AddItem(ctx, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(cart))
+}
+
+// Update Cart Item godoc
+// @summary Update Cart Item
+// @description Replace the quantity of a product that is already in the cart of the current user
+// @tags cart
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param productID path int true "Product's ID"
+// @param payload body entity.CartQuantityRequest true "Update cart item request body"
+// @success 200 {object} entity.Cart
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /cart/items/:productID [patch]
+func (srv *service) UpdateItem(c *fiber.Ctx) error {
+	productId, err := c.ParamsInt("productID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidProductId.Error()).WithDebug(err.Error()))
+	}
+
+	var data entity.CartQuantityRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	cart, err := srv.usecase.UpdateItem(ctx, productId, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(cart))
+}
+
+// Remove Cart Item godoc
+// @summary Remove Cart Item
+// @description Remove a product from the cart of the current user
+// @tags cart
+// @produce json
+// @security BearerAuth
+// @param productID path int true "Product's ID"
+// @success 200 {object} entity.Cart
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /cart/items/:productID [delete]
+func (srv *service) RemoveItem(c *fiber.Ctx) error {
+	productId, err := c.ParamsInt("productID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidProductId.Error()).WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	cart, err := srv.usecase.RemoveItem(ctx, productId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(cart))
+}
+
+// Clear Cart godoc
+// @summary Clear Cart
+// @description Remove every product from the cart of the current user
+// @tags cart
+// @security BearerAuth
+// @success 200
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /cart/ [delete]
+func (srv *service) ClearCart(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err := srv.usecase.ClearCart(ctx)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
+
+// Checkout Cart godoc
+// @summary Checkout Cart
+// @description Place an order with the products of the cart, they leave the cart once the order is placed
+// @tags cart
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param Idempotency-Key header string false "Key to safely retry the request without applying it twice"
+// @param payload body entity.CheckoutRequest false "Checkout request body"
+// @success 201 {object} entity.Order
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 422 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /cart/checkout [post]
+func (srv *service) Checkout(c *fiber.Ctx) error {
+	var data entity.CheckoutRequest
+
+	// every field is optional, so is the body
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&data); err != nil {
+			return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+		}
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	order, err := srv.usecase.Checkout(ctx, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(core.ResponseData(order))
+}
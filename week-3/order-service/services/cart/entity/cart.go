@@ -0,0 +1,98 @@
+package entity
+
+import "order_service/internal/core"
+
+const (
+	// MaxCartItems is how many different products a cart can hold.
+	MaxCartItems = 50
+	// MaxItemQuantity is the largest quantity of a single product in a cart.
+	MaxItemQuantity = 1000
+)
+
+// CartItem is what a cart stores for a product, prices and stock are looked up again on every read.
+type CartItem struct {
+	ProductId int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// CartLine is a cart item checked against the live product. Available is false when the product
+// was deleted or does not have enough stock left for the quantity in the cart.
+type CartLine struct {
+	ProductName string     `json:"product_name"`
+	ProductId   int        `json:"product_id"`
+	Quantity    int        `json:"quantity"`
+	Stock       int        `json:"stock"`
+	UnitPrice   core.Money `json:"unit_price" swaggertype:"number"`
+	LineTotal   core.Money `json:"line_total" swaggertype:"number"`
+	Available   bool       `json:"available"`
+}
+
+// Cart is priced in the base currency, the order placed at checkout is converted like any other order.
+type Cart struct {
+	Items     []CartLine `json:"items"`
+	UserId    int        `json:"user_id"`
+	Subtotal  core.Money `json:"subtotal" swaggertype:"number"`
+	Available bool       `json:"available"`
+}
+
+func NewCartItem(productId, quantity int) CartItem {
+	return CartItem{
+		ProductId: productId,
+		Quantity:  quantity,
+	}
+}
+
+func NewCart(userId int) Cart {
+	return Cart{
+		Items:     []CartLine{},
+		UserId:    userId,
+		Available: true,
+	}
+}
+
+func (item CartItem) GetProductId() int {
+	return item.ProductId
+}
+
+func (item CartItem) GetQuantity() int {
+	return item.Quantity
+}
+
+// AddLine adds a line to the cart, only available lines count towards the subtotal.
+func (cart *Cart) AddLine(line CartLine) {
+	if cart == nil {
+		return
+	}
+
+	cart.Items = append(cart.Items, line)
+
+	if line.Available {
+		cart.Subtotal = cart.Subtotal.Add(line.LineTotal)
+	} else {
+		cart.Available = false
+	}
+}
+
+func (cart *Cart) GetItemsSafe() []CartLine {
+	if cart == nil {
+		return []CartLine{}
+	}
+
+	return cart.Items
+}
+
+func (cart *Cart) GetSubtotalSafe() core.Money {
+	if cart == nil {
+		return 0
+	}
+
+	return cart.Subtotal
+}
+
+func (cart *Cart) IsAvailable() bool {
+	if cart == nil {
+		return false
+	}
+
+	return cart.Available
+}
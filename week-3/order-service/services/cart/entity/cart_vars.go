@@ -0,0 +1,49 @@
+package entity
+
+import addressEntity "order_service/services/address/entity"
+
+type CartItemRequest struct {
+	ProductId int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+type CartQuantityRequest struct {
+	Quantity int `json:"quantity"`
+}
+
+type CheckoutRequest struct {
+	// Currency is optional, the user's preferred currency or the base currency is used when empty
+	Currency string `json:"currency"`
+	// Coupons are optional coupon codes, applied in the given order
+	Coupons []string `json:"coupons"`
+	// Region is where the order is taxed, e.g. US-CA, the configured default region is used when empty
+	Region string `json:"region"`
+	// AddressId is a saved address to ship to, the default address is used when neither it nor ShippingAddress is given
+	AddressId int `json:"address_id"`
+	// ShippingAddress is an address to ship to that is not saved in the address book
+	ShippingAddress *addressEntity.AddressRequest `json:"shipping_address" swaggertype:"object"`
+	// PaymentMethod is wallet or card, the wallet balance is charged when empty
+	PaymentMethod string `json:"payment_method" example:"wallet"`
+	// CardToken is the token of the card to charge, only for card payments
+	CardToken string `json:"card_token"`
+}
+
+func (data CartItemRequest) Validate() error {
+	if data.ProductId < 1 {
+		return ErrInvalidProductId
+	}
+
+	return validateQuantity(data.Quantity)
+}
+
+func (data CartQuantityRequest) Validate() error {
+	return validateQuantity(data.Quantity)
+}
+
+func validateQuantity(quantity int) error {
+	if quantity < 1 || quantity > MaxItemQuantity {
+		return ErrInvalidQuantity
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "errors"
+
+var (
+	ErrInvalidProductId   = errors.New("product id is not valid")
+	ErrInvalidQuantity    = errors.New("quantity must be between 1 and 1000")
+	ErrProductNotFound    = errors.New("product does not exist")
+	ErrCartItemNotFound   = errors.New("product is not in the cart")
+	ErrCartFull           = errors.New("cart cannot hold more than 50 different products")
+	ErrCartEmpty          = errors.New("cart is empty")
+	ErrCannotGetCart      = errors.New("cannot get cart")
+	ErrCannotUpdateCart   = errors.New("cannot update cart")
+	ErrCannotCheckoutCart = errors.New("cannot check out cart")
+)
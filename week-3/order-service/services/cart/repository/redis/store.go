@@ -0,0 +1,166 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"order_service/internal/core"
+	"order_service/services/cart/entity"
+	"sort"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type CartRepository interface {
+	GetItems(ctx context.Context, userId int) (*[]entity.CartItem, error)
+	AddItem(ctx context.Context, userId int, item entity.CartItem, expiration int) (int, error)
+	SetItem(ctx context.Context, userId int, item entity.CartItem, expiration int) error
+	RemoveItem(ctx context.Context, userId, productId int) error
+	RemoveItems(ctx context.Context, userId int, items []entity.CartItem) error
+	ClearItems(ctx context.Context, userId int) error
+}
+
+// a cart is a hash of product id to quantity, scripts keep the checks and the writes atomic
+var (
+	// KEYS[1] cart, ARGV product id, quantity to add, max items, max quantity, expiration
+	scriptAddItem = redis.NewScript(`
+local current = tonumber(redis.call('HGET', KEYS[1], ARGV[1]) or '0')
+if current == 0 and redis.call('HLEN', KEYS[1]) >= tonumber(ARGV[3]) then
+	return -1
+end
+if current + tonumber(ARGV[2]) > tonumber(ARGV[4]) then
+	return -2
+end
+local quantity = redis.call('HINCRBY', KEYS[1], ARGV[1], ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[5])
+return quantity
+`)
+
+	// KEYS[1] cart, ARGV product id, quantity, expiration
+	scriptSetItem = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+	// KEYS[1] cart, ARGV pairs of product id and quantity to take out
+	scriptRemoveItems = redis.NewScript(`
+for i = 1, #ARGV, 2 do
+	local quantity = redis.call('HINCRBY', KEYS[1], ARGV[i], -tonumber(ARGV[i + 1]))
+	if quantity <= 0 then
+		redis.call('HDEL', KEYS[1], ARGV[i])
+	end
+end
+return 1
+`)
+)
+
+type redisRepo struct {
+	db *redis.Client
+}
+
+func NewCartRepo(db *redis.Client) CartRepository {
+	return &redisRepo{
+		db,
+	}
+}
+
+func cartKey(userId int) string {
+	return fmt.Sprintf("cart:%d", userId)
+}
+
+// GetItems returns the items of the cart ordered by product id, an expired cart is simply empty.
+func (repo *redisRepo) GetItems(ctx context.Context, userId int) (*[]entity.CartItem, error) {
+	values, err := repo.db.HGetAll(ctx, cartKey(userId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]entity.CartItem, 0, len(values))
+
+	for field, value := range values {
+		productId, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+
+		quantity, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, entity.NewCartItem(productId, quantity))
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ProductId < items[j].ProductId
+	})
+
+	return &items, nil
+}
+
+// AddItem adds the quantity to what the cart already holds and returns the new quantity.
+func (repo *redisRepo) AddItem(ctx context.Context, userId int, item entity.CartItem, expiration int) (int, error) {
+	quantity, err := scriptAddItem.Run(ctx, repo.db, []string{cartKey(userId)}, item.GetProductId(), item.GetQuantity(), entity.MaxCartItems, entity.MaxItemQuantity, expiration).Int()
+	if err != nil {
+		return 0, err
+	}
+
+	switch quantity {
+	case -1:
+		return 0, entity.ErrCartFull
+	case -2:
+		return 0, entity.ErrInvalidQuantity
+	}
+
+	return quantity, nil
+}
+
+// SetItem replaces the quantity of a product that is already in the cart.
+func (repo *redisRepo) SetItem(ctx context.Context, userId int, item entity.CartItem, expiration int) error {
+	updated, err := scriptSetItem.Run(ctx, repo.db, []string{cartKey(userId)}, item.GetProductId(), item.GetQuantity(), expiration).Int()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return core.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (repo *redisRepo) RemoveItem(ctx context.Context, userId, productId int) error {
+	removed, err := repo.db.HDel(ctx, cartKey(userId), strconv.Itoa(productId)).Result()
+	if err != nil {
+		return err
+	}
+
+	if removed == 0 {
+		return core.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// RemoveItems takes the quantities out of the cart instead of dropping the whole cart, so that items
+// added while an order was being placed are kept.
+func (repo *redisRepo) RemoveItems(ctx context.Context, userId int, items []entity.CartItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(items)*2)
+	for _, item := range items {
+		args = append(args, item.GetProductId(), item.GetQuantity())
+	}
+
+	return scriptRemoveItems.Run(ctx, repo.db, []string{cartKey(userId)}, args...).Err()
+}
+
+func (repo *redisRepo) ClearItems(ctx context.Context, userId int) error {
+	return repo.db.Del(ctx, cartKey(userId)).Err()
+}
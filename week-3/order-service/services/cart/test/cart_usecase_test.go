@@ -0,0 +1,272 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	addressEntity "order_service/services/address/entity"
+	"order_service/services/cart/entity"
+	"order_service/services/cart/test/mock"
+	"order_service/services/cart/usecase"
+	orderEntity "order_service/services/order/entity"
+	productEntity "order_service/services/product/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type CartUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo    *mock.MockCartRepository
+	mockProduct *mock.MockProductReader
+	mockOrder   *mock.MockOrderCreator
+	usecase     usecase.CartUsecase
+	ctx         context.Context
+	products    []productEntity.Product
+}
+
+func (suite *CartUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockCartRepository(ctrl)
+	suite.mockProduct = mock.NewMockProductReader(ctrl)
+	suite.mockOrder = mock.NewMockOrderCreator(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, suite.mockProduct, suite.mockOrder, 60)
+	suite.ctx = requesterContext(2, 0)
+	suite.products = []productEntity.Product{
+		{Id: 1, Name: "orange", Quantity: 10, Price: core.NewMoney(250)},
+		{Id: 2, Name: "pineapple", Quantity: 1, Price: core.NewMoney(500)},
+	}
+}
+
+func (suite *CartUsecaseTestSuite) TestGetCart() {
+	tests := []struct {
+		name          string
+		items         []entity.CartItem
+		products      []productEntity.Product
+		wantSubtotal  core.Money
+		wantAvailable []bool
+		wantCheckout  bool
+	}{
+		{
+			name:          "Empty cart",
+			items:         []entity.CartItem{},
+			wantAvailable: []bool{},
+			wantCheckout:  true,
+		},
+		{
+			name:          "Lines are priced with the live products",
+			items:         []entity.CartItem{entity.NewCartItem(1, 2), entity.NewCartItem(2, 1)},
+			products:      suite.products,
+			wantSubtotal:  core.NewMoney(1000),
+			wantAvailable: []bool{true, true},
+			wantCheckout:  true,
+		},
+		{
+			name:          "Line over the stock is not available",
+			items:         []entity.CartItem{entity.NewCartItem(1, 2), entity.NewCartItem(2, 3)},
+			products:      suite.products,
+			wantSubtotal:  core.NewMoney(500),
+			wantAvailable: []bool{true, false},
+		},
+		{
+			name:          "Deleted product is not available",
+			items:         []entity.CartItem{entity.NewCartItem(1, 2), entity.NewCartItem(3, 1)},
+			products:      suite.products[:1],
+			wantSubtotal:  core.NewMoney(500),
+			wantAvailable: []bool{true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().GetItems(gomock.Any(), 2).Return(&tt.items, nil)
+			if len(tt.items) > 0 {
+				suite.mockProduct.EXPECT().GetProductsByIds(gomock.Any(), gomock.Any()).Return(&tt.products, nil)
+			}
+
+			cart, err := suite.usecase.GetCart(suite.ctx)
+
+			suite.NoError(err)
+			suite.Equal(tt.wantSubtotal, cart.GetSubtotalSafe(), "subtotal should only count available lines")
+
+			available := make([]bool, 0, len(cart.GetItemsSafe()))
+			for _, line := range cart.GetItemsSafe() {
+				available = append(available, line.Available)
+			}
+			suite.Equal(tt.wantAvailable, available, "lines should be checked against the live stock")
+			suite.Equal(tt.wantCheckout, cart.IsAvailable(), "cart should only be available when every line is")
+		})
+	}
+}
+
+func (suite *CartUsecaseTestSuite) TestAddItem() {
+	tests := []struct {
+		name      string
+		products  []productEntity.Product
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Item is added",
+			products:  suite.products[:1],
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Unknown product",
+			products:  []productEntity.Product{},
+			wantErr:   core.ErrNotFound.WithError(entity.ErrProductNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Cart is full",
+			products:  suite.products[:1],
+			callRepo:  true,
+			repoErr:   entity.ErrCartFull,
+			wantErr:   core.ErrUnprocessableEntity.WithError(entity.ErrCartFull.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown error",
+			products:  suite.products[:1],
+			callRepo:  true,
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotUpdateCart.Error()).WithDebug("this is an error"),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockProduct.EXPECT().GetProductsByIds(gomock.Any(), []int{1}).Return(&tt.products, nil)
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().AddItem(gomock.Any(), 2, entity.NewCartItem(1, 2), 60).Return(2, tt.repoErr)
+			}
+			if tt.callRepo && tt.repoErr == nil {
+				items := []entity.CartItem{entity.NewCartItem(1, 2)}
+				suite.mockRepo.EXPECT().GetItems(gomock.Any(), 2).Return(&items, nil)
+				suite.mockProduct.EXPECT().GetProductsByIds(gomock.Any(), []int{1}).Return(&tt.products, nil)
+			}
+
+			_, err := suite.usecase.AddItem(suite.ctx, &entity.CartItemRequest{ProductId: 1, Quantity: 2})
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+		})
+	}
+}
+
+func (suite *CartUsecaseTestSuite) TestUpdateItemNotInCart() {
+	suite.mockRepo.EXPECT().SetItem(gomock.Any(), 2, entity.NewCartItem(5, 1), 60).Return(core.ErrRecordNotFound)
+
+	_, err := suite.usecase.UpdateItem(suite.ctx, 5, &entity.CartQuantityRequest{Quantity: 1})
+
+	suite.ErrorIs(err, core.ErrNotFound.WithError(entity.ErrCartItemNotFound.Error()), "product should be in the cart")
+}
+
+func (suite *CartUsecaseTestSuite) TestCheckout() {
+	items := []entity.CartItem{entity.NewCartItem(1, 2), entity.NewCartItem(2, 1)}
+
+	tests := []struct {
+		name      string
+		items     []entity.CartItem
+		request   entity.CheckoutRequest
+		callOrder bool
+		orderErr  error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Cart is turned into an order",
+			items:     items,
+			request:   entity.CheckoutRequest{Currency: "EUR", Coupons: []string{"summer"}, Region: "DE"},
+			callOrder: true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Cart is shipped to a saved address",
+			items:     items,
+			request:   entity.CheckoutRequest{Coupons: []string{"summer"}, AddressId: 3},
+			callOrder: true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Saved and given shipping address",
+			items:     items,
+			request:   entity.CheckoutRequest{AddressId: 3, ShippingAddress: &addressEntity.AddressRequest{Recipient: "Jane Doe", Line1: "1 Market St", City: "Berlin", Country: "DE"}},
+			wantErr:   core.ErrBadRequest.WithError(orderEntity.ErrAmbiguousAddress.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Empty cart",
+			items:     []entity.CartItem{},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrCartEmpty.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Duplicated coupon",
+			items:     items,
+			request:   entity.CheckoutRequest{Coupons: []string{"summer", "SUMMER"}},
+			wantErr:   core.ErrBadRequest.WithError(orderEntity.ErrDuplicateCoupon.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Order is rejected and the cart is kept",
+			items:     items,
+			callOrder: true,
+			orderErr:  core.ErrConfict.WithError(orderEntity.ErrOutOfStock.Error()),
+			wantErr:   core.ErrConfict.WithError(orderEntity.ErrOutOfStock.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().GetItems(gomock.Any(), 2).Return(&tt.items, nil)
+			if tt.callOrder {
+				suite.mockOrder.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, order *orderEntity.Order) error {
+					suite.Len(order.GetItemsSafe(), len(tt.items), "every cart item should be ordered")
+					suite.Equal(tt.request.Currency, order.GetCurrencySafe(), "currency should be passed on")
+					suite.Equal(tt.request.Region, order.GetRegionSafe(), "region should be passed on")
+					suite.Equal(tt.request.AddressId, order.GetAddressIdSafe(), "saved address should be passed on")
+
+					return tt.orderErr
+				})
+			}
+			if tt.callOrder && tt.orderErr == nil {
+				suite.mockRepo.EXPECT().RemoveItems(gomock.Any(), 2, tt.items).Return(nil)
+			}
+
+			order, err := suite.usecase.Checkout(suite.ctx, &tt.request)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal([]string{"SUMMER"}, order.GetCouponCodesSafe(), "coupon codes should be normalized")
+			}
+		})
+	}
+}
+
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
+
+func TestCartUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CartUsecaseTestSuite))
+}
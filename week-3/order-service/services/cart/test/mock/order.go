@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/order.go
+//
+// Generated by this command:
+//
+//	mockgen -source usecase/order.go -destination test/mock/order.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/order/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrderCreator is a mock of OrderCreator interface.
+type MockOrderCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderCreatorMockRecorder
+}
+
+// MockOrderCreatorMockRecorder is the mock recorder for MockOrderCreator.
+type MockOrderCreatorMockRecorder struct {
+	mock *MockOrderCreator
+}
+
+// NewMockOrderCreator creates a new mock instance.
+func NewMockOrderCreator(ctrl *gomock.Controller) *MockOrderCreator {
+	mock := &MockOrderCreator{ctrl: ctrl}
+	mock.recorder = &MockOrderCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderCreator) EXPECT() *MockOrderCreatorMockRecorder {
+	return m.recorder
+}
+
+// CreateOrder mocks base method.
+func (m *MockOrderCreator) CreateOrder(ctx context.Context, data *entity.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", ctx, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockOrderCreatorMockRecorder) CreateOrder(ctx, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderCreator)(nil).CreateOrder), ctx, data)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/product.go
+//
+// Generated by this command:
+//
+//	mockgen -source usecase/product.go -destination test/mock/product.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/product/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductReader is a mock of ProductReader interface.
+type MockProductReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductReaderMockRecorder
+}
+
+// MockProductReaderMockRecorder is the mock recorder for MockProductReader.
+type MockProductReaderMockRecorder struct {
+	mock *MockProductReader
+}
+
+// NewMockProductReader creates a new mock instance.
+func NewMockProductReader(ctrl *gomock.Controller) *MockProductReader {
+	mock := &MockProductReader{ctrl: ctrl}
+	mock.recorder = &MockProductReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductReader) EXPECT() *MockProductReaderMockRecorder {
+	return m.recorder
+}
+
+// GetProductsByIds mocks base method.
+func (m *MockProductReader) GetProductsByIds(ctx context.Context, productIds []int) (*[]entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductsByIds", ctx, productIds)
+	ret0, _ := ret[0].(*[]entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductsByIds indicates an expected call of GetProductsByIds.
+func (mr *MockProductReaderMockRecorder) GetProductsByIds(ctx, productIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsByIds", reflect.TypeOf((*MockProductReader)(nil).GetProductsByIds), ctx, productIds)
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/redis/store.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/redis/store.go -destination test/mock/store.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/cart/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCartRepository is a mock of CartRepository interface.
+type MockCartRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartRepositoryMockRecorder
+}
+
+// MockCartRepositoryMockRecorder is the mock recorder for MockCartRepository.
+type MockCartRepositoryMockRecorder struct {
+	mock *MockCartRepository
+}
+
+// NewMockCartRepository creates a new mock instance.
+func NewMockCartRepository(ctrl *gomock.Controller) *MockCartRepository {
+	mock := &MockCartRepository{ctrl: ctrl}
+	mock.recorder = &MockCartRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartRepository) EXPECT() *MockCartRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddItem mocks base method.
+func (m *MockCartRepository) AddItem(ctx context.Context, userId int, item entity.CartItem, expiration int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItem", ctx, userId, item, expiration)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddItem indicates an expected call of AddItem.
+func (mr *MockCartRepositoryMockRecorder) AddItem(ctx, userId, item, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItem", reflect.TypeOf((*MockCartRepository)(nil).AddItem), ctx, userId, item, expiration)
+}
+
+// ClearItems mocks base method.
+func (m *MockCartRepository) ClearItems(ctx context.Context, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearItems", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearItems indicates an expected call of ClearItems.
+func (mr *MockCartRepositoryMockRecorder) ClearItems(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearItems", reflect.TypeOf((*MockCartRepository)(nil).ClearItems), ctx, userId)
+}
+
+// GetItems mocks base method.
+func (m *MockCartRepository) GetItems(ctx context.Context, userId int) (*[]entity.CartItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, userId)
+	ret0, _ := ret[0].(*[]entity.CartItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockCartRepositoryMockRecorder) GetItems(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockCartRepository)(nil).GetItems), ctx, userId)
+}
+
+// RemoveItem mocks base method.
+func (m *MockCartRepository) RemoveItem(ctx context.Context, userId, productId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveItem", ctx, userId, productId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveItem indicates an expected call of RemoveItem.
+func (mr *MockCartRepositoryMockRecorder) RemoveItem(ctx, userId, productId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItem", reflect.TypeOf((*MockCartRepository)(nil).RemoveItem), ctx, userId, productId)
+}
+
+// RemoveItems mocks base method.
+func (m *MockCartRepository) RemoveItems(ctx context.Context, userId int, items []entity.CartItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveItems", ctx, userId, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveItems indicates an expected call of RemoveItems.
+func (mr *MockCartRepositoryMockRecorder) RemoveItems(ctx, userId, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItems", reflect.TypeOf((*MockCartRepository)(nil).RemoveItems), ctx, userId, items)
+}
+
+// SetItem mocks base method.
+func (m *MockCartRepository) SetItem(ctx context.Context, userId int, item entity.CartItem, expiration int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetItem", ctx, userId, item, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetItem indicates an expected call of SetItem.
+func (mr *MockCartRepositoryMockRecorder) SetItem(ctx, userId, item, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetItem", reflect.TypeOf((*MockCartRepository)(nil).SetItem), ctx, userId, item, expiration)
+}
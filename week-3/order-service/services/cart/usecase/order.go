@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	orderEntity "order_service/services/order/entity"
+)
+
+// OrderCreator places the order of a checked out cart, with the same checks as any other order.
+// It is implemented by the order usecase.
+type OrderCreator interface {
+	CreateOrder(ctx context.Context, data *orderEntity.Order) error
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	productEntity "order_service/services/product/entity"
+)
+
+// ProductReader gives the live price and stock of the products in a cart.
+// It is implemented by the product usecase.
+type ProductReader interface {
+	GetProductsByIds(ctx context.Context, productIds []int) (*[]productEntity.Product, error)
+}
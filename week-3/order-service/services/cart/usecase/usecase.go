@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"order_service/internal/core"
+	"order_service/services/cart/entity"
+	cartRepo "order_service/services/cart/repository/redis"
+	orderEntity "order_service/services/order/entity"
+	productEntity "order_service/services/product/entity"
+)
+
+type CartUsecase interface {
+	GetCart(ctx context.Context) (*entity.Cart, error)
+	AddItem(ctx context.Context, data *entity.CartItemRequest) (*entity.Cart, error)
+	UpdateItem(ctx context.Context, productId int, data *entity.CartQuantityRequest) (*entity.Cart, error)
+	RemoveItem(ctx context.Context, productId int) (*entity.Cart, error)
+	ClearCart(ctx context.Context) error
+	Checkout(ctx context.Context, data *entity.CheckoutRequest) (*orderEntity.Order, error)
+}
+
+type cartUsecase struct {
+	repo        cartRepo.CartRepository
+	product     ProductReader
+	order       OrderCreator
+	expireInSec int
+}
+
+// NewUsecase builds the cart usecase, a cart left untouched for expireInSec is dropped.
+func NewUsecase(repo cartRepo.CartRepository, product ProductReader, order OrderCreator, expireInSec int) CartUsecase {
+	return &cartUsecase{
+		repo,
+		product,
+		order,
+		expireInSec,
+	}
+}
+
+func requesterId(ctx context.Context) (int, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return 0, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return int(uid.GetLocalID()), nil
+}
+
+func (uc *cartUsecase) GetCart(ctx context.Context) (*entity.Cart, error) {
+	userId, err := requesterId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.getCart(ctx, userId)
+}
+
+// getCart prices the stored items with the live products.
+func (uc *cartUsecase) getCart(ctx context.Context, userId int) (*entity.Cart, error) {
+	items, err := uc.repo.GetItems(ctx, userId)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetCart.Error()).WithDebug(err.Error())
+	}
+
+	cart := entity.NewCart(userId)
+	if len(*items) == 0 {
+		return &cart, nil
+	}
+
+	productIds := make([]int, 0, len(*items))
+	for _, item := range *items {
+		productIds = append(productIds, item.GetProductId())
+	}
+
+	products, err := uc.product.GetProductsByIds(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	productMap := make(map[int]productEntity.Product, len(*products))
+	for _, product := range *products {
+		productMap[product.Id] = product
+	}
+
+	for _, item := range *items {
+		line := entity.CartLine{
+			ProductId: item.GetProductId(),
+			Quantity:  item.GetQuantity(),
+		}
+
+		product, ok := productMap[item.GetProductId()]
+		if ok {
+			line.ProductName = product.GetName()
+			line.Stock = product.GetQuantity()
+			line.UnitPrice = product.GetPrice()
+			line.LineTotal = product.GetPrice().Mul(item.GetQuantity())
+			line.Available = product.GetQuantity() >= item.GetQuantity()
+		}
+
+		cart.AddLine(line)
+	}
+
+	return &cart, nil
+}
+
+func (uc *cartUsecase) AddItem(ctx context.Context, data *entity.CartItemRequest) (*entity.Cart, error) {
+	userId, err := requesterId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := uc.product.GetProductsByIds(ctx, []int{data.ProductId})
+	if err != nil {
+		return nil, err
+	}
+	if len(*products) == 0 {
+		return nil, core.ErrNotFound.WithError(entity.ErrProductNotFound.Error())
+	}
+
+	_, err = uc.repo.AddItem(ctx, userId, entity.NewCartItem(data.ProductId, data.Quantity), uc.expireInSec)
+	if err != nil {
+		if err == entity.ErrCartFull || err == entity.ErrInvalidQuantity {
+			return nil, core.ErrUnprocessableEntity.WithError(err.Error())
+		}
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotUpdateCart.Error()).WithDebug(err.Error())
+	}
+
+	return uc.getCart(ctx, userId)
+}
+
+func (uc *cartUsecase) UpdateItem(ctx context.Context, productId int, data *entity.CartQuantityRequest) (*entity.Cart, error) {
+	userId, err := requesterId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.repo.SetItem(ctx, userId, entity.NewCartItem(productId, data.Quantity), uc.expireInSec)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return nil, core.ErrNotFound.WithError(entity.ErrCartItemNotFound.Error())
+		}
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotUpdateCart.Error()).WithDebug(err.Error())
+	}
+
+	return uc.getCart(ctx, userId)
+}
+
+func (uc *cartUsecase) RemoveItem(ctx context.Context, productId int) (*entity.Cart, error) {
+	userId, err := requesterId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.repo.RemoveItem(ctx, userId, productId)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return nil, core.ErrNotFound.WithError(entity.ErrCartItemNotFound.Error())
+		}
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotUpdateCart.Error()).WithDebug(err.Error())
+	}
+
+	return uc.getCart(ctx, userId)
+}
+
+func (uc *cartUsecase) ClearCart(ctx context.Context) error {
+	userId, err := requesterId(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.ClearItems(ctx, userId)
+	if err != nil {
+		return core.ErrInternalServerError.WithError(entity.ErrCannotUpdateCart.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// Checkout places an order with the items of the cart through the regular order path, which checks
+// price and stock again under lock. The checked out items leave the cart only once the order is committed.
+func (uc *cartUsecase) Checkout(ctx context.Context, data *entity.CheckoutRequest) (*orderEntity.Order, error) {
+	userId, err := requesterId(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := uc.repo.GetItems(ctx, userId)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotCheckoutCart.Error()).WithDebug(err.Error())
+	}
+	if len(*items) == 0 {
+		return nil, core.ErrBadRequest.WithError(entity.ErrCartEmpty.Error())
+	}
+
+	request := orderEntity.OrderRequest{
+		Currency:        data.Currency,
+		Items:           make([]orderEntity.ProductItem, 0, len(*items)),
+		Coupons:         data.Coupons,
+		Region:          data.Region,
+		AddressId:       data.AddressId,
+		ShippingAddress: data.ShippingAddress,
+		PaymentMethod:   data.PaymentMethod,
+		CardToken:       data.CardToken,
+	}
+	for _, item := range *items {
+		request.Items = append(request.Items, orderEntity.ProductItem{ProductId: item.GetProductId(), Quantity: item.GetQuantity()})
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	orderItems := make([]orderEntity.OrderItem, 0, len(request.Items))
+	for _, item := range request.GetItems() {
+		orderItems = append(orderItems, orderEntity.NewOrderItem(0, item.GetItemId(), "", 0, item.GetItemQuantity()))
+	}
+
+	order := orderEntity.NewOrder(0, 0, 0, orderItems)
+	order.SetCurrency(request.Currency)
+	order.SetCouponCodes(request.GetCoupons())
+	order.SetRegion(request.Region)
+	order.SetAddressId(request.AddressId)
+	order.SetShippingAddress(request.GetShippingAddress())
+	order.SetPaymentRequest(request.GetPaymentRequest())
+
+	err = uc.order.CreateOrder(ctx, &order)
+	if err != nil {
+		return nil, err
+	}
+
+	// the order is already committed, failing here would make the client place it a second time
+	err = uc.repo.RemoveItems(ctx, userId, *items)
+	if err != nil {
+		log.Println("empty cart after checkout error:", err)
+	}
+
+	return &order, nil
+}
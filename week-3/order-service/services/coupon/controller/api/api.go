@@ -0,0 +1,118 @@
+package api
+
+import (
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/coupon/entity"
+	couponUc "order_service/services/coupon/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CouponService interface {
+	CreateCoupon(*fiber.Ctx) error
+	GetCoupons(*fiber.Ctx) error
+	DeactivateCoupon(*fiber.Ctx) error
+}
+
+type service struct {
+	usecase couponUc.CouponUsecase
+}
+
+func NewService(uc couponUc.CouponUsecase) CouponService {
+	return &service{
+		usecase: uc,
+	}
+}
+
+// Create Coupon godoc
+// @summary Create Coupon
+// @description Create a percentage, fixed amount or buy X get Y coupon, only admin can do this action
+// @tags coupons
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param payload body entity.CouponRequest true "Create coupon request body"
+// @success 201 {object} entity.Coupon
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /coupons/ [post]
+func (srv *service) CreateCoupon(c *fiber.Ctx) error {
+	var data entity.CouponRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidCouponValue.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	coupon, err := srv.usecase.CreateCoupon(ctx, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(core.ResponseData(coupon))
+}
+
+// Get Coupons godoc
+// @summary Get Coupons
+// @description Get every coupon with its redemption count, only admin can do this action
+// @tags coupons
+// @produce json
+// @security BearerAuth
+// @success 200 {array} entity.Coupon
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /coupons/ [get]
+func (srv *service) GetCoupons(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	coupons, err := srv.usecase.GetCoupons(ctx)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(coupons))
+}
+
+// Deactivate Coupon godoc
+// @summary Deactivate Coupon
+// @description Stop a coupon from being redeemed, only admin can do this action
+// @tags coupons
+// @security BearerAuth
+// @param code path string true "Coupon code"
+// @success 200
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /coupons/:code [delete]
+func (srv *service) DeactivateCoupon(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err := srv.usecase.DeactivateCoupon(ctx, c.Params("code"))
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
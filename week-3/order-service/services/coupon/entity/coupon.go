@@ -0,0 +1,157 @@
+package entity
+
+import (
+	"fmt"
+	"order_service/internal/core"
+	"strings"
+	"time"
+)
+
+type CouponType string
+
+const (
+	CouponPercentage CouponType = "percentage"
+	CouponFixed      CouponType = "fixed"
+	CouponBuyXGetY   CouponType = "buy_x_get_y"
+)
+
+func (t CouponType) IsValid() bool {
+	switch t {
+	case CouponPercentage, CouponFixed, CouponBuyXGetY:
+		return true
+	}
+
+	return false
+}
+
+// Coupon amounts (AmountOff, MinSpend) are in the base currency. A zero limit means no limit,
+// and a ProductId restricts the coupon to that product (it is required for buy X get Y).
+//
+// UserRedeemedCount is not stored on the coupon, it is filled in for the user checking out.
+type Coupon struct {
+	StartsAt          *time.Time `json:"starts_at"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         *time.Time `json:"updated_at"`
+	ProductId         *int       `json:"product_id"`
+	Code              string     `json:"code"`
+	Type              CouponType `json:"type"`
+	Id                int        `json:"id"`
+	PercentOff        int        `json:"percent_off"`
+	AmountOff         core.Money `json:"amount_off" swaggertype:"number"`
+	BuyQuantity       int        `json:"buy_quantity"`
+	GetQuantity       int        `json:"get_quantity"`
+	MinSpend          core.Money `json:"min_spend" swaggertype:"number"`
+	UsageLimit        int        `json:"usage_limit"`
+	PerUserLimit      int        `json:"per_user_limit"`
+	RedeemedCount     int        `json:"redeemed_count"`
+	UserRedeemedCount int        `json:"-"`
+	Active            bool       `json:"active"`
+}
+
+// CouponLine is an order line as seen by the promotion rules, priced in the base currency.
+type CouponLine struct {
+	ProductId int
+	Quantity  int
+	UnitPrice core.Money
+}
+
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (coupon Coupon) GetId() int {
+	return coupon.Id
+}
+
+func (coupon Coupon) GetCode() string {
+	return coupon.Code
+}
+
+// CheckEligible tells whether the coupon can be redeemed now by a user whose order is worth subtotal.
+func (coupon Coupon) CheckEligible(now time.Time, subtotal core.Money) error {
+	if !coupon.Active {
+		return ErrCouponInactive
+	}
+	if coupon.StartsAt != nil && now.Before(*coupon.StartsAt) {
+		return ErrCouponNotStarted
+	}
+	if coupon.ExpiresAt != nil && !now.Before(*coupon.ExpiresAt) {
+		return ErrCouponExpired
+	}
+	if coupon.UsageLimit > 0 && coupon.RedeemedCount >= coupon.UsageLimit {
+		return ErrCouponUsageExceeded
+	}
+	if coupon.PerUserLimit > 0 && coupon.UserRedeemedCount >= coupon.PerUserLimit {
+		return ErrCouponUserLimitExceeded
+	}
+	if subtotal < coupon.MinSpend {
+		return ErrCouponMinSpend
+	}
+
+	return nil
+}
+
+// Discount computes the amount taken off the given lines. It never exceeds what the eligible lines are worth.
+func (coupon Coupon) Discount(lines []CouponLine) (core.Money, error) {
+	eligible := core.NewMoney(0)
+	matched := false
+
+	for _, line := range lines {
+		if coupon.ProductId != nil && line.ProductId != *coupon.ProductId {
+			continue
+		}
+
+		matched = true
+		eligible = eligible.Add(line.UnitPrice.Mul(line.Quantity))
+	}
+
+	if !matched {
+		return 0, ErrCouponNotApplicable
+	}
+
+	var discount core.Money
+
+	switch coupon.Type {
+	case CouponPercentage:
+		discount = eligible.Percent(coupon.PercentOff)
+	case CouponFixed:
+		discount = coupon.AmountOff
+	case CouponBuyXGetY:
+		// every full group of buy + get units gets its last get units for free
+		group := coupon.BuyQuantity + coupon.GetQuantity
+		for _, line := range lines {
+			if line.ProductId != *coupon.ProductId {
+				continue
+			}
+
+			free := line.Quantity / group * coupon.GetQuantity
+			discount = discount.Add(line.UnitPrice.Mul(free))
+		}
+		if discount == 0 {
+			return 0, ErrCouponNotApplicable
+		}
+	default:
+		return 0, ErrInvalidCouponType
+	}
+
+	if discount > eligible {
+		discount = eligible
+	}
+
+	return discount, nil
+}
+
+// Describe is the label printed next to the discount line of an order.
+func (coupon Coupon) Describe() string {
+	switch coupon.Type {
+	case CouponPercentage:
+		return fmt.Sprintf("%d%% off", coupon.PercentOff)
+	case CouponFixed:
+		return fmt.Sprintf("%s off", coupon.AmountOff)
+	case CouponBuyXGetY:
+		return fmt.Sprintf("buy %d get %d free", coupon.BuyQuantity, coupon.GetQuantity)
+	}
+
+	return string(coupon.Type)
+}
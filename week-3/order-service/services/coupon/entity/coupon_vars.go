@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"order_service/internal/core"
+	"time"
+)
+
+type CouponRequest struct {
+	StartsAt     *time.Time `json:"starts_at"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	ProductId    *int       `json:"product_id"`
+	Code         string     `json:"code"`
+	Type         CouponType `json:"type"`
+	PercentOff   int        `json:"percent_off"`
+	AmountOff    core.Money `json:"amount_off" swaggertype:"number"`
+	BuyQuantity  int        `json:"buy_quantity"`
+	GetQuantity  int        `json:"get_quantity"`
+	MinSpend     core.Money `json:"min_spend" swaggertype:"number"`
+	UsageLimit   int        `json:"usage_limit"`
+	PerUserLimit int        `json:"per_user_limit"`
+}
+
+func (data CouponRequest) Validate() error {
+	code := NormalizeCode(data.Code)
+	if len(code) < 3 || len(code) > 32 {
+		return ErrInvalidCouponCode
+	}
+
+	switch data.Type {
+	case CouponPercentage:
+		if data.PercentOff < 1 || data.PercentOff > 100 {
+			return ErrInvalidCouponValue
+		}
+	case CouponFixed:
+		if !data.AmountOff.IsPositive() {
+			return ErrInvalidCouponValue
+		}
+	case CouponBuyXGetY:
+		if data.ProductId == nil || data.BuyQuantity < 1 || data.GetQuantity < 1 {
+			return ErrInvalidCouponValue
+		}
+	default:
+		return ErrInvalidCouponType
+	}
+
+	if data.MinSpend.IsNegative() || data.UsageLimit < 0 || data.PerUserLimit < 0 {
+		return ErrInvalidCouponValue
+	}
+
+	if data.StartsAt != nil && data.ExpiresAt != nil && !data.ExpiresAt.After(*data.StartsAt) {
+		return ErrInvalidCouponValue
+	}
+
+	return nil
+}
+
+func (data CouponRequest) ToCoupon() Coupon {
+	return Coupon{
+		Code:         NormalizeCode(data.Code),
+		Type:         data.Type,
+		PercentOff:   data.PercentOff,
+		AmountOff:    data.AmountOff,
+		ProductId:    data.ProductId,
+		BuyQuantity:  data.BuyQuantity,
+		GetQuantity:  data.GetQuantity,
+		MinSpend:     data.MinSpend,
+		UsageLimit:   data.UsageLimit,
+		PerUserLimit: data.PerUserLimit,
+		StartsAt:     data.StartsAt,
+		ExpiresAt:    data.ExpiresAt,
+		Active:       true,
+		CreatedAt:    time.Now(),
+	}
+}
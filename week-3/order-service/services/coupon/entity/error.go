@@ -0,0 +1,21 @@
+package entity
+
+import "errors"
+
+var (
+	ErrInvalidCouponCode       = errors.New("coupon code must have between 3 and 32 characters")
+	ErrInvalidCouponType       = errors.New("coupon type is not valid")
+	ErrInvalidCouponValue      = errors.New("coupon values are not valid for its type")
+	ErrCouponNotFound          = errors.New("coupon cannot be found")
+	ErrCouponAlreadyExists     = errors.New("coupon code is already used")
+	ErrCouponInactive          = errors.New("coupon is no longer active")
+	ErrCouponNotStarted        = errors.New("coupon cannot be used yet")
+	ErrCouponExpired           = errors.New("coupon has expired")
+	ErrCouponUsageExceeded     = errors.New("coupon has been fully redeemed")
+	ErrCouponUserLimitExceeded = errors.New("coupon has already been used the maximum number of times")
+	ErrCouponMinSpend          = errors.New("order does not reach the coupon minimum spend")
+	ErrCouponNotApplicable     = errors.New("coupon does not apply to any item of the order")
+	ErrCannotCreateCoupon      = errors.New("coupon cannot be create")
+	ErrCannotGetCoupons        = errors.New("coupons cannot be get")
+	ErrCannotDeactivateCoupon  = errors.New("coupon cannot be deactivate")
+)
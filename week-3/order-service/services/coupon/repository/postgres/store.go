@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/services/coupon/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CouponRepository interface {
+	CreateCoupon(ctx context.Context, coupon *entity.Coupon) error
+	GetCoupons(ctx context.Context) (*[]entity.Coupon, error)
+	DeactivateCoupon(ctx context.Context, code string) error
+}
+
+const (
+	QUERY_CREATE_COUPON     = "INSERT INTO coupons (code, type, percent_off, amount_off, product_id, buy_quantity, get_quantity, min_spend, usage_limit, per_user_limit, active, starts_at, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) ON CONFLICT (code) DO NOTHING RETURNING id"
+	QUERY_GET_COUPONS       = "SELECT id, code, type, percent_off, amount_off, product_id, buy_quantity, get_quantity, min_spend, usage_limit, per_user_limit, redeemed_count, active, starts_at, expires_at, created_at, updated_at FROM coupons ORDER BY id"
+	QUERY_DEACTIVATE_COUPON = "UPDATE coupons SET active = false, updated_at = $2 WHERE code = $1"
+)
+
+type postgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCouponRepo(db *pgxpool.Pool) CouponRepository {
+	return &postgresRepo{
+		db,
+	}
+}
+
+func (repo *postgresRepo) CreateCoupon(ctx context.Context, coupon *entity.Coupon) error {
+	var id int
+
+	err := repo.db.QueryRow(ctx, QUERY_CREATE_COUPON, coupon.Code, coupon.Type, coupon.PercentOff, coupon.AmountOff, coupon.ProductId, coupon.BuyQuantity, coupon.GetQuantity, coupon.MinSpend, coupon.UsageLimit, coupon.PerUserLimit, coupon.Active, coupon.StartsAt, coupon.ExpiresAt, coupon.CreatedAt).Scan(&id)
+	if err != nil {
+		// nothing is returned when the code is already taken
+		if err == pgx.ErrNoRows {
+			return entity.ErrCouponAlreadyExists
+		}
+		return err
+	}
+
+	coupon.Id = id
+
+	return nil
+}
+
+func (repo *postgresRepo) GetCoupons(ctx context.Context) (*[]entity.Coupon, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_COUPONS)
+	if err != nil {
+		return nil, err
+	}
+
+	coupons, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Coupon, error) {
+		var coupon entity.Coupon
+
+		err := row.Scan(&coupon.Id, &coupon.Code, &coupon.Type, &coupon.PercentOff, &coupon.AmountOff, &coupon.ProductId, &coupon.BuyQuantity, &coupon.GetQuantity, &coupon.MinSpend, &coupon.UsageLimit, &coupon.PerUserLimit, &coupon.RedeemedCount, &coupon.Active, &coupon.StartsAt, &coupon.ExpiresAt, &coupon.CreatedAt, &coupon.UpdatedAt)
+		if err != nil {
+			return entity.Coupon{}, err
+		}
+
+		return coupon, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &coupons, nil
+}
+
+func (repo *postgresRepo) DeactivateCoupon(ctx context.Context, code string) error {
+	tag, err := repo.db.Exec(ctx, QUERY_DEACTIVATE_COUPON, code, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return core.ErrRecordNotFound
+	}
+
+	return nil
+}
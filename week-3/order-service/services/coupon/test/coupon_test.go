@@ -0,0 +1,217 @@
+package test
+
+import (
+	"order_service/internal/core"
+	"order_service/services/coupon/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CouponTestSuite struct {
+	suite.Suite
+	lines []entity.CouponLine
+}
+
+func (suite *CouponTestSuite) SetupTest() {
+	// 5 oranges at 2.00 and 2 apples at 3.50
+	suite.lines = []entity.CouponLine{
+		{ProductId: 1, Quantity: 5, UnitPrice: core.NewMoney(200)},
+		{ProductId: 2, Quantity: 2, UnitPrice: core.NewMoney(350)},
+	}
+}
+
+func (suite *CouponTestSuite) TestDiscount() {
+	orangeId, bananaId := 1, 3
+
+	tests := []struct {
+		name      string
+		coupon    entity.Coupon
+		want      core.Money
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Percentage of the whole order",
+			coupon:    entity.Coupon{Type: entity.CouponPercentage, PercentOff: 15},
+			want:      core.NewMoney(255),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Percentage of one product",
+			coupon:    entity.Coupon{Type: entity.CouponPercentage, PercentOff: 50, ProductId: &orangeId},
+			want:      core.NewMoney(500),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Fixed amount",
+			coupon:    entity.Coupon{Type: entity.CouponFixed, AmountOff: core.NewMoney(300)},
+			want:      core.NewMoney(300),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Fixed amount is capped by the eligible lines",
+			coupon:    entity.Coupon{Type: entity.CouponFixed, AmountOff: core.NewMoney(5000)},
+			want:      core.NewMoney(1700),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Buy two get one only counts full groups",
+			coupon:    entity.Coupon{Type: entity.CouponBuyXGetY, ProductId: &orangeId, BuyQuantity: 2, GetQuantity: 1},
+			want:      core.NewMoney(200),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Buy X get Y without a full group",
+			coupon:    entity.Coupon{Type: entity.CouponBuyXGetY, ProductId: &orangeId, BuyQuantity: 5, GetQuantity: 1},
+			wantErr:   entity.ErrCouponNotApplicable,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Product is not in the order",
+			coupon:    entity.Coupon{Type: entity.CouponPercentage, PercentOff: 10, ProductId: &bananaId},
+			wantErr:   entity.ErrCouponNotApplicable,
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			discount, err := tt.coupon.Discount(suite.lines)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			suite.Equal(tt.want, discount, "discount should be computed correctly")
+		})
+	}
+}
+
+func (suite *CouponTestSuite) TestCheckEligible() {
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		coupon    entity.Coupon
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Eligible coupon",
+			coupon:    entity.Coupon{Active: true, StartsAt: &before, ExpiresAt: &after, UsageLimit: 10, RedeemedCount: 9, PerUserLimit: 2, UserRedeemedCount: 1, MinSpend: core.NewMoney(1000)},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Inactive coupon",
+			coupon:    entity.Coupon{},
+			wantErr:   entity.ErrCouponInactive,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Not started yet",
+			coupon:    entity.Coupon{Active: true, StartsAt: &after},
+			wantErr:   entity.ErrCouponNotStarted,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Expires exactly now",
+			coupon:    entity.Coupon{Active: true, ExpiresAt: &now},
+			wantErr:   entity.ErrCouponExpired,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Usage limit reached",
+			coupon:    entity.Coupon{Active: true, UsageLimit: 10, RedeemedCount: 10},
+			wantErr:   entity.ErrCouponUsageExceeded,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Per user limit reached",
+			coupon:    entity.Coupon{Active: true, PerUserLimit: 1, UserRedeemedCount: 1},
+			wantErr:   entity.ErrCouponUserLimitExceeded,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Minimum spend not reached",
+			coupon:    entity.Coupon{Active: true, MinSpend: core.NewMoney(1001)},
+			wantErr:   entity.ErrCouponMinSpend,
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := tt.coupon.CheckEligible(now, core.NewMoney(1000))
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+		})
+	}
+}
+
+func (suite *CouponTestSuite) TestRequestValidate() {
+	productId := 1
+	startsAt := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := startsAt.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		request   entity.CouponRequest
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Valid buy X get Y",
+			request:   entity.CouponRequest{Code: "b2g1", Type: entity.CouponBuyXGetY, ProductId: &productId, BuyQuantity: 2, GetQuantity: 1},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Code too short",
+			request:   entity.CouponRequest{Code: " a ", Type: entity.CouponFixed, AmountOff: core.NewMoney(100)},
+			wantErr:   entity.ErrInvalidCouponCode,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown type",
+			request:   entity.CouponRequest{Code: "FREE", Type: "free"},
+			wantErr:   entity.ErrInvalidCouponType,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Percentage over 100",
+			request:   entity.CouponRequest{Code: "MORE", Type: entity.CouponPercentage, PercentOff: 101},
+			wantErr:   entity.ErrInvalidCouponValue,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Buy X get Y without product",
+			request:   entity.CouponRequest{Code: "B2G1", Type: entity.CouponBuyXGetY, BuyQuantity: 2, GetQuantity: 1},
+			wantErr:   entity.ErrInvalidCouponValue,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Expires before it starts",
+			request:   entity.CouponRequest{Code: "LATE", Type: entity.CouponFixed, AmountOff: core.NewMoney(100), StartsAt: &startsAt, ExpiresAt: &expiresAt},
+			wantErr:   entity.ErrInvalidCouponValue,
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := tt.request.Validate()
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+		})
+	}
+}
+
+func TestCouponTestSuite(t *testing.T) {
+	suite.Run(t, new(CouponTestSuite))
+}
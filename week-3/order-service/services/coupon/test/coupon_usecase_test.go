@@ -0,0 +1,155 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	"order_service/services/coupon/entity"
+	"order_service/services/coupon/test/mock"
+	"order_service/services/coupon/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type CouponUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo  *mock.MockCouponRepository
+	usecase   usecase.CouponUsecase
+	adminCtx  context.Context
+	memberCtx context.Context
+}
+
+func (suite *CouponUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockCouponRepository(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo)
+	suite.adminCtx = requesterContext(1, 1)
+	suite.memberCtx = requesterContext(2, 0)
+}
+
+func (suite *CouponUsecaseTestSuite) TestCreateCoupon() {
+	request := &entity.CouponRequest{Code: " summer10 ", Type: entity.CouponPercentage, PercentOff: 10}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Admin creates a coupon",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Member cannot create a coupon",
+			ctx:       suite.memberCtx,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrCannotCreateCoupon.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Code already used",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			repoErr:   entity.ErrCouponAlreadyExists,
+			wantErr:   core.ErrConfict.WithError(entity.ErrCouponAlreadyExists.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown error",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotCreateCoupon.Error()).WithDebug("this is an error"),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().CreateCoupon(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, coupon *entity.Coupon) error {
+					suite.Equal("SUMMER10", coupon.GetCode(), "code should be normalized")
+					suite.True(coupon.Active, "new coupons should be active")
+					return tt.repoErr
+				})
+			}
+
+			coupon, err := suite.usecase.CreateCoupon(tt.ctx, request)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr != nil {
+				suite.Nil(coupon, "no coupon should be returned on error")
+			}
+		})
+	}
+}
+
+func (suite *CouponUsecaseTestSuite) TestDeactivateCoupon() {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Admin deactivates a coupon",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Member cannot deactivate a coupon",
+			ctx:       suite.memberCtx,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrCannotDeactivateCoupon.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown coupon",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrCouponNotFound.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().DeactivateCoupon(gomock.Any(), "SUMMER10").Return(tt.repoErr)
+			}
+
+			err := suite.usecase.DeactivateCoupon(tt.ctx, "summer10")
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+		})
+	}
+}
+
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
+
+func TestCouponUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CouponUsecaseTestSuite))
+}
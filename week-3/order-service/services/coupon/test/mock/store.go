@@ -0,0 +1,84 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/postgres/store.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/postgres/store.go -destination test/mock/store.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/coupon/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCouponRepository is a mock of CouponRepository interface.
+type MockCouponRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCouponRepositoryMockRecorder
+}
+
+// MockCouponRepositoryMockRecorder is the mock recorder for MockCouponRepository.
+type MockCouponRepositoryMockRecorder struct {
+	mock *MockCouponRepository
+}
+
+// NewMockCouponRepository creates a new mock instance.
+func NewMockCouponRepository(ctrl *gomock.Controller) *MockCouponRepository {
+	mock := &MockCouponRepository{ctrl: ctrl}
+	mock.recorder = &MockCouponRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCouponRepository) EXPECT() *MockCouponRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCoupon mocks base method.
+func (m *MockCouponRepository) CreateCoupon(ctx context.Context, coupon *entity.Coupon) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCoupon", ctx, coupon)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCoupon indicates an expected call of CreateCoupon.
+func (mr *MockCouponRepositoryMockRecorder) CreateCoupon(ctx, coupon any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCoupon", reflect.TypeOf((*MockCouponRepository)(nil).CreateCoupon), ctx, coupon)
+}
+
+// DeactivateCoupon mocks base method.
+func (m *MockCouponRepository) DeactivateCoupon(ctx context.Context, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateCoupon", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateCoupon indicates an expected call of DeactivateCoupon.
+func (mr *MockCouponRepositoryMockRecorder) DeactivateCoupon(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateCoupon", reflect.TypeOf((*MockCouponRepository)(nil).DeactivateCoupon), ctx, code)
+}
+
+// GetCoupons mocks base method.
+func (m *MockCouponRepository) GetCoupons(ctx context.Context) (*[]entity.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoupons", ctx)
+	ret0, _ := ret[0].(*[]entity.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoupons indicates an expected call of GetCoupons.
+func (mr *MockCouponRepositoryMockRecorder) GetCoupons(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoupons", reflect.TypeOf((*MockCouponRepository)(nil).GetCoupons), ctx)
+}
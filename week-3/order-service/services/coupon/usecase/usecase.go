@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/services/coupon/entity"
+	couponRepo "order_service/services/coupon/repository/postgres"
+)
+
+type CouponUsecase interface {
+	CreateCoupon(ctx context.Context, data *entity.CouponRequest) (*entity.Coupon, error)
+	GetCoupons(ctx context.Context) (*[]entity.Coupon, error)
+	DeactivateCoupon(ctx context.Context, code string) error
+}
+
+type couponUsecase struct {
+	repo couponRepo.CouponRepository
+}
+
+func NewUsecase(repo couponRepo.CouponRepository) CouponUsecase {
+	return &couponUsecase{
+		repo,
+	}
+}
+
+func isAdmin(ctx context.Context) (bool, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return false, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return uid.GetRole() == 1, nil
+}
+
+func (uc *couponUsecase) CreateCoupon(ctx context.Context, data *entity.CouponRequest) (*entity.Coupon, error) {
+	admin, err := isAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return nil, core.ErrBadRequest.WithError(entity.ErrCannotCreateCoupon.Error())
+	}
+
+	coupon := data.ToCoupon()
+
+	err = uc.repo.CreateCoupon(ctx, &coupon)
+	if err != nil {
+		if err == entity.ErrCouponAlreadyExists {
+			return nil, core.ErrConfict.WithError(entity.ErrCouponAlreadyExists.Error())
+		}
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotCreateCoupon.Error()).WithDebug(err.Error())
+	}
+
+	return &coupon, nil
+}
+
+func (uc *couponUsecase) GetCoupons(ctx context.Context) (*[]entity.Coupon, error) {
+	admin, err := isAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return nil, core.ErrBadRequest.WithError(entity.ErrCannotGetCoupons.Error())
+	}
+
+	coupons, err := uc.repo.GetCoupons(ctx)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetCoupons.Error()).WithDebug(err.Error())
+	}
+
+	return coupons, nil
+}
+
+// DeactivateCoupon stops a coupon from being redeemed. Coupons are never deleted since orders keep referring to them.
+func (uc *couponUsecase) DeactivateCoupon(ctx context.Context, code string) error {
+	admin, err := isAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return core.ErrBadRequest.WithError(entity.ErrCannotDeactivateCoupon.Error())
+	}
+
+	err = uc.repo.DeactivateCoupon(ctx, entity.NormalizeCode(code))
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return core.ErrNotFound.WithError(entity.ErrCouponNotFound.Error())
+		}
+		return core.ErrInternalServerError.WithError(entity.ErrCannotDeactivateCoupon.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
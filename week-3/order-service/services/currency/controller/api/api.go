@@ -0,0 +1,173 @@
+package api
+
+import (
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/currency/entity"
+	currencyUc "order_service/services/currency/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CurrencyService interface {
+	GetRates(*fiber.Ctx) error
+	SetRate(*fiber.Ctx) error
+	DeleteRate(*fiber.Ctx) error
+	ReloadRates(*fiber.Ctx) error
+	SetUserCurrency(*fiber.Ctx) error
+}
+
+type service struct {
+	usecase currencyUc.CurrencyUsecase
+}
+
+func NewService(uc currencyUc.CurrencyUsecase) CurrencyService {
+	return &service{
+		usecase: uc,
+	}
+}
+
+// Get Exchange Rates godoc
+// @summary Get Exchange Rates
+// @description Get every currency orders can be paid in, with its rate from the base currency listed first
+// @tags currencies
+// @security BearerAuth
+// @success 200 {array} entity.ExchangeRate
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /currencies/ [get]
+func (srv *service) GetRates(c *fiber.Ctx) error {
+	rates, err := srv.usecase.GetRates(c.Context())
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(rates))
+}
+
+// Set Exchange Rate godoc
+// @summary Set Exchange Rate
+// @description Create or update the rate of a currency, only admin can do this action
+// @tags currencies
+// @accept application/json
+// @security BearerAuth
+// @param currency path string true "ISO 4217 currency code"
+// @param payload body entity.ExchangeRateRequest true "Exchange rate request body"
+// @success 200
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /currencies/:currency [put]
+func (srv *service) SetRate(c *fiber.Ctx) error {
+	var data entity.ExchangeRateRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidRate.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err := srv.usecase.SetRate(ctx, c.Params("currency"), data.Rate)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
+
+// Delete Exchange Rate godoc
+// @summary Delete Exchange Rate
+// @description Stop selling in a currency, only admin can do this action
+// @tags currencies
+// @security BearerAuth
+// @param currency path string true "ISO 4217 currency code"
+// @success 200
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /currencies/:currency [delete]
+func (srv *service) DeleteRate(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err := srv.usecase.DeleteRate(ctx, c.Params("currency"))
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
+
+// Reload Exchange Rates godoc
+// @summary Reload Exchange Rates
+// @description Load the rates from the configured rates file, only admin can do this action
+// @tags currencies
+// @security BearerAuth
+// @success 200 {object} int "Number of rates loaded"
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /currencies/reload [post]
+func (srv *service) ReloadRates(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	loaded, err := srv.usecase.ReloadRates(ctx)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(loaded))
+}
+
+// Set User Currency godoc
+// @summary Set User Currency
+// @description Choose the currency the current user is charged in when an order does not ask for one
+// @tags users
+// @accept application/json
+// @security BearerAuth
+// @param payload body entity.UserCurrencyRequest true "User currency request body"
+// @success 200
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /users/currency [patch]
+func (srv *service) SetUserCurrency(c *fiber.Ctx) error {
+	var data entity.UserCurrencyRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err := srv.usecase.SetUserCurrency(ctx, data.Currency)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
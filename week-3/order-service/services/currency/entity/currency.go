@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"order_service/internal/core"
+	"strings"
+	"time"
+)
+
+// ExchangeRate tells how much of Currency one unit of the base currency buys.
+type ExchangeRate struct {
+	UpdatedAt *time.Time `json:"updated_at"`
+	Currency  string     `json:"currency"`
+	Rate      core.Rate  `json:"rate" swaggertype:"number"`
+}
+
+func NewExchangeRate(currency string, rate core.Rate) ExchangeRate {
+	return ExchangeRate{
+		Currency: currency,
+		Rate:     rate,
+	}
+}
+
+func (rate *ExchangeRate) SetUpdatedAt(updatedAt *time.Time) {
+	if rate != nil {
+		rate.UpdatedAt = updatedAt
+	}
+}
+
+func (rate ExchangeRate) GetCurrency() string {
+	return rate.Currency
+}
+
+func (rate ExchangeRate) GetRate() core.Rate {
+	return rate.Rate
+}
+
+// NormalizeCurrency upper-cases an ISO 4217 code and checks it is made of three letters.
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", ErrInvalidCurrency
+	}
+
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+
+	return code, nil
+}
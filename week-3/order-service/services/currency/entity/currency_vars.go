@@ -0,0 +1,25 @@
+package entity
+
+import "order_service/internal/core"
+
+type ExchangeRateRequest struct {
+	Rate core.Rate `json:"rate" swaggertype:"number"`
+}
+
+func (data ExchangeRateRequest) Validate() error {
+	if !data.Rate.IsPositive() {
+		return ErrInvalidRate
+	}
+
+	return nil
+}
+
+type UserCurrencyRequest struct {
+	Currency string `json:"currency"`
+}
+
+func (data UserCurrencyRequest) Validate() error {
+	_, err := NormalizeCurrency(data.Currency)
+
+	return err
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "errors"
+
+var (
+	ErrInvalidCurrency      = errors.New("currency must be a three letters ISO 4217 code")
+	ErrInvalidRate          = errors.New("exchange rate must be greater than zero")
+	ErrUnsupportedCurrency  = errors.New("currency has no exchange rate")
+	ErrBaseCurrency         = errors.New("base currency rate cannot be changed")
+	ErrCannotGetRates       = errors.New("exchange rates cannot be get")
+	ErrCannotUpdateRate     = errors.New("exchange rate cannot be update")
+	ErrCannotDeleteRate     = errors.New("exchange rate cannot be delete")
+	ErrCannotLoadRates      = errors.New("exchange rates cannot be load from file")
+	ErrCannotUpdateCurrency = errors.New("user currency cannot be update")
+)
@@ -0,0 +1,38 @@
+package file
+
+import (
+	"encoding/json"
+	"order_service/internal/core"
+	"os"
+)
+
+// RatesFile reads exchange rates from a local JSON file shaped like {"EUR": "0.92", "VND": 25000}.
+type RatesFile interface {
+	ReadRates() (map[string]core.Rate, error)
+}
+
+type ratesFile struct {
+	path string
+}
+
+func NewRatesFile(path string) RatesFile {
+	return &ratesFile{
+		path,
+	}
+}
+
+func (file *ratesFile) ReadRates() (map[string]core.Rate, error) {
+	content, err := os.ReadFile(file.path)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]core.Rate)
+
+	err = json.Unmarshal(content, &rates)
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/currency/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CurrencyRepository interface {
+	GetRates(ctx context.Context) (*[]entity.ExchangeRate, error)
+	GetRate(ctx context.Context, currency string) (*entity.ExchangeRate, error)
+	UpsertRates(ctx context.Context, rates []entity.ExchangeRate) error
+	DeleteRate(ctx context.Context, currency string) error
+	GetUserCurrency(ctx context.Context, userId int) (string, error)
+	SetUserCurrency(ctx context.Context, userId int, currency string) error
+}
+
+const (
+	QUERY_GET_RATES         = "SELECT currency, rate, updated_at FROM exchange_rates ORDER BY currency"
+	QUERY_GET_RATE          = "SELECT currency, rate, updated_at FROM exchange_rates WHERE currency = $1"
+	QUERY_UPSERT_RATE       = "INSERT INTO exchange_rates (currency, rate, updated_at) VALUES ($1, $2, $3) ON CONFLICT (currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at"
+	QUERY_DELETE_RATE       = "DELETE FROM exchange_rates WHERE currency = $1"
+	QUERY_GET_USER_CURRENCY = "SELECT COALESCE(currency, '') FROM users WHERE id = $1"
+	QUERY_SET_USER_CURRENCY = "UPDATE users SET currency = $2, updated_at = $3 WHERE id = $1"
+)
+
+type postgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCurrencyRepo(db *pgxpool.Pool) CurrencyRepository {
+	return &postgresRepo{
+		db,
+	}
+}
+
+func (repo *postgresRepo) GetRates(ctx context.Context) (*[]entity.ExchangeRate, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_RATES)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.ExchangeRate, error) {
+		var rate entity.ExchangeRate
+
+		err := row.Scan(&rate.Currency, &rate.Rate, &rate.UpdatedAt)
+		if err != nil {
+			return entity.ExchangeRate{}, err
+		}
+
+		return rate, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rates, nil
+}
+
+func (repo *postgresRepo) GetRate(ctx context.Context, currency string) (*entity.ExchangeRate, error) {
+	var rate entity.ExchangeRate
+
+	err := repo.db.QueryRow(ctx, QUERY_GET_RATE, currency).Scan(&rate.Currency, &rate.Rate, &rate.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, core.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+func (repo *postgresRepo) UpsertRates(ctx context.Context, rates []entity.ExchangeRate) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		now := time.Now()
+		batch := &pgx.Batch{}
+
+		for _, rate := range rates {
+			batch.Queue(QUERY_UPSERT_RATE, rate.GetCurrency(), rate.GetRate(), now)
+		}
+
+		return tx.SendBatch(ctx, batch).Close()
+	})
+}
+
+func (repo *postgresRepo) DeleteRate(ctx context.Context, currency string) error {
+	result, err := repo.db.Exec(ctx, QUERY_DELETE_RATE, currency)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return core.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (repo *postgresRepo) GetUserCurrency(ctx context.Context, userId int) (string, error) {
+	var currency string
+
+	err := repo.db.QueryRow(ctx, QUERY_GET_USER_CURRENCY, userId).Scan(&currency)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", core.ErrRecordNotFound
+		}
+		return "", err
+	}
+
+	return currency, nil
+}
+
+func (repo *postgresRepo) SetUserCurrency(ctx context.Context, userId int, currency string) error {
+	result, err := repo.db.Exec(ctx, QUERY_SET_USER_CURRENCY, userId, currency)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return core.ErrRecordNotFound
+	}
+
+	return nil
+}
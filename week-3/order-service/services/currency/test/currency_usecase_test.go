@@ -0,0 +1,267 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	"order_service/services/currency/entity"
+	"order_service/services/currency/test/mock"
+	"order_service/services/currency/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type CurrencyUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo  *mock.MockCurrencyRepository
+	mockFile  *mock.MockRatesFile
+	usecase   usecase.CurrencyUsecase
+	euroRate  core.Rate
+	adminCtx  context.Context
+	memberCtx context.Context
+}
+
+func (suite *CurrencyUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockCurrencyRepository(ctrl)
+	suite.mockFile = mock.NewMockRatesFile(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, suite.mockFile, "USD")
+	suite.euroRate, _ = core.ParseRate("0.92")
+	suite.adminCtx = requesterContext(1, 1)
+	suite.memberCtx = requesterContext(2, 0)
+}
+
+func (suite *CurrencyUsecaseTestSuite) TestResolveCurrency() {
+	tests := []struct {
+		name         string
+		requested    string
+		preferred    string
+		callPrefer   bool
+		callRate     bool
+		rateCurrency string
+		rateErr      error
+		want         string
+		wantRate     core.Rate
+		wantErr      error
+		assertion    assert.ErrorAssertionFunc
+	}{
+		{
+			name:         "Requested currency wins",
+			requested:    "eur",
+			callRate:     true,
+			rateCurrency: "EUR",
+			want:         "EUR",
+			wantRate:     suite.euroRate,
+			assertion:    assert.NoError,
+		},
+		{
+			name:         "Preferred currency is used when none is requested",
+			preferred:    "EUR",
+			callPrefer:   true,
+			callRate:     true,
+			rateCurrency: "EUR",
+			want:         "EUR",
+			wantRate:     suite.euroRate,
+			assertion:    assert.NoError,
+		},
+		{
+			name:       "Base currency without any preference",
+			callPrefer: true,
+			want:       "USD",
+			wantRate:   core.RateOne,
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Base currency needs no rate",
+			requested: "USD",
+			want:      "USD",
+			wantRate:  core.RateOne,
+			assertion: assert.NoError,
+		},
+		{
+			name:         "Currency without rate",
+			requested:    "XYZ",
+			callRate:     true,
+			rateCurrency: "XYZ",
+			rateErr:      core.ErrRecordNotFound,
+			wantErr:      core.ErrBadRequest.WithError(entity.ErrUnsupportedCurrency.Error()),
+			assertion:    assert.Error,
+		},
+		{
+			name:      "Malformed currency",
+			requested: "EURO",
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrInvalidCurrency.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callPrefer {
+				suite.mockRepo.EXPECT().GetUserCurrency(gomock.Any(), 2).Return(tt.preferred, nil)
+			}
+			if tt.callRate {
+				rate := entity.NewExchangeRate(tt.rateCurrency, suite.euroRate)
+				if tt.rateErr != nil {
+					suite.mockRepo.EXPECT().GetRate(gomock.Any(), tt.rateCurrency).Return(nil, tt.rateErr)
+				} else {
+					suite.mockRepo.EXPECT().GetRate(gomock.Any(), tt.rateCurrency).Return(&rate, nil)
+				}
+			}
+
+			currency, rate, err := suite.usecase.ResolveCurrency(context.Background(), 2, tt.requested)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			suite.Equal(tt.want, currency, "currency should be resolved correctly")
+			suite.Equal(tt.wantRate, rate, "rate should be resolved correctly")
+		})
+	}
+}
+
+func (suite *CurrencyUsecaseTestSuite) TestSetRate() {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		currency  string
+		rate      core.Rate
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Admin sets a rate",
+			ctx:       suite.adminCtx,
+			currency:  "eur",
+			rate:      suite.euroRate,
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Member cannot set a rate",
+			ctx:       suite.memberCtx,
+			currency:  "EUR",
+			rate:      suite.euroRate,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrCannotUpdateRate.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Base currency rate is fixed",
+			ctx:       suite.adminCtx,
+			currency:  "USD",
+			rate:      suite.euroRate,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrBaseCurrency.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Rate must be positive",
+			ctx:       suite.adminCtx,
+			currency:  "EUR",
+			rate:      0,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrInvalidRate.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown error",
+			ctx:       suite.adminCtx,
+			currency:  "EUR",
+			rate:      suite.euroRate,
+			callRepo:  true,
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotUpdateRate.Error()).WithDebug("this is an error"),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().UpsertRates(gomock.Any(), []entity.ExchangeRate{entity.NewExchangeRate("EUR", tt.rate)}).Return(tt.repoErr)
+			}
+
+			err := suite.usecase.SetRate(tt.ctx, tt.currency, tt.rate)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+		})
+	}
+}
+
+func (suite *CurrencyUsecaseTestSuite) TestLoadRates() {
+	tests := []struct {
+		name      string
+		fileRates map[string]core.Rate
+		fileErr   error
+		callRepo  bool
+		want      int
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Rates are loaded and the base currency is skipped",
+			fileRates: map[string]core.Rate{"eur": suite.euroRate, "USD": core.RateOne},
+			callRepo:  true,
+			want:      1,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Unreadable file",
+			fileErr:   errors.New("no such file"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotLoadRates.Error()).WithDebug("no such file"),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Malformed currency in file",
+			fileRates: map[string]core.Rate{"EURO": suite.euroRate},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrInvalidCurrency.Error()).WithDebug("EURO"),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockFile.EXPECT().ReadRates().Return(tt.fileRates, tt.fileErr)
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().UpsertRates(gomock.Any(), []entity.ExchangeRate{entity.NewExchangeRate("EUR", suite.euroRate)}).Return(nil)
+			}
+
+			loaded, err := suite.usecase.LoadRates(context.Background())
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			suite.Equal(tt.want, loaded, "number of loaded rates should be returned")
+		})
+	}
+}
+
+func (suite *CurrencyUsecaseTestSuite) TestLoadRatesWithoutFile() {
+	uc := usecase.NewUsecase(suite.mockRepo, nil, "USD")
+
+	_, err := uc.LoadRates(context.Background())
+
+	suite.ErrorIs(err, core.ErrBadRequest.WithError(entity.ErrCannotLoadRates.Error()), "loading without a rates file should fail")
+}
+
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
+
+func TestCurrencyUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CurrencyUsecaseTestSuite))
+}
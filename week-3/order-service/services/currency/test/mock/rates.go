@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/file/rates.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/file/rates.go -destination test/mock/rates.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	core "order_service/internal/core"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRatesFile is a mock of RatesFile interface.
+type MockRatesFile struct {
+	ctrl     *gomock.Controller
+	recorder *MockRatesFileMockRecorder
+}
+
+// MockRatesFileMockRecorder is the mock recorder for MockRatesFile.
+type MockRatesFileMockRecorder struct {
+	mock *MockRatesFile
+}
+
+// NewMockRatesFile creates a new mock instance.
+func NewMockRatesFile(ctrl *gomock.Controller) *MockRatesFile {
+	mock := &MockRatesFile{ctrl: ctrl}
+	mock.recorder = &MockRatesFileMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRatesFile) EXPECT() *MockRatesFileMockRecorder {
+	return m.recorder
+}
+
+// ReadRates mocks base method.
+func (m *MockRatesFile) ReadRates() (map[string]core.Rate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadRates")
+	ret0, _ := ret[0].(map[string]core.Rate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadRates indicates an expected call of ReadRates.
+func (mr *MockRatesFileMockRecorder) ReadRates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadRates", reflect.TypeOf((*MockRatesFile)(nil).ReadRates))
+}
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/postgres/store.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/postgres/store.go -destination test/mock/store.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/currency/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCurrencyRepository is a mock of CurrencyRepository interface.
+type MockCurrencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyRepositoryMockRecorder
+}
+
+// MockCurrencyRepositoryMockRecorder is the mock recorder for MockCurrencyRepository.
+type MockCurrencyRepositoryMockRecorder struct {
+	mock *MockCurrencyRepository
+}
+
+// NewMockCurrencyRepository creates a new mock instance.
+func NewMockCurrencyRepository(ctrl *gomock.Controller) *MockCurrencyRepository {
+	mock := &MockCurrencyRepository{ctrl: ctrl}
+	mock.recorder = &MockCurrencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyRepository) EXPECT() *MockCurrencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteRate mocks base method.
+func (m *MockCurrencyRepository) DeleteRate(ctx context.Context, currency string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRate", ctx, currency)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRate indicates an expected call of DeleteRate.
+func (mr *MockCurrencyRepositoryMockRecorder) DeleteRate(ctx, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRate", reflect.TypeOf((*MockCurrencyRepository)(nil).DeleteRate), ctx, currency)
+}
+
+// GetRate mocks base method.
+func (m *MockCurrencyRepository) GetRate(ctx context.Context, currency string) (*entity.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRate", ctx, currency)
+	ret0, _ := ret[0].(*entity.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRate indicates an expected call of GetRate.
+func (mr *MockCurrencyRepositoryMockRecorder) GetRate(ctx, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRate", reflect.TypeOf((*MockCurrencyRepository)(nil).GetRate), ctx, currency)
+}
+
+// GetRates mocks base method.
+func (m *MockCurrencyRepository) GetRates(ctx context.Context) (*[]entity.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRates", ctx)
+	ret0, _ := ret[0].(*[]entity.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRates indicates an expected call of GetRates.
+func (mr *MockCurrencyRepositoryMockRecorder) GetRates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRates", reflect.TypeOf((*MockCurrencyRepository)(nil).GetRates), ctx)
+}
+
+// GetUserCurrency mocks base method.
+func (m *MockCurrencyRepository) GetUserCurrency(ctx context.Context, userId int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCurrency", ctx, userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCurrency indicates an expected call of GetUserCurrency.
+func (mr *MockCurrencyRepositoryMockRecorder) GetUserCurrency(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCurrency", reflect.TypeOf((*MockCurrencyRepository)(nil).GetUserCurrency), ctx, userId)
+}
+
+// SetUserCurrency mocks base method.
+func (m *MockCurrencyRepository) SetUserCurrency(ctx context.Context, userId int, currency string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserCurrency", ctx, userId, currency)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserCurrency indicates an expected call of SetUserCurrency.
+func (mr *MockCurrencyRepositoryMockRecorder) SetUserCurrency(ctx, userId, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserCurrency", reflect.TypeOf((*MockCurrencyRepository)(nil).SetUserCurrency), ctx, userId, currency)
+}
+
+// UpsertRates mocks base method.
+func (m *MockCurrencyRepository) UpsertRates(ctx context.Context, rates []entity.ExchangeRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRates", ctx, rates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertRates indicates an expected call of UpsertRates.
+func (mr *MockCurrencyRepositoryMockRecorder) UpsertRates(ctx, rates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRates", reflect.TypeOf((*MockCurrencyRepository)(nil).UpsertRates), ctx, rates)
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/services/currency/entity"
+	ratesFile "order_service/services/currency/repository/file"
+	currencyRepo "order_service/services/currency/repository/postgres"
+)
+
+type CurrencyUsecase interface {
+	GetRates(ctx context.Context) (*[]entity.ExchangeRate, error)
+	SetRate(ctx context.Context, currency string, rate core.Rate) error
+	DeleteRate(ctx context.Context, currency string) error
+	ReloadRates(ctx context.Context) (int, error)
+	LoadRates(ctx context.Context) (int, error)
+	SetUserCurrency(ctx context.Context, currency string) error
+	ResolveCurrency(ctx context.Context, userId int, currency string) (string, core.Rate, error)
+}
+
+type currencyUsecase struct {
+	repo         currencyRepo.CurrencyRepository
+	ratesFile    ratesFile.RatesFile
+	baseCurrency string
+}
+
+// NewUsecase builds the currency usecase. Every price in the catalog is in baseCurrency,
+// ratesFile may be nil when rates are only managed through the API.
+func NewUsecase(repo currencyRepo.CurrencyRepository, ratesFile ratesFile.RatesFile, baseCurrency string) CurrencyUsecase {
+	return &currencyUsecase{
+		repo,
+		ratesFile,
+		baseCurrency,
+	}
+}
+
+func isAdmin(ctx context.Context) (bool, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return false, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return uid.GetRole() == 1, nil
+}
+
+func (uc *currencyUsecase) GetRates(ctx context.Context) (*[]entity.ExchangeRate, error) {
+	rates, err := uc.repo.GetRates(ctx)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetRates.Error()).WithDebug(err.Error())
+	}
+
+	// the base currency is never stored, list it first so clients see every currency they can pay in
+	result := append([]entity.ExchangeRate{entity.NewExchangeRate(uc.baseCurrency, core.RateOne)}, *rates...)
+
+	return &result, nil
+}
+
+func (uc *currencyUsecase) SetRate(ctx context.Context, currency string, rate core.Rate) error {
+	admin, err := isAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return core.ErrBadRequest.WithError(entity.ErrCannotUpdateRate.Error())
+	}
+
+	currency, err = entity.NormalizeCurrency(currency)
+	if err != nil {
+		return core.ErrBadRequest.WithError(err.Error())
+	}
+	if currency == uc.baseCurrency {
+		return core.ErrBadRequest.WithError(entity.ErrBaseCurrency.Error())
+	}
+	if !rate.IsPositive() {
+		return core.ErrBadRequest.WithError(entity.ErrInvalidRate.Error())
+	}
+
+	err = uc.repo.UpsertRates(ctx, []entity.ExchangeRate{entity.NewExchangeRate(currency, rate)})
+	if err != nil {
+		return core.ErrInternalServerError.WithError(entity.ErrCannotUpdateRate.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+func (uc *currencyUsecase) DeleteRate(ctx context.Context, currency string) error {
+	admin, err := isAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return core.ErrBadRequest.WithError(entity.ErrCannotDeleteRate.Error())
+	}
+
+	currency, err = entity.NormalizeCurrency(currency)
+	if err != nil {
+		return core.ErrBadRequest.WithError(err.Error())
+	}
+
+	err = uc.repo.DeleteRate(ctx, currency)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return core.ErrNotFound.WithError(entity.ErrUnsupportedCurrency.Error())
+		}
+		return core.ErrInternalServerError.WithError(entity.ErrCannotDeleteRate.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// ReloadRates is the admin facing LoadRates.
+func (uc *currencyUsecase) ReloadRates(ctx context.Context) (int, error) {
+	admin, err := isAdmin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !admin {
+		return 0, core.ErrBadRequest.WithError(entity.ErrCannotLoadRates.Error())
+	}
+
+	return uc.LoadRates(ctx)
+}
+
+// LoadRates upserts every rate of the rates file and returns how many were loaded.
+// Rates missing from the file are left untouched.
+func (uc *currencyUsecase) LoadRates(ctx context.Context) (int, error) {
+	if uc.ratesFile == nil {
+		return 0, core.ErrBadRequest.WithError(entity.ErrCannotLoadRates.Error())
+	}
+
+	fileRates, err := uc.ratesFile.ReadRates()
+	if err != nil {
+		return 0, core.ErrInternalServerError.WithError(entity.ErrCannotLoadRates.Error()).WithDebug(err.Error())
+	}
+
+	rates := make([]entity.ExchangeRate, 0, len(fileRates))
+	for code, rate := range fileRates {
+		currency, err := entity.NormalizeCurrency(code)
+		if err != nil {
+			return 0, core.ErrBadRequest.WithError(err.Error()).WithDebug(code)
+		}
+		if currency == uc.baseCurrency {
+			continue
+		}
+		if !rate.IsPositive() {
+			return 0, core.ErrBadRequest.WithError(entity.ErrInvalidRate.Error()).WithDebug(code)
+		}
+
+		rates = append(rates, entity.NewExchangeRate(currency, rate))
+	}
+
+	err = uc.repo.UpsertRates(ctx, rates)
+	if err != nil {
+		return 0, core.ErrInternalServerError.WithError(entity.ErrCannotLoadRates.Error()).WithDebug(err.Error())
+	}
+
+	return len(rates), nil
+}
+
+func (uc *currencyUsecase) SetUserCurrency(ctx context.Context, currency string) error {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	currency, _, err = uc.getRate(ctx, currency)
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.SetUserCurrency(ctx, int(uid.GetLocalID()), currency)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return core.ErrNotFound.WithError(entity.ErrCannotUpdateCurrency.Error())
+		}
+		return core.ErrInternalServerError.WithError(entity.ErrCannotUpdateCurrency.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// ResolveCurrency picks the currency a user is charged in and the rate from the base currency:
+// the requested currency first, then the user's preferred one, and the base currency otherwise.
+func (uc *currencyUsecase) ResolveCurrency(ctx context.Context, userId int, currency string) (string, core.Rate, error) {
+	if currency == "" {
+		preferred, err := uc.repo.GetUserCurrency(ctx, userId)
+		if err != nil && err != core.ErrRecordNotFound {
+			return "", 0, core.ErrInternalServerError.WithError(entity.ErrCannotGetRates.Error()).WithDebug(err.Error())
+		}
+
+		currency = preferred
+	}
+
+	if currency == "" {
+		return uc.baseCurrency, core.RateOne, nil
+	}
+
+	return uc.getRate(ctx, currency)
+}
+
+func (uc *currencyUsecase) getRate(ctx context.Context, currency string) (string, core.Rate, error) {
+	currency, err := entity.NormalizeCurrency(currency)
+	if err != nil {
+		return "", 0, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	if currency == uc.baseCurrency {
+		return currency, core.RateOne, nil
+	}
+
+	rate, err := uc.repo.GetRate(ctx, currency)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return "", 0, core.ErrBadRequest.WithError(entity.ErrUnsupportedCurrency.Error())
+		}
+		return "", 0, core.ErrInternalServerError.WithError(entity.ErrCannotGetRates.Error()).WithDebug(err.Error())
+	}
+
+	return currency, rate.GetRate(), nil
+}
@@ -0,0 +1,9 @@
+package entity
+
+import "errors"
+
+var (
+	ErrInvalidKey          = errors.New("idempotency key is not valid. Must be at most 255 characters")
+	ErrRequestInProgress   = errors.New("a request with the same idempotency key is still being processed")
+	ErrFingerprintMismatch = errors.New("idempotency key has already been used with a different request")
+)
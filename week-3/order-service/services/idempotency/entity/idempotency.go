@@ -0,0 +1,35 @@
+package entity
+
+// Record is what gets stored for a (user, idempotency key) pair.
+// It is saved as in-flight as soon as the first request comes in and completed with its response afterward.
+type Record struct {
+	Fingerprint string `json:"fingerprint"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+	StatusCode  int    `json:"status_code"`
+	Completed   bool   `json:"completed"`
+}
+
+func NewRecord(fingerprint string) Record {
+	return Record{
+		Fingerprint: fingerprint,
+		Completed:   false,
+	}
+}
+
+func (record *Record) Complete(statusCode int, contentType string, body []byte) {
+	if record != nil {
+		record.StatusCode = statusCode
+		record.ContentType = contentType
+		record.Body = body
+		record.Completed = true
+	}
+}
+
+func (record Record) GetFingerprint() string {
+	return record.Fingerprint
+}
+
+func (record Record) IsCompleted() bool {
+	return record.Completed
+}
@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"order_service/internal/core"
+	"order_service/services/idempotency/entity"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type IdempotencyRepository interface {
+	ReserveRecord(ctx context.Context, userID int, key string, record entity.Record, expiration int) (bool, error)
+	GetRecord(ctx context.Context, userID int, key string) (*entity.Record, error)
+	SetRecord(ctx context.Context, userID int, key string, record entity.Record, expiration int) error
+	DeleteRecord(ctx context.Context, userID int, key string) error
+}
+
+type redisRepo struct {
+	db *redis.Client
+}
+
+func NewIdempotencyRepo(db *redis.Client) IdempotencyRepository {
+	return &redisRepo{
+		db,
+	}
+}
+
+func (repo *redisRepo) ReserveRecord(ctx context.Context, userID int, key string, record entity.Record, expiration int) (bool, error) {
+	redisKey := fmt.Sprintf("idempotency:%d:%s", userID, key)
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := repo.db.SetNX(ctx, redisKey, value, time.Second*time.Duration(expiration)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+func (repo *redisRepo) GetRecord(ctx context.Context, userID int, key string) (*entity.Record, error) {
+	redisKey := fmt.Sprintf("idempotency:%d:%s", userID, key)
+
+	value, err := repo.db.Get(ctx, redisKey).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, core.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	var record entity.Record
+
+	err = json.Unmarshal(value, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (repo *redisRepo) SetRecord(ctx context.Context, userID int, key string, record entity.Record, expiration int) error {
+	redisKey := fmt.Sprintf("idempotency:%d:%s", userID, key)
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return repo.db.Set(ctx, redisKey, value, time.Second*time.Duration(expiration)).Err()
+}
+
+func (repo *redisRepo) DeleteRecord(ctx context.Context, userID int, key string) error {
+	redisKey := fmt.Sprintf("idempotency:%d:%s", userID, key)
+
+	return repo.db.Del(ctx, redisKey).Err()
+}
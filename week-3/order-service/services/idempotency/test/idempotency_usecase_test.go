@@ -0,0 +1,188 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	"order_service/services/idempotency/entity"
+	"order_service/services/idempotency/test/mock"
+	"order_service/services/idempotency/usecase"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type IdempotencyUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo *mock.MockIdempotencyRepository
+	usecase  usecase.IdempotencyUsecase
+}
+
+func (suite *IdempotencyUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockIdempotencyRepository(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, 60, 86400)
+}
+
+func (suite *IdempotencyUsecaseTestSuite) TestBegin() {
+	completed := entity.NewRecord("fingerprint")
+	completed.Complete(201, "application/json", []byte(`{"data":true}`))
+
+	inFlight := entity.NewRecord("fingerprint")
+
+	tests := []struct {
+		name       string
+		key        string
+		callRepo   bool
+		reserved   bool
+		reserveErr error
+		callGet    bool
+		stored     *entity.Record
+		getErr     error
+		want       *entity.Record
+		wantErr    error
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "First request reserves the key",
+			key:       "key-1",
+			callRepo:  true,
+			reserved:  true,
+			want:      nil,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Replay of a completed request",
+			key:       "key-1",
+			callRepo:  true,
+			reserved:  false,
+			callGet:   true,
+			stored:    &completed,
+			want:      &completed,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Replay with a different body",
+			key:       "key-1",
+			callRepo:  true,
+			reserved:  false,
+			callGet:   true,
+			stored:    &entity.Record{Fingerprint: "another", Completed: true},
+			want:      nil,
+			wantErr:   core.ErrUnprocessableEntity.WithError(entity.ErrFingerprintMismatch.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Replay while the first request is in flight",
+			key:       "key-1",
+			callRepo:  true,
+			reserved:  false,
+			callGet:   true,
+			stored:    &inFlight,
+			want:      nil,
+			wantErr:   core.ErrConfict.WithError(entity.ErrRequestInProgress.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Reservation expired in between",
+			key:       "key-1",
+			callRepo:  true,
+			reserved:  false,
+			callGet:   true,
+			getErr:    core.ErrRecordNotFound,
+			want:      nil,
+			wantErr:   core.ErrConfict.WithError(entity.ErrRequestInProgress.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:       "Repo return an error",
+			key:        "key-1",
+			callRepo:   true,
+			reserveErr: errors.New("this is an error"),
+			want:       nil,
+			wantErr:    core.ErrInternalServerError.WithDebug(errors.New("this is an error").Error()),
+			assertion:  assert.Error,
+		},
+		{
+			name:      "Key is too long",
+			key:       strings.Repeat("k", 256),
+			want:      nil,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrInvalidKey.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().ReserveRecord(gomock.Any(), 1, tt.key, entity.NewRecord("fingerprint"), 60).Return(tt.reserved, tt.reserveErr)
+			}
+			if tt.callGet {
+				suite.mockRepo.EXPECT().GetRecord(gomock.Any(), 1, tt.key).Return(tt.stored, tt.getErr)
+			}
+
+			record, err := suite.usecase.Begin(context.Background(), 1, tt.key, "fingerprint")
+
+			suite.Equal(tt.want, record, "record should be returned correctly")
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+		})
+	}
+}
+
+func (suite *IdempotencyUsecaseTestSuite) TestComplete() {
+	record := entity.NewRecord("fingerprint")
+	record.Complete(201, "application/json", []byte(`{"data":true}`))
+
+	suite.mockRepo.EXPECT().SetRecord(gomock.Any(), 1, "key-1", record, 86400).Return(nil)
+
+	err := suite.usecase.Complete(context.Background(), 1, "key-1", record)
+
+	suite.NoError(err, "record should be stored with the record expiration")
+}
+
+func (suite *IdempotencyUsecaseTestSuite) TestAbort() {
+	tests := []struct {
+		name      string
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Key released",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Repo return an error",
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().DeleteRecord(gomock.Any(), 1, "key-1").Return(tt.repoErr)
+
+			err := suite.usecase.Abort(context.Background(), 1, "key-1")
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+		})
+	}
+}
+
+func TestIdempotencyUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyUsecaseTestSuite))
+}
@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/redis/store.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/redis/store.go -destination test/mock/store.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/idempotency/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteRecord mocks base method.
+func (m *MockIdempotencyRepository) DeleteRecord(ctx context.Context, userID int, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecord", ctx, userID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecord indicates an expected call of DeleteRecord.
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteRecord(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecord", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteRecord), ctx, userID, key)
+}
+
+// GetRecord mocks base method.
+func (m *MockIdempotencyRepository) GetRecord(ctx context.Context, userID int, key string) (*entity.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecord", ctx, userID, key)
+	ret0, _ := ret[0].(*entity.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecord indicates an expected call of GetRecord.
+func (mr *MockIdempotencyRepositoryMockRecorder) GetRecord(ctx, userID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecord", reflect.TypeOf((*MockIdempotencyRepository)(nil).GetRecord), ctx, userID, key)
+}
+
+// ReserveRecord mocks base method.
+func (m *MockIdempotencyRepository) ReserveRecord(ctx context.Context, userID int, key string, record entity.Record, expiration int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveRecord", ctx, userID, key, record, expiration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveRecord indicates an expected call of ReserveRecord.
+func (mr *MockIdempotencyRepositoryMockRecorder) ReserveRecord(ctx, userID, key, record, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveRecord", reflect.TypeOf((*MockIdempotencyRepository)(nil).ReserveRecord), ctx, userID, key, record, expiration)
+}
+
+// SetRecord mocks base method.
+func (m *MockIdempotencyRepository) SetRecord(ctx context.Context, userID int, key string, record entity.Record, expiration int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRecord", ctx, userID, key, record, expiration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRecord indicates an expected call of SetRecord.
+func (mr *MockIdempotencyRepositoryMockRecorder) SetRecord(ctx, userID, key, record, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRecord", reflect.TypeOf((*MockIdempotencyRepository)(nil).SetRecord), ctx, userID, key, record, expiration)
+}
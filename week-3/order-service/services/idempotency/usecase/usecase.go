@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/services/idempotency/entity"
+	idempotencyRepo "order_service/services/idempotency/repository/redis"
+)
+
+type IdempotencyUsecase interface {
+	Begin(ctx context.Context, userId int, key, fingerprint string) (*entity.Record, error)
+	Complete(ctx context.Context, userId int, key string, record entity.Record) error
+	Abort(ctx context.Context, userId int, key string) error
+}
+
+type idempotencyUsecase struct {
+	repo              idempotencyRepo.IdempotencyRepository
+	lockExpireInSec   int
+	recordExpireInSec int
+}
+
+func NewUsecase(repo idempotencyRepo.IdempotencyRepository, lockExpireInSec, recordExpireInSec int) IdempotencyUsecase {
+	return &idempotencyUsecase{
+		repo,
+		lockExpireInSec,
+		recordExpireInSec,
+	}
+}
+
+// Begin reserves the key for a new request. It returns the stored record when the request is a replay
+// of a completed one, or nil when the caller should go on and process the request.
+func (uc *idempotencyUsecase) Begin(ctx context.Context, userId int, key, fingerprint string) (*entity.Record, error) {
+	if key == "" || len(key) > 255 {
+		return nil, core.ErrBadRequest.WithError(entity.ErrInvalidKey.Error())
+	}
+
+	reserved, err := uc.repo.ReserveRecord(ctx, userId, key, entity.NewRecord(fingerprint), uc.lockExpireInSec)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	record, err := uc.repo.GetRecord(ctx, userId, key)
+	if err != nil {
+		// the reservation expired between both calls, the client has to retry
+		if err == core.ErrRecordNotFound {
+			return nil, core.ErrConfict.WithError(entity.ErrRequestInProgress.Error())
+		}
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	if record.GetFingerprint() != fingerprint {
+		return nil, core.ErrUnprocessableEntity.WithError(entity.ErrFingerprintMismatch.Error())
+	}
+
+	if !record.IsCompleted() {
+		return nil, core.ErrConfict.WithError(entity.ErrRequestInProgress.Error())
+	}
+
+	return record, nil
+}
+
+func (uc *idempotencyUsecase) Complete(ctx context.Context, userId int, key string, record entity.Record) error {
+	err := uc.repo.SetRecord(ctx, userId, key, record, uc.recordExpireInSec)
+	if err != nil {
+		return core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// Abort releases the key so that the client can retry a request which did not produce a final response.
+func (uc *idempotencyUsecase) Abort(ctx context.Context, userId int, key string) error {
+	err := uc.repo.DeleteRecord(ctx, userId, key)
+	if err != nil {
+		return core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return nil
+}
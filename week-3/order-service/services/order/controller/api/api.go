@@ -6,6 +6,8 @@ import (
 	"order_service/pkg"
 	orderEntity "order_service/services/order/entity"
 	orderUsecase "order_service/services/order/usecase"
+	paymentEntity "order_service/services/payment/entity"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -17,8 +19,17 @@ type OrderService interface {
 	GetTopFiveOrdersByPrice(*fiber.Ctx) error
 	GetNumOfOrdersByMonth(*fiber.Ctx) error
 	GetOrder(*fiber.Ctx) error
+	UpdateOrderStatus(*fiber.Ctx) error
+	GetOrderStatusHistories(*fiber.Ctx) error
+	CancelOrder(*fiber.Ctx) error
+	PayOrder(*fiber.Ctx) error
+	GetOrderPayments(*fiber.Ctx) error
+	ReceivePaymentEvent(*fiber.Ctx) error
 }
 
+// HeaderSignature carries the hex encoded HMAC-SHA256 of a payment gateway callback's body.
+const HeaderSignature = "X-Signature"
+
 type service struct {
 	usecase orderUsecase.OrderUsecase
 }
@@ -31,15 +42,18 @@ func NewService(uc orderUsecase.OrderUsecase) OrderService {
 
 // Create Order godoc
 // @summary Create a new order
-// @description Create a new order with the input payload
+// @description Create a new order with the input payload, its stock is reserved until the order is paid or the reservation expires. It is shipped to the given address, the named saved address or else the default address of the user. The order is charged to the wallet balance or to a card, it stays pending until its payment is captured or is cancelled once its reservation expires, and a payment that cannot be made comes back failed with its reason
 // @tags orders
 // @accept application/json
 // @security BearerAuth
+// @param Idempotency-Key header string false "Key to safely retry the request without applying it twice"
 // @param payload body entity.OrderRequest true "Create order request body"
-// @success 201
+// @success 201 {object} entity.Order
 // @failure 400 {object} core.DefaultError
 // @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
 // @failure 409 {object} core.DefaultError
+// @failure 422 {object} core.DefaultError
 // @failure 500 {object} core.DefaultError
 // @router /orders/ [post]
 func (srv *service) CreateOrder(c *fiber.Ctx) error {
@@ -50,7 +64,7 @@ func (srv *service) CreateOrder(c *fiber.Ctx) error {
 	}
 
 	if err := data.Validate(); err != nil {
-		return core.ErrBadRequest.WithError(orderEntity.ErrItemEmpty.Error())
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
 	}
 
 	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
@@ -69,73 +83,141 @@ func (srv *service) CreateOrder(c *fiber.Ctx) error {
 	}
 
 	newOrder := orderEntity.NewOrder(0, 0, 0.0, newItems)
+	newOrder.SetCurrency(data.Currency)
+	newOrder.SetCouponCodes(data.GetCoupons())
+	newOrder.SetRegion(data.Region)
+	newOrder.SetAddressId(data.AddressId)
+	newOrder.SetShippingAddress(data.GetShippingAddress())
+	newOrder.SetPaymentRequest(data.GetPaymentRequest())
 
 	err := srv.usecase.CreateOrder(ctx, &newOrder)
 	if err != nil {
 		return pkg.WriteResponse(c, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(core.ResponseData(true))
+	return c.Status(fiber.StatusCreated).JSON(core.ResponseData(newOrder))
 }
 
 // Get Orders godoc
 // @summary Get Orders
-// @description Get all of orders of the current user or entire users based on user's role
+// @description Get a page of orders of the current user or entire users based on user's role
 // @tags orders
 // @security BearerAuth
+// @param limit query int false "Page size, 20 by default and 100 at most"
+// @param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @param start_date query string false "Orders created from this date (2006-01-02 or RFC 3339)"
+// @param end_date query string false "Orders created before this date (2006-01-02 or RFC 3339)"
+// @param min_total_price query number false "Minimum total price in the base currency"
+// @param max_total_price query number false "Maximum total price in the base currency"
+// @param sort query string false "Sort field" Enums(created_at, total_price)
+// @param order query string false "Sort direction" Enums(asc, desc)
+// @param user_id query int false "Only orders of this user, admin only"
 // @success 200 {array} entity.Order
+// @failure 400 {object} core.DefaultError
 // @failure 401 {object} core.DefaultError
 // @failure 404 {object} core.DefaultError
 // @failure 500 {object} core.DefaultError
 // @router /orders/ [get]
 func (srv *service) GetOrders(c *fiber.Ctx) error {
+	var data orderEntity.OrderListRequest
+
+	if err := c.QueryParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(orderEntity.ErrInvalidFilter.Error()).WithDebug(err.Error()))
+	}
+
+	filter, err := data.ToFilter()
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
 	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
 	if !ok {
 		return pkg.WriteResponse(c, core.ErrUnauthorized)
 	}
 	ctx := core.ContextWithRequester(c.Context(), requester)
 
-	orders, err := srv.usecase.GetOrders(ctx)
+	orders, nextCursor, err := srv.usecase.GetOrders(ctx, filter)
 	if err != nil {
 		return pkg.WriteResponse(c, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(core.ResponseData(orders))
+	return c.Status(fiber.StatusOK).JSON(core.ResponseDataWithPaging(orders, core.Paging{
+		NextCursor: nextCursor,
+		Limit:      filter.Limit,
+	}))
 }
 
 // Get Orders Summarize godoc
 // @summary Get Orders Summarize
-// @description Get summarized orders of the current user and export to excel
+// @description Get summarized orders of every user and download them as xlsx, csv, ndjson or ods, picked with the format query or the Accept header. Only admin can export the summary. Prefer queueing a summarize report with POST /reports
 // @tags orders
+// @deprecated
+// @produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,text/csv,application/x-ndjson,application/vnd.oasis.opendocument.spreadsheet
 // @security BearerAuth
+// @param format query string false "Report format" Enums(xlsx, csv, ndjson, ods)
 // @param payload body entity.OrdersSummarizeReq true "Order summary request body"
-// @success 301
+// @success 200 {file} file
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
 // @failure 500 {object} core.DefaultError
-// @router /orders/summarize [get]
+// @router /orders/summarize [post]
 func (srv *service) GetOrdersSummarize(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrInternalServerError.WithDebug(err.Error()))
+	}
+	// the summary covers the orders of every user, like the summarize report it is only for admin
+	if uid.GetRole() != 1 {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(orderEntity.ErrSummarizeAdminOnly.Error()))
+	}
+
 	var orderSummaryReq orderEntity.OrdersSummarizeReq
 
 	if err := c.BodyParser(&orderSummaryReq); err != nil {
 		return pkg.WriteResponse(c, err)
 	}
 
+	format, err := pkg.NegotiateReportFormat(c)
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
 	datas, err := srv.usecase.GetOrdersSummarize(c.Context(), orderSummaryReq.StartDate, orderSummaryReq.EndDate)
 	if err != nil {
 		return pkg.WriteResponse(c, err)
 	}
 
-	excelName, err := pkg.GenerateExcel(datas, orderSummaryReq.StartDate, orderSummaryReq.EndDate)
+	taxes, err := srv.usecase.GetTaxesSummarize(c.Context(), orderSummaryReq.StartDate, orderSummaryReq.EndDate)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	report := pkg.NewSummarizeReport(datas, taxes, orderSummaryReq.StartDate, orderSummaryReq.EndDate)
+	reportName, err := pkg.ExportReport(report, format)
 	if err != nil {
 		return pkg.WriteResponse(c, core.ErrInternalServerError.WithDebug(err.Error()))
 	}
 
-	return c.Redirect(fmt.Sprintf("/static/%s", excelName), fiber.StatusMovedPermanently)
+	// the file is only kept for this response
+	downloadName := fmt.Sprintf("summarize-%s-%s.%s", orderSummaryReq.StartDate.Format(time.DateOnly), orderSummaryReq.EndDate.Format(time.DateOnly), format)
+	err = pkg.SendDocument(c, reportName, downloadName, true)
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrInternalServerError.WithDebug(err.Error()))
+	}
+
+	return nil
 }
 
 // Get Top Five Orders Order By Price godoc
 // @summary Get Top Five Orders
-// @description Get top five orders order by price
+// @description Get the five most expensive orders by their price in the base currency, prefer GET /analytics/leaderboards/orders which takes a date range and a limit
 // @tags orders
+// @deprecated
 // @security BearerAuth
 // @success 200 {array} entity.Order
 // @failure 404 {object} core.DefaultError
@@ -152,11 +234,13 @@ func (srv *service) GetTopFiveOrdersByPrice(c *fiber.Ctx) error {
 
 // Get Order godoc
 // @summary Get Order
-// @description Get specific order of the current user and export to pdf
+// @description Get specific order of the current user and download it as a pdf invoice, prefer queueing an invoice report with POST /reports
 // @tags orders
+// @deprecated
+// @produce application/pdf
 // @security BearerAuth
 // @param orderID path int true "Order's ID"
-// @success 301
+// @success 200 {file} file
 // @failure 401 {object} core.DefaultError
 // @failure 404 {object} core.DefaultError
 // @failure 500 {object} core.DefaultError
@@ -183,18 +267,25 @@ func (srv *service) GetOrder(c *fiber.Ctx) error {
 		return pkg.WriteResponse(c, err)
 	}
 
-	err = pkg.GeneratePDF(order)
+	pdfName, err := pkg.GeneratePDF(order)
 	if err != nil {
 		return pkg.WriteResponse(c, core.ErrInternalServerError.WithDebug(err.Error()))
 	}
 
-	return c.Redirect(fmt.Sprintf("/static/invoice-%d-%d.pdf", order.GetUserIdSafe(), order.GetIdSafe()), fiber.StatusMovedPermanently)
+	// the file is only kept for this response
+	err = pkg.SendDocument(c, pdfName, fmt.Sprintf("invoice-%d.pdf", order.GetIdSafe()), true)
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrInternalServerError.WithDebug(err.Error()))
+	}
+
+	return nil
 }
 
 // Get Aggregated Orders By Month godoc
 // @summary Get Aggregated Orders By Month
-// @description Get aggregated orders group by month of the current user
+// @description Get aggregated orders group by month of the current user, prefer GET /analytics/orders?granularity=month
 // @tags orders
+// @deprecated
 // @security BearerAuth
 // @success 200
 // @failure 401 {object} core.DefaultError
@@ -220,3 +311,213 @@ func (srv *service) GetNumOfOrdersByMonth(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(core.ResponseData(orders))
 }
+
+// Update Order Status godoc
+// @summary Update Order Status
+// @description Move an order to the next status of its lifecycle, only admin can do this action. An order is only paid once its payment is captured
+// @tags orders
+// @accept application/json
+// @security BearerAuth
+// @param orderID path int true "Order's ID"
+// @param payload body entity.OrderStatusRequest true "Order status request body"
+// @success 200
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /orders/:orderID/status [patch]
+func (srv *service) UpdateOrderStatus(c *fiber.Ctx) error {
+	targetOrderId, err := c.ParamsInt("orderID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrNotFound)
+	}
+
+	var data orderEntity.OrderStatusRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err = srv.usecase.UpdateOrderStatus(ctx, targetOrderId, data.Status)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
+
+// Get Order Status Histories godoc
+// @summary Get Order Status Histories
+// @description Get every status transition of an order with the actor who made it
+// @tags orders
+// @security BearerAuth
+// @param orderID path int true "Order's ID"
+// @success 200 {array} entity.OrderStatusHistory
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /orders/:orderID/status [get]
+func (srv *service) GetOrderStatusHistories(c *fiber.Ctx) error {
+	targetOrderId, err := c.ParamsInt("orderID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrNotFound)
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	histories, err := srv.usecase.GetOrderStatusHistories(ctx, targetOrderId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(histories))
+}
+
+// Cancel Order godoc
+// @summary Cancel Order
+// @description Cancel an order, give back its stock and refund or void its payments. Customers can only cancel their own orders within the cancellation window, admin can cancel any order
+// @tags orders
+// @security BearerAuth
+// @param Idempotency-Key header string false "Key to safely retry the request without applying it twice"
+// @param orderID path int true "Order's ID"
+// @success 200
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 422 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /orders/:orderID/cancel [post]
+func (srv *service) CancelOrder(c *fiber.Ctx) error {
+	targetOrderId, err := c.ParamsInt("orderID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrNotFound)
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err = srv.usecase.CancelOrder(ctx, targetOrderId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
+
+// Pay Order godoc
+// @summary Pay Order
+// @description Make another attempt at paying a pending order of the current user, e.g. after a declined card. A wallet payment is captured right away, a card payment is confirmed by the gateway later
+// @tags orders
+// @accept application/json
+// @security BearerAuth
+// @param Idempotency-Key header string false "Key to safely retry the request without applying it twice"
+// @param orderID path int true "Order's ID"
+// @param payload body entity.PaymentRequest true "Payment request body"
+// @success 201 {object} entity.Payment
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /orders/:orderID/payments [post]
+func (srv *service) PayOrder(c *fiber.Ctx) error {
+	targetOrderId, err := c.ParamsInt("orderID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrNotFound)
+	}
+
+	var data paymentEntity.PaymentRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	payment, err := srv.usecase.PayOrder(ctx, targetOrderId, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(core.ResponseData(payment))
+}
+
+// Get Order Payments godoc
+// @summary Get Order Payments
+// @description Get every attempt at paying an order with the references of its provider
+// @tags orders
+// @security BearerAuth
+// @param orderID path int true "Order's ID"
+// @success 200 {array} entity.Payment
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /orders/:orderID/payments [get]
+func (srv *service) GetOrderPayments(c *fiber.Ctx) error {
+	targetOrderId, err := c.ParamsInt("orderID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrNotFound)
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	payments, err := srv.usecase.GetOrderPayments(ctx, targetOrderId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(payments))
+}
+
+// Receive Payment Event godoc
+// @summary Receive Payment Event
+// @description Payment gateway callback, the body has to be signed with the webhook secret in the X-Signature header as hex encoded HMAC-SHA256. The order is paid once its payment is captured
+// @tags payments
+// @accept application/json
+// @produce json
+// @param X-Signature header string true "HMAC-SHA256 of the body"
+// @param payload body entity.GatewayEvent true "Payment gateway event"
+// @success 200
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /payments/webhook [post]
+func (srv *service) ReceivePaymentEvent(c *fiber.Ctx) error {
+	err := srv.usecase.ReceivePaymentEvent(c.Context(), c.Body(), c.Get(HeaderSignature))
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
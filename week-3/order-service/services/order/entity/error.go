@@ -3,12 +3,29 @@ package entity
 import "errors"
 
 var (
-	ErrMissingField        = errors.New("missing item's field")
-	ErrInvalidMemory       = errors.New("invalid memory in required variable")
-	ErrNotEqual            = errors.New("products and order's items is not equal")
-	ErrItemEmpty           = errors.New("item cannot be empty")
-	ErrCannotCreateOrder   = errors.New("order cannot be create")
-	ErrInsufficientBalance = errors.New("order cannot be create because user's balance is insufficient")
-	ErrOutOfStock          = errors.New("one item in order's items is out of stock")
-	ErrOrderNotFound       = errors.New("cannot be found any orders")
+	ErrMissingField          = errors.New("missing item's field")
+	ErrInvalidMemory         = errors.New("invalid memory in required variable")
+	ErrNotEqual              = errors.New("products and order's items is not equal")
+	ErrItemEmpty             = errors.New("item cannot be empty")
+	ErrInvalidQuantity       = errors.New("quantity of order's items must be positive")
+	ErrCannotCreateOrder     = errors.New("order cannot be create")
+	ErrOutOfStock            = errors.New("one item in order's items is out of stock")
+	ErrProductNotFound       = errors.New("one item in order's items cannot be found")
+	ErrDuplicateItem         = errors.New("one product appears more than once in order's items")
+	ErrOrderNotFound         = errors.New("cannot be found any orders")
+	ErrInvalidOrderStatus    = errors.New("order status is not valid")
+	ErrInvalidTransition     = errors.New("order status cannot be changed to the requested status")
+	ErrCannotUpdateStatus    = errors.New("order status cannot be update")
+	ErrCannotCancelOrder     = errors.New("order cannot be cancel")
+	ErrOrderAlreadyCancelled = errors.New("order has already been cancelled")
+	ErrCancelWindowExpired   = errors.New("order can no longer be cancelled")
+	ErrInvalidCursor         = errors.New("cursor is not valid")
+	ErrInvalidFilter         = errors.New("order filter is not valid")
+	ErrTooManyCoupons        = errors.New("too many coupons for one order")
+	ErrDuplicateCoupon       = errors.New("one coupon appears more than once in order's coupons")
+	ErrReservationExpired    = errors.New("stock reserved for the order has been released, the order has to be cancelled")
+	ErrAmbiguousAddress      = errors.New("order can either name a saved address or give a shipping address, not both")
+	ErrCannotPayOrder        = errors.New("order cannot be pay")
+	ErrPaidByPayment         = errors.New("order is only paid once its payment is captured")
+	ErrSummarizeAdminOnly    = errors.New("only admin can export the orders summary")
 )
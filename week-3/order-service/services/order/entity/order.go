@@ -1,23 +1,52 @@
 package entity
 
-import "time"
-
-type OrderStatus string
+import (
+	"order_service/internal/core"
+	addressEntity "order_service/services/address/entity"
+	paymentEntity "order_service/services/payment/entity"
+	shipmentEntity "order_service/services/shipment/entity"
+	"strings"
+	"time"
+)
 
+// Order prices are in the charged Currency, converted from the base currency with ExchangeRate.
+// BaseTotalPrice is what the payment of the order is charged, in the base currency.
+// Both totals are net of the coupon Discounts, CouponCodes are the codes asked for at checkout.
+// They include the exclusive taxes of Region, Taxes breaks every tax rate down.
+// The stock of a new order is only reserved until ReservedUntil, it is taken out once the order is paid.
+// ShippingAddress is a copy of where the order is shipped, AddressId is the saved address asked for at checkout.
+// Shipments are the parcels handed to carriers with their tracking history, they are only read with one order.
+// An order stays pending until its payment is captured, or is cancelled once its reservation expires. Payment
+// is the attempt made when it was placed and PaymentRequest is how the user asked to pay.
 type Order struct {
-	CreatedAt  time.Time   `json:"created_at"`
-	UpdatedAt  *time.Time  `json:"updated_at"`
-	Items      []OrderItem `json:"items"`
-	Id         int         `json:"id"`
-	UserId     int         `json:"user_id"`
-	TotalPrice float32     `json:"total_price"`
+	CreatedAt       time.Time                     `json:"created_at"`
+	UpdatedAt       *time.Time                    `json:"updated_at"`
+	ReservedUntil   *time.Time                    `json:"reserved_until,omitempty"`
+	ShippingAddress *OrderAddress                 `json:"shipping_address"`
+	Shipments       []shipmentEntity.Shipment     `json:"shipments,omitempty" swaggertype:"array,object"`
+	Payment         *paymentEntity.Payment        `json:"payment,omitempty" swaggertype:"object"`
+	PaymentRequest  *paymentEntity.PaymentRequest `json:"-"`
+	Items           []OrderItem                   `json:"items"`
+	Discounts       []OrderDiscount               `json:"discounts"`
+	Taxes           []OrderTax                    `json:"taxes"`
+	CouponCodes     []string                      `json:"-"`
+	Status          OrderStatus                   `json:"status"`
+	Currency        string                        `json:"currency"`
+	Region          string                        `json:"region"`
+	Id              int                           `json:"id"`
+	UserId          int                           `json:"user_id"`
+	AddressId       int                           `json:"-"`
+	TotalPrice      core.Money                    `json:"total_price" swaggertype:"number"`
+	BaseTotalPrice  core.Money                    `json:"base_total_price" swaggertype:"number"`
+	ExchangeRate    core.Rate                     `json:"exchange_rate" swaggertype:"number"`
 }
 
-func NewOrder(id, userId int, totalPrice float32, items []OrderItem) Order {
+func NewOrder(id, userId int, totalPrice core.Money, items []OrderItem) Order {
 	return Order{
 		Id:         id,
 		UserId:     userId,
 		TotalPrice: totalPrice,
+		Status:     OrderStatusPending,
 		CreatedAt:  time.Now(),
 		Items:      items,
 	}
@@ -35,12 +64,48 @@ func (order *Order) SetUserId(id int) {
 	}
 }
 
-func (order *Order) SetTotalPrice(price float32) {
+func (order *Order) SetTotalPrice(price core.Money) {
 	if order != nil {
 		order.TotalPrice = price
 	}
 }
 
+func (order *Order) SetBaseTotalPrice(price core.Money) {
+	if order != nil {
+		order.BaseTotalPrice = price
+	}
+}
+
+func (order *Order) SetCurrency(currency string) {
+	if order != nil {
+		order.Currency = currency
+	}
+}
+
+func (order *Order) SetRegion(region string) {
+	if order != nil {
+		order.Region = region
+	}
+}
+
+func (order *Order) SetReservedUntil(until time.Time) {
+	if order != nil {
+		order.ReservedUntil = &until
+	}
+}
+
+func (order *Order) SetExchangeRate(rate core.Rate) {
+	if order != nil {
+		order.ExchangeRate = rate
+	}
+}
+
+func (order *Order) SetStatus(status OrderStatus) {
+	if order != nil {
+		order.Status = status
+	}
+}
+
 func (order *Order) SetCreatedAt(ca time.Time) {
 	if order != nil {
 		order.CreatedAt = ca
@@ -53,12 +118,60 @@ func (order *Order) SetUpdatedAt(ua *time.Time) {
 	}
 }
 
+func (order *Order) SetItems(items []OrderItem) {
+	if order != nil {
+		order.Items = items
+	}
+}
+
 func (order *Order) AddItem(item OrderItem) {
 	if order != nil {
 		order.Items = append(order.Items, item)
 	}
 }
 
+func (order *Order) SetCouponCodes(codes []string) {
+	if order != nil {
+		order.CouponCodes = codes
+	}
+}
+
+func (order *Order) SetAddressId(id int) {
+	if order != nil {
+		order.AddressId = id
+	}
+}
+
+func (order *Order) SetShippingAddress(address *OrderAddress) {
+	if order != nil {
+		order.ShippingAddress = address
+	}
+}
+
+func (order *Order) SetShipments(shipments []shipmentEntity.Shipment) {
+	if order != nil {
+		order.Shipments = shipments
+	}
+}
+
+func (order *Order) SetPayment(payment *paymentEntity.Payment) {
+	if order != nil {
+		order.Payment = payment
+	}
+}
+
+func (order *Order) SetPaymentRequest(request *paymentEntity.PaymentRequest) {
+	if order != nil {
+		order.PaymentRequest = request
+	}
+}
+
+func (order *Order) AddDiscount(discount OrderDiscount) {
+	if order != nil {
+		order.Discounts = append(order.Discounts, discount)
+	}
+}
+
 func (order *Order) GetIdSafe() int {
 	if order != nil {
 		return order.Id
@@ -75,7 +188,7 @@ func (order *Order) GetUserIdSafe() int {
 	return 0
 }
 
-func (order *Order) GetTotalPriceSafe() float32 {
+func (order *Order) GetTotalPriceSafe() core.Money {
 	if order != nil {
 		return order.TotalPrice
 	}
@@ -83,6 +196,38 @@ func (order *Order) GetTotalPriceSafe() float32 {
 	return 0
 }
 
+func (order *Order) GetBaseTotalPriceSafe() core.Money {
+	if order != nil {
+		return order.BaseTotalPrice
+	}
+
+	return 0
+}
+
+func (order *Order) GetCurrencySafe() string {
+	if order != nil {
+		return order.Currency
+	}
+
+	return ""
+}
+
+func (order *Order) GetExchangeRateSafe() core.Rate {
+	if order != nil {
+		return order.ExchangeRate
+	}
+
+	return 0
+}
+
+func (order *Order) GetStatusSafe() OrderStatus {
+	if order != nil {
+		return order.Status
+	}
+
+	return ""
+}
+
 func (order *Order) GetItemsSafe() []OrderItem {
 	if order != nil {
 		return order.Items
@@ -91,6 +236,85 @@ func (order *Order) GetItemsSafe() []OrderItem {
 	return []OrderItem{}
 }
 
+func (order *Order) GetCouponCodesSafe() []string {
+	if order != nil {
+		return order.CouponCodes
+	}
+
+	return []string{}
+}
+
+func (order *Order) AddTax(tax OrderTax) {
+	if order != nil {
+		order.Taxes = append(order.Taxes, tax)
+	}
+}
+
+func (order *Order) GetRegionSafe() string {
+	if order != nil {
+		return order.Region
+	}
+
+	return ""
+}
+
+func (order *Order) GetReservedUntilSafe() time.Time {
+	if order != nil && order.ReservedUntil != nil {
+		return *order.ReservedUntil
+	}
+
+	return time.Time{}
+}
+
+func (order *Order) GetAddressIdSafe() int {
+	if order != nil {
+		return order.AddressId
+	}
+
+	return 0
+}
+
+func (order *Order) GetShippingAddressSafe() *OrderAddress {
+	if order != nil {
+		return order.ShippingAddress
+	}
+
+	return nil
+}
+
+func (order *Order) GetShipmentsSafe() []shipmentEntity.Shipment {
+	if order != nil {
+		return order.Shipments
+	}
+
+	return nil
+}
+
+// GetPaymentRequestSafe returns how the user asked to pay, the wallet is charged when nothing was asked for.
+func (order *Order) GetPaymentRequestSafe() *paymentEntity.PaymentRequest {
+	if order != nil && order.PaymentRequest != nil {
+		return order.PaymentRequest
+	}
+
+	return &paymentEntity.PaymentRequest{}
+}
+
+func (order *Order) GetTaxesSafe() []OrderTax {
+	if order != nil {
+		return order.Taxes
+	}
+
+	return []OrderTax{}
+}
+
+func (order *Order) GetDiscountsSafe() []OrderDiscount {
+	if order != nil {
+		return order.Discounts
+	}
+
+	return []OrderDiscount{}
+}
+
 func (order *Order) GetItemSafe(idx int) *OrderItem {
 	if order != nil && idx >= 0 && idx < len(order.Items) {
 		return &order.Items[idx]
@@ -99,15 +323,22 @@ func (order *Order) GetItemSafe(idx int) *OrderItem {
 	return nil
 }
 
+// OrderItem Tax is the tax of the whole line at TaxRate, it is part of the line price when TaxInclusive.
 type OrderItem struct {
-	ProductName  string  `json:"product_name"`
-	OrderId      int     `json:"order_id"`
-	ProductId    int     `json:"product_id"`
-	Quantity     int     `json:"quantity"`
-	ProductPrice float32 `json:"product_price"`
+	ProductName  string     `json:"product_name"`
+	Currency     string     `json:"currency"`
+	TaxClass     string     `json:"tax_class"`
+	OrderId      int        `json:"order_id"`
+	ProductId    int        `json:"product_id"`
+	Quantity     int        `json:"quantity"`
+	ProductPrice core.Money `json:"product_price" swaggertype:"number"`
+	ExchangeRate core.Rate  `json:"exchange_rate" swaggertype:"number"`
+	Tax          core.Money `json:"tax" swaggertype:"number"`
+	TaxRate      core.Rate  `json:"tax_rate" swaggertype:"number"`
+	TaxInclusive bool       `json:"tax_inclusive"`
 }
 
-func NewOrderItem(orderId, productId int, productName string, productPrice float32, quantity int) OrderItem {
+func NewOrderItem(orderId, productId int, productName string, productPrice core.Money, quantity int) OrderItem {
 	return OrderItem{
 		OrderId:      orderId,
 		ProductId:    productId,
@@ -135,12 +366,38 @@ func (item *OrderItem) SetProductName(productName string) {
 	}
 }
 
-func (item *OrderItem) SetProductPrice(productPrice float32) {
+func (item *OrderItem) SetProductPrice(productPrice core.Money) {
 	if item != nil {
 		item.ProductPrice = productPrice
 	}
 }
 
+func (item *OrderItem) SetCurrency(currency string) {
+	if item != nil {
+		item.Currency = currency
+	}
+}
+
+func (item *OrderItem) SetExchangeRate(rate core.Rate) {
+	if item != nil {
+		item.ExchangeRate = rate
+	}
+}
+
+func (item *OrderItem) SetTaxClass(class string) {
+	if item != nil {
+		item.TaxClass = class
+	}
+}
+
+func (item *OrderItem) SetTax(tax core.Money, rate core.Rate, inclusive bool) {
+	if item != nil {
+		item.Tax = tax
+		item.TaxRate = rate
+		item.TaxInclusive = inclusive
+	}
+}
+
 func (item OrderItem) GetProductId() int {
 	return item.ProductId
 }
@@ -153,6 +410,194 @@ func (item OrderItem) GetProductName() string {
 	return item.ProductName
 }
 
-func (item OrderItem) GetProductPrice() float32 {
+func (item OrderItem) GetProductPrice() core.Money {
 	return item.ProductPrice
 }
+
+func (item OrderItem) GetCurrency() string {
+	return item.Currency
+}
+
+func (item OrderItem) GetExchangeRate() core.Rate {
+	return item.ExchangeRate
+}
+
+func (item OrderItem) GetTaxClass() string {
+	return item.TaxClass
+}
+
+func (item OrderItem) GetTax() core.Money {
+	return item.Tax
+}
+
+func (item OrderItem) GetTaxRate() core.Rate {
+	return item.TaxRate
+}
+
+func (item OrderItem) IsTaxInclusive() bool {
+	return item.TaxInclusive
+}
+
+// OrderDiscount is a coupon applied to an order. Amount is in the order's currency, BaseAmount in the base one.
+type OrderDiscount struct {
+	Code        string     `json:"code"`
+	Description string     `json:"description"`
+	OrderId     int        `json:"order_id"`
+	CouponId    int        `json:"coupon_id"`
+	Amount      core.Money `json:"amount" swaggertype:"number"`
+	BaseAmount  core.Money `json:"base_amount" swaggertype:"number"`
+}
+
+func NewOrderDiscount(orderId, couponId int, code, description string, amount, baseAmount core.Money) OrderDiscount {
+	return OrderDiscount{
+		OrderId:     orderId,
+		CouponId:    couponId,
+		Code:        code,
+		Description: description,
+		Amount:      amount,
+		BaseAmount:  baseAmount,
+	}
+}
+
+func (discount OrderDiscount) GetCouponId() int {
+	return discount.CouponId
+}
+
+func (discount OrderDiscount) GetCode() string {
+	return discount.Code
+}
+
+func (discount OrderDiscount) GetDescription() string {
+	return discount.Description
+}
+
+func (discount OrderDiscount) GetAmount() core.Money {
+	return discount.Amount
+}
+
+func (discount OrderDiscount) GetBaseAmount() core.Money {
+	return discount.BaseAmount
+}
+
+// OrderTax sums the tax of the order lines taxed at the same rate, in the order's currency.
+// The base amounts are the same sums in the base currency, so taxes of all orders can be added up.
+type OrderTax struct {
+	Name              string     `json:"name"`
+	OrderId           int        `json:"order_id"`
+	Rate              core.Rate  `json:"rate" swaggertype:"number"`
+	Inclusive         bool       `json:"inclusive"`
+	TaxableAmount     core.Money `json:"taxable_amount" swaggertype:"number"`
+	Amount            core.Money `json:"amount" swaggertype:"number"`
+	BaseTaxableAmount core.Money `json:"base_taxable_amount" swaggertype:"number"`
+	BaseAmount        core.Money `json:"base_amount" swaggertype:"number"`
+}
+
+func NewOrderTax(orderId int, name string, rate core.Rate, inclusive bool) OrderTax {
+	return OrderTax{
+		OrderId:   orderId,
+		Name:      name,
+		Rate:      rate,
+		Inclusive: inclusive,
+	}
+}
+
+// Add accounts for one more order line taxed at this rate.
+func (tax *OrderTax) Add(taxableAmount, amount, baseTaxableAmount, baseAmount core.Money) {
+	if tax != nil {
+		tax.TaxableAmount = tax.TaxableAmount.Add(taxableAmount)
+		tax.Amount = tax.Amount.Add(amount)
+		tax.BaseTaxableAmount = tax.BaseTaxableAmount.Add(baseTaxableAmount)
+		tax.BaseAmount = tax.BaseAmount.Add(baseAmount)
+	}
+}
+
+func (tax OrderTax) GetName() string {
+	return tax.Name
+}
+
+func (tax OrderTax) GetRate() core.Rate {
+	return tax.Rate
+}
+
+func (tax OrderTax) IsInclusive() bool {
+	return tax.Inclusive
+}
+
+func (tax OrderTax) GetTaxableAmount() core.Money {
+	return tax.TaxableAmount
+}
+
+func (tax OrderTax) GetAmount() core.Money {
+	return tax.Amount
+}
+
+func (tax OrderTax) GetBaseTaxableAmount() core.Money {
+	return tax.BaseTaxableAmount
+}
+
+func (tax OrderTax) GetBaseAmount() core.Money {
+	return tax.BaseAmount
+}
+
+// OrderAddress is the address an order is shipped to, copied when the order is placed so later edits of the
+// address book do not change it. AddressId is the saved address it was copied from, 0 when it was given inline.
+type OrderAddress struct {
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+	OrderId    int    `json:"order_id"`
+	AddressId  int    `json:"address_id"`
+}
+
+func NewOrderAddress(address *addressEntity.Address) *OrderAddress {
+	if address == nil {
+		return nil
+	}
+
+	return &OrderAddress{
+		AddressId:  address.Id,
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		State:      address.State,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+func (address *OrderAddress) SetOrderId(id int) {
+	if address != nil {
+		address.OrderId = id
+	}
+}
+
+// Lines is the address as it is written on a parcel, empty parts are left out.
+func (address *OrderAddress) Lines() []string {
+	if address == nil {
+		return []string{}
+	}
+
+	// like San Francisco, CA 94105
+	locality := strings.TrimSpace(address.State + " " + address.PostalCode)
+	if address.City != "" && locality != "" {
+		locality = address.City + ", " + locality
+	} else {
+		locality = address.City + locality
+	}
+
+	lines := make([]string, 0, 6)
+	for _, line := range []string{address.Recipient, address.Line1, address.Line2, locality, address.Country, address.Phone} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
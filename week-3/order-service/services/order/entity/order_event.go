@@ -0,0 +1,9 @@
+package entity
+
+// Order events are written to the outbox together with the change, other systems learn about orders
+// through them.
+const (
+	EventAggregateOrder = "order"
+	EventOrderCreated   = "order.created"
+	EventOrderCancelled = "order.cancelled"
+)
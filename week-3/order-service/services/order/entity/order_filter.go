@@ -0,0 +1,194 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"order_service/internal/core"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultOrderPageSize = 20
+	MaxOrderPageSize     = 100
+)
+
+type OrderSortField string
+
+const (
+	OrderSortByCreatedAt  OrderSortField = "created_at"
+	OrderSortByTotalPrice OrderSortField = "total_price"
+)
+
+// OrderListRequest holds the raw query parameters of the order listing.
+type OrderListRequest struct {
+	Cursor        string      `query:"cursor"`
+	StartDate     string      `query:"start_date"`
+	EndDate       string      `query:"end_date"`
+	Sort          string      `query:"sort"`
+	Order         string      `query:"order"`
+	MinTotalPrice *core.Money `query:"min_total_price"`
+	MaxTotalPrice *core.Money `query:"max_total_price"`
+	UserId        int         `query:"user_id"`
+	Limit         int         `query:"limit"`
+}
+
+// OrderFilter is the validated form of OrderListRequest the repository works with.
+// A zero UserId means orders of every user.
+type OrderFilter struct {
+	StartDate     *time.Time
+	EndDate       *time.Time
+	MinTotalPrice *core.Money
+	MaxTotalPrice *core.Money
+	Cursor        *OrderCursor
+	SortBy        OrderSortField
+	Descending    bool
+	UserId        int
+	Limit         int
+}
+
+// OrderCursor points right after the last order of a page. It carries the sort key of that order
+// alongside its id so that pages stay stable when several orders share the same sort value.
+type OrderCursor struct {
+	CreatedAt  *time.Time     `json:"created_at,omitempty"`
+	TotalPrice *core.Money    `json:"total_price,omitempty"`
+	SortBy     OrderSortField `json:"sort_by"`
+	Descending bool           `json:"desc"`
+	Id         int            `json:"id"`
+}
+
+func NewOrderCursor(filter *OrderFilter, order Order) OrderCursor {
+	cursor := OrderCursor{
+		SortBy:     filter.SortBy,
+		Descending: filter.Descending,
+		Id:         order.Id,
+	}
+
+	switch filter.SortBy {
+	case OrderSortByTotalPrice:
+		// orders are compared in the base currency, whatever currency they were charged in
+		totalPrice := order.BaseTotalPrice
+		cursor.TotalPrice = &totalPrice
+	default:
+		createdAt := order.CreatedAt
+		cursor.CreatedAt = &createdAt
+	}
+
+	return cursor
+}
+
+func (cursor OrderCursor) Encode() string {
+	bytes, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func DecodeOrderCursor(s string) (*OrderCursor, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor OrderCursor
+
+	if err := json.Unmarshal(bytes, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	switch cursor.SortBy {
+	case OrderSortByCreatedAt:
+		if cursor.CreatedAt == nil {
+			return nil, ErrInvalidCursor
+		}
+	case OrderSortByTotalPrice:
+		if cursor.TotalPrice == nil {
+			return nil, ErrInvalidCursor
+		}
+	default:
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func (data OrderListRequest) ToFilter() (*OrderFilter, error) {
+	filter := OrderFilter{
+		SortBy:        OrderSortByCreatedAt,
+		Descending:    true,
+		UserId:        data.UserId,
+		Limit:         data.Limit,
+		MinTotalPrice: data.MinTotalPrice,
+		MaxTotalPrice: data.MaxTotalPrice,
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultOrderPageSize
+	}
+	if filter.Limit > MaxOrderPageSize {
+		filter.Limit = MaxOrderPageSize
+	}
+
+	switch OrderSortField(data.Sort) {
+	case "", OrderSortByCreatedAt:
+	case OrderSortByTotalPrice:
+		filter.SortBy = OrderSortByTotalPrice
+	default:
+		return nil, ErrInvalidFilter
+	}
+
+	switch strings.ToLower(data.Order) {
+	case "", "desc":
+	case "asc":
+		filter.Descending = false
+	default:
+		return nil, ErrInvalidFilter
+	}
+
+	if data.StartDate != "" {
+		startDate, err := parseFilterDate(data.StartDate)
+		if err != nil {
+			return nil, ErrInvalidFilter
+		}
+		filter.StartDate = &startDate
+	}
+
+	if data.EndDate != "" {
+		endDate, err := parseFilterDate(data.EndDate)
+		if err != nil {
+			return nil, ErrInvalidFilter
+		}
+		filter.EndDate = &endDate
+	}
+
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		return nil, ErrInvalidFilter
+	}
+
+	if filter.MinTotalPrice != nil && filter.MaxTotalPrice != nil && *filter.MaxTotalPrice < *filter.MinTotalPrice {
+		return nil, ErrInvalidFilter
+	}
+
+	if data.Cursor != "" {
+		cursor, err := DecodeOrderCursor(data.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		// a cursor only makes sense for the ordering it was issued with
+		if cursor.SortBy != filter.SortBy || cursor.Descending != filter.Descending {
+			return nil, ErrInvalidCursor
+		}
+		filter.Cursor = cursor
+	}
+
+	return &filter, nil
+}
+
+// parseFilterDate accepts either a plain date or a full RFC 3339 timestamp.
+func parseFilterDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,57 @@
+package entity
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	ReservationActive    ReservationStatus = "active"
+	ReservationConfirmed ReservationStatus = "confirmed"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// ReservationSweeperActorId is the actor recorded on the orders cancelled because their reservation expired.
+const ReservationSweeperActorId = 0
+
+// Reservation holds Quantity units of a product for a pending order until ExpiresAt. Held units are
+// counted in products.reserved_quantity and only leave the stock once the reservation is confirmed.
+type Reservation struct {
+	ExpiresAt time.Time         `json:"expires_at"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt *time.Time        `json:"updated_at"`
+	Status    ReservationStatus `json:"status"`
+	Id        int               `json:"id"`
+	OrderId   int               `json:"order_id"`
+	ProductId int               `json:"product_id"`
+	Quantity  int               `json:"quantity"`
+}
+
+func (reservation Reservation) GetId() int {
+	return reservation.Id
+}
+
+func (reservation Reservation) GetProductId() int {
+	return reservation.ProductId
+}
+
+func (reservation Reservation) GetQuantity() int {
+	return reservation.Quantity
+}
+
+func (reservation Reservation) GetStatus() ReservationStatus {
+	return reservation.Status
+}
+
+// IsLapsed tells whether the units are no longer held, either because the reservation was released or
+// because its time is up and the sweeper has not got to it yet.
+func (reservation Reservation) IsLapsed(now time.Time) bool {
+	switch reservation.Status {
+	case ReservationReleased, ReservationExpired:
+		return true
+	case ReservationActive:
+		return !now.Before(reservation.ExpiresAt)
+	}
+
+	return false
+}
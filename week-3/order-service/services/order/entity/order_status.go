@@ -0,0 +1,66 @@
+package entity
+
+import "time"
+
+type OrderStatus string
+
+const (
+	OrderStatusPending    OrderStatus = "pending"
+	OrderStatusPaid       OrderStatus = "paid"
+	OrderStatusFulfilling OrderStatus = "fulfilling"
+	OrderStatusShipped    OrderStatus = "shipped"
+	OrderStatusDelivered  OrderStatus = "delivered"
+	OrderStatusCancelled  OrderStatus = "cancelled"
+)
+
+// orderStatusTransitions lists, for every status, the statuses an order is allowed to move to next.
+// delivered and cancelled are terminal states.
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:    {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:       {OrderStatusFulfilling, OrderStatusCancelled},
+	OrderStatusFulfilling: {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:    {OrderStatusDelivered},
+	OrderStatusDelivered:  {},
+	OrderStatusCancelled:  {},
+}
+
+func (status OrderStatus) IsValid() bool {
+	_, ok := orderStatusTransitions[status]
+
+	return ok
+}
+
+func (status OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (status OrderStatus) IsTerminal() bool {
+	return status.IsValid() && len(orderStatusTransitions[status]) == 0
+}
+
+// OrderStatusHistory records a single status transition of an order and who made it.
+// FromStatus is nil for the initial status an order is created with.
+type OrderStatusHistory struct {
+	CreatedAt  time.Time    `json:"created_at"`
+	FromStatus *OrderStatus `json:"from_status"`
+	ToStatus   OrderStatus  `json:"to_status"`
+	Id         int          `json:"id"`
+	OrderId    int          `json:"order_id"`
+	ActorId    int          `json:"actor_id"`
+}
+
+func NewOrderStatusHistory(orderId, actorId int, from *OrderStatus, to OrderStatus) OrderStatusHistory {
+	return OrderStatusHistory{
+		OrderId:    orderId,
+		ActorId:    actorId,
+		FromStatus: from,
+		ToStatus:   to,
+		CreatedAt:  time.Now(),
+	}
+}
@@ -1,27 +1,55 @@
 package entity
 
-import "time"
+import (
+	"order_service/internal/core"
+	addressEntity "order_service/services/address/entity"
+	couponEntity "order_service/services/coupon/entity"
+	paymentEntity "order_service/services/payment/entity"
+	"time"
+)
 
 type OrderRequest struct {
-	Items []ProductItem `json:"items"`
+	// Currency is optional, the user's preferred currency or the base currency is used when empty
+	Currency string        `json:"currency"`
+	Items    []ProductItem `json:"items"`
+	// Coupons are optional coupon codes, applied in the given order
+	Coupons []string `json:"coupons"`
+	// Region is where the order is taxed, e.g. US-CA, the configured default region is used when empty
+	Region string `json:"region"`
+	// AddressId is a saved address to ship to, the default address is used when neither it nor ShippingAddress is given
+	AddressId int `json:"address_id"`
+	// ShippingAddress is an address to ship to that is not saved in the address book
+	ShippingAddress *addressEntity.AddressRequest `json:"shipping_address" swaggertype:"object"`
+	// PaymentMethod is wallet or card, the wallet balance is charged when empty
+	PaymentMethod string `json:"payment_method" example:"wallet"`
+	// CardToken is the token of the card to charge, only for card payments
+	CardToken string `json:"card_token"`
 }
 
+// MaxOrderCoupons is how many coupons can be stacked on a single order.
+const MaxOrderCoupons = 5
+
 type ProductItem struct {
 	ProductId int `json:"product_id"`
 	Quantity  int `json:"quantity"`
 }
 
+type OrderStatusRequest struct {
+	Status OrderStatus `json:"status"`
+}
+
 type AggregatedOrdersByMonth struct {
 	Time        time.Time
 	NumOfOrders int
 }
 
 type OrdersSummarize struct {
-	UserId                   int     `json:"user_id"`
-	Username                 string  `json:"username"`
-	NumOfOrders              int     `json:"num_of_orders"`
-	SumOrderPrice            float32 `json:"sum_order_price"`
-	AverageOrderItemQuantity float32 `json:"average_order_item_quantity"`
+	UserId                   int        `json:"user_id"`
+	Username                 string     `json:"username"`
+	NumOfOrders              int        `json:"num_of_orders"`
+	SumOrderPrice            core.Money `json:"sum_order_price" swaggertype:"number"`
+	SumTax                   core.Money `json:"sum_tax" swaggertype:"number"`
+	AverageOrderItemQuantity float32    `json:"average_order_item_quantity"`
 }
 
 type OrdersSummarizeReq struct {
@@ -34,10 +62,62 @@ func (data OrderRequest) Validate() error {
 		return ErrItemEmpty
 	}
 
+	seen := make(map[int]bool, len(data.Items))
+
 	for _, item := range data.Items {
 		if item.ProductId == 0 || item.Quantity == 0 {
 			return ErrMissingField
 		}
+
+		// a negative quantity would give stock back and charge a negative total
+		if item.Quantity < 0 {
+			return ErrInvalidQuantity
+		}
+
+		if seen[item.ProductId] {
+			return ErrDuplicateItem
+		}
+		seen[item.ProductId] = true
+	}
+
+	if len(data.Coupons) > MaxOrderCoupons {
+		return ErrTooManyCoupons
+	}
+
+	seenCodes := make(map[string]bool, len(data.Coupons))
+
+	for _, code := range data.Coupons {
+		code = couponEntity.NormalizeCode(code)
+		if code == "" {
+			return ErrMissingField
+		}
+
+		if seenCodes[code] {
+			return ErrDuplicateCoupon
+		}
+		seenCodes[code] = true
+	}
+
+	if data.AddressId < 0 {
+		return addressEntity.ErrAddressNotFound
+	}
+
+	if data.ShippingAddress != nil {
+		if data.AddressId != 0 {
+			return ErrAmbiguousAddress
+		}
+
+		if err := data.ShippingAddress.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return data.GetPaymentRequest().Validate()
+}
+
+func (data OrderStatusRequest) Validate() error {
+	if !data.Status.IsValid() {
+		return ErrInvalidOrderStatus
 	}
 
 	return nil
@@ -47,6 +127,34 @@ func (data OrderRequest) GetItems() []ProductItem {
 	return data.Items
 }
 
+// GetCoupons returns the normalized coupon codes.
+func (data OrderRequest) GetCoupons() []string {
+	codes := make([]string, 0, len(data.Coupons))
+	for _, code := range data.Coupons {
+		codes = append(codes, couponEntity.NormalizeCode(code))
+	}
+
+	return codes
+}
+
+// GetShippingAddress returns the copy of the address given with the order, nil when none was given.
+func (data OrderRequest) GetShippingAddress() *OrderAddress {
+	if data.ShippingAddress == nil {
+		return nil
+	}
+
+	address := data.ShippingAddress.ToAddress()
+
+	return NewOrderAddress(&address)
+}
+
+func (data OrderRequest) GetPaymentRequest() *paymentEntity.PaymentRequest {
+	return &paymentEntity.PaymentRequest{
+		Method:    data.PaymentMethod,
+		CardToken: data.CardToken,
+	}
+}
+
 func (data ProductItem) GetItemId() int {
 	return data.ProductId
 }
@@ -3,10 +3,14 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"order_service/internal/core"
 	"order_service/pkg"
+	couponEntity "order_service/services/coupon/entity"
 	orderEntity "order_service/services/order/entity"
+	outboxEntity "order_service/services/outbox/entity"
 	productEntity "order_service/services/product/entity"
 	userEntity "order_service/services/user/entity"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -14,28 +18,61 @@ import (
 )
 
 type OrderRepository interface {
-	CreateOrder(ctx context.Context, order *orderEntity.Order, callbackFn func(order *orderEntity.Order, user *userEntity.User, products *[]productEntity.Product) (bool, error)) error
-	GetOrders(ctx context.Context) (*[]orderEntity.Order, error)
-	GetOrdersByUserId(ctx context.Context, userId int) (*[]orderEntity.Order, error)
+	CreateOrder(ctx context.Context, order *orderEntity.Order, callbackFn func(order *orderEntity.Order, user *userEntity.User, products *[]productEntity.Product, coupons *[]couponEntity.Coupon) (bool, error)) error
+	GetOrders(ctx context.Context, filter *orderEntity.OrderFilter) (*[]orderEntity.Order, error)
 	GetOrdersSummarize(ctx context.Context, startDate, endDate time.Time) (*[]orderEntity.OrdersSummarize, error)
+	GetTaxesSummarize(ctx context.Context, startDate, endDate time.Time) (*[]orderEntity.OrderTax, error)
+	ReleaseExpiredReservations(ctx context.Context, now time.Time, limit int) (int, error)
+	GetExpiredOrderIds(ctx context.Context, limit int) ([]int, error)
 	GetTopFiveOrdersByPrice(ctx context.Context) (*[]orderEntity.Order, error)
 	GetNumOfOrdersPerMonth(ctx context.Context, userId int) (*[]orderEntity.AggregatedOrdersByMonth, error)
 	GetOrder(ctx context.Context, userId, orderId int) (*orderEntity.Order, error)
+	UpdateOrderStatus(ctx context.Context, orderId, actorId int, status orderEntity.OrderStatus, callbackFn func(order *orderEntity.Order, status orderEntity.OrderStatus) error) error
+	GetOrderStatusHistories(ctx context.Context, orderId int) (*[]orderEntity.OrderStatusHistory, error)
+	CancelOrder(ctx context.Context, orderId, actorId int, callbackFn func(order *orderEntity.Order) error) error
+	GetUnrefundedOrderIds(ctx context.Context, cancelledBefore time.Time, limit int) ([]int, error)
+	MarkOrderRefunded(ctx context.Context, orderId int, now time.Time) error
 }
 
 const (
-	QUERY_GET_ORDERS                  = "SELECT o.id AS order_id, o.user_id, oi.product_id, oi.product_name, oi.product_price, oi.quantity, o.total_price, o.created_at, o.updated_at FROM orders AS o JOIN order_items AS oi ON o.id = oi.order_id"
-	QUERY_GET_ORDERS_BY_USER_ID       = "SELECT o.id AS order_id, o.user_id, oi.product_id, oi.product_name, oi.product_price, oi.quantity, o.total_price, o.created_at, o.updated_at FROM orders AS o JOIN order_items AS oi ON o.id = oi.order_id WHERE o.user_id = $1"
-	QUERY_GET_ORDERS_DESC_BY_PRICE    = "SELECT o.id as order_id, o.user_id, oi.product_id, oi.product_name, oi.product_price, oi.quantity, o.total_price, o.created_at, o.updated_at FROM orders AS o JOIN order_items AS oi ON o.id = oi.order_id ORDER BY o.total_price DESC LIMIT 5"
-	QUERY_GET_NUM_OF_ORDERS_PER_MONTH = "SELECT DATE_TRUNC('month', created_at) as time, COUNT(*) as num_of_orders FROM (SELECT * FROM orders AS o JOIN order_items AS oi ON o.id = oi.order_id WHERE o.user_id = $1) GROUP BY time ORDER BY time"
-	QUERY_GET_ORDERS_SUMMARIZE        = "SELECT u.id, u.username, COUNT(DISTINCT order_id) AS num_of_orders, SUM(COALESCE(product_price, 0)) AS sum_order_price, AVG(COALESCE(quantity, 0)) AS avg_order_item_quantity FROM users AS u LEFT JOIN (SELECT o.id AS order_id, o.user_id, o.total_price, oi.product_price, oi.quantity FROM orders AS o JOIN order_items AS oi ON o.id = oi.order_id WHERE (o.created_at BETWEEN DATE_TRUNC('day', CAST($1 AS DATE)) AND DATE_TRUNC('day', CAST($2 AS DATE)))) AS agg ON u.id = agg.user_id GROUP BY u.id"
-	QUERY_GET_ORDER                   = "SELECT o.id as order_id, o.user_id, oi.product_id, oi.product_name, oi.product_price, oi.quantity, o.total_price, o.created_at, o.updated_at FROM orders AS o JOIN order_items AS oi ON o.id = oi.order_id WHERE o.user_id = $1 AND o.id = $2"
-	QUERY_GET_USER_LOCK               = "SELECT * FROM users WHERE id = $1 FOR UPDATE"
-	QUERY_GET_PRODUCT_LOCK            = "SELECT * FROM products WHERE id = $1 FOR UPDATE"
-	QUERY_CREATE_ORDER_WITH_RETURN_ID = "INSERT INTO orders (user_id, total_price) VALUES ($1, $2) RETURNING id"
-	QUERY_CREATE_ORDER_ITEM           = "INSERT INTO order_items (order_id, product_id, product_name, product_price, quantity) VALUES ($1, $2, $3, $4, $5)"
-	QUERY_UPDATE_USER_BALANCE         = "UPDATE users SET balance = balance - $2, updated_at = $3 WHERE id = $1"
-	QUERY_UPDATE_PRODUCT_QUANTITY     = "UPDATE products SET quantity = quantity - ?, updated_at = ? WHERE id = ?"
+	QUERY_GET_ORDERS_PAGE             = "SELECT o.id, o.user_id, o.status, o.currency, o.region, o.exchange_rate, o.total_price, o.base_total_price, o.created_at, o.updated_at, COALESCE((SELECT json_agg(json_build_object('order_id', oi.order_id, 'product_id', oi.product_id, 'product_name', oi.product_name, 'product_price', oi.product_price, 'quantity', oi.quantity, 'currency', oi.currency, 'exchange_rate', oi.exchange_rate, 'tax_class', oi.tax_class, 'tax', oi.tax, 'tax_rate', oi.tax_rate, 'tax_inclusive', oi.tax_inclusive) ORDER BY oi.product_id) FROM order_items AS oi WHERE oi.order_id = o.id), '[]') AS items, COALESCE((SELECT json_agg(json_build_object('order_id', od.order_id, 'coupon_id', od.coupon_id, 'code', od.code, 'description', od.description, 'amount', od.amount, 'base_amount', od.base_amount) ORDER BY od.coupon_id) FROM order_discounts AS od WHERE od.order_id = o.id), '[]') AS discounts, COALESCE((SELECT json_agg(json_build_object('order_id', ot.order_id, 'name', ot.name, 'rate', ot.rate, 'inclusive', ot.inclusive, 'taxable_amount', ot.taxable_amount, 'amount', ot.amount, 'base_taxable_amount', ot.base_taxable_amount, 'base_amount', ot.base_amount) ORDER BY ot.id) FROM order_taxes AS ot WHERE ot.order_id = o.id), '[]') AS taxes, (SELECT json_build_object('order_id', osa.order_id, 'address_id', COALESCE(osa.address_id, 0), 'recipient', osa.recipient, 'phone', osa.phone, 'line1', osa.line1, 'line2', osa.line2, 'city', osa.city, 'state', osa.state, 'postal_code', osa.postal_code, 'country', osa.country) FROM order_shipping_addresses AS osa WHERE osa.order_id = o.id) AS shipping_address FROM orders AS o"
+	QUERY_GET_ORDERS_DESC_BY_PRICE    = "SELECT o.id as order_id, o.user_id, oi.product_id, oi.product_name, oi.product_price, oi.quantity, o.status, o.currency, o.exchange_rate, o.total_price, o.base_total_price, o.created_at, o.updated_at FROM (SELECT * FROM orders ORDER BY base_total_price DESC, id LIMIT 5) AS o JOIN order_items AS oi ON o.id = oi.order_id ORDER BY o.base_total_price DESC, o.id, oi.product_id"
+	QUERY_GET_NUM_OF_ORDERS_PER_MONTH = "SELECT DATE_TRUNC('month', created_at) as time, COUNT(*) as num_of_orders FROM orders WHERE user_id = $1 GROUP BY time ORDER BY time"
+	QUERY_GET_ORDERS_SUMMARIZE        = "SELECT u.id, u.username, COALESCE(SUM(s.num_of_orders), 0) AS num_of_orders, COALESCE(SUM(s.item_price), 0) AS sum_order_price, COALESCE(SUM(s.tax), 0) AS sum_tax, COALESCE(SUM(s.units_sold)::numeric / NULLIF(SUM(s.num_of_items), 0), 0) AS avg_order_item_quantity FROM users AS u LEFT JOIN daily_user_sales AS s ON s.user_id = u.id AND s.day >= CAST($1 AS DATE) AND s.day < CAST($2 AS DATE) GROUP BY u.id"
+	QUERY_GET_ORDER                   = "SELECT o.id as order_id, o.user_id, oi.product_id, oi.product_name, oi.product_price, oi.quantity, oi.tax_class, oi.tax, oi.tax_rate, oi.tax_inclusive, o.status, o.currency, o.region, o.exchange_rate, o.total_price, o.base_total_price, o.created_at, o.updated_at FROM orders AS o JOIN order_items AS oi ON o.id = oi.order_id WHERE o.user_id = $1 AND o.id = $2"
+	QUERY_GET_USER_LOCK               = "SELECT id, username, password, balance, created_at, updated_at FROM users WHERE id = $1 FOR UPDATE"
+	QUERY_GET_PRODUCTS_LOCK           = "SELECT id, name, quantity - reserved_quantity, price, tax_class, created_at, updated_at FROM products WHERE id = ANY($1) ORDER BY id FOR UPDATE"
+	QUERY_CREATE_ORDER_WITH_RETURN_ID = "INSERT INTO orders (user_id, total_price, status, currency, exchange_rate, base_total_price, region) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id"
+	QUERY_GET_ORDER_LOCK              = "SELECT id, user_id, status, currency, exchange_rate, total_price, base_total_price, created_at, updated_at FROM orders WHERE id = $1 FOR UPDATE"
+	QUERY_UPDATE_ORDER_STATUS         = "UPDATE orders SET status = $2, updated_at = $3 WHERE id = $1"
+	QUERY_CREATE_ORDER_STATUS_HISTORY = "INSERT INTO order_status_histories (order_id, from_status, to_status, actor_id, created_at) VALUES ($1, $2, $3, $4, $5)"
+	QUERY_GET_ORDER_ITEMS_BY_ORDER_ID = "SELECT order_id, product_id, product_name, product_price, quantity, currency, exchange_rate FROM order_items WHERE order_id = $1 ORDER BY product_id"
+	QUERY_RESTOCK_PRODUCTS_QUANTITY   = "UPDATE products AS p SET quantity = p.quantity + v.quantity, updated_at = $3 FROM unnest($1::int[], $2::int[]) AS v(id, quantity) WHERE p.id = v.id"
+	QUERY_CANCEL_ORDER                = "UPDATE orders SET status = $2, updated_at = $3 WHERE id = $1 AND status <> $2"
+	QUERY_GET_UNREFUNDED_ORDER_IDS    = "SELECT id FROM orders WHERE status = 'cancelled' AND refunded_at IS NULL AND updated_at <= $1 ORDER BY id LIMIT $2"
+	QUERY_MARK_ORDER_REFUNDED         = "UPDATE orders SET refunded_at = $2 WHERE id = $1 AND refunded_at IS NULL"
+	QUERY_GET_ORDER_STATUS_HISTORIES  = "SELECT id, order_id, from_status, to_status, actor_id, created_at FROM order_status_histories WHERE order_id = $1 ORDER BY created_at, id"
+	QUERY_RESERVE_PRODUCTS_QUANTITY   = "UPDATE products AS p SET reserved_quantity = p.reserved_quantity + v.quantity, updated_at = $3 FROM unnest($1::int[], $2::int[]) AS v(id, quantity) WHERE p.id = v.id"
+	QUERY_CONFIRM_PRODUCTS_QUANTITY   = "UPDATE products AS p SET quantity = p.quantity - v.quantity, reserved_quantity = p.reserved_quantity - v.quantity, updated_at = $3 FROM unnest($1::int[], $2::int[]) AS v(id, quantity) WHERE p.id = v.id"
+	QUERY_RELEASE_PRODUCTS_QUANTITY   = "UPDATE products AS p SET reserved_quantity = p.reserved_quantity - v.quantity, updated_at = $3 FROM unnest($1::int[], $2::int[]) AS v(id, quantity) WHERE p.id = v.id"
+	QUERY_GET_RESERVATIONS_LOCK       = "SELECT id, order_id, product_id, quantity, status, expires_at, created_at, updated_at FROM inventory_reservations WHERE order_id = $1 ORDER BY product_id FOR UPDATE"
+	QUERY_GET_EXPIRED_RESERVATIONS    = "SELECT id, order_id, product_id, quantity, status, expires_at, created_at, updated_at FROM inventory_reservations WHERE status = 'active' AND expires_at <= $1 ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED"
+	QUERY_GET_EXPIRED_ORDER_IDS       = "SELECT o.id FROM orders AS o WHERE o.status = 'pending' AND EXISTS (SELECT 1 FROM inventory_reservations AS r WHERE r.order_id = o.id AND r.status = 'expired') ORDER BY o.id LIMIT $1"
+	QUERY_UPDATE_RESERVATIONS_STATUS  = "UPDATE inventory_reservations SET status = $2, updated_at = $3 WHERE id = ANY($1)"
+	QUERY_GET_COUPONS_LOCK            = "SELECT id, code, type, percent_off, amount_off, product_id, buy_quantity, get_quantity, min_spend, usage_limit, per_user_limit, redeemed_count, active, starts_at, expires_at, created_at, updated_at FROM coupons WHERE code = ANY($1) ORDER BY id FOR UPDATE"
+	QUERY_COUNT_USER_REDEMPTIONS      = "SELECT coupon_id, COUNT(*) FROM coupon_redemptions WHERE user_id = $1 AND coupon_id = ANY($2) GROUP BY coupon_id"
+	QUERY_CREATE_COUPON_REDEMPTIONS   = "INSERT INTO coupon_redemptions (coupon_id, user_id, order_id, created_at) SELECT unnest($1::int[]), $2, $3, $4"
+	QUERY_REDEEM_COUPONS              = "UPDATE coupons SET redeemed_count = redeemed_count + 1, updated_at = $2 WHERE id = ANY($1)"
+	QUERY_RELEASE_COUPONS             = "WITH released AS (DELETE FROM coupon_redemptions WHERE order_id = $1 RETURNING coupon_id) UPDATE coupons AS c SET redeemed_count = c.redeemed_count - 1, updated_at = $2 FROM released AS r WHERE c.id = r.coupon_id"
+	QUERY_GET_ORDER_TAXES             = "SELECT order_id, name, rate, inclusive, taxable_amount, amount, base_taxable_amount, base_amount FROM order_taxes WHERE order_id = $1 ORDER BY id"
+	QUERY_GET_TAXES_SUMMARIZE         = "SELECT name, rate, inclusive, SUM(taxable_amount), SUM(amount) FROM daily_tax_sales WHERE day >= CAST($1 AS DATE) AND day < CAST($2 AS DATE) GROUP BY name, rate, inclusive HAVING SUM(taxable_amount) <> 0 OR SUM(amount) <> 0 ORDER BY name, rate"
+	QUERY_GET_ORDER_DISCOUNTS         = "SELECT order_id, coupon_id, code, description, amount, base_amount FROM order_discounts WHERE order_id = $1 ORDER BY coupon_id"
+	QUERY_CREATE_SHIPPING_ADDRESS     = "INSERT INTO order_shipping_addresses (order_id, address_id, recipient, phone, line1, line2, city, state, postal_code, country) VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7, $8, $9, $10)"
+	QUERY_GET_SHIPPING_ADDRESS        = "SELECT order_id, COALESCE(address_id, 0), recipient, phone, line1, line2, city, state, postal_code, country FROM order_shipping_addresses WHERE order_id = $1"
+	QUERY_CREATE_OUTBOX_EVENT         = "INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload, available_at, created_at) VALUES ($1, $2, $3, $4, $5, $5)"
+	QUERY_ADD_DAILY_USER_SALES        = "INSERT INTO daily_user_sales AS s (day, user_id, num_of_orders, revenue, item_price, num_of_items, units_sold, tax) SELECT (o.created_at::timestamptz AT TIME ZONE 'UTC')::date, o.user_id, $2, $2 * o.base_total_price, $2 * i.item_price, $2 * i.num_of_items, $2 * i.units_sold, $2 * COALESCE(t.tax, 0) FROM orders AS o CROSS JOIN LATERAL (SELECT COALESCE(SUM(ROUND(product_price / exchange_rate, 2)), 0) AS item_price, COUNT(*) AS num_of_items, COALESCE(SUM(quantity), 0) AS units_sold FROM order_items WHERE order_id = o.id) AS i CROSS JOIN LATERAL (SELECT SUM(base_amount) AS tax FROM order_taxes WHERE order_id = o.id) AS t WHERE o.id = $1 ON CONFLICT (day, user_id) DO UPDATE SET num_of_orders = s.num_of_orders + EXCLUDED.num_of_orders, revenue = s.revenue + EXCLUDED.revenue, item_price = s.item_price + EXCLUDED.item_price, num_of_items = s.num_of_items + EXCLUDED.num_of_items, units_sold = s.units_sold + EXCLUDED.units_sold, tax = s.tax + EXCLUDED.tax"
+	QUERY_ADD_DAILY_PRODUCT_SALES     = "INSERT INTO daily_product_sales AS s (day, product_id, num_of_orders, units_sold, revenue) SELECT (o.created_at::timestamptz AT TIME ZONE 'UTC')::date AS day, oi.product_id, $2, $2 * SUM(oi.quantity), $2 * SUM(ROUND(oi.product_price / oi.exchange_rate, 2) * oi.quantity) FROM orders AS o JOIN order_items AS oi ON oi.order_id = o.id WHERE o.id = $1 GROUP BY day, oi.product_id ON CONFLICT (day, product_id) DO UPDATE SET num_of_orders = s.num_of_orders + EXCLUDED.num_of_orders, units_sold = s.units_sold + EXCLUDED.units_sold, revenue = s.revenue + EXCLUDED.revenue"
+	QUERY_ADD_DAILY_TAX_SALES         = "INSERT INTO daily_tax_sales AS s (day, name, rate, inclusive, taxable_amount, amount) SELECT (o.created_at::timestamptz AT TIME ZONE 'UTC')::date AS day, ot.name, ot.rate, ot.inclusive, $2 * SUM(ot.base_taxable_amount), $2 * SUM(ot.base_amount) FROM orders AS o JOIN order_taxes AS ot ON ot.order_id = o.id WHERE o.id = $1 GROUP BY day, ot.name, ot.rate, ot.inclusive ON CONFLICT (day, name, rate, inclusive) DO UPDATE SET taxable_amount = s.taxable_amount + EXCLUDED.taxable_amount, amount = s.amount + EXCLUDED.amount"
 )
 
 type postgresRepo struct {
@@ -48,7 +85,7 @@ func NewOrderRepo(db *pgxpool.Pool) OrderRepository {
 	}
 }
 
-func (repo *postgresRepo) CreateOrder(ctx context.Context, order *orderEntity.Order, callbackFn func(order *orderEntity.Order, user *userEntity.User, products *[]productEntity.Product) (bool, error)) error {
+func (repo *postgresRepo) CreateOrder(ctx context.Context, order *orderEntity.Order, callbackFn func(order *orderEntity.Order, user *userEntity.User, products *[]productEntity.Product, coupons *[]couponEntity.Coupon) (bool, error)) error {
 	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
 		var user userEntity.User
 
@@ -57,23 +94,36 @@ func (repo *postgresRepo) CreateOrder(ctx context.Context, order *orderEntity.Or
 			return err
 		}
 
-		// fetch required datas
+		// fetch required datas, the callback expects products in the same order as the order's items
 		orderItems := order.GetItemsSafe()
-		products := make([]productEntity.Product, 0, len(orderItems))
+		productIds := make([]int, 0, len(orderItems))
 		for _, item := range orderItems {
-			var product productEntity.Product
+			productIds = append(productIds, item.GetProductId())
+		}
 
-			err := tx.QueryRow(ctx, QUERY_GET_PRODUCT_LOCK, item.GetProductId()).Scan(&product.Id, &product.Name, &product.Quantity, &product.Price, &product.CreatedAt, &product.UpdatedAt)
-			if err != nil {
-				return err
+		lockedProducts, err := lockProducts(ctx, tx, productIds)
+		if err != nil {
+			return err
+		}
+
+		products := make([]productEntity.Product, 0, len(orderItems))
+		for _, item := range orderItems {
+			product, ok := lockedProducts[item.GetProductId()]
+			if !ok {
+				return orderEntity.ErrProductNotFound
 			}
-			fmt.Println(product.GetQuantity())
 
 			products = append(products, product)
 		}
 
+		// coupons are locked under the user row, so the usage counts cannot move until this order is done
+		coupons, err := lockCoupons(ctx, tx, user.GetId(), order.GetCouponCodesSafe())
+		if err != nil {
+			return err
+		}
+
 		// run business logic
-		accept, err := callbackFn(order, &user, &products)
+		accept, err := callbackFn(order, &user, &products, &coupons)
 		if err != nil {
 			return err
 		}
@@ -83,30 +133,109 @@ func (repo *postgresRepo) CreateOrder(ctx context.Context, order *orderEntity.Or
 
 		var newOrderId int
 
-		err = tx.QueryRow(ctx, QUERY_CREATE_ORDER_WITH_RETURN_ID, order.GetUserIdSafe(), order.GetTotalPriceSafe()).Scan(&newOrderId)
+		err = tx.QueryRow(ctx, QUERY_CREATE_ORDER_WITH_RETURN_ID, order.GetUserIdSafe(), order.GetTotalPriceSafe(), order.GetStatusSafe(), order.GetCurrencySafe(), order.GetExchangeRateSafe(), order.GetBaseTotalPriceSafe(), order.GetRegionSafe()).Scan(&newOrderId)
 		if err != nil {
 			return err
 		}
 		order.SetId(newOrderId)
 
-		// handle product's stock and user's balance after ordered
-		orderItems = order.GetItemsSafe()
-		if len(orderItems) == 0 {
-			return orderEntity.ErrInvalidMemory
+		_, err = tx.Exec(ctx, QUERY_CREATE_ORDER_STATUS_HISTORY, order.GetIdSafe(), nil, order.GetStatusSafe(), order.GetUserIdSafe(), time.Now())
+		if err != nil {
+			return err
 		}
 
-		for idx, item := range orderItems {
-			_, err = tx.Exec(ctx, QUERY_CREATE_ORDER_ITEM, order.GetIdSafe(), item.GetProductId(), item.GetProductName(), item.GetProductPrice(), item.GetQuantity())
+		if address := order.GetShippingAddressSafe(); address != nil {
+			address.SetOrderId(order.GetIdSafe())
+
+			_, err = tx.Exec(ctx, QUERY_CREATE_SHIPPING_ADDRESS, address.OrderId, address.AddressId, address.Recipient, address.Phone, address.Line1, address.Line2, address.City, address.State, address.PostalCode, address.Country)
 			if err != nil {
 				return err
 			}
-			_, err = tx.Exec(ctx, QUERY_UPDATE_PRODUCT_QUANTITY, products[idx].GetId(), products[idx].GetQuantity(), time.Now())
+		}
+
+		err = createOrderEvent(ctx, tx, orderEntity.EventOrderCreated, order)
+		if err != nil {
+			return err
+		}
+
+		// handle product's stock after ordered, the order is paid later through its payment
+		orderItems = order.GetItemsSafe()
+		if len(orderItems) == 0 {
+			return orderEntity.ErrInvalidMemory
+		}
+
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"order_items"}, []string{"order_id", "product_id", "product_name", "product_price", "quantity", "currency", "exchange_rate", "tax_class", "tax", "tax_rate", "tax_inclusive"}, pgx.CopyFromSlice(len(orderItems), func(i int) ([]any, error) {
+			item := orderItems[i]
+
+			return []any{order.GetIdSafe(), item.GetProductId(), item.GetProductName(), item.GetProductPrice(), item.GetQuantity(), item.GetCurrency(), item.GetExchangeRate(), item.GetTaxClass(), item.GetTax(), item.GetTaxRate(), item.IsTaxInclusive()}, nil
+		}))
+		if err != nil {
+			return err
+		}
+
+		// the callback leaves the quantity to take out of stock on every product, it is only held for now
+		// and taken out once the order is paid
+		quantities := make([]int, 0, len(products))
+		for _, product := range products {
+			quantities = append(quantities, product.GetQuantity())
+		}
+
+		now := time.Now()
+
+		_, err = tx.Exec(ctx, QUERY_RESERVE_PRODUCTS_QUANTITY, productIds, quantities, now)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"inventory_reservations"}, []string{"order_id", "product_id", "quantity", "status", "expires_at", "created_at"}, pgx.CopyFromSlice(len(productIds), func(i int) ([]any, error) {
+			return []any{order.GetIdSafe(), productIds[i], quantities[i], string(orderEntity.ReservationActive), order.GetReservedUntilSafe(), now}, nil
+		}))
+		if err != nil {
+			return err
+		}
+
+		taxes := order.GetTaxesSafe()
+		if len(taxes) > 0 {
+			_, err = tx.CopyFrom(ctx, pgx.Identifier{"order_taxes"}, []string{"order_id", "name", "rate", "inclusive", "taxable_amount", "amount", "base_taxable_amount", "base_amount"}, pgx.CopyFromSlice(len(taxes), func(i int) ([]any, error) {
+				tax := taxes[i]
+
+				return []any{order.GetIdSafe(), tax.GetName(), tax.GetRate(), tax.IsInclusive(), tax.GetTaxableAmount(), tax.GetAmount(), tax.GetBaseTaxableAmount(), tax.GetBaseAmount()}, nil
+			}))
 			if err != nil {
 				return err
 			}
 		}
 
-		_, err = tx.Exec(ctx, QUERY_UPDATE_USER_BALANCE, user.GetId(), user.GetBalance(), time.Now())
+		err = addDailySales(ctx, tx, order.GetIdSafe(), 1)
+		if err != nil {
+			return err
+		}
+
+		discounts := order.GetDiscountsSafe()
+		if len(discounts) == 0 {
+			return nil
+		}
+
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"order_discounts"}, []string{"order_id", "coupon_id", "code", "description", "amount", "base_amount"}, pgx.CopyFromSlice(len(discounts), func(i int) ([]any, error) {
+			discount := discounts[i]
+
+			return []any{order.GetIdSafe(), discount.GetCouponId(), discount.GetCode(), discount.GetDescription(), discount.GetAmount(), discount.GetBaseAmount()}, nil
+		}))
+		if err != nil {
+			return err
+		}
+
+		couponIds := make([]int, 0, len(discounts))
+		for _, discount := range discounts {
+			couponIds = append(couponIds, discount.GetCouponId())
+		}
+
+		_, err = tx.Exec(ctx, QUERY_CREATE_COUPON_REDEMPTIONS, couponIds, user.GetId(), order.GetIdSafe(), now)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_REDEEM_COUPONS, couponIds, now)
 		if err != nil {
 			return err
 		}
@@ -115,94 +244,323 @@ func (repo *postgresRepo) CreateOrder(ctx context.Context, order *orderEntity.Or
 	})
 }
 
-func (repo *postgresRepo) GetOrders(ctx context.Context) (*[]orderEntity.Order, error) {
-	rows, err := repo.db.Query(ctx, QUERY_GET_ORDERS)
+// lockCoupons locks the coupons behind the given codes, in id order like lockProducts, and fills in how many
+// times the user already redeemed each of them. Coupons come back in the same order as the codes.
+func lockCoupons(ctx context.Context, tx pgx.Tx, userId int, codes []string) ([]couponEntity.Coupon, error) {
+	if len(codes) == 0 {
+		return []couponEntity.Coupon{}, nil
+	}
+
+	rows, err := tx.Query(ctx, QUERY_GET_COUPONS_LOCK, codes)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	ordersMap := make(map[int]*orderEntity.Order)
+	lockedCoupons, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (couponEntity.Coupon, error) {
+		var coupon couponEntity.Coupon
 
-	for rows.Next() {
-		var orderId, userId, productId, quantity int
-		var productName string
-		var productPrice, totalPrice float32
-		var createdAt time.Time
-		var updatedAt *time.Time
-
-		err := rows.Scan(&orderId, &userId, &productId, &productName, &productPrice, &quantity, &totalPrice, &createdAt, &updatedAt)
+		err := row.Scan(&coupon.Id, &coupon.Code, &coupon.Type, &coupon.PercentOff, &coupon.AmountOff, &coupon.ProductId, &coupon.BuyQuantity, &coupon.GetQuantity, &coupon.MinSpend, &coupon.UsageLimit, &coupon.PerUserLimit, &coupon.RedeemedCount, &coupon.Active, &coupon.StartsAt, &coupon.ExpiresAt, &coupon.CreatedAt, &coupon.UpdatedAt)
 		if err != nil {
-			return nil, err
+			return couponEntity.Coupon{}, err
 		}
 
-		item := orderEntity.NewOrderItem(orderId, productId, productName, productPrice, quantity)
+		return coupon, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if _, exists := ordersMap[orderId]; !exists {
-			ordersMap[orderId] = &orderEntity.Order{
-				Id:         orderId,
-				UserId:     userId,
-				TotalPrice: totalPrice,
-				Items:      []orderEntity.OrderItem{item},
-			}
-		} else {
-			ordersMap[orderId].Items = append(ordersMap[orderId].Items, item)
+	couponsMap := make(map[string]couponEntity.Coupon, len(lockedCoupons))
+	couponIds := make([]int, 0, len(lockedCoupons))
+	for _, coupon := range lockedCoupons {
+		couponsMap[coupon.GetCode()] = coupon
+		couponIds = append(couponIds, coupon.GetId())
+	}
+
+	rows, err = tx.Query(ctx, QUERY_COUNT_USER_REDEMPTIONS, userId, couponIds)
+	if err != nil {
+		return nil, err
+	}
+
+	redemptions := make(map[int]int, len(couponIds))
+
+	var couponId, count int
+	_, err = pgx.ForEachRow(rows, []any{&couponId, &count}, func() error {
+		redemptions[couponId] = count
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	coupons := make([]couponEntity.Coupon, 0, len(codes))
+	for _, code := range codes {
+		coupon, ok := couponsMap[code]
+		if !ok {
+			return nil, couponEntity.ErrCouponNotFound
 		}
+
+		coupon.UserRedeemedCount = redemptions[coupon.GetId()]
+		coupons = append(coupons, coupon)
 	}
 
-	var orders []orderEntity.Order
+	return coupons, nil
+}
 
-	for _, order := range ordersMap {
-		orders = append(orders, *order)
+// lockProducts locks every given product with a single statement. Rows are always locked in id order,
+// whatever order they were asked in, so two transactions sharing products cannot deadlock each other.
+func lockProducts(ctx context.Context, tx pgx.Tx, productIds []int) (map[int]productEntity.Product, error) {
+	rows, err := tx.Query(ctx, QUERY_GET_PRODUCTS_LOCK, productIds)
+	if err != nil {
+		return nil, err
 	}
 
-	return &orders, nil
+	products, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (productEntity.Product, error) {
+		var product productEntity.Product
+
+		err := row.Scan(&product.Id, &product.Name, &product.Quantity, &product.Price, &product.TaxClass, &product.CreatedAt, &product.UpdatedAt)
+		if err != nil {
+			return productEntity.Product{}, err
+		}
+
+		return product, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	productsMap := make(map[int]productEntity.Product, len(products))
+	for _, product := range products {
+		productsMap[product.GetId()] = product
+	}
+
+	return productsMap, nil
+}
+
+func collectReservations(rows pgx.Rows) ([]orderEntity.Reservation, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (orderEntity.Reservation, error) {
+		var reservation orderEntity.Reservation
+
+		err := row.Scan(&reservation.Id, &reservation.OrderId, &reservation.ProductId, &reservation.Quantity, &reservation.Status, &reservation.ExpiresAt, &reservation.CreatedAt, &reservation.UpdatedAt)
+		if err != nil {
+			return orderEntity.Reservation{}, err
+		}
+
+		return reservation, nil
+	})
 }
 
-func (repo *postgresRepo) GetOrdersByUserId(ctx context.Context, userId int) (*[]orderEntity.Order, error) {
-	rows, err := repo.db.Query(ctx, QUERY_GET_ORDERS_BY_USER_ID)
+// lockReservations locks the reservations of an order. Reservations are always locked before products,
+// like the sweeper does, so both cannot deadlock each other.
+func lockReservations(ctx context.Context, tx pgx.Tx, orderId int) ([]orderEntity.Reservation, error) {
+	rows, err := tx.Query(ctx, QUERY_GET_RESERVATIONS_LOCK, orderId)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	ordersMap := make(map[int]*orderEntity.Order)
+	return collectReservations(rows)
+}
 
-	for rows.Next() {
-		var orderId, userId, productId, quantity int
-		var productName string
-		var productPrice, totalPrice float32
-		var createdAt time.Time
-		var updatedAt *time.Time
+// confirmReservations takes the units held for an order out of the stock. Orders placed before
+// reservations, or already confirmed ones, have nothing left to take out.
+func confirmReservations(ctx context.Context, tx pgx.Tx, orderId int, now time.Time) error {
+	reservations, err := lockReservations(ctx, tx, orderId)
+	if err != nil {
+		return err
+	}
+
+	productIds := make([]int, 0, len(reservations))
+	quantities := make([]int, 0, len(reservations))
+	reservationIds := make([]int, 0, len(reservations))
+
+	for _, reservation := range reservations {
+		if reservation.IsLapsed(now) {
+			return orderEntity.ErrReservationExpired
+		}
+		if reservation.GetStatus() != orderEntity.ReservationActive {
+			continue
+		}
+
+		productIds = append(productIds, reservation.GetProductId())
+		quantities = append(quantities, reservation.GetQuantity())
+		reservationIds = append(reservationIds, reservation.GetId())
+	}
+
+	if len(reservationIds) == 0 {
+		return nil
+	}
+
+	_, err = lockProducts(ctx, tx, productIds)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, QUERY_CONFIRM_PRODUCTS_QUANTITY, productIds, quantities, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, QUERY_UPDATE_RESERVATIONS_STATUS, reservationIds, orderEntity.ReservationConfirmed, now)
 
-		err := rows.Scan(&orderId, &userId, &productId, &productName, &productPrice, &quantity, &totalPrice, &createdAt, &updatedAt)
+	return err
+}
+
+// ReleaseExpiredReservations gives the units of at most limit reservations that ran out of time back to
+// the available stock. Rows locked by an order being paid or cancelled are skipped until the next run.
+func (repo *postgresRepo) ReleaseExpiredReservations(ctx context.Context, now time.Time, limit int) (int, error) {
+	released := 0
+
+	err := pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, QUERY_GET_EXPIRED_RESERVATIONS, now, limit)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		item := orderEntity.NewOrderItem(orderId, productId, productName, productPrice, quantity)
+		reservations, err := collectReservations(rows)
+		if err != nil {
+			return err
+		}
+		if len(reservations) == 0 {
+			return nil
+		}
 
-		if _, exists := ordersMap[orderId]; !exists {
-			ordersMap[orderId] = &orderEntity.Order{
-				Id:         orderId,
-				UserId:     userId,
-				TotalPrice: totalPrice,
-				Items:      []orderEntity.OrderItem{item},
-			}
-		} else {
-			ordersMap[orderId].Items = append(ordersMap[orderId].Items, item)
+		// one product can be held by several orders, an update joined on duplicated ids would only apply one
+		releasedQuantities := make(map[int]int, len(reservations))
+		reservationIds := make([]int, 0, len(reservations))
+		for _, reservation := range reservations {
+			releasedQuantities[reservation.GetProductId()] += reservation.GetQuantity()
+			reservationIds = append(reservationIds, reservation.GetId())
 		}
+
+		productIds := make([]int, 0, len(releasedQuantities))
+		quantities := make([]int, 0, len(releasedQuantities))
+		for productId, quantity := range releasedQuantities {
+			productIds = append(productIds, productId)
+			quantities = append(quantities, quantity)
+		}
+
+		_, err = lockProducts(ctx, tx, productIds)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_RELEASE_PRODUCTS_QUANTITY, productIds, quantities, now)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_UPDATE_RESERVATIONS_STATUS, reservationIds, orderEntity.ReservationExpired, now)
+		if err != nil {
+			return err
+		}
+
+		released = len(reservations)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	var orders []orderEntity.Order
+	return released, nil
+}
+
+// GetExpiredOrderIds returns at most limit pending orders whose stock was released because their reservation
+// expired.
+func (repo *postgresRepo) GetExpiredOrderIds(ctx context.Context, limit int) ([]int, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_EXPIRED_ORDER_IDS, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+func (repo *postgresRepo) GetOrders(ctx context.Context, filter *orderEntity.OrderFilter) (*[]orderEntity.Order, error) {
+	query, args := buildGetOrdersQuery(filter)
+
+	rows, err := repo.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (orderEntity.Order, error) {
+		var order orderEntity.Order
 
-	for _, order := range ordersMap {
-		orders = append(orders, *order)
+		err := row.Scan(&order.Id, &order.UserId, &order.Status, &order.Currency, &order.Region, &order.ExchangeRate, &order.TotalPrice, &order.BaseTotalPrice, &order.CreatedAt, &order.UpdatedAt, &order.Items, &order.Discounts, &order.Taxes, &order.ShippingAddress)
+		if err != nil {
+			return orderEntity.Order{}, err
+		}
+
+		return order, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &orders, nil
 }
 
+// buildGetOrdersQuery turns the filter into a keyset paginated query, every order of the page
+// comes with its items aggregated as json so the page is hydrated in a single round trip.
+func buildGetOrdersQuery(filter *orderEntity.OrderFilter) (string, []any) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	addArg := func(arg any) string {
+		args = append(args, arg)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.UserId != 0 {
+		conditions = append(conditions, fmt.Sprintf("o.user_id = %s", addArg(filter.UserId)))
+	}
+	if filter.StartDate != nil {
+		conditions = append(conditions, fmt.Sprintf("o.created_at >= %s", addArg(*filter.StartDate)))
+	}
+	if filter.EndDate != nil {
+		conditions = append(conditions, fmt.Sprintf("o.created_at < %s", addArg(*filter.EndDate)))
+	}
+	if filter.MinTotalPrice != nil {
+		conditions = append(conditions, fmt.Sprintf("o.base_total_price >= %s", addArg(*filter.MinTotalPrice)))
+	}
+	if filter.MaxTotalPrice != nil {
+		conditions = append(conditions, fmt.Sprintf("o.base_total_price <= %s", addArg(*filter.MaxTotalPrice)))
+	}
+
+	sortColumn := "o.created_at"
+	if filter.SortBy == orderEntity.OrderSortByTotalPrice {
+		sortColumn = "o.base_total_price"
+	}
+
+	direction, comparator := "ASC", ">"
+	if filter.Descending {
+		direction, comparator = "DESC", "<"
+	}
+
+	if cursor := filter.Cursor; cursor != nil {
+		var sortValue any = cursor.CreatedAt
+		if filter.SortBy == orderEntity.OrderSortByTotalPrice {
+			sortValue = cursor.TotalPrice
+		}
+
+		conditions = append(conditions, fmt.Sprintf("(%s, o.id) %s (%s, %s)", sortColumn, comparator, addArg(sortValue), addArg(cursor.Id)))
+	}
+
+	var query strings.Builder
+
+	query.WriteString(QUERY_GET_ORDERS_PAGE)
+	if len(conditions) > 0 {
+		query.WriteString(" WHERE ")
+		query.WriteString(strings.Join(conditions, " AND "))
+	}
+	query.WriteString(fmt.Sprintf(" ORDER BY %s %s, o.id %s LIMIT %s", sortColumn, direction, direction, addArg(filter.Limit)))
+
+	return query.String(), args
+}
+
+// GetOrdersSummarize adds up the daily sales of every user over the UTC days of the period, amounts are in the
+// base currency.
 func (repo *postgresRepo) GetOrdersSummarize(ctx context.Context, startDate, endDate time.Time) (*[]orderEntity.OrdersSummarize, error) {
 	rows, err := repo.db.Query(ctx, QUERY_GET_ORDERS_SUMMARIZE, startDate, endDate)
 	if err != nil {
@@ -212,7 +570,7 @@ func (repo *postgresRepo) GetOrdersSummarize(ctx context.Context, startDate, end
 	datas, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (orderEntity.OrdersSummarize, error) {
 		var data orderEntity.OrdersSummarize
 
-		err := row.Scan(&data.UserId, &data.Username, &data.NumOfOrders, &data.SumOrderPrice, &data.AverageOrderItemQuantity)
+		err := row.Scan(&data.UserId, &data.Username, &data.NumOfOrders, &data.SumOrderPrice, &data.SumTax, &data.AverageOrderItemQuantity)
 		if err != nil {
 			return orderEntity.OrdersSummarize{}, err
 		}
@@ -226,6 +584,31 @@ func (repo *postgresRepo) GetOrdersSummarize(ctx context.Context, startDate, end
 	return &datas, nil
 }
 
+// GetTaxesSummarize adds up, in the base currency, the taxes collected at every rate over the UTC days of the
+// period from the daily tax rollup, which cancelled orders are taken back out of.
+func (repo *postgresRepo) GetTaxesSummarize(ctx context.Context, startDate, endDate time.Time) (*[]orderEntity.OrderTax, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_TAXES_SUMMARIZE, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	taxes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (orderEntity.OrderTax, error) {
+		var tax orderEntity.OrderTax
+
+		err := row.Scan(&tax.Name, &tax.Rate, &tax.Inclusive, &tax.BaseTaxableAmount, &tax.BaseAmount)
+		if err != nil {
+			return orderEntity.OrderTax{}, err
+		}
+
+		return tax, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &taxes, nil
+}
+
 func (repo *postgresRepo) GetOrder(ctx context.Context, userId, orderId int) (*orderEntity.Order, error) {
 	var order orderEntity.Order
 
@@ -237,27 +620,94 @@ func (repo *postgresRepo) GetOrder(ctx context.Context, userId, orderId int) (*o
 
 	for rows.Next() {
 		var orderId, userId, productId, quantity int
-		var productName string
-		var totalPrice, productPrice float32
+		var productName, taxClass, currency, region string
+		var status orderEntity.OrderStatus
+		var totalPrice, baseTotalPrice, productPrice, tax core.Money
+		var exchangeRate, taxRate core.Rate
+		var taxInclusive bool
 		var createdAt time.Time
 		var updatedAt *time.Time
 
-		err := rows.Scan(&orderId, &userId, &productId, &productName, &productPrice, &quantity, &totalPrice, &createdAt, &updatedAt)
+		err := rows.Scan(&orderId, &userId, &productId, &productName, &productPrice, &quantity, &taxClass, &tax, &taxRate, &taxInclusive, &status, &currency, &region, &exchangeRate, &totalPrice, &baseTotalPrice, &createdAt, &updatedAt)
 		if err != nil {
 			return nil, err
 		}
 
 		order.SetId(orderId)
 		order.SetUserId(userId)
+		order.SetStatus(status)
+		order.SetCurrency(currency)
+		order.SetRegion(region)
+		order.SetExchangeRate(exchangeRate)
 		order.SetTotalPrice(totalPrice)
+		order.SetBaseTotalPrice(baseTotalPrice)
 		order.SetCreatedAt(createdAt)
 		order.SetUpdatedAt(updatedAt)
 
 		item := orderEntity.NewOrderItem(orderId, productId, productName, productPrice, quantity)
+		item.SetCurrency(currency)
+		item.SetExchangeRate(exchangeRate)
+		item.SetTaxClass(taxClass)
+		item.SetTax(tax, taxRate, taxInclusive)
 
 		order.AddItem(item)
 	}
 
+	if order.GetIdSafe() == 0 {
+		return nil, core.ErrRecordNotFound
+	}
+
+	rows, err = repo.db.Query(ctx, QUERY_GET_ORDER_DISCOUNTS, order.GetIdSafe())
+	if err != nil {
+		return nil, err
+	}
+
+	discounts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (orderEntity.OrderDiscount, error) {
+		var discount orderEntity.OrderDiscount
+
+		err := row.Scan(&discount.OrderId, &discount.CouponId, &discount.Code, &discount.Description, &discount.Amount, &discount.BaseAmount)
+		if err != nil {
+			return orderEntity.OrderDiscount{}, err
+		}
+
+		return discount, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	order.Discounts = discounts
+
+	rows, err = repo.db.Query(ctx, QUERY_GET_ORDER_TAXES, order.GetIdSafe())
+	if err != nil {
+		return nil, err
+	}
+
+	taxes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (orderEntity.OrderTax, error) {
+		var tax orderEntity.OrderTax
+
+		err := row.Scan(&tax.OrderId, &tax.Name, &tax.Rate, &tax.Inclusive, &tax.TaxableAmount, &tax.Amount, &tax.BaseTaxableAmount, &tax.BaseAmount)
+		if err != nil {
+			return orderEntity.OrderTax{}, err
+		}
+
+		return tax, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	order.Taxes = taxes
+
+	var address orderEntity.OrderAddress
+
+	err = repo.db.QueryRow(ctx, QUERY_GET_SHIPPING_ADDRESS, order.GetIdSafe()).Scan(&address.OrderId, &address.AddressId, &address.Recipient, &address.Phone, &address.Line1, &address.Line2, &address.City, &address.State, &address.PostalCode, &address.Country)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+	// orders placed before addresses existed have none
+	if err == nil {
+		order.SetShippingAddress(&address)
+	}
+
 	return &order, nil
 }
 
@@ -268,38 +718,46 @@ func (repo *postgresRepo) GetTopFiveOrdersByPrice(ctx context.Context) (*[]order
 	}
 	defer rows.Close()
 
-	ordersMap := make(map[int]*orderEntity.Order)
+	// the items of an order come one after another, from the most expensive order in the base currency down
+	var orders []orderEntity.Order
 
 	for rows.Next() {
 		var orderId, userId, productId, quantity int
-		var productName string
-		var productPrice, totalPrice float32
+		var productName, currency string
+		var status orderEntity.OrderStatus
+		var productPrice, totalPrice, baseTotalPrice core.Money
+		var exchangeRate core.Rate
 		var createdAt time.Time
 		var updatedAt *time.Time
 
-		err := rows.Scan(&orderId, &userId, &productId, &productName, &productPrice, &quantity, &totalPrice, &createdAt, &updatedAt)
+		err := rows.Scan(&orderId, &userId, &productId, &productName, &productPrice, &quantity, &status, &currency, &exchangeRate, &totalPrice, &baseTotalPrice, &createdAt, &updatedAt)
 		if err != nil {
 			return nil, err
 		}
 
 		item := orderEntity.NewOrderItem(orderId, productId, productName, productPrice, quantity)
+		item.SetCurrency(currency)
+		item.SetExchangeRate(exchangeRate)
 
-		if _, exists := ordersMap[orderId]; !exists {
-			ordersMap[orderId] = &orderEntity.Order{
-				Id:         orderId,
-				UserId:     userId,
-				TotalPrice: totalPrice,
-				Items:      []orderEntity.OrderItem{item},
-			}
+		if len(orders) == 0 || orders[len(orders)-1].Id != orderId {
+			orders = append(orders, orderEntity.Order{
+				Id:             orderId,
+				UserId:         userId,
+				Status:         status,
+				Currency:       currency,
+				ExchangeRate:   exchangeRate,
+				TotalPrice:     totalPrice,
+				BaseTotalPrice: baseTotalPrice,
+				CreatedAt:      createdAt,
+				UpdatedAt:      updatedAt,
+				Items:          []orderEntity.OrderItem{item},
+			})
 		} else {
-			ordersMap[orderId].Items = append(ordersMap[orderId].Items, item)
+			orders[len(orders)-1].Items = append(orders[len(orders)-1].Items, item)
 		}
 	}
-
-	var orders []orderEntity.Order
-
-	for _, order := range ordersMap {
-		orders = append(orders, *order)
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return &orders, nil
@@ -327,3 +785,245 @@ func (repo *postgresRepo) GetNumOfOrdersPerMonth(ctx context.Context, userId int
 
 	return &orders, nil
 }
+
+func (repo *postgresRepo) UpdateOrderStatus(ctx context.Context, orderId, actorId int, status orderEntity.OrderStatus, callbackFn func(order *orderEntity.Order, status orderEntity.OrderStatus) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		var order orderEntity.Order
+
+		err := tx.QueryRow(ctx, QUERY_GET_ORDER_LOCK, orderId).Scan(&order.Id, &order.UserId, &order.Status, &order.Currency, &order.ExchangeRate, &order.TotalPrice, &order.BaseTotalPrice, &order.CreatedAt, &order.UpdatedAt)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return core.ErrRecordNotFound
+			}
+			return err
+		}
+
+		// run business logic
+		err = callbackFn(&order, status)
+		if err != nil {
+			return err
+		}
+
+		fromStatus := order.GetStatusSafe()
+		now := time.Now()
+
+		// paying for the order turns its reservation into a real deduction
+		if status == orderEntity.OrderStatusPaid {
+			err = confirmReservations(ctx, tx, order.GetIdSafe(), now)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(ctx, QUERY_UPDATE_ORDER_STATUS, order.GetIdSafe(), status, now)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_CREATE_ORDER_STATUS_HISTORY, order.GetIdSafe(), fromStatus, status, actorId, now)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (repo *postgresRepo) GetOrderStatusHistories(ctx context.Context, orderId int) (*[]orderEntity.OrderStatusHistory, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_ORDER_STATUS_HISTORIES, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (orderEntity.OrderStatusHistory, error) {
+		var history orderEntity.OrderStatusHistory
+
+		err := row.Scan(&history.Id, &history.OrderId, &history.FromStatus, &history.ToStatus, &history.ActorId, &history.CreatedAt)
+		if err != nil {
+			return orderEntity.OrderStatusHistory{}, err
+		}
+
+		return history, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &histories, nil
+}
+
+func (repo *postgresRepo) CancelOrder(ctx context.Context, orderId, actorId int, callbackFn func(order *orderEntity.Order) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		var order orderEntity.Order
+
+		err := tx.QueryRow(ctx, QUERY_GET_ORDER_LOCK, orderId).Scan(&order.Id, &order.UserId, &order.Status, &order.Currency, &order.ExchangeRate, &order.TotalPrice, &order.BaseTotalPrice, &order.CreatedAt, &order.UpdatedAt)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return core.ErrRecordNotFound
+			}
+			return err
+		}
+
+		// run business logic
+		err = callbackFn(&order)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, QUERY_GET_ORDER_ITEMS_BY_ORDER_ID, order.GetIdSafe())
+		if err != nil {
+			return err
+		}
+
+		items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (orderEntity.OrderItem, error) {
+			var item orderEntity.OrderItem
+
+			err := row.Scan(&item.OrderId, &item.ProductId, &item.ProductName, &item.ProductPrice, &item.Quantity, &item.Currency, &item.ExchangeRate)
+			if err != nil {
+				return orderEntity.OrderItem{}, err
+			}
+
+			return item, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		reservations, err := lockReservations(ctx, tx, order.GetIdSafe())
+		if err != nil {
+			return err
+		}
+
+		reservationsMap := make(map[int]orderEntity.Reservation, len(reservations))
+		for _, reservation := range reservations {
+			reservationsMap[reservation.GetProductId()] = reservation
+		}
+
+		// units still held go back to the available stock, units already taken out are restocked and
+		// units of a lapsed reservation are back already. Orders placed before reservations have none.
+		productIds := make([]int, 0, len(items))
+		restockIds, restockQuantities := make([]int, 0, len(items)), make([]int, 0, len(items))
+		releaseIds, releaseQuantities := make([]int, 0, len(items)), make([]int, 0, len(items))
+		releasedReservationIds := make([]int, 0, len(items))
+
+		for _, item := range items {
+			productIds = append(productIds, item.GetProductId())
+
+			reservation, ok := reservationsMap[item.GetProductId()]
+			switch {
+			case !ok || reservation.GetStatus() == orderEntity.ReservationConfirmed:
+				restockIds = append(restockIds, item.GetProductId())
+				restockQuantities = append(restockQuantities, item.GetQuantity())
+			case reservation.GetStatus() == orderEntity.ReservationActive:
+				releaseIds = append(releaseIds, item.GetProductId())
+				releaseQuantities = append(releaseQuantities, reservation.GetQuantity())
+				releasedReservationIds = append(releasedReservationIds, reservation.GetId())
+			}
+		}
+
+		_, err = lockProducts(ctx, tx, productIds)
+		if err != nil {
+			return err
+		}
+
+		if len(restockIds) > 0 {
+			_, err = tx.Exec(ctx, QUERY_RESTOCK_PRODUCTS_QUANTITY, restockIds, restockQuantities, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(releaseIds) > 0 {
+			_, err = tx.Exec(ctx, QUERY_RELEASE_PRODUCTS_QUANTITY, releaseIds, releaseQuantities, now)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(ctx, QUERY_UPDATE_RESERVATIONS_STATUS, releasedReservationIds, orderEntity.ReservationReleased, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		// the status guards against cancelling the same order twice, its payments are given back by the caller
+		// and refunded_at is only set once they are
+		tag, err := tx.Exec(ctx, QUERY_CANCEL_ORDER, order.GetIdSafe(), orderEntity.OrderStatusCancelled, now)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return orderEntity.ErrOrderAlreadyCancelled
+		}
+
+		// a cancelled order no longer counts as sold
+		err = addDailySales(ctx, tx, order.GetIdSafe(), -1)
+		if err != nil {
+			return err
+		}
+
+		// coupons used by the order can be redeemed again, the discount lines stay as a record
+		_, err = tx.Exec(ctx, QUERY_RELEASE_COUPONS, order.GetIdSafe(), now)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_CREATE_ORDER_STATUS_HISTORY, order.GetIdSafe(), order.GetStatusSafe(), orderEntity.OrderStatusCancelled, actorId, now)
+		if err != nil {
+			return err
+		}
+
+		order.SetStatus(orderEntity.OrderStatusCancelled)
+		order.SetItems(items)
+
+		return createOrderEvent(ctx, tx, orderEntity.EventOrderCancelled, &order)
+	})
+}
+
+// GetUnrefundedOrderIds returns at most limit orders cancelled before cancelledBefore whose payments have not
+// been given back yet.
+func (repo *postgresRepo) GetUnrefundedOrderIds(ctx context.Context, cancelledBefore time.Time, limit int) ([]int, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_UNREFUNDED_ORDER_IDS, cancelledBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+func (repo *postgresRepo) MarkOrderRefunded(ctx context.Context, orderId int, now time.Time) error {
+	_, err := repo.db.Exec(ctx, QUERY_MARK_ORDER_REFUNDED, orderId, now)
+
+	return err
+}
+
+// addDailySales adds the order to the daily sales rollups within tx, or takes it back out with a sign of -1.
+// It has to run once the items and taxes of the order are written.
+func addDailySales(ctx context.Context, tx pgx.Tx, orderId, sign int) error {
+	_, err := tx.Exec(ctx, QUERY_ADD_DAILY_USER_SALES, orderId, sign)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, QUERY_ADD_DAILY_PRODUCT_SALES, orderId, sign)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, QUERY_ADD_DAILY_TAX_SALES, orderId, sign)
+
+	return err
+}
+
+// createOrderEvent writes the event to the outbox within tx, it is only relayed if the change commits.
+func createOrderEvent(ctx context.Context, tx pgx.Tx, eventType string, order *orderEntity.Order) error {
+	event, err := outboxEntity.NewEvent(orderEntity.EventAggregateOrder, order.GetIdSafe(), eventType, order)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, QUERY_CREATE_OUTBOX_EVENT, event.GetAggregateType(), event.GetAggregateId(), event.GetType(), event.GetPayload(), event.GetCreatedAt())
+
+	return err
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/address.go
+//
+// Generated by this command:
+//
+//	mockgen -source usecase/address.go -destination test/mock/address.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/address/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAddressResolver is a mock of AddressResolver interface.
+type MockAddressResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressResolverMockRecorder
+}
+
+// MockAddressResolverMockRecorder is the mock recorder for MockAddressResolver.
+type MockAddressResolverMockRecorder struct {
+	mock *MockAddressResolver
+}
+
+// NewMockAddressResolver creates a new mock instance.
+func NewMockAddressResolver(ctrl *gomock.Controller) *MockAddressResolver {
+	mock := &MockAddressResolver{ctrl: ctrl}
+	mock.recorder = &MockAddressResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddressResolver) EXPECT() *MockAddressResolverMockRecorder {
+	return m.recorder
+}
+
+// ResolveAddress mocks base method.
+func (m *MockAddressResolver) ResolveAddress(ctx context.Context, userId, addressId int) (*entity.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAddress", ctx, userId, addressId)
+	ret0, _ := ret[0].(*entity.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveAddress indicates an expected call of ResolveAddress.
+func (mr *MockAddressResolverMockRecorder) ResolveAddress(ctx, userId, addressId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAddress", reflect.TypeOf((*MockAddressResolver)(nil).ResolveAddress), ctx, userId, addressId)
+}
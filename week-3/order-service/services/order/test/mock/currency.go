@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/currency.go
+//
+// Generated by this command:
+//
+//	mockgen -source usecase/currency.go -destination test/mock/currency.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	core "order_service/internal/core"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCurrencyResolver is a mock of CurrencyResolver interface.
+type MockCurrencyResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockCurrencyResolverMockRecorder
+}
+
+// MockCurrencyResolverMockRecorder is the mock recorder for MockCurrencyResolver.
+type MockCurrencyResolverMockRecorder struct {
+	mock *MockCurrencyResolver
+}
+
+// NewMockCurrencyResolver creates a new mock instance.
+func NewMockCurrencyResolver(ctrl *gomock.Controller) *MockCurrencyResolver {
+	mock := &MockCurrencyResolver{ctrl: ctrl}
+	mock.recorder = &MockCurrencyResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCurrencyResolver) EXPECT() *MockCurrencyResolverMockRecorder {
+	return m.recorder
+}
+
+// ResolveCurrency mocks base method.
+func (m *MockCurrencyResolver) ResolveCurrency(ctx context.Context, userId int, currency string) (string, core.Rate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveCurrency", ctx, userId, currency)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(core.Rate)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ResolveCurrency indicates an expected call of ResolveCurrency.
+func (mr *MockCurrencyResolverMockRecorder) ResolveCurrency(ctx, userId, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveCurrency", reflect.TypeOf((*MockCurrencyResolver)(nil).ResolveCurrency), ctx, userId, currency)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/payment.go
+//
+// Generated by this command:
+//
+//	mockgen -source usecase/payment.go -destination test/mock/payment.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/payment/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPaymentProcessor is a mock of PaymentProcessor interface.
+type MockPaymentProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentProcessorMockRecorder
+}
+
+// MockPaymentProcessorMockRecorder is the mock recorder for MockPaymentProcessor.
+type MockPaymentProcessorMockRecorder struct {
+	mock *MockPaymentProcessor
+}
+
+// NewMockPaymentProcessor creates a new mock instance.
+func NewMockPaymentProcessor(ctrl *gomock.Controller) *MockPaymentProcessor {
+	mock := &MockPaymentProcessor{ctrl: ctrl}
+	mock.recorder = &MockPaymentProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentProcessor) EXPECT() *MockPaymentProcessorMockRecorder {
+	return m.recorder
+}
+
+// GetOrderPayments mocks base method.
+func (m *MockPaymentProcessor) GetOrderPayments(ctx context.Context, orderId int) (*[]entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderPayments", ctx, orderId)
+	ret0, _ := ret[0].(*[]entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderPayments indicates an expected call of GetOrderPayments.
+func (mr *MockPaymentProcessorMockRecorder) GetOrderPayments(ctx, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderPayments", reflect.TypeOf((*MockPaymentProcessor)(nil).GetOrderPayments), ctx, orderId)
+}
+
+// HandleGatewayEvent mocks base method.
+func (m *MockPaymentProcessor) HandleGatewayEvent(ctx context.Context, body []byte, signature string) (*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleGatewayEvent", ctx, body, signature)
+	ret0, _ := ret[0].(*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandleGatewayEvent indicates an expected call of HandleGatewayEvent.
+func (mr *MockPaymentProcessorMockRecorder) HandleGatewayEvent(ctx, body, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleGatewayEvent", reflect.TypeOf((*MockPaymentProcessor)(nil).HandleGatewayEvent), ctx, body, signature)
+}
+
+// Pay mocks base method.
+func (m *MockPaymentProcessor) Pay(ctx context.Context, orderId, userId int, data *entity.PaymentRequest) (*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pay", ctx, orderId, userId, data)
+	ret0, _ := ret[0].(*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pay indicates an expected call of Pay.
+func (mr *MockPaymentProcessorMockRecorder) Pay(ctx, orderId, userId, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pay", reflect.TypeOf((*MockPaymentProcessor)(nil).Pay), ctx, orderId, userId, data)
+}
+
+// ReleaseOrderPayments mocks base method.
+func (m *MockPaymentProcessor) ReleaseOrderPayments(ctx context.Context, orderId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseOrderPayments", ctx, orderId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseOrderPayments indicates an expected call of ReleaseOrderPayments.
+func (mr *MockPaymentProcessorMockRecorder) ReleaseOrderPayments(ctx, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseOrderPayments", reflect.TypeOf((*MockPaymentProcessor)(nil).ReleaseOrderPayments), ctx, orderId)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/shipment.go
+//
+// Generated by this command:
+//
+//	mockgen -source usecase/shipment.go -destination test/mock/shipment.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/shipment/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockShipmentResolver is a mock of ShipmentResolver interface.
+type MockShipmentResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockShipmentResolverMockRecorder
+}
+
+// MockShipmentResolverMockRecorder is the mock recorder for MockShipmentResolver.
+type MockShipmentResolverMockRecorder struct {
+	mock *MockShipmentResolver
+}
+
+// NewMockShipmentResolver creates a new mock instance.
+func NewMockShipmentResolver(ctrl *gomock.Controller) *MockShipmentResolver {
+	mock := &MockShipmentResolver{ctrl: ctrl}
+	mock.recorder = &MockShipmentResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShipmentResolver) EXPECT() *MockShipmentResolverMockRecorder {
+	return m.recorder
+}
+
+// GetOrderShipments mocks base method.
+func (m *MockShipmentResolver) GetOrderShipments(ctx context.Context, orderId int) ([]entity.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderShipments", ctx, orderId)
+	ret0, _ := ret[0].([]entity.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderShipments indicates an expected call of GetOrderShipments.
+func (mr *MockShipmentResolverMockRecorder) GetOrderShipments(ctx, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderShipments", reflect.TypeOf((*MockShipmentResolver)(nil).GetOrderShipments), ctx, orderId)
+}
@@ -11,9 +11,10 @@ package mock
 
 import (
 	context "context"
-	entity "order_service/services/order/entity"
-	entity0 "order_service/services/product/entity"
-	entity1 "order_service/services/user/entity"
+	entity "order_service/services/coupon/entity"
+	entity0 "order_service/services/order/entity"
+	entity1 "order_service/services/product/entity"
+	entity2 "order_service/services/user/entity"
 	reflect "reflect"
 	time "time"
 
@@ -43,8 +44,22 @@ func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CancelOrder mocks base method.
+func (m *MockOrderRepository) CancelOrder(ctx context.Context, orderId, actorId int, callbackFn func(*entity0.Order) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, orderId, actorId, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrderRepositoryMockRecorder) CancelOrder(ctx, orderId, actorId, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrderRepository)(nil).CancelOrder), ctx, orderId, actorId, callbackFn)
+}
+
 // CreateOrder mocks base method.
-func (m *MockOrderRepository) CreateOrder(ctx context.Context, order *entity.Order, callbackFn func(*entity.Order, *entity1.User, *[]entity0.Product) (bool, error)) error {
+func (m *MockOrderRepository) CreateOrder(ctx context.Context, order *entity0.Order, callbackFn func(*entity0.Order, *entity2.User, *[]entity1.Product, *[]entity.Coupon) (bool, error)) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateOrder", ctx, order, callbackFn)
 	ret0, _ := ret[0].(error)
@@ -57,11 +72,26 @@ func (mr *MockOrderRepositoryMockRecorder) CreateOrder(ctx, order, callbackFn an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderRepository)(nil).CreateOrder), ctx, order, callbackFn)
 }
 
+// GetExpiredOrderIds mocks base method.
+func (m *MockOrderRepository) GetExpiredOrderIds(ctx context.Context, limit int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredOrderIds", ctx, limit)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredOrderIds indicates an expected call of GetExpiredOrderIds.
+func (mr *MockOrderRepositoryMockRecorder) GetExpiredOrderIds(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredOrderIds", reflect.TypeOf((*MockOrderRepository)(nil).GetExpiredOrderIds), ctx, limit)
+}
+
 // GetNumOfOrdersPerMonth mocks base method.
-func (m *MockOrderRepository) GetNumOfOrdersPerMonth(ctx context.Context, userId int) (*[]entity.AggregatedOrdersByMonth, error) {
+func (m *MockOrderRepository) GetNumOfOrdersPerMonth(ctx context.Context, userId int) (*[]entity0.AggregatedOrdersByMonth, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetNumOfOrdersPerMonth", ctx, userId)
-	ret0, _ := ret[0].(*[]entity.AggregatedOrdersByMonth)
+	ret0, _ := ret[0].(*[]entity0.AggregatedOrdersByMonth)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -73,10 +103,10 @@ func (mr *MockOrderRepositoryMockRecorder) GetNumOfOrdersPerMonth(ctx, userId an
 }
 
 // GetOrder mocks base method.
-func (m *MockOrderRepository) GetOrder(ctx context.Context, userId, orderId int) (*entity.Order, error) {
+func (m *MockOrderRepository) GetOrder(ctx context.Context, userId, orderId int) (*entity0.Order, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetOrder", ctx, userId, orderId)
-	ret0, _ := ret[0].(*entity.Order)
+	ret0, _ := ret[0].(*entity0.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -87,41 +117,41 @@ func (mr *MockOrderRepositoryMockRecorder) GetOrder(ctx, userId, orderId any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderRepository)(nil).GetOrder), ctx, userId, orderId)
 }
 
-// GetOrders mocks base method.
-func (m *MockOrderRepository) GetOrders(ctx context.Context) (*[]entity.Order, error) {
+// GetOrderStatusHistories mocks base method.
+func (m *MockOrderRepository) GetOrderStatusHistories(ctx context.Context, orderId int) (*[]entity0.OrderStatusHistory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrders", ctx)
-	ret0, _ := ret[0].(*[]entity.Order)
+	ret := m.ctrl.Call(m, "GetOrderStatusHistories", ctx, orderId)
+	ret0, _ := ret[0].(*[]entity0.OrderStatusHistory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrders indicates an expected call of GetOrders.
-func (mr *MockOrderRepositoryMockRecorder) GetOrders(ctx any) *gomock.Call {
+// GetOrderStatusHistories indicates an expected call of GetOrderStatusHistories.
+func (mr *MockOrderRepositoryMockRecorder) GetOrderStatusHistories(ctx, orderId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrderRepository)(nil).GetOrders), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderStatusHistories", reflect.TypeOf((*MockOrderRepository)(nil).GetOrderStatusHistories), ctx, orderId)
 }
 
-// GetOrdersByUserId mocks base method.
-func (m *MockOrderRepository) GetOrdersByUserId(ctx context.Context, userId int) (*[]entity.Order, error) {
+// GetOrders mocks base method.
+func (m *MockOrderRepository) GetOrders(ctx context.Context, filter *entity0.OrderFilter) (*[]entity0.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrdersByUserId", ctx, userId)
-	ret0, _ := ret[0].(*[]entity.Order)
+	ret := m.ctrl.Call(m, "GetOrders", ctx, filter)
+	ret0, _ := ret[0].(*[]entity0.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrdersByUserId indicates an expected call of GetOrdersByUserId.
-func (mr *MockOrderRepositoryMockRecorder) GetOrdersByUserId(ctx, userId any) *gomock.Call {
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockOrderRepositoryMockRecorder) GetOrders(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersByUserId", reflect.TypeOf((*MockOrderRepository)(nil).GetOrdersByUserId), ctx, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrderRepository)(nil).GetOrders), ctx, filter)
 }
 
 // GetOrdersSummarize mocks base method.
-func (m *MockOrderRepository) GetOrdersSummarize(ctx context.Context, startDate, endDate time.Time) (*[]entity.OrdersSummarize, error) {
+func (m *MockOrderRepository) GetOrdersSummarize(ctx context.Context, startDate, endDate time.Time) (*[]entity0.OrdersSummarize, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetOrdersSummarize", ctx, startDate, endDate)
-	ret0, _ := ret[0].(*[]entity.OrdersSummarize)
+	ret0, _ := ret[0].(*[]entity0.OrdersSummarize)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -132,11 +162,26 @@ func (mr *MockOrderRepositoryMockRecorder) GetOrdersSummarize(ctx, startDate, en
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersSummarize", reflect.TypeOf((*MockOrderRepository)(nil).GetOrdersSummarize), ctx, startDate, endDate)
 }
 
+// GetTaxesSummarize mocks base method.
+func (m *MockOrderRepository) GetTaxesSummarize(ctx context.Context, startDate, endDate time.Time) (*[]entity0.OrderTax, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxesSummarize", ctx, startDate, endDate)
+	ret0, _ := ret[0].(*[]entity0.OrderTax)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxesSummarize indicates an expected call of GetTaxesSummarize.
+func (mr *MockOrderRepositoryMockRecorder) GetTaxesSummarize(ctx, startDate, endDate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxesSummarize", reflect.TypeOf((*MockOrderRepository)(nil).GetTaxesSummarize), ctx, startDate, endDate)
+}
+
 // GetTopFiveOrdersByPrice mocks base method.
-func (m *MockOrderRepository) GetTopFiveOrdersByPrice(ctx context.Context) (*[]entity.Order, error) {
+func (m *MockOrderRepository) GetTopFiveOrdersByPrice(ctx context.Context) (*[]entity0.Order, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTopFiveOrdersByPrice", ctx)
-	ret0, _ := ret[0].(*[]entity.Order)
+	ret0, _ := ret[0].(*[]entity0.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -146,3 +191,61 @@ func (mr *MockOrderRepositoryMockRecorder) GetTopFiveOrdersByPrice(ctx any) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopFiveOrdersByPrice", reflect.TypeOf((*MockOrderRepository)(nil).GetTopFiveOrdersByPrice), ctx)
 }
+
+// GetUnrefundedOrderIds mocks base method.
+func (m *MockOrderRepository) GetUnrefundedOrderIds(ctx context.Context, cancelledBefore time.Time, limit int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnrefundedOrderIds", ctx, cancelledBefore, limit)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnrefundedOrderIds indicates an expected call of GetUnrefundedOrderIds.
+func (mr *MockOrderRepositoryMockRecorder) GetUnrefundedOrderIds(ctx, cancelledBefore, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnrefundedOrderIds", reflect.TypeOf((*MockOrderRepository)(nil).GetUnrefundedOrderIds), ctx, cancelledBefore, limit)
+}
+
+// MarkOrderRefunded mocks base method.
+func (m *MockOrderRepository) MarkOrderRefunded(ctx context.Context, orderId int, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOrderRefunded", ctx, orderId, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOrderRefunded indicates an expected call of MarkOrderRefunded.
+func (mr *MockOrderRepositoryMockRecorder) MarkOrderRefunded(ctx, orderId, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOrderRefunded", reflect.TypeOf((*MockOrderRepository)(nil).MarkOrderRefunded), ctx, orderId, now)
+}
+
+// ReleaseExpiredReservations mocks base method.
+func (m *MockOrderRepository) ReleaseExpiredReservations(ctx context.Context, now time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredReservations", ctx, now, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredReservations indicates an expected call of ReleaseExpiredReservations.
+func (mr *MockOrderRepositoryMockRecorder) ReleaseExpiredReservations(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredReservations", reflect.TypeOf((*MockOrderRepository)(nil).ReleaseExpiredReservations), ctx, now, limit)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderRepository) UpdateOrderStatus(ctx context.Context, orderId, actorId int, status entity0.OrderStatus, callbackFn func(*entity0.Order, entity0.OrderStatus) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, orderId, actorId, status, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderRepositoryMockRecorder) UpdateOrderStatus(ctx, orderId, actorId, status, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdateOrderStatus), ctx, orderId, actorId, status, callbackFn)
+}
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/tax.go
+//
+// Generated by this command:
+//
+//	mockgen -source usecase/tax.go -destination test/mock/tax.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/tax/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTaxResolver is a mock of TaxResolver interface.
+type MockTaxResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxResolverMockRecorder
+}
+
+// MockTaxResolverMockRecorder is the mock recorder for MockTaxResolver.
+type MockTaxResolverMockRecorder struct {
+	mock *MockTaxResolver
+}
+
+// NewMockTaxResolver creates a new mock instance.
+func NewMockTaxResolver(ctrl *gomock.Controller) *MockTaxResolver {
+	mock := &MockTaxResolver{ctrl: ctrl}
+	mock.recorder = &MockTaxResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaxResolver) EXPECT() *MockTaxResolverMockRecorder {
+	return m.recorder
+}
+
+// ResolveRules mocks base method.
+func (m *MockTaxResolver) ResolveRules(ctx context.Context, region string) (string, entity.TaxRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveRules", ctx, region)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(entity.TaxRules)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ResolveRules indicates an expected call of ResolveRules.
+func (mr *MockTaxResolverMockRecorder) ResolveRules(ctx, region any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRules", reflect.TypeOf((*MockTaxResolver)(nil).ResolveRules), ctx, region)
+}
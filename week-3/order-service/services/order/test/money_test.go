@@ -0,0 +1,142 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"order_service/internal/core"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type MoneyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *MoneyTestSuite) TestParseMoney() {
+	tests := []struct {
+		name      string
+		input     string
+		want      core.Money
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "Whole amount", input: "12", want: core.NewMoney(1200), assertion: assert.NoError},
+		{name: "Fractional amount", input: "0.1", want: core.NewMoney(10), assertion: assert.NoError},
+		{name: "Negative amount", input: "-3.05", want: core.NewMoney(-305), assertion: assert.NoError},
+		{name: "Trailing zeros", input: "1.2500", want: core.NewMoney(125), assertion: assert.NoError},
+		{name: "Too many decimals", input: "1.005", wantErr: core.ErrInvalidMoney, assertion: assert.Error},
+		{name: "Not a number", input: "1e5", wantErr: core.ErrInvalidMoney, assertion: assert.Error},
+		{name: "Empty", input: "", wantErr: core.ErrInvalidMoney, assertion: assert.Error},
+		{name: "Overflow", input: "999999999999999999999", wantErr: core.ErrMoneyOverflow, assertion: assert.Error},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			got, err := core.ParseMoney(tt.input)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			suite.Equal(tt.want, got, "money should be parsed exactly")
+		})
+	}
+}
+
+func (suite *MoneyTestSuite) TestString() {
+	suite.Equal("0.00", core.NewMoney(0).String())
+	suite.Equal("12.05", core.NewMoney(1205).String())
+	suite.Equal("-0.50", core.NewMoney(-50).String())
+}
+
+func (suite *MoneyTestSuite) TestJSON() {
+	var data struct {
+		Price core.Money `json:"price"`
+		Total core.Money `json:"total"`
+	}
+
+	err := json.Unmarshal([]byte(`{"price": 19.99, "total": "0.30"}`), &data)
+
+	suite.NoError(err)
+	suite.Equal(core.NewMoney(1999), data.Price, "number should be decoded exactly")
+	suite.Equal(core.NewMoney(30), data.Total, "string should be decoded exactly")
+
+	encoded, err := json.Marshal(data)
+
+	suite.NoError(err)
+	suite.JSONEq(`{"price": 19.99, "total": 0.30}`, string(encoded), "money should be encoded as a number")
+}
+
+func (suite *MoneyTestSuite) TestScanNumeric() {
+	tests := []struct {
+		name      string
+		numeric   pgtype.Numeric
+		want      core.Money
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "Same scale", numeric: pgtype.Numeric{Int: big.NewInt(1234), Exp: -2, Valid: true}, want: core.NewMoney(1234), assertion: assert.NoError},
+		{name: "Whole number", numeric: pgtype.Numeric{Int: big.NewInt(5), Exp: 0, Valid: true}, want: core.NewMoney(500), assertion: assert.NoError},
+		{name: "Extra decimals are rounded", numeric: pgtype.Numeric{Int: big.NewInt(-12345), Exp: -3, Valid: true}, want: core.NewMoney(-1235), assertion: assert.NoError},
+		{name: "Null", numeric: pgtype.Numeric{}, want: core.NewMoney(0), assertion: assert.NoError},
+		{name: "NaN", numeric: pgtype.Numeric{NaN: true, Valid: true}, assertion: assert.Error},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			var got core.Money
+
+			err := got.ScanNumeric(tt.numeric)
+
+			tt.assertion(suite.T(), err)
+			if err != nil {
+				return
+			}
+			suite.Equal(tt.want, got, "numeric should be scanned exactly")
+
+			value, err := got.NumericValue()
+			suite.NoError(err)
+			suite.Equal(int64(-2), int64(value.Exp), "numeric should be written with two decimals")
+		})
+	}
+}
+
+func (suite *MoneyTestSuite) TestTax() {
+	rate, _ := core.ParseRate("0.125")
+
+	tests := []struct {
+		name      string
+		amount    core.Money
+		inclusive bool
+		mode      core.RoundingMode
+		want      core.Money
+	}{
+		{name: "Exclusive half up", amount: core.NewMoney(100), mode: core.RoundHalfUp, want: core.NewMoney(13)},
+		{name: "Exclusive half even", amount: core.NewMoney(100), mode: core.RoundHalfEven, want: core.NewMoney(12)},
+		{name: "Exclusive up", amount: core.NewMoney(101), mode: core.RoundUp, want: core.NewMoney(13)},
+		{name: "Exclusive down", amount: core.NewMoney(107), mode: core.RoundDown, want: core.NewMoney(13)},
+		{name: "Inclusive", amount: core.NewMoney(1125), inclusive: true, mode: core.RoundHalfUp, want: core.NewMoney(125)},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.Equal(tt.want, tt.amount.Tax(rate, tt.inclusive, tt.mode), "tax should be rounded with the mode")
+		})
+	}
+}
+
+func (suite *MoneyTestSuite) TestAllocate() {
+	shares := core.NewMoney(100).Allocate([]core.Money{core.NewMoney(100), core.NewMoney(100), core.NewMoney(100)})
+	suite.Equal([]core.Money{core.NewMoney(34), core.NewMoney(33), core.NewMoney(33)}, shares, "left over cents should go to the first shares")
+
+	shares = core.NewMoney(400).Allocate([]core.Money{core.NewMoney(1000), core.NewMoney(3000)})
+	suite.Equal([]core.Money{core.NewMoney(100), core.NewMoney(300)}, shares, "shares should follow the weights")
+
+	shares = core.NewMoney(100).Allocate([]core.Money{core.NewMoney(0), core.NewMoney(0)})
+	suite.Equal([]core.Money{core.NewMoney(0), core.NewMoney(0)}, shares, "nothing is allocated without weights")
+}
+
+func TestMoneyTestSuite(t *testing.T) {
+	suite.Run(t, new(MoneyTestSuite))
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"order_service/internal/core"
+	"order_service/services/order/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type OrderFilterTestSuite struct {
+	suite.Suite
+}
+
+func (suite *OrderFilterTestSuite) TestToFilter() {
+	minPrice, maxPrice := core.NewMoney(5000), core.NewMoney(1000)
+	startDate := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 10, 2, 8, 30, 0, 0, time.UTC)
+
+	descCursor := entity.NewOrderCursor(&entity.OrderFilter{SortBy: entity.OrderSortByCreatedAt, Descending: true}, entity.Order{Id: 7, CreatedAt: createdAt})
+
+	tests := []struct {
+		name      string
+		request   entity.OrderListRequest
+		want      *entity.OrderFilter
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "Defaults",
+			request: entity.OrderListRequest{},
+			want: &entity.OrderFilter{
+				SortBy:     entity.OrderSortByCreatedAt,
+				Descending: true,
+				Limit:      entity.DefaultOrderPageSize,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:    "Limit is capped",
+			request: entity.OrderListRequest{Limit: 1000, Sort: "total_price", Order: "asc"},
+			want: &entity.OrderFilter{
+				SortBy:     entity.OrderSortByTotalPrice,
+				Descending: false,
+				Limit:      entity.MaxOrderPageSize,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:    "Date range",
+			request: entity.OrderListRequest{StartDate: "2024-10-01"},
+			want: &entity.OrderFilter{
+				SortBy:     entity.OrderSortByCreatedAt,
+				Descending: true,
+				Limit:      entity.DefaultOrderPageSize,
+				StartDate:  &startDate,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:    "Cursor of the same ordering",
+			request: entity.OrderListRequest{Cursor: descCursor.Encode()},
+			want: &entity.OrderFilter{
+				SortBy:     entity.OrderSortByCreatedAt,
+				Descending: true,
+				Limit:      entity.DefaultOrderPageSize,
+				Cursor:     &descCursor,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Cursor of another ordering",
+			request:   entity.OrderListRequest{Cursor: descCursor.Encode(), Order: "asc"},
+			wantErr:   entity.ErrInvalidCursor,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Malformed cursor",
+			request:   entity.OrderListRequest{Cursor: "not-a-cursor"},
+			wantErr:   entity.ErrInvalidCursor,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown sort field",
+			request:   entity.OrderListRequest{Sort: "user_id"},
+			wantErr:   entity.ErrInvalidFilter,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Malformed date",
+			request:   entity.OrderListRequest{EndDate: "yesterday"},
+			wantErr:   entity.ErrInvalidFilter,
+			assertion: assert.Error,
+		},
+		{
+			name:      "End date before start date",
+			request:   entity.OrderListRequest{StartDate: "2024-10-02", EndDate: "2024-10-01"},
+			wantErr:   entity.ErrInvalidFilter,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Inverted price range",
+			request:   entity.OrderListRequest{MinTotalPrice: &minPrice, MaxTotalPrice: &maxPrice},
+			wantErr:   entity.ErrInvalidFilter,
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			filter, err := tt.request.ToFilter()
+
+			suite.Equal(tt.want, filter, "filter should be built correctly")
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+		})
+	}
+}
+
+func (suite *OrderFilterTestSuite) TestCursorRoundTrip() {
+	order := entity.Order{Id: 42, TotalPrice: core.NewMoney(1250), BaseTotalPrice: core.NewMoney(1000), CreatedAt: time.Now()}
+	filter := &entity.OrderFilter{SortBy: entity.OrderSortByTotalPrice, Descending: true}
+
+	cursor := entity.NewOrderCursor(filter, order)
+
+	decoded, err := entity.DecodeOrderCursor(cursor.Encode())
+
+	suite.NoError(err, "encoded cursor should be decoded")
+	suite.Equal(&cursor, decoded, "cursor should survive the round trip")
+	suite.Nil(decoded.CreatedAt, "only the sort key should be carried")
+	suite.Equal(core.NewMoney(1000), *decoded.TotalPrice, "price cursor should be in the base currency")
+}
+
+func TestOrderFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(OrderFilterTestSuite))
+}
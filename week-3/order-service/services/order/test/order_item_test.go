@@ -1,6 +1,7 @@
 package test
 
 import (
+	"order_service/internal/core"
 	"order_service/services/order/entity"
 	"testing"
 
@@ -28,7 +29,7 @@ func (suite *OrderItemTestSuite) TestNewOrderItem() {
 	suite.Equal(1, item.OrderId, "OrderId should be set correctly")
 	suite.Equal(1, item.ProductId, "ProductId should be set correctly")
 	suite.Equal("orange", item.ProductName, "ProductName should be set correctly")
-	suite.Equal(float32(50), item.ProductPrice, "ProductPrice should be set correctly")
+	suite.Equal(core.NewMoney(50), item.ProductPrice, "ProductPrice should be set correctly")
 	suite.Equal(1, item.Quantity, "Quantity should be set correctly")
 }
 
@@ -147,8 +148,8 @@ func (suite *OrderItemTestSuite) TestSetProductPrice() {
 	tests := []struct {
 		name         string
 		orderItem    *entity.OrderItem
-		productPrice float32
-		want         float32
+		productPrice core.Money
+		want         core.Money
 		panic        bool
 	}{
 		{
@@ -0,0 +1,178 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"order_service/pkg"
+	couponEntity "order_service/services/coupon/entity"
+	"order_service/services/order/entity"
+	orderRepo "order_service/services/order/repository/postgres"
+	"order_service/services/order/usecase"
+	productEntity "order_service/services/product/entity"
+	userEntity "order_service/services/user/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The benchmark mirrors week-4/load-test: 200 users ordering concurrently against a small set of hot products.
+// Every order holds several products listed in a random order, which is what used to deadlock.
+const (
+	BENCH_CONCURRENT_USER = 200
+	BENCH_PRODUCTS        = 10
+	BENCH_ITEMS_PER_ORDER = 3
+)
+
+func setUpBenchDB(b *testing.B) (*pgxpool.Pool, []int, []int) {
+	url := os.Getenv("PG_URL")
+	if url == "" {
+		b.Skip("PG_URL is not set")
+	}
+
+	ctx := context.Background()
+
+	db, err := pgxpool.New(ctx, url)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(db.Close)
+
+	runId := time.Now().UnixNano()
+
+	userIds := make([]int, 0, BENCH_CONCURRENT_USER)
+	for i := 0; i < BENCH_CONCURRENT_USER; i++ {
+		var id int
+
+		err := db.QueryRow(ctx, "INSERT INTO users (username, password, balance) VALUES ($1, $2, $3) RETURNING id", fmt.Sprintf("bench-%d-%d", runId, i), "bench", 1e9).Scan(&id)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		userIds = append(userIds, id)
+	}
+
+	productIds := make([]int, 0, BENCH_PRODUCTS)
+	for i := 0; i < BENCH_PRODUCTS; i++ {
+		var id int
+
+		err := db.QueryRow(ctx, "INSERT INTO products (name, quantity, price) VALUES ($1, $2, $3) RETURNING id", fmt.Sprintf("bench-%d-%d", runId, i), 1e9, 1).Scan(&id)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		productIds = append(productIds, id)
+	}
+
+	return db, userIds, productIds
+}
+
+func newBenchOrder(rnd *rand.Rand, userIds, productIds []int) *entity.Order {
+	items := make([]entity.OrderItem, 0, BENCH_ITEMS_PER_ORDER)
+	for _, idx := range rnd.Perm(len(productIds))[:BENCH_ITEMS_PER_ORDER] {
+		items = append(items, entity.OrderItem{ProductId: productIds[idx], Quantity: 1})
+	}
+
+	order := entity.NewOrder(0, userIds[rnd.Intn(len(userIds))], 0, items)
+	order.SetCurrency("USD")
+
+	return &order
+}
+
+// createOrderPerRow is the previous strategy: one locking select, one insert and one update per item, in request order.
+func createOrderPerRow(ctx context.Context, db *pgxpool.Pool, order *entity.Order) error {
+	return pkg.RunInTransaction(ctx, db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "SELECT id FROM users WHERE id = $1 FOR UPDATE", order.GetUserIdSafe())
+		if err != nil {
+			return err
+		}
+
+		items := order.GetItemsSafe()
+		for _, item := range items {
+			var product productEntity.Product
+
+			err := tx.QueryRow(ctx, "SELECT id, name, price FROM products WHERE id = $1 FOR UPDATE", item.GetProductId()).Scan(&product.Id, &product.Name, &product.Price)
+			if err != nil {
+				return err
+			}
+		}
+
+		var orderId int
+
+		err = tx.QueryRow(ctx, orderRepo.QUERY_CREATE_ORDER_WITH_RETURN_ID, order.GetUserIdSafe(), len(items), entity.OrderStatusPending).Scan(&orderId)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			_, err = tx.Exec(ctx, "INSERT INTO order_items (order_id, product_id, product_name, product_price, quantity) VALUES ($1, $2, $3, $4, $5)", orderId, item.GetProductId(), "", 1, item.GetQuantity())
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(ctx, "UPDATE products SET quantity = quantity - $2, updated_at = $3 WHERE id = $1", item.GetProductId(), item.GetQuantity(), time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(ctx, "UPDATE users SET balance = balance - $2, updated_at = $3 WHERE id = $1", order.GetUserIdSafe(), len(items), time.Now())
+
+		return err
+	})
+}
+
+func runOrderBenchmark(b *testing.B, userIds, productIds []int, createFn func(ctx context.Context, order *entity.Order) error) {
+	var deadlocks atomic.Int64
+
+	b.SetParallelism(BENCH_CONCURRENT_USER/runtime.GOMAXPROCS(0) + 1)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+		for pb.Next() {
+			err := createFn(context.Background(), newBenchOrder(rnd, userIds, productIds))
+			if err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == "40P01" {
+					deadlocks.Add(1)
+					continue
+				}
+
+				b.Error(err)
+			}
+		}
+	})
+
+	b.ReportMetric(float64(deadlocks.Load()), "deadlocks")
+}
+
+func BenchmarkCreateOrder(b *testing.B) {
+	db, userIds, productIds := setUpBenchDB(b)
+
+	repo := orderRepo.NewOrderRepo(db)
+	// orders skip currency, tax, address resolution and payment, the callback prices them at a rate of one without taxes
+	uc := usecase.NewUsecase(repo, nil, nil, nil, nil, nil, time.Hour, time.Hour)
+
+	b.Run("PerRowLock", func(b *testing.B) {
+		runOrderBenchmark(b, userIds, productIds, func(ctx context.Context, order *entity.Order) error {
+			return createOrderPerRow(ctx, db, order)
+		})
+	})
+
+	b.Run("BatchedLock", func(b *testing.B) {
+		runOrderBenchmark(b, userIds, productIds, func(ctx context.Context, order *entity.Order) error {
+			return repo.CreateOrder(ctx, order, func(order *entity.Order, user *userEntity.User, products *[]productEntity.Product, coupons *[]couponEntity.Coupon) (bool, error) {
+				return uc.CreateOrderCallback(order, user, products, coupons, nil)
+			})
+		})
+	})
+}
@@ -0,0 +1,172 @@
+package test
+
+import (
+	"order_service/services/order/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type OrderStatusTestSuite struct {
+	suite.Suite
+}
+
+func (suite *OrderStatusTestSuite) TestIsValid() {
+	tests := []struct {
+		name   string
+		status entity.OrderStatus
+		want   bool
+	}{
+		{
+			name:   "Known status",
+			status: entity.OrderStatusShipped,
+			want:   true,
+		},
+		{
+			name:   "Unknown status",
+			status: entity.OrderStatus("done"),
+			want:   false,
+		},
+		{
+			name:   "Empty status",
+			status: entity.OrderStatus(""),
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.Equal(tt.want, tt.status.IsValid(), "status validity should be reported correctly")
+		})
+	}
+}
+
+func (suite *OrderStatusTestSuite) TestCanTransitionTo() {
+	tests := []struct {
+		name string
+		from entity.OrderStatus
+		to   entity.OrderStatus
+		want bool
+	}{
+		{
+			name: "Pending to paid",
+			from: entity.OrderStatusPending,
+			to:   entity.OrderStatusPaid,
+			want: true,
+		},
+		{
+			name: "Pending to cancelled",
+			from: entity.OrderStatusPending,
+			to:   entity.OrderStatusCancelled,
+			want: true,
+		},
+		{
+			name: "Paid to fulfilling",
+			from: entity.OrderStatusPaid,
+			to:   entity.OrderStatusFulfilling,
+			want: true,
+		},
+		{
+			name: "Fulfilling to shipped",
+			from: entity.OrderStatusFulfilling,
+			to:   entity.OrderStatusShipped,
+			want: true,
+		},
+		{
+			name: "Shipped to delivered",
+			from: entity.OrderStatusShipped,
+			to:   entity.OrderStatusDelivered,
+			want: true,
+		},
+		{
+			name: "Pending skips to shipped",
+			from: entity.OrderStatusPending,
+			to:   entity.OrderStatusShipped,
+			want: false,
+		},
+		{
+			name: "Shipped cannot be cancelled",
+			from: entity.OrderStatusShipped,
+			to:   entity.OrderStatusCancelled,
+			want: false,
+		},
+		{
+			name: "Delivered is terminal",
+			from: entity.OrderStatusDelivered,
+			to:   entity.OrderStatusPending,
+			want: false,
+		},
+		{
+			name: "Cancelled is terminal",
+			from: entity.OrderStatusCancelled,
+			to:   entity.OrderStatusPaid,
+			want: false,
+		},
+		{
+			name: "Same status",
+			from: entity.OrderStatusPaid,
+			to:   entity.OrderStatusPaid,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.Equal(tt.want, tt.from.CanTransitionTo(tt.to), "transition should be checked correctly")
+		})
+	}
+}
+
+func (suite *OrderStatusTestSuite) TestIsTerminal() {
+	suite.True(entity.OrderStatusDelivered.IsTerminal(), "delivered should be terminal")
+	suite.True(entity.OrderStatusCancelled.IsTerminal(), "cancelled should be terminal")
+	suite.False(entity.OrderStatusPending.IsTerminal(), "pending should not be terminal")
+	suite.False(entity.OrderStatus("unknown").IsTerminal(), "unknown status should not be terminal")
+}
+
+func (suite *OrderStatusTestSuite) TestStatusRequestValidate() {
+	suite.NoError(entity.OrderStatusRequest{Status: entity.OrderStatusPaid}.Validate(), "known status should be accepted")
+	suite.ErrorIs(entity.OrderStatusRequest{Status: "done"}.Validate(), entity.ErrInvalidOrderStatus, "unknown status should be rejected")
+}
+
+func (suite *OrderStatusTestSuite) TestReservationIsLapsed() {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		reservation entity.Reservation
+		want        bool
+	}{
+		{
+			name:        "Active reservation in time",
+			reservation: entity.Reservation{Status: entity.ReservationActive, ExpiresAt: now.Add(time.Minute)},
+			want:        false,
+		},
+		{
+			name:        "Active reservation out of time",
+			reservation: entity.Reservation{Status: entity.ReservationActive, ExpiresAt: now.Add(-time.Minute)},
+			want:        true,
+		},
+		{
+			name:        "Expired reservation",
+			reservation: entity.Reservation{Status: entity.ReservationExpired, ExpiresAt: now.Add(-time.Minute)},
+			want:        true,
+		},
+		{
+			name:        "Confirmed reservation never lapses",
+			reservation: entity.Reservation{Status: entity.ReservationConfirmed, ExpiresAt: now.Add(-time.Minute)},
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.Equal(tt.want, tt.reservation.IsLapsed(now), "lapsed reservations should be reported correctly")
+		})
+	}
+}
+
+func TestOrderStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(OrderStatusTestSuite))
+}
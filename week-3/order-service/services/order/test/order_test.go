@@ -1,6 +1,7 @@
 package test
 
 import (
+	"order_service/internal/core"
 	"order_service/services/order/entity"
 	"testing"
 	"time"
@@ -59,7 +60,7 @@ func (suite *OrderTestSuite) TestNewOrder() {
 
 	suite.Equal(1, order.Id, "Id should be set correctly")
 	suite.Equal(1, order.UserId, "User Id should be set correctly")
-	suite.Equal(float32(100), order.TotalPrice, "TotalPrice should be set correctly")
+	suite.Equal(core.NewMoney(100), order.TotalPrice, "TotalPrice should be set correctly")
 	suite.Equal(items, order.Items, "Order's Items should be set correctly")
 }
 
@@ -141,8 +142,8 @@ func (suite *OrderTestSuite) TestSetTotalPrice() {
 	tests := []struct {
 		name       string
 		order      *entity.Order
-		totalPrice float32
-		want       float32
+		totalPrice core.Money
+		want       core.Money
 		panic      bool
 	}{
 		{
@@ -257,7 +258,7 @@ func (suite *OrderTestSuite) TestAddItem_NonNilOrder() {
 		OrderId:      1,
 		ProductId:    3,
 		ProductName:  "pineapple",
-		ProductPrice: 25.0,
+		ProductPrice: 25,
 		Quantity:     2,
 	}
 
@@ -351,7 +352,7 @@ func (suite *OrderTestSuite) TestGetTotalPriceSafe() {
 	tests := []struct {
 		name  string
 		order *entity.Order
-		want  float32
+		want  core.Money
 	}{
 		{
 			name:  "Non nil order",
@@ -425,6 +426,34 @@ func (suite *OrderTestSuite) TestGetItemSafe() {
 	}
 }
 
+func (suite *OrderTestSuite) TestOrderAddressLines() {
+	tests := []struct {
+		name    string
+		address *entity.OrderAddress
+		want    []string
+	}{
+		{
+			name:    "Full address",
+			address: &entity.OrderAddress{Recipient: "Jane Doe", Line1: "1 Market St", Line2: "Suite 200", City: "San Francisco", State: "CA", PostalCode: "94105", Country: "US", Phone: "+1 415 555 0100"},
+			want:    []string{"Jane Doe", "1 Market St", "Suite 200", "San Francisco, CA 94105", "US", "+1 415 555 0100"},
+		},
+		{
+			name:    "Empty parts are left out",
+			address: &entity.OrderAddress{Recipient: "Jane Doe", Line1: "10 Downing St", City: "London", PostalCode: "SW1A 2AA", Country: "GB"},
+			want:    []string{"Jane Doe", "10 Downing St", "London, SW1A 2AA", "GB"},
+		},
+		{
+			name:    "Nil address",
+			address: nil,
+			want:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Equal(tt.want, tt.address.Lines(), "address should be written like on a parcel")
+	}
+}
+
 func TestOrderTestSuite(t *testing.T) {
 	suite.Run(t, new(OrderTestSuite))
 }
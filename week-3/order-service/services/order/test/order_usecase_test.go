@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"order_service/internal/core"
+	addressEntity "order_service/services/address/entity"
+	couponEntity "order_service/services/coupon/entity"
 	orderEntity "order_service/services/order/entity"
 	"order_service/services/order/test/mock"
 	"order_service/services/order/usecase"
+	paymentEntity "order_service/services/payment/entity"
 	productEntity "order_service/services/product/entity"
+	shipmentEntity "order_service/services/shipment/entity"
+	taxEntity "order_service/services/tax/entity"
 	userEntity "order_service/services/user/entity"
 	"testing"
 	"time"
@@ -19,15 +24,25 @@ import (
 
 type OrderUsecaseTestSuite struct {
 	suite.Suite
-	mockRepo *mock.MockOrderRepository
-	usecase  usecase.OrderUsecase
+	mockRepo     *mock.MockOrderRepository
+	mockCurrency *mock.MockCurrencyResolver
+	mockTax      *mock.MockTaxResolver
+	mockAddress  *mock.MockAddressResolver
+	mockShipment *mock.MockShipmentResolver
+	mockPayment  *mock.MockPaymentProcessor
+	usecase      usecase.OrderUsecase
 }
 
 func (suite *OrderUsecaseTestSuite) SetupTest() {
 	ctrl := gomock.NewController(suite.T())
 
 	suite.mockRepo = mock.NewMockOrderRepository(ctrl)
-	suite.usecase = usecase.NewUsecase(suite.mockRepo)
+	suite.mockCurrency = mock.NewMockCurrencyResolver(ctrl)
+	suite.mockTax = mock.NewMockTaxResolver(ctrl)
+	suite.mockAddress = mock.NewMockAddressResolver(ctrl)
+	suite.mockShipment = mock.NewMockShipmentResolver(ctrl)
+	suite.mockPayment = mock.NewMockPaymentProcessor(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, suite.mockCurrency, suite.mockTax, suite.mockAddress, suite.mockShipment, suite.mockPayment, time.Hour, 15*time.Minute)
 }
 
 func (suite *OrderUsecaseTestSuite) TestCreateOrder() {
@@ -48,12 +63,25 @@ func (suite *OrderUsecaseTestSuite) TestCreateOrder() {
 		},
 	}
 
+	savedAddress := &addressEntity.Address{Id: 3, UserId: 1, Recipient: "Jane Doe", Line1: "1 Market St", City: "San Francisco", State: "CA", PostalCode: "94105", Country: "US", IsDefault: true}
+	inlineAddress := &orderEntity.OrderAddress{Recipient: "John Doe", Line1: "2 Mission St", City: "San Francisco", Country: "US"}
+	captured := &paymentEntity.Payment{Id: 5, OrderId: 1, UserId: 1, Provider: paymentEntity.PaymentMethodWallet, Status: paymentEntity.PaymentStatusCaptured, Amount: 150}
+	declined := &paymentEntity.Payment{Id: 5, OrderId: 1, UserId: 1, Provider: paymentEntity.PaymentMethodWallet, Status: paymentEntity.PaymentStatusFailed, FailureReason: paymentEntity.ErrInsufficientBalance.Error()}
+
 	tests := []struct {
-		name      string
-		order     *orderEntity.Order
-		repoErr   error
-		want      error
-		assertion assert.ErrorAssertionFunc
+		name        string
+		order       *orderEntity.Order
+		currencyErr error
+		taxErr      error
+		address     *addressEntity.Address
+		addressErr  error
+		wantAddress *orderEntity.OrderAddress
+		repoErr     error
+		payment     *paymentEntity.Payment
+		payErr      error
+		wantStatus  orderEntity.OrderStatus
+		want        error
+		assertion   assert.ErrorAssertionFunc
 	}{
 		{
 			name: "Successful order creation",
@@ -62,11 +90,14 @@ func (suite *OrderUsecaseTestSuite) TestCreateOrder() {
 				UserId:     1,
 				TotalPrice: 150,
 				Items:      items,
+				Status:     orderEntity.OrderStatusPending,
 				CreatedAt:  time.Now(),
 			},
-			repoErr:   nil,
-			want:      nil,
-			assertion: assert.NoError,
+			repoErr:    nil,
+			payment:    captured,
+			wantStatus: orderEntity.OrderStatusPaid,
+			want:       nil,
+			assertion:  assert.NoError,
 		},
 		{
 			name: "Product out of stock",
@@ -82,17 +113,32 @@ func (suite *OrderUsecaseTestSuite) TestCreateOrder() {
 			assertion: assert.Error,
 		},
 		{
-			name: "Insufficient user's balance",
+			name: "Declined payment leaves the order pending",
 			order: &orderEntity.Order{
 				Id:         1,
 				UserId:     1,
 				TotalPrice: 150,
 				Items:      items,
+				Status:     orderEntity.OrderStatusPending,
 				CreatedAt:  time.Now(),
 			},
-			repoErr:   orderEntity.ErrInsufficientBalance,
-			want:      core.ErrConfict.WithError(orderEntity.ErrInsufficientBalance.Error()),
-			assertion: assert.Error,
+			payment:    declined,
+			wantStatus: orderEntity.OrderStatusPending,
+			assertion:  assert.NoError,
+		},
+		{
+			name: "Payment cannot be started",
+			order: &orderEntity.Order{
+				Id:         1,
+				UserId:     1,
+				TotalPrice: 150,
+				Items:      items,
+				Status:     orderEntity.OrderStatusPending,
+				CreatedAt:  time.Now(),
+			},
+			payErr:     core.ErrInternalServerError.WithError(paymentEntity.ErrCannotCreatePayment.Error()),
+			wantStatus: orderEntity.OrderStatusPending,
+			assertion:  assert.NoError,
 		},
 		{
 			name: "Unknown error",
@@ -107,19 +153,143 @@ func (suite *OrderUsecaseTestSuite) TestCreateOrder() {
 			want:      core.ErrInternalServerError.WithError(orderEntity.ErrCannotCreateOrder.Error()).WithDebug(errors.New("this is an error").Error()),
 			assertion: assert.Error,
 		},
+		{
+			name: "Unknown coupon",
+			order: &orderEntity.Order{
+				Id:          1,
+				UserId:      1,
+				Items:       items,
+				CouponCodes: []string{"NOPE"},
+				CreatedAt:   time.Now(),
+			},
+			repoErr:   couponEntity.ErrCouponNotFound,
+			want:      core.ErrNotFound.WithError(couponEntity.ErrCouponNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name: "Expired coupon",
+			order: &orderEntity.Order{
+				Id:          1,
+				UserId:      1,
+				Items:       items,
+				CouponCodes: []string{"SUMMER"},
+				CreatedAt:   time.Now(),
+			},
+			repoErr:   couponEntity.ErrCouponExpired,
+			want:      core.ErrUnprocessableEntity.WithError(couponEntity.ErrCouponExpired.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name: "Unsupported currency",
+			order: &orderEntity.Order{
+				Id:        1,
+				UserId:    1,
+				Currency:  "XYZ",
+				Items:     items,
+				CreatedAt: time.Now(),
+			},
+			currencyErr: core.ErrBadRequest.WithError("currency has no exchange rate"),
+			want:        core.ErrBadRequest.WithError("currency has no exchange rate"),
+			assertion:   assert.Error,
+		},
+		{
+			name: "Invalid region",
+			order: &orderEntity.Order{
+				Id:        1,
+				UserId:    1,
+				Region:    "not a region",
+				Items:     items,
+				CreatedAt: time.Now(),
+			},
+			taxErr:    core.ErrBadRequest.WithError(taxEntity.ErrInvalidRegion.Error()),
+			want:      core.ErrBadRequest.WithError(taxEntity.ErrInvalidRegion.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name: "Shipped to the default address",
+			order: &orderEntity.Order{
+				Id:        1,
+				UserId:    1,
+				Items:     items,
+				CreatedAt: time.Now(),
+			},
+			address:     savedAddress,
+			wantAddress: &orderEntity.OrderAddress{AddressId: 3, Recipient: "Jane Doe", Line1: "1 Market St", City: "San Francisco", State: "CA", PostalCode: "94105", Country: "US"},
+			assertion:   assert.NoError,
+		},
+		{
+			name: "Shipped to the given address",
+			order: &orderEntity.Order{
+				Id:              1,
+				UserId:          1,
+				Items:           items,
+				ShippingAddress: inlineAddress,
+				CreatedAt:       time.Now(),
+			},
+			wantAddress: inlineAddress,
+			assertion:   assert.NoError,
+		},
+		{
+			name: "Unknown saved address",
+			order: &orderEntity.Order{
+				Id:        1,
+				UserId:    1,
+				AddressId: 9,
+				Items:     items,
+				CreatedAt: time.Now(),
+			},
+			addressErr: core.ErrNotFound.WithError(addressEntity.ErrAddressNotFound.Error()),
+			want:       core.ErrNotFound.WithError(addressEntity.ErrAddressNotFound.Error()),
+			assertion:  assert.Error,
+		},
 	}
 
 	for _, tt := range tests {
 		suite.Run(tt.name, func() {
 			suite.SetupTest()
 
-			suite.mockRepo.EXPECT().CreateOrder(gomock.Any(), tt.order, gomock.Any()).Return(tt.repoErr)
+			suite.mockCurrency.EXPECT().ResolveCurrency(gomock.Any(), 1, tt.order.Currency).Return("USD", core.RateOne, tt.currencyErr)
+			if tt.currencyErr == nil {
+				suite.mockTax.EXPECT().ResolveRules(gomock.Any(), tt.order.Region).Return("US", taxEntity.TaxRules{}, tt.taxErr)
+			}
+			if tt.currencyErr == nil && tt.taxErr == nil && tt.order.ShippingAddress == nil {
+				suite.mockAddress.EXPECT().ResolveAddress(gomock.Any(), 1, tt.order.AddressId).Return(tt.address, tt.addressErr)
+			}
+			if tt.currencyErr == nil && tt.taxErr == nil && tt.addressErr == nil {
+				suite.mockRepo.EXPECT().CreateOrder(gomock.Any(), tt.order, gomock.Any()).Return(tt.repoErr)
+			}
+			if tt.currencyErr == nil && tt.taxErr == nil && tt.addressErr == nil && tt.repoErr == nil {
+				suite.mockPayment.EXPECT().Pay(gomock.Any(), 1, 1, &paymentEntity.PaymentRequest{}).Return(tt.payment, tt.payErr)
+			}
+			if tt.payment.GetStatusSafe() == paymentEntity.PaymentStatusCaptured {
+				suite.mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), 1, 1, orderEntity.OrderStatusPaid, gomock.Any()).Return(nil)
+			}
 
-			err := suite.usecase.CreateOrder(context.Background(), tt.order)
+			err := suite.usecase.CreateOrder(requesterContext(1, 0), tt.order)
 
 			if tt.assertion(suite.T(), err) {
 				suite.ErrorIs(err, tt.want, "error should return correctly")
 			}
+			if tt.currencyErr == nil {
+				suite.Equal("USD", tt.order.GetCurrencySafe(), "resolved currency should be set on the order")
+			}
+			if tt.currencyErr == nil && tt.taxErr == nil {
+				suite.Equal("US", tt.order.GetRegionSafe(), "resolved region should be set on the order")
+				suite.WithinDuration(time.Now().Add(15*time.Minute), tt.order.GetReservedUntilSafe(), time.Minute, "stock should be reserved for the configured time")
+			}
+			if tt.addressErr == nil {
+				suite.Equal(tt.wantAddress, tt.order.GetShippingAddressSafe(), "shipping address should be copied on the order")
+			}
+			if tt.wantStatus != "" {
+				suite.Equal(tt.wantStatus, tt.order.GetStatusSafe(), "order should only be paid once its payment is captured")
+			}
+			if tt.wantStatus != "" && tt.payErr == nil {
+				suite.Equal(tt.payment, tt.order.Payment, "payment attempt should be attached to the order")
+			}
+			if tt.payErr != nil {
+				suite.Equal(paymentEntity.PaymentStatusFailed, tt.order.Payment.GetStatusSafe(), "payment that cannot be made should come back failed")
+				suite.Equal(tt.payErr.Error(), tt.order.Payment.FailureReason, "reason of the failure should be given")
+			}
 		})
 	}
 }
@@ -246,7 +416,7 @@ func (suite *OrderUsecaseTestSuite) TestCreateOrderCallback() {
 			assertion: assert.Error,
 		},
 		{
-			name: "Insufficient user's balance",
+			name: "Balance is left to the payment",
 			order: &orderEntity.Order{
 				Id:         0,
 				UserId:     1,
@@ -275,9 +445,9 @@ func (suite *OrderUsecaseTestSuite) TestCreateOrderCallback() {
 				Balance:  50,
 			},
 			products:  products,
-			want:      false,
-			wantErr:   orderEntity.ErrInsufficientBalance,
-			assertion: assert.Error,
+			want:      true,
+			wantErr:   nil,
+			assertion: assert.NoError,
 		},
 	}
 
@@ -285,7 +455,7 @@ func (suite *OrderUsecaseTestSuite) TestCreateOrderCallback() {
 		suite.Run(tt.name, func() {
 			suite.SetupTest()
 
-			accept, err := suite.usecase.CreateOrderCallback(tt.order, tt.user, tt.products)
+			accept, err := suite.usecase.CreateOrderCallback(tt.order, tt.user, tt.products, &[]couponEntity.Coupon{}, nil)
 
 			suite.Equal(tt.want, accept, "first return argument must be equal")
 
@@ -296,42 +466,375 @@ func (suite *OrderUsecaseTestSuite) TestCreateOrderCallback() {
 	}
 }
 
-func (suite *OrderUsecaseTestSuite) TestGetOrders() {
+func (suite *OrderUsecaseTestSuite) TestCreateOrderCallbackExactTotal() {
+	// 0.10 three times and 0.20 is exactly 0.50, which float32 arithmetic used to miss
+	user := &userEntity.User{Id: 1, Balance: core.NewMoney(50)}
+	products := &[]productEntity.Product{
+		{Id: 1, Name: "candy", Quantity: 10, Price: core.NewMoney(10)},
+		{Id: 2, Name: "gum", Quantity: 10, Price: core.NewMoney(20)},
+	}
+	order := &orderEntity.Order{
+		UserId: 1,
+		Items: []orderEntity.OrderItem{
+			{ProductId: 1, Quantity: 3},
+			{ProductId: 2, Quantity: 1},
+		},
+	}
+
+	accept, err := suite.usecase.CreateOrderCallback(order, user, products, &[]couponEntity.Coupon{}, nil)
+
+	suite.NoError(err, "exact total should be accepted")
+	suite.True(accept, "order should be accepted")
+	suite.Equal(core.NewMoney(50), order.GetTotalPriceSafe(), "total price should be exact")
+	suite.Equal(core.NewMoney(10), order.GetItemSafe(0).GetProductPrice(), "item price should be copied from product")
+}
+
+func (suite *OrderUsecaseTestSuite) TestCreateOrderCallbackConvertsCurrency() {
+	rate, _ := core.ParseRate("0.92")
+	user := &userEntity.User{Id: 1, Balance: core.NewMoney(5000)}
+	products := &[]productEntity.Product{
+		{Id: 1, Name: "orange", Quantity: 10, Price: core.NewMoney(1999)},
+		{Id: 2, Name: "apple", Quantity: 10, Price: core.NewMoney(500)},
+	}
+	order := &orderEntity.Order{
+		UserId:       1,
+		Currency:     "EUR",
+		ExchangeRate: rate,
+		Items: []orderEntity.OrderItem{
+			{ProductId: 1, Quantity: 2},
+			{ProductId: 2, Quantity: 1},
+		},
+	}
+
+	accept, err := suite.usecase.CreateOrderCallback(order, user, products, &[]couponEntity.Coupon{}, nil)
+
+	suite.NoError(err)
+	suite.True(accept, "order should be accepted")
+	// 19.99 * 0.92 = 18.3908 rounds to 18.39, 5.00 * 0.92 = 4.60
+	suite.Equal(core.NewMoney(1839), order.GetItemSafe(0).GetProductPrice(), "unit price should be converted")
+	suite.Equal(core.NewMoney(4138), order.GetTotalPriceSafe(), "total should add up the converted lines")
+	suite.Equal(core.NewMoney(4498), order.GetBaseTotalPriceSafe(), "base total should stay in the base currency")
+	suite.Equal("EUR", order.GetItemSafe(1).GetCurrency(), "items should carry the charged currency")
+	suite.Equal(rate, order.GetItemSafe(1).GetExchangeRate(), "items should carry the rate used")
+}
+
+func (suite *OrderUsecaseTestSuite) TestCreateOrderCallbackAppliesCoupons() {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	orangeId := 1
+
+	percentage := couponEntity.Coupon{Id: 1, Code: "TEN", Type: couponEntity.CouponPercentage, PercentOff: 10, Active: true}
+	fixed := couponEntity.Coupon{Id: 2, Code: "FIVE", Type: couponEntity.CouponFixed, AmountOff: core.NewMoney(500), Active: true, ExpiresAt: &future}
+	buyTwoGetOne := couponEntity.Coupon{Id: 3, Code: "B2G1", Type: couponEntity.CouponBuyXGetY, ProductId: &orangeId, BuyQuantity: 2, GetQuantity: 1, Active: true}
+	huge := couponEntity.Coupon{Id: 4, Code: "HUGE", Type: couponEntity.CouponFixed, AmountOff: core.NewMoney(100000), Active: true}
+
+	tests := []struct {
+		name          string
+		coupons       []couponEntity.Coupon
+		want          bool
+		wantTotal     core.Money
+		wantDiscounts int
+		wantErr       error
+		assertion     assert.ErrorAssertionFunc
+	}{
+		{
+			// 3 oranges at 10.00 and an apple at 5.00
+			name:      "Without coupon",
+			want:      true,
+			wantTotal: core.NewMoney(3500),
+			assertion: assert.NoError,
+		},
+		{
+			name:          "Percentage coupon",
+			coupons:       []couponEntity.Coupon{percentage},
+			want:          true,
+			wantTotal:     core.NewMoney(3150),
+			wantDiscounts: 1,
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Stacked fixed and buy two get one coupons",
+			coupons:       []couponEntity.Coupon{fixed, buyTwoGetOne},
+			want:          true,
+			wantTotal:     core.NewMoney(2000),
+			wantDiscounts: 2,
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Discount never goes below zero",
+			coupons:       []couponEntity.Coupon{huge, percentage},
+			want:          true,
+			wantTotal:     core.NewMoney(0),
+			wantDiscounts: 2,
+			assertion:     assert.NoError,
+		},
+		{
+			name:      "Minimum spend not reached",
+			coupons:   []couponEntity.Coupon{{Id: 5, Code: "BIG", Type: couponEntity.CouponPercentage, PercentOff: 5, MinSpend: core.NewMoney(10000), Active: true}},
+			wantErr:   couponEntity.ErrCouponMinSpend,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Expired coupon",
+			coupons:   []couponEntity.Coupon{{Id: 6, Code: "OLD", Type: couponEntity.CouponPercentage, PercentOff: 5, ExpiresAt: &past, Active: true}},
+			wantErr:   couponEntity.ErrCouponExpired,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Per user limit reached",
+			coupons:   []couponEntity.Coupon{{Id: 7, Code: "ONCE", Type: couponEntity.CouponPercentage, PercentOff: 5, PerUserLimit: 1, UserRedeemedCount: 1, Active: true}},
+			wantErr:   couponEntity.ErrCouponUserLimitExceeded,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Coupon fully redeemed",
+			coupons:   []couponEntity.Coupon{{Id: 8, Code: "FIRST100", Type: couponEntity.CouponPercentage, PercentOff: 5, UsageLimit: 100, RedeemedCount: 100, Active: true}},
+			wantErr:   couponEntity.ErrCouponUsageExceeded,
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			user := &userEntity.User{Id: 1, Balance: core.NewMoney(10000)}
+			products := &[]productEntity.Product{
+				{Id: 1, Name: "orange", Quantity: 10, Price: core.NewMoney(1000)},
+				{Id: 2, Name: "apple", Quantity: 10, Price: core.NewMoney(500)},
+			}
+			order := &orderEntity.Order{
+				UserId: 1,
+				Items: []orderEntity.OrderItem{
+					{ProductId: 1, Quantity: 3},
+					{ProductId: 2, Quantity: 1},
+				},
+			}
+			coupons := tt.coupons
+			if coupons == nil {
+				coupons = []couponEntity.Coupon{}
+			}
+
+			accept, err := suite.usecase.CreateOrderCallback(order, user, products, &coupons, nil)
+
+			suite.Equal(tt.want, accept, "first return argument must be equal")
+			if !tt.assertion(suite.T(), err) {
+				return
+			}
+			if tt.wantErr != nil {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+				return
+			}
+
+			suite.Equal(tt.wantTotal, order.GetTotalPriceSafe(), "total should be net of discounts")
+			suite.Equal(tt.wantTotal, order.GetBaseTotalPriceSafe(), "base total should be net of discounts")
+			suite.Equal(tt.wantTotal, order.GetBaseTotalPriceSafe(), "payment should be charged the discounted total")
+			suite.Len(order.GetDiscountsSafe(), tt.wantDiscounts, "every coupon should leave a discount line")
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestCreateOrderCallbackConvertsDiscounts() {
+	rate, _ := core.ParseRate("0.92")
+	user := &userEntity.User{Id: 1, Balance: core.NewMoney(5000)}
+	products := &[]productEntity.Product{
+		{Id: 1, Name: "orange", Quantity: 10, Price: core.NewMoney(2000)},
+	}
+	order := &orderEntity.Order{
+		UserId:       1,
+		Currency:     "EUR",
+		ExchangeRate: rate,
+		Items:        []orderEntity.OrderItem{{ProductId: 1, Quantity: 1}},
+	}
+	coupons := &[]couponEntity.Coupon{{Id: 1, Code: "FIVE", Type: couponEntity.CouponFixed, AmountOff: core.NewMoney(500), Active: true}}
+
+	accept, err := suite.usecase.CreateOrderCallback(order, user, products, coupons, nil)
+
+	suite.NoError(err)
+	suite.True(accept, "order should be accepted")
+	// 20.00 * 0.92 = 18.40, the 5.00 discount is 4.60 once converted
+	suite.Equal(core.NewMoney(460), order.GetDiscountsSafe()[0].GetAmount(), "discount should be converted")
+	suite.Equal(core.NewMoney(500), order.GetDiscountsSafe()[0].GetBaseAmount(), "discount should keep its base amount")
+	suite.Equal(core.NewMoney(1380), order.GetTotalPriceSafe(), "total should be net of the converted discount")
+	suite.Equal(core.NewMoney(1500), order.GetBaseTotalPriceSafe(), "base total should be net of the base discount")
+}
+
+func (suite *OrderUsecaseTestSuite) TestCreateOrderCallbackAppliesTaxes() {
+	tenPercent, _ := core.ParseRate("0.1")
+	twentyPercent, _ := core.ParseRate("0.2")
+
+	vat := taxEntity.TaxRule{Id: 1, Name: "VAT", TaxClass: taxEntity.DefaultTaxClass, Rate: tenPercent, Rounding: core.RoundHalfUp}
+
 	tests := []struct {
 		name      string
-		repoErr   error
-		want      *[]orderEntity.Order
-		wantErr   error
-		assertion assert.ErrorAssertionFunc
+		rules     taxEntity.TaxRules
+		products  []productEntity.Product
+		items     []orderEntity.OrderItem
+		coupons   []couponEntity.Coupon
+		wantTotal core.Money
+		wantTaxes []core.Money
 	}{
 		{
-			name:    "Successful get orders",
-			repoErr: nil,
-			want: &[]orderEntity.Order{
+			name:      "Exclusive tax is added to the total",
+			rules:     taxEntity.TaxRules{taxEntity.DefaultTaxClass: vat},
+			products:  []productEntity.Product{{Id: 1, Name: "orange", Quantity: 10, Price: core.NewMoney(1999)}},
+			items:     []orderEntity.OrderItem{{ProductId: 1, Quantity: 1}},
+			wantTotal: core.NewMoney(2199),
+			wantTaxes: []core.Money{core.NewMoney(200)},
+		},
+		{
+			name: "Inclusive tax is already in the price",
+			rules: taxEntity.TaxRules{taxEntity.DefaultTaxClass: {
+				Id: 2, Name: "VAT", TaxClass: taxEntity.DefaultTaxClass, Rate: twentyPercent, Inclusive: true, Rounding: core.RoundHalfUp,
+			}},
+			products:  []productEntity.Product{{Id: 1, Name: "orange", Quantity: 10, Price: core.NewMoney(1200)}},
+			items:     []orderEntity.OrderItem{{ProductId: 1, Quantity: 1}},
+			wantTotal: core.NewMoney(1200),
+			wantTaxes: []core.Money{core.NewMoney(200)},
+		},
+		{
+			name: "Rounding mode of the rule is used",
+			rules: taxEntity.TaxRules{taxEntity.DefaultTaxClass: {
+				Id: 3, Name: "VAT", TaxClass: taxEntity.DefaultTaxClass, Rate: tenPercent, Rounding: core.RoundDown,
+			}},
+			products:  []productEntity.Product{{Id: 1, Name: "orange", Quantity: 10, Price: core.NewMoney(1999)}},
+			items:     []orderEntity.OrderItem{{ProductId: 1, Quantity: 1}},
+			wantTotal: core.NewMoney(2198),
+			wantTaxes: []core.Money{core.NewMoney(199)},
+		},
+		{
+			name:      "Class without rule is not taxed",
+			rules:     taxEntity.TaxRules{taxEntity.DefaultTaxClass: vat},
+			products:  []productEntity.Product{{Id: 1, Name: "bread", Quantity: 10, Price: core.NewMoney(1999), TaxClass: "food"}},
+			items:     []orderEntity.OrderItem{{ProductId: 1, Quantity: 1}},
+			wantTotal: core.NewMoney(1999),
+		},
+		{
+			name:  "Discount is spread over the lines before tax",
+			rules: taxEntity.TaxRules{taxEntity.DefaultTaxClass: vat},
+			products: []productEntity.Product{
+				{Id: 1, Name: "orange", Quantity: 10, Price: core.NewMoney(1000)},
+				{Id: 2, Name: "pineapple", Quantity: 10, Price: core.NewMoney(3000)},
+			},
+			items:   []orderEntity.OrderItem{{ProductId: 1, Quantity: 1}, {ProductId: 2, Quantity: 1}},
+			coupons: []couponEntity.Coupon{{Id: 1, Code: "FOUR", Type: couponEntity.CouponFixed, AmountOff: core.NewMoney(400), Active: true}},
+			// 40.00 - 4.00 leaves 9.00 and 27.00 taxable, 0.90 + 2.70 of tax
+			wantTotal: core.NewMoney(3960),
+			wantTaxes: []core.Money{core.NewMoney(360)},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			user := &userEntity.User{Id: 1, Balance: core.NewMoney(10000)}
+			order := &orderEntity.Order{UserId: 1, Items: tt.items}
+
+			accept, err := suite.usecase.CreateOrderCallback(order, user, &tt.products, &tt.coupons, tt.rules)
+
+			suite.NoError(err)
+			suite.True(accept, "order should be accepted")
+			suite.Equal(tt.wantTotal, order.GetTotalPriceSafe(), "total should include exclusive taxes")
+			suite.Equal(tt.wantTotal, order.GetBaseTotalPriceSafe(), "base total should include exclusive taxes")
+			suite.Equal(tt.wantTotal, order.GetBaseTotalPriceSafe(), "payment should be charged the taxed total")
+
+			taxes := make([]core.Money, 0, len(order.GetTaxesSafe()))
+			for _, tax := range order.GetTaxesSafe() {
+				taxes = append(taxes, tax.GetAmount())
+			}
+			if tt.wantTaxes == nil {
+				tt.wantTaxes = []core.Money{}
+			}
+			suite.Equal(tt.wantTaxes, taxes, "taxes should be summed per rule")
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestGetOrders() {
+	createdAt := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	orders := []orderEntity.Order{
+		{
+			Id:         3,
+			UserId:     2,
+			TotalPrice: 100,
+			CreatedAt:  createdAt.Add(2 * time.Hour),
+			Items: []orderEntity.OrderItem{
 				{
-					Id:         1,
-					UserId:     1,
-					TotalPrice: 100,
-					Items: []orderEntity.OrderItem{
-						{
-							OrderId:      1,
-							ProductId:    1,
-							ProductName:  "orange",
-							ProductPrice: 50,
-							Quantity:     2,
-						},
-					},
+					OrderId:      3,
+					ProductId:    1,
+					ProductName:  "orange",
+					ProductPrice: 50,
+					Quantity:     2,
 				},
 			},
-			wantErr:   nil,
+		},
+		{
+			Id:         2,
+			UserId:     2,
+			TotalPrice: 50,
+			CreatedAt:  createdAt.Add(time.Hour),
+		},
+		{
+			Id:         1,
+			UserId:     2,
+			TotalPrice: 25,
+			CreatedAt:  createdAt,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		filter         orderEntity.OrderFilter
+		wantUserId     int
+		repoOrders     *[]orderEntity.Order
+		repoErr        error
+		want           *[]orderEntity.Order
+		wantNextCursor string
+		wantErr        error
+		assertion      assert.ErrorAssertionFunc
+	}{
+		{
+			name:           "Last page of the current user",
+			ctx:            requesterContext(2, 0),
+			filter:         orderEntity.OrderFilter{SortBy: orderEntity.OrderSortByCreatedAt, Descending: true, UserId: 5, Limit: 3},
+			wantUserId:     2,
+			repoOrders:     &[]orderEntity.Order{orders[0], orders[1], orders[2]},
+			want:           &[]orderEntity.Order{orders[0], orders[1], orders[2]},
+			wantNextCursor: "",
+			assertion:      assert.NoError,
+		},
+		{
+			name:       "Page followed by another page",
+			ctx:        requesterContext(2, 0),
+			filter:     orderEntity.OrderFilter{SortBy: orderEntity.OrderSortByCreatedAt, Descending: true, Limit: 2},
+			wantUserId: 2,
+			repoOrders: &[]orderEntity.Order{orders[0], orders[1], orders[2]},
+			want:       &[]orderEntity.Order{orders[0], orders[1]},
+			wantNextCursor: orderEntity.NewOrderCursor(
+				&orderEntity.OrderFilter{SortBy: orderEntity.OrderSortByCreatedAt, Descending: true},
+				orders[1],
+			).Encode(),
 			assertion: assert.NoError,
 		},
 		{
-			name:      "Repo return an error",
-			repoErr:   errors.New("this is an error"),
-			want:      nil,
-			wantErr:   core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()).WithDebug(errors.New("this is an error").Error()),
-			assertion: assert.Error,
+			name:           "Admin filters by user",
+			ctx:            requesterContext(1, 1),
+			filter:         orderEntity.OrderFilter{SortBy: orderEntity.OrderSortByTotalPrice, Limit: 20, UserId: 2},
+			wantUserId:     2,
+			repoOrders:     &[]orderEntity.Order{orders[2]},
+			want:           &[]orderEntity.Order{orders[2]},
+			wantNextCursor: "",
+			assertion:      assert.NoError,
+		},
+		{
+			name:       "Repo return an error",
+			ctx:        requesterContext(1, 1),
+			filter:     orderEntity.OrderFilter{SortBy: orderEntity.OrderSortByCreatedAt, Limit: 20},
+			wantUserId: 0,
+			repoErr:    errors.New("this is an error"),
+			want:       nil,
+			wantErr:    core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()).WithDebug(errors.New("this is an error").Error()),
+			assertion:  assert.Error,
 		},
 	}
 
@@ -339,11 +842,19 @@ func (suite *OrderUsecaseTestSuite) TestGetOrders() {
 		suite.Run(tt.name, func() {
 			suite.SetupTest()
 
-			suite.mockRepo.EXPECT().GetOrders(gomock.Any()).Return(tt.want, tt.repoErr)
+			suite.mockRepo.EXPECT().GetOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, filter *orderEntity.OrderFilter) (*[]orderEntity.Order, error) {
+					suite.Equal(tt.wantUserId, filter.UserId, "user filter should be enforced")
+					suite.Equal(tt.filter.Limit+1, filter.Limit, "one extra order should be fetched")
 
-			orders, err := suite.usecase.GetOrders(context.Background())
+					return tt.repoOrders, tt.repoErr
+				})
 
-			suite.Equal(tt.want, orders, "orders should be retrieved correctly")
+			filter := tt.filter
+			got, nextCursor, err := suite.usecase.GetOrders(tt.ctx, &filter)
+
+			suite.Equal(tt.want, got, "orders should be retrieved correctly")
+			suite.Equal(tt.wantNextCursor, nextCursor, "next cursor should be returned correctly")
 
 			if tt.assertion(suite.T(), err) {
 				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
@@ -512,10 +1023,25 @@ func (suite *OrderUsecaseTestSuite) TestGetOrdersSummarize() {
 }
 
 func (suite *OrderUsecaseTestSuite) TestGetOrder() {
+	shipmentErr := core.ErrInternalServerError.WithError(shipmentEntity.ErrCannotGetShipments.Error())
+	shipments := []shipmentEntity.Shipment{
+		{
+			Id:             3,
+			OrderId:        1,
+			UserId:         1,
+			Carrier:        "ups",
+			TrackingNumber: "1Z999AA10123456784",
+			Status:         shipmentEntity.ShipmentStatusInTransit,
+		},
+	}
+
 	tests := []struct {
 		name            string
 		userId, orderId int
+		order           *orderEntity.Order
 		repoErr         error
+		shipments       []shipmentEntity.Shipment
+		shipmentErr     error
 		want            *orderEntity.Order
 		wantErr         error
 		assertion       assert.ErrorAssertionFunc
@@ -524,7 +1050,22 @@ func (suite *OrderUsecaseTestSuite) TestGetOrder() {
 			name:    "Successful get order",
 			userId:  1,
 			orderId: 1,
-			repoErr: nil,
+			order: &orderEntity.Order{
+				Id:         1,
+				UserId:     1,
+				TotalPrice: 100,
+				Items: []orderEntity.OrderItem{
+					{
+						OrderId:      1,
+						ProductId:    1,
+						ProductName:  "orange",
+						ProductPrice: 50,
+						Quantity:     2,
+					},
+				},
+			},
+			repoErr:   nil,
+			shipments: shipments,
 			want: &orderEntity.Order{
 				Id:         1,
 				UserId:     1,
@@ -538,6 +1079,7 @@ func (suite *OrderUsecaseTestSuite) TestGetOrder() {
 						Quantity:     2,
 					},
 				},
+				Shipments: shipments,
 			},
 			wantErr:   nil,
 			assertion: assert.NoError,
@@ -551,13 +1093,26 @@ func (suite *OrderUsecaseTestSuite) TestGetOrder() {
 			wantErr:   core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()).WithDebug(errors.New("this is an error").Error()),
 			assertion: assert.Error,
 		},
+		{
+			name:        "Shipments cannot be read",
+			userId:      1,
+			orderId:     1,
+			order:       &orderEntity.Order{Id: 1, UserId: 1},
+			shipmentErr: shipmentErr,
+			want:        nil,
+			wantErr:     shipmentErr,
+			assertion:   assert.Error,
+		},
 	}
 
 	for _, tt := range tests {
 		suite.Run(tt.name, func() {
 			suite.SetupTest()
 
-			suite.mockRepo.EXPECT().GetOrder(gomock.Any(), tt.userId, tt.orderId).Return(tt.want, tt.repoErr)
+			suite.mockRepo.EXPECT().GetOrder(gomock.Any(), tt.userId, tt.orderId).Return(tt.order, tt.repoErr)
+			if tt.repoErr == nil {
+				suite.mockShipment.EXPECT().GetOrderShipments(gomock.Any(), tt.orderId).Return(tt.shipments, tt.shipmentErr)
+			}
 
 			orders, err := suite.usecase.GetOrder(context.Background(), tt.userId, tt.orderId)
 
@@ -570,6 +1125,746 @@ func (suite *OrderUsecaseTestSuite) TestGetOrder() {
 	}
 }
 
+func (suite *OrderUsecaseTestSuite) TestUpdateOrderStatus() {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		status    orderEntity.OrderStatus
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Admin updates status",
+			ctx:       requesterContext(1, 1),
+			status:    orderEntity.OrderStatusFulfilling,
+			callRepo:  true,
+			repoErr:   nil,
+			wantErr:   nil,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Customer cannot update status",
+			ctx:       requesterContext(2, 0),
+			status:    orderEntity.OrderStatusFulfilling,
+			callRepo:  false,
+			wantErr:   core.ErrBadRequest.WithError(orderEntity.ErrCannotUpdateStatus.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Order is only paid through its payment",
+			ctx:       requesterContext(1, 1),
+			status:    orderEntity.OrderStatusPaid,
+			callRepo:  false,
+			wantErr:   core.ErrConfict.WithError(orderEntity.ErrPaidByPayment.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Order does not exist",
+			ctx:       requesterContext(1, 1),
+			status:    orderEntity.OrderStatusFulfilling,
+			callRepo:  true,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Invalid transition",
+			ctx:       requesterContext(1, 1),
+			status:    orderEntity.OrderStatusFulfilling,
+			callRepo:  true,
+			repoErr:   orderEntity.ErrInvalidTransition,
+			wantErr:   core.ErrConfict.WithError(orderEntity.ErrInvalidTransition.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo return an error",
+			ctx:       requesterContext(1, 1),
+			status:    orderEntity.OrderStatusFulfilling,
+			callRepo:  true,
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithError(orderEntity.ErrCannotUpdateStatus.Error()).WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), 1, 1, tt.status, gomock.Any()).Return(tt.repoErr)
+			}
+
+			err := suite.usecase.UpdateOrderStatus(tt.ctx, 1, tt.status)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestPayOrder() {
+	request := &paymentEntity.PaymentRequest{Method: paymentEntity.PaymentMethodCard, CardToken: "tok_visa"}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		payment     *paymentEntity.Payment
+		payErr      error
+		callRepo    bool
+		repoErr     error
+		callRelease bool
+		want        *paymentEntity.Payment
+		wantErr     error
+		assertion   assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Card payment waits for the gateway",
+			ctx:       requesterContext(2, 0),
+			payment:   &paymentEntity.Payment{Id: 5, OrderId: 1, UserId: 2, Provider: paymentEntity.PaymentMethodCard, Reference: "ch_1", Status: paymentEntity.PaymentStatusPending},
+			want:      &paymentEntity.Payment{Id: 5, OrderId: 1, UserId: 2, Provider: paymentEntity.PaymentMethodCard, Reference: "ch_1", Status: paymentEntity.PaymentStatusPending},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Captured payment pays the order",
+			ctx:       requesterContext(2, 0),
+			payment:   &paymentEntity.Payment{Id: 5, OrderId: 1, UserId: 2, Status: paymentEntity.PaymentStatusCaptured},
+			callRepo:  true,
+			want:      &paymentEntity.Payment{Id: 5, OrderId: 1, UserId: 2, Status: paymentEntity.PaymentStatusCaptured},
+			assertion: assert.NoError,
+		},
+		{
+			name:        "Reservation expired before the payment was captured",
+			ctx:         requesterContext(2, 0),
+			payment:     &paymentEntity.Payment{Id: 5, OrderId: 1, UserId: 2, Status: paymentEntity.PaymentStatusCaptured},
+			callRepo:    true,
+			repoErr:     orderEntity.ErrReservationExpired,
+			callRelease: true,
+			wantErr:     core.ErrConfict.WithError(orderEntity.ErrReservationExpired.Error()),
+			assertion:   assert.Error,
+		},
+		{
+			name:      "Order already has a payment",
+			ctx:       requesterContext(2, 0),
+			payErr:    core.ErrConfict.WithError(paymentEntity.ErrPaymentInProgress.Error()),
+			wantErr:   core.ErrConfict.WithError(paymentEntity.ErrPaymentInProgress.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Admin cannot pay orders",
+			ctx:       requesterContext(1, 1),
+			wantErr:   core.ErrBadRequest.WithError(orderEntity.ErrCannotPayOrder.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.payment != nil || tt.payErr != nil {
+				suite.mockPayment.EXPECT().Pay(gomock.Any(), 1, 2, request).Return(tt.payment, tt.payErr)
+			}
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), 1, 2, orderEntity.OrderStatusPaid, gomock.Any()).Return(tt.repoErr)
+			}
+			if tt.callRelease {
+				suite.mockPayment.EXPECT().ReleaseOrderPayments(gomock.Any(), 1).Return(nil)
+			}
+
+			got, err := suite.usecase.PayOrder(tt.ctx, 1, request)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+			suite.Equal(tt.want, got, "payment should be returned correctly")
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestReceivePaymentEvent() {
+	body := []byte(`{"event_id":"evt_1"}`)
+
+	tests := []struct {
+		name      string
+		payment   *paymentEntity.Payment
+		eventErr  error
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Captured payment pays the order",
+			payment:   &paymentEntity.Payment{Id: 5, OrderId: 1, Status: paymentEntity.PaymentStatusCaptured},
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Authorized payment leaves the order pending",
+			payment:   &paymentEntity.Payment{Id: 5, OrderId: 1, Status: paymentEntity.PaymentStatusAuthorized},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Order was already paid",
+			payment:   &paymentEntity.Payment{Id: 5, OrderId: 1, Status: paymentEntity.PaymentStatusCaptured},
+			callRepo:  true,
+			repoErr:   orderEntity.ErrInvalidTransition,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Invalid signature",
+			eventErr:  core.ErrUnauthorized.WithError(paymentEntity.ErrInvalidSignature.Error()),
+			wantErr:   core.ErrUnauthorized.WithError(paymentEntity.ErrInvalidSignature.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo return an error",
+			payment:   &paymentEntity.Payment{Id: 5, OrderId: 1, Status: paymentEntity.PaymentStatusCaptured},
+			callRepo:  true,
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithError(orderEntity.ErrCannotPayOrder.Error()).WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockPayment.EXPECT().HandleGatewayEvent(gomock.Any(), body, "signature").Return(tt.payment, tt.eventErr)
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().UpdateOrderStatus(gomock.Any(), 1, paymentEntity.GatewayActorId, orderEntity.OrderStatusPaid, gomock.Any()).Return(tt.repoErr)
+			}
+
+			err := suite.usecase.ReceivePaymentEvent(context.Background(), body, "signature")
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestGetOrderPayments() {
+	payments := &[]paymentEntity.Payment{{Id: 5, OrderId: 1, UserId: 2, Status: paymentEntity.PaymentStatusFailed}}
+
+	suite.Run("Owner reads the payments", func() {
+		suite.SetupTest()
+
+		suite.mockRepo.EXPECT().GetOrder(gomock.Any(), 2, 1).Return(&orderEntity.Order{Id: 1, UserId: 2}, nil)
+		suite.mockPayment.EXPECT().GetOrderPayments(gomock.Any(), 1).Return(payments, nil)
+
+		got, err := suite.usecase.GetOrderPayments(requesterContext(2, 0), 1)
+
+		suite.NoError(err)
+		suite.Equal(payments, got)
+	})
+
+	suite.Run("Customer reads another user's payments", func() {
+		suite.SetupTest()
+
+		suite.mockRepo.EXPECT().GetOrder(gomock.Any(), 3, 1).Return(nil, core.ErrRecordNotFound)
+
+		_, err := suite.usecase.GetOrderPayments(requesterContext(3, 0), 1)
+
+		suite.ErrorIs(err, core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()).WithDebug(core.ErrRecordNotFound.Error()))
+	})
+}
+
+func (suite *OrderUsecaseTestSuite) TestReleaseExpiredReservations() {
+	tests := []struct {
+		name      string
+		batches   []int
+		repoErr   error
+		want      int
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Nothing to release",
+			batches:   []int{0},
+			want:      0,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Full batches are followed by another run",
+			batches:   []int{500, 500, 12},
+			want:      1012,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Repo return an error",
+			batches:   []int{500},
+			repoErr:   errors.New("this is an error"),
+			want:      500,
+			wantErr:   core.ErrInternalServerError.WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			calls := make([]any, 0, len(tt.batches)+1)
+			for _, released := range tt.batches {
+				calls = append(calls, suite.mockRepo.EXPECT().ReleaseExpiredReservations(gomock.Any(), gomock.Any(), 500).Return(released, nil))
+			}
+			if tt.repoErr != nil {
+				calls = append(calls, suite.mockRepo.EXPECT().ReleaseExpiredReservations(gomock.Any(), gomock.Any(), 500).Return(0, tt.repoErr))
+			}
+			gomock.InOrder(calls...)
+
+			released, err := suite.usecase.ReleaseExpiredReservations(context.Background())
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+			suite.Equal(tt.want, released, "every released reservation should be counted")
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestCancelExpiredOrders() {
+	tests := []struct {
+		name       string
+		orders     map[int]*orderEntity.Order
+		orderIds   []int
+		repoErr    error
+		cancelErr  map[int]error
+		releaseErr map[int]error
+		want       int
+		wantErr    error
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Nothing expired",
+			want:      0,
+			assertion: assert.NoError,
+		},
+		{
+			name:     "Pending orders are cancelled and refunded",
+			orderIds: []int{1, 2, 3, 4},
+			orders: map[int]*orderEntity.Order{
+				1: {Id: 1, Status: orderEntity.OrderStatusPending},
+				2: {Id: 2, Status: orderEntity.OrderStatusPaid},
+				3: {Id: 3, Status: orderEntity.OrderStatusPending},
+				4: {Id: 4, Status: orderEntity.OrderStatusPending},
+			},
+			cancelErr:  map[int]error{4: errors.New("this is an error")},
+			releaseErr: map[int]error{3: core.ErrInternalServerError.WithError(paymentEntity.ErrCannotReleasePayment.Error())},
+			want:       2,
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Repo return an error",
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().GetExpiredOrderIds(gomock.Any(), 100).Return(tt.orderIds, tt.repoErr)
+			for _, orderId := range tt.orderIds {
+				order := tt.orders[orderId]
+
+				suite.mockRepo.EXPECT().CancelOrder(gomock.Any(), orderId, orderEntity.ReservationSweeperActorId, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _, _ int, callbackFn func(order *orderEntity.Order) error) error {
+						if tt.cancelErr[orderId] != nil {
+							return tt.cancelErr[orderId]
+						}
+
+						return callbackFn(order)
+					})
+				// orders paid meanwhile or that failed to cancel are not refunded
+				if order.Status != orderEntity.OrderStatusPending || tt.cancelErr[orderId] != nil {
+					continue
+				}
+
+				suite.mockPayment.EXPECT().ReleaseOrderPayments(gomock.Any(), orderId).Return(tt.releaseErr[orderId])
+				if tt.releaseErr[orderId] == nil {
+					suite.mockRepo.EXPECT().MarkOrderRefunded(gomock.Any(), orderId, gomock.Any()).Return(nil)
+				}
+			}
+
+			cancelled, err := suite.usecase.CancelExpiredOrders(context.Background())
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+			suite.Equal(tt.want, cancelled, "every cancelled order should be counted")
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestRetryPendingRefunds() {
+	tests := []struct {
+		name       string
+		orderIds   []int
+		repoErr    error
+		releaseErr map[int]error
+		want       int
+		wantErr    error
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Nothing to refund",
+			want:      0,
+			assertion: assert.NoError,
+		},
+		{
+			name:       "Order failing again is left for the next sweep",
+			orderIds:   []int{1, 2, 3},
+			releaseErr: map[int]error{2: core.ErrInternalServerError.WithError(paymentEntity.ErrCannotReleasePayment.Error())},
+			want:       2,
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Repo return an error",
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().GetUnrefundedOrderIds(gomock.Any(), gomock.Any(), 100).DoAndReturn(
+				func(_ context.Context, cancelledBefore time.Time, _ int) ([]int, error) {
+					suite.WithinDuration(time.Now().Add(-time.Minute), cancelledBefore, time.Second, "fresh cancellations should be left to their request")
+
+					return tt.orderIds, tt.repoErr
+				})
+			for _, orderId := range tt.orderIds {
+				suite.mockPayment.EXPECT().ReleaseOrderPayments(gomock.Any(), orderId).Return(tt.releaseErr[orderId])
+				if tt.releaseErr[orderId] == nil {
+					suite.mockRepo.EXPECT().MarkOrderRefunded(gomock.Any(), orderId, gomock.Any()).Return(nil)
+				}
+			}
+
+			refunded, err := suite.usecase.RetryPendingRefunds(context.Background())
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+			suite.Equal(tt.want, refunded, "every refunded order should be counted")
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestUpdateOrderStatusCallback() {
+	tests := []struct {
+		name      string
+		order     *orderEntity.Order
+		status    orderEntity.OrderStatus
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Valid transition",
+			order:     &orderEntity.Order{Id: 1, Status: orderEntity.OrderStatusPaid},
+			status:    orderEntity.OrderStatusFulfilling,
+			wantErr:   nil,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Invalid transition",
+			order:     &orderEntity.Order{Id: 1, Status: orderEntity.OrderStatusDelivered},
+			status:    orderEntity.OrderStatusPaid,
+			wantErr:   orderEntity.ErrInvalidTransition,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Nil order",
+			order:     nil,
+			status:    orderEntity.OrderStatusPaid,
+			wantErr:   orderEntity.ErrInvalidMemory,
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := suite.usecase.UpdateOrderStatusCallback(tt.order, tt.status)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestGetOrderStatusHistories() {
+	pending := orderEntity.OrderStatusPending
+	histories := &[]orderEntity.OrderStatusHistory{
+		{
+			Id:       1,
+			OrderId:  1,
+			ToStatus: orderEntity.OrderStatusPending,
+			ActorId:  2,
+		},
+		{
+			Id:         2,
+			OrderId:    1,
+			FromStatus: &pending,
+			ToStatus:   orderEntity.OrderStatusPaid,
+			ActorId:    1,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		checkOwner  bool
+		ownerErr    error
+		callHistory bool
+		repoErr     error
+		want        *[]orderEntity.OrderStatusHistory
+		wantErr     error
+		assertion   assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "Admin gets any order's histories",
+			ctx:         requesterContext(1, 1),
+			callHistory: true,
+			want:        histories,
+			assertion:   assert.NoError,
+		},
+		{
+			name:        "Owner gets own order's histories",
+			ctx:         requesterContext(2, 0),
+			checkOwner:  true,
+			callHistory: true,
+			want:        histories,
+			assertion:   assert.NoError,
+		},
+		{
+			name:       "Customer cannot see other's order",
+			ctx:        requesterContext(3, 0),
+			checkOwner: true,
+			ownerErr:   core.ErrRecordNotFound,
+			want:       nil,
+			wantErr:    core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()).WithDebug(core.ErrRecordNotFound.Error()),
+			assertion:  assert.Error,
+		},
+		{
+			name:        "Repo return an error",
+			ctx:         requesterContext(1, 1),
+			callHistory: true,
+			repoErr:     errors.New("this is an error"),
+			want:        nil,
+			wantErr:     core.ErrInternalServerError.WithDebug(errors.New("this is an error").Error()),
+			assertion:   assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.checkOwner {
+				suite.mockRepo.EXPECT().GetOrder(gomock.Any(), gomock.Any(), 1).Return(&orderEntity.Order{Id: 1}, tt.ownerErr)
+			}
+			if tt.callHistory {
+				suite.mockRepo.EXPECT().GetOrderStatusHistories(gomock.Any(), 1).Return(tt.want, tt.repoErr)
+			}
+
+			got, err := suite.usecase.GetOrderStatusHistories(tt.ctx, 1)
+
+			suite.Equal(tt.want, got, "histories should be retrieved correctly")
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestCancelOrder() {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		order      *orderEntity.Order
+		repoErr    error
+		releaseErr error
+		wantErr    error
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Owner cancels a pending order",
+			ctx:       requesterContext(2, 0),
+			order:     &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusPending, CreatedAt: time.Now()},
+			wantErr:   nil,
+			assertion: assert.NoError,
+		},
+		{
+			name:       "Payments cannot be given back yet",
+			ctx:        requesterContext(2, 0),
+			order:      &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusPaid, CreatedAt: time.Now()},
+			releaseErr: core.ErrInternalServerError.WithError(paymentEntity.ErrCannotReleasePayment.Error()),
+			wantErr:    nil,
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Customer cancels another user's order",
+			ctx:       requesterContext(3, 0),
+			order:     &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusPending, CreatedAt: time.Now()},
+			wantErr:   core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Order has already been cancelled",
+			ctx:       requesterContext(2, 0),
+			order:     &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusCancelled, CreatedAt: time.Now()},
+			wantErr:   core.ErrConfict.WithError(orderEntity.ErrOrderAlreadyCancelled.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Order does not exist",
+			ctx:       requesterContext(1, 1),
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo return an error",
+			ctx:       requesterContext(1, 1),
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithError(orderEntity.ErrCannotCancelOrder.Error()).WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().CancelOrder(gomock.Any(), 1, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _, _ int, callbackFn func(order *orderEntity.Order) error) error {
+					if tt.repoErr != nil {
+						return tt.repoErr
+					}
+
+					return callbackFn(tt.order)
+				})
+			if tt.wantErr == nil {
+				suite.mockPayment.EXPECT().ReleaseOrderPayments(gomock.Any(), 1).Return(tt.releaseErr)
+			}
+			// a refund that failed is left unmarked for the sweeper to retry
+			if tt.wantErr == nil && tt.releaseErr == nil {
+				suite.mockRepo.EXPECT().MarkOrderRefunded(gomock.Any(), 1, gomock.Any()).Return(nil)
+			}
+
+			err := suite.usecase.CancelOrder(tt.ctx, 1)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestCancelOrderCallback() {
+	tests := []struct {
+		name        string
+		order       *orderEntity.Order
+		requesterId int
+		isAdmin     bool
+		wantErr     error
+		assertion   assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "Owner within cancellation window",
+			order:       &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusPaid, CreatedAt: time.Now().Add(-time.Minute)},
+			requesterId: 2,
+			wantErr:     nil,
+			assertion:   assert.NoError,
+		},
+		{
+			name:        "Owner after cancellation window",
+			order:       &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusPending, CreatedAt: time.Now().Add(-2 * time.Hour)},
+			requesterId: 2,
+			wantErr:     orderEntity.ErrCancelWindowExpired,
+			assertion:   assert.Error,
+		},
+		{
+			name:        "Admin after cancellation window",
+			order:       &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusFulfilling, CreatedAt: time.Now().Add(-48 * time.Hour)},
+			requesterId: 1,
+			isAdmin:     true,
+			wantErr:     nil,
+			assertion:   assert.NoError,
+		},
+		{
+			name:        "Not the owner",
+			order:       &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusPending, CreatedAt: time.Now()},
+			requesterId: 3,
+			wantErr:     orderEntity.ErrOrderNotFound,
+			assertion:   assert.Error,
+		},
+		{
+			name:        "Already cancelled",
+			order:       &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusCancelled, CreatedAt: time.Now()},
+			requesterId: 1,
+			isAdmin:     true,
+			wantErr:     orderEntity.ErrOrderAlreadyCancelled,
+			assertion:   assert.Error,
+		},
+		{
+			name:        "Shipped order",
+			order:       &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusShipped, CreatedAt: time.Now()},
+			requesterId: 1,
+			isAdmin:     true,
+			wantErr:     orderEntity.ErrInvalidTransition,
+			assertion:   assert.Error,
+		},
+		{
+			name:        "Nil order",
+			order:       nil,
+			requesterId: 1,
+			isAdmin:     true,
+			wantErr:     orderEntity.ErrInvalidMemory,
+			assertion:   assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			err := suite.usecase.CancelOrderCallback(tt.order, tt.requesterId, tt.isAdmin)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+		})
+	}
+}
+
+func (suite *OrderUsecaseTestSuite) TestUpdateOrderStatusToCancelled() {
+	suite.mockRepo.EXPECT().CancelOrder(gomock.Any(), 1, 1, gomock.Any()).Return(nil)
+	suite.mockPayment.EXPECT().ReleaseOrderPayments(gomock.Any(), 1).Return(nil)
+	suite.mockRepo.EXPECT().MarkOrderRefunded(gomock.Any(), 1, gomock.Any()).Return(nil)
+
+	err := suite.usecase.UpdateOrderStatus(requesterContext(1, 1), 1, orderEntity.OrderStatusCancelled)
+
+	suite.NoError(err, "cancelling through status update should go through the cancel flow")
+}
+
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
+
 func TestOrderUsecaseTestSuite(t *testing.T) {
 	suite.Run(t, new(OrderUsecaseTestSuite))
 }
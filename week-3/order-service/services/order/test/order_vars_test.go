@@ -1,6 +1,7 @@
 package test
 
 import (
+	addressEntity "order_service/services/address/entity"
 	"order_service/services/order/entity"
 	"testing"
 
@@ -53,6 +54,92 @@ func (suite *OrderVarsTestSuite) TestValidate() {
 			want:      entity.ErrMissingField,
 			assertion: assert.Error,
 		},
+		{
+			name: "Zero quantity order item",
+			order: entity.OrderRequest{
+				Items: []entity.ProductItem{
+					{
+						ProductId: 1,
+						Quantity:  0,
+					},
+				},
+			},
+			want:      entity.ErrMissingField,
+			assertion: assert.Error,
+		},
+		{
+			name: "Negative quantity order item",
+			order: entity.OrderRequest{
+				Items: []entity.ProductItem{
+					{
+						ProductId: 1,
+						Quantity:  -2,
+					},
+				},
+			},
+			want:      entity.ErrInvalidQuantity,
+			assertion: assert.Error,
+		},
+		{
+			name: "Duplicated order item",
+			order: entity.OrderRequest{
+				Items: []entity.ProductItem{
+					{
+						ProductId: 1,
+						Quantity:  1,
+					},
+					{
+						ProductId: 1,
+						Quantity:  2,
+					},
+				},
+			},
+			want:      entity.ErrDuplicateItem,
+			assertion: assert.Error,
+		},
+		{
+			name: "Duplicated coupon code",
+			order: entity.OrderRequest{
+				Items: []entity.ProductItem{
+					{
+						ProductId: 1,
+						Quantity:  1,
+					},
+				},
+				Coupons: []string{"summer", " SUMMER "},
+			},
+			want:      entity.ErrDuplicateCoupon,
+			assertion: assert.Error,
+		},
+		{
+			name: "Saved and given shipping address",
+			order: entity.OrderRequest{
+				Items: []entity.ProductItem{
+					{
+						ProductId: 1,
+						Quantity:  1,
+					},
+				},
+				AddressId:       1,
+				ShippingAddress: &addressEntity.AddressRequest{Recipient: "Jane Doe", Line1: "1 Market St", City: "San Francisco", Country: "US"},
+			},
+			want:      entity.ErrAmbiguousAddress,
+			assertion: assert.Error,
+		},
+		{
+			name: "Invalid shipping address",
+			order: entity.OrderRequest{
+				Items: []entity.ProductItem{
+					{
+						ProductId: 1,
+						Quantity:  1,
+					},
+				},
+				ShippingAddress: &addressEntity.AddressRequest{Recipient: "Jane Doe", Line1: "1 Market St", City: "San Francisco", Country: "USA"},
+			},
+			want:      addressEntity.ErrInvalidCountry,
+			assertion: assert.Error,
+		},
 	}
 
 	for _, tt := range tests {
@@ -97,6 +184,31 @@ func (suite *OrderVarsTestSuite) TestGetItems() {
 	}
 }
 
+func (suite *OrderVarsTestSuite) TestGetShippingAddress() {
+	tests := []struct {
+		name  string
+		order entity.OrderRequest
+		want  *entity.OrderAddress
+	}{
+		{
+			name:  "Given address is copied",
+			order: entity.OrderRequest{ShippingAddress: &addressEntity.AddressRequest{Label: "Home", Recipient: " Jane Doe ", Line1: "1 Market St", City: "San Francisco", Country: "us"}},
+			want:  &entity.OrderAddress{Recipient: "Jane Doe", Line1: "1 Market St", City: "San Francisco", Country: "US"},
+		},
+		{
+			name:  "No address given",
+			order: entity.OrderRequest{AddressId: 1},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.Equal(tt.want, tt.order.GetShippingAddress(), "shipping address should be copied correctly")
+		})
+	}
+}
+
 func (suite *OrderVarsTestSuite) TestGetItemId() {
 	tests := []struct {
 		name string
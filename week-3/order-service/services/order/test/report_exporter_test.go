@@ -0,0 +1,199 @@
+package test
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/order/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/xuri/excelize/v2"
+)
+
+type ReportExporterTestSuite struct {
+	suite.Suite
+	report *pkg.SummarizeReport
+}
+
+func (suite *ReportExporterTestSuite) SetupTest() {
+	rate, err := core.ParseRate("0.11")
+	suite.Require().NoError(err)
+
+	tax := entity.NewOrderTax(0, "VAT", rate, false)
+	tax.Add(core.NewMoney(10000), core.NewMoney(1100), core.NewMoney(10000), core.NewMoney(1100))
+
+	datas := []entity.OrdersSummarize{
+		{UserId: 1, Username: "alice", NumOfOrders: 2, SumOrderPrice: core.NewMoney(10000), SumTax: core.NewMoney(1100), AverageOrderItemQuantity: 1.5},
+		{UserId: 2, Username: "bob, jr", NumOfOrders: 1, SumOrderPrice: core.NewMoney(250), SumTax: core.NewMoney(0), AverageOrderItemQuantity: 3},
+	}
+	taxes := []entity.OrderTax{tax}
+
+	start := time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC)
+	suite.report = pkg.NewSummarizeReport(&datas, &taxes, start, end)
+}
+
+func (suite *ReportExporterTestSuite) export(format pkg.ReportFormat) []byte {
+	exporter, err := pkg.NewReportExporter(format)
+	suite.Require().NoError(err)
+	suite.Equal(format, exporter.Format(), "exporter should be for the format")
+
+	var buf bytes.Buffer
+	suite.Require().NoError(exporter.Export(&buf, suite.report))
+
+	return buf.Bytes()
+}
+
+func (suite *ReportExporterTestSuite) TestNewSummarizeReport() {
+	suite.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), suite.report.StartDate, "start date should be a day")
+	suite.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), suite.report.EndDate, "end date should be a day")
+
+	tables := suite.report.Tables()
+	suite.Len(tables, 2)
+	suite.Equal([]string{"user_id", "username", "num_of_orders", "sum_order_price", "sum_tax", "average_order_item_quantity"}, tables[0].Keys)
+	suite.Equal([]string{"name", "rate", "inclusive", "taxable_amount", "amount"}, tables[1].Keys)
+}
+
+func (suite *ReportExporterTestSuite) TestCSV() {
+	reader := csv.NewReader(bytes.NewReader(suite.export(pkg.ReportFormatCSV)))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	suite.Require().NoError(err)
+
+	// the empty record between the tables is skipped by the reader
+	suite.Equal([][]string{
+		{"User ID", "Username", "Num of Orders", "Sum Order Price", "Tax", "Average Order Quantity"},
+		{"1", "alice", "2", "100.00", "11.00", "1.5"},
+		{"2", "bob, jr", "1", "2.50", "0.00", "3"},
+		{"Tax", "Rate", "Inclusive", "Taxable Amount", "Tax Amount"},
+		{"VAT", "0.11000000", "false", "100.00", "11.00"},
+	}, records)
+}
+
+func (suite *ReportExporterTestSuite) TestNDJSON() {
+	scanner := bufio.NewScanner(bytes.NewReader(suite.export(pkg.ReportFormatNDJSON)))
+
+	var lines []map[string]any
+	var keys [][]string
+	for scanner.Scan() {
+		var line map[string]any
+		suite.Require().NoError(json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+
+		// keys are written in column order
+		var lineKeys []string
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.Token()
+		for decoder.More() {
+			token, err := decoder.Token()
+			suite.Require().NoError(err)
+			lineKeys = append(lineKeys, token.(string))
+			var value any
+			suite.Require().NoError(decoder.Decode(&value))
+		}
+		keys = append(keys, lineKeys)
+	}
+	suite.Require().NoError(scanner.Err())
+
+	suite.Require().Len(lines, 3)
+	suite.Equal(map[string]any{"table": "summarize", "user_id": float64(1), "username": "alice", "num_of_orders": float64(2), "sum_order_price": float64(100), "sum_tax": float64(11), "average_order_item_quantity": 1.5}, lines[0])
+	suite.Equal("bob, jr", lines[1]["username"])
+	suite.Equal(map[string]any{"table": "taxes", "name": "VAT", "rate": 0.11, "inclusive": false, "taxable_amount": float64(100), "amount": float64(11)}, lines[2])
+	suite.Equal([]string{"table", "user_id", "username", "num_of_orders", "sum_order_price", "sum_tax", "average_order_item_quantity"}, keys[0])
+}
+
+func (suite *ReportExporterTestSuite) TestODS() {
+	content := suite.export(pkg.ReportFormatODS)
+
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	suite.Require().NoError(err)
+	suite.Require().NotEmpty(archive.File)
+	suite.Equal("mimetype", archive.File[0].Name, "mimetype should be the first entry")
+	suite.Equal(zip.Store, archive.File[0].Method, "mimetype should not be compressed")
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		suite.Require().NoError(err)
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		suite.Require().NoError(err)
+		files[file.Name] = string(data)
+	}
+
+	suite.Equal(pkg.ReportFormatODS.ContentType(), files["mimetype"])
+	suite.Contains(files, "META-INF/manifest.xml")
+	suite.Contains(files["content.xml"], `<table:table table:name="summarize">`)
+	suite.Contains(files["content.xml"], `<table:table table:name="taxes">`)
+	suite.Contains(files["content.xml"], `<table:table-cell office:value-type="float" office:value="100.00"><text:p>100.00</text:p></table:table-cell>`)
+	suite.Contains(files["content.xml"], `<table:table-cell office:value-type="string"><text:p>bob, jr</text:p></table:table-cell>`)
+	suite.Contains(files["content.xml"], `<table:table-cell office:value-type="boolean" office:boolean-value="false"><text:p>false</text:p></table:table-cell>`)
+}
+
+func (suite *ReportExporterTestSuite) TestXLSX() {
+	f, err := excelize.OpenReader(bytes.NewReader(suite.export(pkg.ReportFormatXLSX)))
+	suite.Require().NoError(err)
+	defer f.Close()
+
+	suite.Equal([]string{"summarize", "taxes"}, f.GetSheetList())
+
+	rows, err := f.GetRows("summarize")
+	suite.Require().NoError(err)
+	suite.Require().Len(rows, 6)
+	suite.Equal([]string{"User ID", "Username", "Num of Orders", "Sum Order Price", "Tax", "Average Order Quantity"}, rows[3])
+	suite.Equal([]string{"1", "alice", "2", "100", "11", "1.5"}, rows[4])
+
+	price, err := f.GetCellType("summarize", "D5")
+	suite.Require().NoError(err)
+	suite.NotEqual(excelize.CellTypeSharedString, price, "amounts should be numbers")
+
+	rows, err = f.GetRows("taxes")
+	suite.Require().NoError(err)
+	suite.Equal([][]string{
+		{"Tax", "Rate", "Inclusive", "Taxable Amount", "Tax Amount"},
+		{"VAT", "0.11", "FALSE", "100", "11"},
+	}, rows)
+}
+
+func (suite *ReportExporterTestSuite) TestReportFormatOf() {
+	tests := []struct {
+		name        string
+		contentType string
+		want        pkg.ReportFormat
+		wantErr     error
+		assertion   assert.ErrorAssertionFunc
+	}{
+		{name: "Excel", contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", want: pkg.ReportFormatXLSX, assertion: assert.NoError},
+		{name: "CSV", contentType: "text/csv", want: pkg.ReportFormatCSV, assertion: assert.NoError},
+		{name: "JSON Lines", contentType: "application/x-ndjson", want: pkg.ReportFormatNDJSON, assertion: assert.NoError},
+		{name: "OpenDocument", contentType: "application/vnd.oasis.opendocument.spreadsheet", want: pkg.ReportFormatODS, assertion: assert.NoError},
+		{name: "Nothing acceptable", contentType: "", wantErr: pkg.ErrUnsupportedReportFormat, assertion: assert.Error},
+		{name: "Unknown", contentType: "application/pdf", wantErr: pkg.ErrUnsupportedReportFormat, assertion: assert.Error},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			got, err := pkg.ReportFormatOf(tt.contentType)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(tt.want, got, "format should be return correctly")
+			}
+		})
+	}
+}
+
+func TestReportExporterTestSuite(t *testing.T) {
+	suite.Run(t, new(ReportExporterTestSuite))
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	addressEntity "order_service/services/address/entity"
+)
+
+// AddressResolver picks the saved address an order is shipped to, nil when there is none.
+// It is implemented by the address usecase.
+type AddressResolver interface {
+	ResolveAddress(ctx context.Context, userId, addressId int) (*addressEntity.Address, error)
+}
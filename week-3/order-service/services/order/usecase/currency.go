@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	"order_service/internal/core"
+)
+
+// CurrencyResolver picks the currency an order is charged in and the rate to convert base prices with.
+// It is implemented by the currency usecase.
+type CurrencyResolver interface {
+	ResolveCurrency(ctx context.Context, userId int, currency string) (string, core.Rate, error)
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"context"
+	paymentEntity "order_service/services/payment/entity"
+)
+
+// PaymentProcessor takes the payments of orders through their providers.
+// It is implemented by the payment usecase.
+type PaymentProcessor interface {
+	Pay(ctx context.Context, orderId, userId int, data *paymentEntity.PaymentRequest) (*paymentEntity.Payment, error)
+	GetOrderPayments(ctx context.Context, orderId int) (*[]paymentEntity.Payment, error)
+	HandleGatewayEvent(ctx context.Context, body []byte, signature string) (*paymentEntity.Payment, error)
+	ReleaseOrderPayments(ctx context.Context, orderId int) error
+}
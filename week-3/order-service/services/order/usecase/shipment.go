@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	shipmentEntity "order_service/services/shipment/entity"
+)
+
+// ShipmentResolver reads the shipments of an order with their tracking history.
+// It is implemented by the shipment usecase.
+type ShipmentResolver interface {
+	GetOrderShipments(ctx context.Context, orderId int) ([]shipmentEntity.Shipment, error)
+}
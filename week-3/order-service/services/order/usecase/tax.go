@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	taxEntity "order_service/services/tax/entity"
+)
+
+// TaxResolver picks the region an order is taxed in and the tax rules of that region.
+// It is implemented by the tax usecase.
+type TaxResolver interface {
+	ResolveRules(ctx context.Context, region string) (string, taxEntity.TaxRules, error)
+}
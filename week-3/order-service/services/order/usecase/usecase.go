@@ -2,31 +2,76 @@ package usecase
 
 import (
 	"context"
+	"log"
 	"order_service/internal/core"
+	couponEntity "order_service/services/coupon/entity"
 	orderEntity "order_service/services/order/entity"
 	orderRepo "order_service/services/order/repository/postgres"
+	paymentEntity "order_service/services/payment/entity"
 	productEntity "order_service/services/product/entity"
+	taxEntity "order_service/services/tax/entity"
 	userEntity "order_service/services/user/entity"
 	"time"
 )
 
 type OrderUsecase interface {
 	CreateOrder(ctx context.Context, data *orderEntity.Order) error
-	CreateOrderCallback(order *orderEntity.Order, user *userEntity.User, products *[]productEntity.Product) (bool, error)
-	GetOrders(ctx context.Context) (*[]orderEntity.Order, error)
+	CreateOrderCallback(order *orderEntity.Order, user *userEntity.User, products *[]productEntity.Product, coupons *[]couponEntity.Coupon, taxRules taxEntity.TaxRules) (bool, error)
+	GetOrders(ctx context.Context, filter *orderEntity.OrderFilter) (*[]orderEntity.Order, string, error)
 	GetTopFiveOrdersByPrice(ctx context.Context) (*[]orderEntity.Order, error)
 	GetNumOfOrdersByMonth(ctx context.Context, userId int) (*[]orderEntity.AggregatedOrdersByMonth, error)
 	GetOrdersSummarize(ctx context.Context, startDate, endDate time.Time) (*[]orderEntity.OrdersSummarize, error)
+	GetTaxesSummarize(ctx context.Context, startDate, endDate time.Time) (*[]orderEntity.OrderTax, error)
 	GetOrder(ctx context.Context, userId, orderId int) (*orderEntity.Order, error)
+	UpdateOrderStatus(ctx context.Context, orderId int, status orderEntity.OrderStatus) error
+	UpdateOrderStatusCallback(order *orderEntity.Order, status orderEntity.OrderStatus) error
+	GetOrderStatusHistories(ctx context.Context, orderId int) (*[]orderEntity.OrderStatusHistory, error)
+	CancelOrder(ctx context.Context, orderId int) error
+	CancelOrderCallback(order *orderEntity.Order, requesterId int, isAdmin bool) error
+	PayOrder(ctx context.Context, orderId int, data *paymentEntity.PaymentRequest) (*paymentEntity.Payment, error)
+	GetOrderPayments(ctx context.Context, orderId int) (*[]paymentEntity.Payment, error)
+	ReceivePaymentEvent(ctx context.Context, body []byte, signature string) error
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+	CancelExpiredOrders(ctx context.Context) (int, error)
+	CancelExpiredOrderCallback(order *orderEntity.Order) error
+	RetryPendingRefunds(ctx context.Context) (int, error)
+	RunReservationSweeper(ctx context.Context, interval time.Duration)
 }
 
+const (
+	// reservationSweepBatch is how many expired reservations are released in one transaction.
+	reservationSweepBatch = 500
+	// expiredOrderBatch is how many orders whose reservation expired are cancelled in one sweep.
+	expiredOrderBatch = 100
+	// refundRetryBatch is how many cancelled orders get their refund retried in one sweep.
+	refundRetryBatch = 100
+	// refundRetryDelay leaves the refund of a fresh cancellation to its request before the sweeper retries it.
+	refundRetryDelay = time.Minute
+)
+
 type orderUsecase struct {
-	repo orderRepo.OrderRepository
+	repo           orderRepo.OrderRepository
+	currency       CurrencyResolver
+	tax            TaxResolver
+	address        AddressResolver
+	shipment       ShipmentResolver
+	payment        PaymentProcessor
+	cancelWindow   time.Duration
+	reservationTTL time.Duration
 }
 
-func NewUsecase(repo orderRepo.OrderRepository) OrderUsecase {
+// NewUsecase builds the order usecase. The stock of a new order is held for reservationTTL, the order has
+// to be paid within that time or its stock goes back on sale.
+func NewUsecase(repo orderRepo.OrderRepository, currency CurrencyResolver, tax TaxResolver, address AddressResolver, shipment ShipmentResolver, payment PaymentProcessor, cancelWindow, reservationTTL time.Duration) OrderUsecase {
 	return &orderUsecase{
 		repo,
+		currency,
+		tax,
+		address,
+		shipment,
+		payment,
+		cancelWindow,
+		reservationTTL,
 	}
 }
 
@@ -45,24 +90,70 @@ func (uc *orderUsecase) CreateOrder(ctx context.Context, data *orderEntity.Order
 	requesterId := uid.GetLocalID()
 	data.SetUserId(int(requesterId))
 
-	err = uc.repo.CreateOrder(ctx, data, uc.CreateOrderCallback)
+	// the rate is taken once here and stored on the order, later rate changes do not affect it
+	currency, rate, err := uc.currency.ResolveCurrency(ctx, int(requesterId), data.GetCurrencySafe())
+	if err != nil {
+		return err
+	}
+	data.SetCurrency(currency)
+	data.SetExchangeRate(rate)
+
+	region, taxRules, err := uc.tax.ResolveRules(ctx, data.GetRegionSafe())
+	if err != nil {
+		return err
+	}
+	data.SetRegion(region)
+	data.SetReservedUntil(time.Now().Add(uc.reservationTTL))
+
+	// an address given with the order is taken as is, otherwise the saved one it names or the default one
+	if data.GetShippingAddressSafe() == nil {
+		address, err := uc.address.ResolveAddress(ctx, int(requesterId), data.GetAddressIdSafe())
+		if err != nil {
+			return err
+		}
+		data.SetShippingAddress(orderEntity.NewOrderAddress(address))
+	}
+
+	err = uc.repo.CreateOrder(ctx, data, func(order *orderEntity.Order, user *userEntity.User, products *[]productEntity.Product, coupons *[]couponEntity.Coupon) (bool, error) {
+		return uc.CreateOrderCallback(order, user, products, coupons, taxRules)
+	})
 	if err != nil {
 		if err == orderEntity.ErrOutOfStock {
 			return core.ErrConfict.WithError(orderEntity.ErrOutOfStock.Error())
 		}
-		if err == orderEntity.ErrInsufficientBalance {
-			return core.ErrConfict.WithError(orderEntity.ErrInsufficientBalance.Error())
+		if err == orderEntity.ErrProductNotFound {
+			return core.ErrNotFound.WithError(orderEntity.ErrProductNotFound.Error())
+		}
+		if err == couponEntity.ErrCouponNotFound {
+			return core.ErrNotFound.WithError(couponEntity.ErrCouponNotFound.Error())
+		}
+		if isCouponRejection(err) {
+			return core.ErrUnprocessableEntity.WithError(err.Error())
 		}
 
 		return core.ErrInternalServerError.WithError(orderEntity.ErrCannotCreateOrder.Error()).WithDebug(err.Error())
 	}
 
+	// the order is already committed, failing here would make the client place it a second time. It stays
+	// pending and can be paid again, the attempt comes back failed with the reason.
+	payment, err := uc.pay(ctx, data.GetIdSafe(), int(requesterId), data.GetPaymentRequestSafe())
+	if err != nil {
+		failed := paymentEntity.NewPayment(data.GetIdSafe(), int(requesterId), data.GetPaymentRequestSafe().GetMethod(), time.Now())
+		failed.Apply(paymentEntity.ProviderResult{Status: paymentEntity.PaymentStatusFailed, FailureReason: err.Error()}, time.Now())
+		payment = &failed
+	}
+
+	data.SetPayment(payment)
+	if payment.GetStatusSafe() == paymentEntity.PaymentStatusCaptured {
+		data.SetStatus(orderEntity.OrderStatusPaid)
+	}
+
 	return nil
 }
 
-func (uc *orderUsecase) CreateOrderCallback(order *orderEntity.Order, user *userEntity.User, products *[]productEntity.Product) (bool, error) {
+func (uc *orderUsecase) CreateOrderCallback(order *orderEntity.Order, user *userEntity.User, products *[]productEntity.Product, coupons *[]couponEntity.Coupon, taxRules taxEntity.TaxRules) (bool, error) {
 	// whether any arguments is nil pointer
-	if order == nil || user == nil || products == nil {
+	if order == nil || user == nil || products == nil || coupons == nil {
 		return false, orderEntity.ErrInvalidMemory
 	}
 
@@ -72,7 +163,19 @@ func (uc *orderUsecase) CreateOrderCallback(order *orderEntity.Order, user *user
 		return false, orderEntity.ErrNotEqual
 	}
 
-	totalPrice := float32(0)
+	// catalog prices and balances are in the base currency, the order is priced in the charged one
+	currency := order.GetCurrencySafe()
+	rate := order.GetExchangeRateSafe()
+	if !rate.IsPositive() {
+		rate = core.RateOne
+		order.SetExchangeRate(rate)
+	}
+
+	totalPrice := core.NewMoney(0)
+	baseTotalPrice := core.NewMoney(0)
+	lineAmounts := make([]core.Money, 0, len(orderItems))
+	baseLineAmounts := make([]core.Money, 0, len(orderItems))
+	couponLines := make([]couponEntity.CouponLine, 0, len(orderItems))
 
 	for idx, item := range orderItems {
 		product := &(*products)[idx]
@@ -82,49 +185,139 @@ func (uc *orderUsecase) CreateOrderCallback(order *orderEntity.Order, user *user
 			return false, orderEntity.ErrOutOfStock
 		}
 
-		totalPrice += product.GetPrice() * float32(item.GetQuantity())
+		// convert the unit price first so the invoice lines add up to the total
+		price := product.GetPrice().Convert(rate)
+
+		lineAmounts = append(lineAmounts, price.Mul(item.GetQuantity()))
+		baseLineAmounts = append(baseLineAmounts, product.GetPrice().Mul(item.GetQuantity()))
+		totalPrice = totalPrice.Add(lineAmounts[idx])
+		baseTotalPrice = baseTotalPrice.Add(baseLineAmounts[idx])
+		couponLines = append(couponLines, couponEntity.CouponLine{ProductId: product.GetId(), Quantity: item.GetQuantity(), UnitPrice: product.GetPrice()})
 
 		// update order's item
 		i := (*order).GetItemSafe(idx)
 
 		i.SetProductName(product.GetName())
-		i.SetProductPrice(product.GetPrice())
+		i.SetProductPrice(price)
+		i.SetCurrency(currency)
+		i.SetExchangeRate(rate)
+		i.SetTaxClass(taxEntity.NormalizeTaxClass(product.GetTaxClass()))
 		product.SetQuantity(i.GetQuantity())
 	}
 
-	if user.GetBalance() < totalPrice {
-		return false, orderEntity.ErrInsufficientBalance
+	// coupon rules work on base prices, each discount line is converted like the items are
+	now := time.Now()
+	baseSubtotal := baseTotalPrice
+	discountTotal := core.NewMoney(0)
+	baseDiscountTotal := core.NewMoney(0)
+	order.Discounts = nil
+
+	for _, coupon := range *coupons {
+		err := coupon.CheckEligible(now, baseSubtotal)
+		if err != nil {
+			return false, err
+		}
+
+		baseDiscount, err := coupon.Discount(couponLines)
+		if err != nil {
+			return false, err
+		}
+
+		// stacked coupons can never take the order below zero
+		if baseDiscount > baseTotalPrice {
+			baseDiscount = baseTotalPrice
+		}
+		discount := baseDiscount.Convert(rate)
+		if discount > totalPrice {
+			discount = totalPrice
+		}
+
+		totalPrice = totalPrice.Sub(discount)
+		baseTotalPrice = baseTotalPrice.Sub(baseDiscount)
+		discountTotal = discountTotal.Add(discount)
+		baseDiscountTotal = baseDiscountTotal.Add(baseDiscount)
+
+		order.AddDiscount(orderEntity.NewOrderDiscount(0, coupon.GetId(), coupon.GetCode(), coupon.Describe(), discount, baseDiscount))
+	}
+
+	// lines are taxed net of their share of the discounts, exclusive taxes come on top of the total
+	lineTaxables := taxableAmounts(lineAmounts, discountTotal)
+	baseLineTaxables := taxableAmounts(baseLineAmounts, baseDiscountTotal)
+	taxIndexes := make(map[int]int)
+	order.Taxes = nil
+
+	for idx := range orderItems {
+		item := order.GetItemSafe(idx)
+
+		rule, ok := taxRules.For(item.GetTaxClass())
+		if !ok {
+			continue
+		}
+
+		tax := rule.Compute(lineTaxables[idx])
+		baseTax := rule.Compute(baseLineTaxables[idx])
+		item.SetTax(tax, rule.GetRate(), rule.IsInclusive())
+
+		if !rule.IsInclusive() {
+			totalPrice = totalPrice.Add(tax)
+			baseTotalPrice = baseTotalPrice.Add(baseTax)
+		}
+
+		taxIdx, exists := taxIndexes[rule.GetId()]
+		if !exists {
+			taxIdx = len(order.Taxes)
+			taxIndexes[rule.GetId()] = taxIdx
+			order.AddTax(orderEntity.NewOrderTax(0, rule.GetName(), rule.GetRate(), rule.IsInclusive()))
+		}
+		order.Taxes[taxIdx].Add(lineTaxables[idx], tax, baseLineTaxables[idx], baseTax)
 	}
 
 	order.SetTotalPrice(totalPrice)
-	user.SetBalance(totalPrice)
+	order.SetBaseTotalPrice(baseTotalPrice)
 
 	return true, nil
 }
 
-func (uc *orderUsecase) GetOrders(ctx context.Context) (*[]orderEntity.Order, error) {
+// taxableAmounts takes from every line its share of the discount.
+func taxableAmounts(amounts []core.Money, discount core.Money) []core.Money {
+	shares := discount.Allocate(amounts)
+
+	taxable := make([]core.Money, 0, len(amounts))
+	for idx, amount := range amounts {
+		taxable = append(taxable, amount.Sub(shares[idx]))
+	}
+
+	return taxable
+}
+
+func (uc *orderUsecase) GetOrders(ctx context.Context, filter *orderEntity.OrderFilter) (*[]orderEntity.Order, string, error) {
 	requester := core.GetRequester(ctx)
 	uid, err := core.DecomposeUID(requester.GetSubject())
 	if err != nil {
-		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+		return nil, "", core.ErrInternalServerError.WithDebug(err.Error())
 	}
 
-	requesterId := uid.GetLocalID()
-	role := uid.GetRole()
+	// customers only ever see their own orders, admin may narrow the listing down to one user
+	if uid.GetRole() != 1 {
+		filter.UserId = int(uid.GetLocalID())
+	}
 
-	var orders *[]orderEntity.Order
+	// fetch one more order than asked for to know whether there is a next page
+	pageFilter := *filter
+	pageFilter.Limit = filter.Limit + 1
 
-	if role == 1 {
-		orders, err = uc.repo.GetOrders(ctx)
-	} else {
-		orders, err = uc.repo.GetOrdersByUserId(ctx, int(requesterId))
+	orders, err := uc.repo.GetOrders(ctx, &pageFilter)
+	if err != nil {
+		return nil, "", core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()).WithDebug(err.Error())
 	}
 
-	if err != nil {
-		return nil, core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()).WithDebug(err.Error())
+	nextCursor := ""
+	if len(*orders) > filter.Limit {
+		*orders = (*orders)[:filter.Limit]
+		nextCursor = orderEntity.NewOrderCursor(filter, (*orders)[filter.Limit-1]).Encode()
 	}
 
-	return orders, nil
+	return orders, nextCursor, nil
 }
 
 func (uc *orderUsecase) GetTopFiveOrdersByPrice(ctx context.Context) (*[]orderEntity.Order, error) {
@@ -154,11 +347,410 @@ func (uc *orderUsecase) GetOrdersSummarize(ctx context.Context, startDate, endDa
 	return datas, nil
 }
 
+func (uc *orderUsecase) GetTaxesSummarize(ctx context.Context, startDate, endDate time.Time) (*[]orderEntity.OrderTax, error) {
+	taxes, err := uc.repo.GetTaxesSummarize(ctx, startDate, endDate)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return taxes, nil
+}
+
 func (uc *orderUsecase) GetOrder(ctx context.Context, userId, orderId int) (*orderEntity.Order, error) {
 	order, err := uc.repo.GetOrder(ctx, userId, orderId)
 	if err != nil {
 		return nil, core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()).WithDebug(err.Error())
 	}
 
+	// the tracking history is shown next to the order
+	shipments, err := uc.shipment.GetOrderShipments(ctx, order.GetIdSafe())
+	if err != nil {
+		return nil, err
+	}
+	order.SetShipments(shipments)
+
 	return order, nil
 }
+
+func (uc *orderUsecase) UpdateOrderStatus(ctx context.Context, orderId int, status orderEntity.OrderStatus) error {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	role := uid.GetRole()
+	if role != 1 {
+		return core.ErrBadRequest.WithError(orderEntity.ErrCannotUpdateStatus.Error())
+	}
+
+	// cancelling has to give back stock and payments, so it goes through its own flow
+	if status == orderEntity.OrderStatusCancelled {
+		return uc.CancelOrder(ctx, orderId)
+	}
+
+	// nobody but the payment provider can say the money was taken
+	if status == orderEntity.OrderStatusPaid {
+		return core.ErrConfict.WithError(orderEntity.ErrPaidByPayment.Error())
+	}
+
+	err = uc.repo.UpdateOrderStatus(ctx, orderId, int(uid.GetLocalID()), status, uc.UpdateOrderStatusCallback)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error())
+		}
+		if err == orderEntity.ErrInvalidTransition {
+			return core.ErrConfict.WithError(orderEntity.ErrInvalidTransition.Error())
+		}
+		if err == orderEntity.ErrReservationExpired {
+			return core.ErrConfict.WithError(orderEntity.ErrReservationExpired.Error())
+		}
+
+		return core.ErrInternalServerError.WithError(orderEntity.ErrCannotUpdateStatus.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+func (uc *orderUsecase) UpdateOrderStatusCallback(order *orderEntity.Order, status orderEntity.OrderStatus) error {
+	if order == nil {
+		return orderEntity.ErrInvalidMemory
+	}
+
+	if !order.GetStatusSafe().CanTransitionTo(status) {
+		return orderEntity.ErrInvalidTransition
+	}
+
+	return nil
+}
+
+func (uc *orderUsecase) GetOrderStatusHistories(ctx context.Context, orderId int) (*[]orderEntity.OrderStatusHistory, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	// customers may only see the history of their own orders
+	if uid.GetRole() != 1 {
+		_, err := uc.repo.GetOrder(ctx, int(uid.GetLocalID()), orderId)
+		if err != nil {
+			return nil, core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()).WithDebug(err.Error())
+		}
+	}
+
+	histories, err := uc.repo.GetOrderStatusHistories(ctx, orderId)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return histories, nil
+}
+
+func (uc *orderUsecase) CancelOrder(ctx context.Context, orderId int) error {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	requesterId := int(uid.GetLocalID())
+	isAdmin := uid.GetRole() == 1
+
+	err = uc.repo.CancelOrder(ctx, orderId, requesterId, func(order *orderEntity.Order) error {
+		return uc.CancelOrderCallback(order, requesterId, isAdmin)
+	})
+	if err != nil {
+		switch err {
+		case core.ErrRecordNotFound, orderEntity.ErrOrderNotFound:
+			return core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error())
+		case orderEntity.ErrOrderAlreadyCancelled, orderEntity.ErrCancelWindowExpired, orderEntity.ErrInvalidTransition:
+			return core.ErrConfict.WithError(err.Error())
+		}
+
+		return core.ErrInternalServerError.WithError(orderEntity.ErrCannotCancelOrder.Error()).WithDebug(err.Error())
+	}
+
+	// the order is cancelled for good, a refund that fails here is retried by the sweeper until it goes through
+	err = uc.refund(ctx, orderId)
+	if err != nil {
+		log.Printf("refund cancelled order %d error: %v", orderId, err)
+	}
+
+	return nil
+}
+
+// refund gives the payments of a cancelled order back and marks the order refunded. Releasing payments that
+// are already given back does nothing, so a refund can be retried until it is marked.
+func (uc *orderUsecase) refund(ctx context.Context, orderId int) error {
+	err := uc.payment.ReleaseOrderPayments(ctx, orderId)
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.MarkOrderRefunded(ctx, orderId, time.Now())
+	if err != nil {
+		return core.ErrInternalServerError.WithError(orderEntity.ErrCannotCancelOrder.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+func (uc *orderUsecase) CancelOrderCallback(order *orderEntity.Order, requesterId int, isAdmin bool) error {
+	if order == nil {
+		return orderEntity.ErrInvalidMemory
+	}
+
+	// customers cannot see, nor cancel, orders of somebody else
+	if !isAdmin && order.GetUserIdSafe() != requesterId {
+		return orderEntity.ErrOrderNotFound
+	}
+
+	status := order.GetStatusSafe()
+	if status == orderEntity.OrderStatusCancelled {
+		return orderEntity.ErrOrderAlreadyCancelled
+	}
+	if !status.CanTransitionTo(orderEntity.OrderStatusCancelled) {
+		return orderEntity.ErrInvalidTransition
+	}
+
+	if !isAdmin && time.Since(order.CreatedAt) > uc.cancelWindow {
+		return orderEntity.ErrCancelWindowExpired
+	}
+
+	return nil
+}
+
+// PayOrder makes another attempt at paying a pending order of the requester, e.g. after a declined card.
+func (uc *orderUsecase) PayOrder(ctx context.Context, orderId int, data *paymentEntity.PaymentRequest) (*paymentEntity.Payment, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	if uid.GetRole() == 1 {
+		return nil, core.ErrBadRequest.WithError(orderEntity.ErrCannotPayOrder.Error())
+	}
+
+	return uc.pay(ctx, orderId, int(uid.GetLocalID()), data)
+}
+
+// pay charges the order and marks it paid when its payment is captured right away. An order whose stock
+// was released meanwhile cannot be paid, its payment is given back.
+func (uc *orderUsecase) pay(ctx context.Context, orderId, userId int, data *paymentEntity.PaymentRequest) (*paymentEntity.Payment, error) {
+	payment, err := uc.payment.Pay(ctx, orderId, userId, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.GetStatusSafe() != paymentEntity.PaymentStatusCaptured {
+		return payment, nil
+	}
+
+	err = uc.markPaid(ctx, orderId, userId)
+	if err != nil {
+		if err == orderEntity.ErrReservationExpired || err == orderEntity.ErrInvalidTransition {
+			return nil, core.ErrConfict.WithError(err.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(orderEntity.ErrCannotPayOrder.Error()).WithDebug(err.Error())
+	}
+
+	return payment, nil
+}
+
+// markPaid moves the order of a captured payment to paid. The payments of an order whose stock was released
+// meanwhile are given back.
+func (uc *orderUsecase) markPaid(ctx context.Context, orderId, actorId int) error {
+	err := uc.repo.UpdateOrderStatus(ctx, orderId, actorId, orderEntity.OrderStatusPaid, uc.UpdateOrderStatusCallback)
+	if err == orderEntity.ErrReservationExpired {
+		releaseErr := uc.payment.ReleaseOrderPayments(ctx, orderId)
+		if releaseErr != nil {
+			return releaseErr
+		}
+	}
+
+	return err
+}
+
+func (uc *orderUsecase) GetOrderPayments(ctx context.Context, orderId int) (*[]paymentEntity.Payment, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	// customers may only see the payments of their own orders
+	if uid.GetRole() != 1 {
+		_, err := uc.repo.GetOrder(ctx, int(uid.GetLocalID()), orderId)
+		if err != nil {
+			return nil, core.ErrNotFound.WithError(orderEntity.ErrOrderNotFound.Error()).WithDebug(err.Error())
+		}
+	}
+
+	return uc.payment.GetOrderPayments(ctx, orderId)
+}
+
+// ReceivePaymentEvent records a payment gateway's callback, the order is paid once its payment is captured.
+// A captured payment of an order that is no longer pending has already been handled, or was given back.
+func (uc *orderUsecase) ReceivePaymentEvent(ctx context.Context, body []byte, signature string) error {
+	payment, err := uc.payment.HandleGatewayEvent(ctx, body, signature)
+	if err != nil {
+		return err
+	}
+
+	if payment.GetStatusSafe() != paymentEntity.PaymentStatusCaptured {
+		return nil
+	}
+
+	err = uc.markPaid(ctx, payment.GetOrderIdSafe(), paymentEntity.GatewayActorId)
+	if err != nil {
+		if err == orderEntity.ErrReservationExpired || err == orderEntity.ErrInvalidTransition {
+			return nil
+		}
+
+		return core.ErrInternalServerError.WithError(orderEntity.ErrCannotPayOrder.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+func isCouponRejection(err error) bool {
+	switch err {
+	case couponEntity.ErrCouponInactive,
+		couponEntity.ErrCouponNotStarted,
+		couponEntity.ErrCouponExpired,
+		couponEntity.ErrCouponUsageExceeded,
+		couponEntity.ErrCouponUserLimitExceeded,
+		couponEntity.ErrCouponMinSpend,
+		couponEntity.ErrCouponNotApplicable:
+		return true
+	}
+
+	return false
+}
+
+// ReleaseExpiredReservations puts the stock of unpaid orders whose reservation ran out back on sale.
+// Paying for them fails from then on, CancelExpiredOrders cancels them.
+func (uc *orderUsecase) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		released, err := uc.repo.ReleaseExpiredReservations(ctx, time.Now(), reservationSweepBatch)
+		if err != nil {
+			return total, core.ErrInternalServerError.WithDebug(err.Error())
+		}
+
+		total += released
+		if released < reservationSweepBatch {
+			return total, nil
+		}
+	}
+}
+
+// CancelExpiredOrders cancels the pending orders whose stock was released, so they no longer count as sold
+// and their payments are given back. An order that cannot be cancelled now is left for the next sweep.
+func (uc *orderUsecase) CancelExpiredOrders(ctx context.Context) (int, error) {
+	orderIds, err := uc.repo.GetExpiredOrderIds(ctx, expiredOrderBatch)
+	if err != nil {
+		return 0, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	cancelled := 0
+	for _, orderId := range orderIds {
+		err := uc.repo.CancelOrder(ctx, orderId, orderEntity.ReservationSweeperActorId, uc.CancelExpiredOrderCallback)
+		if err != nil {
+			// paid or cancelled meanwhile
+			if err != orderEntity.ErrOrderAlreadyCancelled && err != orderEntity.ErrInvalidTransition {
+				log.Printf("cancel expired order %d error: %v", orderId, err)
+			}
+			continue
+		}
+		cancelled++
+
+		// a refund that fails here is retried like the one of any cancelled order
+		err = uc.refund(ctx, orderId)
+		if err != nil {
+			log.Printf("refund expired order %d error: %v", orderId, err)
+		}
+	}
+
+	return cancelled, nil
+}
+
+// CancelExpiredOrderCallback only lets the sweeper cancel an order that is still waiting for its payment.
+func (uc *orderUsecase) CancelExpiredOrderCallback(order *orderEntity.Order) error {
+	if order == nil {
+		return orderEntity.ErrInvalidMemory
+	}
+
+	switch order.GetStatusSafe() {
+	case orderEntity.OrderStatusPending:
+		return nil
+	case orderEntity.OrderStatusCancelled:
+		return orderEntity.ErrOrderAlreadyCancelled
+	}
+
+	return orderEntity.ErrInvalidTransition
+}
+
+// RetryPendingRefunds gives back the payments of cancelled orders whose refund failed, an order that fails
+// again is left for the next sweep.
+func (uc *orderUsecase) RetryPendingRefunds(ctx context.Context) (int, error) {
+	orderIds, err := uc.repo.GetUnrefundedOrderIds(ctx, time.Now().Add(-refundRetryDelay), refundRetryBatch)
+	if err != nil {
+		return 0, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	refunded := 0
+	for _, orderId := range orderIds {
+		err := uc.refund(ctx, orderId)
+		if err != nil {
+			log.Printf("retry refund of order %d error: %v", orderId, err)
+			continue
+		}
+
+		refunded++
+	}
+
+	return refunded, nil
+}
+
+// RunReservationSweeper releases expired reservations, cancels their orders and retries pending refunds every
+// interval until ctx is done.
+func (uc *orderUsecase) RunReservationSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := uc.ReleaseExpiredReservations(ctx)
+			if err != nil {
+				log.Println("release expired reservations error:", err)
+			}
+			if released > 0 {
+				log.Printf("released %d expired reservations", released)
+			}
+
+			cancelled, err := uc.CancelExpiredOrders(ctx)
+			if err != nil {
+				log.Println("cancel expired orders error:", err)
+			}
+			if cancelled > 0 {
+				log.Printf("cancelled %d expired orders", cancelled)
+			}
+
+			refunded, err := uc.RetryPendingRefunds(ctx)
+			if err != nil {
+				log.Println("retry pending refunds error:", err)
+			}
+			if refunded > 0 {
+				log.Printf("refunded %d cancelled orders", refunded)
+			}
+		}
+	}
+}
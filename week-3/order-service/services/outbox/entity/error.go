@@ -0,0 +1,9 @@
+package entity
+
+import "errors"
+
+var (
+	ErrCannotRelayEvents  = errors.New("outbox events cannot be relayed")
+	ErrUnexpectedResponse = errors.New("event receiver answered with an unexpected status")
+	ErrPublisherClosed    = errors.New("event publisher is closed")
+)
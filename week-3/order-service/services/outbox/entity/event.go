@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event is a domain event written to the outbox in the same transaction as the change it describes.
+// The relay hands it to a publisher until one delivery succeeds, so receivers may see it more than once
+// and should dedupe on Id.
+type Event struct {
+	CreatedAt     time.Time       `json:"created_at"`
+	AvailableAt   time.Time       `json:"-"`
+	PublishedAt   *time.Time      `json:"-"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	AggregateType string          `json:"aggregate_type"`
+	Type          string          `json:"type"`
+	LastError     string          `json:"-"`
+	Id            int64           `json:"id"`
+	AggregateId   int             `json:"aggregate_id"`
+	Attempts      int             `json:"attempts"`
+}
+
+func NewEvent(aggregateType string, aggregateId int, eventType string, payload any) (Event, error) {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		Type:          eventType,
+		Payload:       content,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+func (event Event) GetId() int64 {
+	return event.Id
+}
+
+func (event Event) GetAggregateType() string {
+	return event.AggregateType
+}
+
+func (event Event) GetAggregateId() int {
+	return event.AggregateId
+}
+
+func (event Event) GetType() string {
+	return event.Type
+}
+
+func (event Event) GetPayload() json.RawMessage {
+	return event.Payload
+}
+
+func (event Event) GetAttempts() int {
+	return event.Attempts
+}
+
+func (event Event) GetAvailableAt() time.Time {
+	return event.AvailableAt
+}
+
+func (event Event) GetCreatedAt() time.Time {
+	return event.CreatedAt
+}
+
+func (event Event) GetLastError() string {
+	return event.LastError
+}
+
+func (event Event) IsPublished() bool {
+	return event.PublishedAt != nil
+}
+
+func (event *Event) MarkPublished(now time.Time) {
+	if event != nil {
+		event.PublishedAt = &now
+	}
+}
+
+// MarkFailed counts a failed delivery and holds the event back for retryIn.
+func (event *Event) MarkFailed(now time.Time, err error, retryIn time.Duration) {
+	if event != nil {
+		event.Attempts++
+		event.LastError = err.Error()
+		event.AvailableAt = now.Add(retryIn)
+	}
+}
+
+// Backoff is how long to wait after the given number of failed deliveries, base doubles on every attempt
+// and never goes over ceiling.
+func Backoff(attempts int, base, ceiling time.Duration) time.Duration {
+	if attempts < 1 {
+		return base
+	}
+
+	backoff := base
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= ceiling {
+			return ceiling
+		}
+	}
+
+	return min(backoff, ceiling)
+}
@@ -0,0 +1,56 @@
+package channel
+
+import (
+	"context"
+	"order_service/services/outbox/entity"
+	"sync"
+)
+
+// ChannelPublisher hands events to subscribers in the same process. Publish waits for room in the buffer,
+// so an event only counts as delivered once it is queued.
+type ChannelPublisher interface {
+	Publish(ctx context.Context, event *entity.Event) error
+	Events() <-chan entity.Event
+	Close()
+}
+
+type channelPublisher struct {
+	events chan entity.Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewChannelPublisher(size int) ChannelPublisher {
+	return &channelPublisher{
+		events: make(chan entity.Event, size),
+		done:   make(chan struct{}),
+	}
+}
+
+func (publisher *channelPublisher) Publish(ctx context.Context, event *entity.Event) error {
+	select {
+	case <-publisher.done:
+		return entity.ErrPublisherClosed
+	default:
+	}
+
+	select {
+	case publisher.events <- *event:
+		return nil
+	case <-publisher.done:
+		return entity.ErrPublisherClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (publisher *channelPublisher) Events() <-chan entity.Event {
+	return publisher.events
+}
+
+// Close stops accepting events, events already queued can still be read.
+func (publisher *channelPublisher) Close() {
+	publisher.once.Do(func() {
+		close(publisher.done)
+	})
+}
@@ -0,0 +1,56 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"order_service/services/outbox/entity"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FilePublisher appends every event to a log file as one JSON line.
+type FilePublisher interface {
+	Publish(ctx context.Context, event *entity.Event) error
+}
+
+type filePublisher struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFilePublisher(path string) FilePublisher {
+	return &filePublisher{
+		path: path,
+	}
+}
+
+// Publish syncs the line to disk before it returns, an event is not marked published while it can still
+// be lost.
+func (publisher *filePublisher) Publish(ctx context.Context, event *entity.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	err = os.MkdirAll(filepath.Dir(publisher.path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(publisher.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
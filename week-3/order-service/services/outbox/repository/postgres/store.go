@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"order_service/pkg"
+	"order_service/services/outbox/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepository interface {
+	RelayEvents(ctx context.Context, now time.Time, limit int, callbackFn func(events *[]entity.Event) error) (int, error)
+}
+
+const (
+	QUERY_GET_PENDING_EVENTS_LOCK = "SELECT id, aggregate_type, aggregate_id, event_type, payload, attempts, COALESCE(last_error, ''), available_at, created_at FROM outbox WHERE published_at IS NULL AND available_at <= $1 ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED"
+	QUERY_MARK_EVENTS_PUBLISHED   = "UPDATE outbox SET published_at = $2 WHERE id = ANY($1)"
+	QUERY_MARK_EVENTS_FAILED      = "UPDATE outbox AS o SET attempts = v.attempts, last_error = v.last_error, available_at = v.available_at FROM unnest($1::bigint[], $2::int[], $3::text[], $4::timestamp[]) AS v(id, attempts, last_error, available_at) WHERE o.id = v.id"
+)
+
+type postgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepo(db *pgxpool.Pool) OutboxRepository {
+	return &postgresRepo{
+		db,
+	}
+}
+
+// RelayEvents locks at most limit events that are due, lets callbackFn publish them and saves how every
+// delivery went. Events locked by another relay are skipped, events the callback left untouched stay due.
+func (repo *postgresRepo) RelayEvents(ctx context.Context, now time.Time, limit int, callbackFn func(events *[]entity.Event) error) (int, error) {
+	published := 0
+
+	err := pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, QUERY_GET_PENDING_EVENTS_LOCK, now, limit)
+		if err != nil {
+			return err
+		}
+
+		events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Event, error) {
+			var event entity.Event
+
+			err := row.Scan(&event.Id, &event.AggregateType, &event.AggregateId, &event.Type, &event.Payload, &event.Attempts, &event.LastError, &event.AvailableAt, &event.CreatedAt)
+			if err != nil {
+				return entity.Event{}, err
+			}
+
+			return event, nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		attempts := make(map[int64]int, len(events))
+		for _, event := range events {
+			attempts[event.GetId()] = event.GetAttempts()
+		}
+
+		// run business logic
+		err = callbackFn(&events)
+		if err != nil {
+			return err
+		}
+
+		publishedIds := make([]int64, 0, len(events))
+		failedIds, failedAttempts := make([]int64, 0, len(events)), make([]int, 0, len(events))
+		failedErrors, failedAvailableAts := make([]string, 0, len(events)), make([]time.Time, 0, len(events))
+
+		for _, event := range events {
+			switch {
+			case event.IsPublished():
+				publishedIds = append(publishedIds, event.GetId())
+			case event.GetAttempts() != attempts[event.GetId()]:
+				failedIds = append(failedIds, event.GetId())
+				failedAttempts = append(failedAttempts, event.GetAttempts())
+				failedErrors = append(failedErrors, event.GetLastError())
+				failedAvailableAts = append(failedAvailableAts, event.GetAvailableAt())
+			}
+		}
+
+		if len(publishedIds) > 0 {
+			_, err = tx.Exec(ctx, QUERY_MARK_EVENTS_PUBLISHED, publishedIds, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(failedIds) > 0 {
+			_, err = tx.Exec(ctx, QUERY_MARK_EVENTS_FAILED, failedIds, failedAttempts, failedErrors, failedAvailableAts)
+			if err != nil {
+				return err
+			}
+		}
+
+		published = len(publishedIds)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return published, nil
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"order_service/pkg"
+	"order_service/services/outbox/entity"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderEventId   = "X-Event-Id"
+	HeaderEventType = "X-Event-Type"
+	HeaderSignature = "X-Signature"
+)
+
+// WebhookPublisher posts every event as JSON to a URL, any answer other than 2xx counts as a failure.
+// With a secret the body is signed with HMAC-SHA256, hex encoded in the X-Signature header.
+type WebhookPublisher interface {
+	Publish(ctx context.Context, event *entity.Event) error
+}
+
+type webhookPublisher struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+func NewWebhookPublisher(url, secret string, timeout time.Duration) WebhookPublisher {
+	return &webhookPublisher{
+		client: &http.Client{Timeout: timeout},
+		url:    url,
+		secret: secret,
+	}
+}
+
+func (publisher *webhookPublisher) Publish(ctx context.Context, event *entity.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, publisher.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventId, strconv.FormatInt(event.GetId(), 10))
+	req.Header.Set(HeaderEventType, event.GetType())
+	if publisher.secret != "" {
+		req.Header.Set(HeaderSignature, Sign(publisher.secret, body))
+	}
+
+	res, err := publisher.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("%w: %d", entity.ErrUnexpectedResponse, res.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign is the signature receivers should compare X-Signature against.
+func Sign(secret string, body []byte) string {
+	return pkg.SignHMAC(secret, body)
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"errors"
+	"order_service/services/outbox/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{name: "No attempt yet", attempts: 0, want: time.Second},
+		{name: "First failure", attempts: 1, want: time.Second},
+		{name: "Doubles on every failure", attempts: 4, want: 8 * time.Second},
+		{name: "Capped", attempts: 10, want: time.Minute},
+		{name: "Does not overflow", attempts: 200, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, entity.Backoff(tt.attempts, time.Second, time.Minute))
+		})
+	}
+}
+
+func TestEventMarkFailed(t *testing.T) {
+	now := time.Now()
+	event := entity.Event{Id: 1, Attempts: 1}
+
+	event.MarkFailed(now, errors.New("timeout"), time.Minute)
+
+	assert.Equal(t, 2, event.GetAttempts(), "attempt should be counted")
+	assert.Equal(t, "timeout", event.GetLastError(), "error should be recorded")
+	assert.Equal(t, now.Add(time.Minute), event.GetAvailableAt(), "event should be held back")
+	assert.False(t, event.IsPublished(), "event should not be published")
+
+	event.MarkPublished(now)
+
+	assert.True(t, event.IsPublished(), "event should be published")
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	"order_service/services/outbox/entity"
+	"order_service/services/outbox/test/mock"
+	"order_service/services/outbox/usecase"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type OutboxUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo      *mock.MockOutboxRepository
+	mockPublisher *mock.MockEventPublisher
+	usecase       usecase.OutboxUsecase
+}
+
+func (suite *OutboxUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockOutboxRepository(ctrl)
+	suite.mockPublisher = mock.NewMockEventPublisher(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, suite.mockPublisher, 3, time.Second, time.Minute)
+}
+
+// relayBatch makes the mocked repo hand events to the callback and report how many it published.
+func relayBatch(events []entity.Event, got *[]entity.Event) func(ctx context.Context, now time.Time, limit int, callbackFn func(events *[]entity.Event) error) (int, error) {
+	return func(ctx context.Context, now time.Time, limit int, callbackFn func(events *[]entity.Event) error) (int, error) {
+		err := callbackFn(&events)
+		if err != nil {
+			return 0, err
+		}
+
+		*got = append(*got, events...)
+
+		published := 0
+		for _, event := range events {
+			if event.IsPublished() {
+				published++
+			}
+		}
+
+		return published, nil
+	}
+}
+
+func (suite *OutboxUsecaseTestSuite) TestRelayEvents() {
+	publishErr := errors.New("connection refused")
+
+	tests := []struct {
+		name          string
+		events        []entity.Event
+		failOn        int64
+		repoErr       error
+		want          int
+		wantPublished []int64
+		wantFailed    []int64
+		wantErr       error
+		assertion     assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "Every event is published",
+			events:        []entity.Event{{Id: 1}, {Id: 2}},
+			want:          2,
+			wantPublished: []int64{1, 2},
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Batch stops at the first failure",
+			events:        []entity.Event{{Id: 1}, {Id: 2, Attempts: 2}, {Id: 3}},
+			failOn:        2,
+			want:          1,
+			wantPublished: []int64{1},
+			wantFailed:    []int64{2},
+			assertion:     assert.NoError,
+		},
+		{
+			name:      "Repository error",
+			repoErr:   errors.New("connection reset"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotRelayEvents.Error()).WithDebug("connection reset"),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			var got []entity.Event
+
+			if tt.repoErr != nil {
+				suite.mockRepo.EXPECT().RelayEvents(gomock.Any(), gomock.Any(), 3, gomock.Any()).Return(0, tt.repoErr)
+			} else {
+				suite.mockRepo.EXPECT().RelayEvents(gomock.Any(), gomock.Any(), 3, gomock.Any()).DoAndReturn(relayBatch(tt.events, &got))
+			}
+
+			for _, event := range tt.events {
+				if event.GetId() == tt.failOn {
+					suite.mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(publishErr)
+					break
+				}
+
+				suite.mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			before := time.Now()
+			published, err := suite.usecase.RelayEvents(context.Background())
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+
+			suite.Equal(tt.want, published, "published count should be correct")
+
+			for _, event := range got {
+				switch {
+				case slices.Contains(tt.wantPublished, event.GetId()):
+					suite.True(event.IsPublished(), "event %d should be published", event.GetId())
+				case slices.Contains(tt.wantFailed, event.GetId()):
+					suite.False(event.IsPublished(), "event %d should not be published", event.GetId())
+					suite.Equal(3, event.GetAttempts(), "failed attempt should be counted")
+					suite.Equal(publishErr.Error(), event.GetLastError(), "failure should be recorded")
+					suite.WithinDuration(before.Add(4*time.Second), event.GetAvailableAt(), time.Second, "retry should back off")
+				default:
+					suite.False(event.IsPublished(), "event %d should be left for later", event.GetId())
+					suite.Zero(event.GetAttempts(), "event %d should not count an attempt", event.GetId())
+				}
+			}
+		})
+	}
+}
+
+func (suite *OutboxUsecaseTestSuite) TestRelayEventsDrainsFullBatches() {
+	var got []entity.Event
+
+	gomock.InOrder(
+		suite.mockRepo.EXPECT().RelayEvents(gomock.Any(), gomock.Any(), 3, gomock.Any()).DoAndReturn(relayBatch([]entity.Event{{Id: 1}, {Id: 2}, {Id: 3}}, &got)),
+		suite.mockRepo.EXPECT().RelayEvents(gomock.Any(), gomock.Any(), 3, gomock.Any()).DoAndReturn(relayBatch([]entity.Event{{Id: 4}}, &got)),
+	)
+	suite.mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).Times(4)
+
+	published, err := suite.usecase.RelayEvents(context.Background())
+
+	suite.NoError(err)
+	suite.Equal(4, published, "every batch should be relayed")
+	suite.Len(got, 4)
+}
+
+func TestOutboxUsecase(t *testing.T) {
+	suite.Run(t, new(OutboxUsecaseTestSuite))
+}
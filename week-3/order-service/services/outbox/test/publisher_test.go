@@ -0,0 +1,107 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"order_service/services/outbox/entity"
+	"order_service/services/outbox/repository/channel"
+	"order_service/services/outbox/repository/file"
+	"order_service/services/outbox/repository/webhook"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEvent(t *testing.T, id int64) entity.Event {
+	event, err := entity.NewEvent("order", 7, "order.created", map[string]int{"id": 7})
+	require.NoError(t, err)
+	event.Id = id
+
+	return event
+}
+
+func TestChannelPublisher(t *testing.T) {
+	publisher := channel.NewChannelPublisher(1)
+	event := newEvent(t, 1)
+
+	assert.NoError(t, publisher.Publish(context.Background(), &event))
+	assert.Equal(t, int64(1), (<-publisher.Events()).GetId(), "event should be queued")
+
+	// a full buffer with nobody reading fails once the context is done
+	assert.NoError(t, publisher.Publish(context.Background(), &event))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, publisher.Publish(ctx, &event), context.DeadlineExceeded)
+
+	publisher.Close()
+	assert.ErrorIs(t, publisher.Publish(context.Background(), &event), entity.ErrPublisherClosed)
+}
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events", "outbox.log")
+	publisher := file.NewFilePublisher(path)
+
+	for id := int64(1); id <= 2; id++ {
+		event := newEvent(t, id)
+		require.NoError(t, publisher.Publish(context.Background(), &event))
+	}
+
+	content, err := os.Open(path)
+	require.NoError(t, err)
+	defer content.Close()
+
+	ids := make([]int64, 0, 2)
+	scanner := bufio.NewScanner(content)
+	for scanner.Scan() {
+		var event entity.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		assert.Equal(t, "order.created", event.GetType())
+		assert.JSONEq(t, `{"id":7}`, string(event.GetPayload()))
+		ids = append(ids, event.GetId())
+	}
+
+	assert.Equal(t, []int64{1, 2}, ids, "every event should be appended as one line")
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "Delivered", status: http.StatusNoContent, assertion: assert.NoError},
+		{name: "Receiver failed", status: http.StatusServiceUnavailable, assertion: assert.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, "5", r.Header.Get(webhook.HeaderEventId))
+				assert.Equal(t, "order.created", r.Header.Get(webhook.HeaderEventType))
+				assert.Equal(t, webhook.Sign("secret", body), r.Header.Get(webhook.HeaderSignature), "body should be signed")
+
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			publisher := webhook.NewWebhookPublisher(server.URL, "secret", time.Second)
+			event := newEvent(t, 5)
+
+			err := publisher.Publish(context.Background(), &event)
+			if tt.assertion(t, err) && err != nil {
+				assert.ErrorIs(t, err, entity.ErrUnexpectedResponse)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"context"
+	"order_service/services/outbox/entity"
+)
+
+// EventPublisher delivers one outbox event. A nil error means the event was handed over for good and is
+// not sent again, any error gets the event retried later.
+type EventPublisher interface {
+	Publish(ctx context.Context, event *entity.Event) error
+}
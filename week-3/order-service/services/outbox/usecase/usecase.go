@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"order_service/internal/core"
+	"order_service/services/outbox/entity"
+	outboxRepo "order_service/services/outbox/repository/postgres"
+	"time"
+)
+
+type OutboxUsecase interface {
+	RelayEvents(ctx context.Context) (int, error)
+	RunRelay(ctx context.Context, interval time.Duration)
+}
+
+type outboxUsecase struct {
+	repo       outboxRepo.OutboxRepository
+	publisher  EventPublisher
+	batchSize  int
+	retryDelay time.Duration
+	maxDelay   time.Duration
+}
+
+// NewUsecase builds the outbox relay. A failed event is retried after retryDelay, the delay doubles on
+// every further failure up to maxDelay.
+func NewUsecase(repo outboxRepo.OutboxRepository, publisher EventPublisher, batchSize int, retryDelay, maxDelay time.Duration) OutboxUsecase {
+	return &outboxUsecase{
+		repo,
+		publisher,
+		batchSize,
+		retryDelay,
+		maxDelay,
+	}
+}
+
+// RelayEvents publishes the due events in id order until none are left. A batch stops at the first
+// failure, the receiver is likely down for the rest too and the transaction should not wait on it.
+func (uc *outboxUsecase) RelayEvents(ctx context.Context) (int, error) {
+	total := 0
+
+	for {
+		published, err := uc.repo.RelayEvents(ctx, time.Now(), uc.batchSize, func(events *[]entity.Event) error {
+			for i := range *events {
+				event := &(*events)[i]
+
+				err := uc.publisher.Publish(ctx, event)
+				if err != nil {
+					event.MarkFailed(time.Now(), err, entity.Backoff(event.GetAttempts()+1, uc.retryDelay, uc.maxDelay))
+					log.Printf("publish event %d error: %v", event.GetId(), err)
+
+					return nil
+				}
+
+				event.MarkPublished(time.Now())
+			}
+
+			return nil
+		})
+		if err != nil {
+			return total, core.ErrInternalServerError.WithError(entity.ErrCannotRelayEvents.Error()).WithDebug(err.Error())
+		}
+
+		total += published
+		if published < uc.batchSize {
+			return total, nil
+		}
+	}
+}
+
+// RunRelay relays the outbox every interval until ctx is done.
+func (uc *outboxUsecase) RunRelay(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := uc.RelayEvents(ctx)
+			if err != nil {
+				log.Println("relay outbox events error:", err)
+			}
+			if published > 0 {
+				log.Printf("published %d outbox events", published)
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "errors"
+
+var (
+	ErrUnknownPaymentMethod  = errors.New("payment method must be wallet or card")
+	ErrMissingCardToken      = errors.New("card payments need a card token")
+	ErrInvalidGatewayEvent   = errors.New("payment event must have a provider, a reference and a known status")
+	ErrInvalidSignature      = errors.New("payment event signature is invalid")
+	ErrWebhookDisabled       = errors.New("payment webhook has no secret configured")
+	ErrInvalidPaymentStatus  = errors.New("payment cannot move to this status")
+	ErrPaymentInProgress     = errors.New("order already has a payment in progress or captured")
+	ErrOrderNotPayable       = errors.New("only pending orders can be paid")
+	ErrOrderNotFound         = errors.New("order cannot be found")
+	ErrPaymentNotFound       = errors.New("payment cannot be found")
+	ErrInsufficientBalance   = errors.New("user's balance is insufficient")
+	ErrCardDeclined          = errors.New("card is declined")
+	ErrUnknownCharge         = errors.New("card gateway does not know the charge")
+	ErrUnexpectedResponse    = errors.New("payment webhook answered with an unexpected status")
+	ErrInvalidMemory         = errors.New("invalid memory")
+	ErrCannotGetPayments     = errors.New("payments cannot be get")
+	ErrCannotCreatePayment   = errors.New("payment cannot be create")
+	ErrCannotUpdatePayment   = errors.New("payment cannot be update")
+	ErrCannotReleasePayment  = errors.New("payment cannot be refunded or voided")
+	ErrProviderNotConfigured = errors.New("payment provider is not configured")
+)
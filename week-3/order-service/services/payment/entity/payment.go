@@ -0,0 +1,200 @@
+package entity
+
+import (
+	"order_service/internal/core"
+	"time"
+	"unicode/utf8"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending    PaymentStatus = "pending"
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	PaymentStatusCaptured   PaymentStatus = "captured"
+	PaymentStatusFailed     PaymentStatus = "failed"
+	PaymentStatusVoided     PaymentStatus = "voided"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+)
+
+// Payment methods, every one is served by the provider of the same name.
+const (
+	PaymentMethodWallet = "wallet"
+	PaymentMethodCard   = "card"
+)
+
+// GatewayActorId is the actor of the order status changes made by payment provider callbacks.
+const GatewayActorId = 0
+
+// paymentStatusTransitions lists, for every status, the statuses a payment is allowed to move to next.
+// A payment is pending until its provider answers, failed, voided and refunded are terminal states.
+var paymentStatusTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusPending:    {PaymentStatusAuthorized, PaymentStatusCaptured, PaymentStatusFailed, PaymentStatusVoided},
+	PaymentStatusAuthorized: {PaymentStatusCaptured, PaymentStatusFailed, PaymentStatusVoided},
+	PaymentStatusCaptured:   {PaymentStatusRefunded},
+	PaymentStatusFailed:     {},
+	PaymentStatusVoided:     {},
+	PaymentStatusRefunded:   {},
+}
+
+func (status PaymentStatus) IsValid() bool {
+	_, ok := paymentStatusTransitions[status]
+
+	return ok
+}
+
+func (status PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range paymentStatusTransitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsOpen tells whether the provider may still take the money, an order has at most one open payment.
+func (status PaymentStatus) IsOpen() bool {
+	return status == PaymentStatusPending || status == PaymentStatusAuthorized
+}
+
+// Payment is one attempt at paying an order with Provider, Reference is the provider's id of it.
+// Amount is in the base currency, like the balance and the order's BaseTotalPrice.
+type Payment struct {
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     *time.Time    `json:"updated_at"`
+	Provider      string        `json:"provider"`
+	Reference     string        `json:"reference"`
+	Status        PaymentStatus `json:"status"`
+	FailureReason string        `json:"failure_reason,omitempty"`
+	Id            int           `json:"id"`
+	OrderId       int           `json:"order_id"`
+	UserId        int           `json:"user_id"`
+	Amount        core.Money    `json:"amount" swaggertype:"number"`
+}
+
+// ProviderResult is what a provider answers, an asynchronous provider leaves Status as it was and reports
+// the outcome later through its webhook. FailureReason is only set for a failed payment.
+type ProviderResult struct {
+	Reference     string
+	Status        PaymentStatus
+	FailureReason string
+}
+
+// NewPayment starts an attempt at paying an order, its amount is taken from the order once it is locked.
+func NewPayment(orderId, userId int, provider string, now time.Time) Payment {
+	return Payment{
+		OrderId:   orderId,
+		UserId:    userId,
+		Provider:  provider,
+		Status:    PaymentStatusPending,
+		CreatedAt: now,
+	}
+}
+
+func (payment *Payment) SetId(id int) {
+	if payment != nil {
+		payment.Id = id
+	}
+}
+
+// Apply records a provider's answer and tells whether it changed the payment. The first reference given
+// sticks, an answer for a status the payment cannot move to is turned down with ErrInvalidPaymentStatus.
+func (payment *Payment) Apply(result ProviderResult, now time.Time) (bool, error) {
+	if payment == nil {
+		return false, ErrInvalidMemory
+	}
+
+	changed := false
+
+	if result.Status != "" && result.Status != payment.Status {
+		if !payment.Status.CanTransitionTo(result.Status) {
+			return false, ErrInvalidPaymentStatus
+		}
+
+		payment.Status = result.Status
+		payment.FailureReason = truncate(result.FailureReason, 200)
+		changed = true
+	}
+
+	if payment.Reference == "" && result.Reference != "" {
+		payment.Reference = result.Reference
+		changed = true
+	}
+
+	if changed {
+		payment.UpdatedAt = &now
+	}
+
+	return changed, nil
+}
+
+func (payment *Payment) SetAmount(amount core.Money) {
+	if payment != nil {
+		payment.Amount = amount
+	}
+}
+
+func (payment *Payment) GetIdSafe() int {
+	if payment != nil {
+		return payment.Id
+	}
+
+	return 0
+}
+
+func (payment *Payment) GetOrderIdSafe() int {
+	if payment != nil {
+		return payment.OrderId
+	}
+
+	return 0
+}
+
+func (payment *Payment) GetUserIdSafe() int {
+	if payment != nil {
+		return payment.UserId
+	}
+
+	return 0
+}
+
+func (payment *Payment) GetProviderSafe() string {
+	if payment != nil {
+		return payment.Provider
+	}
+
+	return ""
+}
+
+func (payment *Payment) GetStatusSafe() PaymentStatus {
+	if payment != nil {
+		return payment.Status
+	}
+
+	return ""
+}
+
+func (payment *Payment) GetReferenceSafe() string {
+	if payment != nil {
+		return payment.Reference
+	}
+
+	return ""
+}
+
+func (payment *Payment) GetAmountSafe() core.Money {
+	if payment != nil {
+		return payment.Amount
+	}
+
+	return core.NewMoney(0)
+}
+
+func truncate(value string, max int) string {
+	if utf8.RuneCountInString(value) <= max {
+		return value
+	}
+
+	return string([]rune(value)[:max])
+}
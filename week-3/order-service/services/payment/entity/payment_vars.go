@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// PaymentRequest is how the user pays an order, the wallet balance is charged when Method is empty.
+// CardToken is the token the card form handed out, tokens starting with tok_decline are declined.
+type PaymentRequest struct {
+	Method    string `json:"payment_method" example:"wallet"`
+	CardToken string `json:"card_token" example:"tok_visa"`
+}
+
+// GatewayEvent is the body of a card gateway's status callback for the charge Reference.
+// EventId is the gateway's id of the callback, FailureReason is only set for a failed charge.
+type GatewayEvent struct {
+	EventId       string        `json:"event_id" example:"evt_0001"`
+	Provider      string        `json:"provider" example:"card"`
+	Reference     string        `json:"reference" example:"ch_0001"`
+	Status        PaymentStatus `json:"status" example:"captured"`
+	FailureReason string        `json:"failure_reason"`
+}
+
+// GetMethod returns the normalized payment method.
+func (data PaymentRequest) GetMethod() string {
+	method := strings.ToLower(strings.TrimSpace(data.Method))
+	if method == "" {
+		return PaymentMethodWallet
+	}
+
+	return method
+}
+
+func (data PaymentRequest) Validate() error {
+	switch data.GetMethod() {
+	case PaymentMethodWallet:
+		return nil
+	case PaymentMethodCard:
+		if strings.TrimSpace(data.CardToken) == "" || utf8.RuneCountInString(data.CardToken) > 100 {
+			return ErrMissingCardToken
+		}
+
+		return nil
+	}
+
+	return ErrUnknownPaymentMethod
+}
+
+func (data GatewayEvent) Validate() error {
+	if strings.TrimSpace(data.EventId) == "" || strings.TrimSpace(data.Provider) == "" || strings.TrimSpace(data.Reference) == "" {
+		return ErrInvalidGatewayEvent
+	}
+
+	if utf8.RuneCountInString(data.Reference) > 100 || utf8.RuneCountInString(data.FailureReason) > 200 {
+		return ErrInvalidGatewayEvent
+	}
+
+	if !data.Status.IsValid() || data.Status == PaymentStatusPending {
+		return ErrInvalidGatewayEvent
+	}
+
+	return nil
+}
+
+// ToResult turns a validated callback into the answer it stands for.
+func (data GatewayEvent) ToResult() ProviderResult {
+	return ProviderResult{
+		Reference:     data.Reference,
+		Status:        data.Status,
+		FailureReason: data.FailureReason,
+	}
+}
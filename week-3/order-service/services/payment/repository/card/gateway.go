@@ -0,0 +1,197 @@
+package card
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"order_service/pkg"
+	"order_service/services/payment/entity"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const HeaderSignature = "X-Signature"
+
+// DeclinePrefix marks the card tokens the mock gateway declines.
+const DeclinePrefix = "tok_decline"
+
+// maxDeliveries is how many times a callback is sent before the gateway gives up on it.
+const maxDeliveries = 3
+
+// CardGateway charges cards. Authorizing and capturing are confirmed later through the payment webhook,
+// refunds and voids are answered right away.
+type CardGateway interface {
+	Name() string
+	Authorize(ctx context.Context, payment *entity.Payment, source string) (entity.ProviderResult, error)
+	Capture(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error)
+	Refund(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error)
+	Void(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error)
+}
+
+type mockGateway struct {
+	client  *http.Client
+	url     string
+	secret  string
+	delay   time.Duration
+	mu      sync.Mutex
+	charges map[string]entity.PaymentStatus
+}
+
+// NewMockGateway builds a card gateway that keeps its charges in memory and posts its callbacks to url
+// after delay, signed with secret like the payment webhook expects. It stands in for a real gateway in
+// development and tests, every card is accepted unless its token starts with DeclinePrefix.
+func NewMockGateway(url, secret string, delay, timeout time.Duration) CardGateway {
+	return &mockGateway{
+		client:  &http.Client{Timeout: timeout},
+		url:     url,
+		secret:  secret,
+		delay:   delay,
+		charges: make(map[string]entity.PaymentStatus),
+	}
+}
+
+func (gateway *mockGateway) Name() string {
+	return entity.PaymentMethodCard
+}
+
+func (gateway *mockGateway) Authorize(ctx context.Context, payment *entity.Payment, source string) (entity.ProviderResult, error) {
+	if gateway.url == "" || gateway.secret == "" {
+		return entity.ProviderResult{}, entity.ErrProviderNotConfigured
+	}
+
+	reference := "ch_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+
+	gateway.mu.Lock()
+	gateway.charges[reference] = entity.PaymentStatusPending
+	gateway.mu.Unlock()
+
+	if strings.HasPrefix(source, DeclinePrefix) {
+		go gateway.confirm(reference, entity.PaymentStatusPending, entity.PaymentStatusFailed, entity.ErrCardDeclined.Error())
+	} else {
+		go gateway.confirm(reference, entity.PaymentStatusPending, entity.PaymentStatusAuthorized, "")
+	}
+
+	return entity.ProviderResult{Reference: reference}, nil
+}
+
+func (gateway *mockGateway) Capture(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error) {
+	status, err := gateway.status(payment.GetReferenceSafe())
+	if err != nil {
+		return entity.ProviderResult{}, err
+	}
+	if status != entity.PaymentStatusAuthorized {
+		return entity.ProviderResult{}, entity.ErrInvalidPaymentStatus
+	}
+
+	go gateway.confirm(payment.GetReferenceSafe(), entity.PaymentStatusAuthorized, entity.PaymentStatusCaptured, "")
+
+	return entity.ProviderResult{}, nil
+}
+
+func (gateway *mockGateway) Refund(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error) {
+	return gateway.move(payment.GetReferenceSafe(), entity.PaymentStatusRefunded)
+}
+
+func (gateway *mockGateway) Void(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error) {
+	// an attempt the gateway never got a charge for has nothing to let go of
+	if payment.GetReferenceSafe() == "" {
+		return entity.ProviderResult{Status: entity.PaymentStatusVoided}, nil
+	}
+
+	return gateway.move(payment.GetReferenceSafe(), entity.PaymentStatusVoided)
+}
+
+func (gateway *mockGateway) status(reference string) (entity.PaymentStatus, error) {
+	gateway.mu.Lock()
+	defer gateway.mu.Unlock()
+
+	status, ok := gateway.charges[reference]
+	if !ok {
+		return "", entity.ErrUnknownCharge
+	}
+
+	return status, nil
+}
+
+// move changes a charge right away.
+func (gateway *mockGateway) move(reference string, next entity.PaymentStatus) (entity.ProviderResult, error) {
+	gateway.mu.Lock()
+	defer gateway.mu.Unlock()
+
+	status, ok := gateway.charges[reference]
+	if !ok {
+		return entity.ProviderResult{}, entity.ErrUnknownCharge
+	}
+	if !status.CanTransitionTo(next) {
+		return entity.ProviderResult{}, entity.ErrInvalidPaymentStatus
+	}
+	gateway.charges[reference] = next
+
+	return entity.ProviderResult{Reference: reference, Status: next}, nil
+}
+
+// confirm moves a charge after the delay and calls the webhook back, unless the charge was voided meanwhile.
+func (gateway *mockGateway) confirm(reference string, from, to entity.PaymentStatus, failureReason string) {
+	time.Sleep(gateway.delay)
+
+	gateway.mu.Lock()
+	if gateway.charges[reference] != from {
+		gateway.mu.Unlock()
+		return
+	}
+	gateway.charges[reference] = to
+	gateway.mu.Unlock()
+
+	event := entity.GatewayEvent{
+		EventId:       "evt_" + strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Provider:      gateway.Name(),
+		Reference:     reference,
+		Status:        to,
+		FailureReason: failureReason,
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxDeliveries; attempt++ {
+		err = gateway.send(context.Background(), event)
+		if err == nil {
+			return
+		}
+
+		time.Sleep(gateway.delay * time.Duration(attempt))
+	}
+
+	log.Printf("card gateway callback for %s error: %v", reference, err)
+}
+
+func (gateway *mockGateway) send(ctx context.Context, event entity.GatewayEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gateway.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, pkg.SignHMAC(gateway.secret, body))
+
+	res, err := gateway.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("%w: %d", entity.ErrUnexpectedResponse, res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/pkg"
+	orderEntity "order_service/services/order/entity"
+	"order_service/services/payment/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment, order *orderEntity.Order, payments []entity.Payment) error) error
+	GetPayments(ctx context.Context, orderId int) (*[]entity.Payment, error)
+	AuthorizePayment(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment, order *orderEntity.Order) (bool, error)) error
+	UpdatePayment(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment) (bool, error)) error
+	UpdatePaymentByReference(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment) (bool, error)) error
+}
+
+const (
+	QUERY_GET_ORDER_LOCK     = "SELECT id, user_id, status, base_total_price FROM orders WHERE id = $1 FOR UPDATE"
+	QUERY_CREATE_PAYMENT     = "INSERT INTO payments (order_id, user_id, provider, reference, status, amount, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id"
+	QUERY_GET_PAYMENTS       = "SELECT id, order_id, user_id, provider, reference, status, amount, failure_reason, created_at, updated_at FROM payments WHERE order_id = $1 ORDER BY created_at, id"
+	QUERY_GET_PAYMENT_LOCK   = "SELECT id, order_id, user_id, provider, reference, status, amount, failure_reason, created_at, updated_at FROM payments WHERE id = $1 FOR UPDATE"
+	QUERY_GET_REFERENCE_LOCK = "SELECT id, order_id, user_id, provider, reference, status, amount, failure_reason, created_at, updated_at FROM payments WHERE provider = $1 AND reference = $2 AND reference <> '' FOR UPDATE"
+	QUERY_UPDATE_PAYMENT     = "UPDATE payments SET reference = $2, status = $3, failure_reason = $4, updated_at = $5 WHERE id = $1"
+)
+
+type postgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPaymentRepo(db *pgxpool.Pool) PaymentRepository {
+	return &postgresRepo{
+		db,
+	}
+}
+
+func scanPayment(row pgx.Row, payment *entity.Payment) error {
+	err := row.Scan(&payment.Id, &payment.OrderId, &payment.UserId, &payment.Provider, &payment.Reference, &payment.Status, &payment.Amount, &payment.FailureReason, &payment.CreatedAt, &payment.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return core.ErrRecordNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CreatePayment stores a new attempt at paying a locked order. callbackFn sees the earlier attempts, locking
+// the order keeps two attempts from being started at the same time.
+func (repo *postgresRepo) CreatePayment(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment, order *orderEntity.Order, payments []entity.Payment) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		var order orderEntity.Order
+
+		err := tx.QueryRow(ctx, QUERY_GET_ORDER_LOCK, payment.OrderId).Scan(&order.Id, &order.UserId, &order.Status, &order.BaseTotalPrice)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return core.ErrRecordNotFound
+			}
+			return err
+		}
+
+		rows, err := tx.Query(ctx, QUERY_GET_PAYMENTS, payment.OrderId)
+		if err != nil {
+			return err
+		}
+
+		payments, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Payment, error) {
+			var payment entity.Payment
+
+			err := scanPayment(row, &payment)
+
+			return payment, err
+		})
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		err = callbackFn(payment, &order, payments)
+		if err != nil {
+			return err
+		}
+
+		var newPaymentId int
+
+		err = tx.QueryRow(ctx, QUERY_CREATE_PAYMENT, payment.OrderId, payment.UserId, payment.Provider, payment.Reference, payment.Status, payment.Amount, payment.CreatedAt).Scan(&newPaymentId)
+		if err != nil {
+			return err
+		}
+		payment.SetId(newPaymentId)
+
+		return nil
+	})
+}
+
+func (repo *postgresRepo) GetPayments(ctx context.Context, orderId int) (*[]entity.Payment, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_PAYMENTS, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Payment, error) {
+		var payment entity.Payment
+
+		err := scanPayment(row, &payment)
+
+		return payment, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &payments, nil
+}
+
+// AuthorizePayment is UpdatePayment with the order of payment locked first. callbackFn records the answer of
+// the provider while the order is held, so a cancellation either sees the payment authorized or is seen by it.
+func (repo *postgresRepo) AuthorizePayment(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment, order *orderEntity.Order) (bool, error)) error {
+	if payment == nil {
+		return entity.ErrInvalidMemory
+	}
+
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		var order orderEntity.Order
+
+		err := tx.QueryRow(ctx, QUERY_GET_ORDER_LOCK, payment.OrderId).Scan(&order.Id, &order.UserId, &order.Status, &order.BaseTotalPrice)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return core.ErrRecordNotFound
+			}
+			return err
+		}
+
+		err = scanPayment(tx.QueryRow(ctx, QUERY_GET_PAYMENT_LOCK, payment.GetIdSafe()), payment)
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		changed, err := callbackFn(payment, &order)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+
+		return writePayment(ctx, tx, payment)
+	})
+}
+
+// UpdatePayment locks the payment with the id of payment and reads it into payment, the changes callbackFn
+// makes are only written when it says so.
+func (repo *postgresRepo) UpdatePayment(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment) (bool, error)) error {
+	return repo.updatePayment(ctx, payment, callbackFn, QUERY_GET_PAYMENT_LOCK, payment.GetIdSafe())
+}
+
+// UpdatePaymentByReference is UpdatePayment for the payment the provider of payment knows by its reference.
+func (repo *postgresRepo) UpdatePaymentByReference(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment) (bool, error)) error {
+	return repo.updatePayment(ctx, payment, callbackFn, QUERY_GET_REFERENCE_LOCK, payment.GetProviderSafe(), payment.GetReferenceSafe())
+}
+
+func (repo *postgresRepo) updatePayment(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment) (bool, error), query string, args ...any) error {
+	if payment == nil {
+		return entity.ErrInvalidMemory
+	}
+
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		err := scanPayment(tx.QueryRow(ctx, query, args...), payment)
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		changed, err := callbackFn(payment)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+
+		return writePayment(ctx, tx, payment)
+	})
+}
+
+func writePayment(ctx context.Context, tx pgx.Tx, payment *entity.Payment) error {
+	updatedAt := time.Now()
+	if payment.UpdatedAt != nil {
+		updatedAt = *payment.UpdatedAt
+	}
+
+	_, err := tx.Exec(ctx, QUERY_UPDATE_PAYMENT, payment.Id, payment.Reference, payment.Status, payment.FailureReason, updatedAt)
+
+	return err
+}
@@ -0,0 +1,13 @@
+package wallet
+
+import (
+	"context"
+	"order_service/internal/core"
+)
+
+// Ledger posts the wallet charges and refunds of the payments to the balance ledger.
+// It is implemented by the user usecase.
+type Ledger interface {
+	ChargeBalance(ctx context.Context, userId int, amount core.Money, reference string) (bool, error)
+	RefundBalance(ctx context.Context, userId int, amount core.Money, reference string) error
+}
@@ -0,0 +1,83 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"order_service/services/payment/entity"
+)
+
+// WalletProvider pays with the balance of the user. The balance is taken when the payment is authorized,
+// capturing it has nothing left to do and voiding it gives the balance back like a refund. Both are posted
+// to the ledger under wallet_<payment id>, so a retried call never moves the money twice and a refund only
+// gives back what was taken.
+type WalletProvider interface {
+	Name() string
+	Authorize(ctx context.Context, payment *entity.Payment, source string) (entity.ProviderResult, error)
+	Capture(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error)
+	Refund(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error)
+	Void(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error)
+}
+
+type walletProvider struct {
+	ledger Ledger
+}
+
+func NewWalletProvider(ledger Ledger) WalletProvider {
+	return &walletProvider{
+		ledger,
+	}
+}
+
+func (provider *walletProvider) Name() string {
+	return entity.PaymentMethodWallet
+}
+
+func (provider *walletProvider) Authorize(ctx context.Context, payment *entity.Payment, source string) (entity.ProviderResult, error) {
+	reference := walletReference(payment)
+
+	// an order that costs nothing has no money to move
+	if !payment.GetAmountSafe().IsPositive() {
+		return entity.ProviderResult{Reference: reference, Status: entity.PaymentStatusAuthorized}, nil
+	}
+
+	charged, err := provider.ledger.ChargeBalance(ctx, payment.GetUserIdSafe(), payment.GetAmountSafe(), reference)
+	if err != nil {
+		return entity.ProviderResult{}, err
+	}
+	if !charged {
+		return entity.ProviderResult{Reference: reference, Status: entity.PaymentStatusFailed, FailureReason: entity.ErrInsufficientBalance.Error()}, nil
+	}
+
+	return entity.ProviderResult{Reference: reference, Status: entity.PaymentStatusAuthorized}, nil
+}
+
+func (provider *walletProvider) Capture(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error) {
+	return entity.ProviderResult{Status: entity.PaymentStatusCaptured}, nil
+}
+
+func (provider *walletProvider) Refund(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error) {
+	return provider.giveBack(ctx, payment, entity.PaymentStatusRefunded)
+}
+
+func (provider *walletProvider) Void(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error) {
+	// a pending payment may have been charged by a Pay that did not get to record it, the ledger has nothing
+	// to give back when it was not
+	return provider.giveBack(ctx, payment, entity.PaymentStatusVoided)
+}
+
+func (provider *walletProvider) giveBack(ctx context.Context, payment *entity.Payment, status entity.PaymentStatus) (entity.ProviderResult, error) {
+	if !payment.GetAmountSafe().IsPositive() {
+		return entity.ProviderResult{Status: status}, nil
+	}
+
+	err := provider.ledger.RefundBalance(ctx, payment.GetUserIdSafe(), payment.GetAmountSafe(), walletReference(payment))
+	if err != nil {
+		return entity.ProviderResult{}, err
+	}
+
+	return entity.ProviderResult{Status: status}, nil
+}
+
+func walletReference(payment *entity.Payment) string {
+	return fmt.Sprintf("wallet_%d", payment.GetIdSafe())
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"order_service/pkg"
+	"order_service/services/payment/entity"
+	"order_service/services/payment/repository/card"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCallbackServer collects the gateway callbacks whose signature matches secret.
+func newCallbackServer(t *testing.T, secret string) (*httptest.Server, <-chan entity.GatewayEvent) {
+	events := make(chan entity.GatewayEvent, 4)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		if !pkg.VerifyHMAC(secret, body, r.Header.Get(card.HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var event entity.GatewayEvent
+		require.NoError(t, json.Unmarshal(body, &event))
+		events <- event
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, events
+}
+
+func waitEvent(t *testing.T, events <-chan entity.GatewayEvent) entity.GatewayEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("gateway callback was not sent")
+	}
+
+	return entity.GatewayEvent{}
+}
+
+func TestMockGatewayCharge(t *testing.T) {
+	srv, events := newCallbackServer(t, "secret")
+	gateway := card.NewMockGateway(srv.URL, "secret", time.Millisecond, time.Second)
+	ctx := context.Background()
+
+	result, err := gateway.Authorize(ctx, &entity.Payment{Id: 1}, "tok_visa")
+	require.NoError(t, err)
+	assert.Empty(t, result.Status, "authorization should be confirmed through the webhook")
+
+	event := waitEvent(t, events)
+	assert.Equal(t, result.Reference, event.Reference)
+	assert.Equal(t, entity.PaymentStatusAuthorized, event.Status)
+	assert.NoError(t, event.Validate(), "callback should be a valid gateway event")
+
+	payment := &entity.Payment{Id: 1, Reference: result.Reference, Status: entity.PaymentStatusAuthorized}
+	_, err = gateway.Capture(ctx, payment)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PaymentStatusCaptured, waitEvent(t, events).Status)
+
+	result, err = gateway.Refund(ctx, payment)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PaymentStatusRefunded, result.Status, "refund should be answered right away")
+
+	_, err = gateway.Refund(ctx, payment)
+	assert.ErrorIs(t, err, entity.ErrInvalidPaymentStatus, "charge should be refunded once")
+}
+
+func TestMockGatewayDecline(t *testing.T) {
+	srv, events := newCallbackServer(t, "secret")
+	gateway := card.NewMockGateway(srv.URL, "secret", time.Millisecond, time.Second)
+
+	result, err := gateway.Authorize(context.Background(), &entity.Payment{Id: 1}, card.DeclinePrefix+"_funds")
+	require.NoError(t, err)
+
+	event := waitEvent(t, events)
+	assert.Equal(t, result.Reference, event.Reference)
+	assert.Equal(t, entity.PaymentStatusFailed, event.Status)
+	assert.Equal(t, entity.ErrCardDeclined.Error(), event.FailureReason)
+
+	_, err = gateway.Capture(context.Background(), &entity.Payment{Reference: result.Reference})
+	assert.ErrorIs(t, err, entity.ErrInvalidPaymentStatus, "declined charge should not be captured")
+}
+
+func TestMockGatewayVoidBeforeConfirm(t *testing.T) {
+	srv, events := newCallbackServer(t, "secret")
+	gateway := card.NewMockGateway(srv.URL, "secret", 50*time.Millisecond, time.Second)
+	ctx := context.Background()
+
+	result, err := gateway.Authorize(ctx, &entity.Payment{Id: 1}, "tok_visa")
+	require.NoError(t, err)
+
+	result, err = gateway.Void(ctx, &entity.Payment{Reference: result.Reference})
+	require.NoError(t, err)
+	assert.Equal(t, entity.PaymentStatusVoided, result.Status)
+
+	select {
+	case event := <-events:
+		t.Fatalf("voided charge should not be confirmed, got %s", event.Status)
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestMockGatewayNotConfigured(t *testing.T) {
+	gateway := card.NewMockGateway("", "", 0, time.Second)
+
+	_, err := gateway.Authorize(context.Background(), &entity.Payment{Id: 1}, "tok_visa")
+
+	assert.ErrorIs(t, err, entity.ErrProviderNotConfigured)
+}
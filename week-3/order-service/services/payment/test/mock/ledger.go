@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/wallet/ledger.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/wallet/ledger.go -destination test/mock/ledger.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	core "order_service/internal/core"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLedger is a mock of Ledger interface.
+type MockLedger struct {
+	ctrl     *gomock.Controller
+	recorder *MockLedgerMockRecorder
+}
+
+// MockLedgerMockRecorder is the mock recorder for MockLedger.
+type MockLedgerMockRecorder struct {
+	mock *MockLedger
+}
+
+// NewMockLedger creates a new mock instance.
+func NewMockLedger(ctrl *gomock.Controller) *MockLedger {
+	mock := &MockLedger{ctrl: ctrl}
+	mock.recorder = &MockLedgerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLedger) EXPECT() *MockLedgerMockRecorder {
+	return m.recorder
+}
+
+// ChargeBalance mocks base method.
+func (m *MockLedger) ChargeBalance(ctx context.Context, userId int, amount core.Money, reference string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChargeBalance", ctx, userId, amount, reference)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChargeBalance indicates an expected call of ChargeBalance.
+func (mr *MockLedgerMockRecorder) ChargeBalance(ctx, userId, amount, reference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChargeBalance", reflect.TypeOf((*MockLedger)(nil).ChargeBalance), ctx, userId, amount, reference)
+}
+
+// RefundBalance mocks base method.
+func (m *MockLedger) RefundBalance(ctx context.Context, userId int, amount core.Money, reference string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundBalance", ctx, userId, amount, reference)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefundBalance indicates an expected call of RefundBalance.
+func (mr *MockLedgerMockRecorder) RefundBalance(ctx, userId, amount, reference any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundBalance", reflect.TypeOf((*MockLedger)(nil).RefundBalance), ctx, userId, amount, reference)
+}
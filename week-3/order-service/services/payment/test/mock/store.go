@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/postgres/store.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/postgres/store.go -destination test/mock/store.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/order/entity"
+	entity0 "order_service/services/payment/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPaymentRepository is a mock of PaymentRepository interface.
+type MockPaymentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRepositoryMockRecorder
+}
+
+// MockPaymentRepositoryMockRecorder is the mock recorder for MockPaymentRepository.
+type MockPaymentRepositoryMockRecorder struct {
+	mock *MockPaymentRepository
+}
+
+// NewMockPaymentRepository creates a new mock instance.
+func NewMockPaymentRepository(ctrl *gomock.Controller) *MockPaymentRepository {
+	mock := &MockPaymentRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRepository) EXPECT() *MockPaymentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AuthorizePayment mocks base method.
+func (m *MockPaymentRepository) AuthorizePayment(ctx context.Context, payment *entity0.Payment, callbackFn func(*entity0.Payment, *entity.Order) (bool, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizePayment", ctx, payment, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AuthorizePayment indicates an expected call of AuthorizePayment.
+func (mr *MockPaymentRepositoryMockRecorder) AuthorizePayment(ctx, payment, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizePayment", reflect.TypeOf((*MockPaymentRepository)(nil).AuthorizePayment), ctx, payment, callbackFn)
+}
+
+// CreatePayment mocks base method.
+func (m *MockPaymentRepository) CreatePayment(ctx context.Context, payment *entity0.Payment, callbackFn func(*entity0.Payment, *entity.Order, []entity0.Payment) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayment", ctx, payment, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePayment indicates an expected call of CreatePayment.
+func (mr *MockPaymentRepositoryMockRecorder) CreatePayment(ctx, payment, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockPaymentRepository)(nil).CreatePayment), ctx, payment, callbackFn)
+}
+
+// GetPayments mocks base method.
+func (m *MockPaymentRepository) GetPayments(ctx context.Context, orderId int) (*[]entity0.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayments", ctx, orderId)
+	ret0, _ := ret[0].(*[]entity0.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayments indicates an expected call of GetPayments.
+func (mr *MockPaymentRepositoryMockRecorder) GetPayments(ctx, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayments", reflect.TypeOf((*MockPaymentRepository)(nil).GetPayments), ctx, orderId)
+}
+
+// UpdatePayment mocks base method.
+func (m *MockPaymentRepository) UpdatePayment(ctx context.Context, payment *entity0.Payment, callbackFn func(*entity0.Payment) (bool, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePayment", ctx, payment, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePayment indicates an expected call of UpdatePayment.
+func (mr *MockPaymentRepositoryMockRecorder) UpdatePayment(ctx, payment, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePayment", reflect.TypeOf((*MockPaymentRepository)(nil).UpdatePayment), ctx, payment, callbackFn)
+}
+
+// UpdatePaymentByReference mocks base method.
+func (m *MockPaymentRepository) UpdatePaymentByReference(ctx context.Context, payment *entity0.Payment, callbackFn func(*entity0.Payment) (bool, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentByReference", ctx, payment, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePaymentByReference indicates an expected call of UpdatePaymentByReference.
+func (mr *MockPaymentRepositoryMockRecorder) UpdatePaymentByReference(ctx, payment, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentByReference", reflect.TypeOf((*MockPaymentRepository)(nil).UpdatePaymentByReference), ctx, payment, callbackFn)
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"order_service/services/payment/entity"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentApply(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		status    entity.PaymentStatus
+		reference string
+		result    entity.ProviderResult
+		want      entity.PaymentStatus
+		wantRef   string
+		changed   bool
+		wantErr   error
+	}{
+		{
+			name:    "Asynchronous provider hands out its reference",
+			status:  entity.PaymentStatusPending,
+			result:  entity.ProviderResult{Reference: "ch_1"},
+			want:    entity.PaymentStatusPending,
+			wantRef: "ch_1",
+			changed: true,
+		},
+		{
+			name:      "Authorized payment is captured",
+			status:    entity.PaymentStatusAuthorized,
+			reference: "ch_1",
+			result:    entity.ProviderResult{Reference: "ch_2", Status: entity.PaymentStatusCaptured},
+			want:      entity.PaymentStatusCaptured,
+			wantRef:   "ch_1",
+			changed:   true,
+		},
+		{
+			name:      "Same answer twice changes nothing",
+			status:    entity.PaymentStatusCaptured,
+			reference: "ch_1",
+			result:    entity.ProviderResult{Reference: "ch_1", Status: entity.PaymentStatusCaptured},
+			want:      entity.PaymentStatusCaptured,
+			wantRef:   "ch_1",
+			changed:   false,
+		},
+		{
+			name:      "Failed payment cannot be captured",
+			status:    entity.PaymentStatusFailed,
+			reference: "ch_1",
+			result:    entity.ProviderResult{Status: entity.PaymentStatusCaptured},
+			want:      entity.PaymentStatusFailed,
+			wantRef:   "ch_1",
+			wantErr:   entity.ErrInvalidPaymentStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment := entity.Payment{Status: tt.status, Reference: tt.reference}
+
+			changed, err := payment.Apply(tt.result, now)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.changed, changed, "change should be reported correctly")
+			assert.Equal(t, tt.want, payment.Status, "status should be set correctly")
+			assert.Equal(t, tt.wantRef, payment.Reference, "reference should be kept correctly")
+			if tt.changed {
+				assert.Equal(t, &now, payment.UpdatedAt, "update time should be set")
+			} else {
+				assert.Nil(t, payment.UpdatedAt, "update time should be left alone")
+			}
+		})
+	}
+}
+
+func TestPaymentApplyTruncatesFailureReason(t *testing.T) {
+	payment := entity.Payment{Status: entity.PaymentStatusPending}
+
+	_, err := payment.Apply(entity.ProviderResult{Status: entity.PaymentStatusFailed, FailureReason: strings.Repeat("é", 250)}, time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("é", 200), payment.FailureReason, "failure reason should fit the column")
+}
+
+func TestPaymentRequestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    entity.PaymentRequest
+		wantMethod string
+		wantErr    error
+	}{
+		{name: "Wallet by default", request: entity.PaymentRequest{}, wantMethod: entity.PaymentMethodWallet},
+		{name: "Card with a token", request: entity.PaymentRequest{Method: " Card ", CardToken: "tok_visa"}, wantMethod: entity.PaymentMethodCard},
+		{name: "Card without a token", request: entity.PaymentRequest{Method: "card"}, wantMethod: entity.PaymentMethodCard, wantErr: entity.ErrMissingCardToken},
+		{name: "Unknown method", request: entity.PaymentRequest{Method: "cash"}, wantMethod: "cash", wantErr: entity.ErrUnknownPaymentMethod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantMethod, tt.request.GetMethod(), "method should be normalized")
+			assert.ErrorIs(t, tt.request.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestGatewayEventValidate(t *testing.T) {
+	valid := entity.GatewayEvent{EventId: "evt_1", Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusAuthorized}
+	assert.NoError(t, valid.Validate())
+
+	missingReference := valid
+	missingReference.Reference = " "
+	assert.ErrorIs(t, missingReference.Validate(), entity.ErrInvalidGatewayEvent)
+
+	pending := valid
+	pending.Status = entity.PaymentStatusPending
+	assert.ErrorIs(t, pending.Validate(), entity.ErrInvalidGatewayEvent)
+
+	unknown := valid
+	unknown.Status = "settled"
+	assert.ErrorIs(t, unknown.Validate(), entity.ErrInvalidGatewayEvent)
+}
@@ -0,0 +1,430 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"order_service/internal/core"
+	"order_service/pkg"
+	orderEntity "order_service/services/order/entity"
+	"order_service/services/payment/entity"
+	"order_service/services/payment/test/mock"
+	"order_service/services/payment/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type PaymentUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo   *mock.MockPaymentRepository
+	mockWallet *mock.MockPaymentProvider
+	mockCard   *mock.MockPaymentProvider
+	usecase    usecase.PaymentUsecase
+}
+
+func (suite *PaymentUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockPaymentRepository(ctrl)
+	suite.mockWallet = mock.NewMockPaymentProvider(ctrl)
+	suite.mockCard = mock.NewMockPaymentProvider(ctrl)
+	suite.mockWallet.EXPECT().Name().Return(entity.PaymentMethodWallet).AnyTimes()
+	suite.mockCard.EXPECT().Name().Return(entity.PaymentMethodCard).AnyTimes()
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, "secret", suite.mockWallet, suite.mockCard)
+}
+
+// expectCreate makes CreatePayment run the callback against order and hand out id 5.
+func (suite *PaymentUsecaseTestSuite) expectCreate(order *orderEntity.Order, payments []entity.Payment) {
+	suite.mockRepo.EXPECT().CreatePayment(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment, order *orderEntity.Order, payments []entity.Payment) error) error {
+			if err := callbackFn(payment, order, payments); err != nil {
+				return err
+			}
+			payment.SetId(5)
+
+			return nil
+		})
+}
+
+// expectAuthorize makes AuthorizePayment run the callback against order, lockedStatus is the status the
+// payment is found in once it is locked.
+func (suite *PaymentUsecaseTestSuite) expectAuthorize(order *orderEntity.Order, lockedStatus entity.PaymentStatus) {
+	suite.mockRepo.EXPECT().AuthorizePayment(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment, order *orderEntity.Order) (bool, error)) error {
+			payment.Status = lockedStatus
+			_, err := callbackFn(payment, order)
+
+			return err
+		})
+}
+
+// expectUpdates makes UpdatePayment run the callback on the payment it is given, like a locked row.
+func (suite *PaymentUsecaseTestSuite) expectUpdates(times int) {
+	suite.mockRepo.EXPECT().UpdatePayment(gomock.Any(), gomock.Any(), gomock.Any()).Times(times).DoAndReturn(
+		func(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment) (bool, error)) error {
+			_, err := callbackFn(payment)
+
+			return err
+		})
+}
+
+func (suite *PaymentUsecaseTestSuite) TestPay() {
+	order := &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusPending, BaseTotalPrice: core.NewMoney(10000)}
+
+	suite.Run("Wallet payment is captured right away", func() {
+		suite.SetupTest()
+
+		suite.expectCreate(order, nil)
+		suite.mockWallet.EXPECT().Authorize(gomock.Any(), gomock.Any(), "").Return(entity.ProviderResult{Reference: "wallet_5", Status: entity.PaymentStatusAuthorized}, nil)
+		suite.mockWallet.EXPECT().Capture(gomock.Any(), gomock.Any()).Return(entity.ProviderResult{Status: entity.PaymentStatusCaptured}, nil)
+		suite.expectAuthorize(order, entity.PaymentStatusPending)
+		suite.expectUpdates(1)
+
+		payment, err := suite.usecase.Pay(context.Background(), 1, 2, &entity.PaymentRequest{})
+
+		suite.Require().NoError(err)
+		suite.Equal(5, payment.Id)
+		suite.Equal(entity.PaymentStatusCaptured, payment.Status, "payment should be captured")
+		suite.Equal("wallet_5", payment.Reference, "reference should be recorded")
+		suite.Equal(core.NewMoney(10000), payment.Amount, "order total should be charged")
+	})
+
+	suite.Run("Insufficient balance fails the payment", func() {
+		suite.SetupTest()
+
+		suite.expectCreate(order, nil)
+		suite.mockWallet.EXPECT().Authorize(gomock.Any(), gomock.Any(), "").Return(entity.ProviderResult{Reference: "wallet_5", Status: entity.PaymentStatusFailed, FailureReason: entity.ErrInsufficientBalance.Error()}, nil)
+		suite.expectAuthorize(order, entity.PaymentStatusPending)
+
+		payment, err := suite.usecase.Pay(context.Background(), 1, 2, &entity.PaymentRequest{})
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.PaymentStatusFailed, payment.Status)
+		suite.Equal(entity.ErrInsufficientBalance.Error(), payment.FailureReason)
+	})
+
+	suite.Run("Card payment waits for the gateway", func() {
+		suite.SetupTest()
+
+		suite.expectCreate(order, []entity.Payment{{Status: entity.PaymentStatusFailed}})
+		suite.mockCard.EXPECT().Authorize(gomock.Any(), gomock.Any(), "tok_visa").Return(entity.ProviderResult{Reference: "ch_1"}, nil)
+		suite.expectAuthorize(order, entity.PaymentStatusPending)
+
+		payment, err := suite.usecase.Pay(context.Background(), 1, 2, &entity.PaymentRequest{Method: "card", CardToken: "tok_visa"})
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.PaymentStatusPending, payment.Status, "payment should wait for the callback")
+		suite.Equal("ch_1", payment.Reference)
+	})
+
+	suite.Run("Unreachable provider fails the payment", func() {
+		suite.SetupTest()
+
+		suite.expectCreate(order, nil)
+		suite.mockCard.EXPECT().Authorize(gomock.Any(), gomock.Any(), "tok_visa").Return(entity.ProviderResult{}, entity.ErrProviderNotConfigured)
+		suite.expectAuthorize(order, entity.PaymentStatusPending)
+		// the provider may have taken the money before it failed to answer
+		suite.mockCard.EXPECT().Void(gomock.Any(), gomock.Any()).Return(entity.ProviderResult{Status: entity.PaymentStatusVoided}, nil)
+
+		payment, err := suite.usecase.Pay(context.Background(), 1, 2, &entity.PaymentRequest{Method: "card", CardToken: "tok_visa"})
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.PaymentStatusFailed, payment.Status)
+		suite.Equal(entity.ErrProviderNotConfigured.Error(), payment.FailureReason)
+	})
+
+	suite.Run("Order cancelled during the charge gets the money back", func() {
+		suite.SetupTest()
+
+		suite.expectCreate(order, nil)
+		suite.mockWallet.EXPECT().Authorize(gomock.Any(), gomock.Any(), "").Return(entity.ProviderResult{Reference: "wallet_5", Status: entity.PaymentStatusAuthorized}, nil)
+		suite.expectAuthorize(&orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusCancelled}, entity.PaymentStatusPending)
+		suite.mockWallet.EXPECT().Void(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error) {
+			suite.Equal("wallet_5", payment.Reference, "charge should be given back")
+
+			return entity.ProviderResult{Status: entity.PaymentStatusVoided}, nil
+		})
+
+		payment, err := suite.usecase.Pay(context.Background(), 1, 2, &entity.PaymentRequest{})
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.PaymentStatusFailed, payment.Status, "payment of a cancelled order should fail")
+		suite.Equal(entity.ErrOrderNotPayable.Error(), payment.FailureReason)
+	})
+
+	suite.Run("Payment voided during the charge gets the money back", func() {
+		suite.SetupTest()
+
+		suite.expectCreate(order, nil)
+		suite.mockWallet.EXPECT().Authorize(gomock.Any(), gomock.Any(), "").Return(entity.ProviderResult{Reference: "wallet_5", Status: entity.PaymentStatusAuthorized}, nil)
+		suite.expectAuthorize(order, entity.PaymentStatusVoided)
+		suite.mockWallet.EXPECT().Void(gomock.Any(), gomock.Any()).Return(entity.ProviderResult{Status: entity.PaymentStatusVoided}, nil)
+
+		payment, err := suite.usecase.Pay(context.Background(), 1, 2, &entity.PaymentRequest{})
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.PaymentStatusVoided, payment.Status, "voided payment should be left as it is")
+	})
+
+	suite.Run("Money that cannot be given back is an error", func() {
+		suite.SetupTest()
+
+		suite.expectCreate(order, nil)
+		suite.mockWallet.EXPECT().Authorize(gomock.Any(), gomock.Any(), "").Return(entity.ProviderResult{Reference: "wallet_5", Status: entity.PaymentStatusAuthorized}, nil)
+		suite.expectAuthorize(order, entity.PaymentStatusVoided)
+		suite.mockWallet.EXPECT().Void(gomock.Any(), gomock.Any()).Return(entity.ProviderResult{}, errors.New("this is an error"))
+
+		payment, err := suite.usecase.Pay(context.Background(), 1, 2, &entity.PaymentRequest{})
+
+		suite.ErrorIs(err, core.ErrInternalServerError.WithError(entity.ErrCannotReleasePayment.Error()).WithDebug(errors.New("this is an error").Error()))
+		suite.Nil(payment)
+	})
+
+	suite.Run("Authorize repo return an error", func() {
+		suite.SetupTest()
+
+		suite.expectCreate(order, nil)
+		suite.mockWallet.EXPECT().Authorize(gomock.Any(), gomock.Any(), "").Return(entity.ProviderResult{Reference: "wallet_5", Status: entity.PaymentStatusAuthorized}, nil)
+		suite.mockRepo.EXPECT().AuthorizePayment(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("this is an error"))
+
+		payment, err := suite.usecase.Pay(context.Background(), 1, 2, &entity.PaymentRequest{})
+
+		suite.ErrorIs(err, core.ErrInternalServerError.WithError(entity.ErrCannotUpdatePayment.Error()).WithDebug(errors.New("this is an error").Error()))
+		suite.Nil(payment)
+	})
+
+	tests := []struct {
+		name      string
+		request   *entity.PaymentRequest
+		callRepo  bool
+		order     *orderEntity.Order
+		payments  []entity.Payment
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Card without a token",
+			request:   &entity.PaymentRequest{Method: "card"},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrMissingCardToken.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Another user's order",
+			request:   &entity.PaymentRequest{},
+			callRepo:  true,
+			order:     &orderEntity.Order{Id: 1, UserId: 3, Status: orderEntity.OrderStatusPending},
+			wantErr:   core.ErrNotFound.WithError(entity.ErrOrderNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Order is not pending",
+			request:   &entity.PaymentRequest{},
+			callRepo:  true,
+			order:     &orderEntity.Order{Id: 1, UserId: 2, Status: orderEntity.OrderStatusPaid},
+			wantErr:   core.ErrConfict.WithError(entity.ErrOrderNotPayable.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Order already has an open payment",
+			request:   &entity.PaymentRequest{},
+			callRepo:  true,
+			order:     order,
+			payments:  []entity.Payment{{Status: entity.PaymentStatusAuthorized}},
+			wantErr:   core.ErrConfict.WithError(entity.ErrPaymentInProgress.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Order does not exist",
+			request:   &entity.PaymentRequest{},
+			callRepo:  true,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrOrderNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo return an error",
+			request:   &entity.PaymentRequest{},
+			callRepo:  true,
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotCreatePayment.Error()).WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo && tt.repoErr == nil {
+				suite.expectCreate(tt.order, tt.payments)
+			} else if tt.callRepo {
+				suite.mockRepo.EXPECT().CreatePayment(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.repoErr)
+			}
+
+			got, err := suite.usecase.Pay(context.Background(), 1, 2, tt.request)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be returned correctly")
+			}
+			suite.Nil(got)
+		})
+	}
+}
+
+func (suite *PaymentUsecaseTestSuite) TestHandleGatewayEvent() {
+	sign := func(event entity.GatewayEvent) ([]byte, string) {
+		body, err := json.Marshal(event)
+		require.NoError(suite.T(), err)
+
+		return body, pkg.SignHMAC("secret", body)
+	}
+
+	// expectReference serves the payment ch_1 like a locked row
+	expectReference := func(stored *entity.Payment) {
+		suite.mockRepo.EXPECT().UpdatePaymentByReference(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, payment *entity.Payment, callbackFn func(payment *entity.Payment) (bool, error)) error {
+				if payment.Provider != stored.Provider || payment.Reference != stored.Reference {
+					return core.ErrRecordNotFound
+				}
+				*payment = *stored
+				_, err := callbackFn(payment)
+
+				return err
+			})
+	}
+
+	suite.Run("Authorized charge is captured", func() {
+		suite.SetupTest()
+
+		body, signature := sign(entity.GatewayEvent{EventId: "evt_1", Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusAuthorized})
+		expectReference(&entity.Payment{Id: 5, OrderId: 1, Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusPending})
+		suite.mockCard.EXPECT().Capture(gomock.Any(), gomock.Any()).Return(entity.ProviderResult{}, nil)
+		suite.expectUpdates(1)
+
+		payment, err := suite.usecase.HandleGatewayEvent(context.Background(), body, signature)
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.PaymentStatusAuthorized, payment.Status, "capture should be confirmed later")
+	})
+
+	suite.Run("Captured charge is recorded", func() {
+		suite.SetupTest()
+
+		body, signature := sign(entity.GatewayEvent{EventId: "evt_2", Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusCaptured})
+		expectReference(&entity.Payment{Id: 5, OrderId: 1, Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusAuthorized})
+
+		payment, err := suite.usecase.HandleGatewayEvent(context.Background(), body, signature)
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.PaymentStatusCaptured, payment.Status)
+	})
+
+	suite.Run("Late callback is ignored", func() {
+		suite.SetupTest()
+
+		body, signature := sign(entity.GatewayEvent{EventId: "evt_3", Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusAuthorized})
+		expectReference(&entity.Payment{Id: 5, OrderId: 1, Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusVoided})
+
+		payment, err := suite.usecase.HandleGatewayEvent(context.Background(), body, signature)
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.PaymentStatusVoided, payment.Status, "voided payment should stay voided")
+	})
+
+	suite.Run("Unknown charge", func() {
+		suite.SetupTest()
+
+		body, signature := sign(entity.GatewayEvent{EventId: "evt_4", Provider: "card", Reference: "ch_2", Status: entity.PaymentStatusCaptured})
+		expectReference(&entity.Payment{Id: 5, Provider: "card", Reference: "ch_1"})
+
+		_, err := suite.usecase.HandleGatewayEvent(context.Background(), body, signature)
+
+		suite.ErrorIs(err, core.ErrNotFound.WithError(entity.ErrPaymentNotFound.Error()))
+	})
+
+	suite.Run("Invalid signature", func() {
+		suite.SetupTest()
+
+		body, _ := sign(entity.GatewayEvent{EventId: "evt_5", Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusCaptured})
+
+		_, err := suite.usecase.HandleGatewayEvent(context.Background(), body, pkg.SignHMAC("other", body))
+
+		suite.ErrorIs(err, core.ErrUnauthorized.WithError(entity.ErrInvalidSignature.Error()))
+	})
+
+	suite.Run("Pending is not a callback", func() {
+		suite.SetupTest()
+
+		body, signature := sign(entity.GatewayEvent{EventId: "evt_6", Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusPending})
+
+		_, err := suite.usecase.HandleGatewayEvent(context.Background(), body, signature)
+
+		suite.ErrorIs(err, core.ErrBadRequest.WithError(entity.ErrInvalidGatewayEvent.Error()))
+	})
+
+	suite.Run("Webhook without a secret", func() {
+		suite.SetupTest()
+
+		uc := usecase.NewUsecase(suite.mockRepo, "", suite.mockCard)
+		body, signature := sign(entity.GatewayEvent{EventId: "evt_7", Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusCaptured})
+
+		_, err := uc.HandleGatewayEvent(context.Background(), body, signature)
+
+		suite.ErrorIs(err, core.ErrUnauthorized.WithError(entity.ErrWebhookDisabled.Error()))
+	})
+}
+
+func (suite *PaymentUsecaseTestSuite) TestReleaseOrderPayments() {
+	suite.Run("Captured payments are refunded and open ones voided", func() {
+		suite.SetupTest()
+
+		payments := &[]entity.Payment{
+			{Id: 1, OrderId: 1, Provider: "card", Reference: "ch_1", Status: entity.PaymentStatusFailed},
+			{Id: 2, OrderId: 1, Provider: "card", Reference: "ch_2", Status: entity.PaymentStatusAuthorized},
+			{Id: 3, OrderId: 1, Provider: "wallet", Reference: "wallet_3", Status: entity.PaymentStatusCaptured},
+		}
+		suite.mockRepo.EXPECT().GetPayments(gomock.Any(), 1).Return(payments, nil)
+		suite.mockCard.EXPECT().Void(gomock.Any(), &(*payments)[1]).Return(entity.ProviderResult{Status: entity.PaymentStatusVoided}, nil)
+		suite.mockWallet.EXPECT().Refund(gomock.Any(), &(*payments)[2]).Return(entity.ProviderResult{Status: entity.PaymentStatusRefunded}, nil)
+		suite.expectUpdates(2)
+
+		err := suite.usecase.ReleaseOrderPayments(context.Background(), 1)
+
+		suite.Require().NoError(err)
+		suite.Equal(entity.PaymentStatusFailed, (*payments)[0].Status)
+		suite.Equal(entity.PaymentStatusVoided, (*payments)[1].Status)
+		suite.Equal(entity.PaymentStatusRefunded, (*payments)[2].Status)
+	})
+
+	suite.Run("Refund cannot reach the provider", func() {
+		suite.SetupTest()
+
+		payments := &[]entity.Payment{{Id: 3, OrderId: 1, Provider: "wallet", Status: entity.PaymentStatusCaptured}}
+		suite.mockRepo.EXPECT().GetPayments(gomock.Any(), 1).Return(payments, nil)
+		suite.mockWallet.EXPECT().Refund(gomock.Any(), gomock.Any()).Return(entity.ProviderResult{}, errors.New("this is an error"))
+
+		err := suite.usecase.ReleaseOrderPayments(context.Background(), 1)
+
+		suite.Error(err)
+		suite.Equal(entity.PaymentStatusCaptured, (*payments)[0].Status, "captured payment should be left as it was")
+	})
+
+	suite.Run("Repo return an error", func() {
+		suite.SetupTest()
+
+		suite.mockRepo.EXPECT().GetPayments(gomock.Any(), 1).Return(nil, errors.New("this is an error"))
+
+		err := suite.usecase.ReleaseOrderPayments(context.Background(), 1)
+
+		suite.ErrorIs(err, core.ErrInternalServerError.WithError(entity.ErrCannotGetPayments.Error()).WithDebug(errors.New("this is an error").Error()))
+	})
+}
+
+func TestPaymentUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PaymentUsecaseTestSuite))
+}
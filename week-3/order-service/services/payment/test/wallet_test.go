@@ -0,0 +1,90 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	"order_service/services/payment/entity"
+	"order_service/services/payment/repository/wallet"
+	"order_service/services/payment/test/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWalletProviderAuthorize(t *testing.T) {
+	tests := []struct {
+		name      string
+		charged   bool
+		ledgerErr error
+		want      entity.ProviderResult
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Balance covers the order",
+			charged:   true,
+			want:      entity.ProviderResult{Reference: "wallet_5", Status: entity.PaymentStatusAuthorized},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Balance is insufficient",
+			charged:   false,
+			want:      entity.ProviderResult{Reference: "wallet_5", Status: entity.PaymentStatusFailed, FailureReason: entity.ErrInsufficientBalance.Error()},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Ledger cannot be reached",
+			ledgerErr: errors.New("this is an error"),
+			want:      entity.ProviderResult{},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := mock.NewMockLedger(gomock.NewController(t))
+			provider := wallet.NewWalletProvider(ledger)
+
+			ledger.EXPECT().ChargeBalance(gomock.Any(), 2, core.NewMoney(1000), "wallet_5").Return(tt.charged, tt.ledgerErr)
+
+			got, err := provider.Authorize(context.Background(), &entity.Payment{Id: 5, UserId: 2, Amount: core.NewMoney(1000)}, "")
+
+			tt.assertion(t, err)
+			assert.Equal(t, tt.want, got, "result should be returned correctly")
+		})
+	}
+}
+
+func TestWalletProviderGiveBack(t *testing.T) {
+	ledger := mock.NewMockLedger(gomock.NewController(t))
+	provider := wallet.NewWalletProvider(ledger)
+	payment := &entity.Payment{Id: 5, UserId: 2, Reference: "wallet_5", Status: entity.PaymentStatusCaptured, Amount: core.NewMoney(1000)}
+
+	ledger.EXPECT().RefundBalance(gomock.Any(), 2, core.NewMoney(1000), "wallet_5").Return(nil).Times(3)
+
+	got, err := provider.Refund(context.Background(), payment)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PaymentStatusRefunded, got.Status)
+
+	payment.Status = entity.PaymentStatusAuthorized
+	got, err = provider.Void(context.Background(), payment)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PaymentStatusVoided, got.Status)
+
+	// a pending payment may have been charged before it was recorded, the ledger only gives back a charge
+	payment.Status, payment.Reference = entity.PaymentStatusPending, ""
+	got, err = provider.Void(context.Background(), payment)
+	require.NoError(t, err)
+	assert.Equal(t, entity.PaymentStatusVoided, got.Status)
+}
+
+func TestWalletProviderFreeOrder(t *testing.T) {
+	provider := wallet.NewWalletProvider(mock.NewMockLedger(gomock.NewController(t)))
+
+	got, err := provider.Authorize(context.Background(), &entity.Payment{Id: 5, UserId: 2}, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.PaymentStatusAuthorized, got.Status, "free order should not touch the ledger")
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+	"order_service/services/payment/entity"
+)
+
+// PaymentProvider moves the money of a payment. A provider that answers right away returns the new status,
+// an asynchronous one returns the status unchanged and reports the outcome through the payment webhook.
+// A declined payment is a failed result, errors are kept for a provider that could not be reached.
+// It is implemented by the wallet provider and the card gateway.
+type PaymentProvider interface {
+	Name() string
+	// Authorize reserves the amount of payment, source is what the user pays with, e.g. a card token.
+	Authorize(ctx context.Context, payment *entity.Payment, source string) (entity.ProviderResult, error)
+	// Capture takes the authorized amount.
+	Capture(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error)
+	// Refund gives a captured amount back.
+	Refund(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error)
+	// Void lets go of an amount that was not captured yet.
+	Void(ctx context.Context, payment *entity.Payment) (entity.ProviderResult, error)
+}
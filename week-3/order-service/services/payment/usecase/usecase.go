@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"order_service/internal/core"
+	"order_service/pkg"
+	orderEntity "order_service/services/order/entity"
+	"order_service/services/payment/entity"
+	paymentRepo "order_service/services/payment/repository/postgres"
+	"time"
+)
+
+type PaymentUsecase interface {
+	Pay(ctx context.Context, orderId, userId int, data *entity.PaymentRequest) (*entity.Payment, error)
+	CreatePaymentCallback(payment *entity.Payment, order *orderEntity.Order, payments []entity.Payment) error
+	GetOrderPayments(ctx context.Context, orderId int) (*[]entity.Payment, error)
+	HandleGatewayEvent(ctx context.Context, body []byte, signature string) (*entity.Payment, error)
+	ApplyCallback(payment *entity.Payment, result entity.ProviderResult) (bool, error)
+	AuthorizeCallback(payment *entity.Payment, order *orderEntity.Order, result entity.ProviderResult) (bool, error)
+	ReleaseOrderPayments(ctx context.Context, orderId int) error
+}
+
+type paymentUsecase struct {
+	repo          paymentRepo.PaymentRepository
+	providers     map[string]PaymentProvider
+	webhookSecret string
+}
+
+// NewUsecase builds the payment usecase, every provider serves the payment method of its name. Gateway
+// callbacks have to be signed with webhookSecret, without a secret every callback is turned down.
+func NewUsecase(repo paymentRepo.PaymentRepository, webhookSecret string, providers ...PaymentProvider) PaymentUsecase {
+	byName := make(map[string]PaymentProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	return &paymentUsecase{
+		repo,
+		byName,
+		webhookSecret,
+	}
+}
+
+// Pay starts a payment of the whole order, a payment the provider authorizes right away is captured at once.
+// A declined payment comes back failed without an error, the order can be paid again.
+func (uc *paymentUsecase) Pay(ctx context.Context, orderId, userId int, data *entity.PaymentRequest) (*entity.Payment, error) {
+	if err := data.Validate(); err != nil {
+		return nil, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	provider, ok := uc.providers[data.GetMethod()]
+	if !ok {
+		return nil, core.ErrBadRequest.WithError(entity.ErrProviderNotConfigured.Error())
+	}
+
+	payment := entity.NewPayment(orderId, userId, provider.Name(), time.Now())
+
+	err := uc.repo.CreatePayment(ctx, &payment, uc.CreatePaymentCallback)
+	if err != nil {
+		switch err {
+		case core.ErrRecordNotFound, entity.ErrOrderNotFound:
+			return nil, core.ErrNotFound.WithError(entity.ErrOrderNotFound.Error())
+		case entity.ErrOrderNotPayable, entity.ErrPaymentInProgress:
+			return nil, core.ErrConfict.WithError(err.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotCreatePayment.Error()).WithDebug(err.Error())
+	}
+
+	// the provider is asked outside of any transaction and its answer is recorded under the lock of the
+	// order. A cancellation that came first leaves the payment failed or voided, the money taken for it
+	// is given back here, and one that comes after finds the payment to give back itself
+	result, authErr := provider.Authorize(ctx, &payment, data.CardToken)
+	if authErr != nil {
+		result = entity.ProviderResult{Status: entity.PaymentStatusFailed, FailureReason: authErr.Error()}
+	}
+	// a provider that failed to answer may still have taken the money
+	mayBeTaken := authErr != nil || result.Status != entity.PaymentStatusFailed
+
+	err = uc.repo.AuthorizePayment(ctx, &payment, func(payment *entity.Payment, order *orderEntity.Order) (bool, error) {
+		return uc.AuthorizeCallback(payment, order, result)
+	})
+	if err != nil {
+		// the payment stays pending, voiding it when the order is cancelled gives back what was taken
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotUpdatePayment.Error()).WithDebug(err.Error())
+	}
+
+	if mayBeTaken && (payment.Status == entity.PaymentStatusFailed || payment.Status == entity.PaymentStatusVoided) {
+		taken := payment
+		taken.Reference, taken.Status = result.Reference, entity.PaymentStatusPending
+
+		_, err = provider.Void(ctx, &taken)
+		if err != nil {
+			return nil, core.ErrInternalServerError.WithError(entity.ErrCannotReleasePayment.Error()).WithDebug(err.Error())
+		}
+	}
+
+	if payment.Status == entity.PaymentStatusAuthorized {
+		err = uc.settle(ctx, &payment, func() (entity.ProviderResult, error) {
+			return provider.Capture(ctx, &payment)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &payment, nil
+}
+
+// CreatePaymentCallback charges the order's total to a new payment, a pending order is paid by one
+// payment at a time.
+func (uc *paymentUsecase) CreatePaymentCallback(payment *entity.Payment, order *orderEntity.Order, payments []entity.Payment) error {
+	if payment == nil || order == nil {
+		return entity.ErrInvalidMemory
+	}
+
+	if order.GetUserIdSafe() != payment.UserId {
+		return entity.ErrOrderNotFound
+	}
+
+	if order.GetStatusSafe() != orderEntity.OrderStatusPending {
+		return entity.ErrOrderNotPayable
+	}
+
+	for _, existing := range payments {
+		if existing.Status.IsOpen() || existing.Status == entity.PaymentStatusCaptured {
+			return entity.ErrPaymentInProgress
+		}
+	}
+
+	payment.SetAmount(order.GetBaseTotalPriceSafe())
+
+	return nil
+}
+
+func (uc *paymentUsecase) GetOrderPayments(ctx context.Context, orderId int) (*[]entity.Payment, error) {
+	payments, err := uc.repo.GetPayments(ctx, orderId)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetPayments.Error()).WithDebug(err.Error())
+	}
+
+	return payments, nil
+}
+
+// HandleGatewayEvent records a card gateway's callback and returns the payment it is about. body has to be
+// signed with the webhook secret, signature is its hex encoded HMAC-SHA256. An authorized payment is
+// captured right away, a callback the payment already moved past is accepted and ignored.
+func (uc *paymentUsecase) HandleGatewayEvent(ctx context.Context, body []byte, signature string) (*entity.Payment, error) {
+	if uc.webhookSecret == "" {
+		return nil, core.ErrUnauthorized.WithError(entity.ErrWebhookDisabled.Error())
+	}
+
+	if !pkg.VerifyHMAC(uc.webhookSecret, body, signature) {
+		return nil, core.ErrUnauthorized.WithError(entity.ErrInvalidSignature.Error())
+	}
+
+	var data entity.GatewayEvent
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, core.ErrBadRequest.WithError(entity.ErrInvalidGatewayEvent.Error()).WithDebug(err.Error())
+	}
+
+	if err := data.Validate(); err != nil {
+		return nil, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	provider, ok := uc.providers[data.Provider]
+	if !ok {
+		return nil, core.ErrBadRequest.WithError(entity.ErrProviderNotConfigured.Error())
+	}
+
+	payment := entity.Payment{Provider: data.Provider, Reference: data.Reference}
+	changed := false
+
+	err := uc.repo.UpdatePaymentByReference(ctx, &payment, func(payment *entity.Payment) (bool, error) {
+		var err error
+
+		changed, err = uc.ApplyCallback(payment, data.ToResult())
+
+		return changed, err
+	})
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return nil, core.ErrNotFound.WithError(entity.ErrPaymentNotFound.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotUpdatePayment.Error()).WithDebug(err.Error())
+	}
+
+	if changed && payment.Status == entity.PaymentStatusAuthorized {
+		err = uc.settle(ctx, &payment, func() (entity.ProviderResult, error) {
+			return provider.Capture(ctx, &payment)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &payment, nil
+}
+
+// ApplyCallback records a provider's answer on a locked payment, an answer that came after the payment
+// moved on is ignored.
+func (uc *paymentUsecase) ApplyCallback(payment *entity.Payment, result entity.ProviderResult) (bool, error) {
+	changed, err := payment.Apply(result, time.Now())
+	if err == entity.ErrInvalidPaymentStatus {
+		return false, nil
+	}
+
+	return changed, err
+}
+
+// AuthorizeCallback records the provider's answer to the authorization of a pending payment of a locked
+// order. An order that is no longer pending fails the payment, a payment that was voided meanwhile is left
+// as it is.
+func (uc *paymentUsecase) AuthorizeCallback(payment *entity.Payment, order *orderEntity.Order, result entity.ProviderResult) (bool, error) {
+	if payment == nil || order == nil {
+		return false, entity.ErrInvalidMemory
+	}
+
+	if payment.Status != entity.PaymentStatusPending {
+		return false, nil
+	}
+
+	if order.GetStatusSafe() != orderEntity.OrderStatusPending {
+		return uc.ApplyCallback(payment, entity.ProviderResult{Status: entity.PaymentStatusFailed, FailureReason: entity.ErrOrderNotPayable.Error()})
+	}
+
+	return uc.ApplyCallback(payment, result)
+}
+
+// ReleaseOrderPayments gives the money of a cancelled order back, captured payments are refunded and the
+// ones still open are voided.
+func (uc *paymentUsecase) ReleaseOrderPayments(ctx context.Context, orderId int) error {
+	payments, err := uc.repo.GetPayments(ctx, orderId)
+	if err != nil {
+		return core.ErrInternalServerError.WithError(entity.ErrCannotGetPayments.Error()).WithDebug(err.Error())
+	}
+
+	for idx := range *payments {
+		payment := &(*payments)[idx]
+
+		provider, ok := uc.providers[payment.Provider]
+		if !ok {
+			return core.ErrInternalServerError.WithError(entity.ErrCannotReleasePayment.Error()).WithDebug(entity.ErrProviderNotConfigured.Error())
+		}
+
+		var release func() (entity.ProviderResult, error)
+
+		switch {
+		case payment.Status == entity.PaymentStatusCaptured:
+			release = func() (entity.ProviderResult, error) { return provider.Refund(ctx, payment) }
+		case payment.Status.IsOpen():
+			release = func() (entity.ProviderResult, error) { return provider.Void(ctx, payment) }
+		default:
+			continue
+		}
+
+		err = uc.settle(ctx, payment, release)
+		if err != nil {
+			return core.ErrInternalServerError.WithError(entity.ErrCannotReleasePayment.Error()).WithDebug(err.Error())
+		}
+	}
+
+	return nil
+}
+
+// settle asks the provider through call and records its answer on payment. A provider that cannot be
+// reached fails a payment that has not taken any money yet, otherwise the payment is left as it was.
+func (uc *paymentUsecase) settle(ctx context.Context, payment *entity.Payment, call func() (entity.ProviderResult, error)) error {
+	result, callErr := call()
+	if callErr != nil {
+		if payment.Status != entity.PaymentStatusPending {
+			return core.ErrInternalServerError.WithError(entity.ErrCannotUpdatePayment.Error()).WithDebug(callErr.Error())
+		}
+
+		result = entity.ProviderResult{Status: entity.PaymentStatusFailed, FailureReason: callErr.Error()}
+	}
+
+	err := uc.repo.UpdatePayment(ctx, payment, func(payment *entity.Payment) (bool, error) {
+		return uc.ApplyCallback(payment, result)
+	})
+	if err != nil {
+		return core.ErrInternalServerError.WithError(entity.ErrCannotUpdatePayment.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
@@ -1,18 +1,24 @@
 package entity
 
-import "time"
+import (
+	"order_service/internal/core"
+	"time"
+)
 
+// Product Quantity is the stock still available for new orders, ReservedQuantity is held by unpaid orders.
 type Product struct {
-	Id        int        `json:"id"`
-	Name      string     `json:"name"`
-	ImageURL  string     `json:"image_url"`
-	Quantity  int        `json:"quantity"`
-	Price     float32    `json:"price"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at"`
+	Id               int        `json:"id"`
+	Name             string     `json:"name"`
+	ImageURL         string     `json:"image_url"`
+	Quantity         int        `json:"quantity"`
+	ReservedQuantity int        `json:"reserved_quantity"`
+	Price            core.Money `json:"price" swaggertype:"number"`
+	TaxClass         string     `json:"tax_class"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        *time.Time `json:"updated_at"`
 }
 
-func NewProduct(id int, name, imageURl string, quantity int, price float32) Product {
+func NewProduct(id int, name, imageURl string, quantity int, price core.Money) Product {
 	return Product{
 		Id:        id,
 		Name:      name,
@@ -36,6 +42,12 @@ func (product *Product) SetQuantity(q int) {
 	}
 }
 
+func (product *Product) SetTaxClass(class string) {
+	if product != nil {
+		product.TaxClass = class
+	}
+}
+
 func (product Product) GetId() int {
 	return product.Id
 }
@@ -48,6 +60,10 @@ func (product Product) GetQuantity() int {
 	return product.Quantity
 }
 
-func (product Product) GetPrice() float32 {
+func (product Product) GetPrice() core.Money {
 	return product.Price
 }
+
+func (product Product) GetTaxClass() string {
+	return product.TaxClass
+}
@@ -2,13 +2,16 @@ package entity
 
 import (
 	"net/http"
+	"order_service/internal/core"
 )
 
 type ProductRequest struct {
-	Name     string  `json:"name"`
-	Image    []byte  `json:"image"`
-	Quantity int     `json:"quantity"`
-	Price    float32 `json:"price"`
+	Name     string     `json:"name"`
+	Image    []byte     `json:"image"`
+	Quantity int        `json:"quantity"`
+	Price    core.Money `json:"price" swaggertype:"number"`
+	// TaxClass picks the tax rules of the product, "standard" when empty
+	TaxClass string `json:"tax_class"`
 }
 
 func (product *ProductRequest) Validate() error {
@@ -17,16 +17,19 @@ type ProductRepository interface {
 	GetProducts(ctx context.Context) (*[]entity.Product, error)
 	SearchProducts(ctx context.Context, searchStr string) (*[]entity.Product, error)
 	GetProduct(ctx context.Context, productID int) (*entity.Product, error)
+	GetProductsByIds(ctx context.Context, productIds []int) (*[]entity.Product, error)
 	UpdateProduct(ctx context.Context, productID int, data entity.Product) error
 	DeleteProduct(ctx context.Context, productID int) error
 }
 
+// quantity is the stock on hand, products are read with the part held by unpaid orders taken out
 const (
-	QUERY_INSERT_PRODUCT          = "INSERT INTO products (name, image_url, quantity, price) VALUES ($1, $2, $3, $4)"
-	QUERY_GET_PRODUCTS            = "SELECT id, name, quantity, price, created_at, updated_at FROM products"
-	QUERY_SEARCH_PRODUCTS_BY_NAME = "SELECT id, name, quantity, price, created_at, updated_at FROM products WHERE name ILIKE $1"
-	QUERY_GET_PRODUCT_BY_ID       = "SELECT id, name, image_url, quantity, price, created_at, updated_at FROM products WHERE id = $1"
-	QUERY_UPDATE_PRODUCT_BY_ID    = "UPDATE products SET name = COALESCE($2, name), image_url = COALESCE($3, image_url), quantity = COALESCE($4, quantity), price = COALESCE($5, price), updated_at = $6 WHERE id = $1"
+	QUERY_INSERT_PRODUCT          = "INSERT INTO products (name, image_url, quantity, price, tax_class) VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'standard'))"
+	QUERY_GET_PRODUCTS            = "SELECT id, name, quantity - reserved_quantity, reserved_quantity, price, tax_class, created_at, updated_at FROM products"
+	QUERY_SEARCH_PRODUCTS_BY_NAME = "SELECT id, name, quantity - reserved_quantity, reserved_quantity, price, tax_class, created_at, updated_at FROM products WHERE name ILIKE $1"
+	QUERY_GET_PRODUCTS_BY_IDS     = "SELECT id, name, quantity - reserved_quantity, reserved_quantity, price, tax_class, created_at, updated_at FROM products WHERE id = ANY($1) ORDER BY id"
+	QUERY_GET_PRODUCT_BY_ID       = "SELECT id, name, image_url, quantity - reserved_quantity, reserved_quantity, price, tax_class, created_at, updated_at FROM products WHERE id = $1"
+	QUERY_UPDATE_PRODUCT_BY_ID    = "UPDATE products SET name = COALESCE($2, name), image_url = COALESCE($3, image_url), quantity = COALESCE($4 + reserved_quantity, quantity), price = COALESCE($5, price), tax_class = COALESCE($7, tax_class), updated_at = $6 WHERE id = $1"
 	QUERY_DELETE_PRODUCT_BY_ID    = "DELETE FROM products WHERE id = $1"
 )
 
@@ -41,7 +44,7 @@ func NewProductRepo(db *pgxpool.Pool) ProductRepository {
 }
 
 func (repo *postgresRepo) CreateProduct(ctx context.Context, data entity.Product) error {
-	_, err := repo.db.Exec(ctx, QUERY_INSERT_PRODUCT, data.Name, data.ImageURL, data.Quantity, data.Price)
+	_, err := repo.db.Exec(ctx, QUERY_INSERT_PRODUCT, data.Name, data.ImageURL, data.Quantity, data.Price, data.TaxClass)
 	if err != nil {
 		return err
 	}
@@ -56,7 +59,7 @@ func (repo *postgresRepo) GetProducts(ctx context.Context) (*[]entity.Product, e
 	datas, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Product, error) {
 		var data entity.Product
 
-		err := row.Scan(&data.Id, &data.Name, &data.Quantity, &data.Price, &data.CreatedAt, &data.UpdatedAt)
+		err := row.Scan(&data.Id, &data.Name, &data.Quantity, &data.ReservedQuantity, &data.Price, &data.TaxClass, &data.CreatedAt, &data.UpdatedAt)
 		if err != nil {
 			return entity.Product{}, err
 		}
@@ -70,13 +73,33 @@ func (repo *postgresRepo) GetProducts(ctx context.Context) (*[]entity.Product, e
 	return &datas, nil
 }
 
+func (repo *postgresRepo) GetProductsByIds(ctx context.Context, productIds []int) (*[]entity.Product, error) {
+	rows, _ := repo.db.Query(ctx, QUERY_GET_PRODUCTS_BY_IDS, productIds)
+
+	products, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Product, error) {
+		var product entity.Product
+
+		err := row.Scan(&product.Id, &product.Name, &product.Quantity, &product.ReservedQuantity, &product.Price, &product.TaxClass, &product.CreatedAt, &product.UpdatedAt)
+		if err != nil {
+			return entity.Product{}, err
+		}
+
+		return product, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &products, nil
+}
+
 func (repo *postgresRepo) SearchProducts(ctx context.Context, searchStr string) (*[]entity.Product, error) {
 	rows, _ := repo.db.Query(ctx, QUERY_SEARCH_PRODUCTS_BY_NAME, fmt.Sprintf(`%%%s%%`, searchStr))
 
 	products, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Product, error) {
 		var product entity.Product
 
-		err := row.Scan(&product.Id, &product.Name, &product.Quantity, &product.Price, &product.CreatedAt, &product.UpdatedAt)
+		err := row.Scan(&product.Id, &product.Name, &product.Quantity, &product.ReservedQuantity, &product.Price, &product.TaxClass, &product.CreatedAt, &product.UpdatedAt)
 		if err != nil {
 			return entity.Product{}, err
 		}
@@ -96,7 +119,7 @@ func (repo *postgresRepo) SearchProducts(ctx context.Context, searchStr string)
 func (repo *postgresRepo) GetProduct(ctx context.Context, productID int) (*entity.Product, error) {
 	var data entity.Product
 
-	err := repo.db.QueryRow(ctx, QUERY_GET_PRODUCT_BY_ID, productID).Scan(&data.Id, &data.Name, &data.ImageURL, &data.Quantity, &data.Price, &data.CreatedAt, &data.UpdatedAt)
+	err := repo.db.QueryRow(ctx, QUERY_GET_PRODUCT_BY_ID, productID).Scan(&data.Id, &data.Name, &data.ImageURL, &data.Quantity, &data.ReservedQuantity, &data.Price, &data.TaxClass, &data.CreatedAt, &data.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, core.ErrRecordNotFound
@@ -111,7 +134,8 @@ func (repo *postgresRepo) UpdateProduct(ctx context.Context, productID int, data
 	newName := pgtype.Text{Valid: false}
 	newUrl := pgtype.Text{Valid: false}
 	newQuantity := pgtype.Int4{Valid: false}
-	newPrice := pgtype.Float4{Valid: false}
+	newPrice := pgtype.Numeric{Valid: false}
+	newTaxClass := pgtype.Text{Valid: false}
 
 	if data.Name != "" {
 		newName = pgtype.Text{String: data.Name, Valid: true}
@@ -125,11 +149,15 @@ func (repo *postgresRepo) UpdateProduct(ctx context.Context, productID int, data
 		newQuantity = pgtype.Int4{Int32: int32(data.Quantity), Valid: true}
 	}
 
-	if data.Price != 0.0 {
-		newPrice = pgtype.Float4{Float32: data.Price, Valid: true}
+	if data.Price != 0 {
+		newPrice, _ = data.Price.NumericValue()
+	}
+
+	if data.TaxClass != "" {
+		newTaxClass = pgtype.Text{String: data.TaxClass, Valid: true}
 	}
 
-	_, err := repo.db.Exec(ctx, QUERY_UPDATE_PRODUCT_BY_ID, productID, newName, newUrl, newQuantity, newPrice, time.Now())
+	_, err := repo.db.Exec(ctx, QUERY_UPDATE_PRODUCT_BY_ID, productID, newName, newUrl, newQuantity, newPrice, time.Now(), newTaxClass)
 	if err != nil {
 		fmt.Println("product update err", err)
 		return err
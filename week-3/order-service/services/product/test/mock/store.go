@@ -98,6 +98,21 @@ func (mr *MockProductRepositoryMockRecorder) GetProducts(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProducts", reflect.TypeOf((*MockProductRepository)(nil).GetProducts), ctx)
 }
 
+// GetProductsByIds mocks base method.
+func (m *MockProductRepository) GetProductsByIds(ctx context.Context, productIds []int) (*[]entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductsByIds", ctx, productIds)
+	ret0, _ := ret[0].(*[]entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductsByIds indicates an expected call of GetProductsByIds.
+func (mr *MockProductRepositoryMockRecorder) GetProductsByIds(ctx, productIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsByIds", reflect.TypeOf((*MockProductRepository)(nil).GetProductsByIds), ctx, productIds)
+}
+
 // SearchProducts mocks base method.
 func (m *MockProductRepository) SearchProducts(ctx context.Context, searchStr string) (*[]entity.Product, error) {
 	m.ctrl.T.Helper()
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"order_service/internal/core"
 	"order_service/services/product/entity"
 	"testing"
 	"time"
@@ -19,19 +20,19 @@ func (suite *ProductTestSuite) SetupTest() {
 		Id:        1,
 		Name:      "orange",
 		Quantity:  10,
-		Price:     2.5,
+		Price:     core.NewMoney(250),
 		CreatedAt: time.Now(),
 	}
 }
 
 func (suite *ProductTestSuite) TestNewProduct() {
-	product := entity.NewProduct(1, "orange", "imageLink", 10, 2.5)
+	product := entity.NewProduct(1, "orange", "imageLink", 10, core.NewMoney(250))
 
 	suite.Equal(1, product.Id, "Id should be set correctly")
 	suite.Equal("orange", product.Name, "Name should be set correctly")
 	suite.Equal("imageLink", product.ImageURL, "ImageURL should be set correctly")
 	suite.Equal(10, product.Quantity, "Quantity should be set correctly")
-	suite.Equal(float32(2.5), product.Price, "Price should be set correctly")
+	suite.Equal(core.NewMoney(250), product.Price, "Price should be set correctly")
 }
 
 func (suite *ProductTestSuite) TestSetId() {
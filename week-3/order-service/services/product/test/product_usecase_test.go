@@ -30,7 +30,7 @@ func (suite *ProductUsecaseTestSuite) SetupTest() {
 			Id:        1,
 			Name:      "orange",
 			Quantity:  10,
-			Price:     2.5,
+			Price:     core.NewMoney(250),
 			CreatedAt: time.Now(),
 		},
 		{
@@ -64,7 +64,7 @@ func (suite *ProductUsecaseTestSuite) TestCreateProduct() {
 			data: &entity.ProductRequest{
 				Name:     "orange",
 				Quantity: 25,
-				Price:    2.5,
+				Price:    core.NewMoney(250),
 			},
 			repoErr:   nil,
 			want:      nil,
@@ -210,7 +210,7 @@ func (suite *ProductUsecaseTestSuite) TestUpdateProduct() {
 			data: &entity.ProductRequest{
 				Name:     "apple",
 				Quantity: 25,
-				Price:    5.5,
+				Price:    core.NewMoney(550),
 			},
 			repoErr:   nil,
 			want:      nil,
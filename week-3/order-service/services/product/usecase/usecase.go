@@ -13,6 +13,7 @@ type ProductUsecase interface {
 	GetProducts(ctx context.Context) (*[]entity.Product, error)
 	SearchProducts(ctx context.Context, nameQuery string) (*[]entity.Product, error)
 	GetProduct(ctx context.Context, productID int) (*entity.Product, error)
+	GetProductsByIds(ctx context.Context, productIds []int) (*[]entity.Product, error)
 	UpdateProduct(ctx context.Context, productID int, data *entity.ProductRequest) error
 	DeleteProduct(ctx context.Context, productID int) error
 }
@@ -47,6 +48,7 @@ func (uc *productUsecase) CreateProduct(ctx context.Context, data *entity.Produc
 	}
 
 	newProduct := entity.NewProduct(0, data.Name, imageUrl, data.Quantity, data.Price)
+	newProduct.SetTaxClass(data.TaxClass)
 
 	err = uc.repo.CreateProduct(ctx, newProduct)
 	if err != nil {
@@ -95,6 +97,16 @@ func (uc *productUsecase) GetProduct(ctx context.Context, productID int) (*entit
 	return product, nil
 }
 
+// GetProductsByIds returns the products that still exist among productIds, missing ones are left out.
+func (uc *productUsecase) GetProductsByIds(ctx context.Context, productIds []int) (*[]entity.Product, error) {
+	products, err := uc.repo.GetProductsByIds(ctx, productIds)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return products, nil
+}
+
 func (uc *productUsecase) UpdateProduct(ctx context.Context, productID int, data *entity.ProductRequest) error {
 	requester := core.GetRequester(ctx)
 	uid, err := core.DecomposeUID(requester.GetSubject())
@@ -123,6 +135,7 @@ func (uc *productUsecase) UpdateProduct(ctx context.Context, productID int, data
 	}
 
 	updatedProduct := entity.NewProduct(productID, data.Name, imageUrl, data.Quantity, data.Price)
+	updatedProduct.SetTaxClass(data.TaxClass)
 	err = uc.repo.UpdateProduct(ctx, productID, updatedProduct)
 	if err != nil {
 		return core.ErrInternalServerError.WithError(entity.ErrCannotUpdate.Error()).WithDebug(err.Error())
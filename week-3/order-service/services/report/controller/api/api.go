@@ -0,0 +1,356 @@
+package api
+
+import (
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/report/entity"
+	reportUc "order_service/services/report/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReportService interface {
+	CreateJob(*fiber.Ctx) error
+	GetJob(*fiber.Ctx) error
+	DownloadJob(*fiber.Ctx) error
+	CancelJob(*fiber.Ctx) error
+	RetryJob(*fiber.Ctx) error
+	CreateSchedule(*fiber.Ctx) error
+	GetSchedules(*fiber.Ctx) error
+	UpdateSchedule(*fiber.Ctx) error
+	DeleteSchedule(*fiber.Ctx) error
+}
+
+type service struct {
+	usecase reportUc.ReportUsecase
+}
+
+func NewService(uc reportUc.ReportUsecase) ReportService {
+	return &service{
+		usecase: uc,
+	}
+}
+
+// Create Report Job godoc
+// @summary Create Report Job
+// @description Queue the generation of an orders summary (xlsx by default, or csv, ndjson or ods) or an order invoice pdf, poll the job for its file. Only admin can export the orders summary
+// @tags reports
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param payload body entity.JobRequest true "Report job request body"
+// @success 202 {object} entity.Job
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /reports/ [post]
+func (srv *service) CreateJob(c *fiber.Ctx) error {
+	var data entity.JobRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidJobType.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	job, err := srv.usecase.CreateJob(ctx, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(core.ResponseData(job))
+}
+
+// Get Report Job godoc
+// @summary Get Report Job
+// @description Get the status of a report job of the current user, the file of a succeeded job is downloaded from /reports/:reportID/download
+// @tags reports
+// @produce json
+// @security BearerAuth
+// @param reportID path int true "Report job's ID"
+// @success 200 {object} entity.Job
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /reports/:reportID [get]
+func (srv *service) GetJob(c *fiber.Ctx) error {
+	jobId, err := c.ParamsInt("reportID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	job, err := srv.usecase.GetJob(ctx, jobId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(job))
+}
+
+// Download Report godoc
+// @summary Download Report
+// @description Download the file of a succeeded report job of the current user
+// @tags reports
+// @produce application/pdf,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,text/csv,application/x-ndjson,application/vnd.oasis.opendocument.spreadsheet
+// @security BearerAuth
+// @param reportID path int true "Report job's ID"
+// @success 200 {file} file
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /reports/:reportID/download [get]
+func (srv *service) DownloadJob(c *fiber.Ctx) error {
+	jobId, err := c.ParamsInt("reportID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	job, err := srv.usecase.GetJobDocument(ctx, jobId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	err = pkg.SendDocument(c, job.FileName, job.GetDownloadNameSafe(), false)
+	if err != nil {
+		if err == pkg.ErrDocumentNotFound {
+			return pkg.WriteResponse(c, core.ErrNotFound.WithError(err.Error()))
+		}
+
+		return pkg.WriteResponse(c, core.ErrInternalServerError.WithDebug(err.Error()))
+	}
+
+	return nil
+}
+
+// Cancel Report Job godoc
+// @summary Cancel Report Job
+// @description Cancel a queued or running report job of the current user
+// @tags reports
+// @produce json
+// @security BearerAuth
+// @param reportID path int true "Report job's ID"
+// @success 200 {object} entity.Job
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /reports/:reportID/cancel [post]
+func (srv *service) CancelJob(c *fiber.Ctx) error {
+	jobId, err := c.ParamsInt("reportID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	job, err := srv.usecase.CancelJob(ctx, jobId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(job))
+}
+
+// Retry Report Job godoc
+// @summary Retry Report Job
+// @description Queue a dead or cancelled report job of the current user again
+// @tags reports
+// @produce json
+// @security BearerAuth
+// @param reportID path int true "Report job's ID"
+// @success 200 {object} entity.Job
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /reports/:reportID/retry [post]
+func (srv *service) RetryJob(c *fiber.Ctx) error {
+	jobId, err := c.ParamsInt("reportID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	job, err := srv.usecase.RetryJob(ctx, jobId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(job))
+}
+
+// Create Report Schedule godoc
+// @summary Create Report Schedule
+// @description Mail the orders summary of the days before every run to the recipients, runs follow a 5 field cron read in the timezone of the schedule (UTC by default). The summary covers 7 days and is an xlsx file by default, only admin can do this action
+// @tags reports
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param payload body entity.ScheduleRequest true "Report schedule request body"
+// @success 201 {object} entity.Schedule
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /reports/schedules [post]
+func (srv *service) CreateSchedule(c *fiber.Ctx) error {
+	var data entity.ScheduleRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(pkg.ErrInvalidCron.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	schedule, err := srv.usecase.CreateSchedule(ctx, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(core.ResponseData(schedule))
+}
+
+// Get Report Schedules godoc
+// @summary Get Report Schedules
+// @description Get the report schedules of the current admin
+// @tags reports
+// @produce json
+// @security BearerAuth
+// @success 200 {array} entity.Schedule
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /reports/schedules [get]
+func (srv *service) GetSchedules(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	schedules, err := srv.usecase.GetSchedules(ctx)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(schedules))
+}
+
+// Update Report Schedule godoc
+// @summary Update Report Schedule
+// @description Replace a report schedule of the current admin, its next run is worked out again. Leaving active out keeps it as it is
+// @tags reports
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param scheduleID path int true "Report schedule's ID"
+// @param payload body entity.ScheduleRequest true "Report schedule request body"
+// @success 200 {object} entity.Schedule
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /reports/schedules/:scheduleID [put]
+func (srv *service) UpdateSchedule(c *fiber.Ctx) error {
+	scheduleId, err := c.ParamsInt("scheduleID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	var data entity.ScheduleRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(pkg.ErrInvalidCron.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	schedule, err := srv.usecase.UpdateSchedule(ctx, scheduleId, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(schedule))
+}
+
+// Delete Report Schedule godoc
+// @summary Delete Report Schedule
+// @description Delete a report schedule of the current admin, the jobs it already queued keep going
+// @tags reports
+// @security BearerAuth
+// @param scheduleID path int true "Report schedule's ID"
+// @success 204
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /reports/schedules/:scheduleID [delete]
+func (srv *service) DeleteSchedule(c *fiber.Ctx) error {
+	scheduleId, err := c.ParamsInt("scheduleID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err = srv.usecase.DeleteSchedule(ctx, scheduleId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).JSON(core.ResponseData(true))
+}
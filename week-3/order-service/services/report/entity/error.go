@@ -0,0 +1,30 @@
+package entity
+
+import "errors"
+
+var (
+	ErrInvalidJobType       = errors.New("report type must be summarize or invoice")
+	ErrInvalidDateRange     = errors.New("start date and end date are required and start date cannot be after end date")
+	ErrInvalidOrderId       = errors.New("invoice report needs an order id")
+	ErrSummarizeAdminOnly   = errors.New("only admin can export the orders summary")
+	ErrJobNotFound          = errors.New("report job cannot be found")
+	ErrJobNotCancellable    = errors.New("only queued or running report job can be cancelled")
+	ErrJobNotRetriable      = errors.New("only dead or cancelled report job can be retried")
+	ErrJobNotRunning        = errors.New("report job is no longer running")
+	ErrJobNotReady          = errors.New("report job has not produced its file yet")
+	ErrJobTimedOut          = errors.New("report job did not finish in time")
+	ErrCannotCreateJob      = errors.New("report job cannot be create")
+	ErrCannotGetJob         = errors.New("report job cannot be get")
+	ErrCannotUpdateJob      = errors.New("report job cannot be update")
+	ErrInvalidRangeDays     = errors.New("range days must be between 1 and 366")
+	ErrInvalidRecipients    = errors.New("schedule needs between 1 and 20 valid email recipients")
+	ErrInvalidScheduleName  = errors.New("schedule name cannot have more than 100 characters")
+	ErrScheduleNeverRuns    = errors.New("cron never matches a date")
+	ErrScheduleAdminOnly    = errors.New("only admin can schedule reports")
+	ErrScheduleNotFound     = errors.New("report schedule cannot be found")
+	ErrCannotCreateSchedule = errors.New("report schedule cannot be create")
+	ErrCannotGetSchedules   = errors.New("report schedules cannot be get")
+	ErrCannotUpdateSchedule = errors.New("report schedule cannot be update")
+	ErrCannotDeleteSchedule = errors.New("report schedule cannot be delete")
+	ErrCannotQueueSchedules = errors.New("due report schedules cannot be queued")
+)
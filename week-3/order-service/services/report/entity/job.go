@@ -0,0 +1,203 @@
+package entity
+
+import (
+	"fmt"
+	"order_service/pkg"
+	"path/filepath"
+	"time"
+)
+
+type JobType string
+
+const (
+	JobTypeSummarize JobType = "summarize"
+	JobTypeInvoice   JobType = "invoice"
+)
+
+func (t JobType) IsValid() bool {
+	return t == JobTypeSummarize || t == JobTypeInvoice
+}
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusCancelled JobStatus = "cancelled"
+	JobStatusDead      JobStatus = "dead"
+)
+
+// JobParams are what the report is built from, StartDate, EndDate and Format for a summarize report and
+// OrderId for an invoice. A job queued by a schedule mails its file to Recipients.
+type JobParams struct {
+	StartDate  *time.Time       `json:"start_date,omitempty"`
+	EndDate    *time.Time       `json:"end_date,omitempty"`
+	Format     pkg.ReportFormat `json:"format,omitempty"`
+	OrderId    int              `json:"order_id,omitempty"`
+	ScheduleId int              `json:"schedule_id,omitempty"`
+	Recipients []string         `json:"recipients,omitempty"`
+}
+
+// Job generates one report in the background. A failed run is queued again after a delay until
+// MaxAttempts runs have failed, the job is then dead and only comes back when retried by hand.
+type Job struct {
+	CreatedAt   time.Time  `json:"created_at"`
+	AvailableAt time.Time  `json:"available_at"`
+	StartedAt   *time.Time `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+	Params      JobParams  `json:"params"`
+	Type        JobType    `json:"type"`
+	Status      JobStatus  `json:"status"`
+	LastError   string     `json:"last_error,omitempty"`
+	FileName    string     `json:"-"`
+	Id          int        `json:"id"`
+	UserId      int        `json:"user_id"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+}
+
+func NewJob(userId int, jobType JobType, params JobParams, maxAttempts int) Job {
+	now := time.Now()
+
+	return Job{
+		UserId:      userId,
+		Type:        jobType,
+		Params:      params,
+		Status:      JobStatusQueued,
+		MaxAttempts: max(maxAttempts, 1),
+		AvailableAt: now,
+		CreatedAt:   now,
+	}
+}
+
+func (job *Job) SetId(id int) {
+	if job != nil {
+		job.Id = id
+	}
+}
+
+func (job *Job) GetIdSafe() int {
+	if job != nil {
+		return job.Id
+	}
+
+	return 0
+}
+
+func (job *Job) GetUserIdSafe() int {
+	if job != nil {
+		return job.UserId
+	}
+
+	return 0
+}
+
+func (job *Job) GetTypeSafe() JobType {
+	if job != nil {
+		return job.Type
+	}
+
+	return ""
+}
+
+func (job *Job) GetStatusSafe() JobStatus {
+	if job != nil {
+		return job.Status
+	}
+
+	return ""
+}
+
+func (job *Job) GetParamsSafe() JobParams {
+	if job != nil {
+		return job.Params
+	}
+
+	return JobParams{}
+}
+
+// GetDownloadNameSafe is the file name the report is downloaded as, the stored file name is not shown.
+func (job *Job) GetDownloadNameSafe() string {
+	if job == nil {
+		return ""
+	}
+
+	switch job.Type {
+	case JobTypeSummarize:
+		if job.Params.StartDate != nil && job.Params.EndDate != nil {
+			return fmt.Sprintf("summarize-%s-%s%s", job.Params.StartDate.Format(time.DateOnly), job.Params.EndDate.Format(time.DateOnly), filepath.Ext(job.FileName))
+		}
+	case JobTypeInvoice:
+		return fmt.Sprintf("invoice-%d%s", job.Params.OrderId, filepath.Ext(job.FileName))
+	}
+
+	return fmt.Sprintf("report-%d%s", job.Id, filepath.Ext(job.FileName))
+}
+
+// Complete records the generated file of a running job.
+func (job *Job) Complete(fileName string, now time.Time) error {
+	if job == nil || job.Status != JobStatusRunning {
+		return ErrJobNotRunning
+	}
+
+	job.Status = JobStatusSucceeded
+	job.FileName = fileName
+	job.LastError = ""
+	job.FinishedAt = &now
+	job.UpdatedAt = &now
+
+	return nil
+}
+
+// Fail records a failed run, the job is queued again after retryIn or is dead once it is out of attempts.
+func (job *Job) Fail(err error, now time.Time, retryIn time.Duration) error {
+	if job == nil || job.Status != JobStatusRunning {
+		return ErrJobNotRunning
+	}
+
+	job.LastError = err.Error()
+	job.UpdatedAt = &now
+
+	if job.Attempts >= job.MaxAttempts {
+		job.Status = JobStatusDead
+		job.FinishedAt = &now
+
+		return nil
+	}
+
+	job.Status = JobStatusQueued
+	job.AvailableAt = now.Add(retryIn)
+
+	return nil
+}
+
+// Cancel stops a job that has not finished, a running job keeps going but its result is thrown away.
+func (job *Job) Cancel(now time.Time) error {
+	if job == nil || (job.Status != JobStatusQueued && job.Status != JobStatusRunning) {
+		return ErrJobNotCancellable
+	}
+
+	job.Status = JobStatusCancelled
+	job.FinishedAt = &now
+	job.UpdatedAt = &now
+
+	return nil
+}
+
+// Retry queues a dead or cancelled job again with a fresh set of attempts.
+func (job *Job) Retry(now time.Time) error {
+	if job == nil || (job.Status != JobStatusDead && job.Status != JobStatusCancelled) {
+		return ErrJobNotRetriable
+	}
+
+	job.Status = JobStatusQueued
+	job.Attempts = 0
+	job.AvailableAt = now
+	job.StartedAt = nil
+	job.FinishedAt = nil
+	job.UpdatedAt = &now
+
+	return nil
+}
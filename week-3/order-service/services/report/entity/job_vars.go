@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"order_service/pkg"
+	"time"
+)
+
+type JobRequest struct {
+	StartDate *time.Time       `json:"start_date"`
+	EndDate   *time.Time       `json:"end_date"`
+	Type      JobType          `json:"type"`
+	Format    pkg.ReportFormat `json:"format" enums:"xlsx,csv,ndjson,ods"`
+	OrderId   int              `json:"order_id"`
+}
+
+func (data JobRequest) Validate() error {
+	switch data.Type {
+	case JobTypeSummarize:
+		if data.StartDate == nil || data.EndDate == nil || data.StartDate.After(*data.EndDate) {
+			return ErrInvalidDateRange
+		}
+		if data.Format != "" && !data.Format.IsValid() {
+			return pkg.ErrUnsupportedReportFormat
+		}
+	case JobTypeInvoice:
+		if data.OrderId <= 0 {
+			return ErrInvalidOrderId
+		}
+	default:
+		return ErrInvalidJobType
+	}
+
+	return nil
+}
+
+func (data JobRequest) ToJobParams() JobParams {
+	if data.Type == JobTypeInvoice {
+		return JobParams{OrderId: data.OrderId}
+	}
+
+	// an orders summary is an excel file unless another format is asked for
+	format := data.Format
+	if format == "" {
+		format = pkg.ReportFormatXLSX
+	}
+
+	return JobParams{
+		StartDate: data.StartDate,
+		EndDate:   data.EndDate,
+		Format:    format,
+	}
+}
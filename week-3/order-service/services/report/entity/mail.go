@@ -0,0 +1,15 @@
+package entity
+
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Mail is a plain text message, the sender is set by the mailer.
+type Mail struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
@@ -0,0 +1,121 @@
+package entity
+
+import (
+	"order_service/pkg"
+	"time"
+)
+
+const (
+	DefaultScheduleRangeDays = 7
+	MaxScheduleRangeDays     = 366
+	MaxScheduleRecipients    = 20
+)
+
+// Schedule queues an orders summary of the RangeDays days before each run and mails it to Recipients.
+// Cron is read in Timezone, NextRunAt is always kept in UTC.
+type Schedule struct {
+	CreatedAt  time.Time        `json:"created_at"`
+	NextRunAt  time.Time        `json:"next_run_at"`
+	LastRunAt  *time.Time       `json:"last_run_at"`
+	UpdatedAt  *time.Time       `json:"updated_at"`
+	Name       string           `json:"name"`
+	Cron       string           `json:"cron"`
+	Timezone   string           `json:"timezone"`
+	Format     pkg.ReportFormat `json:"format"`
+	Recipients []string         `json:"recipients"`
+	Id         int              `json:"id"`
+	UserId     int              `json:"user_id"`
+	RangeDays  int              `json:"range_days"`
+	LastJobId  int              `json:"last_job_id,omitempty"`
+	Active     bool             `json:"active"`
+}
+
+func NewSchedule(userId int, now time.Time) Schedule {
+	return Schedule{
+		UserId:    userId,
+		Active:    true,
+		CreatedAt: now,
+	}
+}
+
+func (schedule *Schedule) SetId(id int) {
+	if schedule != nil {
+		schedule.Id = id
+	}
+}
+
+func (schedule *Schedule) GetIdSafe() int {
+	if schedule != nil {
+		return schedule.Id
+	}
+
+	return 0
+}
+
+func (schedule *Schedule) GetUserIdSafe() int {
+	if schedule != nil {
+		return schedule.UserId
+	}
+
+	return 0
+}
+
+func (schedule *Schedule) SetLastJobId(jobId int) {
+	if schedule != nil {
+		schedule.LastJobId = jobId
+	}
+}
+
+// NextRunAfter is the first time after t the cron matches in the timezone of the schedule.
+func (schedule *Schedule) NextRunAfter(t time.Time) (time.Time, error) {
+	cron, err := pkg.ParseCron(schedule.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	location, err := pkg.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := cron.Next(t.In(location))
+	if next.IsZero() {
+		return time.Time{}, ErrScheduleNeverRuns
+	}
+
+	return next.UTC(), nil
+}
+
+// Queue builds the job of a due run and moves the schedule to its next run. The report covers the RangeDays
+// days before the day of the run in the timezone of the schedule, runs missed while nothing was running
+// are not made up for.
+func (schedule *Schedule) Queue(now time.Time, maxAttempts int) (Job, error) {
+	next, err := schedule.NextRunAfter(now)
+	if err != nil {
+		return Job{}, err
+	}
+
+	location, err := pkg.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return Job{}, err
+	}
+
+	// the summary stops before its end date
+	today := now.In(location)
+	endDate := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	startDate := endDate.AddDate(0, 0, -schedule.RangeDays)
+
+	job := NewJob(schedule.UserId, JobTypeSummarize, JobParams{
+		StartDate:  &startDate,
+		EndDate:    &endDate,
+		Format:     schedule.Format,
+		ScheduleId: schedule.Id,
+		Recipients: schedule.Recipients,
+	}, maxAttempts)
+
+	schedule.NextRunAt = next
+	schedule.LastRunAt = &now
+	schedule.UpdatedAt = &now
+
+	return job, nil
+}
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"net/mail"
+	"order_service/pkg"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type ScheduleRequest struct {
+	Name       string           `json:"name" example:"Weekly orders summary"`
+	Cron       string           `json:"cron" example:"0 8 * * MON"`
+	Timezone   string           `json:"timezone" example:"Asia/Jakarta"`
+	Format     pkg.ReportFormat `json:"format" enums:"xlsx,csv,ndjson,ods"`
+	RangeDays  int              `json:"range_days" example:"7"`
+	Recipients []string         `json:"recipients" example:"finance@example.com"`
+	Active     *bool            `json:"active"`
+}
+
+func (data ScheduleRequest) Validate() error {
+	if utf8.RuneCountInString(data.Name) > 100 {
+		return ErrInvalidScheduleName
+	}
+
+	if _, err := pkg.ParseCron(data.Cron); err != nil {
+		return err
+	}
+
+	if data.Timezone != "" {
+		if _, err := pkg.LoadLocation(data.Timezone); err != nil {
+			return err
+		}
+	}
+
+	if data.Format != "" && !data.Format.IsValid() {
+		return pkg.ErrUnsupportedReportFormat
+	}
+
+	if data.RangeDays < 0 || data.RangeDays > MaxScheduleRangeDays {
+		return ErrInvalidRangeDays
+	}
+
+	if len(data.Recipients) == 0 || len(data.Recipients) > MaxScheduleRecipients {
+		return ErrInvalidRecipients
+	}
+	for _, recipient := range data.Recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return ErrInvalidRecipients
+		}
+	}
+
+	return nil
+}
+
+// ApplyTo sets the schedule from a validated request and works out its next run from now, a schedule is
+// an excel file of the last 7 days in UTC unless asked otherwise.
+func (data ScheduleRequest) ApplyTo(schedule *Schedule, now time.Time) error {
+	schedule.Name = strings.TrimSpace(data.Name)
+	schedule.Cron = strings.TrimSpace(data.Cron)
+
+	schedule.Timezone = data.Timezone
+	if schedule.Timezone == "" {
+		schedule.Timezone = time.UTC.String()
+	}
+
+	schedule.Format = data.Format
+	if schedule.Format == "" {
+		schedule.Format = pkg.ReportFormatXLSX
+	}
+
+	schedule.RangeDays = data.RangeDays
+	if schedule.RangeDays == 0 {
+		schedule.RangeDays = DefaultScheduleRangeDays
+	}
+
+	// only the address is kept, display names are dropped
+	schedule.Recipients = make([]string, 0, len(data.Recipients))
+	for _, recipient := range data.Recipients {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return ErrInvalidRecipients
+		}
+		schedule.Recipients = append(schedule.Recipients, address.Address)
+	}
+
+	if data.Active != nil {
+		schedule.Active = *data.Active
+	}
+
+	next, err := schedule.NextRunAfter(now)
+	if err != nil {
+		return err
+	}
+	schedule.NextRunAt = next
+
+	return nil
+}
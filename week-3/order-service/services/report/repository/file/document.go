@@ -0,0 +1,37 @@
+package file
+
+import (
+	"order_service/pkg"
+	orderEntity "order_service/services/order/entity"
+	"os"
+)
+
+// DocumentGenerator writes report files into the storage directory and returns their names.
+type DocumentGenerator interface {
+	GenerateSummarize(report *pkg.SummarizeReport, format pkg.ReportFormat) (string, error)
+	GenerateInvoice(order *orderEntity.Order) (string, error)
+	ReadDocument(name string) ([]byte, error)
+}
+
+type documentGenerator struct{}
+
+func NewDocumentGenerator() DocumentGenerator {
+	return &documentGenerator{}
+}
+
+func (generator *documentGenerator) GenerateSummarize(report *pkg.SummarizeReport, format pkg.ReportFormat) (string, error) {
+	return pkg.ExportReport(report, format)
+}
+
+func (generator *documentGenerator) GenerateInvoice(order *orderEntity.Order) (string, error) {
+	return pkg.GeneratePDF(order)
+}
+
+func (generator *documentGenerator) ReadDocument(name string) ([]byte, error) {
+	path, err := pkg.DocumentPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/report/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReportRepository interface {
+	CreateJob(ctx context.Context, job *entity.Job) error
+	GetJob(ctx context.Context, jobId int) (*entity.Job, error)
+	ClaimJob(ctx context.Context, now, staleBefore time.Time) (*entity.Job, error)
+	UpdateJob(ctx context.Context, jobId int, callbackFn func(job *entity.Job) error) error
+	CreateSchedule(ctx context.Context, schedule *entity.Schedule) error
+	GetSchedules(ctx context.Context, userId int) (*[]entity.Schedule, error)
+	UpdateSchedule(ctx context.Context, scheduleId int, callbackFn func(schedule *entity.Schedule) error) error
+	DeleteSchedule(ctx context.Context, userId, scheduleId int) error
+	QueueDueSchedules(ctx context.Context, now time.Time, limit int, callbackFn func(schedule *entity.Schedule) (*entity.Job, error)) (int, error)
+}
+
+const (
+	QUERY_CREATE_JOB_WITH_RETURN_ID      = "INSERT INTO report_jobs (user_id, type, status, params, max_attempts, available_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id"
+	QUERY_GET_JOB                        = "SELECT id, user_id, type, status, params, attempts, max_attempts, COALESCE(last_error, ''), COALESCE(file_name, ''), available_at, started_at, finished_at, created_at, updated_at FROM report_jobs WHERE id = $1"
+	QUERY_GET_JOB_LOCK                   = "SELECT id, user_id, type, status, params, attempts, max_attempts, COALESCE(last_error, ''), COALESCE(file_name, ''), available_at, started_at, finished_at, created_at, updated_at FROM report_jobs WHERE id = $1 FOR UPDATE"
+	QUERY_CLAIM_JOB                      = "UPDATE report_jobs SET status = 'running', attempts = attempts + 1, started_at = $1, updated_at = $1 WHERE id = (SELECT id FROM report_jobs WHERE (status = 'queued' AND available_at <= $1) OR (status = 'running' AND started_at <= $2) ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED) RETURNING id, user_id, type, status, params, attempts, max_attempts, COALESCE(last_error, ''), COALESCE(file_name, ''), available_at, started_at, finished_at, created_at, updated_at"
+	QUERY_UPDATE_JOB                     = "UPDATE report_jobs SET status = $2, attempts = $3, last_error = NULLIF($4, ''), file_name = NULLIF($5, ''), available_at = $6, started_at = $7, finished_at = $8, updated_at = $9 WHERE id = $1"
+	QUERY_CREATE_SCHEDULE_WITH_RETURN_ID = "INSERT INTO report_schedules (user_id, name, cron, timezone, format, range_days, recipients, active, next_run_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id"
+	QUERY_GET_SCHEDULES                  = "SELECT id, user_id, name, cron, timezone, format, range_days, recipients, active, next_run_at, last_run_at, COALESCE(last_job_id, 0), created_at, updated_at FROM report_schedules WHERE user_id = $1 ORDER BY id"
+	QUERY_GET_SCHEDULE_LOCK              = "SELECT id, user_id, name, cron, timezone, format, range_days, recipients, active, next_run_at, last_run_at, COALESCE(last_job_id, 0), created_at, updated_at FROM report_schedules WHERE id = $1 FOR UPDATE"
+	QUERY_GET_DUE_SCHEDULES_LOCK         = "SELECT id, user_id, name, cron, timezone, format, range_days, recipients, active, next_run_at, last_run_at, COALESCE(last_job_id, 0), created_at, updated_at FROM report_schedules WHERE active AND next_run_at <= $1 ORDER BY next_run_at, id LIMIT $2 FOR UPDATE SKIP LOCKED"
+	QUERY_UPDATE_SCHEDULE                = "UPDATE report_schedules SET name = $2, cron = $3, timezone = $4, format = $5, range_days = $6, recipients = $7, active = $8, next_run_at = $9, last_run_at = $10, last_job_id = NULLIF($11, 0), updated_at = $12 WHERE id = $1"
+	QUERY_DELETE_SCHEDULE                = "DELETE FROM report_schedules WHERE id = $1 AND user_id = $2"
+)
+
+type postgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewReportRepo(db *pgxpool.Pool) ReportRepository {
+	return &postgresRepo{
+		db,
+	}
+}
+
+func scanJob(row pgx.Row) (*entity.Job, error) {
+	var job entity.Job
+
+	err := row.Scan(&job.Id, &job.UserId, &job.Type, &job.Status, &job.Params, &job.Attempts, &job.MaxAttempts, &job.LastError, &job.FileName, &job.AvailableAt, &job.StartedAt, &job.FinishedAt, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, core.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (repo *postgresRepo) CreateJob(ctx context.Context, job *entity.Job) error {
+	var newJobId int
+
+	err := repo.db.QueryRow(ctx, QUERY_CREATE_JOB_WITH_RETURN_ID, job.UserId, job.Type, job.Status, job.Params, job.MaxAttempts, job.AvailableAt, job.CreatedAt).Scan(&newJobId)
+	if err != nil {
+		return err
+	}
+	job.SetId(newJobId)
+
+	return nil
+}
+
+func (repo *postgresRepo) GetJob(ctx context.Context, jobId int) (*entity.Job, error) {
+	return scanJob(repo.db.QueryRow(ctx, QUERY_GET_JOB, jobId))
+}
+
+// ClaimJob marks the oldest due job as running and counts the attempt. A job still running since before
+// staleBefore is claimed again, its worker is taken to be gone. core.ErrRecordNotFound means nothing is due.
+func (repo *postgresRepo) ClaimJob(ctx context.Context, now, staleBefore time.Time) (*entity.Job, error) {
+	return scanJob(repo.db.QueryRow(ctx, QUERY_CLAIM_JOB, now, staleBefore))
+}
+
+func (repo *postgresRepo) UpdateJob(ctx context.Context, jobId int, callbackFn func(job *entity.Job) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		job, err := scanJob(tx.QueryRow(ctx, QUERY_GET_JOB_LOCK, jobId))
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		err = callbackFn(job)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_UPDATE_JOB, job.Id, job.Status, job.Attempts, job.LastError, job.FileName, job.AvailableAt, job.StartedAt, job.FinishedAt, job.UpdatedAt)
+
+		return err
+	})
+}
+
+func scanSchedule(row pgx.Row) (*entity.Schedule, error) {
+	var schedule entity.Schedule
+
+	err := row.Scan(&schedule.Id, &schedule.UserId, &schedule.Name, &schedule.Cron, &schedule.Timezone, &schedule.Format, &schedule.RangeDays, &schedule.Recipients, &schedule.Active, &schedule.NextRunAt, &schedule.LastRunAt, &schedule.LastJobId, &schedule.CreatedAt, &schedule.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, core.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func updateSchedule(ctx context.Context, tx pgx.Tx, schedule *entity.Schedule) error {
+	_, err := tx.Exec(ctx, QUERY_UPDATE_SCHEDULE, schedule.Id, schedule.Name, schedule.Cron, schedule.Timezone, schedule.Format, schedule.RangeDays, schedule.Recipients, schedule.Active, schedule.NextRunAt, schedule.LastRunAt, schedule.LastJobId, schedule.UpdatedAt)
+
+	return err
+}
+
+func (repo *postgresRepo) CreateSchedule(ctx context.Context, schedule *entity.Schedule) error {
+	var newScheduleId int
+
+	err := repo.db.QueryRow(ctx, QUERY_CREATE_SCHEDULE_WITH_RETURN_ID, schedule.UserId, schedule.Name, schedule.Cron, schedule.Timezone, schedule.Format, schedule.RangeDays, schedule.Recipients, schedule.Active, schedule.NextRunAt, schedule.CreatedAt).Scan(&newScheduleId)
+	if err != nil {
+		return err
+	}
+	schedule.SetId(newScheduleId)
+
+	return nil
+}
+
+func (repo *postgresRepo) GetSchedules(ctx context.Context, userId int) (*[]entity.Schedule, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_SCHEDULES, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Schedule, error) {
+		schedule, err := scanSchedule(row)
+		if err != nil {
+			return entity.Schedule{}, err
+		}
+
+		return *schedule, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedules, nil
+}
+
+func (repo *postgresRepo) UpdateSchedule(ctx context.Context, scheduleId int, callbackFn func(schedule *entity.Schedule) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		schedule, err := scanSchedule(tx.QueryRow(ctx, QUERY_GET_SCHEDULE_LOCK, scheduleId))
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		err = callbackFn(schedule)
+		if err != nil {
+			return err
+		}
+
+		return updateSchedule(ctx, tx, schedule)
+	})
+}
+
+func (repo *postgresRepo) DeleteSchedule(ctx context.Context, userId, scheduleId int) error {
+	tag, err := repo.db.Exec(ctx, QUERY_DELETE_SCHEDULE, scheduleId, userId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return core.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// QueueDueSchedules hands up to limit schedules due at now to callbackFn, which moves each to its next run
+// and returns the job to queue, a nil job only updates the schedule. The jobs and the schedules are written
+// together, a schedule being queued elsewhere is skipped.
+func (repo *postgresRepo) QueueDueSchedules(ctx context.Context, now time.Time, limit int, callbackFn func(schedule *entity.Schedule) (*entity.Job, error)) (int, error) {
+	queued := 0
+
+	err := pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, QUERY_GET_DUE_SCHEDULES_LOCK, now, limit)
+		if err != nil {
+			return err
+		}
+
+		schedules, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Schedule, error) {
+			schedule, err := scanSchedule(row)
+			if err != nil {
+				return entity.Schedule{}, err
+			}
+
+			return *schedule, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range schedules {
+			schedule := &schedules[i]
+
+			// run business logic
+			job, err := callbackFn(schedule)
+			if err != nil {
+				return err
+			}
+
+			if job != nil {
+				var newJobId int
+
+				err = tx.QueryRow(ctx, QUERY_CREATE_JOB_WITH_RETURN_ID, job.UserId, job.Type, job.Status, job.Params, job.MaxAttempts, job.AvailableAt, job.CreatedAt).Scan(&newJobId)
+				if err != nil {
+					return err
+				}
+				job.SetId(newJobId)
+				schedule.SetLastJobId(newJobId)
+				queued++
+			}
+
+			err = updateSchedule(ctx, tx, schedule)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return queued, nil
+}
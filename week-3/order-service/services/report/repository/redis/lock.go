@@ -0,0 +1,47 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SchedulerLock lets one process at a time do the work behind name, token tells the holders apart.
+type SchedulerLock interface {
+	Acquire(ctx context.Context, name, token string, expiration int) (bool, error)
+	Release(ctx context.Context, name, token string) error
+}
+
+// KEYS[1] lock, ARGV token, the lock is only deleted by whoever holds it
+var scriptReleaseLock = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type redisRepo struct {
+	db *redis.Client
+}
+
+func NewSchedulerLock(db *redis.Client) SchedulerLock {
+	return &redisRepo{
+		db,
+	}
+}
+
+// Acquire takes the lock for expiration seconds unless someone else holds it, a holder that dies lets
+// it go once it expires.
+func (repo *redisRepo) Acquire(ctx context.Context, name, token string, expiration int) (bool, error) {
+	key := fmt.Sprintf("lock:%s", name)
+
+	return repo.db.SetNX(ctx, key, token, time.Second*time.Duration(expiration)).Result()
+}
+
+func (repo *redisRepo) Release(ctx context.Context, name, token string) error {
+	key := fmt.Sprintf("lock:%s", name)
+
+	return scriptReleaseLock.Run(ctx, repo.db, []string{key}, token).Err()
+}
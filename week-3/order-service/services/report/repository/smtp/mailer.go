@@ -0,0 +1,189 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"order_service/services/report/entity"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrAuthNotSupported = errors.New("smtp server does not support AUTH")
+
+// Mailer delivers mails, the SMTP one can point at a local sink like mailpit in development and tests.
+type Mailer interface {
+	Send(ctx context.Context, mail *entity.Mail) error
+}
+
+type smtpMailer struct {
+	host    string
+	addr    string
+	from    string
+	auth    smtp.Auth
+	timeout time.Duration
+}
+
+// NewSMTPMailer sends from the from address through host:port. STARTTLS is used when the server offers it,
+// without a username nothing is authenticated.
+func NewSMTPMailer(host string, port int, username, password, from string, timeout time.Duration) Mailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpMailer{
+		host:    host,
+		addr:    net.JoinHostPort(host, strconv.Itoa(port)),
+		from:    from,
+		auth:    auth,
+		timeout: timeout,
+	}
+}
+
+func (mailer *smtpMailer) Send(ctx context.Context, mail *entity.Mail) error {
+	message, err := buildMessage(mailer.from, mail, time.Now())
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: mailer.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", mailer.addr)
+	if err != nil {
+		return err
+	}
+
+	// net/smtp knows nothing of contexts, the whole conversation has to fit in the deadline
+	deadline := time.Now().Add(mailer.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, mailer.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: mailer.host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if mailer.auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return ErrAuthNotSupported
+		}
+		err = client.Auth(mailer.auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(mailer.from)
+	if err != nil {
+		return err
+	}
+
+	for _, to := range mail.To {
+		err = client.Rcpt(to)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(message)
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// buildMessage writes the mail as multipart/mixed, the body goes first as quoted printable text and every
+// attachment follows in base64.
+func buildMessage(from string, mail *entity.Mail, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(mail.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", writer.Boundary())
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	body := quotedprintable.NewWriter(part)
+	_, err = body.Write([]byte(mail.Body))
+	if err != nil {
+		return nil, err
+	}
+	err = body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range mail.Attachments {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.Name})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// 57 bytes make a 76 character line, the longest allowed
+		for data := attachment.Data; len(data) > 0; {
+			n := min(len(data), 57)
+			_, err = fmt.Fprintf(part, "%s\r\n", base64.StdEncoding.EncodeToString(data[:n]))
+			if err != nil {
+				return nil, err
+			}
+			data = data[n:]
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
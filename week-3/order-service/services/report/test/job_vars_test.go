@@ -0,0 +1,57 @@
+package test
+
+import (
+	"order_service/pkg"
+	"order_service/services/report/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRequestValidate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name    string
+		data    entity.JobRequest
+		wantErr error
+	}{
+		{name: "Summarize", data: entity.JobRequest{Type: entity.JobTypeSummarize, StartDate: &start, EndDate: &end}},
+		{name: "Summarize as csv", data: entity.JobRequest{Type: entity.JobTypeSummarize, StartDate: &start, EndDate: &end, Format: pkg.ReportFormatCSV}},
+		{name: "Summarize in an unknown format", data: entity.JobRequest{Type: entity.JobTypeSummarize, StartDate: &start, EndDate: &end, Format: "pdf"}, wantErr: pkg.ErrUnsupportedReportFormat},
+		{name: "Summarize without dates", data: entity.JobRequest{Type: entity.JobTypeSummarize}, wantErr: entity.ErrInvalidDateRange},
+		{name: "Summarize ending before it starts", data: entity.JobRequest{Type: entity.JobTypeSummarize, StartDate: &end, EndDate: &start}, wantErr: entity.ErrInvalidDateRange},
+		{name: "Invoice", data: entity.JobRequest{Type: entity.JobTypeInvoice, OrderId: 1}},
+		{name: "Invoice without order", data: entity.JobRequest{Type: entity.JobTypeInvoice}, wantErr: entity.ErrInvalidOrderId},
+		{name: "Unknown type", data: entity.JobRequest{Type: "csv"}, wantErr: entity.ErrInvalidJobType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.data.Validate())
+		})
+	}
+}
+
+func TestJobRequestToJobParams(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name string
+		data entity.JobRequest
+		want entity.JobParams
+	}{
+		{name: "Summarize defaults to xlsx", data: entity.JobRequest{Type: entity.JobTypeSummarize, StartDate: &start, EndDate: &end}, want: entity.JobParams{StartDate: &start, EndDate: &end, Format: pkg.ReportFormatXLSX}},
+		{name: "Summarize keeps the requested format", data: entity.JobRequest{Type: entity.JobTypeSummarize, StartDate: &start, EndDate: &end, Format: pkg.ReportFormatODS}, want: entity.JobParams{StartDate: &start, EndDate: &end, Format: pkg.ReportFormatODS}},
+		{name: "Invoice has no format", data: entity.JobRequest{Type: entity.JobTypeInvoice, OrderId: 1, Format: pkg.ReportFormatCSV}, want: entity.JobParams{OrderId: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.data.ToJobParams())
+		})
+	}
+}
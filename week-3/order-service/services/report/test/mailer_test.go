@@ -0,0 +1,155 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"order_service/services/report/entity"
+	"order_service/services/report/repository/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSink is the least of an SMTP server, it accepts a single mail and keeps its envelope and data.
+type smtpSink struct {
+	listener   net.Listener
+	from       string
+	recipients []string
+	data       chan string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	sink := &smtpSink{listener: listener, data: make(chan string, 1)}
+	go sink.serve()
+
+	return sink
+}
+
+func (sink *smtpSink) port() int {
+	return sink.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (sink *smtpSink) serve() {
+	conn, err := sink.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 sink ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			sink.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 ok")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			sink.recipients = append(sink.recipients, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 ok")
+		case command == "DATA":
+			reply("354 go ahead")
+
+			var data strings.Builder
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			sink.data <- data.String()
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	sink := newSMTPSink(t)
+	mailer := smtp.NewSMTPMailer("127.0.0.1", sink.port(), "", "", "reports@order-service.local", time.Second)
+
+	attachment := []byte(strings.Repeat("order,total\n", 20))
+	err := mailer.Send(context.Background(), &entity.Mail{
+		To:          []string{"finance@example.com", "ops@example.com"},
+		Subject:     "Orders summary 2024-01-01 to 2024-01-07",
+		Body:        "The orders summary is attached.",
+		Attachments: []entity.Attachment{{Name: "summarize.csv", ContentType: "text/csv", Data: attachment}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "reports@order-service.local", sink.from)
+	assert.Equal(t, []string{"finance@example.com", "ops@example.com"}, sink.recipients, "every recipient should be in the envelope")
+
+	message, err := mail.ReadMessage(strings.NewReader(<-sink.data))
+	require.NoError(t, err)
+	assert.Equal(t, "Orders summary 2024-01-01 to 2024-01-07", message.Header.Get("Subject"))
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+
+	body, err := reader.NextPart()
+	require.NoError(t, err)
+	text, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "The orders summary is attached.", string(text), "body should be the first part")
+
+	// multipart decodes quoted printable by itself, base64 is left to the reader
+	file, err := reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "summarize.csv", file.FileName())
+	assert.Equal(t, "base64", file.Header.Get("Content-Transfer-Encoding"))
+	encoded, err := io.ReadAll(file)
+	require.NoError(t, err)
+	for _, line := range strings.Split(strings.TrimSpace(string(encoded)), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76, "base64 lines should be at most 76 characters")
+	}
+	decoded, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(bytes.ReplaceAll(encoded, []byte("\r\n"), nil))))
+	require.NoError(t, err)
+	assert.Equal(t, attachment, decoded, "attachment should be sent whole")
+
+	_, err = reader.NextPart()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestSMTPMailerRequiresAuth(t *testing.T) {
+	sink := newSMTPSink(t)
+	mailer := smtp.NewSMTPMailer("127.0.0.1", sink.port(), "user", "secret", "reports@order-service.local", time.Second)
+
+	err := mailer.Send(context.Background(), &entity.Mail{To: []string{"finance@example.com"}, Subject: "Orders summary"})
+	assert.ErrorIs(t, err, smtp.ErrAuthNotSupported, "credentials should not be dropped silently")
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/file/document.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/file/document.go -destination test/mock/document.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	pkg "order_service/pkg"
+	entity "order_service/services/order/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDocumentGenerator is a mock of DocumentGenerator interface.
+type MockDocumentGenerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocumentGeneratorMockRecorder
+}
+
+// MockDocumentGeneratorMockRecorder is the mock recorder for MockDocumentGenerator.
+type MockDocumentGeneratorMockRecorder struct {
+	mock *MockDocumentGenerator
+}
+
+// NewMockDocumentGenerator creates a new mock instance.
+func NewMockDocumentGenerator(ctrl *gomock.Controller) *MockDocumentGenerator {
+	mock := &MockDocumentGenerator{ctrl: ctrl}
+	mock.recorder = &MockDocumentGeneratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDocumentGenerator) EXPECT() *MockDocumentGeneratorMockRecorder {
+	return m.recorder
+}
+
+// GenerateInvoice mocks base method.
+func (m *MockDocumentGenerator) GenerateInvoice(order *entity.Order) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateInvoice", order)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateInvoice indicates an expected call of GenerateInvoice.
+func (mr *MockDocumentGeneratorMockRecorder) GenerateInvoice(order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateInvoice", reflect.TypeOf((*MockDocumentGenerator)(nil).GenerateInvoice), order)
+}
+
+// GenerateSummarize mocks base method.
+func (m *MockDocumentGenerator) GenerateSummarize(report *pkg.SummarizeReport, format pkg.ReportFormat) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateSummarize", report, format)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateSummarize indicates an expected call of GenerateSummarize.
+func (mr *MockDocumentGeneratorMockRecorder) GenerateSummarize(report, format any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSummarize", reflect.TypeOf((*MockDocumentGenerator)(nil).GenerateSummarize), report, format)
+}
+
+// ReadDocument mocks base method.
+func (m *MockDocumentGenerator) ReadDocument(name string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadDocument", name)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadDocument indicates an expected call of ReadDocument.
+func (mr *MockDocumentGeneratorMockRecorder) ReadDocument(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadDocument", reflect.TypeOf((*MockDocumentGenerator)(nil).ReadDocument), name)
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/redis/lock.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/redis/lock.go -destination test/mock/lock.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSchedulerLock is a mock of SchedulerLock interface.
+type MockSchedulerLock struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulerLockMockRecorder
+}
+
+// MockSchedulerLockMockRecorder is the mock recorder for MockSchedulerLock.
+type MockSchedulerLockMockRecorder struct {
+	mock *MockSchedulerLock
+}
+
+// NewMockSchedulerLock creates a new mock instance.
+func NewMockSchedulerLock(ctrl *gomock.Controller) *MockSchedulerLock {
+	mock := &MockSchedulerLock{ctrl: ctrl}
+	mock.recorder = &MockSchedulerLockMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchedulerLock) EXPECT() *MockSchedulerLockMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockSchedulerLock) Acquire(ctx context.Context, name, token string, expiration int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx, name, token, expiration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockSchedulerLockMockRecorder) Acquire(ctx, name, token, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockSchedulerLock)(nil).Acquire), ctx, name, token, expiration)
+}
+
+// Release mocks base method.
+func (m *MockSchedulerLock) Release(ctx context.Context, name, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, name, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockSchedulerLockMockRecorder) Release(ctx, name, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockSchedulerLock)(nil).Release), ctx, name, token)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase/order.go
+//
+// Generated by this command:
+//
+//	mockgen -source usecase/order.go -destination test/mock/order.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/order/entity"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrderReader is a mock of OrderReader interface.
+type MockOrderReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderReaderMockRecorder
+}
+
+// MockOrderReaderMockRecorder is the mock recorder for MockOrderReader.
+type MockOrderReaderMockRecorder struct {
+	mock *MockOrderReader
+}
+
+// NewMockOrderReader creates a new mock instance.
+func NewMockOrderReader(ctrl *gomock.Controller) *MockOrderReader {
+	mock := &MockOrderReader{ctrl: ctrl}
+	mock.recorder = &MockOrderReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderReader) EXPECT() *MockOrderReaderMockRecorder {
+	return m.recorder
+}
+
+// GetOrder mocks base method.
+func (m *MockOrderReader) GetOrder(ctx context.Context, userId, orderId int) (*entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, userId, orderId)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrderReaderMockRecorder) GetOrder(ctx, userId, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderReader)(nil).GetOrder), ctx, userId, orderId)
+}
+
+// GetOrdersSummarize mocks base method.
+func (m *MockOrderReader) GetOrdersSummarize(ctx context.Context, startDate, endDate time.Time) (*[]entity.OrdersSummarize, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrdersSummarize", ctx, startDate, endDate)
+	ret0, _ := ret[0].(*[]entity.OrdersSummarize)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrdersSummarize indicates an expected call of GetOrdersSummarize.
+func (mr *MockOrderReaderMockRecorder) GetOrdersSummarize(ctx, startDate, endDate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersSummarize", reflect.TypeOf((*MockOrderReader)(nil).GetOrdersSummarize), ctx, startDate, endDate)
+}
+
+// GetTaxesSummarize mocks base method.
+func (m *MockOrderReader) GetTaxesSummarize(ctx context.Context, startDate, endDate time.Time) (*[]entity.OrderTax, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxesSummarize", ctx, startDate, endDate)
+	ret0, _ := ret[0].(*[]entity.OrderTax)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxesSummarize indicates an expected call of GetTaxesSummarize.
+func (mr *MockOrderReaderMockRecorder) GetTaxesSummarize(ctx, startDate, endDate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxesSummarize", reflect.TypeOf((*MockOrderReader)(nil).GetTaxesSummarize), ctx, startDate, endDate)
+}
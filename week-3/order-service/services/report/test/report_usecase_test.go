@@ -0,0 +1,788 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	"order_service/pkg"
+	orderEntity "order_service/services/order/entity"
+	"order_service/services/report/entity"
+	"order_service/services/report/test/mock"
+	"order_service/services/report/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type ReportUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo      *mock.MockReportRepository
+	mockDocuments *mock.MockDocumentGenerator
+	mockOrder     *mock.MockOrderReader
+	mockMailer    *mock.MockMailer
+	mockLock      *mock.MockSchedulerLock
+	usecase       usecase.ReportUsecase
+	adminCtx      context.Context
+	memberCtx     context.Context
+	startDate     time.Time
+	endDate       time.Time
+}
+
+func (suite *ReportUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockReportRepository(ctrl)
+	suite.mockDocuments = mock.NewMockDocumentGenerator(ctrl)
+	suite.mockOrder = mock.NewMockOrderReader(ctrl)
+	suite.mockMailer = mock.NewMockMailer(ctrl)
+	suite.mockLock = mock.NewMockSchedulerLock(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, suite.mockDocuments, suite.mockOrder, suite.mockMailer, suite.mockLock, 3, time.Minute, time.Hour, 60)
+	suite.adminCtx = requesterContext(1, 1)
+	suite.memberCtx = requesterContext(2, 0)
+	suite.startDate = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.endDate = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// updateJob makes the mocked repo run the callback on a copy of job, like the locked row would be.
+func updateJob(job entity.Job) func(ctx context.Context, jobId int, callbackFn func(job *entity.Job) error) error {
+	return func(ctx context.Context, jobId int, callbackFn func(job *entity.Job) error) error {
+		return callbackFn(&job)
+	}
+}
+
+func (suite *ReportUsecaseTestSuite) TestCreateJob() {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		data      entity.JobRequest
+		callOrder bool
+		orderErr  error
+		callRepo  bool
+		repoErr   error
+		wantUser  int
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Admin queues a summarize report",
+			ctx:       suite.adminCtx,
+			data:      entity.JobRequest{Type: entity.JobTypeSummarize, StartDate: &suite.startDate, EndDate: &suite.endDate},
+			callRepo:  true,
+			wantUser:  1,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Member cannot export the summary",
+			ctx:       suite.memberCtx,
+			data:      entity.JobRequest{Type: entity.JobTypeSummarize, StartDate: &suite.startDate, EndDate: &suite.endDate},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrSummarizeAdminOnly.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Member queues the invoice of an own order",
+			ctx:       suite.memberCtx,
+			data:      entity.JobRequest{Type: entity.JobTypeInvoice, OrderId: 5},
+			callOrder: true,
+			callRepo:  true,
+			wantUser:  2,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Invoice of an unknown order",
+			ctx:       suite.memberCtx,
+			data:      entity.JobRequest{Type: entity.JobTypeInvoice, OrderId: 5},
+			callOrder: true,
+			orderErr:  core.ErrNotFound,
+			wantErr:   core.ErrNotFound,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repository error",
+			ctx:       suite.adminCtx,
+			data:      entity.JobRequest{Type: entity.JobTypeSummarize, StartDate: &suite.startDate, EndDate: &suite.endDate},
+			callRepo:  true,
+			repoErr:   errors.New("connection reset"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotCreateJob.Error()).WithDebug("connection reset"),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callOrder {
+				suite.mockOrder.EXPECT().GetOrder(gomock.Any(), 2, tt.data.OrderId).Return(&orderEntity.Order{Id: tt.data.OrderId, UserId: 2}, tt.orderErr)
+			}
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().CreateJob(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, job *entity.Job) error {
+					job.SetId(9)
+					return tt.repoErr
+				})
+			}
+
+			job, err := suite.usecase.CreateJob(tt.ctx, &tt.data)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(9, job.GetIdSafe(), "job id should be set")
+				suite.Equal(tt.wantUser, job.GetUserIdSafe(), "job should belong to the requester")
+				suite.Equal(entity.JobStatusQueued, job.GetStatusSafe(), "job should be queued")
+				suite.Equal(3, job.MaxAttempts, "job should get the configured attempts")
+			}
+		})
+	}
+}
+
+func (suite *ReportUsecaseTestSuite) TestGetJob() {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		job       *entity.Job
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Owner sees the job",
+			ctx:       suite.memberCtx,
+			job:       &entity.Job{Id: 1, UserId: 2},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Admin sees any job",
+			ctx:       suite.adminCtx,
+			job:       &entity.Job{Id: 1, UserId: 2},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Job of someone else is missing",
+			ctx:       suite.memberCtx,
+			job:       &entity.Job{Id: 1, UserId: 3},
+			wantErr:   core.ErrNotFound.WithError(entity.ErrJobNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown job",
+			ctx:       suite.memberCtx,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrJobNotFound.Error()).WithDebug(core.ErrRecordNotFound.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().GetJob(gomock.Any(), 1).Return(tt.job, tt.repoErr)
+
+			job, err := suite.usecase.GetJob(tt.ctx, 1)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(tt.job, job, "job should be returned")
+			}
+		})
+	}
+}
+
+func (suite *ReportUsecaseTestSuite) TestGetJobDocument() {
+	tests := []struct {
+		name         string
+		job          *entity.Job
+		wantDownload string
+		wantErr      error
+		assertion    assert.ErrorAssertionFunc
+	}{
+		{
+			name:         "Succeeded invoice",
+			job:          &entity.Job{Id: 1, UserId: 2, Type: entity.JobTypeInvoice, Status: entity.JobStatusSucceeded, Params: entity.JobParams{OrderId: 5}, FileName: "invoice-5-0f8fad5b.pdf"},
+			wantDownload: "invoice-5.pdf",
+			assertion:    assert.NoError,
+		},
+		{
+			name:         "Succeeded summarize",
+			job:          &entity.Job{Id: 1, UserId: 2, Type: entity.JobTypeSummarize, Status: entity.JobStatusSucceeded, Params: entity.JobParams{StartDate: &suite.startDate, EndDate: &suite.endDate}, FileName: "summarize-0f8fad5b.xlsx"},
+			wantDownload: "summarize-2024-01-01-2024-02-01.xlsx",
+			assertion:    assert.NoError,
+		},
+		{
+			name:      "Job still running",
+			job:       &entity.Job{Id: 1, UserId: 2, Type: entity.JobTypeInvoice, Status: entity.JobStatusRunning},
+			wantErr:   core.ErrConfict.WithError(entity.ErrJobNotReady.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Job of someone else",
+			job:       &entity.Job{Id: 1, UserId: 3, Type: entity.JobTypeInvoice, Status: entity.JobStatusSucceeded},
+			wantErr:   core.ErrNotFound.WithError(entity.ErrJobNotFound.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().GetJob(gomock.Any(), 1).Return(tt.job, nil)
+
+			job, err := suite.usecase.GetJobDocument(suite.memberCtx, 1)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(tt.wantDownload, job.GetDownloadNameSafe(), "download name should not expose the stored file")
+			}
+		})
+	}
+}
+
+func (suite *ReportUsecaseTestSuite) TestCancelJob() {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		job        entity.Job
+		wantStatus entity.JobStatus
+		wantErr    error
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "Queued job is cancelled",
+			ctx:        suite.memberCtx,
+			job:        entity.Job{Id: 1, UserId: 2, Status: entity.JobStatusQueued},
+			wantStatus: entity.JobStatusCancelled,
+			assertion:  assert.NoError,
+		},
+		{
+			name:       "Running job is cancelled by admin",
+			ctx:        suite.adminCtx,
+			job:        entity.Job{Id: 1, UserId: 2, Status: entity.JobStatusRunning},
+			wantStatus: entity.JobStatusCancelled,
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Finished job cannot be cancelled",
+			ctx:       suite.memberCtx,
+			job:       entity.Job{Id: 1, UserId: 2, Status: entity.JobStatusSucceeded},
+			wantErr:   core.ErrConfict.WithError(entity.ErrJobNotCancellable.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Job of someone else",
+			ctx:       suite.memberCtx,
+			job:       entity.Job{Id: 1, UserId: 3, Status: entity.JobStatusQueued},
+			wantErr:   core.ErrNotFound.WithError(entity.ErrJobNotFound.Error()).WithDebug(entity.ErrJobNotFound.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().UpdateJob(gomock.Any(), 1, gomock.Any()).DoAndReturn(updateJob(tt.job))
+
+			job, err := suite.usecase.CancelJob(tt.ctx, 1)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(tt.wantStatus, job.GetStatusSafe(), "status should be updated")
+				suite.NotNil(job.FinishedAt, "job should be finished")
+			}
+		})
+	}
+}
+
+func (suite *ReportUsecaseTestSuite) TestRetryJob() {
+	tests := []struct {
+		name      string
+		job       entity.Job
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Dead job is queued again",
+			job:       entity.Job{Id: 1, UserId: 2, Status: entity.JobStatusDead, Attempts: 3, MaxAttempts: 3},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Queued job cannot be retried",
+			job:       entity.Job{Id: 1, UserId: 2, Status: entity.JobStatusQueued},
+			wantErr:   core.ErrConfict.WithError(entity.ErrJobNotRetriable.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().UpdateJob(gomock.Any(), 1, gomock.Any()).DoAndReturn(updateJob(tt.job))
+
+			job, err := suite.usecase.RetryJob(suite.memberCtx, 1)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(entity.JobStatusQueued, job.GetStatusSafe(), "job should be queued")
+				suite.Zero(job.Attempts, "attempts should start over")
+			}
+		})
+	}
+}
+
+func (suite *ReportUsecaseTestSuite) TestProcessNextJob() {
+	genErr := errors.New("disk full")
+	mailErr := errors.New("550 mailbox unavailable")
+
+	tests := []struct {
+		name          string
+		job           *entity.Job
+		format        pkg.ReportFormat
+		recipients    []string
+		claimErr      error
+		runs          bool
+		genErr        error
+		mailErr       error
+		cancelled     bool
+		wantProcessed bool
+		wantStatus    entity.JobStatus
+		wantRetryIn   time.Duration
+		wantErr       error
+		assertion     assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Nothing is due",
+			claimErr:  core.ErrRecordNotFound,
+			assertion: assert.NoError,
+		},
+		{
+			name:          "Report is generated",
+			job:           &entity.Job{Id: 1, UserId: 1, Type: entity.JobTypeSummarize, Status: entity.JobStatusRunning, Attempts: 1, MaxAttempts: 3},
+			runs:          true,
+			wantProcessed: true,
+			wantStatus:    entity.JobStatusSucceeded,
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Report is generated in the requested format",
+			job:           &entity.Job{Id: 1, UserId: 1, Type: entity.JobTypeSummarize, Status: entity.JobStatusRunning, Attempts: 1, MaxAttempts: 3},
+			format:        pkg.ReportFormatCSV,
+			runs:          true,
+			wantProcessed: true,
+			wantStatus:    entity.JobStatusSucceeded,
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Scheduled report is mailed",
+			job:           &entity.Job{Id: 1, UserId: 1, Type: entity.JobTypeSummarize, Status: entity.JobStatusRunning, Attempts: 1, MaxAttempts: 3},
+			recipients:    []string{"finance@example.com"},
+			runs:          true,
+			wantProcessed: true,
+			wantStatus:    entity.JobStatusSucceeded,
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Failed delivery is retried later",
+			job:           &entity.Job{Id: 1, UserId: 1, Type: entity.JobTypeSummarize, Status: entity.JobStatusRunning, Attempts: 1, MaxAttempts: 3},
+			recipients:    []string{"finance@example.com"},
+			runs:          true,
+			mailErr:       mailErr,
+			wantProcessed: true,
+			wantStatus:    entity.JobStatusQueued,
+			wantRetryIn:   time.Minute,
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Failed run is retried later",
+			job:           &entity.Job{Id: 1, UserId: 1, Type: entity.JobTypeSummarize, Status: entity.JobStatusRunning, Attempts: 2, MaxAttempts: 3},
+			runs:          true,
+			genErr:        genErr,
+			wantProcessed: true,
+			wantStatus:    entity.JobStatusQueued,
+			wantRetryIn:   2 * time.Minute,
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Last failed run is dead lettered",
+			job:           &entity.Job{Id: 1, UserId: 1, Type: entity.JobTypeSummarize, Status: entity.JobStatusRunning, Attempts: 3, MaxAttempts: 3},
+			runs:          true,
+			genErr:        genErr,
+			wantProcessed: true,
+			wantStatus:    entity.JobStatusDead,
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Job that never finished is dead lettered without running",
+			job:           &entity.Job{Id: 1, UserId: 1, Type: entity.JobTypeSummarize, Status: entity.JobStatusRunning, Attempts: 4, MaxAttempts: 3},
+			wantProcessed: true,
+			wantStatus:    entity.JobStatusDead,
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Job cancelled while running keeps its status",
+			job:           &entity.Job{Id: 1, UserId: 1, Type: entity.JobTypeSummarize, Status: entity.JobStatusRunning, Attempts: 1, MaxAttempts: 3},
+			runs:          true,
+			cancelled:     true,
+			wantProcessed: true,
+			wantStatus:    entity.JobStatusCancelled,
+			assertion:     assert.NoError,
+		},
+		{
+			name:      "Claim error",
+			claimErr:  errors.New("connection reset"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotGetJob.Error()).WithDebug("connection reset"),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.job != nil {
+				tt.job.Params = entity.JobParams{StartDate: &suite.startDate, EndDate: &suite.endDate, Format: tt.format, ScheduleId: 5, Recipients: tt.recipients}
+			}
+			suite.mockRepo.EXPECT().ClaimJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.job, tt.claimErr)
+
+			// jobs without a format were queued as excel
+			wantFormat := tt.format
+			if wantFormat == "" {
+				wantFormat = pkg.ReportFormatXLSX
+			}
+
+			if tt.runs {
+				datas := []orderEntity.OrdersSummarize{}
+				taxes := []orderEntity.OrderTax{}
+				suite.mockOrder.EXPECT().GetOrdersSummarize(gomock.Any(), suite.startDate, suite.endDate).Return(&datas, nil)
+				suite.mockOrder.EXPECT().GetTaxesSummarize(gomock.Any(), suite.startDate, suite.endDate).Return(&taxes, nil)
+				report := pkg.NewSummarizeReport(&datas, &taxes, suite.startDate, suite.endDate)
+				suite.mockDocuments.EXPECT().GenerateSummarize(report, wantFormat).Return("summarize."+string(wantFormat), tt.genErr)
+			}
+
+			if tt.runs && tt.genErr == nil && len(tt.recipients) > 0 {
+				suite.mockDocuments.EXPECT().ReadDocument("summarize."+string(wantFormat)).Return([]byte("workbook"), nil)
+				suite.mockMailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, mail *entity.Mail) error {
+					suite.Equal(tt.recipients, mail.To, "report should be mailed to the recipients")
+					suite.Equal("Orders summary 2024-01-01 to 2024-01-31", mail.Subject, "subject should name the days covered")
+					suite.Equal([]entity.Attachment{{Name: "summarize-2024-01-01-2024-02-01.xlsx", ContentType: wantFormat.ContentType(), Data: []byte("workbook")}}, mail.Attachments, "report should be attached")
+
+					return tt.mailErr
+				})
+			}
+
+			var saved entity.Job
+			if tt.job != nil {
+				locked := *tt.job
+				if tt.cancelled {
+					locked.Status = entity.JobStatusCancelled
+				}
+
+				suite.mockRepo.EXPECT().UpdateJob(gomock.Any(), tt.job.Id, gomock.Any()).DoAndReturn(func(ctx context.Context, jobId int, callbackFn func(job *entity.Job) error) error {
+					err := callbackFn(&locked)
+					saved = locked
+					return err
+				})
+			}
+
+			before := time.Now()
+			processed, err := suite.usecase.ProcessNextJob(context.Background())
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			suite.Equal(tt.wantProcessed, processed, "processed should be reported correctly")
+
+			if tt.job == nil {
+				return
+			}
+
+			suite.Equal(tt.wantStatus, saved.GetStatusSafe(), "status should be saved")
+			switch tt.wantStatus {
+			case entity.JobStatusSucceeded:
+				suite.Equal("summarize."+string(wantFormat), saved.FileName, "file should be recorded")
+			case entity.JobStatusQueued:
+				wantLastError := genErr
+				if tt.mailErr != nil {
+					wantLastError = tt.mailErr
+				}
+				suite.Equal(wantLastError.Error(), saved.LastError, "failure should be recorded")
+				suite.WithinDuration(before.Add(tt.wantRetryIn), saved.AvailableAt, time.Second, "retry should be delayed")
+			case entity.JobStatusDead:
+				suite.NotEmpty(saved.LastError, "failure should be recorded")
+				suite.NotNil(saved.FinishedAt, "dead job should be finished")
+			}
+		})
+	}
+}
+
+func (suite *ReportUsecaseTestSuite) TestCreateSchedule() {
+	repoErr := errors.New("connection refused")
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		data      entity.ScheduleRequest
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Admin schedules a weekly summary",
+			ctx:       suite.adminCtx,
+			data:      entity.ScheduleRequest{Name: "Weekly", Cron: "0 8 * * MON", Recipients: []string{"Finance <finance@example.com>"}},
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Member cannot schedule the summary",
+			ctx:       suite.memberCtx,
+			data:      entity.ScheduleRequest{Name: "Weekly", Cron: "0 8 * * MON", Recipients: []string{"finance@example.com"}},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrScheduleAdminOnly.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Schedule that never runs",
+			ctx:       suite.adminCtx,
+			data:      entity.ScheduleRequest{Name: "Never", Cron: "0 8 30 2 *", Recipients: []string{"finance@example.com"}},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrScheduleNeverRuns.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo error",
+			ctx:       suite.adminCtx,
+			data:      entity.ScheduleRequest{Name: "Weekly", Cron: "0 8 * * MON", Recipients: []string{"finance@example.com"}},
+			callRepo:  true,
+			repoErr:   repoErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotCreateSchedule.Error()).WithDebug(repoErr.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().CreateSchedule(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, schedule *entity.Schedule) error {
+					schedule.SetId(1)
+					return tt.repoErr
+				})
+			}
+
+			schedule, err := suite.usecase.CreateSchedule(tt.ctx, &tt.data)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(1, schedule.UserId, "schedule should belong to the requester")
+				suite.Equal([]string{"finance@example.com"}, schedule.Recipients, "recipients should be reduced to their address")
+				suite.Equal(pkg.ReportFormatXLSX, schedule.Format, "format should default to xlsx")
+				suite.Equal(time.Monday, schedule.NextRunAt.Weekday(), "next run should be on the cron day")
+				suite.True(schedule.Active, "schedule should start active")
+			}
+		})
+	}
+}
+
+func (suite *ReportUsecaseTestSuite) TestUpdateSchedule() {
+	inactive := false
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		schedule  entity.Schedule
+		data      entity.ScheduleRequest
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Admin pauses the schedule",
+			ctx:       suite.adminCtx,
+			schedule:  entity.Schedule{Id: 1, UserId: 1, Cron: "@daily", Timezone: "UTC", Active: true},
+			data:      entity.ScheduleRequest{Name: "Daily", Cron: "@daily", Recipients: []string{"finance@example.com"}, Active: &inactive},
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Schedule of another admin is missing",
+			ctx:       suite.adminCtx,
+			schedule:  entity.Schedule{Id: 1, UserId: 3, Cron: "@daily", Timezone: "UTC", Active: true},
+			data:      entity.ScheduleRequest{Name: "Daily", Cron: "@daily", Recipients: []string{"finance@example.com"}},
+			callRepo:  true,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrScheduleNotFound.Error()).WithDebug(entity.ErrScheduleNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown schedule",
+			ctx:       suite.adminCtx,
+			data:      entity.ScheduleRequest{Name: "Daily", Cron: "@daily", Recipients: []string{"finance@example.com"}},
+			callRepo:  true,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrScheduleNotFound.Error()).WithDebug(core.ErrRecordNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Member cannot update schedules",
+			ctx:       suite.memberCtx,
+			data:      entity.ScheduleRequest{Name: "Daily", Cron: "@daily", Recipients: []string{"finance@example.com"}},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrScheduleAdminOnly.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().UpdateSchedule(gomock.Any(), 1, gomock.Any()).DoAndReturn(func(ctx context.Context, scheduleId int, callbackFn func(schedule *entity.Schedule) error) error {
+					if tt.repoErr != nil {
+						return tt.repoErr
+					}
+
+					schedule := tt.schedule
+					return callbackFn(&schedule)
+				})
+			}
+
+			schedule, err := suite.usecase.UpdateSchedule(tt.ctx, 1, &tt.data)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.False(schedule.Active, "schedule should be paused")
+				suite.NotNil(schedule.UpdatedAt, "update time should be set")
+			}
+		})
+	}
+}
+
+func (suite *ReportUsecaseTestSuite) TestQueueDueSchedules() {
+	lockErr := errors.New("redis down")
+	repoErr := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		acquired   bool
+		lockErr    error
+		schedules  []entity.Schedule
+		repoErr    error
+		wantQueued int
+		wantErr    error
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "Due schedules are queued",
+			acquired:   true,
+			schedules:  []entity.Schedule{{Id: 1, UserId: 1, Cron: "@daily", Timezone: "Asia/Jakarta", Format: pkg.ReportFormatCSV, RangeDays: 7, Recipients: []string{"finance@example.com"}, Active: true}},
+			wantQueued: 1,
+			assertion:  assert.NoError,
+		},
+		{
+			name:       "Schedule that never runs again is paused",
+			acquired:   true,
+			schedules:  []entity.Schedule{{Id: 1, UserId: 1, Cron: "0 0 30 2 *", Timezone: "UTC", Active: true}},
+			wantQueued: 0,
+			assertion:  assert.NoError,
+		},
+		{
+			name:       "Another scheduler holds the lock",
+			acquired:   false,
+			wantQueued: 0,
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Lock error",
+			lockErr:   lockErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotQueueSchedules.Error()).WithDebug(lockErr.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo error",
+			acquired:  true,
+			repoErr:   repoErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotQueueSchedules.Error()).WithDebug(repoErr.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			var token string
+			suite.mockLock.EXPECT().Acquire(gomock.Any(), "report_scheduler", gomock.Any(), 60).DoAndReturn(func(ctx context.Context, name, lockToken string, expiration int) (bool, error) {
+				token = lockToken
+				return tt.acquired, tt.lockErr
+			})
+
+			if tt.acquired {
+				suite.mockRepo.EXPECT().QueueDueSchedules(gomock.Any(), gomock.Any(), 100, gomock.Any()).DoAndReturn(func(ctx context.Context, now time.Time, limit int, callbackFn func(schedule *entity.Schedule) (*entity.Job, error)) (int, error) {
+					if tt.repoErr != nil {
+						return 0, tt.repoErr
+					}
+
+					queued := 0
+					for i := range tt.schedules {
+						schedule := tt.schedules[i]
+						job, err := callbackFn(&schedule)
+						suite.NoError(err, "callback should not fail")
+
+						if job == nil {
+							suite.False(schedule.Active, "schedule should be paused")
+							continue
+						}
+
+						suite.Equal(entity.JobTypeSummarize, job.Type, "schedule should queue a summary")
+						suite.Equal(schedule.Recipients, job.Params.Recipients, "job should mail the recipients")
+						suite.Equal(schedule.Id, job.Params.ScheduleId, "job should point at its schedule")
+						suite.Equal(3, job.MaxAttempts, "job should use the configured attempts")
+						suite.True(schedule.NextRunAt.After(now), "schedule should move to its next run")
+						queued++
+					}
+
+					return queued, nil
+				})
+				suite.mockLock.EXPECT().Release(gomock.Any(), "report_scheduler", gomock.Any()).DoAndReturn(func(ctx context.Context, name, lockToken string) error {
+					suite.Equal(token, lockToken, "lock should be released with its token")
+					return nil
+				})
+			}
+
+			queued, err := suite.usecase.QueueDueSchedules(context.Background())
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			suite.Equal(tt.wantQueued, queued, "queued schedules should be counted")
+		})
+	}
+}
+
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
+
+func TestReportUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ReportUsecaseTestSuite))
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"order_service/pkg"
+	"order_service/services/report/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNext(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{name: "Every quarter hour", expr: "*/15 * * * *", from: time.Date(2024, 1, 1, 10, 7, 30, 0, time.UTC), want: time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)},
+		{name: "Strictly after the current minute", expr: "0 8 * * *", from: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), want: time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC)},
+		{name: "Monday morning in Jakarta", expr: "0 8 * * MON", from: time.Date(2024, 1, 3, 12, 0, 0, 0, jakarta), want: time.Date(2024, 1, 8, 8, 0, 0, 0, jakarta)},
+		{name: "Working hours", expr: "30 9-17/4 * * MON-FRI", from: time.Date(2024, 1, 5, 18, 0, 0, 0, time.UTC), want: time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC)},
+		{name: "Sunday written as 7", expr: "0 0 * * 7", from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{name: "Either day field matches", expr: "0 0 15 * FRI", from: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), want: time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "Monthly over the year end", expr: "@monthly", from: time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC), want: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "Leap day", expr: "0 0 29 FEB *", from: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "Never", expr: "0 0 30 2 *", from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := pkg.ParseCron(tt.expr)
+			require.NoError(t, err)
+
+			got := cron.Next(tt.from)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "* * * FOO *", "@every 5m"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := pkg.ParseCron(expr)
+			assert.Equal(t, pkg.ErrInvalidCron, err)
+		})
+	}
+}
+
+func TestScheduleRequestValidate(t *testing.T) {
+	valid := entity.ScheduleRequest{Name: "Weekly", Cron: "0 8 * * MON", Recipients: []string{"finance@example.com"}}
+
+	tests := []struct {
+		name    string
+		modify  func(data *entity.ScheduleRequest)
+		wantErr error
+	}{
+		{name: "Valid", modify: func(data *entity.ScheduleRequest) {}},
+		{name: "Named recipient", modify: func(data *entity.ScheduleRequest) { data.Recipients = []string{"Finance <finance@example.com>"} }},
+		{name: "Invalid cron", modify: func(data *entity.ScheduleRequest) { data.Cron = "every monday" }, wantErr: pkg.ErrInvalidCron},
+		{name: "Unknown timezone", modify: func(data *entity.ScheduleRequest) { data.Timezone = "Mars/Olympus" }, wantErr: pkg.ErrInvalidTimezone},
+		{name: "Local timezone", modify: func(data *entity.ScheduleRequest) { data.Timezone = "Local" }, wantErr: pkg.ErrInvalidTimezone},
+		{name: "Unknown format", modify: func(data *entity.ScheduleRequest) { data.Format = "pdf" }, wantErr: pkg.ErrUnsupportedReportFormat},
+		{name: "Range too long", modify: func(data *entity.ScheduleRequest) { data.RangeDays = 367 }, wantErr: entity.ErrInvalidRangeDays},
+		{name: "No recipients", modify: func(data *entity.ScheduleRequest) { data.Recipients = nil }, wantErr: entity.ErrInvalidRecipients},
+		{name: "Invalid recipient", modify: func(data *entity.ScheduleRequest) { data.Recipients = []string{"finance"} }, wantErr: entity.ErrInvalidRecipients},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := valid
+			tt.modify(&data)
+
+			assert.Equal(t, tt.wantErr, data.Validate())
+		})
+	}
+}
+
+func TestScheduleQueue(t *testing.T) {
+	schedule := entity.Schedule{Id: 5, UserId: 1, Cron: "0 8 * * MON", Timezone: "Asia/Jakarta", Format: pkg.ReportFormatCSV, RangeDays: 7, Recipients: []string{"finance@example.com"}, Active: true}
+
+	// 8:00 on Monday the 8th in Jakarta is still Sunday in UTC
+	now := time.Date(2024, 1, 8, 1, 0, 30, 0, time.UTC)
+
+	job, err := schedule.Queue(now, 3)
+	require.NoError(t, err)
+
+	wantStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, entity.JobTypeSummarize, job.Type)
+	assert.Equal(t, 1, job.UserId)
+	assert.Equal(t, 3, job.MaxAttempts)
+	assert.Equal(t, entity.JobParams{StartDate: &wantStart, EndDate: &wantEnd, Format: pkg.ReportFormatCSV, ScheduleId: 5, Recipients: []string{"finance@example.com"}}, job.Params)
+	assert.Equal(t, time.Date(2024, 1, 15, 1, 0, 0, 0, time.UTC), schedule.NextRunAt)
+	assert.Equal(t, &now, schedule.LastRunAt)
+}
+
+func TestScheduleRequestApplyTo(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	schedule := entity.NewSchedule(1, now)
+
+	err := entity.ScheduleRequest{Name: " Daily ", Cron: "@daily", Recipients: []string{"Finance <finance@example.com>"}}.ApplyTo(&schedule, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Daily", schedule.Name)
+	assert.Equal(t, "UTC", schedule.Timezone)
+	assert.Equal(t, pkg.ReportFormatXLSX, schedule.Format)
+	assert.Equal(t, entity.DefaultScheduleRangeDays, schedule.RangeDays)
+	assert.Equal(t, []string{"finance@example.com"}, schedule.Recipients)
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), schedule.NextRunAt)
+	assert.True(t, schedule.Active)
+}
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	orderEntity "order_service/services/order/entity"
+	"time"
+)
+
+// OrderReader loads what the reports are built from. It is implemented by the order usecase.
+type OrderReader interface {
+	GetOrdersSummarize(ctx context.Context, startDate, endDate time.Time) (*[]orderEntity.OrdersSummarize, error)
+	GetTaxesSummarize(ctx context.Context, startDate, endDate time.Time) (*[]orderEntity.OrderTax, error)
+	GetOrder(ctx context.Context, userId, orderId int) (*orderEntity.Order, error)
+}
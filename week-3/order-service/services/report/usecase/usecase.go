@@ -0,0 +1,522 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/report/entity"
+	reportFile "order_service/services/report/repository/file"
+	reportRepo "order_service/services/report/repository/postgres"
+	reportLock "order_service/services/report/repository/redis"
+	reportMailer "order_service/services/report/repository/smtp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	schedulerLockName = "report_scheduler"
+	scheduleBatchSize = 100
+)
+
+type ReportUsecase interface {
+	CreateJob(ctx context.Context, data *entity.JobRequest) (*entity.Job, error)
+	GetJob(ctx context.Context, jobId int) (*entity.Job, error)
+	GetJobDocument(ctx context.Context, jobId int) (*entity.Job, error)
+	CancelJob(ctx context.Context, jobId int) (*entity.Job, error)
+	RetryJob(ctx context.Context, jobId int) (*entity.Job, error)
+	ProcessNextJob(ctx context.Context) (bool, error)
+	RunWorkers(ctx context.Context, workers int, interval time.Duration)
+	CreateSchedule(ctx context.Context, data *entity.ScheduleRequest) (*entity.Schedule, error)
+	GetSchedules(ctx context.Context) (*[]entity.Schedule, error)
+	UpdateSchedule(ctx context.Context, scheduleId int, data *entity.ScheduleRequest) (*entity.Schedule, error)
+	DeleteSchedule(ctx context.Context, scheduleId int) error
+	QueueDueSchedules(ctx context.Context) (int, error)
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+type reportUsecase struct {
+	repo            reportRepo.ReportRepository
+	documents       reportFile.DocumentGenerator
+	order           OrderReader
+	mailer          reportMailer.Mailer
+	lock            reportLock.SchedulerLock
+	maxAttempts     int
+	retryDelay      time.Duration
+	jobTimeout      time.Duration
+	lockExpireInSec int
+}
+
+// NewUsecase builds the report usecase. A job gets maxAttempts runs, the n-th failed run is retried after
+// n * retryDelay. A run is given up after jobTimeout and its job can then be claimed by another worker.
+// Only the scheduler holding lock queues the due schedules, the lock is let go after lockExpireInSec at worst.
+func NewUsecase(repo reportRepo.ReportRepository, documents reportFile.DocumentGenerator, order OrderReader, mailer reportMailer.Mailer, lock reportLock.SchedulerLock, maxAttempts int, retryDelay, jobTimeout time.Duration, lockExpireInSec int) ReportUsecase {
+	return &reportUsecase{
+		repo,
+		documents,
+		order,
+		mailer,
+		lock,
+		maxAttempts,
+		retryDelay,
+		jobTimeout,
+		lockExpireInSec,
+	}
+}
+
+func getRequester(ctx context.Context) (int, bool, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return 0, false, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return int(uid.GetLocalID()), uid.GetRole() == 1, nil
+}
+
+func (uc *reportUsecase) CreateJob(ctx context.Context, data *entity.JobRequest) (*entity.Job, error) {
+	userId, admin, err := getRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch data.Type {
+	case entity.JobTypeSummarize:
+		if !admin {
+			return nil, core.ErrBadRequest.WithError(entity.ErrSummarizeAdminOnly.Error())
+		}
+	case entity.JobTypeInvoice:
+		// fail now rather than in the worker when the order is not the requester's
+		_, err := uc.order.GetOrder(ctx, userId, data.OrderId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	job := entity.NewJob(userId, data.Type, data.ToJobParams(), uc.maxAttempts)
+
+	err = uc.repo.CreateJob(ctx, &job)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotCreateJob.Error()).WithDebug(err.Error())
+	}
+
+	return &job, nil
+}
+
+// GetJob returns a job of the requester, admin can see every job.
+func (uc *reportUsecase) GetJob(ctx context.Context, jobId int) (*entity.Job, error) {
+	userId, admin, err := getRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := uc.repo.GetJob(ctx, jobId)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return nil, core.ErrNotFound.WithError(entity.ErrJobNotFound.Error()).WithDebug(err.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetJob.Error()).WithDebug(err.Error())
+	}
+
+	// someone else's job is reported as missing, not as forbidden
+	if !admin && job.GetUserIdSafe() != userId {
+		return nil, core.ErrNotFound.WithError(entity.ErrJobNotFound.Error())
+	}
+
+	return job, nil
+}
+
+// GetJobDocument returns a succeeded job of the requester, its file can be sent.
+func (uc *reportUsecase) GetJobDocument(ctx context.Context, jobId int) (*entity.Job, error) {
+	job, err := uc.GetJob(ctx, jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.GetStatusSafe() != entity.JobStatusSucceeded {
+		return nil, core.ErrConfict.WithError(entity.ErrJobNotReady.Error())
+	}
+
+	return job, nil
+}
+
+func (uc *reportUsecase) CancelJob(ctx context.Context, jobId int) (*entity.Job, error) {
+	return uc.updateOwnJob(ctx, jobId, func(job *entity.Job) error {
+		return job.Cancel(time.Now())
+	})
+}
+
+func (uc *reportUsecase) RetryJob(ctx context.Context, jobId int) (*entity.Job, error) {
+	return uc.updateOwnJob(ctx, jobId, func(job *entity.Job) error {
+		return job.Retry(time.Now())
+	})
+}
+
+func (uc *reportUsecase) updateOwnJob(ctx context.Context, jobId int, callbackFn func(job *entity.Job) error) (*entity.Job, error) {
+	userId, admin, err := getRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated entity.Job
+
+	err = uc.repo.UpdateJob(ctx, jobId, func(job *entity.Job) error {
+		if !admin && job.GetUserIdSafe() != userId {
+			return entity.ErrJobNotFound
+		}
+
+		err := callbackFn(job)
+		if err != nil {
+			return err
+		}
+
+		updated = *job
+
+		return nil
+	})
+	if err != nil {
+		switch err {
+		case core.ErrRecordNotFound, entity.ErrJobNotFound:
+			return nil, core.ErrNotFound.WithError(entity.ErrJobNotFound.Error()).WithDebug(err.Error())
+		case entity.ErrJobNotCancellable, entity.ErrJobNotRetriable:
+			return nil, core.ErrConfict.WithError(err.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotUpdateJob.Error()).WithDebug(err.Error())
+	}
+
+	return &updated, nil
+}
+
+// ProcessNextJob runs the oldest due job, it reports false when there was nothing to run.
+func (uc *reportUsecase) ProcessNextJob(ctx context.Context) (bool, error) {
+	now := time.Now()
+
+	job, err := uc.repo.ClaimJob(ctx, now, now.Add(-uc.jobTimeout))
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return false, nil
+		}
+
+		return false, core.ErrInternalServerError.WithError(entity.ErrCannotGetJob.Error()).WithDebug(err.Error())
+	}
+
+	// a job claimed again past its last attempt never got to finish, most likely it keeps taking its
+	// worker down
+	var runErr error
+	var fileName string
+	if job.Attempts > job.MaxAttempts {
+		runErr = entity.ErrJobTimedOut
+	} else {
+		runCtx, cancel := context.WithTimeout(ctx, uc.jobTimeout)
+		fileName, runErr = uc.runJob(runCtx, job)
+		cancel()
+	}
+
+	err = uc.repo.UpdateJob(ctx, job.GetIdSafe(), func(job *entity.Job) error {
+		if runErr != nil {
+			return job.Fail(runErr, time.Now(), uc.retryDelay*time.Duration(job.Attempts))
+		}
+
+		return job.Complete(fileName, time.Now())
+	})
+	if err != nil {
+		// the job was cancelled while it ran, its result is not wanted anymore
+		if err == entity.ErrJobNotRunning {
+			return true, nil
+		}
+
+		return true, core.ErrInternalServerError.WithError(entity.ErrCannotUpdateJob.Error()).WithDebug(err.Error())
+	}
+
+	if runErr != nil {
+		log.Printf("report job %d error: %v", job.GetIdSafe(), runErr)
+	}
+
+	return true, nil
+}
+
+func (uc *reportUsecase) runJob(ctx context.Context, job *entity.Job) (string, error) {
+	params := job.GetParamsSafe()
+
+	switch job.GetTypeSafe() {
+	case entity.JobTypeSummarize:
+		if params.StartDate == nil || params.EndDate == nil {
+			return "", entity.ErrInvalidDateRange
+		}
+
+		datas, err := uc.order.GetOrdersSummarize(ctx, *params.StartDate, *params.EndDate)
+		if err != nil {
+			return "", err
+		}
+
+		taxes, err := uc.order.GetTaxesSummarize(ctx, *params.StartDate, *params.EndDate)
+		if err != nil {
+			return "", err
+		}
+
+		// jobs queued before formats existed have none and were excel
+		format := params.Format
+		if format == "" {
+			format = pkg.ReportFormatXLSX
+		}
+
+		fileName, err := uc.documents.GenerateSummarize(pkg.NewSummarizeReport(datas, taxes, *params.StartDate, *params.EndDate), format)
+		if err != nil {
+			return "", err
+		}
+
+		// a failed delivery fails the run, the report is generated and sent again on the next attempt
+		if len(params.Recipients) > 0 {
+			err = uc.mailReport(ctx, job, fileName, format)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		return fileName, nil
+	case entity.JobTypeInvoice:
+		order, err := uc.order.GetOrder(ctx, job.GetUserIdSafe(), params.OrderId)
+		if err != nil {
+			return "", err
+		}
+
+		return uc.documents.GenerateInvoice(order)
+	}
+
+	return "", entity.ErrInvalidJobType
+}
+
+func (uc *reportUsecase) mailReport(ctx context.Context, job *entity.Job, fileName string, format pkg.ReportFormat) error {
+	params := job.GetParamsSafe()
+
+	data, err := uc.documents.ReadDocument(fileName)
+	if err != nil {
+		return err
+	}
+
+	generated := *job
+	generated.FileName = fileName
+
+	// the summary stops before its end date, the last day it covers is the one before
+	startDate := params.StartDate.Format(time.DateOnly)
+	lastDate := params.EndDate.AddDate(0, 0, -1).Format(time.DateOnly)
+
+	return uc.mailer.Send(ctx, &entity.Mail{
+		To:      params.Recipients,
+		Subject: fmt.Sprintf("Orders summary %s to %s", startDate, lastDate),
+		Body:    fmt.Sprintf("The orders summary from %s to %s is attached.\n\nIt was sent by report schedule %d, job %d.\n", startDate, lastDate, params.ScheduleId, job.GetIdSafe()),
+		Attachments: []entity.Attachment{
+			{Name: generated.GetDownloadNameSafe(), ContentType: format.ContentType(), Data: data},
+		},
+	})
+}
+
+// RunWorkers starts workers that run due jobs one after another, a worker with nothing to do waits interval
+// before it looks again. It returns once ctx is done and every worker has stopped.
+func (uc *reportUsecase) RunWorkers(ctx context.Context, workers int, interval time.Duration) {
+	done := make(chan struct{})
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+
+			for {
+				processed, err := uc.ProcessNextJob(ctx)
+				if err != nil {
+					log.Println("process report job error:", err)
+				}
+				if processed && err == nil {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(interval):
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < workers; i++ {
+		<-done
+	}
+}
+
+// CreateSchedule stores a report schedule of the requester, only admin can schedule the orders summary.
+func (uc *reportUsecase) CreateSchedule(ctx context.Context, data *entity.ScheduleRequest) (*entity.Schedule, error) {
+	userId, admin, err := getRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return nil, core.ErrBadRequest.WithError(entity.ErrScheduleAdminOnly.Error())
+	}
+
+	now := time.Now().UTC()
+	schedule := entity.NewSchedule(userId, now)
+
+	err = data.ApplyTo(&schedule, now)
+	if err != nil {
+		return nil, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	err = uc.repo.CreateSchedule(ctx, &schedule)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotCreateSchedule.Error()).WithDebug(err.Error())
+	}
+
+	return &schedule, nil
+}
+
+// GetSchedules returns the schedules of the requester.
+func (uc *reportUsecase) GetSchedules(ctx context.Context) (*[]entity.Schedule, error) {
+	userId, admin, err := getRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return nil, core.ErrBadRequest.WithError(entity.ErrScheduleAdminOnly.Error())
+	}
+
+	schedules, err := uc.repo.GetSchedules(ctx, userId)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetSchedules.Error()).WithDebug(err.Error())
+	}
+
+	return schedules, nil
+}
+
+// UpdateSchedule replaces a schedule of the requester, its next run is worked out again from now.
+func (uc *reportUsecase) UpdateSchedule(ctx context.Context, scheduleId int, data *entity.ScheduleRequest) (*entity.Schedule, error) {
+	userId, admin, err := getRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return nil, core.ErrBadRequest.WithError(entity.ErrScheduleAdminOnly.Error())
+	}
+
+	var updated entity.Schedule
+
+	err = uc.repo.UpdateSchedule(ctx, scheduleId, func(schedule *entity.Schedule) error {
+		// another admin's schedule is reported as missing
+		if schedule.GetUserIdSafe() != userId {
+			return entity.ErrScheduleNotFound
+		}
+
+		now := time.Now().UTC()
+
+		err := data.ApplyTo(schedule, now)
+		if err != nil {
+			return err
+		}
+		schedule.UpdatedAt = &now
+
+		updated = *schedule
+
+		return nil
+	})
+	if err != nil {
+		switch err {
+		case core.ErrRecordNotFound, entity.ErrScheduleNotFound:
+			return nil, core.ErrNotFound.WithError(entity.ErrScheduleNotFound.Error()).WithDebug(err.Error())
+		case entity.ErrInvalidRecipients, pkg.ErrInvalidTimezone, entity.ErrScheduleNeverRuns, pkg.ErrInvalidCron:
+			return nil, core.ErrBadRequest.WithError(err.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotUpdateSchedule.Error()).WithDebug(err.Error())
+	}
+
+	return &updated, nil
+}
+
+func (uc *reportUsecase) DeleteSchedule(ctx context.Context, scheduleId int) error {
+	userId, admin, err := getRequester(ctx)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return core.ErrBadRequest.WithError(entity.ErrScheduleAdminOnly.Error())
+	}
+
+	err = uc.repo.DeleteSchedule(ctx, userId, scheduleId)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return core.ErrNotFound.WithError(entity.ErrScheduleNotFound.Error()).WithDebug(err.Error())
+		}
+
+		return core.ErrInternalServerError.WithError(entity.ErrCannotDeleteSchedule.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// QueueDueSchedules queues a job for every due schedule and reports how many were queued. Only one
+// scheduler does it at a time, the others report 0 until the lock is free.
+func (uc *reportUsecase) QueueDueSchedules(ctx context.Context) (int, error) {
+	token := uuid.NewString()
+
+	acquired, err := uc.lock.Acquire(ctx, schedulerLockName, token, uc.lockExpireInSec)
+	if err != nil {
+		return 0, core.ErrInternalServerError.WithError(entity.ErrCannotQueueSchedules.Error()).WithDebug(err.Error())
+	}
+	if !acquired {
+		return 0, nil
+	}
+	defer func() {
+		err := uc.lock.Release(context.WithoutCancel(ctx), schedulerLockName, token)
+		if err != nil {
+			log.Println("release report scheduler lock error:", err)
+		}
+	}()
+
+	total := 0
+	for {
+		now := time.Now().UTC()
+
+		queued, err := uc.repo.QueueDueSchedules(ctx, now, scheduleBatchSize, func(schedule *entity.Schedule) (*entity.Job, error) {
+			job, err := schedule.Queue(now, uc.maxAttempts)
+			if err != nil {
+				// a schedule without a next run would stay due forever
+				log.Printf("report schedule %d error: %v", schedule.GetIdSafe(), err)
+				schedule.Active = false
+				schedule.UpdatedAt = &now
+
+				return nil, nil
+			}
+
+			return &job, nil
+		})
+		if err != nil {
+			return total, core.ErrInternalServerError.WithError(entity.ErrCannotQueueSchedules.Error()).WithDebug(err.Error())
+		}
+
+		total += queued
+		if queued < scheduleBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// RunScheduler queues the due schedules every interval until ctx is done.
+func (uc *reportUsecase) RunScheduler(ctx context.Context, interval time.Duration) {
+	for {
+		queued, err := uc.QueueDueSchedules(ctx)
+		if err != nil {
+			log.Println("queue report schedules error:", err)
+		} else if queued > 0 {
+			log.Printf("queued %d scheduled reports", queued)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
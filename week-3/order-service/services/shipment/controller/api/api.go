@@ -0,0 +1,130 @@
+package api
+
+import (
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/shipment/entity"
+	shipmentUc "order_service/services/shipment/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderSignature carries the hex encoded HMAC-SHA256 of a carrier callback's body.
+const HeaderSignature = "X-Signature"
+
+type ShipmentService interface {
+	CreateShipment(*fiber.Ctx) error
+	GetShipments(*fiber.Ctx) error
+	ReceiveCarrierEvent(*fiber.Ctx) error
+}
+
+type service struct {
+	usecase shipmentUc.ShipmentUsecase
+}
+
+func NewService(uc shipmentUc.ShipmentUsecase) ShipmentService {
+	return &service{
+		usecase: uc,
+	}
+}
+
+// Create Shipment godoc
+// @summary Create Shipment
+// @description Record the carrier and tracking number of a parcel of a fulfilling or shipped order, the order is moved to shipped with its first shipment, only for admin
+// @tags shipments
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param orderID path int true "Order's ID"
+// @param payload body entity.ShipmentRequest true "Shipment request body"
+// @success 201 {object} entity.Shipment
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /orders/:orderID/shipments [post]
+func (srv *service) CreateShipment(c *fiber.Ctx) error {
+	orderId, err := c.ParamsInt("orderID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	var data entity.ShipmentRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidTrackingNumber.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	shipment, err := srv.usecase.CreateShipment(ctx, orderId, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(core.ResponseData(shipment))
+}
+
+// Get Shipments godoc
+// @summary Get Shipments
+// @description Get the shipments of an order with their tracking history, oldest event first
+// @tags shipments
+// @produce json
+// @security BearerAuth
+// @param orderID path int true "Order's ID"
+// @success 200 {array} entity.Shipment
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /orders/:orderID/shipments [get]
+func (srv *service) GetShipments(c *fiber.Ctx) error {
+	orderId, err := c.ParamsInt("orderID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	shipments, err := srv.usecase.GetShipments(ctx, orderId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(shipments))
+}
+
+// Receive Carrier Event godoc
+// @summary Receive Carrier Event
+// @description Carrier status callback, the body has to be signed with the webhook secret in the X-Signature header as hex encoded HMAC-SHA256. A callback sent again with the same event id is accepted and ignored
+// @tags shipments
+// @accept application/json
+// @produce json
+// @param X-Signature header string true "HMAC-SHA256 of the body"
+// @param payload body entity.CarrierEvent true "Carrier event"
+// @success 200
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /shipments/webhook [post]
+func (srv *service) ReceiveCarrierEvent(c *fiber.Ctx) error {
+	err := srv.usecase.ReceiveCarrierEvent(c.Context(), c.Body(), c.Get(HeaderSignature))
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
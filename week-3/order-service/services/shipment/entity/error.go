@@ -0,0 +1,21 @@
+package entity
+
+import "errors"
+
+var (
+	ErrInvalidCarrier        = errors.New("carrier must be 2 to 30 lower case letters, digits, dashes or underscores")
+	ErrInvalidTrackingNumber = errors.New("tracking number must be 4 to 40 letters or digits")
+	ErrInvalidShipmentStatus = errors.New("shipment status is invalid")
+	ErrInvalidCarrierEvent   = errors.New("carrier event must have an event id, a carrier, a tracking number, a status and when it occurred")
+	ErrInvalidSignature      = errors.New("carrier event signature is invalid")
+	ErrWebhookDisabled       = errors.New("carrier webhook has no secret configured")
+	ErrTrackingNumberTaken   = errors.New("tracking number is already used by another shipment")
+	ErrOrderNotShippable     = errors.New("only fulfilling or shipped orders can be shipped")
+	ErrOrderNotFound         = errors.New("order cannot be found")
+	ErrShipmentNotFound      = errors.New("shipment cannot be found")
+	ErrDuplicateEvent        = errors.New("carrier event is already recorded")
+	ErrInvalidMemory         = errors.New("invalid memory")
+	ErrCannotGetShipments    = errors.New("shipments cannot be get")
+	ErrCannotCreateShipment  = errors.New("shipment cannot be create")
+	ErrCannotRecordEvent     = errors.New("carrier event cannot be record")
+)
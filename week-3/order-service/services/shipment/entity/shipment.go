@@ -0,0 +1,172 @@
+package entity
+
+import "time"
+
+type ShipmentStatus string
+
+const (
+	ShipmentStatusLabelCreated   ShipmentStatus = "label_created"
+	ShipmentStatusInTransit      ShipmentStatus = "in_transit"
+	ShipmentStatusOutForDelivery ShipmentStatus = "out_for_delivery"
+	ShipmentStatusDelivered      ShipmentStatus = "delivered"
+	ShipmentStatusException      ShipmentStatus = "exception"
+	ShipmentStatusReturned       ShipmentStatus = "returned"
+)
+
+// CarrierActorId is the actor of the order status changes made by carrier callbacks.
+const CarrierActorId = 0
+
+func (status ShipmentStatus) IsValid() bool {
+	switch status {
+	case ShipmentStatusLabelCreated, ShipmentStatusInTransit, ShipmentStatusOutForDelivery, ShipmentStatusDelivered, ShipmentStatusException, ShipmentStatusReturned:
+		return true
+	}
+
+	return false
+}
+
+// IsFinal tells whether the parcel has come to rest, a delivered or returned shipment does not move on.
+func (status ShipmentStatus) IsFinal() bool {
+	return status == ShipmentStatusDelivered || status == ShipmentStatusReturned
+}
+
+// Shipment is a parcel of an order handed to Carrier, UserId is the owner of the order.
+// Status follows the latest event of the Events timeline, LastEventAt is when that event occurred.
+type Shipment struct {
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      *time.Time      `json:"updated_at"`
+	LastEventAt    *time.Time      `json:"last_event_at"`
+	Events         []ShipmentEvent `json:"events"`
+	Carrier        string          `json:"carrier"`
+	TrackingNumber string          `json:"tracking_number"`
+	Status         ShipmentStatus  `json:"status"`
+	Id             int             `json:"id"`
+	OrderId        int             `json:"order_id"`
+	UserId         int             `json:"user_id"`
+}
+
+// ShipmentEvent is one step of a shipment's timeline. EventId is the carrier's own id of the callback,
+// it is empty for the events recorded here rather than reported by the carrier.
+type ShipmentEvent struct {
+	OccurredAt  time.Time      `json:"occurred_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	EventId     string         `json:"-"`
+	Status      ShipmentStatus `json:"status"`
+	Location    string         `json:"location"`
+	Description string         `json:"description"`
+	Id          int            `json:"id"`
+	ShipmentId  int            `json:"shipment_id"`
+}
+
+// NewShipment starts the shipment of an order, its timeline opens with the label being created.
+func NewShipment(orderId int, carrier, trackingNumber string, now time.Time) Shipment {
+	return Shipment{
+		OrderId:        orderId,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		Status:         ShipmentStatusLabelCreated,
+		LastEventAt:    &now,
+		CreatedAt:      now,
+		Events: []ShipmentEvent{
+			NewShipmentEvent("", ShipmentStatusLabelCreated, "", "Shipping label created", now, now),
+		},
+	}
+}
+
+func NewShipmentEvent(eventId string, status ShipmentStatus, location, description string, occurredAt, now time.Time) ShipmentEvent {
+	return ShipmentEvent{
+		EventId:     eventId,
+		Status:      status,
+		Location:    location,
+		Description: description,
+		OccurredAt:  occurredAt,
+		CreatedAt:   now,
+	}
+}
+
+func (shipment *Shipment) SetId(id int) {
+	if shipment != nil {
+		shipment.Id = id
+	}
+}
+
+func (shipment *Shipment) SetUserId(id int) {
+	if shipment != nil {
+		shipment.UserId = id
+	}
+}
+
+func (shipment *Shipment) SetUpdatedAt(ua time.Time) {
+	if shipment != nil {
+		shipment.UpdatedAt = &ua
+	}
+}
+
+func (shipment *Shipment) AddEvent(event ShipmentEvent) {
+	if shipment != nil {
+		shipment.Events = append(shipment.Events, event)
+	}
+}
+
+// Apply moves the shipment to the status of event and tells whether it did. Carriers do not promise to call
+// back in order, an event older than the latest one only fills in the timeline, and a delivered or returned
+// shipment stays as it is.
+func (shipment *Shipment) Apply(event ShipmentEvent) bool {
+	if shipment == nil || shipment.Status.IsFinal() {
+		return false
+	}
+
+	if shipment.LastEventAt != nil && event.OccurredAt.Before(*shipment.LastEventAt) {
+		return false
+	}
+
+	occurredAt := event.OccurredAt
+	shipment.LastEventAt = &occurredAt
+	shipment.Status = event.Status
+
+	return true
+}
+
+func (shipment *Shipment) GetIdSafe() int {
+	if shipment != nil {
+		return shipment.Id
+	}
+
+	return 0
+}
+
+func (shipment *Shipment) GetOrderIdSafe() int {
+	if shipment != nil {
+		return shipment.OrderId
+	}
+
+	return 0
+}
+
+func (shipment *Shipment) GetStatusSafe() ShipmentStatus {
+	if shipment != nil {
+		return shipment.Status
+	}
+
+	return ""
+}
+
+func (shipment *Shipment) GetEventsSafe() []ShipmentEvent {
+	if shipment != nil {
+		return shipment.Events
+	}
+
+	return nil
+}
+
+func (event *ShipmentEvent) SetId(id int) {
+	if event != nil {
+		event.Id = id
+	}
+}
+
+func (event *ShipmentEvent) SetShipmentId(id int) {
+	if event != nil {
+		event.ShipmentId = id
+	}
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	carrierPattern        = regexp.MustCompile(`^[a-z0-9_-]{2,30}$`)
+	trackingNumberPattern = regexp.MustCompile(`^[A-Z0-9]{4,40}$`)
+)
+
+type ShipmentRequest struct {
+	Carrier        string `json:"carrier" example:"ups"`
+	TrackingNumber string `json:"tracking_number" example:"1Z999AA10123456784"`
+}
+
+// CarrierEvent is the body of a carrier's status callback. EventId is the carrier's id of the callback,
+// a callback sent again with the same id is only recorded once.
+type CarrierEvent struct {
+	OccurredAt     time.Time      `json:"occurred_at"`
+	EventId        string         `json:"event_id" example:"evt_0001"`
+	Carrier        string         `json:"carrier" example:"ups"`
+	TrackingNumber string         `json:"tracking_number" example:"1Z999AA10123456784"`
+	Status         ShipmentStatus `json:"status" example:"in_transit"`
+	Location       string         `json:"location" example:"Oakland, CA"`
+	Description    string         `json:"description" example:"Departed from facility"`
+}
+
+func normalizeCarrier(carrier string) string {
+	return strings.ToLower(strings.TrimSpace(carrier))
+}
+
+func normalizeTrackingNumber(trackingNumber string) string {
+	return strings.ToUpper(strings.TrimSpace(trackingNumber))
+}
+
+func validateParcel(carrier, trackingNumber string) error {
+	if !carrierPattern.MatchString(normalizeCarrier(carrier)) {
+		return ErrInvalidCarrier
+	}
+
+	if !trackingNumberPattern.MatchString(normalizeTrackingNumber(trackingNumber)) {
+		return ErrInvalidTrackingNumber
+	}
+
+	return nil
+}
+
+func (data ShipmentRequest) Validate() error {
+	return validateParcel(data.Carrier, data.TrackingNumber)
+}
+
+func (data ShipmentRequest) ToShipment(orderId int, now time.Time) Shipment {
+	return NewShipment(orderId, normalizeCarrier(data.Carrier), normalizeTrackingNumber(data.TrackingNumber), now)
+}
+
+func (data CarrierEvent) Validate() error {
+	if strings.TrimSpace(data.EventId) == "" || utf8.RuneCountInString(data.EventId) > 100 || data.OccurredAt.IsZero() {
+		return ErrInvalidCarrierEvent
+	}
+
+	if err := validateParcel(data.Carrier, data.TrackingNumber); err != nil {
+		return err
+	}
+
+	if !data.Status.IsValid() {
+		return ErrInvalidShipmentStatus
+	}
+
+	return nil
+}
+
+// GetParcel is the carrier and the tracking number the event is about, as they are stored.
+func (data CarrierEvent) GetParcel() (string, string) {
+	return normalizeCarrier(data.Carrier), normalizeTrackingNumber(data.TrackingNumber)
+}
+
+// ToShipmentEvent turns a validated callback into a timeline event, overly long texts are cut to fit.
+func (data CarrierEvent) ToShipmentEvent(now time.Time) ShipmentEvent {
+	return NewShipmentEvent(strings.TrimSpace(data.EventId), data.Status, truncate(strings.TrimSpace(data.Location), 200), truncate(strings.TrimSpace(data.Description), 500), data.OccurredAt, now)
+}
+
+func truncate(value string, max int) string {
+	if utf8.RuneCountInString(value) <= max {
+		return value
+	}
+
+	return string([]rune(value)[:max])
+}
@@ -0,0 +1,243 @@
+package postgres
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/pkg"
+	orderEntity "order_service/services/order/entity"
+	"order_service/services/shipment/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ShipmentRepository interface {
+	CreateShipment(ctx context.Context, shipment *entity.Shipment, actorId int, callbackFn func(shipment *entity.Shipment, orderStatus orderEntity.OrderStatus) (bool, error)) error
+	GetShipments(ctx context.Context, orderId int) (*[]entity.Shipment, error)
+	RecordEvent(ctx context.Context, carrier, trackingNumber string, event *entity.ShipmentEvent, callbackFn func(shipment *entity.Shipment, event *entity.ShipmentEvent, orderStatus orderEntity.OrderStatus, undelivered int) (bool, error)) error
+}
+
+const (
+	QUERY_GET_ORDER_LOCK              = "SELECT user_id, status FROM orders WHERE id = $1 FOR UPDATE"
+	QUERY_UPDATE_ORDER_STATUS         = "UPDATE orders SET status = $2, updated_at = $3 WHERE id = $1"
+	QUERY_CREATE_ORDER_STATUS_HISTORY = "INSERT INTO order_status_histories (order_id, from_status, to_status, actor_id, created_at) VALUES ($1, $2, $3, $4, $5)"
+	QUERY_CREATE_SHIPMENT             = "INSERT INTO shipments (order_id, user_id, carrier, tracking_number, status, last_event_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (carrier, tracking_number) DO NOTHING RETURNING id"
+	QUERY_GET_SHIPMENTS               = "SELECT id, order_id, user_id, carrier, tracking_number, status, last_event_at, created_at, updated_at FROM shipments WHERE order_id = $1 ORDER BY id"
+	QUERY_GET_SHIPMENT_LOCK           = "SELECT id, order_id, user_id, carrier, tracking_number, status, last_event_at, created_at, updated_at FROM shipments WHERE carrier = $1 AND tracking_number = $2 FOR UPDATE"
+	QUERY_COUNT_UNDELIVERED           = "SELECT COUNT(*) FROM shipments WHERE order_id = $1 AND id <> $2 AND status <> 'delivered'"
+	QUERY_UPDATE_SHIPMENT             = "UPDATE shipments SET status = $2, last_event_at = $3, updated_at = $4 WHERE id = $1"
+	QUERY_CREATE_EVENT                = "INSERT INTO shipment_events (shipment_id, event_id, status, location, description, occurred_at, created_at) VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7) ON CONFLICT (shipment_id, event_id) DO NOTHING RETURNING id"
+	QUERY_GET_EVENTS                  = "SELECT e.id, e.shipment_id, COALESCE(e.event_id, ''), e.status, e.location, e.description, e.occurred_at, e.created_at FROM shipment_events e JOIN shipments s ON s.id = e.shipment_id WHERE s.order_id = $1 ORDER BY e.occurred_at, e.id"
+)
+
+type postgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewShipmentRepo(db *pgxpool.Pool) ShipmentRepository {
+	return &postgresRepo{
+		db,
+	}
+}
+
+func scanShipment(row pgx.Row) (*entity.Shipment, error) {
+	var shipment entity.Shipment
+
+	err := row.Scan(&shipment.Id, &shipment.OrderId, &shipment.UserId, &shipment.Carrier, &shipment.TrackingNumber, &shipment.Status, &shipment.LastEventAt, &shipment.CreatedAt, &shipment.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, core.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &shipment, nil
+}
+
+// createEvent adds event to the timeline of its shipment, entity.ErrDuplicateEvent means the carrier already
+// sent it.
+func createEvent(ctx context.Context, tx pgx.Tx, event *entity.ShipmentEvent) error {
+	var newEventId int
+
+	err := tx.QueryRow(ctx, QUERY_CREATE_EVENT, event.ShipmentId, event.EventId, event.Status, event.Location, event.Description, event.OccurredAt, event.CreatedAt).Scan(&newEventId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entity.ErrDuplicateEvent
+		}
+		return err
+	}
+	event.SetId(newEventId)
+
+	return nil
+}
+
+// moveOrder changes the status of a locked order and records who did it.
+func moveOrder(ctx context.Context, tx pgx.Tx, orderId, actorId int, from, to orderEntity.OrderStatus, now time.Time) error {
+	_, err := tx.Exec(ctx, QUERY_UPDATE_ORDER_STATUS, orderId, to, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, QUERY_CREATE_ORDER_STATUS_HISTORY, orderId, from, to, actorId, now)
+
+	return err
+}
+
+// CreateShipment stores the shipment of a locked order along with its first events. When callbackFn says so
+// the order is moved to shipped in the same transaction. entity.ErrTrackingNumberTaken means the carrier's
+// tracking number is already on a shipment.
+func (repo *postgresRepo) CreateShipment(ctx context.Context, shipment *entity.Shipment, actorId int, callbackFn func(shipment *entity.Shipment, orderStatus orderEntity.OrderStatus) (bool, error)) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		var userId int
+		var orderStatus orderEntity.OrderStatus
+
+		err := tx.QueryRow(ctx, QUERY_GET_ORDER_LOCK, shipment.OrderId).Scan(&userId, &orderStatus)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return core.ErrRecordNotFound
+			}
+			return err
+		}
+		shipment.SetUserId(userId)
+
+		// run business logic
+		ship, err := callbackFn(shipment, orderStatus)
+		if err != nil {
+			return err
+		}
+
+		var newShipmentId int
+
+		err = tx.QueryRow(ctx, QUERY_CREATE_SHIPMENT, shipment.OrderId, shipment.UserId, shipment.Carrier, shipment.TrackingNumber, shipment.Status, shipment.LastEventAt, shipment.CreatedAt).Scan(&newShipmentId)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return entity.ErrTrackingNumberTaken
+			}
+			return err
+		}
+		shipment.SetId(newShipmentId)
+
+		for i := range shipment.Events {
+			shipment.Events[i].SetShipmentId(newShipmentId)
+
+			err = createEvent(ctx, tx, &shipment.Events[i])
+			if err != nil {
+				return err
+			}
+		}
+
+		if ship {
+			return moveOrder(ctx, tx, shipment.OrderId, actorId, orderStatus, orderEntity.OrderStatusShipped, shipment.CreatedAt)
+		}
+
+		return nil
+	})
+}
+
+// GetShipments returns the shipments of an order with their timelines, oldest event first.
+func (repo *postgresRepo) GetShipments(ctx context.Context, orderId int) (*[]entity.Shipment, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_SHIPMENTS, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	shipments, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Shipment, error) {
+		shipment, err := scanShipment(row)
+		if err != nil {
+			return entity.Shipment{}, err
+		}
+		shipment.Events = []entity.ShipmentEvent{}
+
+		return *shipment, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(shipments) == 0 {
+		return &shipments, nil
+	}
+
+	rows, err = repo.db.Query(ctx, QUERY_GET_EVENTS, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.ShipmentEvent, error) {
+		var event entity.ShipmentEvent
+
+		err := row.Scan(&event.Id, &event.ShipmentId, &event.EventId, &event.Status, &event.Location, &event.Description, &event.OccurredAt, &event.CreatedAt)
+		if err != nil {
+			return entity.ShipmentEvent{}, err
+		}
+
+		return event, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[int]int, len(shipments))
+	for i, shipment := range shipments {
+		positions[shipment.Id] = i
+	}
+
+	for _, event := range events {
+		if i, ok := positions[event.ShipmentId]; ok {
+			shipments[i].AddEvent(event)
+		}
+	}
+
+	return &shipments, nil
+}
+
+// RecordEvent adds a carrier event to the timeline of the shipment it is about and hands both to callbackFn
+// along with the status of the order and how many of its other shipments are not delivered yet. The shipment
+// is then updated, and the order is moved to delivered when callbackFn says so. entity.ErrDuplicateEvent
+// means the event was already recorded, nothing is changed then.
+func (repo *postgresRepo) RecordEvent(ctx context.Context, carrier, trackingNumber string, event *entity.ShipmentEvent, callbackFn func(shipment *entity.Shipment, event *entity.ShipmentEvent, orderStatus orderEntity.OrderStatus, undelivered int) (bool, error)) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		shipment, err := scanShipment(tx.QueryRow(ctx, QUERY_GET_SHIPMENT_LOCK, carrier, trackingNumber))
+		if err != nil {
+			return err
+		}
+
+		event.SetShipmentId(shipment.GetIdSafe())
+
+		err = createEvent(ctx, tx, event)
+		if err != nil {
+			return err
+		}
+
+		var userId, undelivered int
+		var orderStatus orderEntity.OrderStatus
+
+		err = tx.QueryRow(ctx, QUERY_GET_ORDER_LOCK, shipment.OrderId).Scan(&userId, &orderStatus)
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(ctx, QUERY_COUNT_UNDELIVERED, shipment.OrderId, shipment.Id).Scan(&undelivered)
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		deliver, err := callbackFn(shipment, event, orderStatus, undelivered)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_UPDATE_SHIPMENT, shipment.Id, shipment.Status, shipment.LastEventAt, shipment.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		if deliver {
+			return moveOrder(ctx, tx, shipment.OrderId, entity.CarrierActorId, orderStatus, orderEntity.OrderStatusDelivered, event.CreatedAt)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,105 @@
+package carrier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"order_service/pkg"
+	"order_service/services/shipment/controller/api"
+	"order_service/services/shipment/entity"
+	"sync"
+	"time"
+)
+
+// SendFunc delivers a callback request, http.DefaultClient.Do or a fiber app's Test method fit it.
+type SendFunc func(req *http.Request) (*http.Response, error)
+
+// Simulator plays a carrier so the webhook can be exercised without one. It hands out tracking numbers and
+// sends status callbacks signed with its secret, every callback gets its own event id and occurs a minute
+// after the one before.
+type Simulator struct {
+	mu      sync.Mutex
+	send    SendFunc
+	name    string
+	secret  string
+	url     string
+	clock   time.Time
+	parcels int
+	events  int
+}
+
+func NewSimulator(name, secret, url string, send SendFunc) *Simulator {
+	return &Simulator{
+		send:   send,
+		name:   name,
+		secret: secret,
+		url:    url,
+		clock:  time.Date(2024, time.January, 1, 8, 0, 0, 0, time.UTC),
+	}
+}
+
+// TrackingNumber hands out a tracking number the simulator has not given before.
+func (simulator *Simulator) TrackingNumber() string {
+	simulator.mu.Lock()
+	defer simulator.mu.Unlock()
+
+	simulator.parcels++
+
+	return fmt.Sprintf("FAKE%010d", simulator.parcels)
+}
+
+// Event makes the next callback about a parcel, it is only sent by Send.
+func (simulator *Simulator) Event(trackingNumber string, status entity.ShipmentStatus, location string) entity.CarrierEvent {
+	simulator.mu.Lock()
+	defer simulator.mu.Unlock()
+
+	simulator.events++
+	simulator.clock = simulator.clock.Add(time.Minute)
+
+	return entity.CarrierEvent{
+		EventId:        fmt.Sprintf("%s-%d", simulator.name, simulator.events),
+		Carrier:        simulator.name,
+		TrackingNumber: trackingNumber,
+		Status:         status,
+		Location:       location,
+		Description:    fmt.Sprintf("Parcel is %s", status),
+		OccurredAt:     simulator.clock,
+	}
+}
+
+// Send signs the callback and posts it to the webhook, the response is left to the caller.
+func (simulator *Simulator) Send(ctx context.Context, event entity.CarrierEvent) (*http.Response, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, simulator.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(api.HeaderSignature, pkg.SignHMAC(simulator.secret, body))
+
+	return simulator.send(req)
+}
+
+// Deliver takes a parcel from the depot to the door, every callback has to be accepted.
+func (simulator *Simulator) Deliver(ctx context.Context, trackingNumber string) error {
+	for _, status := range []entity.ShipmentStatus{entity.ShipmentStatusInTransit, entity.ShipmentStatusOutForDelivery, entity.ShipmentStatusDelivered} {
+		res, err := simulator.Send(ctx, simulator.Event(trackingNumber, status, "Depot"))
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s callback answered with %d", status, res.StatusCode)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/postgres/store.go
+//
+// Generated by this command:
+//
+//	mockgen -source repository/postgres/store.go -destination test/mock/store.go -package mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "order_service/services/order/entity"
+	entity0 "order_service/services/shipment/entity"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockShipmentRepository is a mock of ShipmentRepository interface.
+type MockShipmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShipmentRepositoryMockRecorder
+}
+
+// MockShipmentRepositoryMockRecorder is the mock recorder for MockShipmentRepository.
+type MockShipmentRepositoryMockRecorder struct {
+	mock *MockShipmentRepository
+}
+
+// NewMockShipmentRepository creates a new mock instance.
+func NewMockShipmentRepository(ctrl *gomock.Controller) *MockShipmentRepository {
+	mock := &MockShipmentRepository{ctrl: ctrl}
+	mock.recorder = &MockShipmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShipmentRepository) EXPECT() *MockShipmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateShipment mocks base method.
+func (m *MockShipmentRepository) CreateShipment(ctx context.Context, shipment *entity0.Shipment, actorId int, callbackFn func(*entity0.Shipment, entity.OrderStatus) (bool, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShipment", ctx, shipment, actorId, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateShipment indicates an expected call of CreateShipment.
+func (mr *MockShipmentRepositoryMockRecorder) CreateShipment(ctx, shipment, actorId, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShipment", reflect.TypeOf((*MockShipmentRepository)(nil).CreateShipment), ctx, shipment, actorId, callbackFn)
+}
+
+// GetShipments mocks base method.
+func (m *MockShipmentRepository) GetShipments(ctx context.Context, orderId int) (*[]entity0.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShipments", ctx, orderId)
+	ret0, _ := ret[0].(*[]entity0.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShipments indicates an expected call of GetShipments.
+func (mr *MockShipmentRepositoryMockRecorder) GetShipments(ctx, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipments", reflect.TypeOf((*MockShipmentRepository)(nil).GetShipments), ctx, orderId)
+}
+
+// RecordEvent mocks base method.
+func (m *MockShipmentRepository) RecordEvent(ctx context.Context, carrier, trackingNumber string, event *entity0.ShipmentEvent, callbackFn func(*entity0.Shipment, *entity0.ShipmentEvent, entity.OrderStatus, int) (bool, error)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordEvent", ctx, carrier, trackingNumber, event, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordEvent indicates an expected call of RecordEvent.
+func (mr *MockShipmentRepositoryMockRecorder) RecordEvent(ctx, carrier, trackingNumber, event, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordEvent", reflect.TypeOf((*MockShipmentRepository)(nil).RecordEvent), ctx, carrier, trackingNumber, event, callbackFn)
+}
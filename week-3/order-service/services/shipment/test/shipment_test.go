@@ -0,0 +1,84 @@
+package test
+
+import (
+	"order_service/services/shipment/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShipmentApply(t *testing.T) {
+	start := time.Date(2024, time.January, 2, 10, 0, 0, 0, time.UTC)
+
+	shipment := entity.NewShipment(7, "ups", "1Z999AA10123456784", start)
+	assert.Equal(t, entity.ShipmentStatusLabelCreated, shipment.Status)
+
+	inTransit := entity.NewShipmentEvent("evt-2", entity.ShipmentStatusInTransit, "", "", start.Add(2*time.Hour), start)
+	assert.True(t, shipment.Apply(inTransit), "newer event should move the shipment")
+	assert.Equal(t, entity.ShipmentStatusInTransit, shipment.Status)
+
+	// a callback the carrier sent late only fills in the timeline
+	pickedUp := entity.NewShipmentEvent("evt-1", entity.ShipmentStatusLabelCreated, "", "", start.Add(time.Hour), start)
+	assert.False(t, shipment.Apply(pickedUp), "older event should not move the shipment back")
+	assert.Equal(t, entity.ShipmentStatusInTransit, shipment.Status)
+
+	delivered := entity.NewShipmentEvent("evt-3", entity.ShipmentStatusDelivered, "", "", start.Add(3*time.Hour), start)
+	assert.True(t, shipment.Apply(delivered))
+
+	returned := entity.NewShipmentEvent("evt-4", entity.ShipmentStatusReturned, "", "", start.Add(4*time.Hour), start)
+	assert.False(t, shipment.Apply(returned), "delivered shipment should not move on")
+	assert.Equal(t, entity.ShipmentStatusDelivered, shipment.Status)
+	assert.Equal(t, start.Add(3*time.Hour), *shipment.LastEventAt)
+}
+
+func TestShipmentRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request entity.ShipmentRequest
+		wantErr error
+	}{
+		{name: "Valid", request: entity.ShipmentRequest{Carrier: "UPS", TrackingNumber: " 1z999aa10123456784 "}},
+		{name: "Missing carrier", request: entity.ShipmentRequest{TrackingNumber: "1Z999AA10123456784"}, wantErr: entity.ErrInvalidCarrier},
+		{name: "Carrier with spaces", request: entity.ShipmentRequest{Carrier: "fed ex", TrackingNumber: "1Z999AA10123456784"}, wantErr: entity.ErrInvalidCarrier},
+		{name: "Short tracking number", request: entity.ShipmentRequest{Carrier: "ups", TrackingNumber: "1Z"}, wantErr: entity.ErrInvalidTrackingNumber},
+		{name: "Tracking number with dashes", request: entity.ShipmentRequest{Carrier: "ups", TrackingNumber: "1Z-999"}, wantErr: entity.ErrInvalidTrackingNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.request.Validate())
+		})
+	}
+}
+
+func TestCarrierEventValidate(t *testing.T) {
+	valid := entity.CarrierEvent{
+		EventId:        "evt-1",
+		Carrier:        "ups",
+		TrackingNumber: "1Z999AA10123456784",
+		Status:         entity.ShipmentStatusInTransit,
+		OccurredAt:     time.Date(2024, time.January, 2, 10, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(event *entity.CarrierEvent)
+		wantErr error
+	}{
+		{name: "Valid", mutate: func(event *entity.CarrierEvent) {}},
+		{name: "Missing event id", mutate: func(event *entity.CarrierEvent) { event.EventId = " " }, wantErr: entity.ErrInvalidCarrierEvent},
+		{name: "Missing time", mutate: func(event *entity.CarrierEvent) { event.OccurredAt = time.Time{} }, wantErr: entity.ErrInvalidCarrierEvent},
+		{name: "Invalid tracking number", mutate: func(event *entity.CarrierEvent) { event.TrackingNumber = "" }, wantErr: entity.ErrInvalidTrackingNumber},
+		{name: "Unknown status", mutate: func(event *entity.CarrierEvent) { event.Status = "lost" }, wantErr: entity.ErrInvalidShipmentStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := valid
+			tt.mutate(&event)
+
+			assert.Equal(t, tt.wantErr, event.Validate())
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"order_service/internal/core"
+	"order_service/pkg"
+	orderEntity "order_service/services/order/entity"
+	"order_service/services/shipment/entity"
+	"order_service/services/shipment/test/mock"
+	"order_service/services/shipment/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type ShipmentUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo *mock.MockShipmentRepository
+	usecase  usecase.ShipmentUsecase
+	adminCtx context.Context
+	userCtx  context.Context
+}
+
+func (suite *ShipmentUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockShipmentRepository(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, "secret")
+	suite.adminCtx = requesterContext(1, 1)
+	suite.userCtx = requesterContext(2, 0)
+}
+
+func (suite *ShipmentUsecaseTestSuite) TestCreateShipment() {
+	repoErr := errors.New("connection refused")
+	request := entity.ShipmentRequest{Carrier: " UPS ", TrackingNumber: "1z999aa10123456784"}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		callRepo    bool
+		orderStatus orderEntity.OrderStatus
+		repoErr     error
+		wantShip    bool
+		wantErr     error
+		assertion   assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "First parcel ships the order",
+			ctx:         suite.adminCtx,
+			callRepo:    true,
+			orderStatus: orderEntity.OrderStatusFulfilling,
+			wantShip:    true,
+			assertion:   assert.NoError,
+		},
+		{
+			name:        "Another parcel of a shipped order",
+			ctx:         suite.adminCtx,
+			callRepo:    true,
+			orderStatus: orderEntity.OrderStatusShipped,
+			wantShip:    false,
+			assertion:   assert.NoError,
+		},
+		{
+			name:        "Unpaid order cannot be shipped",
+			ctx:         suite.adminCtx,
+			callRepo:    true,
+			orderStatus: orderEntity.OrderStatusPending,
+			wantErr:     core.ErrConfict.WithError(entity.ErrOrderNotShippable.Error()),
+			assertion:   assert.Error,
+		},
+		{
+			name:      "Customer cannot ship",
+			ctx:       suite.userCtx,
+			callRepo:  false,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrCannotCreateShipment.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Order not found",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrOrderNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Tracking number taken",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			repoErr:   entity.ErrTrackingNumberTaken,
+			wantErr:   core.ErrConfict.WithError(entity.ErrTrackingNumberTaken.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo error",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			repoErr:   repoErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotCreateShipment.Error()).WithDebug(repoErr.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().CreateShipment(gomock.Any(), gomock.Any(), 1, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, shipment *entity.Shipment, actorId int, callbackFn func(shipment *entity.Shipment, orderStatus orderEntity.OrderStatus) (bool, error)) error {
+						if tt.repoErr != nil {
+							return tt.repoErr
+						}
+
+						ship, err := callbackFn(shipment, tt.orderStatus)
+						if err != nil {
+							return err
+						}
+						suite.Equal(tt.wantShip, ship, "order should be moved to shipped with its first parcel only")
+
+						return nil
+					})
+			}
+
+			shipment, err := suite.usecase.CreateShipment(tt.ctx, 7, &request)
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+
+			if err == nil {
+				suite.Equal("ups", shipment.Carrier, "carrier should be normalized")
+				suite.Equal("1Z999AA10123456784", shipment.TrackingNumber, "tracking number should be normalized")
+				suite.Equal(entity.ShipmentStatusLabelCreated, shipment.Status)
+				suite.Len(shipment.Events, 1, "timeline should open with the label")
+			}
+		})
+	}
+}
+
+func (suite *ShipmentUsecaseTestSuite) TestGetShipments() {
+	shipments := []entity.Shipment{
+		{Id: 1, OrderId: 7, UserId: 2, Carrier: "ups", TrackingNumber: "1Z1", Status: entity.ShipmentStatusInTransit},
+		{Id: 2, OrderId: 7, UserId: 2, Carrier: "ups", TrackingNumber: "1Z2", Status: entity.ShipmentStatusLabelCreated},
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantLen   int
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "Owner sees the shipments", ctx: suite.userCtx, wantLen: 2, assertion: assert.NoError},
+		{name: "Admin sees the shipments", ctx: suite.adminCtx, wantLen: 2, assertion: assert.NoError},
+		{name: "Other customer sees nothing", ctx: requesterContext(3, 0), wantLen: 0, assertion: assert.NoError},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			copied := append([]entity.Shipment{}, shipments...)
+			suite.mockRepo.EXPECT().GetShipments(gomock.Any(), 7).Return(&copied, nil)
+
+			got, err := suite.usecase.GetShipments(tt.ctx, 7)
+			tt.assertion(suite.T(), err)
+			suite.Len(*got, tt.wantLen)
+		})
+	}
+}
+
+func (suite *ShipmentUsecaseTestSuite) TestReceiveCarrierEvent() {
+	repoErr := errors.New("connection refused")
+
+	valid, err := json.Marshal(entity.CarrierEvent{
+		EventId:        "evt-1",
+		Carrier:        "UPS",
+		TrackingNumber: "1z999aa10123456784",
+		Status:         entity.ShipmentStatusInTransit,
+		OccurredAt:     time.Date(2024, time.January, 2, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(suite.T(), err)
+
+	invalid, err := json.Marshal(entity.CarrierEvent{EventId: "evt-2", Carrier: "ups", TrackingNumber: "1Z999AA10123456784", Status: "lost", OccurredAt: time.Now()})
+	require.NoError(suite.T(), err)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Signed event is recorded",
+			secret:    "secret",
+			body:      valid,
+			signature: pkg.SignHMAC("secret", valid),
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Event sent again is ignored",
+			secret:    "secret",
+			body:      valid,
+			signature: pkg.SignHMAC("secret", valid),
+			callRepo:  true,
+			repoErr:   entity.ErrDuplicateEvent,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Signed with another secret",
+			secret:    "secret",
+			body:      valid,
+			signature: pkg.SignHMAC("guess", valid),
+			wantErr:   core.ErrUnauthorized.WithError(entity.ErrInvalidSignature.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Signature is not hex",
+			secret:    "secret",
+			body:      valid,
+			signature: "not-a-signature",
+			wantErr:   core.ErrUnauthorized.WithError(entity.ErrInvalidSignature.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "No secret configured",
+			secret:    "",
+			body:      valid,
+			signature: pkg.SignHMAC("", valid),
+			wantErr:   core.ErrUnauthorized.WithError(entity.ErrWebhookDisabled.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown status",
+			secret:    "secret",
+			body:      invalid,
+			signature: pkg.SignHMAC("secret", invalid),
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrInvalidShipmentStatus.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown tracking number",
+			secret:    "secret",
+			body:      valid,
+			signature: pkg.SignHMAC("secret", valid),
+			callRepo:  true,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrShipmentNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo error",
+			secret:    "secret",
+			body:      valid,
+			signature: pkg.SignHMAC("secret", valid),
+			callRepo:  true,
+			repoErr:   repoErr,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotRecordEvent.Error()).WithDebug(repoErr.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+			uc := usecase.NewUsecase(suite.mockRepo, tt.secret)
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().RecordEvent(gomock.Any(), "ups", "1Z999AA10123456784", gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, carrier, trackingNumber string, event *entity.ShipmentEvent, callbackFn func(shipment *entity.Shipment, event *entity.ShipmentEvent, orderStatus orderEntity.OrderStatus, undelivered int) (bool, error)) error {
+						suite.Equal("evt-1", event.EventId)
+						suite.Equal(entity.ShipmentStatusInTransit, event.Status)
+
+						return tt.repoErr
+					})
+			}
+
+			err := uc.ReceiveCarrierEvent(context.Background(), tt.body, tt.signature)
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+		})
+	}
+}
+
+func (suite *ShipmentUsecaseTestSuite) TestRecordEventCallback() {
+	at := time.Date(2024, time.January, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		status      entity.ShipmentStatus
+		event       entity.ShipmentStatus
+		orderStatus orderEntity.OrderStatus
+		undelivered int
+		wantStatus  entity.ShipmentStatus
+		wantDeliver bool
+	}{
+		{
+			name:        "Parcel moves on",
+			status:      entity.ShipmentStatusLabelCreated,
+			event:       entity.ShipmentStatusInTransit,
+			orderStatus: orderEntity.OrderStatusShipped,
+			wantStatus:  entity.ShipmentStatusInTransit,
+		},
+		{
+			name:        "Last parcel delivered delivers the order",
+			status:      entity.ShipmentStatusOutForDelivery,
+			event:       entity.ShipmentStatusDelivered,
+			orderStatus: orderEntity.OrderStatusShipped,
+			wantStatus:  entity.ShipmentStatusDelivered,
+			wantDeliver: true,
+		},
+		{
+			name:        "Other parcels are still on the way",
+			status:      entity.ShipmentStatusOutForDelivery,
+			event:       entity.ShipmentStatusDelivered,
+			orderStatus: orderEntity.OrderStatusShipped,
+			undelivered: 1,
+			wantStatus:  entity.ShipmentStatusDelivered,
+		},
+		{
+			name:        "Order already delivered",
+			status:      entity.ShipmentStatusOutForDelivery,
+			event:       entity.ShipmentStatusDelivered,
+			orderStatus: orderEntity.OrderStatusDelivered,
+			wantStatus:  entity.ShipmentStatusDelivered,
+		},
+		{
+			name:        "Delivered parcel stays delivered",
+			status:      entity.ShipmentStatusDelivered,
+			event:       entity.ShipmentStatusException,
+			orderStatus: orderEntity.OrderStatusDelivered,
+			wantStatus:  entity.ShipmentStatusDelivered,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			last := at.Add(-time.Hour)
+			shipment := entity.Shipment{Id: 1, OrderId: 7, Status: tt.status, LastEventAt: &last}
+			event := entity.NewShipmentEvent("evt-1", tt.event, "", "", at, at)
+
+			deliver, err := suite.usecase.RecordEventCallback(&shipment, &event, tt.orderStatus, tt.undelivered)
+			suite.NoError(err)
+			suite.Equal(tt.wantDeliver, deliver, "order should only be delivered with its last parcel")
+			suite.Equal(tt.wantStatus, shipment.Status)
+			suite.NotNil(shipment.UpdatedAt)
+		})
+	}
+
+	_, err := suite.usecase.RecordEventCallback(nil, nil, orderEntity.OrderStatusShipped, 0)
+	suite.ErrorIs(err, entity.ErrInvalidMemory)
+}
+
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
+
+func TestShipmentUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ShipmentUsecaseTestSuite))
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"order_service/internal/core"
+	orderEntity "order_service/services/order/entity"
+	"order_service/services/shipment/controller/api"
+	"order_service/services/shipment/entity"
+	"order_service/services/shipment/test/carrier"
+	"order_service/services/shipment/test/mock"
+	"order_service/services/shipment/usecase"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newWebhookApp serves the carrier webhook on a repo that keeps one shipment in memory, events are recorded
+// once per event id like the unique index does.
+func newWebhookApp(t *testing.T, shipment *entity.Shipment, orderStatus *orderEntity.OrderStatus) *fiber.App {
+	repo := mock.NewMockShipmentRepository(gomock.NewController(t))
+	seen := map[string]bool{}
+
+	repo.EXPECT().RecordEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, carrierName, trackingNumber string, event *entity.ShipmentEvent, callbackFn func(shipment *entity.Shipment, event *entity.ShipmentEvent, orderStatus orderEntity.OrderStatus, undelivered int) (bool, error)) error {
+			if carrierName != shipment.Carrier || trackingNumber != shipment.TrackingNumber {
+				return core.ErrRecordNotFound
+			}
+			if seen[event.EventId] {
+				return entity.ErrDuplicateEvent
+			}
+			seen[event.EventId] = true
+			shipment.AddEvent(*event)
+
+			deliver, err := callbackFn(shipment, event, *orderStatus, 0)
+			if err != nil {
+				return err
+			}
+			if deliver {
+				*orderStatus = orderEntity.OrderStatusDelivered
+			}
+
+			return nil
+		})
+
+	srv := api.NewService(usecase.NewUsecase(repo, "secret"))
+
+	app := fiber.New()
+	app.Post("/shipments/webhook", srv.ReceiveCarrierEvent)
+
+	return app
+}
+
+func sendTo(app *fiber.App) carrier.SendFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		return app.Test(req)
+	}
+}
+
+func TestCarrierWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	orderStatus := orderEntity.OrderStatusShipped
+	shipment := entity.Shipment{Id: 1, OrderId: 7, UserId: 2, Carrier: "fakeship", Status: entity.ShipmentStatusLabelCreated}
+
+	app := newWebhookApp(t, &shipment, &orderStatus)
+	simulator := carrier.NewSimulator("fakeship", "secret", "/shipments/webhook", sendTo(app))
+	shipment.TrackingNumber = simulator.TrackingNumber()
+
+	// a callback sent twice is accepted but only recorded once
+	event := simulator.Event(shipment.TrackingNumber, entity.ShipmentStatusInTransit, "Oakland, CA")
+	for i := 0; i < 2; i++ {
+		res, err := simulator.Send(ctx, event)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+	assert.Len(t, shipment.Events, 1, "duplicate callback should not be recorded")
+	assert.Equal(t, entity.ShipmentStatusInTransit, shipment.Status)
+
+	require.NoError(t, simulator.Deliver(ctx, shipment.TrackingNumber))
+	assert.Equal(t, entity.ShipmentStatusDelivered, shipment.Status)
+	assert.Len(t, shipment.Events, 4)
+	assert.Equal(t, orderEntity.OrderStatusDelivered, orderStatus, "order should be delivered with its parcel")
+
+	// an impostor without the secret is turned away
+	impostor := carrier.NewSimulator("fakeship", "guess", "/shipments/webhook", sendTo(app))
+	res, err := impostor.Send(ctx, impostor.Event(shipment.TrackingNumber, entity.ShipmentStatusReturned, "Nowhere"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, entity.ShipmentStatusDelivered, shipment.Status)
+
+	// the carrier's other parcels are unknown here
+	res, err = simulator.Send(ctx, simulator.Event(simulator.TrackingNumber(), entity.ShipmentStatusInTransit, "Depot"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"order_service/internal/core"
+	"order_service/pkg"
+	orderEntity "order_service/services/order/entity"
+	"order_service/services/shipment/entity"
+	shipmentRepo "order_service/services/shipment/repository/postgres"
+	"time"
+)
+
+type ShipmentUsecase interface {
+	CreateShipment(ctx context.Context, orderId int, data *entity.ShipmentRequest) (*entity.Shipment, error)
+	CreateShipmentCallback(shipment *entity.Shipment, orderStatus orderEntity.OrderStatus) (bool, error)
+	GetShipments(ctx context.Context, orderId int) (*[]entity.Shipment, error)
+	GetOrderShipments(ctx context.Context, orderId int) ([]entity.Shipment, error)
+	ReceiveCarrierEvent(ctx context.Context, body []byte, signature string) error
+	RecordEventCallback(shipment *entity.Shipment, event *entity.ShipmentEvent, orderStatus orderEntity.OrderStatus, undelivered int) (bool, error)
+}
+
+type shipmentUsecase struct {
+	repo          shipmentRepo.ShipmentRepository
+	webhookSecret string
+}
+
+// NewUsecase builds the shipment usecase, carrier callbacks have to be signed with webhookSecret. Without a
+// secret every callback is turned down.
+func NewUsecase(repo shipmentRepo.ShipmentRepository, webhookSecret string) ShipmentUsecase {
+	return &shipmentUsecase{
+		repo,
+		webhookSecret,
+	}
+}
+
+// CreateShipment records that a fulfilling or shipped order was handed to a carrier, the order is moved to
+// shipped with its first shipment.
+func (uc *shipmentUsecase) CreateShipment(ctx context.Context, orderId int, data *entity.ShipmentRequest) (*entity.Shipment, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	if uid.GetRole() != 1 {
+		return nil, core.ErrBadRequest.WithError(entity.ErrCannotCreateShipment.Error())
+	}
+
+	shipment := data.ToShipment(orderId, time.Now())
+
+	err = uc.repo.CreateShipment(ctx, &shipment, int(uid.GetLocalID()), uc.CreateShipmentCallback)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return nil, core.ErrNotFound.WithError(entity.ErrOrderNotFound.Error())
+		}
+		if err == entity.ErrOrderNotShippable {
+			return nil, core.ErrConfict.WithError(entity.ErrOrderNotShippable.Error())
+		}
+		if err == entity.ErrTrackingNumberTaken {
+			return nil, core.ErrConfict.WithError(entity.ErrTrackingNumberTaken.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotCreateShipment.Error()).WithDebug(err.Error())
+	}
+
+	return &shipment, nil
+}
+
+// CreateShipmentCallback tells whether the order has to be moved to shipped, an order already shipped gets
+// one more parcel.
+func (uc *shipmentUsecase) CreateShipmentCallback(shipment *entity.Shipment, orderStatus orderEntity.OrderStatus) (bool, error) {
+	if shipment == nil {
+		return false, entity.ErrInvalidMemory
+	}
+
+	switch orderStatus {
+	case orderEntity.OrderStatusFulfilling:
+		return true, nil
+	case orderEntity.OrderStatusShipped:
+		return false, nil
+	}
+
+	return false, entity.ErrOrderNotShippable
+}
+
+// GetShipments returns the shipments of an order with their tracking history, customers only see the ones of
+// their own orders.
+func (uc *shipmentUsecase) GetShipments(ctx context.Context, orderId int) (*[]entity.Shipment, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	shipments, err := uc.repo.GetShipments(ctx, orderId)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetShipments.Error()).WithDebug(err.Error())
+	}
+
+	if uid.GetRole() == 1 {
+		return shipments, nil
+	}
+
+	owned := make([]entity.Shipment, 0, len(*shipments))
+	for _, shipment := range *shipments {
+		if shipment.UserId == int(uid.GetLocalID()) {
+			owned = append(owned, shipment)
+		}
+	}
+
+	return &owned, nil
+}
+
+// GetOrderShipments returns the shipments of an order its caller already may see.
+func (uc *shipmentUsecase) GetOrderShipments(ctx context.Context, orderId int) ([]entity.Shipment, error) {
+	shipments, err := uc.repo.GetShipments(ctx, orderId)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetShipments.Error()).WithDebug(err.Error())
+	}
+
+	return *shipments, nil
+}
+
+// ReceiveCarrierEvent records a carrier's status callback. body has to be signed with the webhook secret,
+// signature is its hex encoded HMAC-SHA256. A callback the carrier sends again is accepted and ignored.
+func (uc *shipmentUsecase) ReceiveCarrierEvent(ctx context.Context, body []byte, signature string) error {
+	if uc.webhookSecret == "" {
+		return core.ErrUnauthorized.WithError(entity.ErrWebhookDisabled.Error())
+	}
+
+	if !pkg.VerifyHMAC(uc.webhookSecret, body, signature) {
+		return core.ErrUnauthorized.WithError(entity.ErrInvalidSignature.Error())
+	}
+
+	var data entity.CarrierEvent
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return core.ErrBadRequest.WithError(entity.ErrInvalidCarrierEvent.Error()).WithDebug(err.Error())
+	}
+
+	if err := data.Validate(); err != nil {
+		return core.ErrBadRequest.WithError(err.Error())
+	}
+
+	carrier, trackingNumber := data.GetParcel()
+	event := data.ToShipmentEvent(time.Now())
+
+	err := uc.repo.RecordEvent(ctx, carrier, trackingNumber, &event, uc.RecordEventCallback)
+	if err != nil {
+		if err == entity.ErrDuplicateEvent {
+			return nil
+		}
+		if err == core.ErrRecordNotFound {
+			return core.ErrNotFound.WithError(entity.ErrShipmentNotFound.Error())
+		}
+
+		return core.ErrInternalServerError.WithError(entity.ErrCannotRecordEvent.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// RecordEventCallback applies the event to the shipment and tells whether the order has to be moved to
+// delivered, which it is once the last of its shipments is delivered.
+func (uc *shipmentUsecase) RecordEventCallback(shipment *entity.Shipment, event *entity.ShipmentEvent, orderStatus orderEntity.OrderStatus, undelivered int) (bool, error) {
+	if shipment == nil || event == nil {
+		return false, entity.ErrInvalidMemory
+	}
+
+	shipment.Apply(*event)
+	shipment.SetUpdatedAt(event.CreatedAt)
+
+	if shipment.GetStatusSafe() != entity.ShipmentStatusDelivered || undelivered > 0 {
+		return false, nil
+	}
+
+	return orderStatus.CanTransitionTo(orderEntity.OrderStatusDelivered), nil
+}
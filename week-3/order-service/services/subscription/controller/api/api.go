@@ -0,0 +1,280 @@
+package api
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/subscription/entity"
+	subscriptionUc "order_service/services/subscription/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SubscriptionService interface {
+	CreateSubscription(*fiber.Ctx) error
+	GetSubscriptions(*fiber.Ctx) error
+	GetSubscription(*fiber.Ctx) error
+	UpdateSubscription(*fiber.Ctx) error
+	DeleteSubscription(*fiber.Ctx) error
+	PauseSubscription(*fiber.Ctx) error
+	ResumeSubscription(*fiber.Ctx) error
+	SkipNextRun(*fiber.Ctx) error
+}
+
+type service struct {
+	usecase subscriptionUc.SubscriptionUsecase
+}
+
+func NewService(uc subscriptionUc.SubscriptionUsecase) SubscriptionService {
+	return &service{
+		usecase: uc,
+	}
+}
+
+// Create Subscription godoc
+// @summary Create Subscription
+// @description Re-order the same products daily, weekly or monthly at the time of the first run, or following a 5 field cron, read in the timezone of the subscription (UTC by default). Every run places an order paid from the wallet, a run failing on the balance or the stock is retried and the user is notified through the subscription events
+// @tags subscriptions
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param Idempotency-Key header string false "Key to safely retry the request without applying it twice"
+// @param payload body entity.SubscriptionRequest true "Subscription request body"
+// @success 201 {object} entity.Subscription
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /subscriptions/ [post]
+func (srv *service) CreateSubscription(c *fiber.Ctx) error {
+	var data entity.SubscriptionRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidSubscription.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	subscription, err := srv.usecase.CreateSubscription(ctx, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(core.ResponseData(subscription))
+}
+
+// Get Subscriptions godoc
+// @summary Get Subscriptions
+// @description Get the subscriptions of the current user
+// @tags subscriptions
+// @produce json
+// @security BearerAuth
+// @success 200 {array} entity.Subscription
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /subscriptions/ [get]
+func (srv *service) GetSubscriptions(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	subscriptions, err := srv.usecase.GetSubscriptions(ctx)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(subscriptions))
+}
+
+// Get Subscription godoc
+// @summary Get Subscription
+// @description Get a subscription of the current user with its next run and how its last run went, admin can see every subscription
+// @tags subscriptions
+// @produce json
+// @security BearerAuth
+// @param subscriptionID path int true "Subscription's ID"
+// @success 200 {object} entity.Subscription
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /subscriptions/:subscriptionID [get]
+func (srv *service) GetSubscription(c *fiber.Ctx) error {
+	subscriptionId, err := c.ParamsInt("subscriptionID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	subscription, err := srv.usecase.GetSubscription(ctx, subscriptionId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(subscription))
+}
+
+// Update Subscription godoc
+// @summary Update Subscription
+// @description Replace the products and the schedule of a subscription of the current user, its next run is worked out again and a run being retried is given up. A paused subscription stays paused
+// @tags subscriptions
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param subscriptionID path int true "Subscription's ID"
+// @param payload body entity.SubscriptionRequest true "Subscription request body"
+// @success 200 {object} entity.Subscription
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /subscriptions/:subscriptionID [put]
+func (srv *service) UpdateSubscription(c *fiber.Ctx) error {
+	subscriptionId, err := c.ParamsInt("subscriptionID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	var data entity.SubscriptionRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidSubscription.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	subscription, err := srv.usecase.UpdateSubscription(ctx, subscriptionId, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(subscription))
+}
+
+// Delete Subscription godoc
+// @summary Delete Subscription
+// @description Delete a subscription of the current user, the orders it already placed are kept
+// @tags subscriptions
+// @security BearerAuth
+// @param subscriptionID path int true "Subscription's ID"
+// @success 204
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /subscriptions/:subscriptionID [delete]
+func (srv *service) DeleteSubscription(c *fiber.Ctx) error {
+	subscriptionId, err := c.ParamsInt("subscriptionID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err = srv.usecase.DeleteSubscription(ctx, subscriptionId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusNoContent).JSON(core.ResponseData(true))
+}
+
+// Pause Subscription godoc
+// @summary Pause Subscription
+// @description Stop placing the orders of an active subscription of the current user until it is resumed
+// @tags subscriptions
+// @produce json
+// @security BearerAuth
+// @param subscriptionID path int true "Subscription's ID"
+// @success 200 {object} entity.Subscription
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /subscriptions/:subscriptionID/pause [post]
+func (srv *service) PauseSubscription(c *fiber.Ctx) error {
+	return srv.changeSubscription(c, srv.usecase.PauseSubscription)
+}
+
+// Resume Subscription godoc
+// @summary Resume Subscription
+// @description Place the orders of a paused subscription of the current user again from its next run, the runs missed while it was paused are not made up for
+// @tags subscriptions
+// @produce json
+// @security BearerAuth
+// @param subscriptionID path int true "Subscription's ID"
+// @success 200 {object} entity.Subscription
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /subscriptions/:subscriptionID/resume [post]
+func (srv *service) ResumeSubscription(c *fiber.Ctx) error {
+	return srv.changeSubscription(c, srv.usecase.ResumeSubscription)
+}
+
+// Skip Next Subscription Run godoc
+// @summary Skip Next Subscription Run
+// @description Skip the next order of an active subscription of the current user, a run being retried is given up
+// @tags subscriptions
+// @produce json
+// @security BearerAuth
+// @param subscriptionID path int true "Subscription's ID"
+// @success 200 {object} entity.Subscription
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /subscriptions/:subscriptionID/skip [post]
+func (srv *service) SkipNextRun(c *fiber.Ctx) error {
+	return srv.changeSubscription(c, srv.usecase.SkipNextRun)
+}
+
+func (srv *service) changeSubscription(c *fiber.Ctx, changeFn func(ctx context.Context, subscriptionId int) (*entity.Subscription, error)) error {
+	subscriptionId, err := c.ParamsInt("subscriptionID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	subscription, err := changeFn(ctx, subscriptionId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(subscription))
+}
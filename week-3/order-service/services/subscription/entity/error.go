@@ -0,0 +1,23 @@
+package entity
+
+import "errors"
+
+var (
+	ErrInvalidSubscription      = errors.New("subscription request is not valid")
+	ErrInvalidFrequency         = errors.New("frequency must be daily, weekly, monthly or cron")
+	ErrInvalidSubscriptionName  = errors.New("subscription name cannot have more than 100 characters")
+	ErrInvalidItems             = errors.New("subscription needs between 1 and 20 products with a positive quantity, each listed once")
+	ErrInvalidAddress           = errors.New("address id cannot be negative")
+	ErrScheduleNeverRuns        = errors.New("cron never matches a date")
+	ErrSubscriptionNotFound     = errors.New("subscription cannot be found")
+	ErrSubscriptionNotActive    = errors.New("only active subscription can be paused or skipped")
+	ErrSubscriptionNotPaused    = errors.New("only paused subscription can be resumed")
+	ErrSubscriptionAdminOnly    = errors.New("admin cannot subscribe, subscriptions place orders")
+	ErrOrderNotPaid             = errors.New("order of the subscription could not be paid")
+	ErrCannotCreateSubscription = errors.New("subscription cannot be create")
+	ErrCannotGetSubscriptions   = errors.New("subscriptions cannot be get")
+	ErrCannotUpdateSubscription = errors.New("subscription cannot be update")
+	ErrCannotDeleteSubscription = errors.New("subscription cannot be delete")
+	ErrCannotRunSubscription    = errors.New("due subscription cannot be run")
+	ErrInvalidMemory            = errors.New("invalid memory")
+)
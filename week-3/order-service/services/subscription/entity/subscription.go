@@ -0,0 +1,297 @@
+package entity
+
+import (
+	"order_service/pkg"
+	orderEntity "order_service/services/order/entity"
+	"time"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive SubscriptionStatus = "active"
+	SubscriptionStatusPaused SubscriptionStatus = "paused"
+)
+
+// Frequency is how often a subscription places its order. Daily, weekly and monthly run at the time, the
+// weekday and the day of the month of the first run, a cron frequency follows a cron expression.
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+	FrequencyCron    Frequency = "cron"
+)
+
+func (frequency Frequency) IsValid() bool {
+	switch frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyCron:
+		return true
+	}
+
+	return false
+}
+
+const (
+	EventAggregateSubscription    = "subscription"
+	EventSubscriptionOrderCreated = "subscription.order_created"
+	EventSubscriptionRunFailed    = "subscription.run_failed"
+	EventSubscriptionRunSkipped   = "subscription.run_skipped"
+	EventSubscriptionPaused       = "subscription.paused"
+)
+
+type SubscriptionItem struct {
+	ProductId int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// Subscription places an order of Items for its user every time Cron matches in Timezone. NextRunAt is
+// always kept in UTC, it is a retry of the last run while Attempts is not zero.
+type Subscription struct {
+	CreatedAt   time.Time          `json:"created_at"`
+	NextRunAt   time.Time          `json:"next_run_at"`
+	LastRunAt   *time.Time         `json:"last_run_at"`
+	UpdatedAt   *time.Time         `json:"updated_at"`
+	Name        string             `json:"name"`
+	Frequency   Frequency          `json:"frequency"`
+	Cron        string             `json:"cron"`
+	Timezone    string             `json:"timezone"`
+	Status      SubscriptionStatus `json:"status"`
+	LastError   string             `json:"last_error,omitempty"`
+	Items       []SubscriptionItem `json:"items"`
+	Id          int                `json:"id"`
+	UserId      int                `json:"user_id"`
+	AddressId   int                `json:"address_id,omitempty"`
+	LastOrderId int                `json:"last_order_id,omitempty"`
+	Attempts    int                `json:"attempts"`
+}
+
+// RunResult is what became of one attempt at placing the order of a subscription, Err is nil when the
+// order was placed and paid. A retryable error may be gone by the next attempt, like a low balance.
+type RunResult struct {
+	Err       error
+	OrderId   int
+	Retryable bool
+}
+
+// Notice tells the user what became of a run, it is written to the outbox as the payload of a
+// subscription event of Type.
+type Notice struct {
+	Subscription Subscription `json:"subscription"`
+	RetryAt      *time.Time   `json:"retry_at,omitempty"`
+	Type         string       `json:"-"`
+	Reason       string       `json:"reason,omitempty"`
+	OrderId      int          `json:"order_id,omitempty"`
+}
+
+func NewSubscription(userId int, now time.Time) Subscription {
+	return Subscription{
+		UserId:    userId,
+		Status:    SubscriptionStatusActive,
+		CreatedAt: now,
+	}
+}
+
+func (subscription *Subscription) SetId(id int) {
+	if subscription != nil {
+		subscription.Id = id
+	}
+}
+
+func (subscription *Subscription) GetIdSafe() int {
+	if subscription != nil {
+		return subscription.Id
+	}
+
+	return 0
+}
+
+func (subscription *Subscription) GetUserIdSafe() int {
+	if subscription != nil {
+		return subscription.UserId
+	}
+
+	return 0
+}
+
+func (subscription *Subscription) GetStatusSafe() SubscriptionStatus {
+	if subscription != nil {
+		return subscription.Status
+	}
+
+	return ""
+}
+
+// NextRunAfter is the first time after t the cron matches in the timezone of the subscription.
+func (subscription *Subscription) NextRunAfter(t time.Time) (time.Time, error) {
+	cron, err := pkg.ParseCron(subscription.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	location, err := pkg.LoadLocation(subscription.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := cron.Next(t.In(location))
+	if next.IsZero() {
+		return time.Time{}, ErrScheduleNeverRuns
+	}
+
+	return next.UTC(), nil
+}
+
+// ToOrder builds the order of a run, it is priced, taxed and paid from the wallet like any other order.
+func (subscription *Subscription) ToOrder() orderEntity.Order {
+	items := make([]orderEntity.OrderItem, 0, len(subscription.Items))
+	for _, item := range subscription.Items {
+		items = append(items, orderEntity.NewOrderItem(0, item.ProductId, "", 0.0, item.Quantity))
+	}
+
+	order := orderEntity.NewOrder(0, 0, 0.0, items)
+	order.SetAddressId(subscription.AddressId)
+
+	return order
+}
+
+func (subscription *Subscription) Pause(now time.Time) error {
+	if subscription == nil {
+		return ErrInvalidMemory
+	}
+
+	if subscription.Status != SubscriptionStatusActive {
+		return ErrSubscriptionNotActive
+	}
+
+	subscription.Status = SubscriptionStatusPaused
+	subscription.UpdatedAt = &now
+
+	return nil
+}
+
+// Resume activates a paused subscription from its next run after now, the runs missed while it was paused
+// are not made up for.
+func (subscription *Subscription) Resume(now time.Time) error {
+	if subscription == nil {
+		return ErrInvalidMemory
+	}
+
+	if subscription.Status != SubscriptionStatusPaused {
+		return ErrSubscriptionNotPaused
+	}
+
+	next, err := subscription.NextRunAfter(now)
+	if err != nil {
+		return err
+	}
+
+	subscription.Status = SubscriptionStatusActive
+	subscription.NextRunAt = next
+	subscription.Attempts = 0
+	subscription.LastError = ""
+	subscription.UpdatedAt = &now
+
+	return nil
+}
+
+// SkipNext moves an active subscription past its next run, a run being retried is given up.
+func (subscription *Subscription) SkipNext(now time.Time) error {
+	if subscription == nil {
+		return ErrInvalidMemory
+	}
+
+	if subscription.Status != SubscriptionStatusActive {
+		return ErrSubscriptionNotActive
+	}
+
+	from := subscription.NextRunAt
+	if from.Before(now) {
+		from = now
+	}
+
+	next, err := subscription.NextRunAfter(from)
+	if err != nil {
+		return err
+	}
+
+	subscription.NextRunAt = next
+	subscription.Attempts = 0
+	subscription.UpdatedAt = &now
+
+	return nil
+}
+
+// Record applies the result of a run and tells the user about it. A retryable failure is tried again
+// after retryDelay times the failed attempts, until maxAttempts or the next run comes first and the run is
+// given up. Any other failure pauses the subscription until the user resumes it. A subscription paused
+// while its order was being placed stays paused.
+func (subscription *Subscription) Record(result RunResult, now time.Time, maxAttempts int, retryDelay time.Duration) (*Notice, error) {
+	if subscription == nil {
+		return nil, ErrInvalidMemory
+	}
+
+	subscription.LastRunAt = &now
+	subscription.UpdatedAt = &now
+
+	notice := Notice{OrderId: result.OrderId}
+	if result.Err == nil {
+		subscription.LastOrderId = result.OrderId
+		subscription.LastError = ""
+		notice.Type = EventSubscriptionOrderCreated
+	} else {
+		subscription.LastError = result.Err.Error()
+		notice.Reason = subscription.LastError
+	}
+
+	if subscription.Status != SubscriptionStatusActive {
+		if result.Err != nil {
+			return nil, nil
+		}
+
+		notice.Subscription = *subscription
+
+		return &notice, nil
+	}
+
+	next, err := subscription.NextRunAfter(now)
+	if err != nil {
+		// a subscription without a next run would stay due forever
+		subscription.Status = SubscriptionStatusPaused
+		subscription.LastError = err.Error()
+		notice.Type = EventSubscriptionPaused
+		notice.Reason = subscription.LastError
+		notice.Subscription = *subscription
+
+		return &notice, nil
+	}
+
+	switch {
+	case result.Err == nil:
+		subscription.Attempts = 0
+		subscription.NextRunAt = next
+	case !result.Retryable:
+		subscription.Status = SubscriptionStatusPaused
+		subscription.Attempts = 0
+		subscription.NextRunAt = next
+		notice.Type = EventSubscriptionPaused
+	default:
+		subscription.Attempts++
+
+		retryAt := now.Add(retryDelay * time.Duration(subscription.Attempts))
+		if subscription.Attempts >= maxAttempts || !retryAt.Before(next) {
+			subscription.Attempts = 0
+			subscription.NextRunAt = next
+			notice.Type = EventSubscriptionRunSkipped
+		} else {
+			subscription.NextRunAt = retryAt
+			notice.Type = EventSubscriptionRunFailed
+			notice.RetryAt = &retryAt
+		}
+	}
+
+	notice.Subscription = *subscription
+
+	return &notice, nil
+}
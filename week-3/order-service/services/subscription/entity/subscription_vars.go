@@ -0,0 +1,122 @@
+package entity
+
+import (
+	"fmt"
+	"order_service/pkg"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxSubscriptionItems = 20
+	// monthly subscriptions starting after the 28th run on the 28th, every month has one
+	maxMonthlyDay = 28
+)
+
+type SubscriptionRequest struct {
+	Name      string    `json:"name" example:"Weekly groceries"`
+	Frequency Frequency `json:"frequency" enums:"daily,weekly,monthly,cron"`
+	// Cron is only read for the cron frequency
+	Cron     string `json:"cron" example:"0 8 * * MON"`
+	Timezone string `json:"timezone" example:"Asia/Jakarta"`
+	// StartAt is the first run of a daily, weekly or monthly subscription, when empty it is timed from now
+	// and the first order is placed one period later
+	StartAt   *time.Time         `json:"start_at"`
+	Items     []SubscriptionItem `json:"items"`
+	AddressId int                `json:"address_id"`
+}
+
+func (data SubscriptionRequest) Validate() error {
+	if utf8.RuneCountInString(data.Name) > 100 {
+		return ErrInvalidSubscriptionName
+	}
+
+	if !data.Frequency.IsValid() {
+		return ErrInvalidFrequency
+	}
+
+	if data.Frequency == FrequencyCron {
+		if _, err := pkg.ParseCron(data.Cron); err != nil {
+			return err
+		}
+	}
+
+	if data.Timezone != "" {
+		if _, err := pkg.LoadLocation(data.Timezone); err != nil {
+			return err
+		}
+	}
+
+	if len(data.Items) == 0 || len(data.Items) > MaxSubscriptionItems {
+		return ErrInvalidItems
+	}
+
+	seen := make(map[int]bool, len(data.Items))
+	for _, item := range data.Items {
+		if item.ProductId <= 0 || item.Quantity <= 0 || seen[item.ProductId] {
+			return ErrInvalidItems
+		}
+		seen[item.ProductId] = true
+	}
+
+	if data.AddressId < 0 {
+		return ErrInvalidAddress
+	}
+
+	return nil
+}
+
+// ApplyTo sets the subscription from a validated request and works out its next run from now. The cron of
+// a daily, weekly or monthly subscription is taken from its first run in its timezone, UTC unless asked
+// otherwise.
+func (data SubscriptionRequest) ApplyTo(subscription *Subscription, now time.Time) error {
+	subscription.Name = strings.TrimSpace(data.Name)
+	subscription.Frequency = data.Frequency
+	subscription.Items = data.Items
+	subscription.AddressId = data.AddressId
+
+	subscription.Timezone = data.Timezone
+	if subscription.Timezone == "" {
+		subscription.Timezone = time.UTC.String()
+	}
+
+	location, err := pkg.LoadLocation(subscription.Timezone)
+	if err != nil {
+		return err
+	}
+
+	start := now
+	if data.StartAt != nil && data.StartAt.After(now) {
+		start = *data.StartAt
+	}
+	first := start.In(location)
+
+	switch data.Frequency {
+	case FrequencyDaily:
+		subscription.Cron = fmt.Sprintf("%d %d * * *", first.Minute(), first.Hour())
+	case FrequencyWeekly:
+		subscription.Cron = fmt.Sprintf("%d %d * * %d", first.Minute(), first.Hour(), first.Weekday())
+	case FrequencyMonthly:
+		subscription.Cron = fmt.Sprintf("%d %d %d * *", first.Minute(), first.Hour(), min(first.Day(), maxMonthlyDay))
+	case FrequencyCron:
+		subscription.Cron = strings.TrimSpace(data.Cron)
+	default:
+		return ErrInvalidFrequency
+	}
+
+	// the first run itself matches, the cron only ever matches whole minutes
+	next, err := subscription.NextRunAfter(start.Truncate(time.Minute).Add(-time.Second))
+	if err != nil {
+		return err
+	}
+	if next.Before(now) {
+		next, err = subscription.NextRunAfter(now)
+		if err != nil {
+			return err
+		}
+	}
+	subscription.NextRunAt = next
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/pkg"
+	outboxEntity "order_service/services/outbox/entity"
+	"order_service/services/subscription/entity"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SubscriptionRepository interface {
+	CreateSubscription(ctx context.Context, subscription *entity.Subscription) error
+	GetSubscriptions(ctx context.Context, userId int) (*[]entity.Subscription, error)
+	GetSubscription(ctx context.Context, subscriptionId int) (*entity.Subscription, error)
+	UpdateSubscription(ctx context.Context, subscriptionId int, callbackFn func(subscription *entity.Subscription) error) error
+	DeleteSubscription(ctx context.Context, userId, subscriptionId int) error
+	ClaimDueSubscription(ctx context.Context, now, leaseUntil time.Time) (*entity.Subscription, error)
+	RecordRun(ctx context.Context, subscriptionId int, callbackFn func(subscription *entity.Subscription) (*entity.Notice, error)) error
+}
+
+const (
+	QUERY_CREATE_SUBSCRIPTION_WITH_RETURN_ID = "INSERT INTO subscriptions (user_id, name, frequency, cron, timezone, items, address_id, status, next_run_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id"
+	QUERY_GET_SUBSCRIPTIONS                  = "SELECT id, user_id, name, frequency, cron, timezone, items, address_id, status, next_run_at, attempts, last_error, last_run_at, COALESCE(last_order_id, 0), created_at, updated_at FROM subscriptions WHERE user_id = $1 ORDER BY id"
+	QUERY_GET_SUBSCRIPTION                   = "SELECT id, user_id, name, frequency, cron, timezone, items, address_id, status, next_run_at, attempts, last_error, last_run_at, COALESCE(last_order_id, 0), created_at, updated_at FROM subscriptions WHERE id = $1"
+	QUERY_GET_SUBSCRIPTION_LOCK              = "SELECT id, user_id, name, frequency, cron, timezone, items, address_id, status, next_run_at, attempts, last_error, last_run_at, COALESCE(last_order_id, 0), created_at, updated_at FROM subscriptions WHERE id = $1 FOR UPDATE"
+	QUERY_CLAIM_SUBSCRIPTION                 = "UPDATE subscriptions SET next_run_at = $2 WHERE id = (SELECT id FROM subscriptions WHERE status = 'active' AND next_run_at <= $1 ORDER BY next_run_at, id LIMIT 1 FOR UPDATE SKIP LOCKED) RETURNING id, user_id, name, frequency, cron, timezone, items, address_id, status, next_run_at, attempts, last_error, last_run_at, COALESCE(last_order_id, 0), created_at, updated_at"
+	QUERY_UPDATE_SUBSCRIPTION                = "UPDATE subscriptions SET name = $2, frequency = $3, cron = $4, timezone = $5, items = $6, address_id = $7, status = $8, next_run_at = $9, attempts = $10, last_error = $11, last_run_at = $12, last_order_id = NULLIF($13, 0), updated_at = $14 WHERE id = $1"
+	QUERY_DELETE_SUBSCRIPTION                = "DELETE FROM subscriptions WHERE id = $1 AND user_id = $2"
+	QUERY_CREATE_OUTBOX_EVENT                = "INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload, available_at, created_at) VALUES ($1, $2, $3, $4, $5, $5)"
+)
+
+type postgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSubscriptionRepo(db *pgxpool.Pool) SubscriptionRepository {
+	return &postgresRepo{
+		db,
+	}
+}
+
+func scanSubscription(row pgx.Row) (*entity.Subscription, error) {
+	var subscription entity.Subscription
+
+	err := row.Scan(&subscription.Id, &subscription.UserId, &subscription.Name, &subscription.Frequency, &subscription.Cron, &subscription.Timezone, &subscription.Items, &subscription.AddressId, &subscription.Status, &subscription.NextRunAt, &subscription.Attempts, &subscription.LastError, &subscription.LastRunAt, &subscription.LastOrderId, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, core.ErrRecordNotFound
+		}
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func updateSubscription(ctx context.Context, tx pgx.Tx, subscription *entity.Subscription) error {
+	_, err := tx.Exec(ctx, QUERY_UPDATE_SUBSCRIPTION, subscription.Id, subscription.Name, subscription.Frequency, subscription.Cron, subscription.Timezone, subscription.Items, subscription.AddressId, subscription.Status, subscription.NextRunAt, subscription.Attempts, subscription.LastError, subscription.LastRunAt, subscription.LastOrderId, subscription.UpdatedAt)
+
+	return err
+}
+
+func (repo *postgresRepo) CreateSubscription(ctx context.Context, subscription *entity.Subscription) error {
+	var newSubscriptionId int
+
+	err := repo.db.QueryRow(ctx, QUERY_CREATE_SUBSCRIPTION_WITH_RETURN_ID, subscription.UserId, subscription.Name, subscription.Frequency, subscription.Cron, subscription.Timezone, subscription.Items, subscription.AddressId, subscription.Status, subscription.NextRunAt, subscription.CreatedAt).Scan(&newSubscriptionId)
+	if err != nil {
+		return err
+	}
+	subscription.SetId(newSubscriptionId)
+
+	return nil
+}
+
+func (repo *postgresRepo) GetSubscriptions(ctx context.Context, userId int) (*[]entity.Subscription, error) {
+	rows, err := repo.db.Query(ctx, QUERY_GET_SUBSCRIPTIONS, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.Subscription, error) {
+		subscription, err := scanSubscription(row)
+		if err != nil {
+			return entity.Subscription{}, err
+		}
+
+		return *subscription, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscriptions, nil
+}
+
+func (repo *postgresRepo) GetSubscription(ctx context.Context, subscriptionId int) (*entity.Subscription, error) {
+	return scanSubscription(repo.db.QueryRow(ctx, QUERY_GET_SUBSCRIPTION, subscriptionId))
+}
+
+func (repo *postgresRepo) UpdateSubscription(ctx context.Context, subscriptionId int, callbackFn func(subscription *entity.Subscription) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		subscription, err := scanSubscription(tx.QueryRow(ctx, QUERY_GET_SUBSCRIPTION_LOCK, subscriptionId))
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		err = callbackFn(subscription)
+		if err != nil {
+			return err
+		}
+
+		return updateSubscription(ctx, tx, subscription)
+	})
+}
+
+func (repo *postgresRepo) DeleteSubscription(ctx context.Context, userId, subscriptionId int) error {
+	tag, err := repo.db.Exec(ctx, QUERY_DELETE_SUBSCRIPTION, subscriptionId, userId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return core.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ClaimDueSubscription takes the subscription that has been due the longest and holds it off until
+// leaseUntil, so no other scheduler runs it meanwhile. A run that never gets recorded is claimed again once
+// the lease is over. core.ErrRecordNotFound means nothing is due.
+func (repo *postgresRepo) ClaimDueSubscription(ctx context.Context, now, leaseUntil time.Time) (*entity.Subscription, error) {
+	return scanSubscription(repo.db.QueryRow(ctx, QUERY_CLAIM_SUBSCRIPTION, now, leaseUntil))
+}
+
+// RecordRun hands the locked subscription to callbackFn, which applies the result of its run and returns
+// the notice to send the user, if any. The subscription and the notice are written together.
+func (repo *postgresRepo) RecordRun(ctx context.Context, subscriptionId int, callbackFn func(subscription *entity.Subscription) (*entity.Notice, error)) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		subscription, err := scanSubscription(tx.QueryRow(ctx, QUERY_GET_SUBSCRIPTION_LOCK, subscriptionId))
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		notice, err := callbackFn(subscription)
+		if err != nil {
+			return err
+		}
+
+		err = updateSubscription(ctx, tx, subscription)
+		if err != nil {
+			return err
+		}
+
+		if notice == nil {
+			return nil
+		}
+
+		return createNoticeEvent(ctx, tx, notice)
+	})
+}
+
+// createNoticeEvent writes the notice to the outbox within tx, it is only relayed if the run is recorded.
+func createNoticeEvent(ctx context.Context, tx pgx.Tx, notice *entity.Notice) error {
+	event, err := outboxEntity.NewEvent(entity.EventAggregateSubscription, notice.Subscription.GetIdSafe(), notice.Type, notice)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, QUERY_CREATE_OUTBOX_EVENT, event.GetAggregateType(), event.GetAggregateId(), event.GetType(), event.GetPayload(), event.GetCreatedAt())
+
+	return err
+}
@@ -0,0 +1,272 @@
+package test
+
+import (
+	"errors"
+	"order_service/pkg"
+	"order_service/services/subscription/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionRequestValidate(t *testing.T) {
+	valid := entity.SubscriptionRequest{Name: "Weekly groceries", Frequency: entity.FrequencyWeekly, Items: []entity.SubscriptionItem{{ProductId: 1, Quantity: 2}}}
+
+	tests := []struct {
+		name    string
+		modify  func(data *entity.SubscriptionRequest)
+		wantErr error
+	}{
+		{name: "Valid", modify: func(data *entity.SubscriptionRequest) {}},
+		{name: "Cron frequency", modify: func(data *entity.SubscriptionRequest) {
+			data.Frequency, data.Cron = entity.FrequencyCron, "0 8 * * MON"
+		}},
+		{name: "Cron is only read for the cron frequency", modify: func(data *entity.SubscriptionRequest) { data.Cron = "every monday" }},
+		{name: "Unknown frequency", modify: func(data *entity.SubscriptionRequest) { data.Frequency = "yearly" }, wantErr: entity.ErrInvalidFrequency},
+		{name: "Invalid cron", modify: func(data *entity.SubscriptionRequest) {
+			data.Frequency, data.Cron = entity.FrequencyCron, "every monday"
+		}, wantErr: pkg.ErrInvalidCron},
+		{name: "Unknown timezone", modify: func(data *entity.SubscriptionRequest) { data.Timezone = "Mars/Olympus" }, wantErr: pkg.ErrInvalidTimezone},
+		{name: "No items", modify: func(data *entity.SubscriptionRequest) { data.Items = nil }, wantErr: entity.ErrInvalidItems},
+		{name: "Quantity must be positive", modify: func(data *entity.SubscriptionRequest) {
+			data.Items = []entity.SubscriptionItem{{ProductId: 1, Quantity: -1}}
+		}, wantErr: entity.ErrInvalidItems},
+		{name: "Product listed twice", modify: func(data *entity.SubscriptionRequest) {
+			data.Items = []entity.SubscriptionItem{{ProductId: 1, Quantity: 1}, {ProductId: 1, Quantity: 2}}
+		}, wantErr: entity.ErrInvalidItems},
+		{name: "Negative address", modify: func(data *entity.SubscriptionRequest) { data.AddressId = -1 }, wantErr: entity.ErrInvalidAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := valid
+			tt.modify(&data)
+
+			assert.Equal(t, tt.wantErr, data.Validate())
+		})
+	}
+}
+
+func TestSubscriptionRequestApplyTo(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	// a Wednesday
+	now := time.Date(2024, 1, 31, 10, 30, 20, 0, time.UTC)
+	startAt := time.Date(2024, 2, 3, 8, 15, 0, 0, jakarta)
+
+	tests := []struct {
+		name     string
+		data     entity.SubscriptionRequest
+		wantCron string
+		wantNext time.Time
+	}{
+		{
+			name:     "Daily from now",
+			data:     entity.SubscriptionRequest{Frequency: entity.FrequencyDaily},
+			wantCron: "30 10 * * *",
+			wantNext: time.Date(2024, 2, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "Weekly from the first run",
+			data:     entity.SubscriptionRequest{Frequency: entity.FrequencyWeekly, Timezone: "Asia/Jakarta", StartAt: &startAt},
+			wantCron: "15 8 * * 6",
+			wantNext: startAt.UTC(),
+		},
+		{
+			name:     "Monthly after the 28th runs on the 28th",
+			data:     entity.SubscriptionRequest{Frequency: entity.FrequencyMonthly},
+			wantCron: "30 10 28 * *",
+			wantNext: time.Date(2024, 2, 28, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "Cron",
+			data:     entity.SubscriptionRequest{Frequency: entity.FrequencyCron, Cron: " 0 8 * * MON "},
+			wantCron: "0 8 * * MON",
+			wantNext: time.Date(2024, 2, 5, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := entity.NewSubscription(2, now)
+
+			require.NoError(t, tt.data.ApplyTo(&subscription, now))
+			assert.Equal(t, tt.wantCron, subscription.Cron)
+			assert.True(t, tt.wantNext.Equal(subscription.NextRunAt), "want %v, got %v", tt.wantNext, subscription.NextRunAt)
+			assert.Equal(t, entity.SubscriptionStatusActive, subscription.Status)
+		})
+	}
+}
+
+func newWeeklySubscription(now time.Time) entity.Subscription {
+	subscription := entity.NewSubscription(2, now)
+	subscription.Cron = "0 8 * * MON"
+	subscription.Timezone = "UTC"
+	subscription.NextRunAt = time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+
+	return subscription
+}
+
+func TestSubscriptionPauseResume(t *testing.T) {
+	now := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	subscription := newWeeklySubscription(now)
+
+	require.NoError(t, subscription.Pause(now))
+	assert.Equal(t, entity.SubscriptionStatusPaused, subscription.Status)
+	assert.Equal(t, entity.ErrSubscriptionNotActive, subscription.Pause(now), "paused subscription should not be paused again")
+	assert.Equal(t, entity.ErrSubscriptionNotActive, subscription.SkipNext(now), "paused subscription should not be skipped")
+
+	later := time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC)
+	subscription.Attempts = 2
+
+	require.NoError(t, subscription.Resume(later))
+	assert.Equal(t, entity.SubscriptionStatusActive, subscription.Status)
+	assert.Equal(t, time.Date(2024, 1, 22, 8, 0, 0, 0, time.UTC), subscription.NextRunAt, "missed runs should not be made up for")
+	assert.Zero(t, subscription.Attempts)
+	assert.Equal(t, entity.ErrSubscriptionNotPaused, subscription.Resume(later), "active subscription should not be resumed")
+}
+
+func TestSubscriptionSkipNext(t *testing.T) {
+	now := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+
+	subscription := newWeeklySubscription(now)
+	require.NoError(t, subscription.SkipNext(now))
+	assert.Equal(t, time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC), subscription.NextRunAt, "next run should be skipped")
+
+	retrying := newWeeklySubscription(now)
+	retrying.NextRunAt = now.Add(time.Hour)
+	retrying.Attempts = 1
+	require.NoError(t, retrying.SkipNext(now))
+	assert.Equal(t, time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC), retrying.NextRunAt, "retry should be given up for the next run")
+	assert.Zero(t, retrying.Attempts)
+}
+
+func TestSubscriptionRecord(t *testing.T) {
+	// the Monday 8:00 run
+	now := time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+	nextRun := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+	lowBalance := errors.New("user's balance is insufficient")
+
+	tests := []struct {
+		name         string
+		attempts     int
+		status       entity.SubscriptionStatus
+		result       entity.RunResult
+		retryDelay   time.Duration
+		wantType     string
+		wantStatus   entity.SubscriptionStatus
+		wantNext     time.Time
+		wantAttempts int
+	}{
+		{
+			name:       "Order placed",
+			result:     entity.RunResult{OrderId: 42},
+			retryDelay: time.Hour,
+			wantType:   entity.EventSubscriptionOrderCreated,
+			wantStatus: entity.SubscriptionStatusActive,
+			wantNext:   nextRun,
+		},
+		{
+			name:         "Retryable failure is retried",
+			attempts:     1,
+			result:       entity.RunResult{Err: lowBalance, Retryable: true},
+			retryDelay:   time.Hour,
+			wantType:     entity.EventSubscriptionRunFailed,
+			wantStatus:   entity.SubscriptionStatusActive,
+			wantNext:     now.Add(2 * time.Hour),
+			wantAttempts: 2,
+		},
+		{
+			name:       "Last attempt gives the run up",
+			attempts:   2,
+			result:     entity.RunResult{Err: lowBalance, Retryable: true},
+			retryDelay: time.Hour,
+			wantType:   entity.EventSubscriptionRunSkipped,
+			wantStatus: entity.SubscriptionStatusActive,
+			wantNext:   nextRun,
+		},
+		{
+			name:       "Retry after the next run gives the run up",
+			result:     entity.RunResult{Err: lowBalance, Retryable: true},
+			retryDelay: 7 * 24 * time.Hour,
+			wantType:   entity.EventSubscriptionRunSkipped,
+			wantStatus: entity.SubscriptionStatusActive,
+			wantNext:   nextRun,
+		},
+		{
+			name:       "Other failure pauses",
+			result:     entity.RunResult{Err: errors.New("one item in order's items cannot be found")},
+			retryDelay: time.Hour,
+			wantType:   entity.EventSubscriptionPaused,
+			wantStatus: entity.SubscriptionStatusPaused,
+			wantNext:   nextRun,
+		},
+		{
+			name:       "Paused meanwhile keeps the order",
+			status:     entity.SubscriptionStatusPaused,
+			result:     entity.RunResult{OrderId: 42},
+			retryDelay: time.Hour,
+			wantType:   entity.EventSubscriptionOrderCreated,
+			wantStatus: entity.SubscriptionStatusPaused,
+			wantNext:   now.Add(5 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := newWeeklySubscription(now)
+			// claimed by the scheduler
+			subscription.NextRunAt = now.Add(5 * time.Minute)
+			subscription.Attempts = tt.attempts
+			if tt.status != "" {
+				subscription.Status = tt.status
+			}
+
+			notice, err := subscription.Record(tt.result, now, 3, tt.retryDelay)
+			require.NoError(t, err)
+			require.NotNil(t, notice)
+
+			assert.Equal(t, tt.wantType, notice.Type)
+			assert.Equal(t, tt.wantStatus, subscription.Status)
+			assert.Equal(t, tt.wantNext, subscription.NextRunAt)
+			assert.Equal(t, tt.wantAttempts, subscription.Attempts)
+			assert.Equal(t, subscription, notice.Subscription, "notice should carry the recorded subscription")
+
+			if tt.result.Err != nil {
+				assert.Equal(t, tt.result.Err.Error(), notice.Reason)
+				assert.Equal(t, tt.result.Err.Error(), subscription.LastError)
+			} else {
+				assert.Equal(t, tt.result.OrderId, subscription.LastOrderId)
+				assert.Empty(t, subscription.LastError)
+			}
+		})
+	}
+
+	t.Run("Failure while paused is not notified", func(t *testing.T) {
+		subscription := newWeeklySubscription(now)
+		subscription.Status = entity.SubscriptionStatusPaused
+
+		notice, err := subscription.Record(entity.RunResult{Err: lowBalance, Retryable: true}, now, 3, time.Hour)
+		require.NoError(t, err)
+		assert.Nil(t, notice)
+		assert.Equal(t, lowBalance.Error(), subscription.LastError)
+	})
+}
+
+func TestSubscriptionToOrder(t *testing.T) {
+	subscription := entity.NewSubscription(2, time.Now())
+	subscription.Items = []entity.SubscriptionItem{{ProductId: 1, Quantity: 2}, {ProductId: 5, Quantity: 1}}
+	subscription.AddressId = 7
+
+	order := subscription.ToOrder()
+
+	items := order.GetItemsSafe()
+	require.Len(t, items, 2)
+	assert.Equal(t, 1, items[0].GetProductId())
+	assert.Equal(t, 2, items[0].GetQuantity())
+	assert.Equal(t, 5, items[1].GetProductId())
+	assert.Equal(t, 7, order.GetAddressIdSafe())
+}
@@ -0,0 +1,418 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	orderEntity "order_service/services/order/entity"
+	paymentEntity "order_service/services/payment/entity"
+	"order_service/services/subscription/entity"
+	"order_service/services/subscription/test/mock"
+	"order_service/services/subscription/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type SubscriptionUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo  *mock.MockSubscriptionRepository
+	mockOrder *mock.MockOrderCreator
+	usecase   usecase.SubscriptionUsecase
+	adminCtx  context.Context
+	memberCtx context.Context
+}
+
+func (suite *SubscriptionUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockSubscriptionRepository(ctrl)
+	suite.mockOrder = mock.NewMockOrderCreator(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, suite.mockOrder, 3, time.Hour, 5*time.Minute)
+	suite.adminCtx = requesterContext(1, 1)
+	suite.memberCtx = requesterContext(2, 0)
+}
+
+// updateSubscription makes the mocked repo run the callback on a copy of subscription, like the locked row
+// would be.
+func updateSubscription(subscription entity.Subscription) func(ctx context.Context, subscriptionId int, callbackFn func(subscription *entity.Subscription) error) error {
+	return func(ctx context.Context, subscriptionId int, callbackFn func(subscription *entity.Subscription) error) error {
+		return callbackFn(&subscription)
+	}
+}
+
+func dueSubscription() *entity.Subscription {
+	return &entity.Subscription{
+		Id:        7,
+		UserId:    2,
+		Cron:      "0 8 * * MON",
+		Timezone:  "UTC",
+		Status:    entity.SubscriptionStatusActive,
+		Items:     []entity.SubscriptionItem{{ProductId: 1, Quantity: 2}},
+		NextRunAt: time.Now().UTC().Add(5 * time.Minute),
+	}
+}
+
+func (suite *SubscriptionUsecaseTestSuite) TestCreateSubscription() {
+	data := entity.SubscriptionRequest{Name: "Weekly groceries", Frequency: entity.FrequencyWeekly, Items: []entity.SubscriptionItem{{ProductId: 1, Quantity: 2}}}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		data      entity.SubscriptionRequest
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Member subscribes",
+			ctx:       suite.memberCtx,
+			data:      data,
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Admin cannot subscribe",
+			ctx:       suite.adminCtx,
+			data:      data,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrSubscriptionAdminOnly.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Invalid cron",
+			ctx:       suite.memberCtx,
+			data:      entity.SubscriptionRequest{Frequency: entity.FrequencyCron, Cron: "0 8 31 2 *", Items: data.Items},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrScheduleNeverRuns.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repository error",
+			ctx:       suite.memberCtx,
+			data:      data,
+			callRepo:  true,
+			repoErr:   errors.New("connection reset"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotCreateSubscription.Error()).WithDebug("connection reset"),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().CreateSubscription(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, subscription *entity.Subscription) error {
+					subscription.SetId(7)
+					return tt.repoErr
+				})
+			}
+
+			subscription, err := suite.usecase.CreateSubscription(tt.ctx, &tt.data)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(7, subscription.GetIdSafe(), "subscription id should be set")
+				suite.Equal(2, subscription.GetUserIdSafe(), "subscription should belong to the requester")
+				suite.Equal(entity.SubscriptionStatusActive, subscription.GetStatusSafe(), "subscription should be active")
+				suite.True(subscription.NextRunAt.After(time.Now()), "next run should be ahead")
+			}
+		})
+	}
+}
+
+func (suite *SubscriptionUsecaseTestSuite) TestGetSubscription() {
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		subscription *entity.Subscription
+		repoErr      error
+		wantErr      error
+		assertion    assert.ErrorAssertionFunc
+	}{
+		{
+			name:         "Owner sees the subscription",
+			ctx:          suite.memberCtx,
+			subscription: &entity.Subscription{Id: 7, UserId: 2},
+			assertion:    assert.NoError,
+		},
+		{
+			name:         "Admin sees any subscription",
+			ctx:          suite.adminCtx,
+			subscription: &entity.Subscription{Id: 7, UserId: 2},
+			assertion:    assert.NoError,
+		},
+		{
+			name:         "Subscription of someone else is missing",
+			ctx:          suite.memberCtx,
+			subscription: &entity.Subscription{Id: 7, UserId: 3},
+			wantErr:      core.ErrNotFound.WithError(entity.ErrSubscriptionNotFound.Error()),
+			assertion:    assert.Error,
+		},
+		{
+			name:      "Unknown subscription",
+			ctx:       suite.memberCtx,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrSubscriptionNotFound.Error()).WithDebug(core.ErrRecordNotFound.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.mockRepo.EXPECT().GetSubscription(gomock.Any(), 7).Return(tt.subscription, tt.repoErr)
+
+			subscription, err := suite.usecase.GetSubscription(tt.ctx, 7)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(tt.subscription, subscription, "subscription should be returned")
+			}
+		})
+	}
+}
+
+func (suite *SubscriptionUsecaseTestSuite) TestChangeSubscription() {
+	paused := *dueSubscription()
+	paused.Status = entity.SubscriptionStatusPaused
+
+	foreign := *dueSubscription()
+	foreign.UserId = 3
+
+	tests := []struct {
+		name         string
+		changeFn     func(uc usecase.SubscriptionUsecase, ctx context.Context, subscriptionId int) (*entity.Subscription, error)
+		subscription entity.Subscription
+		repoErr      error
+		wantStatus   entity.SubscriptionStatus
+		wantErr      error
+		assertion    assert.ErrorAssertionFunc
+	}{
+		{
+			name:         "Pause",
+			changeFn:     usecase.SubscriptionUsecase.PauseSubscription,
+			subscription: *dueSubscription(),
+			wantStatus:   entity.SubscriptionStatusPaused,
+			assertion:    assert.NoError,
+		},
+		{
+			name:         "Pause a paused subscription",
+			changeFn:     usecase.SubscriptionUsecase.PauseSubscription,
+			subscription: paused,
+			wantErr:      core.ErrConfict.WithError(entity.ErrSubscriptionNotActive.Error()),
+			assertion:    assert.Error,
+		},
+		{
+			name:         "Resume",
+			changeFn:     usecase.SubscriptionUsecase.ResumeSubscription,
+			subscription: paused,
+			wantStatus:   entity.SubscriptionStatusActive,
+			assertion:    assert.NoError,
+		},
+		{
+			name:         "Resume an active subscription",
+			changeFn:     usecase.SubscriptionUsecase.ResumeSubscription,
+			subscription: *dueSubscription(),
+			wantErr:      core.ErrConfict.WithError(entity.ErrSubscriptionNotPaused.Error()),
+			assertion:    assert.Error,
+		},
+		{
+			name:         "Skip the next run",
+			changeFn:     usecase.SubscriptionUsecase.SkipNextRun,
+			subscription: *dueSubscription(),
+			wantStatus:   entity.SubscriptionStatusActive,
+			assertion:    assert.NoError,
+		},
+		{
+			name:         "Subscription of someone else is missing",
+			changeFn:     usecase.SubscriptionUsecase.PauseSubscription,
+			subscription: foreign,
+			wantErr:      core.ErrNotFound.WithError(entity.ErrSubscriptionNotFound.Error()).WithDebug(entity.ErrSubscriptionNotFound.Error()),
+			assertion:    assert.Error,
+		},
+		{
+			name:      "Unknown subscription",
+			changeFn:  usecase.SubscriptionUsecase.PauseSubscription,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrSubscriptionNotFound.Error()).WithDebug(core.ErrRecordNotFound.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.repoErr != nil {
+				suite.mockRepo.EXPECT().UpdateSubscription(gomock.Any(), 7, gomock.Any()).Return(tt.repoErr)
+			} else {
+				suite.mockRepo.EXPECT().UpdateSubscription(gomock.Any(), 7, gomock.Any()).DoAndReturn(updateSubscription(tt.subscription))
+			}
+
+			subscription, err := tt.changeFn(suite.usecase, suite.memberCtx, 7)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal(tt.wantStatus, subscription.GetStatusSafe(), "status should be changed")
+				suite.NotNil(subscription.UpdatedAt, "update time should be set")
+			}
+		})
+	}
+}
+
+func (suite *SubscriptionUsecaseTestSuite) TestRunNextSubscription() {
+	outOfStock := core.ErrConfict.WithError(orderEntity.ErrOutOfStock.Error())
+	productGone := core.ErrNotFound.WithError(orderEntity.ErrProductNotFound.Error())
+
+	tests := []struct {
+		name         string
+		claimErr     error
+		orderErr     error
+		payment      *paymentEntity.Payment
+		callCancel   bool
+		recordErr    error
+		wantRan      bool
+		wantType     string
+		wantAttempts int
+		wantStatus   entity.SubscriptionStatus
+		wantErr      error
+		assertion    assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Nothing is due",
+			claimErr:  core.ErrRecordNotFound,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Claim error",
+			claimErr:  errors.New("connection reset"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotRunSubscription.Error()).WithDebug("connection reset"),
+			assertion: assert.Error,
+		},
+		{
+			name:       "Order is placed and paid",
+			payment:    &paymentEntity.Payment{Status: paymentEntity.PaymentStatusCaptured},
+			wantRan:    true,
+			wantType:   entity.EventSubscriptionOrderCreated,
+			wantStatus: entity.SubscriptionStatusActive,
+			assertion:  assert.NoError,
+		},
+		{
+			name:         "Unpaid order is cancelled and retried",
+			payment:      &paymentEntity.Payment{Status: paymentEntity.PaymentStatusFailed, FailureReason: "user's balance is insufficient"},
+			callCancel:   true,
+			wantRan:      true,
+			wantType:     entity.EventSubscriptionRunFailed,
+			wantAttempts: 1,
+			wantStatus:   entity.SubscriptionStatusActive,
+			assertion:    assert.NoError,
+		},
+		{
+			name:         "Out of stock is retried",
+			orderErr:     outOfStock,
+			wantRan:      true,
+			wantType:     entity.EventSubscriptionRunFailed,
+			wantAttempts: 1,
+			wantStatus:   entity.SubscriptionStatusActive,
+			assertion:    assert.NoError,
+		},
+		{
+			name:       "Missing product pauses",
+			orderErr:   productGone,
+			wantRan:    true,
+			wantType:   entity.EventSubscriptionPaused,
+			wantStatus: entity.SubscriptionStatusPaused,
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Deleted while running",
+			payment:   &paymentEntity.Payment{Status: paymentEntity.PaymentStatusCaptured},
+			recordErr: core.ErrRecordNotFound,
+			wantRan:   true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Record error",
+			payment:   &paymentEntity.Payment{Status: paymentEntity.PaymentStatusCaptured},
+			recordErr: errors.New("connection reset"),
+			wantRan:   true,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotRunSubscription.Error()).WithDebug("connection reset"),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.claimErr != nil {
+				suite.mockRepo.EXPECT().ClaimDueSubscription(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, tt.claimErr)
+			} else {
+				suite.mockRepo.EXPECT().ClaimDueSubscription(gomock.Any(), gomock.Any(), gomock.Any()).Return(dueSubscription(), nil)
+				suite.mockOrder.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, order *orderEntity.Order) error {
+					requester := core.GetRequester(ctx)
+					uid, err := core.DecomposeUID(requester.GetSubject())
+					suite.NoError(err)
+					suite.Equal(uint32(2), uid.GetLocalID(), "order should be placed as the subscribed user")
+					suite.Len(order.GetItemsSafe(), 1, "order should have the subscribed items")
+
+					if tt.orderErr != nil {
+						return tt.orderErr
+					}
+					order.SetId(42)
+					order.SetPayment(tt.payment)
+
+					return nil
+				})
+				if tt.callCancel {
+					suite.mockOrder.EXPECT().CancelOrder(gomock.Any(), 42).Return(nil)
+				}
+
+				var notice *entity.Notice
+				suite.mockRepo.EXPECT().RecordRun(gomock.Any(), 7, gomock.Any()).DoAndReturn(func(ctx context.Context, subscriptionId int, callbackFn func(subscription *entity.Subscription) (*entity.Notice, error)) error {
+					var err error
+					notice, err = callbackFn(dueSubscription())
+					suite.NoError(err)
+
+					return tt.recordErr
+				})
+				defer func() {
+					if tt.wantType == "" {
+						return
+					}
+					suite.Equal(tt.wantType, notice.Type, "notice should be of the outcome")
+					suite.Equal(tt.wantAttempts, notice.Subscription.Attempts, "attempts should be counted")
+					suite.Equal(tt.wantStatus, notice.Subscription.Status, "status should follow the outcome")
+				}()
+			}
+
+			ran, err := suite.usecase.RunNextSubscription(context.Background())
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			suite.Equal(tt.wantRan, ran, "ran should be reported")
+		})
+	}
+}
+
+func TestSubscriptionUsecase(t *testing.T) {
+	suite.Run(t, new(SubscriptionUsecaseTestSuite))
+}
+
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
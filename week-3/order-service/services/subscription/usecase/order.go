@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"context"
+	orderEntity "order_service/services/order/entity"
+)
+
+// OrderCreator places the orders of the subscriptions as their users. It is implemented by the order
+// usecase.
+type OrderCreator interface {
+	CreateOrder(ctx context.Context, data *orderEntity.Order) error
+	CancelOrder(ctx context.Context, orderId int) error
+}
@@ -0,0 +1,314 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"order_service/internal/core"
+	"order_service/pkg"
+	orderEntity "order_service/services/order/entity"
+	paymentEntity "order_service/services/payment/entity"
+	"order_service/services/subscription/entity"
+	subscriptionRepo "order_service/services/subscription/repository/postgres"
+	"time"
+)
+
+type SubscriptionUsecase interface {
+	CreateSubscription(ctx context.Context, data *entity.SubscriptionRequest) (*entity.Subscription, error)
+	GetSubscriptions(ctx context.Context) (*[]entity.Subscription, error)
+	GetSubscription(ctx context.Context, subscriptionId int) (*entity.Subscription, error)
+	UpdateSubscription(ctx context.Context, subscriptionId int, data *entity.SubscriptionRequest) (*entity.Subscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionId int) error
+	PauseSubscription(ctx context.Context, subscriptionId int) (*entity.Subscription, error)
+	ResumeSubscription(ctx context.Context, subscriptionId int) (*entity.Subscription, error)
+	SkipNextRun(ctx context.Context, subscriptionId int) (*entity.Subscription, error)
+	RunNextSubscription(ctx context.Context) (bool, error)
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+type subscriptionUsecase struct {
+	repo        subscriptionRepo.SubscriptionRepository
+	order       OrderCreator
+	maxAttempts int
+	retryDelay  time.Duration
+	claimTTL    time.Duration
+}
+
+// NewUsecase builds the subscription usecase. A run failing for a reason that may go away, like a low
+// balance or an out of stock product, gets maxAttempts tries, the n-th failed one is retried after
+// n * retryDelay. A run is held by its scheduler for claimTTL and is run again once it is over.
+func NewUsecase(repo subscriptionRepo.SubscriptionRepository, order OrderCreator, maxAttempts int, retryDelay, claimTTL time.Duration) SubscriptionUsecase {
+	return &subscriptionUsecase{
+		repo,
+		order,
+		maxAttempts,
+		retryDelay,
+		claimTTL,
+	}
+}
+
+func getRequester(ctx context.Context) (int, bool, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return 0, false, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return int(uid.GetLocalID()), uid.GetRole() == 1, nil
+}
+
+// CreateSubscription stores a subscription of the requester, admin cannot place orders and so cannot
+// subscribe either.
+func (uc *subscriptionUsecase) CreateSubscription(ctx context.Context, data *entity.SubscriptionRequest) (*entity.Subscription, error) {
+	userId, admin, err := getRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if admin {
+		return nil, core.ErrBadRequest.WithError(entity.ErrSubscriptionAdminOnly.Error())
+	}
+
+	now := time.Now().UTC()
+	subscription := entity.NewSubscription(userId, now)
+
+	err = data.ApplyTo(&subscription, now)
+	if err != nil {
+		return nil, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	err = uc.repo.CreateSubscription(ctx, &subscription)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotCreateSubscription.Error()).WithDebug(err.Error())
+	}
+
+	return &subscription, nil
+}
+
+// GetSubscriptions returns the subscriptions of the requester.
+func (uc *subscriptionUsecase) GetSubscriptions(ctx context.Context) (*[]entity.Subscription, error) {
+	userId, _, err := getRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := uc.repo.GetSubscriptions(ctx, userId)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetSubscriptions.Error()).WithDebug(err.Error())
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscription returns a subscription of the requester, admin can see every subscription.
+func (uc *subscriptionUsecase) GetSubscription(ctx context.Context, subscriptionId int) (*entity.Subscription, error) {
+	userId, admin, err := getRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := uc.repo.GetSubscription(ctx, subscriptionId)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return nil, core.ErrNotFound.WithError(entity.ErrSubscriptionNotFound.Error()).WithDebug(err.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetSubscriptions.Error()).WithDebug(err.Error())
+	}
+
+	// someone else's subscription is reported as missing, not as forbidden
+	if !admin && subscription.GetUserIdSafe() != userId {
+		return nil, core.ErrNotFound.WithError(entity.ErrSubscriptionNotFound.Error())
+	}
+
+	return subscription, nil
+}
+
+// UpdateSubscription replaces the items and the schedule of a subscription of the requester, its next run
+// is worked out again from now.
+func (uc *subscriptionUsecase) UpdateSubscription(ctx context.Context, subscriptionId int, data *entity.SubscriptionRequest) (*entity.Subscription, error) {
+	return uc.updateOwnSubscription(ctx, subscriptionId, func(subscription *entity.Subscription, now time.Time) error {
+		err := data.ApplyTo(subscription, now)
+		if err != nil {
+			return err
+		}
+		subscription.Attempts = 0
+		subscription.UpdatedAt = &now
+
+		return nil
+	})
+}
+
+func (uc *subscriptionUsecase) PauseSubscription(ctx context.Context, subscriptionId int) (*entity.Subscription, error) {
+	return uc.updateOwnSubscription(ctx, subscriptionId, func(subscription *entity.Subscription, now time.Time) error {
+		return subscription.Pause(now)
+	})
+}
+
+func (uc *subscriptionUsecase) ResumeSubscription(ctx context.Context, subscriptionId int) (*entity.Subscription, error) {
+	return uc.updateOwnSubscription(ctx, subscriptionId, func(subscription *entity.Subscription, now time.Time) error {
+		return subscription.Resume(now)
+	})
+}
+
+func (uc *subscriptionUsecase) SkipNextRun(ctx context.Context, subscriptionId int) (*entity.Subscription, error) {
+	return uc.updateOwnSubscription(ctx, subscriptionId, func(subscription *entity.Subscription, now time.Time) error {
+		return subscription.SkipNext(now)
+	})
+}
+
+func (uc *subscriptionUsecase) updateOwnSubscription(ctx context.Context, subscriptionId int, callbackFn func(subscription *entity.Subscription, now time.Time) error) (*entity.Subscription, error) {
+	userId, _, err := getRequester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated entity.Subscription
+
+	err = uc.repo.UpdateSubscription(ctx, subscriptionId, func(subscription *entity.Subscription) error {
+		if subscription.GetUserIdSafe() != userId {
+			return entity.ErrSubscriptionNotFound
+		}
+
+		err := callbackFn(subscription, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		updated = *subscription
+
+		return nil
+	})
+	if err != nil {
+		switch err {
+		case core.ErrRecordNotFound, entity.ErrSubscriptionNotFound:
+			return nil, core.ErrNotFound.WithError(entity.ErrSubscriptionNotFound.Error()).WithDebug(err.Error())
+		case entity.ErrSubscriptionNotActive, entity.ErrSubscriptionNotPaused:
+			return nil, core.ErrConfict.WithError(err.Error())
+		case pkg.ErrInvalidTimezone, entity.ErrInvalidFrequency, entity.ErrScheduleNeverRuns, pkg.ErrInvalidCron:
+			return nil, core.ErrBadRequest.WithError(err.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotUpdateSubscription.Error()).WithDebug(err.Error())
+	}
+
+	return &updated, nil
+}
+
+func (uc *subscriptionUsecase) DeleteSubscription(ctx context.Context, subscriptionId int) error {
+	userId, _, err := getRequester(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = uc.repo.DeleteSubscription(ctx, userId, subscriptionId)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return core.ErrNotFound.WithError(entity.ErrSubscriptionNotFound.Error()).WithDebug(err.Error())
+		}
+
+		return core.ErrInternalServerError.WithError(entity.ErrCannotDeleteSubscription.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// RunNextSubscription places the order of the subscription that has been due the longest and records
+// how it went, it reports false when nothing was due.
+func (uc *subscriptionUsecase) RunNextSubscription(ctx context.Context) (bool, error) {
+	now := time.Now().UTC()
+
+	subscription, err := uc.repo.ClaimDueSubscription(ctx, now, now.Add(uc.claimTTL))
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return false, nil
+		}
+
+		return false, core.ErrInternalServerError.WithError(entity.ErrCannotRunSubscription.Error()).WithDebug(err.Error())
+	}
+
+	result := uc.placeOrder(ctx, subscription)
+
+	err = uc.repo.RecordRun(ctx, subscription.GetIdSafe(), func(subscription *entity.Subscription) (*entity.Notice, error) {
+		return subscription.Record(result, time.Now().UTC(), uc.maxAttempts, uc.retryDelay)
+	})
+	if err != nil {
+		// the subscription was deleted while its order was placed
+		if err == core.ErrRecordNotFound {
+			return true, nil
+		}
+
+		return true, core.ErrInternalServerError.WithError(entity.ErrCannotRunSubscription.Error()).WithDebug(err.Error())
+	}
+
+	if result.Err != nil {
+		log.Printf("subscription %d run error: %v", subscription.GetIdSafe(), result.Err)
+	}
+
+	return true, nil
+}
+
+// placeOrder creates the order of a run as the subscribed user and pays it from the wallet. An order that
+// could not be paid is cancelled so that its stock is not held until the reservation runs out, it is
+// placed again on the next attempt.
+func (uc *subscriptionUsecase) placeOrder(ctx context.Context, subscription *entity.Subscription) entity.RunResult {
+	sub := core.NewUID(uint32(subscription.GetUserIdSafe()), 0).String()
+	ctx = core.ContextWithRequester(ctx, core.NewRequester(sub, ""))
+
+	order := subscription.ToOrder()
+
+	err := uc.order.CreateOrder(ctx, &order)
+	if err != nil {
+		return entity.RunResult{Err: err, Retryable: isRetryable(err)}
+	}
+
+	if order.Payment.GetStatusSafe() == paymentEntity.PaymentStatusCaptured {
+		return entity.RunResult{OrderId: order.GetIdSafe()}
+	}
+
+	err = uc.order.CancelOrder(ctx, order.GetIdSafe())
+	if err != nil {
+		log.Printf("cancel unpaid subscription order %d error: %v", order.GetIdSafe(), err)
+	}
+
+	reason := entity.ErrOrderNotPaid
+	if order.Payment != nil && order.Payment.FailureReason != "" {
+		reason = errors.New(order.Payment.FailureReason)
+	}
+
+	return entity.RunResult{Err: reason, OrderId: order.GetIdSafe(), Retryable: true}
+}
+
+// isRetryable tells whether an order may go through on a later attempt, like once the product is back in
+// stock or the server is back up. Anything else, like a product that is gone, needs the user to step in.
+func isRetryable(err error) bool {
+	if err.Error() == orderEntity.ErrOutOfStock.Error() {
+		return true
+	}
+
+	var carrier core.StatusCodeCarrier
+	if errors.As(err, &carrier) {
+		return carrier.StatusCode() >= 500
+	}
+
+	return true
+}
+
+// RunScheduler places the orders of the due subscriptions one after another, with nothing due it waits
+// interval before it looks again. It returns once ctx is done.
+func (uc *subscriptionUsecase) RunScheduler(ctx context.Context, interval time.Duration) {
+	for {
+		ran, err := uc.RunNextSubscription(ctx)
+		if err != nil {
+			log.Println("run subscription error:", err)
+		}
+		if ran && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"order_service/internal/core"
+	"order_service/pkg"
+	"order_service/services/tax/entity"
+	taxUc "order_service/services/tax/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaxService interface {
+	GetRules(*fiber.Ctx) error
+	SetRule(*fiber.Ctx) error
+	DeleteRule(*fiber.Ctx) error
+}
+
+type service struct {
+	usecase taxUc.TaxUsecase
+}
+
+func NewService(uc taxUc.TaxUsecase) TaxService {
+	return &service{
+		usecase: uc,
+	}
+}
+
+// Get Tax Rules godoc
+// @summary Get Tax Rules
+// @description Get the tax rules of every region and tax class
+// @tags taxes
+// @produce json
+// @security BearerAuth
+// @success 200 {array} entity.TaxRule
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /taxes/ [get]
+func (srv *service) GetRules(c *fiber.Ctx) error {
+	rules, err := srv.usecase.GetRules(c.Context())
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(rules))
+}
+
+// Set Tax Rule godoc
+// @summary Set Tax Rule
+// @description Create or replace the tax rule of a region and tax class, only admin can do this action
+// @tags taxes
+// @accept application/json
+// @produce json
+// @security BearerAuth
+// @param payload body entity.TaxRuleRequest true "Tax rule request body"
+// @success 200 {object} entity.TaxRule
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /taxes/ [put]
+func (srv *service) SetRule(c *fiber.Ctx) error {
+	var data entity.TaxRuleRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidTaxRate.Error()).WithDebug(err.Error()))
+	}
+
+	if err := data.Validate(); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	rule, err := srv.usecase.SetRule(ctx, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(rule))
+}
+
+// Delete Tax Rule godoc
+// @summary Delete Tax Rule
+// @description Stop taxing a tax class in a region, only admin can do this action
+// @tags taxes
+// @security BearerAuth
+// @param ruleID path int true "Tax rule's ID"
+// @success 200
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /taxes/:ruleID [delete]
+func (srv *service) DeleteRule(c *fiber.Ctx) error {
+	ruleId, err := c.ParamsInt("ruleID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	err = srv.usecase.DeleteRule(ctx, ruleId)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+}
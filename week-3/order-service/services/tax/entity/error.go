@@ -0,0 +1,14 @@
+package entity
+
+import "errors"
+
+var (
+	ErrInvalidRegion     = errors.New("region must be a country code, optionally followed by a subdivision like US-CA")
+	ErrInvalidTaxRate    = errors.New("tax rate must be between 0 and 1")
+	ErrMissingTaxName    = errors.New("tax rule must have a name")
+	ErrTaxRuleNotFound   = errors.New("tax rule cannot be found")
+	ErrCannotGetRules    = errors.New("tax rules cannot be get")
+	ErrCannotUpdateRule  = errors.New("tax rule cannot be update")
+	ErrCannotDeleteRule  = errors.New("tax rule cannot be delete")
+	ErrCannotResolveRule = errors.New("tax rules of the region cannot be resolved")
+)
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"order_service/internal/core"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultTaxClass is the class of products that were not given one.
+const DefaultTaxClass = "standard"
+
+var regionPattern = regexp.MustCompile(`^[A-Z]{2}(-[A-Z0-9]{1,3})?$`)
+
+// TaxRule taxes products of TaxClass sold in Region at Rate, e.g. 0.1 for 10%. Inclusive rules mean
+// catalog prices already contain the tax, Rounding tells how the tax of every order line is rounded.
+type TaxRule struct {
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt *time.Time        `json:"updated_at"`
+	Region    string            `json:"region"`
+	TaxClass  string            `json:"tax_class"`
+	Name      string            `json:"name"`
+	Rounding  core.RoundingMode `json:"rounding"`
+	Id        int               `json:"id"`
+	Rate      core.Rate         `json:"rate" swaggertype:"number"`
+	Inclusive bool              `json:"inclusive"`
+}
+
+// TaxRules are the rules of one region keyed by tax class.
+type TaxRules map[string]TaxRule
+
+// NormalizeRegion upper cases an ISO 3166 country code, optionally followed by a subdivision such as US-CA.
+func NormalizeRegion(region string) (string, error) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if !regionPattern.MatchString(region) {
+		return "", ErrInvalidRegion
+	}
+
+	return region, nil
+}
+
+func NormalizeTaxClass(class string) string {
+	class = strings.ToLower(strings.TrimSpace(class))
+	if class == "" {
+		return DefaultTaxClass
+	}
+
+	return class
+}
+
+func (rule TaxRule) GetId() int {
+	return rule.Id
+}
+
+func (rule TaxRule) GetName() string {
+	return rule.Name
+}
+
+func (rule TaxRule) GetRate() core.Rate {
+	return rule.Rate
+}
+
+func (rule TaxRule) IsInclusive() bool {
+	return rule.Inclusive
+}
+
+// Compute returns the tax of an order line worth amount.
+func (rule TaxRule) Compute(amount core.Money) core.Money {
+	return amount.Tax(rule.Rate, rule.Inclusive, rule.Rounding)
+}
+
+// For returns the rule of the tax class, products of a class without rule are not taxed.
+func (rules TaxRules) For(class string) (TaxRule, bool) {
+	rule, ok := rules[NormalizeTaxClass(class)]
+
+	return rule, ok
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"order_service/internal/core"
+	"strings"
+	"time"
+)
+
+type TaxRuleRequest struct {
+	Region    string            `json:"region"`
+	TaxClass  string            `json:"tax_class"`
+	Name      string            `json:"name"`
+	Rounding  core.RoundingMode `json:"rounding"`
+	Rate      core.Rate         `json:"rate" swaggertype:"number"`
+	Inclusive bool              `json:"inclusive"`
+}
+
+func (data TaxRuleRequest) Validate() error {
+	if _, err := NormalizeRegion(data.Region); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(data.Name) == "" {
+		return ErrMissingTaxName
+	}
+
+	// a rate of 1 would be a 100% tax
+	if data.Rate.IsNegative() || data.Rate > core.RateOne {
+		return ErrInvalidTaxRate
+	}
+
+	if data.Rounding != "" && !data.Rounding.IsValid() {
+		return core.ErrInvalidRoundingMode
+	}
+
+	return nil
+}
+
+func (data TaxRuleRequest) ToTaxRule() TaxRule {
+	region, _ := NormalizeRegion(data.Region)
+
+	rounding := data.Rounding
+	if rounding == "" {
+		rounding = core.RoundHalfUp
+	}
+
+	return TaxRule{
+		Region:    region,
+		TaxClass:  NormalizeTaxClass(data.TaxClass),
+		Name:      strings.TrimSpace(data.Name),
+		Rate:      data.Rate,
+		Inclusive: data.Inclusive,
+		Rounding:  rounding,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/services/tax/entity"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TaxRepository interface {
+	GetRules(ctx context.Context) (*[]entity.TaxRule, error)
+	GetRegionRules(ctx context.Context, region string) (*[]entity.TaxRule, error)
+	UpsertRule(ctx context.Context, rule *entity.TaxRule) error
+	DeleteRule(ctx context.Context, ruleId int) error
+}
+
+const (
+	QUERY_GET_TAX_RULES        = "SELECT id, region, tax_class, name, rate, inclusive, rounding, created_at, updated_at FROM tax_rules ORDER BY region, tax_class"
+	QUERY_GET_REGION_TAX_RULES = "SELECT id, region, tax_class, name, rate, inclusive, rounding, created_at, updated_at FROM tax_rules WHERE region = $1 ORDER BY tax_class"
+	QUERY_UPSERT_TAX_RULE      = "INSERT INTO tax_rules (region, tax_class, name, rate, inclusive, rounding, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (region, tax_class) DO UPDATE SET name = EXCLUDED.name, rate = EXCLUDED.rate, inclusive = EXCLUDED.inclusive, rounding = EXCLUDED.rounding, updated_at = EXCLUDED.created_at RETURNING id"
+	QUERY_DELETE_TAX_RULE      = "DELETE FROM tax_rules WHERE id = $1"
+)
+
+type postgresRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTaxRepo(db *pgxpool.Pool) TaxRepository {
+	return &postgresRepo{
+		db,
+	}
+}
+
+func (repo *postgresRepo) GetRules(ctx context.Context) (*[]entity.TaxRule, error) {
+	return repo.getRules(ctx, QUERY_GET_TAX_RULES)
+}
+
+func (repo *postgresRepo) GetRegionRules(ctx context.Context, region string) (*[]entity.TaxRule, error) {
+	return repo.getRules(ctx, QUERY_GET_REGION_TAX_RULES, region)
+}
+
+func (repo *postgresRepo) getRules(ctx context.Context, query string, args ...any) (*[]entity.TaxRule, error) {
+	rows, err := repo.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.TaxRule, error) {
+		var rule entity.TaxRule
+
+		err := row.Scan(&rule.Id, &rule.Region, &rule.TaxClass, &rule.Name, &rule.Rate, &rule.Inclusive, &rule.Rounding, &rule.CreatedAt, &rule.UpdatedAt)
+		if err != nil {
+			return entity.TaxRule{}, err
+		}
+
+		return rule, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rules, nil
+}
+
+// UpsertRule creates the rule of a region and tax class, or replaces the existing one.
+func (repo *postgresRepo) UpsertRule(ctx context.Context, rule *entity.TaxRule) error {
+	var id int
+
+	err := repo.db.QueryRow(ctx, QUERY_UPSERT_TAX_RULE, rule.Region, rule.TaxClass, rule.Name, rule.Rate, rule.Inclusive, rule.Rounding, rule.CreatedAt).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	rule.Id = id
+
+	return nil
+}
+
+func (repo *postgresRepo) DeleteRule(ctx context.Context, ruleId int) error {
+	tag, err := repo.db.Exec(ctx, QUERY_DELETE_TAX_RULE, ruleId)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return core.ErrRecordNotFound
+	}
+
+	return nil
+}
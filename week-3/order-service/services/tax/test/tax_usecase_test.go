@@ -0,0 +1,228 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"order_service/internal/core"
+	"order_service/services/tax/entity"
+	"order_service/services/tax/test/mock"
+	"order_service/services/tax/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type TaxUsecaseTestSuite struct {
+	suite.Suite
+	mockRepo  *mock.MockTaxRepository
+	usecase   usecase.TaxUsecase
+	vatRate   core.Rate
+	adminCtx  context.Context
+	memberCtx context.Context
+}
+
+func (suite *TaxUsecaseTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+
+	suite.mockRepo = mock.NewMockTaxRepository(ctrl)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, "DE")
+	suite.vatRate, _ = core.ParseRate("0.19")
+	suite.adminCtx = requesterContext(1, 1)
+	suite.memberCtx = requesterContext(2, 0)
+}
+
+func (suite *TaxUsecaseTestSuite) TestResolveRules() {
+	tests := []struct {
+		name       string
+		region     string
+		callRepo   bool
+		repoRegion string
+		repoErr    error
+		want       string
+		wantClass  string
+		wantErr    error
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "Requested region wins",
+			region:     "us-ca",
+			callRepo:   true,
+			repoRegion: "US-CA",
+			want:       "US-CA",
+			wantClass:  entity.DefaultTaxClass,
+			assertion:  assert.NoError,
+		},
+		{
+			name:       "Default region is used when none is requested",
+			callRepo:   true,
+			repoRegion: "DE",
+			want:       "DE",
+			wantClass:  entity.DefaultTaxClass,
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Malformed region",
+			region:    "Germany",
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrInvalidRegion.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:       "Unknown error",
+			region:     "FR",
+			callRepo:   true,
+			repoRegion: "FR",
+			repoErr:    errors.New("this is an error"),
+			wantErr:    core.ErrInternalServerError.WithError(entity.ErrCannotResolveRule.Error()).WithDebug("this is an error"),
+			assertion:  assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				rules := []entity.TaxRule{{Id: 1, Region: tt.repoRegion, TaxClass: entity.DefaultTaxClass, Name: "VAT", Rate: suite.vatRate}}
+				if tt.repoErr != nil {
+					suite.mockRepo.EXPECT().GetRegionRules(gomock.Any(), tt.repoRegion).Return(nil, tt.repoErr)
+				} else {
+					suite.mockRepo.EXPECT().GetRegionRules(gomock.Any(), tt.repoRegion).Return(&rules, nil)
+				}
+			}
+
+			region, rules, err := suite.usecase.ResolveRules(context.Background(), tt.region)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			suite.Equal(tt.want, region, "region should be resolved correctly")
+			if tt.wantClass != "" {
+				_, ok := rules.For(tt.wantClass)
+				suite.True(ok, "rules should be keyed by tax class")
+			}
+		})
+	}
+}
+
+func (suite *TaxUsecaseTestSuite) TestResolveRulesWithoutDefault() {
+	uc := usecase.NewUsecase(suite.mockRepo, "")
+
+	region, rules, err := uc.ResolveRules(context.Background(), "")
+
+	suite.NoError(err)
+	suite.Equal("", region, "orders without region should stay untaxed")
+	suite.Empty(rules, "orders without region should have no rules")
+}
+
+func (suite *TaxUsecaseTestSuite) TestSetRule() {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Admin sets a rule",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Member cannot set a rule",
+			ctx:       suite.memberCtx,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrCannotUpdateRule.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Unknown error",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotUpdateRule.Error()).WithDebug("this is an error"),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().UpsertRule(gomock.Any(), gomock.Any()).Return(tt.repoErr)
+			}
+
+			rule, err := suite.usecase.SetRule(tt.ctx, &entity.TaxRuleRequest{Region: "de", Name: " VAT ", Rate: suite.vatRate})
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			if tt.wantErr == nil {
+				suite.Equal("DE", rule.Region, "region should be normalized")
+				suite.Equal(entity.DefaultTaxClass, rule.TaxClass, "tax class should default to standard")
+				suite.Equal(core.RoundHalfUp, rule.Rounding, "rounding should default to half up")
+			}
+		})
+	}
+}
+
+func (suite *TaxUsecaseTestSuite) TestDeleteRule() {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		callRepo  bool
+		repoErr   error
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Admin deletes a rule",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Member cannot delete a rule",
+			ctx:       suite.memberCtx,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrCannotDeleteRule.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Rule not found",
+			ctx:       suite.adminCtx,
+			callRepo:  true,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrTaxRuleNotFound.Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().DeleteRule(gomock.Any(), 1).Return(tt.repoErr)
+			}
+
+			err := suite.usecase.DeleteRule(tt.ctx, 1)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+		})
+	}
+}
+
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
+
+func TestTaxUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TaxUsecaseTestSuite))
+}
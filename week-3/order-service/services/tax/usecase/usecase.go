@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"order_service/internal/core"
+	"order_service/services/tax/entity"
+	taxRepo "order_service/services/tax/repository/postgres"
+)
+
+type TaxUsecase interface {
+	GetRules(ctx context.Context) (*[]entity.TaxRule, error)
+	SetRule(ctx context.Context, data *entity.TaxRuleRequest) (*entity.TaxRule, error)
+	DeleteRule(ctx context.Context, ruleId int) error
+	ResolveRules(ctx context.Context, region string) (string, entity.TaxRules, error)
+}
+
+type taxUsecase struct {
+	repo          taxRepo.TaxRepository
+	defaultRegion string
+}
+
+// NewUsecase builds the tax usecase, orders that do not say where they are shipped are taxed as defaultRegion.
+// An empty defaultRegion leaves those orders untaxed.
+func NewUsecase(repo taxRepo.TaxRepository, defaultRegion string) TaxUsecase {
+	return &taxUsecase{
+		repo,
+		defaultRegion,
+	}
+}
+
+func isAdmin(ctx context.Context) (bool, error) {
+	requester := core.GetRequester(ctx)
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return false, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return uid.GetRole() == 1, nil
+}
+
+func (uc *taxUsecase) GetRules(ctx context.Context) (*[]entity.TaxRule, error) {
+	rules, err := uc.repo.GetRules(ctx)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetRules.Error()).WithDebug(err.Error())
+	}
+
+	return rules, nil
+}
+
+func (uc *taxUsecase) SetRule(ctx context.Context, data *entity.TaxRuleRequest) (*entity.TaxRule, error) {
+	admin, err := isAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return nil, core.ErrBadRequest.WithError(entity.ErrCannotUpdateRule.Error())
+	}
+
+	rule := data.ToTaxRule()
+
+	err = uc.repo.UpsertRule(ctx, &rule)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotUpdateRule.Error()).WithDebug(err.Error())
+	}
+
+	return &rule, nil
+}
+
+func (uc *taxUsecase) DeleteRule(ctx context.Context, ruleId int) error {
+	admin, err := isAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return core.ErrBadRequest.WithError(entity.ErrCannotDeleteRule.Error())
+	}
+
+	err = uc.repo.DeleteRule(ctx, ruleId)
+	if err != nil {
+		if err == core.ErrRecordNotFound {
+			return core.ErrNotFound.WithError(entity.ErrTaxRuleNotFound.Error())
+		}
+		return core.ErrInternalServerError.WithError(entity.ErrCannotDeleteRule.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// ResolveRules returns the region an order is taxed in, the requested one or else the default one, with its rules.
+func (uc *taxUsecase) ResolveRules(ctx context.Context, region string) (string, entity.TaxRules, error) {
+	if region == "" {
+		region = uc.defaultRegion
+	}
+	if region == "" {
+		return "", entity.TaxRules{}, nil
+	}
+
+	region, err := entity.NormalizeRegion(region)
+	if err != nil {
+		return "", nil, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	rules, err := uc.repo.GetRegionRules(ctx, region)
+	if err != nil {
+		return "", nil, core.ErrInternalServerError.WithError(entity.ErrCannotResolveRule.Error()).WithDebug(err.Error())
+	}
+
+	regionRules := make(entity.TaxRules, len(*rules))
+	for _, rule := range *rules {
+		regionRules[rule.TaxClass] = rule
+	}
+
+	return region, regionRules, nil
+}
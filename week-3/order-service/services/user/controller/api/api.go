@@ -14,6 +14,11 @@ type UserService interface {
 	GetUser(*fiber.Ctx) error
 	GetUserProfile(*fiber.Ctx) error
 	AddUserBalance(*fiber.Ctx) error
+	GetBalanceHistory(*fiber.Ctx) error
+	GetTopUps(*fiber.Ctx) error
+	ApproveTopUp(*fiber.Ctx) error
+	RejectTopUp(*fiber.Ctx) error
+	AdjustBalance(*fiber.Ctx) error
 }
 
 type service struct {
@@ -99,12 +104,16 @@ func (srv *service) GetUser(c *fiber.Ctx) error {
 
 // Add User Balance godoc
 // @summary Add User Balance
-// @description Add user balance
+// @description Top the wallet balance of the current user up within the limits per top-up and per day. A top-up below the approval threshold is credited right away, a larger one is accepted as pending until an admin reviews it
 // @tags users
 // @security BearerAuth
+// @param Idempotency-Key header string false "Key to safely retry the request without applying it twice"
 // @param payload body entity.UserRequest true "User request body"
-// @success 200
+// @success 200 {object} entity.TopUp
+// @success 202 {object} entity.TopUp
+// @failure 400 {object} core.DefaultError
 // @failure 404 {object} core.DefaultError
+// @failure 422 {object} core.DefaultError
 // @failure 500 {object} core.DefaultError
 // @router /users/balance [post]
 func (srv *service) AddUserBalance(c *fiber.Ctx) error {
@@ -125,10 +134,205 @@ func (srv *service) AddUserBalance(c *fiber.Ctx) error {
 		return pkg.WriteResponse(c, err)
 	}
 
-	err = srv.usecase.AddUserBalance(c.Context(), requesterId, data.Balance)
+	topUp, err := srv.usecase.AddUserBalance(c.Context(), requesterId, data.Balance)
 	if err != nil {
 		return pkg.WriteResponse(c, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(core.ResponseData(true))
+	if topUp.GetStatusSafe() == entity.TopUpStatusPending {
+		return c.Status(fiber.StatusAccepted).JSON(core.ResponseData(topUp))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(topUp))
+}
+
+// Get Balance History godoc
+// @summary Get Balance History
+// @description Page through the wallet ledger entries of the current user newest first: top-ups, order charges, refunds and adjustments, with the balance each one left behind
+// @tags users
+// @security BearerAuth
+// @param limit query int false "Page size, 20 by default and 100 at most"
+// @param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @success 200 {array} entity.LedgerEntry
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /users/balance/history [get]
+func (srv *service) GetBalanceHistory(c *fiber.Ctx) error {
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+
+	uid, err := core.DecomposeUID(requester.GetSubject())
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrInternalServerError.WithDebug(err.Error()))
+	}
+
+	var data entity.BalanceHistoryRequest
+
+	if err := c.QueryParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidCursor.Error()).WithDebug(err.Error()))
+	}
+
+	filter, err := data.ToFilter(int(uid.GetLocalID()))
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	entries, nextCursor, err := srv.usecase.GetBalanceHistory(c.Context(), filter)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseDataWithPaging(entries, core.Paging{
+		NextCursor: nextCursor,
+		Limit:      filter.Limit,
+	}))
+}
+
+// Get Top-Ups godoc
+// @summary Get Top-Ups
+// @description Get the top-ups newest first, admin see the top-ups of every user and customers only their own
+// @tags users
+// @security BearerAuth
+// @param status query string false "Only the top-ups in this status: pending, approved or rejected"
+// @success 200 {array} entity.TopUp
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /users/balance/top-ups [get]
+func (srv *service) GetTopUps(c *fiber.Ctx) error {
+	var data entity.TopUpListRequest
+
+	if err := c.QueryParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidTopUpStatus.Error()).WithDebug(err.Error()))
+	}
+
+	filter, err := data.ToFilter()
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	topUps, err := srv.usecase.GetTopUps(ctx, filter)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(topUps))
+}
+
+// Approve Top-Up godoc
+// @summary Approve Top-Up
+// @description Approve a pending top-up and credit the wallet of its user, only admin can review top-ups and never their own
+// @tags users
+// @accept application/json
+// @security BearerAuth
+// @param topUpID path int true "Top-up's ID"
+// @param payload body entity.ReviewRequest false "Review request body"
+// @success 200 {object} entity.TopUp
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /users/balance/top-ups/:topUpID/approve [post]
+func (srv *service) ApproveTopUp(c *fiber.Ctx) error {
+	return srv.reviewTopUp(c, true)
+}
+
+// Reject Top-Up godoc
+// @summary Reject Top-Up
+// @description Reject a pending top-up, the wallet is left untouched. Only admin can review top-ups and never their own
+// @tags users
+// @accept application/json
+// @security BearerAuth
+// @param topUpID path int true "Top-up's ID"
+// @param payload body entity.ReviewRequest false "Review request body"
+// @success 200 {object} entity.TopUp
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /users/balance/top-ups/:topUpID/reject [post]
+func (srv *service) RejectTopUp(c *fiber.Ctx) error {
+	return srv.reviewTopUp(c, false)
+}
+
+func (srv *service) reviewTopUp(c *fiber.Ctx, approve bool) error {
+	topUpId, err := c.ParamsInt("topUpID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+	}
+
+	var data entity.ReviewRequest
+
+	// the note is optional, so is the body
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&data); err != nil {
+			return pkg.WriteResponse(c, core.ErrBadRequest.WithDebug(err.Error()))
+		}
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	topUp, err := srv.usecase.ReviewTopUp(ctx, topUpId, approve, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(core.ResponseData(topUp))
+}
+
+// Adjust Balance godoc
+// @summary Adjust Balance
+// @description Credit or debit the wallet of a user for a reason code, the adjustment is recorded in the balance history along with the admin who made it. A debit never takes the balance below zero. Only admin can adjust balances
+// @tags users
+// @accept application/json
+// @security BearerAuth
+// @param Idempotency-Key header string false "Key to safely retry the request without applying it twice"
+// @param userID path int true "User's ID"
+// @param payload body entity.AdjustmentRequest true "Adjustment request body"
+// @success 201 {object} entity.LedgerEntry
+// @failure 400 {object} core.DefaultError
+// @failure 401 {object} core.DefaultError
+// @failure 404 {object} core.DefaultError
+// @failure 409 {object} core.DefaultError
+// @failure 500 {object} core.DefaultError
+// @router /users/:userID/balance/adjustments [post]
+func (srv *service) AdjustBalance(c *fiber.Ctx) error {
+	userId, err := c.ParamsInt("userID")
+	if err != nil {
+		return pkg.WriteResponse(c, core.ErrNotFound)
+	}
+
+	var data entity.AdjustmentRequest
+
+	if err := c.BodyParser(&data); err != nil {
+		return pkg.WriteResponse(c, core.ErrBadRequest.WithError(entity.ErrInvalidAdjustment.Error()).WithDebug(err.Error()))
+	}
+
+	requester, ok := c.Locals(core.KeyRequester).(core.Requester)
+	if !ok {
+		return pkg.WriteResponse(c, core.ErrUnauthorized)
+	}
+	ctx := core.ContextWithRequester(c.Context(), requester)
+
+	entry, err := srv.usecase.AdjustBalance(ctx, userId, &data)
+	if err != nil {
+		return pkg.WriteResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(core.ResponseData(entry))
 }
@@ -3,6 +3,29 @@ package entity
 import "errors"
 
 var (
-	ErrCannotGetUser    = errors.New("can not get user info")
-	ErrCannotAddBalance = errors.New("can not add balance")
+	ErrCannotGetUser           = errors.New("can not get user info")
+	ErrCannotAddBalance        = errors.New("can not add balance")
+	ErrInvalidAmount           = errors.New("amount must be positive")
+	ErrInsufficientBalance     = errors.New("user's balance is insufficient")
+	ErrUnbalancedTransaction   = errors.New("ledger transaction does not balance")
+	ErrDuplicateTransaction    = errors.New("ledger transaction was already posted")
+	ErrNothingCharged          = errors.New("nothing was charged for the refunded reference")
+	ErrCannotPostTransaction   = errors.New("can not post ledger transaction")
+	ErrCannotGetBalanceHistory = errors.New("can not get balance history")
+	ErrCannotReconcile         = errors.New("can not reconcile balances")
+	ErrInvalidCursor           = errors.New("cursor is not valid")
+	ErrInvalidMemory           = errors.New("invalid memory")
+	ErrTopUpLimitExceeded      = errors.New("top-up is above the limit per top-up")
+	ErrDailyTopUpLimitExceeded = errors.New("top-ups are above the daily limit")
+	ErrTopUpNotFound           = errors.New("top-up not found")
+	ErrTopUpAlreadyReviewed    = errors.New("top-up was already reviewed")
+	ErrInvalidTopUpStatus      = errors.New("top-up status is not valid")
+	ErrCannotGetTopUps         = errors.New("can not get top-ups")
+	ErrCannotReviewTopUp       = errors.New("can not review top-up")
+	ErrInvalidDirection        = errors.New("direction must be credit or debit")
+	ErrInvalidReasonCode       = errors.New("reason code is not valid")
+	ErrNoteTooLong             = errors.New("note is too long")
+	ErrCannotAdjustBalance     = errors.New("can not adjust balance")
+	ErrCannotReviewOwnTopUp    = errors.New("top-up can not be reviewed by its own user")
+	ErrInvalidAdjustment       = errors.New("adjustment request is not valid")
 )
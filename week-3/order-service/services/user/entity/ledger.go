@@ -0,0 +1,184 @@
+package entity
+
+import (
+	"order_service/internal/core"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LedgerKind string
+
+const (
+	LedgerKindTopUp       LedgerKind = "top_up"
+	LedgerKindOrderCharge LedgerKind = "order_charge"
+	LedgerKindRefund      LedgerKind = "refund"
+	LedgerKindAdjustment  LedgerKind = "adjustment"
+)
+
+// LedgerAccount is the account a ledger entry is posted to. Wallet is the balance of the entry's user,
+// the other accounts belong to the shop: external is money coming from or going out of the shop and
+// orders holds what wallets paid for orders.
+type LedgerAccount string
+
+const (
+	LedgerAccountWallet   LedgerAccount = "wallet"
+	LedgerAccountExternal LedgerAccount = "external"
+	LedgerAccountOrders   LedgerAccount = "orders"
+)
+
+// counterAccounts is the shop account every kind of transaction moves the wallet's money against.
+var counterAccounts = map[LedgerKind]LedgerAccount{
+	LedgerKindTopUp:       LedgerAccountExternal,
+	LedgerKindOrderCharge: LedgerAccountOrders,
+	LedgerKindRefund:      LedgerAccountOrders,
+	LedgerKindAdjustment:  LedgerAccountExternal,
+}
+
+// LedgerEntry is one leg of a ledger transaction, Amount is credited to Account when positive and debited
+// from it when negative. BalanceAfter is only kept on wallet entries. ActorId is who made the transaction,
+// it is empty for the ones the payments make.
+type LedgerEntry struct {
+	CreatedAt     time.Time     `json:"created_at"`
+	BalanceAfter  *core.Money   `json:"balance_after,omitempty" swaggertype:"number"`
+	ActorId       *int          `json:"actor_id,omitempty"`
+	TransactionId string        `json:"transaction_id"`
+	Account       LedgerAccount `json:"account"`
+	Kind          LedgerKind    `json:"kind"`
+	Reference     string        `json:"reference"`
+	ReasonCode    ReasonCode    `json:"reason_code,omitempty"`
+	Note          string        `json:"note,omitempty"`
+	Id            int64         `json:"id"`
+	UserId        int           `json:"user_id"`
+	Amount        core.Money    `json:"amount" swaggertype:"number"`
+}
+
+// LedgerTransaction moves money between the wallet of UserId and a shop account, its entries always
+// sum up to zero. Reference is what the transaction is for, e.g. a payment, a transaction is posted
+// once per kind and reference.
+type LedgerTransaction struct {
+	CreatedAt  time.Time
+	ActorId    *int
+	Id         string
+	Kind       LedgerKind
+	Reference  string
+	ReasonCode ReasonCode
+	Note       string
+	UserId     int
+	Entries    []LedgerEntry
+}
+
+// NewLedgerTransaction builds the two entries moving amount into the wallet of userId, a negative amount
+// takes it out of the wallet instead.
+func NewLedgerTransaction(userId int, kind LedgerKind, amount core.Money, reference string, now time.Time) LedgerTransaction {
+	transaction := LedgerTransaction{
+		Id:        uuid.NewString(),
+		Kind:      kind,
+		Reference: reference,
+		UserId:    userId,
+		CreatedAt: now,
+	}
+
+	transaction.Entries = []LedgerEntry{
+		transaction.newEntry(LedgerAccountWallet, amount),
+		transaction.newEntry(counterAccounts[kind], amount.Mul(-1)),
+	}
+
+	return transaction
+}
+
+func (transaction *LedgerTransaction) newEntry(account LedgerAccount, amount core.Money) LedgerEntry {
+	return LedgerEntry{
+		TransactionId: transaction.Id,
+		Account:       account,
+		Kind:          transaction.Kind,
+		Reference:     transaction.Reference,
+		UserId:        transaction.UserId,
+		Amount:        amount,
+		CreatedAt:     transaction.CreatedAt,
+	}
+}
+
+// Attribute records who made the transaction and why on every entry of it.
+func (transaction *LedgerTransaction) Attribute(actorId int, reasonCode ReasonCode, note string) {
+	if transaction == nil {
+		return
+	}
+
+	transaction.ActorId = &actorId
+	transaction.ReasonCode = reasonCode
+	transaction.Note = note
+
+	for idx := range transaction.Entries {
+		transaction.Entries[idx].ActorId = &actorId
+		transaction.Entries[idx].ReasonCode = reasonCode
+		transaction.Entries[idx].Note = note
+	}
+}
+
+// Validate checks the double-entry rules, every entry moves money and the entries sum up to zero.
+func (transaction *LedgerTransaction) Validate() error {
+	if transaction == nil {
+		return ErrInvalidMemory
+	}
+
+	if _, ok := counterAccounts[transaction.Kind]; !ok || len(transaction.Entries) < 2 {
+		return ErrUnbalancedTransaction
+	}
+
+	// an adjustment is only ever made for a reason
+	if transaction.Kind == LedgerKindAdjustment && !transaction.ReasonCode.IsValid() {
+		return ErrInvalidReasonCode
+	}
+
+	sum := core.NewMoney(0)
+	for _, entry := range transaction.Entries {
+		if entry.Amount == 0 {
+			return ErrUnbalancedTransaction
+		}
+		sum = sum.Add(entry.Amount)
+	}
+
+	if sum != 0 {
+		return ErrUnbalancedTransaction
+	}
+
+	return nil
+}
+
+// GetWalletAmountSafe returns what the transaction adds to the wallet, negative when it takes money out.
+func (transaction *LedgerTransaction) GetWalletAmountSafe() core.Money {
+	amount := core.NewMoney(0)
+
+	if transaction != nil {
+		for _, entry := range transaction.Entries {
+			if entry.Account == LedgerAccountWallet {
+				amount = amount.Add(entry.Amount)
+			}
+		}
+	}
+
+	return amount
+}
+
+// SetWalletBalance records the wallet balance the transaction leaves behind on its wallet entries.
+func (transaction *LedgerTransaction) SetWalletBalance(balance core.Money) {
+	if transaction == nil {
+		return
+	}
+
+	for idx := range transaction.Entries {
+		if transaction.Entries[idx].Account == LedgerAccountWallet {
+			balanceAfter := balance
+			transaction.Entries[idx].BalanceAfter = &balanceAfter
+		}
+	}
+}
+
+func (transaction *LedgerTransaction) GetUserIdSafe() int {
+	if transaction != nil {
+		return transaction.UserId
+	}
+
+	return 0
+}
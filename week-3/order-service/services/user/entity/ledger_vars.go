@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"order_service/internal/core"
+)
+
+const (
+	DefaultLedgerPageSize = 20
+	MaxLedgerPageSize     = 100
+)
+
+// BalanceHistoryRequest holds the raw query parameters of the balance history.
+type BalanceHistoryRequest struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+}
+
+// LedgerFilter is the validated form of BalanceHistoryRequest, wallet entries of UserId newest first.
+type LedgerFilter struct {
+	Cursor *LedgerCursor
+	UserId int
+	Limit  int
+}
+
+// LedgerCursor points right after the last entry of a page, entries are only ever appended so their id
+// alone keeps the pages stable.
+type LedgerCursor struct {
+	Id int64 `json:"id"`
+}
+
+// BalanceMismatch is a user whose cached balance is not the sum of its wallet entries.
+type BalanceMismatch struct {
+	UserId  int        `json:"user_id"`
+	Balance core.Money `json:"balance" swaggertype:"number"`
+	Ledger  core.Money `json:"ledger" swaggertype:"number"`
+}
+
+// Reconciliation is the outcome of checking the cached balances against the ledger.
+type Reconciliation struct {
+	Mismatches             []BalanceMismatch `json:"mismatches"`
+	UnbalancedTransactions []string          `json:"unbalanced_transactions"`
+	Users                  int               `json:"users"`
+}
+
+func (reconciliation *Reconciliation) IsBalanced() bool {
+	return reconciliation != nil && len(reconciliation.Mismatches) == 0 && len(reconciliation.UnbalancedTransactions) == 0
+}
+
+func (cursor LedgerCursor) Encode() string {
+	bytes, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+func DecodeLedgerCursor(s string) (*LedgerCursor, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor LedgerCursor
+
+	if err := json.Unmarshal(bytes, &cursor); err != nil || cursor.Id <= 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func (data BalanceHistoryRequest) ToFilter(userId int) (*LedgerFilter, error) {
+	filter := LedgerFilter{
+		UserId: userId,
+		Limit:  data.Limit,
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLedgerPageSize
+	}
+	if filter.Limit > MaxLedgerPageSize {
+		filter.Limit = MaxLedgerPageSize
+	}
+
+	if data.Cursor != "" {
+		cursor, err := DecodeLedgerCursor(data.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.Cursor = cursor
+	}
+
+	return &filter, nil
+}
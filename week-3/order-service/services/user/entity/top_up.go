@@ -0,0 +1,173 @@
+package entity
+
+import (
+	"fmt"
+	"order_service/internal/core"
+	"time"
+)
+
+type TopUpStatus string
+
+const (
+	TopUpStatusPending  TopUpStatus = "pending"
+	TopUpStatusApproved TopUpStatus = "approved"
+	TopUpStatusRejected TopUpStatus = "rejected"
+)
+
+func (status TopUpStatus) IsValid() bool {
+	return status == TopUpStatusPending || status == TopUpStatusApproved || status == TopUpStatusRejected
+}
+
+// ReasonCode is why an admin adjusted a balance.
+type ReasonCode string
+
+const (
+	ReasonCodeCorrection ReasonCode = "correction"
+	ReasonCodeGoodwill   ReasonCode = "goodwill"
+	ReasonCodePromotion  ReasonCode = "promotion"
+	ReasonCodeChargeback ReasonCode = "chargeback"
+	ReasonCodeFraud      ReasonCode = "fraud"
+)
+
+func (code ReasonCode) IsValid() bool {
+	switch code {
+	case ReasonCodeCorrection, ReasonCodeGoodwill, ReasonCodePromotion, ReasonCodeChargeback, ReasonCodeFraud:
+		return true
+	}
+
+	return false
+}
+
+// TopUpLimits bounds the top-ups users make themselves, a zero limit is not enforced. A top-up of at least
+// ApprovalThreshold waits for an admin to approve it before the wallet is credited.
+type TopUpLimits struct {
+	PerTransaction    core.Money
+	PerDay            core.Money
+	ApprovalThreshold core.Money
+}
+
+// Check tells what becomes of a top-up of amount when the user already topped dailyTotal up today,
+// pending top-ups included.
+func (limits TopUpLimits) Check(amount, dailyTotal core.Money) (TopUpStatus, error) {
+	if !amount.IsPositive() {
+		return "", ErrInvalidAmount
+	}
+
+	if limits.PerTransaction.IsPositive() && amount > limits.PerTransaction {
+		return "", ErrTopUpLimitExceeded
+	}
+
+	if limits.PerDay.IsPositive() && dailyTotal.Add(amount) > limits.PerDay {
+		return "", ErrDailyTopUpLimitExceeded
+	}
+
+	if limits.ApprovalThreshold.IsPositive() && amount >= limits.ApprovalThreshold {
+		return TopUpStatusPending, nil
+	}
+
+	return TopUpStatusApproved, nil
+}
+
+// TopUp is a user's request to add money to its wallet. The wallet is credited once the top-up is
+// approved, right away for small amounts and by an admin for large ones.
+type TopUp struct {
+	CreatedAt  time.Time   `json:"created_at"`
+	ReviewedAt *time.Time  `json:"reviewed_at"`
+	ReviewerId *int        `json:"reviewer_id,omitempty"`
+	Status     TopUpStatus `json:"status"`
+	ReviewNote string      `json:"review_note,omitempty"`
+	Id         int         `json:"id"`
+	UserId     int         `json:"user_id"`
+	Amount     core.Money  `json:"amount" swaggertype:"number"`
+}
+
+func NewTopUp(userId int, amount core.Money, now time.Time) TopUp {
+	return TopUp{
+		UserId:    userId,
+		Amount:    amount,
+		Status:    TopUpStatusPending,
+		CreatedAt: now,
+	}
+}
+
+func (topUp *TopUp) SetId(id int) {
+	if topUp != nil {
+		topUp.Id = id
+	}
+}
+
+func (topUp *TopUp) SetStatus(status TopUpStatus) {
+	if topUp != nil {
+		topUp.Status = status
+	}
+}
+
+// Review approves or rejects a pending top-up on behalf of reviewerId, nobody reviews its own top-up.
+func (topUp *TopUp) Review(approve bool, reviewerId int, note string, now time.Time) error {
+	if topUp == nil {
+		return ErrInvalidMemory
+	}
+
+	if topUp.Status != TopUpStatusPending {
+		return ErrTopUpAlreadyReviewed
+	}
+
+	if topUp.UserId == reviewerId {
+		return ErrCannotReviewOwnTopUp
+	}
+
+	topUp.Status = TopUpStatusRejected
+	if approve {
+		topUp.Status = TopUpStatusApproved
+	}
+	topUp.ReviewerId = &reviewerId
+	topUp.ReviewNote = note
+	topUp.ReviewedAt = &now
+
+	return nil
+}
+
+// ToTransaction builds the credit of an approved top-up, made by its reviewer or else by the user itself.
+func (topUp *TopUp) ToTransaction(now time.Time) LedgerTransaction {
+	transaction := NewLedgerTransaction(topUp.UserId, LedgerKindTopUp, topUp.Amount, fmt.Sprintf("top_up_%d", topUp.Id), now)
+
+	actorId := topUp.UserId
+	if topUp.ReviewerId != nil {
+		actorId = *topUp.ReviewerId
+	}
+	transaction.Attribute(actorId, "", topUp.ReviewNote)
+
+	return transaction
+}
+
+func (topUp *TopUp) GetIdSafe() int {
+	if topUp != nil {
+		return topUp.Id
+	}
+
+	return 0
+}
+
+func (topUp *TopUp) GetUserIdSafe() int {
+	if topUp != nil {
+		return topUp.UserId
+	}
+
+	return 0
+}
+
+func (topUp *TopUp) GetStatusSafe() TopUpStatus {
+	if topUp != nil {
+		return topUp.Status
+	}
+
+	return ""
+}
+
+func (topUp *TopUp) GetAmountSafe() core.Money {
+	if topUp != nil {
+		return topUp.Amount
+	}
+
+	return core.NewMoney(0)
+}
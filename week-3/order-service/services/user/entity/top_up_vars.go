@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"order_service/internal/core"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	AdjustmentCredit = "credit"
+	AdjustmentDebit  = "debit"
+)
+
+// AdjustmentRequest is an admin's credit or debit of a user's wallet, Amount is always positive and
+// Direction tells which way it goes.
+type AdjustmentRequest struct {
+	Direction  string     `json:"direction" example:"credit"`
+	ReasonCode ReasonCode `json:"reason_code" example:"goodwill"`
+	Note       string     `json:"note" example:"Late delivery of order 42"`
+	Amount     core.Money `json:"amount" swaggertype:"number" example:"10.5"`
+}
+
+// ReviewRequest is an admin's note on approving or rejecting a top-up.
+type ReviewRequest struct {
+	Note string `json:"note" example:"Confirmed with the bank transfer"`
+}
+
+// TopUpListRequest holds the raw query parameters of the top-up listing, admin see every user's top-ups
+// and customers only their own.
+type TopUpListRequest struct {
+	Status TopUpStatus `query:"status"`
+}
+
+// TopUpFilter is the validated form of TopUpListRequest, a zero UserId means top-ups of every user.
+type TopUpFilter struct {
+	Status TopUpStatus
+	UserId int
+}
+
+func (data *AdjustmentRequest) Validate() error {
+	data.Direction = strings.ToLower(strings.TrimSpace(data.Direction))
+	data.Note = strings.TrimSpace(data.Note)
+
+	if data.Direction != AdjustmentCredit && data.Direction != AdjustmentDebit {
+		return ErrInvalidDirection
+	}
+
+	if !data.Amount.IsPositive() {
+		return ErrInvalidAmount
+	}
+
+	if !data.ReasonCode.IsValid() {
+		return ErrInvalidReasonCode
+	}
+
+	if utf8.RuneCountInString(data.Note) > 200 {
+		return ErrNoteTooLong
+	}
+
+	return nil
+}
+
+// GetWalletAmount returns what the adjustment adds to the wallet, negative for a debit.
+func (data AdjustmentRequest) GetWalletAmount() core.Money {
+	if data.Direction == AdjustmentDebit {
+		return data.Amount.Mul(-1)
+	}
+
+	return data.Amount
+}
+
+func (data *ReviewRequest) Validate() error {
+	data.Note = strings.TrimSpace(data.Note)
+
+	if utf8.RuneCountInString(data.Note) > 200 {
+		return ErrNoteTooLong
+	}
+
+	return nil
+}
+
+func (data TopUpListRequest) ToFilter() (*TopUpFilter, error) {
+	if data.Status != "" && !data.Status.IsValid() {
+		return nil, ErrInvalidTopUpStatus
+	}
+
+	return &TopUpFilter{Status: data.Status}, nil
+}
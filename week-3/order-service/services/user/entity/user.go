@@ -1,13 +1,17 @@
 package entity
 
-import "time"
+import (
+	"order_service/internal/core"
+	"time"
+)
 
 type User struct {
 	Id        int        `json:"id"`
 	Username  string     `json:"username"`
 	Password  string     `json:"-"` // sensitive field, should not send to user
 	Role      int        `json:"role"`
-	Balance   float32    `json:"balance"`
+	Balance   core.Money `json:"balance" swaggertype:"number"`
+	Currency  string     `json:"currency"` // preferred currency, empty for the base currency
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
 }
@@ -17,13 +21,13 @@ func NewUser(id int, username, password string) User {
 		Id:        id,
 		Username:  username,
 		Password:  password,
-		Balance:   0.0,
+		Balance:   0,
 		CreatedAt: time.Now(),
 		UpdatedAt: &time.Time{},
 	}
 }
 
-func (user *User) SetBalance(b float32) {
+func (user *User) SetBalance(b core.Money) {
 	if user != nil {
 		user.Balance = b
 	}
@@ -33,6 +37,10 @@ func (user User) GetId() int {
 	return user.Id
 }
 
-func (user User) GetBalance() float32 {
+func (user User) GetBalance() core.Money {
 	return user.Balance
 }
+
+func (user User) GetCurrency() string {
+	return user.Currency
+}
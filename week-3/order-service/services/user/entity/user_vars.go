@@ -1,5 +1,7 @@
 package entity
 
+import "order_service/internal/core"
+
 type UserRequest struct {
-	Balance float32 `json:"balance"`
+	Balance core.Money `json:"balance" swaggertype:"number"`
 }
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"order_service/internal/core"
+	"order_service/pkg"
 	"order_service/services/user/entity"
 
 	"github.com/jackc/pgx/v5"
@@ -12,13 +13,31 @@ import (
 type UserRepository interface {
 	GetUsers(ctx context.Context) (*[]entity.User, error)
 	GetUserById(ctx context.Context, userId int) (*entity.User, error)
-	AddUserBalanceById(ctx context.Context, userId int, balance float32) error
+	PostTransaction(ctx context.Context, transaction *entity.LedgerTransaction, callbackFn func(user *entity.User, transaction *entity.LedgerTransaction) error) error
+	GetLedgerEntries(ctx context.Context, filter *entity.LedgerFilter) (*[]entity.LedgerEntry, error)
+	Reconcile(ctx context.Context) (*entity.Reconciliation, error)
+	CreateTopUp(ctx context.Context, topUp *entity.TopUp, callbackFn func(topUp *entity.TopUp, dailyTotal core.Money) error) error
+	GetTopUps(ctx context.Context, filter *entity.TopUpFilter) (*[]entity.TopUp, error)
+	ReviewTopUp(ctx context.Context, topUp *entity.TopUp, callbackFn func(topUp *entity.TopUp) error) error
 }
 
 const (
-	QUERY_GET_USER_BY_ID            = "SELECT * FROM users WHERE id = $1"
-	QUERY_GET_USERS                 = "SELECT * FROM users"
-	QUERY_UPDATE_USER_BALANCE_BY_ID = "UPDATE users SET balance = COALESCE(balance, 0.0) + $2 WHERE id = $1"
+	QUERY_GET_USER_BY_ID              = "SELECT id, username, password, balance, COALESCE(currency, ''), created_at, updated_at FROM users WHERE id = $1"
+	QUERY_GET_USERS                   = "SELECT id, username, password, balance, COALESCE(currency, ''), created_at, updated_at FROM users"
+	QUERY_GET_USER_LOCK               = "SELECT id, username, password, COALESCE(balance, 0), COALESCE(currency, ''), created_at, updated_at FROM users WHERE id = $1 FOR UPDATE"
+	QUERY_GET_USER_ID_LOCK            = "SELECT id FROM users WHERE id = $1 FOR UPDATE"
+	QUERY_UPDATE_USER_BALANCE         = "UPDATE users SET balance = $2, updated_at = $3 WHERE id = $1"
+	QUERY_GET_TRANSACTION_POSTED      = "SELECT EXISTS (SELECT 1 FROM ledger_entries WHERE kind = $1 AND reference = $2 AND reference <> '')"
+	QUERY_CREATE_LEDGER_ENTRY         = "INSERT INTO ledger_entries (transaction_id, account, user_id, kind, amount, balance_after, reference, actor_id, reason_code, note, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) ON CONFLICT (kind, reference, account) WHERE reference <> '' DO NOTHING RETURNING id"
+	QUERY_GET_LEDGER_ENTRIES          = "SELECT id, transaction_id::text, account, user_id, kind, amount, balance_after, reference, actor_id, reason_code, note, created_at FROM ledger_entries WHERE user_id = $1 AND account = 'wallet' AND ($2::bigint IS NULL OR id < $2) ORDER BY id DESC LIMIT $3"
+	QUERY_COUNT_USERS                 = "SELECT COUNT(*) FROM users"
+	QUERY_GET_BALANCE_MISMATCHES      = "SELECT u.id, COALESCE(u.balance, 0), COALESCE(SUM(l.amount), 0) FROM users u LEFT JOIN ledger_entries l ON l.user_id = u.id AND l.account = 'wallet' GROUP BY u.id HAVING COALESCE(u.balance, 0) <> COALESCE(SUM(l.amount), 0) ORDER BY u.id"
+	QUERY_GET_UNBALANCED_TRANSACTIONS = "SELECT transaction_id::text FROM ledger_entries GROUP BY transaction_id HAVING SUM(amount) <> 0 OR COUNT(*) < 2 ORDER BY transaction_id"
+	QUERY_GET_DAILY_TOP_UP_TOTAL      = "SELECT COALESCE(SUM(amount), 0) FROM top_ups WHERE user_id = $1 AND status <> 'rejected' AND created_at >= date_trunc('day', $2::timestamp)"
+	QUERY_CREATE_TOP_UP               = "INSERT INTO top_ups (user_id, amount, status, created_at) VALUES ($1, $2, $3, $4) RETURNING id"
+	QUERY_GET_TOP_UPS                 = "SELECT id, user_id, amount, status, reviewer_id, review_note, created_at, reviewed_at FROM top_ups WHERE ($1 = 0 OR user_id = $1) AND ($2 = '' OR status::text = $2) ORDER BY id DESC"
+	QUERY_GET_TOP_UP_LOCK             = "SELECT id, user_id, amount, status, reviewer_id, review_note, created_at, reviewed_at FROM top_ups WHERE id = $1 FOR UPDATE"
+	QUERY_UPDATE_TOP_UP               = "UPDATE top_ups SET status = $2, reviewer_id = $3, review_note = $4, reviewed_at = $5 WHERE id = $1"
 )
 
 type postgresRepo struct {
@@ -38,7 +57,7 @@ func (repo *postgresRepo) GetUsers(ctx context.Context) (*[]entity.User, error)
 	datas, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.User, error) {
 		var data entity.User
 
-		err := row.Scan(&data.Id, &data.Username, &data.Password, &data.Balance, &data.CreatedAt, &data.UpdatedAt)
+		err := row.Scan(&data.Id, &data.Username, &data.Password, &data.Balance, &data.Currency, &data.CreatedAt, &data.UpdatedAt)
 		if err != nil {
 			return entity.User{}, err
 		}
@@ -55,7 +74,7 @@ func (repo *postgresRepo) GetUsers(ctx context.Context) (*[]entity.User, error)
 func (repo *postgresRepo) GetUserById(ctx context.Context, userId int) (*entity.User, error) {
 	var data entity.User
 
-	err := repo.db.QueryRow(ctx, QUERY_GET_USER_BY_ID, userId).Scan(&data.Id, &data.Username, &data.Password, &data.Balance, &data.CreatedAt, &data.UpdatedAt)
+	err := repo.db.QueryRow(ctx, QUERY_GET_USER_BY_ID, userId).Scan(&data.Id, &data.Username, &data.Password, &data.Balance, &data.Currency, &data.CreatedAt, &data.UpdatedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, core.ErrRecordNotFound
@@ -66,11 +85,246 @@ func (repo *postgresRepo) GetUserById(ctx context.Context, userId int) (*entity.
 	return &data, nil
 }
 
-func (repo *postgresRepo) AddUserBalanceById(ctx context.Context, userId int, balance float32) error {
-	_, err := repo.db.Exec(ctx, QUERY_UPDATE_USER_BALANCE_BY_ID, userId, balance)
+// PostTransaction appends the entries of transaction to the ledger and caches the new wallet balance on
+// its locked user. callbackFn sees the balance before the transaction. entity.ErrDuplicateTransaction
+// means a transaction of the same kind and reference was already posted, entity.ErrNothingCharged that
+// a refund has no order charge of its reference to give back.
+func (repo *postgresRepo) PostTransaction(ctx context.Context, transaction *entity.LedgerTransaction, callbackFn func(user *entity.User, transaction *entity.LedgerTransaction) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		return postTransaction(ctx, tx, transaction, callbackFn)
+	})
+}
+
+// postTransaction posts transaction inside tx, a nil callbackFn posts it without any further check.
+func postTransaction(ctx context.Context, tx pgx.Tx, transaction *entity.LedgerTransaction, callbackFn func(user *entity.User, transaction *entity.LedgerTransaction) error) error {
+	var user entity.User
+
+	err := tx.QueryRow(ctx, QUERY_GET_USER_LOCK, transaction.GetUserIdSafe()).Scan(&user.Id, &user.Username, &user.Password, &user.Balance, &user.Currency, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return core.ErrRecordNotFound
+		}
+		return err
+	}
+
+	var posted bool
+
+	err = tx.QueryRow(ctx, QUERY_GET_TRANSACTION_POSTED, transaction.Kind, transaction.Reference).Scan(&posted)
+	if err != nil {
+		return err
+	}
+	if posted {
+		return entity.ErrDuplicateTransaction
+	}
+
+	// the charge of a reference is posted under the lock of the same user, so a refund either sees it or
+	// the charge comes after and is given back by whoever finds it
+	if transaction.Kind == entity.LedgerKindRefund {
+		err = tx.QueryRow(ctx, QUERY_GET_TRANSACTION_POSTED, entity.LedgerKindOrderCharge, transaction.Reference).Scan(&posted)
+		if err != nil {
+			return err
+		}
+		if !posted {
+			return entity.ErrNothingCharged
+		}
+	}
+
+	// run business logic
+	if callbackFn != nil {
+		err = callbackFn(&user, transaction)
+		if err != nil {
+			return err
+		}
+	}
+
+	balance := user.GetBalance().Add(transaction.GetWalletAmountSafe())
+	transaction.SetWalletBalance(balance)
+
+	for idx := range transaction.Entries {
+		entry := &transaction.Entries[idx]
+
+		err = tx.QueryRow(ctx, QUERY_CREATE_LEDGER_ENTRY, entry.TransactionId, entry.Account, entry.UserId, entry.Kind, entry.Amount, entry.BalanceAfter, entry.Reference, entry.ActorId, entry.ReasonCode, entry.Note, entry.CreatedAt).Scan(&entry.Id)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return entity.ErrDuplicateTransaction
+			}
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, QUERY_UPDATE_USER_BALANCE, user.Id, balance, transaction.CreatedAt)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (repo *postgresRepo) GetLedgerEntries(ctx context.Context, filter *entity.LedgerFilter) (*[]entity.LedgerEntry, error) {
+	var cursorId *int64
+	if filter.Cursor != nil {
+		cursorId = &filter.Cursor.Id
+	}
+
+	rows, _ := repo.db.Query(ctx, QUERY_GET_LEDGER_ENTRIES, filter.UserId, cursorId, filter.Limit)
+	defer rows.Close()
+
+	datas, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.LedgerEntry, error) {
+		var data entity.LedgerEntry
+
+		err := row.Scan(&data.Id, &data.TransactionId, &data.Account, &data.UserId, &data.Kind, &data.Amount, &data.BalanceAfter, &data.Reference, &data.ActorId, &data.ReasonCode, &data.Note, &data.CreatedAt)
+		if err != nil {
+			return entity.LedgerEntry{}, err
+		}
+
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &datas, nil
+}
+
+// Reconcile compares the cached balance of every user with the sum of its wallet entries, and looks for
+// ledger transactions whose entries do not sum up to zero.
+func (repo *postgresRepo) Reconcile(ctx context.Context) (*entity.Reconciliation, error) {
+	var reconciliation entity.Reconciliation
+
+	err := repo.db.QueryRow(ctx, QUERY_COUNT_USERS).Scan(&reconciliation.Users)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _ := repo.db.Query(ctx, QUERY_GET_BALANCE_MISMATCHES)
+	reconciliation.Mismatches, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.BalanceMismatch, error) {
+		var data entity.BalanceMismatch
+
+		err := row.Scan(&data.UserId, &data.Balance, &data.Ledger)
+		if err != nil {
+			return entity.BalanceMismatch{}, err
+		}
+
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _ = repo.db.Query(ctx, QUERY_GET_UNBALANCED_TRANSACTIONS)
+	reconciliation.UnbalancedTransactions, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	return &reconciliation, nil
+}
+
+func scanTopUp(row pgx.Row, topUp *entity.TopUp) error {
+	err := row.Scan(&topUp.Id, &topUp.UserId, &topUp.Amount, &topUp.Status, &topUp.ReviewerId, &topUp.ReviewNote, &topUp.CreatedAt, &topUp.ReviewedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return core.ErrRecordNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CreateTopUp stores a top-up of the locked user, callbackFn sees what the user already topped up that day
+// and decides whether the top-up is approved. An approved top-up credits the wallet right away.
+func (repo *postgresRepo) CreateTopUp(ctx context.Context, topUp *entity.TopUp, callbackFn func(topUp *entity.TopUp, dailyTotal core.Money) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		var userId int
+
+		// top-ups of the same user are counted one at a time
+		err := tx.QueryRow(ctx, QUERY_GET_USER_ID_LOCK, topUp.GetUserIdSafe()).Scan(&userId)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return core.ErrRecordNotFound
+			}
+			return err
+		}
+
+		var dailyTotal core.Money
+
+		err = tx.QueryRow(ctx, QUERY_GET_DAILY_TOP_UP_TOTAL, userId, topUp.CreatedAt).Scan(&dailyTotal)
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		err = callbackFn(topUp, dailyTotal)
+		if err != nil {
+			return err
+		}
+
+		var newTopUpId int
+
+		err = tx.QueryRow(ctx, QUERY_CREATE_TOP_UP, topUp.UserId, topUp.Amount, topUp.Status, topUp.CreatedAt).Scan(&newTopUpId)
+		if err != nil {
+			return err
+		}
+		topUp.SetId(newTopUpId)
+
+		if topUp.Status != entity.TopUpStatusApproved {
+			return nil
+		}
+
+		transaction := topUp.ToTransaction(topUp.CreatedAt)
+
+		return postTransaction(ctx, tx, &transaction, nil)
+	})
+}
+
+func (repo *postgresRepo) GetTopUps(ctx context.Context, filter *entity.TopUpFilter) (*[]entity.TopUp, error) {
+	rows, _ := repo.db.Query(ctx, QUERY_GET_TOP_UPS, filter.UserId, filter.Status)
+	defer rows.Close()
+
+	datas, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entity.TopUp, error) {
+		var data entity.TopUp
+
+		err := scanTopUp(row, &data)
+		if err != nil {
+			return entity.TopUp{}, err
+		}
+
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &datas, nil
+}
+
+// ReviewTopUp loads the locked top-up of topUp.Id into topUp and lets callbackFn review it, an approved
+// top-up credits the wallet in the same transaction.
+func (repo *postgresRepo) ReviewTopUp(ctx context.Context, topUp *entity.TopUp, callbackFn func(topUp *entity.TopUp) error) error {
+	return pkg.RunInTransaction(ctx, repo.db, func(tx pgx.Tx) error {
+		err := scanTopUp(tx.QueryRow(ctx, QUERY_GET_TOP_UP_LOCK, topUp.GetIdSafe()), topUp)
+		if err != nil {
+			return err
+		}
+
+		// run business logic
+		err = callbackFn(topUp)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, QUERY_UPDATE_TOP_UP, topUp.Id, topUp.Status, topUp.ReviewerId, topUp.ReviewNote, topUp.ReviewedAt)
+		if err != nil {
+			return err
+		}
+
+		if topUp.Status != entity.TopUpStatusApproved {
+			return nil
+		}
+
+		transaction := topUp.ToTransaction(*topUp.ReviewedAt)
+
+		return postTransaction(ctx, tx, &transaction, nil)
+	})
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"order_service/internal/core"
+	"order_service/services/user/entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLedgerTransaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        entity.LedgerKind
+		amount      core.Money
+		wantCounter entity.LedgerAccount
+	}{
+		{name: "Top-up comes from outside", kind: entity.LedgerKindTopUp, amount: core.NewMoney(1000), wantCounter: entity.LedgerAccountExternal},
+		{name: "Order charge goes to the orders", kind: entity.LedgerKindOrderCharge, amount: core.NewMoney(-1000), wantCounter: entity.LedgerAccountOrders},
+		{name: "Refund comes back from the orders", kind: entity.LedgerKindRefund, amount: core.NewMoney(1000), wantCounter: entity.LedgerAccountOrders},
+		{name: "Adjustment against the outside", kind: entity.LedgerKindAdjustment, amount: core.NewMoney(-250), wantCounter: entity.LedgerAccountExternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := entity.NewLedgerTransaction(7, tt.kind, tt.amount, "ref", time.Now())
+			if tt.kind == entity.LedgerKindAdjustment {
+				transaction.Attribute(1, entity.ReasonCodeCorrection, "")
+			}
+
+			require.NoError(t, transaction.Validate())
+			require.Len(t, transaction.Entries, 2)
+			assert.Equal(t, entity.LedgerAccountWallet, transaction.Entries[0].Account)
+			assert.Equal(t, tt.wantCounter, transaction.Entries[1].Account, "counter account should match the kind")
+			assert.Equal(t, tt.amount, transaction.GetWalletAmountSafe())
+
+			for _, entry := range transaction.Entries {
+				assert.Equal(t, transaction.Id, entry.TransactionId, "entries should share the transaction")
+				assert.Equal(t, 7, entry.UserId)
+				assert.Equal(t, "ref", entry.Reference)
+			}
+		})
+	}
+}
+
+func TestLedgerTransactionValidate(t *testing.T) {
+	zero := entity.NewLedgerTransaction(7, entity.LedgerKindTopUp, core.NewMoney(0), "", time.Now())
+	assert.ErrorIs(t, zero.Validate(), entity.ErrUnbalancedTransaction, "empty entries should be rejected")
+
+	unknown := entity.NewLedgerTransaction(7, "gift", core.NewMoney(100), "", time.Now())
+	assert.ErrorIs(t, unknown.Validate(), entity.ErrUnbalancedTransaction, "unknown kind should be rejected")
+
+	oneLeg := entity.NewLedgerTransaction(7, entity.LedgerKindTopUp, core.NewMoney(100), "", time.Now())
+	oneLeg.Entries = oneLeg.Entries[:1]
+	assert.ErrorIs(t, oneLeg.Validate(), entity.ErrUnbalancedTransaction, "single entry should be rejected")
+
+	unexplained := entity.NewLedgerTransaction(7, entity.LedgerKindAdjustment, core.NewMoney(100), "", time.Now())
+	assert.ErrorIs(t, unexplained.Validate(), entity.ErrInvalidReasonCode, "adjustment without a reason should be rejected")
+
+	var missing *entity.LedgerTransaction
+	assert.ErrorIs(t, missing.Validate(), entity.ErrInvalidMemory)
+}
+
+func TestLedgerTransactionAttribute(t *testing.T) {
+	transaction := entity.NewLedgerTransaction(7, entity.LedgerKindAdjustment, core.NewMoney(-100), "", time.Now())
+
+	transaction.Attribute(1, entity.ReasonCodeChargeback, "Disputed order 42")
+
+	require.NoError(t, transaction.Validate())
+	for _, entry := range transaction.Entries {
+		require.NotNil(t, entry.ActorId)
+		assert.Equal(t, 1, *entry.ActorId, "entries should be attributed to the actor")
+		assert.Equal(t, entity.ReasonCodeChargeback, entry.ReasonCode)
+		assert.Equal(t, "Disputed order 42", entry.Note)
+	}
+}
+
+func TestLedgerTransactionSetWalletBalance(t *testing.T) {
+	transaction := entity.NewLedgerTransaction(7, entity.LedgerKindTopUp, core.NewMoney(100), "", time.Now())
+
+	transaction.SetWalletBalance(core.NewMoney(600))
+
+	require.NotNil(t, transaction.Entries[0].BalanceAfter)
+	assert.Equal(t, core.NewMoney(600), *transaction.Entries[0].BalanceAfter, "wallet entry should keep the balance")
+	assert.Nil(t, transaction.Entries[1].BalanceAfter, "shop entry should not keep a balance")
+}
+
+func TestBalanceHistoryRequestToFilter(t *testing.T) {
+	filter, err := entity.BalanceHistoryRequest{}.ToFilter(7)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.LedgerFilter{UserId: 7, Limit: entity.DefaultLedgerPageSize}, filter)
+
+	filter, err = entity.BalanceHistoryRequest{Limit: 1000, Cursor: entity.LedgerCursor{Id: 42}.Encode()}.ToFilter(7)
+	require.NoError(t, err)
+	assert.Equal(t, entity.MaxLedgerPageSize, filter.Limit, "limit should be capped")
+	assert.Equal(t, &entity.LedgerCursor{Id: 42}, filter.Cursor, "cursor should be decoded")
+
+	_, err = entity.BalanceHistoryRequest{Cursor: "not-a-cursor"}.ToFilter(7)
+	assert.ErrorIs(t, err, entity.ErrInvalidCursor)
+
+	_, err = entity.BalanceHistoryRequest{Cursor: entity.LedgerCursor{Id: 0}.Encode()}.ToFilter(7)
+	assert.ErrorIs(t, err, entity.ErrInvalidCursor)
+}
@@ -1,16 +1,17 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: store.go
+// Source: repository/postgres/store.go
 //
 // Generated by this command:
 //
-//	mockgen -source store.go
+//	mockgen -source repository/postgres/store.go -destination test/mock/store.go -package mock
 //
 
-// Package mock_postgres is a generated GoMock package.
+// Package mock is a generated GoMock package.
 package mock
 
 import (
 	context "context"
+	core "order_service/internal/core"
 	entity "order_service/services/user/entity"
 	reflect "reflect"
 
@@ -40,18 +41,48 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 	return m.recorder
 }
 
-// AddUserBalanceById mocks base method.
-func (m *MockUserRepository) AddUserBalanceById(ctx context.Context, userId int, balance float32) error {
+// CreateTopUp mocks base method.
+func (m *MockUserRepository) CreateTopUp(ctx context.Context, topUp *entity.TopUp, callbackFn func(*entity.TopUp, core.Money) error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddUserBalanceById", ctx, userId, balance)
+	ret := m.ctrl.Call(m, "CreateTopUp", ctx, topUp, callbackFn)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddUserBalanceById indicates an expected call of AddUserBalanceById.
-func (mr *MockUserRepositoryMockRecorder) AddUserBalanceById(ctx, userId, balance any) *gomock.Call {
+// CreateTopUp indicates an expected call of CreateTopUp.
+func (mr *MockUserRepositoryMockRecorder) CreateTopUp(ctx, topUp, callbackFn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserBalanceById", reflect.TypeOf((*MockUserRepository)(nil).AddUserBalanceById), ctx, userId, balance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTopUp", reflect.TypeOf((*MockUserRepository)(nil).CreateTopUp), ctx, topUp, callbackFn)
+}
+
+// GetLedgerEntries mocks base method.
+func (m *MockUserRepository) GetLedgerEntries(ctx context.Context, filter *entity.LedgerFilter) (*[]entity.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerEntries", ctx, filter)
+	ret0, _ := ret[0].(*[]entity.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerEntries indicates an expected call of GetLedgerEntries.
+func (mr *MockUserRepositoryMockRecorder) GetLedgerEntries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerEntries", reflect.TypeOf((*MockUserRepository)(nil).GetLedgerEntries), ctx, filter)
+}
+
+// GetTopUps mocks base method.
+func (m *MockUserRepository) GetTopUps(ctx context.Context, filter *entity.TopUpFilter) (*[]entity.TopUp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopUps", ctx, filter)
+	ret0, _ := ret[0].(*[]entity.TopUp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopUps indicates an expected call of GetTopUps.
+func (mr *MockUserRepositoryMockRecorder) GetTopUps(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopUps", reflect.TypeOf((*MockUserRepository)(nil).GetTopUps), ctx, filter)
 }
 
 // GetUserById mocks base method.
@@ -83,3 +114,46 @@ func (mr *MockUserRepositoryMockRecorder) GetUsers(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserRepository)(nil).GetUsers), ctx)
 }
+
+// PostTransaction mocks base method.
+func (m *MockUserRepository) PostTransaction(ctx context.Context, transaction *entity.LedgerTransaction, callbackFn func(*entity.User, *entity.LedgerTransaction) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostTransaction", ctx, transaction, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostTransaction indicates an expected call of PostTransaction.
+func (mr *MockUserRepositoryMockRecorder) PostTransaction(ctx, transaction, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostTransaction", reflect.TypeOf((*MockUserRepository)(nil).PostTransaction), ctx, transaction, callbackFn)
+}
+
+// Reconcile mocks base method.
+func (m *MockUserRepository) Reconcile(ctx context.Context) (*entity.Reconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reconcile", ctx)
+	ret0, _ := ret[0].(*entity.Reconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reconcile indicates an expected call of Reconcile.
+func (mr *MockUserRepositoryMockRecorder) Reconcile(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reconcile", reflect.TypeOf((*MockUserRepository)(nil).Reconcile), ctx)
+}
+
+// ReviewTopUp mocks base method.
+func (m *MockUserRepository) ReviewTopUp(ctx context.Context, topUp *entity.TopUp, callbackFn func(*entity.TopUp) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewTopUp", ctx, topUp, callbackFn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReviewTopUp indicates an expected call of ReviewTopUp.
+func (mr *MockUserRepositoryMockRecorder) ReviewTopUp(ctx, topUp, callbackFn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewTopUp", reflect.TypeOf((*MockUserRepository)(nil).ReviewTopUp), ctx, topUp, callbackFn)
+}
@@ -0,0 +1,139 @@
+package test
+
+import (
+	"order_service/internal/core"
+	"order_service/services/user/entity"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopUpLimitsCheck(t *testing.T) {
+	limits := entity.TopUpLimits{
+		PerTransaction:    core.NewMoney(1000),
+		PerDay:            core.NewMoney(2000),
+		ApprovalThreshold: core.NewMoney(500),
+	}
+
+	tests := []struct {
+		name       string
+		limits     entity.TopUpLimits
+		amount     core.Money
+		dailyTotal core.Money
+		want       entity.TopUpStatus
+		wantErr    error
+	}{
+		{name: "Small top-up is approved", limits: limits, amount: core.NewMoney(499), want: entity.TopUpStatusApproved},
+		{name: "Top-up at the threshold waits for approval", limits: limits, amount: core.NewMoney(500), want: entity.TopUpStatusPending},
+		{name: "Top-up up to the limit per top-up", limits: limits, amount: core.NewMoney(1000), want: entity.TopUpStatusPending},
+		{name: "Top-up above the limit per top-up", limits: limits, amount: core.NewMoney(1001), wantErr: entity.ErrTopUpLimitExceeded},
+		{name: "Top-up up to the daily limit", limits: limits, amount: core.NewMoney(400), dailyTotal: core.NewMoney(1600), want: entity.TopUpStatusApproved},
+		{name: "Top-up above the daily limit", limits: limits, amount: core.NewMoney(400), dailyTotal: core.NewMoney(1601), wantErr: entity.ErrDailyTopUpLimitExceeded},
+		{name: "Top-up must be positive", limits: limits, amount: core.NewMoney(0), wantErr: entity.ErrInvalidAmount},
+		{name: "Zero limits are not enforced", amount: core.NewMoney(1000000), dailyTotal: core.NewMoney(1000000), want: entity.TopUpStatusApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.limits.Check(tt.amount, tt.dailyTotal)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTopUpReview(t *testing.T) {
+	now := time.Now()
+
+	topUp := entity.NewTopUp(7, core.NewMoney(800), now)
+	assert.Equal(t, entity.TopUpStatusPending, topUp.Status, "top-up should start pending")
+
+	assert.ErrorIs(t, topUp.Review(true, 7, "", now), entity.ErrCannotReviewOwnTopUp, "user should not review its own top-up")
+
+	require.NoError(t, topUp.Review(false, 1, "Unknown transfer", now))
+	assert.Equal(t, entity.TopUpStatusRejected, topUp.Status)
+	require.NotNil(t, topUp.ReviewerId)
+	assert.Equal(t, 1, *topUp.ReviewerId)
+	assert.Equal(t, "Unknown transfer", topUp.ReviewNote)
+	assert.NotNil(t, topUp.ReviewedAt)
+
+	assert.ErrorIs(t, topUp.Review(true, 1, "", now), entity.ErrTopUpAlreadyReviewed, "reviewed top-up should not be reviewed again")
+
+	var missing *entity.TopUp
+	assert.ErrorIs(t, missing.Review(true, 1, "", now), entity.ErrInvalidMemory)
+}
+
+func TestTopUpToTransaction(t *testing.T) {
+	topUp := entity.NewTopUp(7, core.NewMoney(800), time.Now())
+	topUp.SetId(3)
+
+	transaction := topUp.ToTransaction(time.Now())
+	require.NoError(t, transaction.Validate())
+	assert.Equal(t, entity.LedgerKindTopUp, transaction.Kind)
+	assert.Equal(t, "top_up_3", transaction.Reference, "top-up should be posted once")
+	assert.Equal(t, core.NewMoney(800), transaction.GetWalletAmountSafe())
+	require.NotNil(t, transaction.ActorId)
+	assert.Equal(t, 7, *transaction.ActorId, "unreviewed top-up should be made by its user")
+
+	require.NoError(t, topUp.Review(true, 1, "Bank transfer received", time.Now()))
+
+	transaction = topUp.ToTransaction(time.Now())
+	require.NotNil(t, transaction.ActorId)
+	assert.Equal(t, 1, *transaction.ActorId, "reviewed top-up should be made by its reviewer")
+	assert.Equal(t, "Bank transfer received", transaction.Note)
+}
+
+func TestAdjustmentRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    entity.AdjustmentRequest
+		want    core.Money
+		wantErr error
+	}{
+		{
+			name: "Credit",
+			data: entity.AdjustmentRequest{Direction: " Credit ", ReasonCode: entity.ReasonCodeGoodwill, Amount: core.NewMoney(100)},
+			want: core.NewMoney(100),
+		},
+		{
+			name: "Debit",
+			data: entity.AdjustmentRequest{Direction: "debit", ReasonCode: entity.ReasonCodeFraud, Amount: core.NewMoney(100)},
+			want: core.NewMoney(-100),
+		},
+		{
+			name:    "Unknown direction",
+			data:    entity.AdjustmentRequest{Direction: "sideways", ReasonCode: entity.ReasonCodeGoodwill, Amount: core.NewMoney(100)},
+			wantErr: entity.ErrInvalidDirection,
+		},
+		{
+			name:    "Amount must be positive",
+			data:    entity.AdjustmentRequest{Direction: "debit", ReasonCode: entity.ReasonCodeGoodwill, Amount: core.NewMoney(-100)},
+			wantErr: entity.ErrInvalidAmount,
+		},
+		{
+			name:    "Reason code is required",
+			data:    entity.AdjustmentRequest{Direction: "credit", Amount: core.NewMoney(100)},
+			wantErr: entity.ErrInvalidReasonCode,
+		},
+		{
+			name:    "Note is too long",
+			data:    entity.AdjustmentRequest{Direction: "credit", ReasonCode: entity.ReasonCodeGoodwill, Note: strings.Repeat("a", 201), Amount: core.NewMoney(100)},
+			wantErr: entity.ErrNoteTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.data.Validate()
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if err == nil {
+				assert.Equal(t, tt.want, tt.data.GetWalletAmount())
+			}
+		})
+	}
+}
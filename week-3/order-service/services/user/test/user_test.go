@@ -1,6 +1,7 @@
 package test
 
 import (
+	"order_service/internal/core"
 	"order_service/services/user/entity"
 	"testing"
 	"time"
@@ -18,7 +19,7 @@ func (suite *UserTestSuite) SetupTest() {
 		Id:        1,
 		Username:  "partridge",
 		Password:  "130703",
-		Balance:   0.0,
+		Balance:   0,
 		CreatedAt: time.Now(),
 	}
 }
@@ -29,29 +30,29 @@ func (suite *UserTestSuite) TestNewUser() {
 	suite.Equal(1, user.Id, "Id should be set correctly")
 	suite.Equal("partridge", user.Username, "Username should be set correctly")
 	suite.Equal("130703", user.Password, "Password should be set correctly")
-	suite.Equal(float32(0.0), user.Balance, "Balance should be set correctly")
+	suite.Equal(core.NewMoney(0), user.Balance, "Balance should be set correctly")
 }
 
 func (suite *UserTestSuite) TestSetBalance() {
 	tests := []struct {
 		name    string
 		user    *entity.User
-		balance float32
-		want    float32
+		balance core.Money
+		want    core.Money
 		panic   bool
 	}{
 		{
 			name:    "Non Nil User",
 			user:    &suite.user,
-			balance: 10.0,
-			want:    10.0,
+			balance: core.NewMoney(1000),
+			want:    core.NewMoney(1000),
 			panic:   false,
 		},
 		{
 			name:    "Nil User",
 			user:    nil,
-			balance: 10.0,
-			want:    0.0,
+			balance: core.NewMoney(1000),
+			want:    0,
 			panic:   true,
 		},
 	}
@@ -7,7 +7,9 @@ import (
 	"order_service/services/user/entity"
 	"order_service/services/user/test/mock"
 	"order_service/services/user/usecase"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -27,20 +29,24 @@ func (suite *UserUsecaseTestSuite) SetupTest() {
 			Id:       1,
 			Username: "partridge",
 			Password: "130703",
-			Balance:  10.0,
+			Balance:  core.NewMoney(1000),
 		},
 		{
 			Id:       2,
 			Username: "partridge1307",
 			Password: "130703",
-			Balance:  5.0,
+			Balance:  core.NewMoney(500),
 		},
 	}
 
 	ctrl := gomock.NewController(suite.T())
 
 	suite.mockRepo = mock.NewMockUserRepository(ctrl)
-	suite.usecase = usecase.NewUsecase(suite.mockRepo)
+	suite.usecase = usecase.NewUsecase(suite.mockRepo, entity.TopUpLimits{
+		PerTransaction:    core.NewMoney(1000),
+		PerDay:            core.NewMoney(2000),
+		ApprovalThreshold: core.NewMoney(500),
+	})
 }
 
 func (suite *UserUsecaseTestSuite) TestGetUsers() {
@@ -143,29 +149,329 @@ func (suite *UserUsecaseTestSuite) TestGetUser() {
 	}
 }
 
+// expectPost makes PostTransaction run the callback against user and check the transaction it posts.
+func (suite *UserUsecaseTestSuite) expectPost(user *entity.User, repoErr error, check func(transaction *entity.LedgerTransaction)) {
+	suite.mockRepo.EXPECT().PostTransaction(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, transaction *entity.LedgerTransaction, callbackFn func(user *entity.User, transaction *entity.LedgerTransaction) error) error {
+			if repoErr != nil {
+				return repoErr
+			}
+			if err := callbackFn(user, transaction); err != nil {
+				return err
+			}
+			check(transaction)
+
+			return nil
+		})
+}
+
 func (suite *UserUsecaseTestSuite) TestAddUserBalance() {
+	tests := []struct {
+		name       string
+		balance    core.Money
+		dailyTotal core.Money
+		callRepo   bool
+		repoErr    error
+		wantStatus entity.TopUpStatus
+		want       error
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "Small top-up is credited",
+			balance:    core.NewMoney(100),
+			callRepo:   true,
+			wantStatus: entity.TopUpStatusApproved,
+			assertion:  assert.NoError,
+		},
+		{
+			name:       "Large top-up waits for approval",
+			balance:    core.NewMoney(800),
+			callRepo:   true,
+			wantStatus: entity.TopUpStatusPending,
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Top-up above the limit per top-up",
+			balance:   core.NewMoney(1001),
+			callRepo:  true,
+			want:      core.ErrUnprocessableEntity.WithError(entity.ErrTopUpLimitExceeded.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:       "Top-up above the daily limit",
+			balance:    core.NewMoney(800),
+			dailyTotal: core.NewMoney(1500),
+			callRepo:   true,
+			want:       core.ErrUnprocessableEntity.WithError(entity.ErrDailyTopUpLimitExceeded.Error()),
+			assertion:  assert.Error,
+		},
+		{
+			name:      "Top-up must be positive",
+			balance:   core.NewMoney(-1000),
+			callRepo:  false,
+			want:      core.ErrBadRequest.WithError(entity.ErrInvalidAmount.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "User does not exist",
+			balance:   core.NewMoney(100),
+			callRepo:  true,
+			repoErr:   core.ErrRecordNotFound,
+			want:      core.ErrNotFound.WithError(entity.ErrCannotGetUser.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "User return an error",
+			balance:   core.NewMoney(100),
+			callRepo:  true,
+			repoErr:   errors.New("this is an error"),
+			want:      core.ErrInternalServerError.WithError(entity.ErrCannotAddBalance.Error()).WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().CreateTopUp(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, topUp *entity.TopUp, callbackFn func(topUp *entity.TopUp, dailyTotal core.Money) error) error {
+						if tt.repoErr != nil {
+							return tt.repoErr
+						}
+
+						return callbackFn(topUp, tt.dailyTotal)
+					})
+			}
+
+			got, err := suite.usecase.AddUserBalance(context.Background(), 1, tt.balance)
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.want, "error should be return correctly")
+				return
+			}
+			suite.Equal(tt.wantStatus, got.Status, "top-up should be held against the limits")
+			suite.Equal(1, got.UserId)
+			suite.Equal(tt.balance, got.Amount)
+		})
+	}
+}
+
+func (suite *UserUsecaseTestSuite) TestGetTopUps() {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantUserId int
+	}{
+		{name: "Admin see every top-up", ctx: requesterContext(1, 1), wantUserId: 0},
+		{name: "Customer see its own top-ups", ctx: requesterContext(2, 0), wantUserId: 2},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			topUps := &[]entity.TopUp{{Id: 1, UserId: 2, Status: entity.TopUpStatusPending}}
+			suite.mockRepo.EXPECT().GetTopUps(gomock.Any(), &entity.TopUpFilter{Status: entity.TopUpStatusPending, UserId: tt.wantUserId}).Return(topUps, nil)
+
+			got, err := suite.usecase.GetTopUps(tt.ctx, &entity.TopUpFilter{Status: entity.TopUpStatusPending})
+
+			suite.NoError(err)
+			suite.Equal(topUps, got)
+		})
+	}
+
+	suite.Run("Repo return an error", func() {
+		suite.SetupTest()
+
+		suite.mockRepo.EXPECT().GetTopUps(gomock.Any(), gomock.Any()).Return(nil, errors.New("this is an error"))
+
+		_, err := suite.usecase.GetTopUps(requesterContext(1, 1), &entity.TopUpFilter{})
+
+		suite.ErrorIs(err, core.ErrInternalServerError.WithError(entity.ErrCannotGetTopUps.Error()).WithDebug(errors.New("this is an error").Error()))
+	})
+}
+
+func (suite *UserUsecaseTestSuite) TestReviewTopUp() {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		approve    bool
+		note       string
+		callRepo   bool
+		topUp      entity.TopUp
+		repoErr    error
+		wantStatus entity.TopUpStatus
+		wantErr    error
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "Admin approve a top-up",
+			ctx:        requesterContext(1, 1),
+			approve:    true,
+			note:       " Bank transfer received ",
+			callRepo:   true,
+			topUp:      entity.TopUp{Id: 3, UserId: 2, Status: entity.TopUpStatusPending, Amount: core.NewMoney(800)},
+			wantStatus: entity.TopUpStatusApproved,
+			assertion:  assert.NoError,
+		},
+		{
+			name:       "Admin reject a top-up",
+			ctx:        requesterContext(1, 1),
+			callRepo:   true,
+			topUp:      entity.TopUp{Id: 3, UserId: 2, Status: entity.TopUpStatusPending, Amount: core.NewMoney(800)},
+			wantStatus: entity.TopUpStatusRejected,
+			assertion:  assert.NoError,
+		},
+		{
+			name:      "Customer can not review",
+			ctx:       requesterContext(2, 0),
+			approve:   true,
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrCannotReviewTopUp.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Note is too long",
+			ctx:       requesterContext(1, 1),
+			note:      strings.Repeat("a", 201),
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrNoteTooLong.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Admin can not review its own top-up",
+			ctx:       requesterContext(1, 1),
+			approve:   true,
+			callRepo:  true,
+			topUp:     entity.TopUp{Id: 3, UserId: 1, Status: entity.TopUpStatusPending, Amount: core.NewMoney(800)},
+			wantErr:   core.ErrConfict.WithError(entity.ErrCannotReviewOwnTopUp.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Top-up was already reviewed",
+			ctx:       requesterContext(1, 1),
+			approve:   true,
+			callRepo:  true,
+			topUp:     entity.TopUp{Id: 3, UserId: 2, Status: entity.TopUpStatusRejected, Amount: core.NewMoney(800)},
+			wantErr:   core.ErrConfict.WithError(entity.ErrTopUpAlreadyReviewed.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Top-up does not exist",
+			ctx:       requesterContext(1, 1),
+			approve:   true,
+			callRepo:  true,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrTopUpNotFound.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo return an error",
+			ctx:       requesterContext(1, 1),
+			approve:   true,
+			callRepo:  true,
+			repoErr:   errors.New("this is an error"),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotReviewTopUp.Error()).WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			if tt.callRepo {
+				suite.mockRepo.EXPECT().ReviewTopUp(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, topUp *entity.TopUp, callbackFn func(topUp *entity.TopUp) error) error {
+						if tt.repoErr != nil {
+							return tt.repoErr
+						}
+						suite.Equal(3, topUp.Id, "requested top-up should be reviewed")
+						*topUp = tt.topUp
+
+						return callbackFn(topUp)
+					})
+			}
+
+			got, err := suite.usecase.ReviewTopUp(tt.ctx, 3, tt.approve, &entity.ReviewRequest{Note: tt.note})
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+				return
+			}
+			suite.Equal(tt.wantStatus, got.Status)
+			suite.Equal(strings.TrimSpace(tt.note), got.ReviewNote)
+			suite.Require().NotNil(got.ReviewerId)
+			suite.Equal(1, *got.ReviewerId, "top-up should be reviewed by the admin")
+		})
+	}
+}
+
+func (suite *UserUsecaseTestSuite) TestAdjustBalance() {
 	tests := []struct {
 		name      string
-		userId    int
-		balance   float32
+		ctx       context.Context
+		data      entity.AdjustmentRequest
+		callRepo  bool
 		repoErr   error
-		want      error
+		want      core.Money
+		wantErr   error
 		assertion assert.ErrorAssertionFunc
 	}{
 		{
-			name:      "User exists",
-			userId:    1,
-			balance:   10.0,
-			repoErr:   nil,
-			want:      nil,
+			name:      "Admin credit a wallet",
+			ctx:       requesterContext(9, 1),
+			data:      entity.AdjustmentRequest{Direction: "credit", ReasonCode: entity.ReasonCodeGoodwill, Note: "Late delivery", Amount: core.NewMoney(200)},
+			callRepo:  true,
+			want:      core.NewMoney(200),
 			assertion: assert.NoError,
 		},
 		{
-			name:      "User return an error",
-			userId:    1,
-			balance:   10.0,
+			name:      "Admin debit a wallet",
+			ctx:       requesterContext(9, 1),
+			data:      entity.AdjustmentRequest{Direction: "debit", ReasonCode: entity.ReasonCodeChargeback, Amount: core.NewMoney(1000)},
+			callRepo:  true,
+			want:      core.NewMoney(-1000),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Debit can not take the wallet below zero",
+			ctx:       requesterContext(9, 1),
+			data:      entity.AdjustmentRequest{Direction: "debit", ReasonCode: entity.ReasonCodeChargeback, Amount: core.NewMoney(1001)},
+			callRepo:  true,
+			wantErr:   core.ErrConfict.WithError(entity.ErrInsufficientBalance.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Customer can not adjust",
+			ctx:       requesterContext(1, 0),
+			data:      entity.AdjustmentRequest{Direction: "credit", ReasonCode: entity.ReasonCodeGoodwill, Amount: core.NewMoney(200)},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrCannotAdjustBalance.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Reason code is required",
+			ctx:       requesterContext(9, 1),
+			data:      entity.AdjustmentRequest{Direction: "credit", Amount: core.NewMoney(200)},
+			wantErr:   core.ErrBadRequest.WithError(entity.ErrInvalidReasonCode.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "User does not exist",
+			ctx:       requesterContext(9, 1),
+			data:      entity.AdjustmentRequest{Direction: "credit", ReasonCode: entity.ReasonCodeGoodwill, Amount: core.NewMoney(200)},
+			callRepo:  true,
+			repoErr:   core.ErrRecordNotFound,
+			wantErr:   core.ErrNotFound.WithError(entity.ErrCannotGetUser.Error()),
+			assertion: assert.Error,
+		},
+		{
+			name:      "Repo return an error",
+			ctx:       requesterContext(9, 1),
+			data:      entity.AdjustmentRequest{Direction: "credit", ReasonCode: entity.ReasonCodeGoodwill, Amount: core.NewMoney(200)},
+			callRepo:  true,
 			repoErr:   errors.New("this is an error"),
-			want:      core.ErrInternalServerError.WithError(entity.ErrCannotAddBalance.Error()).WithDebug(errors.New("this is an error").Error()),
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotAdjustBalance.Error()).WithDebug(errors.New("this is an error").Error()),
 			assertion: assert.Error,
 		},
 	}
@@ -174,17 +480,200 @@ func (suite *UserUsecaseTestSuite) TestAddUserBalance() {
 		suite.Run(tt.name, func() {
 			suite.SetupTest()
 
-			suite.mockRepo.EXPECT().AddUserBalanceById(gomock.Any(), tt.userId, tt.balance).Return(tt.repoErr)
+			if tt.callRepo {
+				suite.expectPost(&(*suite.users)[0], tt.repoErr, func(transaction *entity.LedgerTransaction) {
+					suite.Equal(entity.LedgerKindAdjustment, transaction.Kind, "adjustment should be posted")
+					suite.Equal(1, transaction.UserId)
+					suite.Equal(tt.data.ReasonCode, transaction.ReasonCode)
+				})
+			}
 
-			err := suite.usecase.AddUserBalance(context.Background(), tt.userId, tt.balance)
+			got, err := suite.usecase.AdjustBalance(tt.ctx, 1, &tt.data)
 
 			if tt.assertion(suite.T(), err) {
-				suite.ErrorIs(err, tt.want, "error should be return correctly")
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+				return
 			}
+			suite.Equal(tt.want, got.Amount, "wallet entry should be returned")
+			suite.Require().NotNil(got.ActorId)
+			suite.Equal(9, *got.ActorId, "adjustment should be attributed to the admin")
+			suite.Equal(tt.data.ReasonCode, got.ReasonCode)
 		})
 	}
 }
 
+func (suite *UserUsecaseTestSuite) TestChargeBalance() {
+	tests := []struct {
+		name      string
+		amount    core.Money
+		repoErr   error
+		want      bool
+		wantErr   error
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Balance covers the charge",
+			amount:    core.NewMoney(1000),
+			want:      true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Balance does not cover the charge",
+			amount:    core.NewMoney(1001),
+			want:      false,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Charge was already posted",
+			amount:    core.NewMoney(1000),
+			repoErr:   entity.ErrDuplicateTransaction,
+			want:      true,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Repo return an error",
+			amount:    core.NewMoney(1000),
+			repoErr:   errors.New("this is an error"),
+			want:      false,
+			wantErr:   core.ErrInternalServerError.WithError(entity.ErrCannotPostTransaction.Error()).WithDebug(errors.New("this is an error").Error()),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			suite.SetupTest()
+
+			suite.expectPost(&(*suite.users)[0], tt.repoErr, func(transaction *entity.LedgerTransaction) {
+				suite.Equal(entity.LedgerKindOrderCharge, transaction.Kind, "order charge should be posted")
+				suite.Equal("wallet_5", transaction.Reference)
+				suite.Equal(tt.amount.Mul(-1), transaction.GetWalletAmountSafe(), "wallet should be debited")
+			})
+
+			got, err := suite.usecase.ChargeBalance(context.Background(), 1, tt.amount, "wallet_5")
+
+			if tt.assertion(suite.T(), err) {
+				suite.ErrorIs(err, tt.wantErr, "error should be return correctly")
+			}
+			suite.Equal(tt.want, got, "charge should be reported correctly")
+		})
+	}
+}
+
+func (suite *UserUsecaseTestSuite) TestRefundBalance() {
+	suite.Run("Refund credits the wallet", func() {
+		suite.SetupTest()
+
+		suite.expectPost(&(*suite.users)[1], nil, func(transaction *entity.LedgerTransaction) {
+			suite.Equal(entity.LedgerKindRefund, transaction.Kind, "refund should be posted")
+			suite.Equal(core.NewMoney(300), transaction.GetWalletAmountSafe(), "wallet should be credited")
+		})
+
+		suite.NoError(suite.usecase.RefundBalance(context.Background(), 2, core.NewMoney(300), "wallet_5"))
+	})
+
+	suite.Run("Refund was already posted", func() {
+		suite.SetupTest()
+
+		suite.expectPost(&(*suite.users)[1], entity.ErrDuplicateTransaction, nil)
+
+		suite.NoError(suite.usecase.RefundBalance(context.Background(), 2, core.NewMoney(300), "wallet_5"))
+	})
+
+	suite.Run("Reference was never charged", func() {
+		suite.SetupTest()
+
+		suite.expectPost(&(*suite.users)[1], entity.ErrNothingCharged, nil)
+
+		suite.NoError(suite.usecase.RefundBalance(context.Background(), 2, core.NewMoney(300), "wallet_5"), "nothing should be given back")
+	})
+
+	suite.Run("Refund must be positive", func() {
+		suite.SetupTest()
+
+		err := suite.usecase.RefundBalance(context.Background(), 2, core.NewMoney(0), "wallet_5")
+
+		suite.ErrorIs(err, core.ErrBadRequest.WithError(entity.ErrInvalidAmount.Error()))
+	})
+}
+
+func (suite *UserUsecaseTestSuite) TestPostTransactionCallback() {
+	user := &(*suite.users)[1]
+
+	charge := entity.NewLedgerTransaction(2, entity.LedgerKindOrderCharge, core.NewMoney(-500), "wallet_5", time.Now())
+	suite.NoError(suite.usecase.PostTransactionCallback(user, &charge), "exact balance should be charged")
+
+	charge = entity.NewLedgerTransaction(2, entity.LedgerKindOrderCharge, core.NewMoney(-501), "wallet_5", time.Now())
+	suite.ErrorIs(suite.usecase.PostTransactionCallback(user, &charge), entity.ErrInsufficientBalance)
+
+	unbalanced := entity.NewLedgerTransaction(2, entity.LedgerKindTopUp, core.NewMoney(500), "", time.Now())
+	unbalanced.Entries[1].Amount = core.NewMoney(-400)
+	suite.ErrorIs(suite.usecase.PostTransactionCallback(user, &unbalanced), entity.ErrUnbalancedTransaction)
+
+	suite.ErrorIs(suite.usecase.PostTransactionCallback(nil, &charge), entity.ErrInvalidMemory)
+}
+
+func (suite *UserUsecaseTestSuite) TestGetBalanceHistory() {
+	entries := func(ids ...int64) *[]entity.LedgerEntry {
+		datas := make([]entity.LedgerEntry, 0, len(ids))
+		for _, id := range ids {
+			datas = append(datas, entity.LedgerEntry{Id: id, UserId: 1, Account: entity.LedgerAccountWallet})
+		}
+
+		return &datas
+	}
+
+	suite.Run("More entries than a page", func() {
+		suite.SetupTest()
+
+		filter := &entity.LedgerFilter{UserId: 1, Limit: 2}
+		suite.mockRepo.EXPECT().GetLedgerEntries(gomock.Any(), &entity.LedgerFilter{UserId: 1, Limit: 3}).Return(entries(9, 7, 4), nil)
+
+		got, nextCursor, err := suite.usecase.GetBalanceHistory(context.Background(), filter)
+
+		suite.Require().NoError(err)
+		suite.Equal(entries(9, 7), got, "page should be cut to the limit")
+		suite.Equal(entity.LedgerCursor{Id: 7}.Encode(), nextCursor, "cursor should point after the last entry")
+	})
+
+	suite.Run("Last page", func() {
+		suite.SetupTest()
+
+		filter := &entity.LedgerFilter{UserId: 1, Limit: 2, Cursor: &entity.LedgerCursor{Id: 7}}
+		suite.mockRepo.EXPECT().GetLedgerEntries(gomock.Any(), &entity.LedgerFilter{UserId: 1, Limit: 3, Cursor: &entity.LedgerCursor{Id: 7}}).Return(entries(4), nil)
+
+		got, nextCursor, err := suite.usecase.GetBalanceHistory(context.Background(), filter)
+
+		suite.Require().NoError(err)
+		suite.Equal(entries(4), got)
+		suite.Empty(nextCursor, "last page should have no cursor")
+	})
+
+	suite.Run("Repo return an error", func() {
+		suite.SetupTest()
+
+		suite.mockRepo.EXPECT().GetLedgerEntries(gomock.Any(), gomock.Any()).Return(nil, errors.New("this is an error"))
+
+		_, _, err := suite.usecase.GetBalanceHistory(context.Background(), &entity.LedgerFilter{UserId: 1, Limit: 2})
+
+		suite.ErrorIs(err, core.ErrInternalServerError.WithError(entity.ErrCannotGetBalanceHistory.Error()).WithDebug(errors.New("this is an error").Error()))
+	})
+}
+
+func (suite *UserUsecaseTestSuite) TestReconcile() {
+	mismatched := &entity.Reconciliation{
+		Users:      2,
+		Mismatches: []entity.BalanceMismatch{{UserId: 2, Balance: core.NewMoney(500), Ledger: core.NewMoney(400)}},
+	}
+	suite.mockRepo.EXPECT().Reconcile(gomock.Any()).Return(mismatched, nil)
+
+	got, err := suite.usecase.Reconcile(context.Background())
+
+	suite.Require().NoError(err)
+	suite.False(got.IsBalanced(), "mismatched balance should be reported")
+	suite.True((&entity.Reconciliation{Users: 2}).IsBalanced())
+}
+
 func (suite *UserUsecaseTestSuite) TestGetUserProfile() {
 	tests := []struct {
 		name      string
@@ -229,6 +718,12 @@ func (suite *UserUsecaseTestSuite) TestGetUserProfile() {
 	}
 }
 
+func requesterContext(userId, role uint32) context.Context {
+	sub := core.NewUID(userId, role).String()
+
+	return core.ContextWithRequester(context.Background(), core.NewRequester(sub, "token-id"))
+}
+
 func TestUserUsecaseTestSuite(t *testing.T) {
 	suite.Run(t, new(UserUsecaseTestSuite))
 }
@@ -5,25 +5,48 @@ import (
 	"order_service/internal/core"
 	"order_service/services/user/entity"
 	userRepo "order_service/services/user/repository/postgres"
+	"time"
 )
 
 type UserUsecase interface {
 	GetUsers(ctx context.Context) (*[]entity.User, error)
 	GetUser(ctx context.Context, userID int) (*entity.User, error)
 	GetUserProfile(ctx context.Context, userId int) (*entity.User, error)
-	AddUserBalance(ctx context.Context, userId int, balance float32) error
+	AddUserBalance(ctx context.Context, userId int, balance core.Money) (*entity.TopUp, error)
+	CreateTopUpCallback(topUp *entity.TopUp, dailyTotal core.Money) error
+	GetTopUps(ctx context.Context, filter *entity.TopUpFilter) (*[]entity.TopUp, error)
+	ReviewTopUp(ctx context.Context, topUpId int, approve bool, data *entity.ReviewRequest) (*entity.TopUp, error)
+	AdjustBalance(ctx context.Context, userId int, data *entity.AdjustmentRequest) (*entity.LedgerEntry, error)
+	ChargeBalance(ctx context.Context, userId int, amount core.Money, reference string) (bool, error)
+	RefundBalance(ctx context.Context, userId int, amount core.Money, reference string) error
+	PostTransactionCallback(user *entity.User, transaction *entity.LedgerTransaction) error
+	GetBalanceHistory(ctx context.Context, filter *entity.LedgerFilter) (*[]entity.LedgerEntry, string, error)
+	Reconcile(ctx context.Context) (*entity.Reconciliation, error)
 }
 
 type userUsecase struct {
-	repo userRepo.UserRepository
+	repo   userRepo.UserRepository
+	limits entity.TopUpLimits
 }
 
-func NewUsecase(repo userRepo.UserRepository) UserUsecase {
+// NewUsecase builds the user usecase, the top-ups users make themselves are bounded by limits.
+func NewUsecase(repo userRepo.UserRepository, limits entity.TopUpLimits) UserUsecase {
 	return &userUsecase{
 		repo,
+		limits,
 	}
 }
 
+// requester returns the id of the requester and whether it is an admin.
+func requester(ctx context.Context) (int, bool, error) {
+	uid, err := core.DecomposeUID(core.GetRequester(ctx).GetSubject())
+	if err != nil {
+		return 0, false, core.ErrInternalServerError.WithDebug(err.Error())
+	}
+
+	return int(uid.GetLocalID()), uid.GetRole() == 1, nil
+}
+
 func (uc *userUsecase) GetUsers(ctx context.Context) (*[]entity.User, error) {
 	users, err := uc.repo.GetUsers(ctx)
 	if err != nil {
@@ -57,11 +80,225 @@ func (uc *userUsecase) GetUserProfile(ctx context.Context, userId int) (*entity.
 	return user, nil
 }
 
-func (uc *userUsecase) AddUserBalance(ctx context.Context, userId int, balance float32) error {
-	err := uc.repo.AddUserBalanceById(ctx, userId, balance)
+// AddUserBalance tops the wallet of the user up with balance. A top-up within the limits and below the
+// approval threshold credits the wallet right away, a larger one stays pending until an admin reviews it.
+func (uc *userUsecase) AddUserBalance(ctx context.Context, userId int, balance core.Money) (*entity.TopUp, error) {
+	if !balance.IsPositive() {
+		return nil, core.ErrBadRequest.WithError(entity.ErrInvalidAmount.Error())
+	}
+
+	topUp := entity.NewTopUp(userId, balance, time.Now())
+
+	err := uc.repo.CreateTopUp(ctx, &topUp, uc.CreateTopUpCallback)
+	if err != nil {
+		switch err {
+		case core.ErrRecordNotFound:
+			return nil, core.ErrNotFound.WithError(entity.ErrCannotGetUser.Error())
+		case entity.ErrTopUpLimitExceeded, entity.ErrDailyTopUpLimitExceeded:
+			return nil, core.ErrUnprocessableEntity.WithError(err.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotAddBalance.Error()).WithDebug(err.Error())
+	}
+
+	return &topUp, nil
+}
+
+// CreateTopUpCallback holds a top-up against the limits, dailyTotal is what the user already topped up
+// today without the rejected top-ups.
+func (uc *userUsecase) CreateTopUpCallback(topUp *entity.TopUp, dailyTotal core.Money) error {
+	if topUp == nil {
+		return entity.ErrInvalidMemory
+	}
+
+	status, err := uc.limits.Check(topUp.GetAmountSafe(), dailyTotal)
+	if err != nil {
+		return err
+	}
+	topUp.SetStatus(status)
+
+	return nil
+}
+
+// GetTopUps lists the top-ups newest first, customers only ever see their own.
+func (uc *userUsecase) GetTopUps(ctx context.Context, filter *entity.TopUpFilter) (*[]entity.TopUp, error) {
+	requesterId, admin, err := requester(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !admin {
+		filter.UserId = requesterId
+	}
+
+	topUps, err := uc.repo.GetTopUps(ctx, filter)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotGetTopUps.Error()).WithDebug(err.Error())
+	}
+
+	return topUps, nil
+}
+
+// ReviewTopUp lets an admin approve or reject a pending top-up, an approved one credits the wallet on
+// behalf of the admin.
+func (uc *userUsecase) ReviewTopUp(ctx context.Context, topUpId int, approve bool, data *entity.ReviewRequest) (*entity.TopUp, error) {
+	reviewerId, admin, err := requester(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return nil, core.ErrBadRequest.WithError(entity.ErrCannotReviewTopUp.Error())
+	}
+
+	if err := data.Validate(); err != nil {
+		return nil, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	topUp := entity.TopUp{Id: topUpId}
+
+	err = uc.repo.ReviewTopUp(ctx, &topUp, func(topUp *entity.TopUp) error {
+		return topUp.Review(approve, reviewerId, data.Note, time.Now())
+	})
+	if err != nil {
+		switch err {
+		case core.ErrRecordNotFound:
+			return nil, core.ErrNotFound.WithError(entity.ErrTopUpNotFound.Error())
+		case entity.ErrTopUpAlreadyReviewed, entity.ErrCannotReviewOwnTopUp:
+			return nil, core.ErrConfict.WithError(err.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotReviewTopUp.Error()).WithDebug(err.Error())
+	}
+
+	return &topUp, nil
+}
+
+// AdjustBalance lets an admin credit or debit the wallet of a user for a reason, a debit never takes the
+// wallet below zero. It returns the wallet entry of the adjustment.
+func (uc *userUsecase) AdjustBalance(ctx context.Context, userId int, data *entity.AdjustmentRequest) (*entity.LedgerEntry, error) {
+	actorId, admin, err := requester(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return nil, core.ErrBadRequest.WithError(entity.ErrCannotAdjustBalance.Error())
+	}
+
+	if err := data.Validate(); err != nil {
+		return nil, core.ErrBadRequest.WithError(err.Error())
+	}
+
+	transaction := entity.NewLedgerTransaction(userId, entity.LedgerKindAdjustment, data.GetWalletAmount(), "", time.Now())
+	transaction.Attribute(actorId, data.ReasonCode, data.Note)
+
+	err = uc.repo.PostTransaction(ctx, &transaction, uc.PostTransactionCallback)
+	if err != nil {
+		switch err {
+		case core.ErrRecordNotFound:
+			return nil, core.ErrNotFound.WithError(entity.ErrCannotGetUser.Error())
+		case entity.ErrInsufficientBalance:
+			return nil, core.ErrConfict.WithError(err.Error())
+		}
+
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotAdjustBalance.Error()).WithDebug(err.Error())
+	}
+
+	return &transaction.Entries[0], nil
+}
+
+// ChargeBalance takes amount out of the wallet of the user for reference, it reports false when the balance
+// does not cover amount. Charging the same reference again is a no-op.
+func (uc *userUsecase) ChargeBalance(ctx context.Context, userId int, amount core.Money, reference string) (bool, error) {
+	if !amount.IsPositive() {
+		return false, core.ErrBadRequest.WithError(entity.ErrInvalidAmount.Error())
+	}
+
+	transaction := entity.NewLedgerTransaction(userId, entity.LedgerKindOrderCharge, amount.Mul(-1), reference, time.Now())
+
+	err := uc.repo.PostTransaction(ctx, &transaction, uc.PostTransactionCallback)
 	if err != nil {
-		return core.ErrInternalServerError.WithError(entity.ErrCannotAddBalance.Error()).WithDebug(err.Error())
+		switch err {
+		case entity.ErrDuplicateTransaction:
+			return true, nil
+		case entity.ErrInsufficientBalance:
+			return false, nil
+		case core.ErrRecordNotFound:
+			return false, core.ErrNotFound.WithError(entity.ErrCannotGetUser.Error())
+		}
+
+		return false, core.ErrInternalServerError.WithError(entity.ErrCannotPostTransaction.Error()).WithDebug(err.Error())
+	}
+
+	return true, nil
+}
+
+// RefundBalance gives amount charged for reference back to the wallet of the user. Refunding the same
+// reference again, or one that was never charged, is a no-op.
+func (uc *userUsecase) RefundBalance(ctx context.Context, userId int, amount core.Money, reference string) error {
+	if !amount.IsPositive() {
+		return core.ErrBadRequest.WithError(entity.ErrInvalidAmount.Error())
+	}
+
+	transaction := entity.NewLedgerTransaction(userId, entity.LedgerKindRefund, amount, reference, time.Now())
+
+	err := uc.repo.PostTransaction(ctx, &transaction, uc.PostTransactionCallback)
+	if err != nil {
+		switch err {
+		case entity.ErrDuplicateTransaction, entity.ErrNothingCharged:
+			return nil
+		case core.ErrRecordNotFound:
+			return core.ErrNotFound.WithError(entity.ErrCannotGetUser.Error())
+		}
+
+		return core.ErrInternalServerError.WithError(entity.ErrCannotPostTransaction.Error()).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// PostTransactionCallback checks a transaction against the locked user, a wallet never goes below zero.
+func (uc *userUsecase) PostTransactionCallback(user *entity.User, transaction *entity.LedgerTransaction) error {
+	if user == nil || transaction == nil {
+		return entity.ErrInvalidMemory
+	}
+
+	if err := transaction.Validate(); err != nil {
+		return err
+	}
+
+	if user.GetBalance().Add(transaction.GetWalletAmountSafe()).IsNegative() {
+		return entity.ErrInsufficientBalance
 	}
 
 	return nil
 }
+
+// GetBalanceHistory returns a page of the wallet entries of the user newest first, alongside the cursor
+// of the next page which is empty on the last one.
+func (uc *userUsecase) GetBalanceHistory(ctx context.Context, filter *entity.LedgerFilter) (*[]entity.LedgerEntry, string, error) {
+	// fetch one more entry than asked for to know whether there is a next page
+	pageFilter := *filter
+	pageFilter.Limit = filter.Limit + 1
+
+	entries, err := uc.repo.GetLedgerEntries(ctx, &pageFilter)
+	if err != nil {
+		return nil, "", core.ErrInternalServerError.WithError(entity.ErrCannotGetBalanceHistory.Error()).WithDebug(err.Error())
+	}
+
+	nextCursor := ""
+	if len(*entries) > filter.Limit {
+		*entries = (*entries)[:filter.Limit]
+		nextCursor = entity.LedgerCursor{Id: (*entries)[filter.Limit-1].Id}.Encode()
+	}
+
+	return entries, nextCursor, nil
+}
+
+func (uc *userUsecase) Reconcile(ctx context.Context) (*entity.Reconciliation, error) {
+	reconciliation, err := uc.repo.Reconcile(ctx)
+	if err != nil {
+		return nil, core.ErrInternalServerError.WithError(entity.ErrCannotReconcile.Error()).WithDebug(err.Error())
+	}
+
+	return reconciliation, nil
+}